@@ -17,7 +17,8 @@ let package = Package(
     dependencies: [
         .package(url: "https://github.com/argmaxinc/WhisperKit", from: "0.10.0"),
         .package(url: "https://github.com/ml-explore/mlx-swift-lm", from: "2.30.6"),
-        .package(path: "/Users/nkristianto/Workspace/github/FluidAudio"),
+        // Pinned exactly: ParakeetService's AsrModelVersion mapping targets this release.
+        .package(url: "https://github.com/FluidInference/FluidAudio.git", exact: "0.12.1"),
     ],
     targets: [
         // Targets are the basic building blocks of a package, defining a module or a test suite.
@@ -0,0 +1,158 @@
+import Foundation
+
+// MARK: - DictationAPI
+
+/// Starting, stopping and pausing dictation, re-casing the last output and filling
+/// dictation templates — for the tray, the quick panel, the control API, URL commands
+/// and voice commands.
+///
+/// `triggers` and `output` are created with the core services once permissions are
+/// granted, so they are looked up on every call. `AppDelegate` owns one instance as
+/// `dictationAPI`.
+final class DictationAPI {
+
+    private let stateManager: AppStateManager
+    private let templates: TemplateService?
+    /// The dictation in progress was started by `toggleClipboardDictation()`.
+    private(set) var isDictatingToClipboard = false
+    private let triggers: () -> ExternalTriggerService?
+    private let output: () -> OutputService?
+
+    init(stateManager: AppStateManager,
+         templates: TemplateService?,
+         triggers: @escaping () -> ExternalTriggerService?,
+         output: @escaping () -> OutputService?) {
+        self.stateManager = stateManager
+        self.templates = templates
+        self.triggers = triggers
+        self.output = output
+    }
+
+    // MARK: - Recording
+
+    /// Starts, stops or toggles a recording like the global shortcut does.
+    func handleTrigger(_ command: ExternalTriggerCommand) {
+        // Not set up until permissions are granted.
+        guard let triggers = triggers() else {
+            Logger.shared.info("DictationAPI: Ignoring '\(command.rawValue)' — core services not started")
+            return
+        }
+        triggers.handle(command)
+    }
+
+    /// Starts a dictation whose text is copied instead of pasted — for launchers, which
+    /// hold the focus while they run — or stops the recording in progress.
+    func toggleClipboardDictation() {
+        if stateManager.currentState == .recording {
+            handleTrigger(.stop)
+            return
+        }
+        isDictatingToClipboard = true
+        handleTrigger(.start)
+        if stateManager.currentState != .recording { isDictatingToClipboard = false }
+    }
+
+    /// Ends a clipboard dictation; called whenever the app is back at rest.
+    func dictationDidRest() {
+        isDictatingToClipboard = false
+    }
+
+    /// Pausing is only possible between recordings.
+    var canChangePause: Bool {
+        stateManager.isDictationPaused || stateManager.currentState == .idle
+    }
+
+    /// Same transitions as the "pause dictation" / "resume dictation" voice commands.
+    func setPaused(_ paused: Bool) {
+        guard paused != stateManager.isDictationPaused else { return }
+        stateManager.perform(paused ? .pauseDictation : .resumeDictation)
+    }
+
+    func togglePause() {
+        setPaused(!stateManager.isDictationPaused)
+    }
+
+    /// Re-types the last pasted text in `textCase`. Returns `false` when nothing has been
+    /// pasted yet, keystrokes can't be sent, or the focus has left the pasted-into field.
+    @discardableResult
+    func changeCaseOfLastOutput(to textCase: TextCase) -> Bool {
+        let changed = output()?.retypeLastInsertion(textCase.apply) ?? false
+        if !changed {
+            Logger.shared.info("DictationAPI: No pasted text in the focused field to change to \(textCase.title)")
+        }
+        return changed
+    }
+
+    /// Per-stage latency, real-time factor and queue depth of this session's dictations.
+    func metrics() -> MetricsSnapshot {
+        stateManager.metrics.snapshot()
+    }
+
+    /// Memory used by the app, the selected model, the recording buffer and Metal.
+    @MainActor
+    func resourceUsage() -> ResourceUsage {
+        stateManager.resourceUsage()
+    }
+
+    // MARK: - Templates
+
+    /// The template being filled, if any.
+    var activeTemplate: TemplateFill? { templates?.activeFill }
+
+    /// Every dictation template, sorted by name.
+    @MainActor
+    func allTemplates() -> [DictationTemplate] {
+        templates?.all() ?? []
+    }
+
+    /// Throws `TemplateError` for a blank name or a body without slots.
+    @MainActor @discardableResult
+    func createTemplate(name: String, body: String) throws -> DictationTemplate? {
+        try templates?.create(name: name, body: body)
+    }
+
+    /// Returns `false` if no template has `id`.
+    @MainActor @discardableResult
+    func updateTemplate(_ id: UUID, name: String, body: String) throws -> Bool {
+        try templates?.update(id, name: name, body: body) ?? false
+    }
+
+    @MainActor @discardableResult
+    func deleteTemplate(_ id: UUID) -> Bool {
+        let deleted = templates?.delete(id) ?? false
+        if deleted { updateTemplateSlotHint() }
+        return deleted
+    }
+
+    /// Starts filling a template: the following dictations fill its slots in order.
+    /// Returns `false` if no template has `id`.
+    @MainActor @discardableResult
+    func startTemplate(_ id: UUID) -> Bool {
+        guard templates?.start(id) != nil else { return false }
+        updateTemplateSlotHint()
+        return true
+    }
+
+    @MainActor
+    func cancelTemplate() {
+        templates?.cancel()
+        updateTemplateSlotHint()
+    }
+
+    /// Puts `text` into the next slot of the active template. Returns the updated fill,
+    /// or `nil` when none is active; a complete fill is for the caller to record and paste.
+    @MainActor @discardableResult
+    func fillTemplate(with text: String) -> TemplateFill? {
+        guard let fill = templates?.fill(with: text) else { return nil }
+        updateTemplateSlotHint()
+        return fill
+    }
+
+    /// Shows the slot the next dictation fills above the recording pill.
+    @MainActor
+    private func updateTemplateSlotHint() {
+        stateManager.templateSlotHint = templates?.activeFill.flatMap { fill in
+            fill.nextSlot.map { "\(fill.name): \($0)" }
+        }
+    }
+}
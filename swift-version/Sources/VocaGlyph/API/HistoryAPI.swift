@@ -0,0 +1,112 @@
+import Foundation
+
+// MARK: - HistoryAPI
+
+/// Past transcriptions and the snippets pinned from them, for the tray, the quick
+/// panel, the control API and URL commands.
+///
+/// `history` and `snippets` are `nil` when the SwiftData store could not be opened;
+/// every call then behaves as if there were no entries. `output` is looked up on every
+/// call because `AppDelegate` creates it with the core services.
+/// `AppDelegate` owns one instance as `historyAPI`.
+final class HistoryAPI {
+
+    private let history: HistoryService?
+    private let snippets: SnippetService?
+    private let output: () -> OutputService?
+
+    init(history: HistoryService?, snippets: SnippetService?, output: @escaping () -> OutputService?) {
+        self.history = history
+        self.snippets = snippets
+        self.output = output
+    }
+
+    // MARK: - History
+
+    /// The newest history entries, newest first.
+    @MainActor
+    func recent(limit: Int) -> [TranscriptionItem] {
+        history?.recent(limit: limit) ?? []
+    }
+
+    /// The newest entry's text, or the last insertion when history is off.
+    @MainActor
+    func lastText() -> String? {
+        recent(limit: 1).first?.text ?? output()?.lastInsertion
+    }
+
+    /// Copies a history entry to the clipboard. Returns `false` if no entry has `id`.
+    @MainActor @discardableResult
+    func copy(_ id: UUID) -> Bool {
+        guard let item = history?.item(id), let output = output() else { return false }
+        output.copyToPasteboard(text: item.text)
+        Logger.shared.info("HistoryAPI: Copied history entry \(id) to the clipboard")
+        return true
+    }
+
+    /// Sends a history entry through `OutputService` again, as if it had just been
+    /// dictated — into whichever app is frontmost. Returns `false` if no entry has `id`.
+    @MainActor @discardableResult
+    func repaste(_ id: UUID) -> Bool {
+        guard let item = history?.item(id), let output = output() else { return false }
+        Logger.shared.info("HistoryAPI: Re-pasting history entry \(id)")
+        output.handleTranscriptionValue(item.text)
+        return true
+    }
+
+    /// Re-pastes the newest history entry, or the last insertion when history is off.
+    @MainActor @discardableResult
+    func pasteLast() -> Bool {
+        if let latest = recent(limit: 1).first {
+            return repaste(latest.id)
+        }
+        guard let output = output(), let text = output.lastInsertion else { return false }
+        output.insert(text)
+        return true
+    }
+
+    /// First step of `clear(confirmationToken:)`. The token is valid for
+    /// `HistoryService.confirmationTokenLifetime` seconds and can be used once.
+    func clearConfirmationToken() -> String? {
+        history?.clearHistoryConfirmationToken()
+    }
+
+    /// Deletes every history entry. Throws `HistoryError.invalidConfirmationToken` unless
+    /// `confirmationToken` came from `clearConfirmationToken()`.
+    @MainActor @discardableResult
+    func clear(confirmationToken: String) throws -> Int {
+        guard let history else { return 0 }
+        return try history.clearHistory(confirmationToken: confirmationToken)
+    }
+
+    // MARK: - Snippets
+
+    /// Every snippet, sorted by name.
+    @MainActor
+    func allSnippets() -> [Snippet] {
+        snippets?.all() ?? []
+    }
+
+    /// Pins a history entry as a snippet. A blank `name` uses the start of the text.
+    /// Returns `nil` if no history entry has `id`.
+    @MainActor @discardableResult
+    func pin(_ id: UUID, name: String = "") -> Snippet? {
+        guard let item = history?.item(id) else { return nil }
+        let trimmed = name.trimmingCharacters(in: .whitespacesAndNewlines)
+        return snippets?.pin(item, name: trimmed.isEmpty ? AppDelegate.menuTitle(for: item.text) : trimmed)
+    }
+
+    /// Pastes a snippet into the frontmost app verbatim. Returns `false` if no snippet has `id`.
+    @MainActor @discardableResult
+    func pasteSnippet(_ id: UUID) -> Bool {
+        guard let snippet = snippets?.snippet(id), let output = output() else { return false }
+        Logger.shared.info("HistoryAPI: Pasting snippet '\(snippet.name)'")
+        output.insert(snippet.text)
+        return true
+    }
+
+    @MainActor @discardableResult
+    func deleteSnippet(_ id: UUID) -> Bool {
+        snippets?.delete(id) ?? false
+    }
+}
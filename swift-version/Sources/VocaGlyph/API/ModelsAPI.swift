@@ -0,0 +1,146 @@
+import Foundation
+
+// MARK: - ModelEngineService
+
+/// What `ModelsAPI` needs from a transcription engine's model store. `WhisperService`
+/// and `ParakeetService` conform below; tests use a mock.
+protocol ModelEngineService: AnyObject {
+    var downloadedModels: Set<String> { get }
+    /// Models that failed the launch checksum pass.
+    var corruptModels: Set<String> { get }
+    var modelsDiskUsage: Int64 { get }
+    func status(for model: String) -> ModelStatus
+    /// Returns `false` when the download can't be paused or wasn't running.
+    func pauseDownload(_ model: String) -> Bool
+    func cancelDownload(_ model: String) -> Bool
+    func repairModel(_ model: String)
+    func checkDownloadedModels()
+}
+
+// MARK: - ModelsAPI
+
+/// Model downloads, status and selection for the tray, the quick panel, the control
+/// API and onboarding. Parakeet models (`parakeet-…`) are routed to `parakeet`,
+/// everything else that isn't built in to `whisper`.
+///
+/// The engines are created after permissions are granted, so they are looked up on
+/// every call. `AppDelegate` owns one instance as `modelsAPI`.
+final class ModelsAPI {
+
+    private let whisper: () -> ModelEngineService?
+    private let parakeet: () -> ModelEngineService?
+    private let settings: SettingsAPI
+    private let registry: ModelRegistry
+
+    init(whisper: @escaping () -> ModelEngineService?,
+         parakeet: @escaping () -> ModelEngineService?,
+         settings: SettingsAPI,
+         registry: ModelRegistry = .shared) {
+        self.whisper = whisper
+        self.parakeet = parakeet
+        self.settings = settings
+        self.registry = registry
+    }
+
+    private func engine(for model: String) -> ModelEngineService? {
+        model.hasPrefix("parakeet-") ? parakeet() : whisper()
+    }
+
+    /// Every downloaded Whisper and Parakeet model, plus `whisper-cpp` once it's installed
+    /// and has a model file, or `nil` before the engines exist.
+    func downloadedModelIds() -> Set<String>? {
+        guard let whisper = whisper(), let parakeet = parakeet() else { return nil }
+        let settings = settings
+        let whisperCpp: Set<String> = WhisperCppEngine(settings: { settings.settings }).isAvailable
+            ? [WhisperCppEngine.modelID] : []
+        return whisper.downloadedModels.union(parakeet.downloadedModels).union(whisperCpp)
+    }
+
+    /// Download status of `model`; `nil` for built-in models, which need no download,
+    /// and before the engines exist.
+    func status(for model: String) -> ModelStatus? {
+        guard !SettingsValidator.builtInTranscriptionModels.contains(model) else { return nil }
+        return engine(for: model)?.status(for: model)
+    }
+
+    /// Download status of every Whisper, Parakeet and custom model, sorted by id.
+    /// Parakeet downloads report no byte counts — FluidAudio does not expose them.
+    func statuses() -> [ModelStatus] {
+        let models = SettingsValidator.knownTranscriptionModels
+            .subtracting(SettingsValidator.builtInTranscriptionModels)
+            .union(registry.customModelIds)
+        return models.sorted().map { status(for: $0) ?? .notDownloaded($0) }
+    }
+
+    /// Bytes used by downloaded models of both engines.
+    func diskUsage() -> Int64 {
+        (whisper()?.modelsDiskUsage ?? 0) + (parakeet()?.modelsDiskUsage ?? 0)
+    }
+
+    /// Pauses an in-flight Whisper download; its partial files are kept and the next
+    /// download of the same model resumes from them. Parakeet downloads cannot be
+    /// paused — use `cancelDownload(_:)`. Returns `false` when nothing was paused.
+    @discardableResult
+    func pauseDownload(_ model: String) -> Bool {
+        guard let engine = engine(for: model), engine.pauseDownload(model) else {
+            Logger.shared.info("ModelsAPI: Nothing to pause for '\(model)'")
+            return false
+        }
+        return true
+    }
+
+    /// Cancels an in-flight or paused download and deletes its partial files.
+    /// Returns `false` when the model was not downloading.
+    @discardableResult
+    func cancelDownload(_ model: String) -> Bool {
+        engine(for: model)?.cancelDownload(model) ?? false
+    }
+
+    /// Re-downloads the damaged files of a model flagged by the launch checksum pass.
+    /// Returns `false` when the model is not known to be corrupt.
+    @discardableResult
+    func repair(_ model: String) -> Bool {
+        guard let engine = engine(for: model), engine.corruptModels.contains(model) else { return false }
+        engine.repairModel(model)
+        return true
+    }
+
+    /// Copies (or symlinks) a local WhisperKit CoreML model folder into the models directory
+    /// and makes it selectable like a built-in model. A sandboxed build always copies: the
+    /// target of a symlink is unreadable once the app relaunches.
+    @discardableResult
+    func importModel(atPath path: String, symlink: Bool = false, sandboxed: Bool = AppSandbox.isActive) throws -> CustomModel {
+        let url = URL(fileURLWithPath: (path as NSString).expandingTildeInPath)
+        if symlink && sandboxed {
+            Logger.shared.info("ModelsAPI: Copying \(url.lastPathComponent) instead of linking it (App Sandbox)")
+        }
+        let model = try registry.importModel(from: url, symlink: symlink && !sandboxed)
+        whisper()?.checkDownloadedModels()
+        return model
+    }
+
+    /// Selects `model` for dictation. Returns the validation message when it is unknown
+    /// or not downloaded.
+    @discardableResult
+    func switchModel(to model: String) -> String? {
+        settings.change { $0.selectedModel = model }
+    }
+}
+
+// MARK: - Engine Conformances
+
+extension WhisperService: ModelEngineService {}
+
+extension ParakeetService: ModelEngineService {
+    /// Parakeet has no checksum pass.
+    var corruptModels: Set<String> { [] }
+
+    /// FluidAudio cannot resume partial downloads, so there is no pause for Parakeet.
+    func pauseDownload(_ model: String) -> Bool { false }
+
+    func cancelDownload(_ model: String) -> Bool {
+        cancelDownload(id: model)
+    }
+
+    func repairModel(_ model: String) {}
+}
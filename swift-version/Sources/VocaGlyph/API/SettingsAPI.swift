@@ -0,0 +1,72 @@
+import Foundation
+
+// MARK: - SettingsAPI
+
+/// Reading and changing settings for the tray, the quick panel, the control API and
+/// voice commands. Every change goes through `SettingsUpdater`, so it is validated,
+/// persisted and applied the same way wherever it comes from.
+///
+/// `AppDelegate` owns one instance as `settingsAPI`.
+final class SettingsAPI {
+
+    private let store: SettingsStore
+    private let updater: SettingsUpdater
+    private let backups: SettingsBackupService
+
+    init(store: SettingsStore = .shared, updater: SettingsUpdater, backups: SettingsBackupService) {
+        self.store = store
+        self.updater = updater
+        self.backups = backups
+    }
+
+    var settings: AppSettings { store.settings }
+
+    /// Applies several settings at once — validated, persisted and applied together.
+    /// See `SettingsUpdater` for the rollback rules. Call on the main thread.
+    @discardableResult
+    func update(_ settings: AppSettings) -> SettingsUpdateResult {
+        updater.update(settings)
+    }
+
+    /// Edits a copy of the current settings and applies it. Returns the first validation
+    /// message when the change is rejected.
+    @discardableResult
+    func change(_ edit: (inout AppSettings) -> Void) -> String? {
+        var settings = store.settings
+        edit(&settings)
+        return update(settings).issues.first?.message
+    }
+
+    /// Returns the validation message when `language` is not a dictation language.
+    @discardableResult
+    func switchLanguage(to language: String) -> String? {
+        change { $0.dictationLanguage = language }
+    }
+
+    /// Archives the current settings, then restores every field to its factory default.
+    /// Throws without changing anything if the backup cannot be written.
+    @discardableResult
+    func reset() throws -> SettingsUpdateResult {
+        let backup = try backups.backup(store.settings)
+        Logger.shared.info("SettingsAPI: Resetting settings to defaults (backup: \(backup.id))")
+        return update(.defaults)
+    }
+
+    /// Settings backups on disk, newest first.
+    func listBackups() -> [SettingsBackup] {
+        backups.listBackups()
+    }
+
+    /// Applies a previously archived snapshot. The settings being replaced are backed up
+    /// first, so a restore can itself be undone. A snapshot that would be rejected throws
+    /// `SettingsBackupError.invalid` before anything is archived.
+    @discardableResult
+    func restoreBackup(_ backup: SettingsBackup) throws -> SettingsUpdateResult {
+        let settings = try backups.load(backup)
+        let issues = updater.issues(in: settings)
+        guard issues.isEmpty else { throw SettingsBackupError.invalid(issues) }
+        try backups.backup(store.settings)
+        Logger.shared.info("SettingsAPI: Restoring settings from \(backup.id)")
+        return update(settings)
+    }
+}
@@ -2,7 +2,7 @@ import Cocoa
 import SwiftUI
 import AppKit
 import SwiftData
-import Sparkle
+import Combine
 
 public class AppDelegate: NSObject, NSApplicationDelegate {
     var statusItem: NSStatusItem!
@@ -10,6 +10,52 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
     
     let stateManager = AppStateManager()
     var hotkeyService: HotkeyService!
+    var externalTriggerService: ExternalTriggerService!
+    lazy var urlSchemeHandler = URLSchemeHandler(target: self)
+    lazy var controlSocketService = ControlSocketService(target: self)
+    @MainActor private lazy var quickPanel = QuickPanel(target: self)
+    private var controlAPISubscription: SettingsSubscription?
+    private var externalTriggersSubscription: SettingsSubscription?
+    /// Transcribes other local apps' audio with the active engine, while enabled.
+    lazy var transcriptionServer = TranscriptionServer { [weak self] buffer in
+        guard let router = self?.stateManager.engineRouter else { throw TranscriptionServerError.notReady }
+        return try await router.transcribe(audioBuffer: buffer)
+    }
+    private var transcriptionServiceSubscription: SettingsSubscription?
+    /// Set while `liveCaptionsEnabled` is on.
+    private(set) var captionServer: CaptionServer?
+    private var liveCaptionsSubscription: SettingsSubscription?
+    private var partialCaptionCancellable: AnyCancellable?
+    var preferencesWatcher: PreferencesWatcher!
+    var systemWakeMonitor: SystemWakeMonitor!
+    /// Set while `idleReductionEnabled` is on.
+    private(set) var idleMonitor: IdleMonitor?
+    private var idleReductionSubscription: SettingsSubscription?
+    /// Set while `otlpExportEnabled` is on.
+    private(set) var otlpExporter: OTLPExporter?
+    private var otlpExportSubscription: SettingsSubscription?
+    /// Set while `pluginsEnabled` is on.
+    private(set) var pluginHost: PluginHost?
+    private var pluginsSubscription: SettingsSubscription?
+    /// Set for the run when launched with `--debug`.
+    private var debugServer: DebugServer?
+    /// Whether the models were unloaded for the current idle period.
+    private var unloadedModelsWhileIdle = false
+    /// Turns on pasting and the hotkey once Accessibility is granted, without a restart.
+    lazy var accessibilityWatcher: AccessibilityWatcher = {
+        let watcher = AccessibilityWatcher(isTrusted: { [weak self] in
+            self?.permissionsService.isAccessibilityTrusted ?? false
+        })
+        watcher.onGranted = { [weak self] in self?.accessibilityWasGranted() }
+        return watcher
+    }()
+    /// Set while quitting waits for the dictation in progress.
+    private var quitDrainer: QuitDrainer?
+    lazy var settingsUpdater = SettingsUpdater(applier: self, downloadedModels: { [weak self] in
+        self?.modelsAPI.downloadedModelIds()
+    })
+    let settingsBackupService = SettingsBackupService()
+    lazy var onboardingCoordinator = OnboardingCoordinator(performer: self)
     var audioRecorder: AudioRecorderService!
     var whisper: WhisperService!
     var parakeet: ParakeetService!
@@ -20,32 +66,92 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
 
     // NSMenuItem used as the container for the dynamic microphone sub-menu.
     private var microphoneMenuItem: NSMenuItem!
+    // NSMenuItem used as the container for the recent-transcriptions sub-menu.
+    private var recentTranscriptionsMenuItem: NSMenuItem!
+    private var historyObserver: NSObjectProtocol?
+    private var dictationStuckObserver: NSObjectProtocol?
+    /// Tap-or-hold state of the control API's hardware key.
+    private let pushToTalkKey = PushToTalkKey()
+    /// Transcriptions not yet output; leftovers from the last run are offered at launch.
+    let pendingOutputStore = PendingOutputStore()
+    // NSMenuItem used as the container for the snippets sub-menu.
+    private var snippetsMenuItem: NSMenuItem!
+    private var dictationTemplatesMenuItem: NSMenuItem!
+    private var incognitoMenuItem: NSMenuItem!
+    // NSMenuItem used as the container for the output-style sub-menu.
+    private var outputStyleMenuItem: NSMenuItem!
+    private var pauseMenuItem: NSMenuItem!
+    /// Disabled first item showing `runtimeStatus().message`.
+    private var runtimeStatusMenuItem: NSMenuItem!
+    private var modelMenuItem: NSMenuItem!
+    private var languageMenuItem: NSMenuItem!
+    private var changeCaseMenuItem: NSMenuItem!
+    /// Keeps the tray icon and menu in sync when incognito mode is toggled from Settings.
+    private var incognitoSubscription: SettingsSubscription?
+    private var crashReportingSubscription: SettingsSubscription?
+    /// Re-draws the tray icon when its theme or flashing setting changes.
+    private var trayIconSubscription: SettingsSubscription?
+    private var recordingFlashTimer: Timer?
+    private var dockIconSubscription: SettingsSubscription?
+    private var alwaysOnTopSubscription: SettingsSubscription?
     
     public override init() {
         super.init()
     }
 
-    /// Serial queue used exclusively for AVAudioEngine start/stop.
-    /// Keeps audio operations off the main thread so a slow 
-    /// `engine.start()` on first launch never blocks the main run loop.
-    private let audioQueue = DispatchQueue(label: "com.vocaglyph.audioQueue", qos: .userInteractive)
-
-    /// Signals that an async startRecording() is currently in flight.
-    /// When .processing arrives while this is true we wait for recording
-    /// to fully start before stopping (prevents a stop-before-start race).
-    private var isStartingRecording = false
-
-    /// Pending stop-and-process block queued while startRecording() was still
-    /// in flight. Drained as soon as startRecording() finishes.
-    private var pendingStopBlock: (() -> Void)?
+    /// Microphone in, transcription out. The source is set with the core services; the
+    /// sinks send live captions, fill a dictation template, record history, feed sink
+    /// plugins and paste, in that order.
+    lazy var dictationPipeline = DictationPipeline(sinks: [
+        ClosureSink(name: "captions") { [weak self] text in
+            self?.captionServer?.sendFinal(text)
+            return false
+        },
+        ClosureSink(name: "template") { [weak self] text in
+            self?.fillDictationTemplateIfActive(with: text) ?? false
+        },
+        ClosureSink(name: "history") { [weak self] text in
+            self?.recordInHistory(text)
+            return false
+        },
+        ClosureSink(name: "plugins") { [weak self] text in
+            self?.deliverToPlugins(text)
+            return false
+        },
+        ClosureSink(name: "output") { [weak self] text in
+            guard let self else { return true }
+            // Read now: the app rests, and ends the clipboard dictation, right after.
+            let toClipboard = self.dictationAPI.isDictatingToClipboard
+            DispatchQueue.main.async {
+                if toClipboard {
+                    self.output?.copyToPasteboard(text: text.trimmingCharacters(in: .whitespacesAndNewlines))
+                } else {
+                    self.output?.handleTranscriptionValue(text)
+                }
+            }
+            return true
+        },
+    ])
     
-    var sharedModelContainer: ModelContainer? = {
+    var sharedModelContainer: ModelContainer? = AppDelegate.makeModelContainer()
+
+    /// Opens the SwiftData store at `dirs.historyStore`, first adopting the store older
+    /// builds left at `legacyStore` so history, templates, word replacements and snippets
+    /// carry over. Tests pass a temporary layout.
+    static func makeModelContainer(dirs: AppDirs = .current, legacyStore: URL? = AppDirs.legacyHistoryStore) -> ModelContainer? {
+        if let legacyStore, dirs.adoptHistoryStore(at: legacyStore) {
+            Logger.shared.info("AppDelegate: Moved history store to \(dirs.historyStore.path)")
+        }
         let schema = Schema([
             TranscriptionItem.self,
             PostProcessingTemplate.self,
             WordReplacement.self,
+            Snippet.self,
+            DictationTemplate.self,
         ])
-        let modelConfiguration = ModelConfiguration(schema: schema, isStoredInMemoryOnly: false)
+        // SwiftData doesn't create the parent directory of an explicit store URL.
+        try? FileManager.default.createDirectory(at: dirs.root, withIntermediateDirectories: true)
+        let modelConfiguration = ModelConfiguration(schema: schema, url: dirs.historyStore)
 
         do {
             return try ModelContainer(for: schema, configurations: [modelConfiguration])
@@ -63,14 +169,33 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
             }
             return try? ModelContainer(for: schema, configurations: [modelConfiguration])
         }
-    }()
+    }
     
     lazy var permissionsService = PermissionsService()
+    /// Created on first use so tests can swap `sharedModelContainer` beforehand.
+    lazy var historyService: HistoryService? = sharedModelContainer.map { HistoryService(container: $0) }
+    lazy var snippetService: SnippetService? = sharedModelContainer.map { SnippetService(container: $0) }
+    lazy var templateService: TemplateService? = sharedModelContainer.map { TemplateService(container: $0) }
+    /// What the tray, the quick panel, the control API and URL commands can do, grouped
+    /// by area, so those callers don't reach into the services themselves.
+    lazy var settingsAPI = SettingsAPI(updater: settingsUpdater, backups: settingsBackupService)
+    lazy var modelsAPI = ModelsAPI(whisper: { [weak self] in self?.whisper },
+                                   parakeet: { [weak self] in self?.parakeet },
+                                   settings: settingsAPI)
+    lazy var dictationAPI = DictationAPI(stateManager: stateManager,
+                                         templates: templateService,
+                                         triggers: { [weak self] in self?.externalTriggerService },
+                                         output: { [weak self] in self?.output })
+    lazy var historyAPI = HistoryAPI(history: historyService,
+                                     snippets: snippetService,
+                                     output: { [weak self] in self?.output })
     var onboardingWindow: NSWindow?
+    /// `--hidden` or login launch: no windows or prompts that steal focus.
+    private var isHiddenLaunch = false
 
     // MARK: - Sparkle Auto-Update
-    private var updaterController: SPUStandardUpdaterController!
-    private var checkForUpdatesViewModel: CheckForUpdatesViewModel!
+    private var updateService: UpdateService!
+    private var updateStatusCancellable: AnyCancellable?
     private var checkForUpdatesMenuItem: NSMenuItem!
     /// One-shot observer token used to revert to .accessory policy after
     /// Sparkle's update window is dismissed.
@@ -84,12 +209,7 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
     public func applicationDidFinishLaunching(_ aNotification: Notification) {
         // ── Sparkle: initialise the updater as early as possible so background
         //    checks can begin and the forced-update guard below works correctly.
-        updaterController = SPUStandardUpdaterController(
-            startingUpdater: true,
-            updaterDelegate: self,
-            userDriverDelegate: nil
-        )
-        checkForUpdatesViewModel = CheckForUpdatesViewModel(updater: updaterController.updater)
+        updateService = UpdateService()
 
         // ── Forced-update guard (Option D) ─────────────────────────────────
         // If the installed build is older than minimumRequiredBuild, block the
@@ -100,18 +220,64 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         if currentBuild < minimumRequiredBuild {
             // Show the mandatory update sheet; do NOT call initializeCoreServices.
             // The app stays as a dormant menu-bar icon until the update installs.
-            updaterController.updater.checkForUpdates()
+            updateService.updater.checkForUpdates()
             return
         }
 
-        // Hide application from dock and cmd-tab switcher
-        NSApp.setActivationPolicy(.accessory)
+        // Hide application from dock and cmd-tab switcher unless the user keeps the Dock icon.
+        NSApp.setActivationPolicy(restingActivationPolicy)
+
+        // Opt-in: capture crashes locally; they are only sent when the user agrees.
+        if SettingsStore.shared.settings.crashReportingEnabled {
+            CrashReporter.install()
+        }
+        crashReportingSubscription = SettingsStore.shared.subscribe { old, new in
+            guard old.crashReportingEnabled != new.crashReportingEnabled else { return }
+            DispatchQueue.main.async {
+                new.crashReportingEnabled ? CrashReporter.install() : CrashReporter.uninstall()
+            }
+        }
+
+        // A LaunchAgent from before Login Items support becomes an SMAppService login item.
+        // Sandboxed, ~/Library/LaunchAgents is out of reach (and the login item is the only way in).
+        if !AppSandbox.isActive {
+            LoginItemService().migrateLegacyLaunchAgents()
+        }
+
+        // First launch: start in the user's macOS language rather than auto-detect.
+        // Runs before launch overrides so a --language flag isn't cleared by this write,
+        // and before onboarding so its model recommendation sees the language.
+        LocaleDefaults.applyIfNeeded()
+
+        // Per-run overrides from launch flags (--model, --language, --hotkey, --hidden).
+        // Applied before any service reads settings; never persisted.
+        let launchOptions = LaunchOptions.parse(ProcessInfo.processInfo.arguments)
+        launchOptions.errors.forEach { Logger.shared.error("AppDelegate: Launch flag ignored — \($0)") }
+        SettingsStore.shared.applyLaunchOverrides(launchOptions.settingsOverrides)
+        // A login launch is as quiet as --hidden: no windows and no first-run prompts.
+        let launchedAtLogin = launchOptions.launchedAtLogin || LoginItemService.isLoginLaunch()
+        if launchedAtLogin {
+            Logger.shared.info("AppDelegate: Launched at login — starting hidden")
+        }
+        isHiddenLaunch = launchOptions.hidden || launchedAtLogin
+        if let port = launchOptions.debugPort {
+            startDebugServer(port: port)
+        }
 
         if permissionsService.areAllCorePermissionsGranted {
             initializeCoreServices()
+        } else if isHiddenLaunch {
+            // Scripted or login launch: don't steal focus with onboarding. Missing permissions
+            // are logged by the services that need them; Settings is reachable from the menu bar.
+            Logger.shared.info("AppDelegate: Hidden launch with missing permissions — skipping onboarding window.")
+            initializeCoreServices()
         } else {
             showOnboardingWindow()
         }
+        if let replayFile = launchOptions.replayFile {
+            // While onboarding is showing this logs "not ready" rather than waiting.
+            replayOnLaunch(replayFile)
+        }
     }
     
     func showOnboardingWindow() {
@@ -120,6 +286,7 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         // inside the window are completely unresponsive (they never receive key events).
         // We switch back to .accessory in initializeCoreServices() once onboarding is done.
         NSApp.setActivationPolicy(.regular)
+        accessibilityWatcher.start()
 
         let onboardingView = OnboardingView(permissionsService: permissionsService, onComplete: { [weak self] in
             DispatchQueue.main.async {
@@ -148,6 +315,7 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         onboardingWindow?.titleVisibility = .hidden
         onboardingWindow?.titlebarAppearsTransparent = true
         onboardingWindow?.isMovableByWindowBackground = true
+        applyAlwaysOnTop()
         
         onboardingWindow?.makeKeyAndOrderFront(nil)
         NSApp.activate(ignoringOtherApps: true)
@@ -155,8 +323,8 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
     
     @MainActor func initializeCoreServices() {
         // Revert to accessory policy now that onboarding is done —
-        // the app runs as a menu-bar agent with no Dock icon.
-        NSApp.setActivationPolicy(.accessory)
+        // the app runs as a menu-bar agent with no Dock icon (unless `showDockIcon`).
+        NSApp.setActivationPolicy(restingActivationPolicy)
 
         // On first install UserDefaults has no "selectedModel" key.
         // Write the default once so every service reads a consistent value
@@ -166,12 +334,17 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
             Logger.shared.info("AppDelegate: First launch — defaulting selectedModel to 'apple-native'.")
         }
 
+        // Tune decoder settings for this Mac on first launch (and after a hardware change)
+        // before WhisperService reads them.
+        HardwareDefaultsService.applyIfNeeded()
+
         // Seed default post-processing templates if this is a first launch
         if let container = sharedModelContainer {
             let context = container.mainContext
             TemplateSeederService.seedDefaultTemplatesIfNeeded(context: context)
             TemplateSeederService.migrateSystemTemplatesIfNeeded(context: context)
             stateManager.modelContext = context
+            historyService?.enforceRetention()
         }
 
         // Initialize Core Services
@@ -184,20 +357,134 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         audioRecorder = AudioRecorderService()
         audioRecorder.configChangeDelegate = self
         audioRecorder.microphoneService = microphoneService
+        audioRecorder.onLevel = { [weak self] level in
+            DispatchQueue.main.async { self?.stateManager.inputLevel = level }
+        }
+        dictationPipeline.source = audioRecorder
+        stateManager.audioSnapshotProvider = { [weak self] in self?.audioRecorder.snapshot() }
+        stateManager.audioBufferUsageProvider = { [weak self] in self?.audioRecorder.bufferUsage() ?? .empty }
         whisper = WhisperService()
         whisper.delegate = self
         stateManager.sharedWhisper = whisper // Let AppStateManager reuse this single instance
         stateManager.engineRouter = EngineRouter(engine: whisper) // initial default
         parakeet = ParakeetService()
         stateManager.sharedParakeet = parakeet // AC#7: single shared ParakeetService instance
+        stateManager.contextCaptureService = ContextCaptureService()
+        stateManager.regexRulesService = RegexRulesService.shared
+        stateManager.pendingOutputs = pendingOutputStore
+        RegexRulesService.shared.startWatching() // Pick up edits to regex-rules.json live
         stateManager.startEngine() // Boot up whatever model is selected in UserDefaults
         output = OutputService()
         hotkeyService = HotkeyService(stateManager: stateManager)
+        hotkeyService.onIncognitoToggle = { [weak self] in self?.toggleIncognitoMode() }
         hotkeyService.start()
+        incognitoSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.incognitoModeEnabled != new.incognitoModeEnabled else { return }
+            DispatchQueue.main.async { self?.refreshIncognitoIndicators() }
+        }
+        trayIconSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.trayIconTheme != new.trayIconTheme
+                || old.trayIconFlashesWhileRecording != new.trayIconFlashesWhileRecording else { return }
+            DispatchQueue.main.async { self?.refreshTrayIcon() }
+        }
+        dockIconSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.showDockIcon != new.showDockIcon else { return }
+            DispatchQueue.main.async { self?.applyDockIconSetting() }
+        }
+        alwaysOnTopSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.alwaysOnTop != new.alwaysOnTop else { return }
+            DispatchQueue.main.async { self?.applyAlwaysOnTop() }
+        }
+        // The watchdog has already returned the tray to idle; say why nothing was pasted
+        // and where the dictation went.
+        dictationStuckObserver = DictationStuckEvent.observe { [weak self] event in
+            var message = "Processing made no progress for \(event.seconds)s and was cancelled. Reloading the model."
+            if let audio = event.recoveredAudio {
+                message += " The recording was saved to \(audio.path)."
+            }
+            NotificationService.shared.post(.transcriptionFailed(message: message))
+            // Text that was transcribed but not output is offered back right away.
+            DispatchQueue.main.async { self?.offerUnrecoveredDictations() }
+        }
+        // Opt-in local command channel so pedals/automation can drive dictation without a hotkey.
+        externalTriggerService = ExternalTriggerService(stateManager: stateManager)
+        applyExternalTriggersSetting()
+        externalTriggersSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.externalTriggersEnabled != new.externalTriggersEnabled else { return }
+            DispatchQueue.main.async { self?.applyExternalTriggersSetting() }
+        }
+        // Optional token-guarded JSON API on a Unix socket for Stream Deck, pedals and plugins.
+        applyControlAPISetting()
+        controlAPISubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.controlAPIEnabled != new.controlAPIEnabled else { return }
+            DispatchQueue.main.async { self?.applyControlAPISetting() }
+        }
+        // Optional transcription service for other local apps, on a second socket.
+        applyTranscriptionServiceSetting()
+        transcriptionServiceSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.transcriptionServiceEnabled != new.transcriptionServiceEnabled else { return }
+            DispatchQueue.main.async { self?.applyTranscriptionServiceSetting() }
+        }
+        // Optional live captions over WebSocket, e.g. for an OBS browser source.
+        applyLiveCaptionsSetting()
+        liveCaptionsSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.liveCaptionsEnabled != new.liveCaptionsEnabled
+                || old.liveCaptionsPort != new.liveCaptionsPort else { return }
+            DispatchQueue.main.async { self?.applyLiveCaptionsSetting() }
+        }
+
+        // Apply preference edits made outside the app (`defaults write`) without a restart.
+        preferencesWatcher = PreferencesWatcher()
+        preferencesWatcher.onChange = { [weak self] keys in
+            self?.applyReloadedPreferences(keys)
+        }
+        preferencesWatcher.start()
+
+        // The event tap, audio device and permissions can all change across sleep.
+        systemWakeMonitor = SystemWakeMonitor()
+        systemWakeMonitor.onWake = { [weak self] reason in
+            self?.recoverAfterWake(reason: reason)
+        }
+        systemWakeMonitor.start()
+
+        // Dictation timings for the control API, Developer Tools and the slow-transcription status.
+        stateManager.metrics.startPublishing()
+        applyOTLPExportSetting()
+        otlpExportSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.otlpExportEnabled != new.otlpExportEnabled
+                || old.otlpEndpoint != new.otlpEndpoint else { return }
+            DispatchQueue.main.async { self?.applyOTLPExportSetting() }
+        }
+
+        // Processor and sink plugins from the plugins folder.
+        applyPluginsSetting()
+        pluginsSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.pluginsEnabled != new.pluginsEnabled else { return }
+            DispatchQueue.main.async { self?.applyPluginsSetting() }
+        }
+
+        // Stop background work when the app hasn't been used for a while.
+        applyIdleReductionSetting()
+        idleReductionSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.idleReductionEnabled != new.idleReductionEnabled
+                || old.idleReductionMinutes != new.idleReductionMinutes else { return }
+            DispatchQueue.main.async { self?.applyIdleReductionSetting() }
+        }
+
+        // Onboarding can be finished without Accessibility; pick it up when it's granted.
+        accessibilityWatcher.start()
+
+        // After the services' first main-queue pass, so their downloaded-model sets are filled.
+        DispatchQueue.main.async { [weak self] in
+            self?.offerPendingCrashReports()
+            self?.offerUnrecoveredDictations()
+            self?.offerRecommendedModelDownloadIfNeeded()
+        }
         
         // Setup Settings Window
         var anySettingsView: AnyView
-        let settingsView = SettingsView(whisper: whisper, parakeet: parakeet, stateManager: stateManager, microphoneService: microphoneService)
+        let settingsView = SettingsView(whisper: whisper, parakeet: parakeet, stateManager: stateManager,
+                                        microphoneService: microphoneService, settingsAPI: settingsAPI)
         if let container = sharedModelContainer {
             anySettingsView = AnyView(settingsView.modelContainer(container))
         } else {
@@ -217,6 +504,7 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         settingsWindow.delegate = self
         settingsWindow.contentViewController = hostingController
         settingsWindow.title = "VocaGlyph Settings"
+        applyAlwaysOnTop()
         
         // Hide native title bar completely for custom SwiftUI sidebar appearance
         settingsWindow.titleVisibility = .hidden
@@ -228,25 +516,20 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         settingsWindow.toolbar = dummyToolbar
         
         statusItem = NSStatusBar.system.statusItem(withLength: NSStatusItem.variableLength)
-        if let button = statusItem.button {
-            let imgUrl = Bundle.main.url(forResource: "appbaricon", withExtension: "png")
-                      ?? Bundle.module.url(forResource: "appbaricon", withExtension: "png")
-            Logger.shared.info("AppDelegate: appbaricon URL = \(imgUrl?.absoluteString ?? "nil")")
-            if let imgUrl, let nsImage = NSImage(contentsOf: imgUrl) {
-                // Resize to menu bar icon dimensions
-                nsImage.size = NSSize(width: 18, height: 18)
-                // isTemplate = false for full-color PNGs.
-                // Use true only if the icon is a black+transparent template design.
-                nsImage.isTemplate = false
-                button.image = nsImage
-            } else {
-                button.image = NSImage(systemSymbolName: "mic.fill", accessibilityDescription: "VocaGlyph")
-            }
-        }
+        statusItem.button?.image = statusImage(for: .idle)
         
         let menu = NSMenu()
         menu.delegate = self
 
+        runtimeStatusMenuItem = NSMenuItem(title: RuntimeStatus.ready.message, action: nil, keyEquivalent: "")
+        runtimeStatusMenuItem.isEnabled = false
+        menu.addItem(runtimeStatusMenuItem)
+        menu.addItem(NSMenuItem.separator())
+
+        let quickPanelMenuItem = NSMenuItem(title: "Quick Panel…", action: #selector(showQuickPanel(_:)), keyEquivalent: "")
+        quickPanelMenuItem.target = self
+        menu.addItem(quickPanelMenuItem)
+
         let settingsMenuItem = NSMenuItem(title: "Settings...", action: #selector(toggleSettingsWindow(_:)), keyEquivalent: ",")
         settingsMenuItem.target = self
         menu.addItem(settingsMenuItem)
@@ -258,8 +541,43 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
             keyEquivalent: ""
         )
         checkForUpdatesMenuItem.target = self
-        checkForUpdatesMenuItem.isEnabled = checkForUpdatesViewModel.canCheckForUpdates
+        checkForUpdatesMenuItem.isEnabled = updateService.canCheckForUpdates
         menu.addItem(checkForUpdatesMenuItem)
+        // Background checks that find a new version retitle the item.
+        updateStatusCancellable = updateService.$status
+            .receive(on: DispatchQueue.main)
+            .sink { [weak self] status in self?.checkForUpdatesMenuItem?.title = status.menuTitle }
+
+        // ── Incognito toggle ──────────────────────────────────────────
+        incognitoMenuItem = NSMenuItem(title: "Incognito Mode", action: #selector(toggleIncognitoMode), keyEquivalent: "")
+        incognitoMenuItem.target = self
+        incognitoMenuItem.state = SettingsStore.shared.settings.incognitoModeEnabled ? .on : .off
+        menu.addItem(incognitoMenuItem)
+
+        // ── Pause toggle ──────────────────────────────────────────────
+        pauseMenuItem = NSMenuItem(title: "Pause Dictation", action: #selector(togglePauseFromMenu(_:)), keyEquivalent: "")
+        pauseMenuItem.target = self
+        menu.addItem(pauseMenuItem)
+
+        // ── Model & language submenus ─────────────────────────────────
+        modelMenuItem = NSMenuItem(title: "Model", action: nil, keyEquivalent: "")
+        modelMenuItem.submenu = NSMenu(title: "Model")
+        menu.addItem(modelMenuItem)
+        languageMenuItem = NSMenuItem(title: "Language", action: nil, keyEquivalent: "")
+        languageMenuItem.submenu = NSMenu(title: "Language")
+        menu.addItem(languageMenuItem)
+
+        // ── Output style submenu ──────────────────────────────────────
+        outputStyleMenuItem = NSMenuItem(title: "Output Style", action: nil, keyEquivalent: "")
+        outputStyleMenuItem.submenu = NSMenu(title: "Output Style")
+        menu.addItem(outputStyleMenuItem)
+        rebuildOutputStyleSubmenu()
+
+        // ── Change case submenu ───────────────────────────────────────
+        changeCaseMenuItem = NSMenuItem(title: "Change Case of Last Output", action: nil, keyEquivalent: "")
+        changeCaseMenuItem.submenu = NSMenu(title: "Change Case of Last Output")
+        menu.addItem(changeCaseMenuItem)
+        rebuildChangeCaseSubmenu()
 
         // ── Microphone submenu ────────────────────────────────────────
         microphoneMenuItem = NSMenuItem(title: "Microphone", action: nil, keyEquivalent: "")
@@ -268,6 +586,27 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         // Populate once so the submenu isn't blank before first open.
         rebuildMicrophoneSubmenu()
 
+        // ── Recent transcriptions submenu ─────────────────────────────
+        recentTranscriptionsMenuItem = NSMenuItem(title: "Recent Transcriptions", action: nil, keyEquivalent: "")
+        recentTranscriptionsMenuItem.submenu = NSMenu(title: "Recent Transcriptions")
+        menu.addItem(recentTranscriptionsMenuItem)
+        rebuildRecentTranscriptionsSubmenu()
+        historyObserver = HistoryChangedEvent.observe { [weak self] _ in
+            Task { @MainActor in self?.rebuildRecentTranscriptionsSubmenu() }
+        }
+
+        // ── Snippets submenu ──────────────────────────────────────────
+        snippetsMenuItem = NSMenuItem(title: "Snippets", action: nil, keyEquivalent: "")
+        snippetsMenuItem.submenu = NSMenu(title: "Snippets")
+        menu.addItem(snippetsMenuItem)
+        rebuildSnippetsSubmenu()
+
+        // ── Dictation templates submenu ───────────────────────────────
+        dictationTemplatesMenuItem = NSMenuItem(title: "Dictation Templates", action: nil, keyEquivalent: "")
+        dictationTemplatesMenuItem.submenu = NSMenu(title: "Dictation Templates")
+        menu.addItem(dictationTemplatesMenuItem)
+        rebuildDictationTemplatesSubmenu()
+
         menu.addItem(NSMenuItem.separator())
 
         let quitMenuItem = NSMenuItem(title: "Quit VocaGlyph", action: #selector(NSApplication.terminate(_:)), keyEquivalent: "q")
@@ -285,7 +624,7 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         NSApp.setActivationPolicy(.regular)
         NSApp.activate(ignoringOtherApps: true)
 
-        updaterController.checkForUpdates(sender)
+        updateService.checkForUpdates(sender)
 
         // One-shot observer: revert to .accessory once the Sparkle window closes
         // (mirrors the pattern used by toggleSettingsWindow). Skip if Settings is
@@ -303,7 +642,7 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
             }
             // Only revert to accessory if the Settings window is also not visible.
             if !(self.settingsWindow?.isVisible ?? false) {
-                NSApp.setActivationPolicy(.accessory)
+                NSApp.setActivationPolicy(self.restingActivationPolicy)
             }
         }
     }
@@ -318,13 +657,125 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         }
     }
     
+    // MARK: - Incognito Mode
+
+    /// Flips incognito mode. Bound to the tray item and the incognito shortcut.
+    @objc func toggleIncognitoMode() {
+        setIncognitoMode(!SettingsStore.shared.settings.incognitoModeEnabled)
+    }
+
+    /// While on, dictations skip history, context capture and transcript logging.
+    func setIncognitoMode(_ enabled: Bool) {
+        SettingsStore.shared.update { $0.incognitoModeEnabled = enabled }
+        Logger.shared.info("AppDelegate: Incognito mode \(enabled ? "on" : "off")")
+    }
+
+    /// Keeps VocaGlyph's windows above other apps while setting up or troubleshooting.
+    func setAlwaysOnTop(_ enabled: Bool) {
+        SettingsStore.shared.update { $0.alwaysOnTop = enabled }
+        Logger.shared.info("AppDelegate: Always on top \(enabled ? "on" : "off")")
+    }
+
+    /// With `alwaysOnTop`, Settings and onboarding float and follow the user into
+    /// full-screen spaces, and the HUD rises to the status-bar level.
+    private func applyAlwaysOnTop() {
+        let onTop = SettingsStore.shared.settings.alwaysOnTop
+        for window in [settingsWindow, onboardingWindow].compactMap({ $0 }) {
+            window.level = onTop ? .floating : .normal
+            window.collectionBehavior = onTop ? [.canJoinAllSpaces, .fullScreenAuxiliary] : []
+        }
+        OverlayPanelManager.shared.setAlwaysOnTop(onTop)
+    }
+
+    /// Updates the tray checkmark and, when idle, the tray icon.
+    private func refreshIncognitoIndicators() {
+        incognitoMenuItem?.state = SettingsStore.shared.settings.incognitoModeEnabled ? .on : .off
+        if stateManager.currentState.isResting {
+            statusItem?.button?.image = statusImage(for: stateManager.currentState)
+            let state = stateManager.currentState
+            controlSocketService.publish(ControlEvent(state: state, icon: trayIcon(for: state)))
+        }
+    }
+
+    /// The tray icon for `state` in the chosen `TrayIconTheme`. While resting this is the
+    /// paused or incognito icon when those are on.
+    private func statusImage(for state: AppState) -> NSImage? {
+        let theme = TrayIconTheme(rawValue: SettingsStore.shared.settings.trayIconTheme) ?? .standard
+        return theme.image(for: trayIcon(for: state))
+    }
+
+    private func trayIcon(for state: AppState) -> TrayIcon {
+        TrayIcon.for(state,
+                     dictationPaused: stateManager.isDictationPaused,
+                     incognito: SettingsStore.shared.settings.incognitoModeEnabled)
+    }
+
+    /// Re-applies the icon after the theme or flashing setting changes.
+    private func refreshTrayIcon() {
+        statusItem?.button?.image = statusImage(for: stateManager.currentState)
+        updateRecordingFlash(for: stateManager.currentState)
+    }
+
+    /// Blinks the icon while recording when `trayIconFlashesWhileRecording` is on, unless
+    /// the user asked macOS to reduce motion.
+    private func updateRecordingFlash(for state: AppState) {
+        recordingFlashTimer?.invalidate()
+        recordingFlashTimer = nil
+        statusItem?.button?.alphaValue = 1
+        guard state == .recording,
+              SettingsStore.shared.settings.trayIconFlashesWhileRecording,
+              !NSWorkspace.shared.accessibilityDisplayShouldReduceMotion else { return }
+        recordingFlashTimer = Timer.scheduledTimer(withTimeInterval: 0.6, repeats: true) { [weak self] _ in
+            guard let button = self?.statusItem?.button else { return }
+            button.alphaValue = button.alphaValue < 1 ? 1 : 0.3
+        }
+    }
+
+    /// `voicetotext://…` URLs from Shortcuts, Raycast or `open`.
+    public func application(_ application: NSApplication, open urls: [URL]) {
+        urls.forEach { urlSchemeHandler.handle($0) }
+    }
+
+    // MARK: - Dock Icon
+
+    /// `.regular` when the user keeps the Dock icon, otherwise `.accessory` (menu bar only).
+    private var restingActivationPolicy: NSApplication.ActivationPolicy {
+        SettingsStore.shared.settings.showDockIcon ? .regular : .accessory
+    }
+
+    /// Applies `showDockIcon` live. With a window open the app is `.regular` anyway.
+    private func applyDockIconSetting() {
+        let windowOpen = (settingsWindow?.isVisible ?? false) || onboardingWindow != nil
+        if !windowOpen {
+            NSApp.setActivationPolicy(restingActivationPolicy)
+        }
+        updateDockBadge(for: stateManager.currentState)
+    }
+
+    /// Mirrors the tray state on the Dock icon while it is shown.
+    private func updateDockBadge(for state: AppState) {
+        let settings = SettingsStore.shared.settings
+        let icon = TrayIcon.for(state,
+                                dictationPaused: stateManager.isDictationPaused,
+                                incognito: settings.incognitoModeEnabled)
+        NSApp.dockTile.badgeLabel = settings.showDockIcon ? icon.dockBadge : nil
+    }
+
+    /// Clicking the Dock icon with no window open shows Settings.
+    public func applicationShouldHandleReopen(_ sender: NSApplication, hasVisibleWindows flag: Bool) -> Bool {
+        if !flag, settingsWindow != nil {
+            toggleSettingsWindow(nil)
+        }
+        return true
+    }
+
     // MARK: - Window Actions
     @objc func toggleSettingsWindow(_ sender: AnyObject?) {
         if settingsWindow.isVisible {
             settingsWindow.orderOut(nil)
             // Revert to accessory so the app disappears from Cmd+Tab and Dock
             // now that no window is visible.
-            NSApp.setActivationPolicy(.accessory)
+            NSApp.setActivationPolicy(restingActivationPolicy)
         } else {
             // Switch to .regular so the app appears in Cmd+Tab and can become
             // a true key window. .accessory prevents the window from fully
@@ -336,6 +787,152 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         }
     }
 
+    // MARK: - Output Style Submenu
+
+    /// Lists every `OutputStyle` with a checkmark on the selected one. A spoken
+    /// command only affects its own dictation and never changes the checkmark.
+    func rebuildOutputStyleSubmenu() {
+        guard let submenu = outputStyleMenuItem?.submenu else { return }
+        submenu.removeAllItems()
+
+        let current = SettingsStore.shared.settings.outputStyle
+        for style in OutputStyle.allCases {
+            let item = NSMenuItem(title: style.title, action: #selector(selectOutputStyle(_:)), keyEquivalent: "")
+            item.target = self
+            item.representedObject = style.rawValue
+            item.state = style.rawValue == current ? .on : .off
+            submenu.addItem(item)
+        }
+    }
+
+    @objc private func selectOutputStyle(_ sender: NSMenuItem) {
+        guard let rawValue = sender.representedObject as? String else { return }
+        SettingsStore.shared.update { $0.outputStyle = rawValue }
+        Logger.shared.info("AppDelegate: Output style set to '\(rawValue)'")
+        rebuildOutputStyleSubmenu()
+    }
+
+    // MARK: - Quick Panel
+
+    /// Opens the popover once the menu has closed, anchored to the status item.
+    @MainActor @objc private func showQuickPanel(_ sender: Any?) {
+        DispatchQueue.main.async { [weak self] in
+            guard let self, let button = self.statusItem?.button, !self.quickPanel.isShown else { return }
+            self.quickPanel.show(relativeTo: button)
+        }
+    }
+
+    // MARK: - Pause, Model & Language
+
+    /// Same transitions as the "pause dictation" / "resume dictation" voice commands.
+    @objc private func togglePauseFromMenu(_ sender: NSMenuItem) {
+        dictationAPI.togglePause()
+    }
+
+    func refreshPauseMenuItem() {
+        guard let pauseMenuItem else { return }
+        let paused = stateManager.isDictationPaused
+        pauseMenuItem.state = paused ? .on : .off
+        pauseMenuItem.isEnabled = dictationAPI.canChangePause
+    }
+
+    /// Models offered in the tray: Apple Native first, then downloaded models by title.
+    /// The selected model is kept even if it is no longer on disk, so its checkmark shows.
+    /// whisper.cpp counts as downloaded once it's installed and has a model file.
+    static func trayModels(downloaded: Set<String>, selected: String) -> [(id: String, title: String)] {
+        let ids = downloaded
+            .union(SettingsValidator.builtInTranscriptionModels.subtracting([WhisperCppEngine.modelID]))
+            .union([selected])
+        return ids
+            .map { (id: $0, title: modelMenuTitle(for: $0)) }
+            .sorted { lhs, rhs in
+                if SettingsValidator.builtInTranscriptionModels.contains(lhs.id) != SettingsValidator.builtInTranscriptionModels.contains(rhs.id) {
+                    return SettingsValidator.builtInTranscriptionModels.contains(lhs.id)
+                }
+                return lhs.title.localizedStandardCompare(rhs.title) == .orderedAscending
+            }
+    }
+
+    /// Short titles for the built-in models; custom models use their own title.
+    static func modelMenuTitle(for id: String) -> String {
+        let titles = [
+            "apple-native": "Apple Native",
+            WhisperCppEngine.modelID: "whisper.cpp",
+            "parakeet-v3": "Parakeet v3", "parakeet-v2": "Parakeet v2",
+            "small": "Whisper Small", "medium": "Whisper Medium",
+            "large-v3": "Whisper Large v3", "large-v3_turbo": "Whisper Large v3 Turbo",
+            "large-v3-v20240930_626MB": "Whisper Large v3 Quantized",
+            "distil-whisper_distil-large-v3": "Distil Large v3",
+        ]
+        return titles[id] ?? ModelRegistry.shared.model(id: id)?.title ?? id
+    }
+
+    func rebuildModelSubmenu() {
+        guard let submenu = modelMenuItem?.submenu, whisper != nil, parakeet != nil else { return }
+        submenu.removeAllItems()
+
+        let selected = SettingsStore.shared.settings.selectedModel
+        for model in Self.trayModels(downloaded: modelsAPI.downloadedModelIds() ?? [], selected: selected) {
+            let item = NSMenuItem(title: model.title, action: #selector(selectModelFromMenu(_:)), keyEquivalent: "")
+            item.target = self
+            item.representedObject = model.id
+            item.state = model.id == selected ? .on : .off
+            submenu.addItem(item)
+        }
+    }
+
+    func rebuildLanguageSubmenu() {
+        guard let submenu = languageMenuItem?.submenu else { return }
+        submenu.removeAllItems()
+
+        let selected = SettingsStore.shared.settings.dictationLanguage
+        for label in WhisperService.supportedDictationLanguages {
+            let item = NSMenuItem(title: label, action: #selector(selectLanguageFromMenu(_:)), keyEquivalent: "")
+            item.target = self
+            item.representedObject = label
+            item.state = label == selected ? .on : .off
+            submenu.addItem(item)
+            if label == "Auto-Detect" { submenu.addItem(NSMenuItem.separator()) }
+        }
+    }
+
+    @objc private func selectModelFromMenu(_ sender: NSMenuItem) {
+        guard let model = sender.representedObject as? String else { return }
+        if let rejection = modelsAPI.switchModel(to: model) {
+            Logger.shared.error("AppDelegate: Model '\(model)' from tray rejected — \(rejection)")
+        }
+    }
+
+    @objc private func selectLanguageFromMenu(_ sender: NSMenuItem) {
+        guard let label = sender.representedObject as? String else { return }
+        if let rejection = settingsAPI.switchLanguage(to: label) {
+            Logger.shared.error("AppDelegate: Language '\(label)' from tray rejected — \(rejection)")
+        }
+    }
+
+    // MARK: - Change Case Submenu
+
+    /// One item per `TextCase`; disabled until something has been pasted.
+    func rebuildChangeCaseSubmenu() {
+        guard let submenu = changeCaseMenuItem?.submenu else { return }
+        submenu.removeAllItems()
+
+        let hasOutput = output?.lastInsertion != nil
+        for textCase in TextCase.allCases {
+            let item = NSMenuItem(title: textCase.title, action: hasOutput ? #selector(changeCaseFromMenu(_:)) : nil, keyEquivalent: "")
+            item.target = self
+            item.representedObject = textCase.rawValue
+            item.isEnabled = hasOutput
+            submenu.addItem(item)
+        }
+    }
+
+    @objc private func changeCaseFromMenu(_ sender: NSMenuItem) {
+        guard let rawValue = sender.representedObject as? String,
+              let textCase = TextCase(rawValue: rawValue) else { return }
+        dictationAPI.changeCaseOfLastOutput(to: textCase)
+    }
+
     // MARK: - Microphone Submenu
 
     /// Rebuilds the Microphone submenu with the current device list.
@@ -377,6 +974,136 @@ public class AppDelegate: NSObject, NSApplicationDelegate {
         microphoneService.select(device)
         rebuildMicrophoneSubmenu()
     }
+
+    // MARK: - Recent Transcriptions Submenu
+
+    /// Number of history entries listed in the status-bar menu.
+    static let recentTranscriptionsMenuLimit = 5
+
+    /// Rebuilds the Recent Transcriptions submenu from history. Clicking an entry copies
+    /// it to the clipboard; holding Option turns the entries into "Paste" items. Also
+    /// rebuilt on `HistoryChangedEvent`, so results appear while the menu is open.
+    @MainActor
+    func rebuildRecentTranscriptionsSubmenu() {
+        guard let submenu = recentTranscriptionsMenuItem?.submenu else { return }
+        submenu.removeAllItems()
+
+        let items = historyAPI.recent(limit: Self.recentTranscriptionsMenuLimit)
+        guard !items.isEmpty else {
+            let empty = NSMenuItem(title: "No Transcriptions Yet", action: nil, keyEquivalent: "")
+            empty.isEnabled = false
+            submenu.addItem(empty)
+            return
+        }
+
+        for item in items {
+            let title = Self.menuTitle(for: item.text)
+
+            let copy = NSMenuItem(title: title, action: #selector(copyRecentTranscription(_:)), keyEquivalent: "")
+            copy.target = self
+            copy.representedObject = item.id
+            copy.toolTip = item.text
+            submenu.addItem(copy)
+
+            let paste = NSMenuItem(title: "Paste “\(title)”", action: #selector(repasteRecentTranscription(_:)), keyEquivalent: "")
+            paste.target = self
+            paste.representedObject = item.id
+            paste.keyEquivalentModifierMask = .option
+            paste.isAlternate = true
+            submenu.addItem(paste)
+        }
+    }
+
+    /// First line of `text`, shortened to fit a menu.
+    static func menuTitle(for text: String, maxLength: Int = 40) -> String {
+        let line = text.split(whereSeparator: \.isNewline).first.map(String.init) ?? text
+        let trimmed = line.trimmingCharacters(in: .whitespaces)
+        return trimmed.count > maxLength ? String(trimmed.prefix(maxLength)) + "…" : trimmed
+    }
+
+    @MainActor @objc private func repasteRecentTranscription(_ sender: NSMenuItem) {
+        guard let id = sender.representedObject as? UUID else { return }
+        historyAPI.repaste(id)
+    }
+
+    @MainActor @objc private func copyRecentTranscription(_ sender: NSMenuItem) {
+        guard let id = sender.representedObject as? UUID else { return }
+        historyAPI.copy(id)
+    }
+
+    // MARK: - Snippets Submenu
+
+    /// Rebuilds the Snippets submenu. Clicking a snippet pastes it into the frontmost app.
+    @MainActor
+    func rebuildSnippetsSubmenu() {
+        guard let submenu = snippetsMenuItem?.submenu else { return }
+        submenu.removeAllItems()
+
+        let snippets = historyAPI.allSnippets()
+        guard !snippets.isEmpty else {
+            let empty = NSMenuItem(title: "Pin a transcription in History to add one", action: nil, keyEquivalent: "")
+            empty.isEnabled = false
+            submenu.addItem(empty)
+            return
+        }
+
+        for snippet in snippets {
+            let item = NSMenuItem(title: snippet.name, action: #selector(pasteSnippetFromMenu(_:)), keyEquivalent: "")
+            item.target = self
+            item.representedObject = snippet.id
+            item.toolTip = snippet.text
+            submenu.addItem(item)
+        }
+    }
+
+    @MainActor @objc private func pasteSnippetFromMenu(_ sender: NSMenuItem) {
+        guard let id = sender.representedObject as? UUID else { return }
+        historyAPI.pasteSnippet(id)
+    }
+
+    // MARK: - Dictation Templates Submenu
+
+    /// Rebuilds the Dictation Templates submenu. Clicking a template starts filling it;
+    /// the one being filled is checked and can be cancelled.
+    @MainActor
+    func rebuildDictationTemplatesSubmenu() {
+        guard let submenu = dictationTemplatesMenuItem?.submenu else { return }
+        submenu.removeAllItems()
+
+        let templates = dictationAPI.allTemplates()
+        guard !templates.isEmpty else {
+            let empty = NSMenuItem(title: "Add one in Settings → Writing Assistant", action: nil, keyEquivalent: "")
+            empty.isEnabled = false
+            submenu.addItem(empty)
+            return
+        }
+
+        let active = dictationAPI.activeTemplate
+        if let active {
+            let cancel = NSMenuItem(title: "Cancel \"\(active.name)\"", action: #selector(cancelDictationTemplateFromMenu), keyEquivalent: "")
+            cancel.target = self
+            submenu.addItem(cancel)
+            submenu.addItem(NSMenuItem.separator())
+        }
+
+        for template in templates {
+            let item = NSMenuItem(title: template.name, action: #selector(startDictationTemplateFromMenu(_:)), keyEquivalent: "")
+            item.target = self
+            item.representedObject = template.id
+            item.toolTip = template.body
+            item.state = active?.templateID == template.id ? .on : .off
+            submenu.addItem(item)
+        }
+    }
+
+    @MainActor @objc private func startDictationTemplateFromMenu(_ sender: NSMenuItem) {
+        guard let id = sender.representedObject as? UUID else { return }
+        dictationAPI.startTemplate(id)
+    }
+
+    @MainActor @objc private func cancelDictationTemplateFromMenu() {
+        dictationAPI.cancelTemplate()
+    }
 }
 
 // MARK: - NSMenuDelegate
@@ -385,7 +1112,8 @@ extension AppDelegate: NSMenuDelegate {
         guard menu === statusItem.menu else { return }
 
         // Keep "Check for Updates…" in sync with Sparkle's internal state.
-        checkForUpdatesMenuItem?.isEnabled = checkForUpdatesViewModel.canCheckForUpdates
+        checkForUpdatesMenuItem?.isEnabled = updateService.canCheckForUpdates
+        runtimeStatusMenuItem?.title = runtimeStatus().message
 
         // Refresh device list and rebuild the submenu each time the status-bar
         // menu is about to open, so newly connected devices are visible immediately.
@@ -394,6 +1122,15 @@ extension AppDelegate: NSMenuDelegate {
             rebuildMicrophoneSubmenu()
             _ = subMenu // suppress unused warning
         }
+
+        refreshPauseMenuItem()
+        rebuildModelSubmenu()
+        rebuildLanguageSubmenu()
+        rebuildOutputStyleSubmenu()
+        rebuildChangeCaseSubmenu()
+        rebuildRecentTranscriptionsSubmenu()
+        rebuildSnippetsSubmenu()
+        rebuildDictationTemplatesSubmenu()
     }
 }
 
@@ -414,7 +1151,7 @@ extension AppDelegate: NSWindowDelegate {
         } else if window === settingsWindow {
             // Settings closed via the red ✕ button — revert to .accessory so the
             // app disappears from Cmd+Tab and the Dock.
-            NSApp.setActivationPolicy(.accessory)
+            NSApp.setActivationPolicy(restingActivationPolicy)
         }
     }
 }
@@ -439,141 +1176,861 @@ extension AppDelegate: AudioRecorderConfigChangeDelegate {
 extension AppDelegate: AppStateManagerDelegate {
     // MARK: - AppStateManagerDelegate
     func appStateDidChange(newState: AppState) {
+        controlSocketService.publish(ControlEvent(state: newState, icon: trayIcon(for: newState)))
         guard let button = statusItem?.button else {
             // statusItem may not be set up yet during app startup — ignore.
             Logger.shared.info("AppDelegate: appStateDidChange(\(newState)) skipped — statusItem not ready yet.")
             return
         }
+        button.toolTip = nil
+        button.attributedTitle = NSAttributedString()
+        button.image = statusImage(for: newState)
+        updateRecordingFlash(for: newState)
+        quitDrainer?.stateDidChange(newState)
+        updateDockBadge(for: newState)
+        if newState == .recording { idleMonitor?.noteActivity() }
         switch newState {
-        case .idle:
+        case .idle, .paused:
 
             // Let HotkeyService know it can accept the next hotkey press.
             hotkeyService.resetToIdle()
-            if let imgUrl = Bundle.main.url(forResource: "appbaricon", withExtension: "png")
-                         ?? Bundle.module.url(forResource: "appbaricon", withExtension: "png"),
-               let nsImage = NSImage(contentsOf: imgUrl) {
-                nsImage.size = NSSize(width: 18, height: 18)
-                nsImage.isTemplate = false
-                button.image = nsImage
-            } else {
-                button.image = NSImage(systemSymbolName: "mic.fill", accessibilityDescription: "VocaGlyph")
-            }
+            dictationAPI.dictationDidRest()
+            captionServer?.dictationEnded()
+        case .error(let message):
+            hotkeyService.resetToIdle()
+            dictationAPI.dictationDidRest()
+            captionServer?.dictationEnded()
+            button.toolTip = message
+            NotificationService.shared.post(.transcriptionFailed(message: message))
         case .initializing:
-            let img = NSImage(systemSymbolName: "gearshape.fill", accessibilityDescription: "initializing")
-            let config = NSImage.SymbolConfiguration(paletteColors: [.systemYellow])
-            button.image = img?.withSymbolConfiguration(config)
+            break
         case .recording:
-            let img = NSImage(systemSymbolName: "waveform.circle.fill", accessibilityDescription: "recording")
-            let config = NSImage.SymbolConfiguration(paletteColors: [.systemRed])
-            button.image = img?.withSymbolConfiguration(config)
-
-            // Run AVAudioEngine.start() on a background serial queue so it never
-            // blocks the main thread. On the very first launch the engine can take
-            // hundreds of milliseconds to settle after mic permission is granted;
-            // doing this on the main thread froze the run loop and prevented the
-            // queued stopRecording() dispatch from ever executing.
-            isStartingRecording = true
-            audioQueue.async { [weak self] in
+            dictationPipeline.startCapture { [weak self] error in
                 guard let self else { return }
-                do {
-                    try self.audioRecorder.startRecording()
-                } catch {
-                    Logger.shared.error("AppDelegate: audioRecorder.startRecording() failed — \(error.localizedDescription). Resetting to idle.")
-                    DispatchQueue.main.async {
-                        self.isStartingRecording = false
-                        self.pendingStopBlock = nil
-                        self.stateManager.setIdle()
-                    }
-                    return
-                }
-                DispatchQueue.main.async {
-                    self.isStartingRecording = false
-                    // Drain any stop request that arrived while we were starting.
-                    if let stop = self.pendingStopBlock {
-                        self.pendingStopBlock = nil
-                        stop()
-                    }
+                Logger.shared.error("AppDelegate: Audio capture failed to start — \(error.localizedDescription).")
+                self.stateManager.setError("Couldn't start recording: \(error.localizedDescription)")
+                if case AudioRecorderError.microphoneAccessNotDetermined = error {
+                    // Show the system prompt now; the next press records.
+                    Task { _ = await self.permissionsService.ensureMicrophoneAccess() }
                 }
             }
 
         case .processing:
-            let img = NSImage(systemSymbolName: "hourglass.circle.fill", accessibilityDescription: "processing")
-            let config = NSImage.SymbolConfiguration(paletteColors: [.systemOrange])
-            button.image = img?.withSymbolConfiguration(config)
-
-            // If startRecording() is still in flight (fast key tap), queue the
-            // stop until it finishes. This prevents a stop-before-start race.
-            // stopRecording() runs on the audio queue because bufferQueue.sync{}
-            // and engine.stop() must not block the main thread.
-            let doStop = { [weak self] in
+            dictationPipeline.stopCapture { [weak self] buffer in
                 guard let self else { return }
-                self.audioQueue.async {
-                    let buffer = self.audioRecorder.stopRecording()
-                    DispatchQueue.main.async {
-                        if let buffer {
-                            Logger.shared.info("AppDelegate: Finished capturing audio segment.")
-                            self.stateManager.processAudio(buffer: buffer)
-                        } else {
-                            self.stateManager.setIdle()
-                        }
-                    }
+                if let buffer {
+                    Logger.shared.info("AppDelegate: Finished capturing audio segment.")
+                    self.stateManager.processAudio(buffer: buffer)
+                } else {
+                    self.stateManager.setIdle()
                 }
             }
-
-            if isStartingRecording {
-                // Store the stop block; it will be called when startRecording() completes.
-                pendingStopBlock = doStop
-            } else {
-                doStop()
-            }
         }
         
         // Update overlay panel
         OverlayPanelManager.shared.updateVisibility(for: newState)
     }
 
-    func appStateManagerDidTranscribe(text: String) {
-        // The transcription has successfully completed.
-        print("Final transcription output bound in AppDelegate: \(text)")
-        
-        // Save to local history (skip when Privacy Mode is active)
-        let privacyModeEnabled = UserDefaults.standard.bool(forKey: "privacyModeEnabled")
-        if !text.isEmpty, !privacyModeEnabled, let container = sharedModelContainer {
+    /// Applies a voice command through the settings and models APIs, so a spoken model
+    /// or language change is validated and loaded exactly like one made in Settings.
+    func appStateManagerDidRecognize(command: VoiceCommand) {
+        let rejection: String?
+        switch command {
+        case .switchLanguage(let label):
+            rejection = settingsAPI.switchLanguage(to: label)
+        case .useModel(let id):
+            rejection = modelsAPI.switchModel(to: id)
+        case .changeCase(let textCase):
+            dictationAPI.changeCaseOfLastOutput(to: textCase)
+            return
+        case .pauseDictation, .resumeDictation:
+            // Already applied: the state change to .paused / .idle updated the icon.
+            return
+        }
+        if let rejection {
+            Logger.shared.error("AppDelegate: Voice command '\(command.summary)' rejected — \(rejection)")
+        }
+    }
+
+    /// Shows the elapsed time next to the tray icon, in orange once the limit is close.
+    func appStateManagerRecordingDidTick(elapsed: Int, remaining: Int?) {
+        guard let button = statusItem?.button else { return }
+        let nearLimit = remaining.map { $0 <= AppStateManager.recordingWarningSeconds } ?? false
+        let text = nearLimit ? "\(AppStateManager.clockString(remaining ?? 0)) left" : AppStateManager.clockString(elapsed)
+        button.attributedTitle = NSAttributedString(string: " " + text, attributes: [
+            .font: NSFont.monospacedDigitSystemFont(ofSize: 12, weight: .medium),
+            .foregroundColor: nearLimit ? NSColor.systemOrange : NSColor.labelColor,
+        ])
+        button.imagePosition = .imageLeading
+    }
+
+    func appStateManagerRecordingWillReachLimit(remaining: Int) {
+        Logger.shared.info("AppDelegate: Recording stops in \(remaining)s (maximum length reached)")
+        NSSound(named: "Tink")?.play()
+    }
+
+    /// Lets the user pick the summary or the verbatim text. Dismissing pastes nothing
+    /// but still keeps the verbatim text in History.
+    func appStateManagerDidSummarize(verbatim: String, summary: String) {
+        SummaryChoicePanel.shared.present(verbatim: verbatim, summary: summary) { [weak self] choice in
+            guard let self else { return }
+            if let choice {
+                self.appStateManagerDidTranscribe(text: choice)
+                return
+            }
+            let settings = SettingsStore.shared.settings
+            guard !settings.privacyModeEnabled, !settings.incognitoModeEnabled else { return }
             Task { @MainActor in
-                let context = container.mainContext
-                let newItem = TranscriptionItem(text: text)
-                context.insert(newItem)
-                
-                self.cleanupOldHistoryItems(context: context)
-                
-                do {
-                    try context.save()
-                } catch {
-                    print("Failed to save new transcription item: \(error)")
-                }
+                self.historyService?.record(verbatim)
             }
         }
-        
-        DispatchQueue.main.async {
-            self.output.handleTranscriptionValue(text)
+    }
+
+    func appStateManagerDidTranscribe(text: String) {
+        dictationPipeline.deliver(text)
+    }
+
+    /// The "history" sink: saves `text` unless Privacy Mode or incognito mode is active.
+    private func recordInHistory(_ text: String) {
+        let settings = SettingsStore.shared.settings
+        guard !text.isEmpty, !settings.privacyModeEnabled, !settings.incognitoModeEnabled else { return }
+        Task { @MainActor in
+            self.historyService?.record(text)
         }
     }
-    
+}
+
+// MARK: - URLSchemeHandling
+extension AppDelegate: URLSchemeHandling {
+    func handleTrigger(_ command: ExternalTriggerCommand) {
+        dictationAPI.handleTrigger(command)
+    }
+
     @MainActor
-    private func cleanupOldHistoryItems(context: ModelContext) {
-        guard let thirtyDaysAgo = Calendar.current.date(byAdding: .day, value: -30, to: Date()) else { return }
-        
-        let fetchDescriptor = FetchDescriptor<TranscriptionItem>(
-            predicate: #Predicate { $0.timestamp < thirtyDaysAgo }
-        )
-        
+    func pasteLastTranscription() -> Bool {
+        historyAPI.pasteLast()
+    }
+
+    func openSettings() {
+        guard let settingsWindow else { return }
+        guard !settingsWindow.isVisible else {
+            settingsWindow.makeKeyAndOrderFront(nil)
+            NSApp.activate(ignoringOtherApps: true)
+            return
+        }
+        toggleSettingsWindow(nil)
+    }
+}
+
+// MARK: - QuickPanelTarget
+extension AppDelegate: QuickPanelTarget {
+    @MainActor
+    func quickPanelModels() -> [(id: String, title: String)] {
+        Self.trayModels(downloaded: modelsAPI.downloadedModelIds() ?? [],
+                        selected: SettingsStore.shared.settings.selectedModel)
+    }
+
+    @MainActor
+    func lastTranscriptionText() -> String? {
+        historyAPI.lastText()
+    }
+
+    @MainActor
+    func canChangeDictationPause() -> Bool {
+        dictationAPI.canChangePause
+    }
+
+    @MainActor
+    func setDictationPaused(_ paused: Bool) {
+        dictationAPI.setPaused(paused)
+    }
+
+    @MainActor
+    func copyToClipboard(_ text: String) {
+        output?.copyToPasteboard(text: text)
+    }
+}
+
+// MARK: - ControlAPITarget
+extension AppDelegate: ControlAPITarget {
+    func applyExternalTriggersSetting() {
+        if SettingsStore.shared.settings.externalTriggersEnabled {
+            externalTriggerService.start()
+        } else {
+            externalTriggerService.stop()
+        }
+    }
+
+    func applyControlAPISetting() {
+        guard SettingsStore.shared.settings.controlAPIEnabled else {
+            controlSocketService.stop()
+            return
+        }
+        do {
+            try controlSocketService.start()
+        } catch {
+            Logger.shared.error("AppDelegate: Control API not started — \(error.localizedDescription)")
+        }
+    }
+
+    func applyTranscriptionServiceSetting() {
+        guard SettingsStore.shared.settings.transcriptionServiceEnabled else {
+            transcriptionServer.stop()
+            return
+        }
+        do {
+            try transcriptionServer.start()
+        } catch {
+            Logger.shared.error("AppDelegate: Transcription service not started — \(error.localizedDescription)")
+        }
+    }
+
+    /// Starts, restarts or stops `captionServer` to match the settings, and feeds it the
+    /// live preview while it runs. An out-of-range port leaves captions off;
+    /// `SettingsValidator` reports it.
+    func applyLiveCaptionsSetting() {
+        let settings = SettingsStore.shared.settings
+        let port = settings.liveCaptionsEnabled && CaptionServer.portRange.contains(settings.liveCaptionsPort)
+            ? UInt16(settings.liveCaptionsPort) : nil
+        if let captionServer, captionServer.port == port { return }
+        partialCaptionCancellable = nil
+        captionServer?.stop()
+        captionServer = nil
+        guard let port else { return }
+        let server = CaptionServer(port: port)
+        do {
+            try server.start()
+        } catch {
+            Logger.shared.error("AppDelegate: Live captions not started on port \(port) — \(error.localizedDescription)")
+            return
+        }
+        captionServer = server
+        partialCaptionCancellable = stateManager.$partialTranscript
+            .compactMap { $0 }
+            .sink { [weak server] text in server?.sendPartial(text) }
+    }
+
+    /// What the app can do right now, for the control API, the tray and the quick panel.
+    @MainActor
+    func runtimeStatus() -> RuntimeStatus {
+        let model = SettingsStore.shared.settings.selectedModel
+        return RuntimeStatus.derive(state: stateManager.currentState,
+                                    dictationPaused: stateManager.isDictationPaused,
+                                    modelTitle: Self.modelMenuTitle(for: model),
+                                    modelStatus: modelsAPI.status(for: model),
+                                    lastOutputWasClipboardOnly: output?.lastOutputWasClipboardOnly ?? false,
+                                    microphoneBlocked: permissionsService.isMicrophoneBlocked,
+                                    slowTranscriptionFactor: stateManager.metrics.slowTranscriptionFactor)
+    }
+
+    @MainActor
+    func controlStatus() -> ControlStatus {
+        let settings = SettingsStore.shared.settings
+        let runtime = runtimeStatus()
+        return ControlStatus(state: stateManager.currentState.name,
+                             model: settings.selectedModel,
+                             language: settings.dictationLanguage,
+                             paused: stateManager.isDictationPaused,
+                             activity: runtime.name,
+                             message: runtime.message,
+                             progress: runtime.progress,
+                             profile: activeProfile()?.name)
+    }
+
+    @MainActor
+    func controlMetrics() -> MetricsSnapshot {
+        dictationAPI.metrics()
+    }
+
+    @MainActor
+    func switchModel(to model: String) -> String? {
+        modelsAPI.switchModel(to: model)
+    }
+
+    @MainActor
+    func switchLanguage(to language: String) -> String? {
+        settingsAPI.switchLanguage(to: language)
+    }
+
+    /// Runs the WAV file at `path` through the same states, engine, text processing and
+    /// sinks as a hotkey dictation, so a pipeline problem can be reproduced with the
+    /// same audio every time. The focused app's context isn't captured for it.
+    @MainActor
+    func replay(fileAt path: String) -> String? {
+        guard dictationPipeline.source != nil else { return "VocaGlyph is not ready." }
+        switch stateManager.currentState {
+        case .initializing: return "The model is still loading."
+        case .recording, .processing: return "A dictation is already in progress."
+        default: break
+        }
+        let source: ReplayAudioSource
         do {
-            let oldItems = try context.fetch(fetchDescriptor)
-            for item in oldItems {
-                context.delete(item)
+            source = try ReplayAudioSource(file: URL(fileURLWithPath: (path as NSString).expandingTildeInPath))
+        } catch {
+            return error.localizedDescription
+        }
+        Logger.shared.info("AppDelegate: Replaying \(source.file.lastPathComponent)")
+        dictationPipeline.nextSource = source
+        stateManager.startRecording(capturingContext: false)
+        guard stateManager.currentState == .recording else {
+            dictationPipeline.nextSource = nil
+            return "Couldn't start the dictation."
+        }
+        stateManager.stopRecording()
+        return nil
+    }
+
+    @MainActor
+    func dictateToClipboard() {
+        dictationAPI.toggleClipboardDictation()
+    }
+
+    @MainActor
+    func togglePause() -> String? {
+        guard dictationAPI.canChangePause else { return "Dictation can only be paused between recordings." }
+        dictationAPI.togglePause()
+        return nil
+    }
+
+    /// Profiles are the AI post-processing templates; switching one changes the prompt
+    /// used when AI post-processing is on.
+    @MainActor
+    func switchProfile(to profile: String) -> String? {
+        guard let context = sharedModelContainer?.mainContext else { return "VocaGlyph is not ready." }
+        let templates = (try? context.fetch(FetchDescriptor<PostProcessingTemplate>(sortBy: [SortDescriptor(\.name)]))) ?? []
+        guard let template = PostProcessingTemplate.matching(profile, in: templates) else {
+            return "Unknown profile '\(profile)'. Known profiles: \(templates.map(\.name).joined(separator: ", "))."
+        }
+        UserDefaults.standard.set(template.id.uuidString, forKey: TemplateSeederService.activeTemplateKey)
+        Logger.shared.info("AppDelegate: Active template changed to '\(template.name)'")
+        return nil
+    }
+
+    @MainActor
+    private func activeProfile() -> PostProcessingTemplate? {
+        guard let context = sharedModelContainer?.mainContext,
+              let id = UserDefaults.standard.string(forKey: TemplateSeederService.activeTemplateKey)
+                .flatMap(UUID.init(uuidString:)) else { return nil }
+        return try? context.fetch(FetchDescriptor<PostProcessingTemplate>(predicate: #Predicate { $0.id == id })).first
+    }
+
+    /// Stream Deck keys: tap to toggle, hold for push-to-talk.
+    @MainActor
+    func handleKey(pressed: Bool) {
+        let command = pressed
+            ? pushToTalkKey.keyDown(isRecording: stateManager.currentState == .recording)
+            : pushToTalkKey.keyUp()
+        if let command { dictationAPI.handleTrigger(command) }
+    }
+
+    /// The engine's transcript of a WAV file, as the transcription service returns it:
+    /// no text processing, nothing pasted or recorded.
+    @MainActor
+    func transcribeFile(atPath path: String) async throws -> String {
+        guard let router = stateManager.engineRouter else { throw TranscriptionServerError.notReady }
+        let url = URL(fileURLWithPath: (path as NSString).expandingTildeInPath)
+        guard let data = try? Data(contentsOf: url) else {
+            throw TranscriptionServerError.invalidAudio("cannot read \(url.path)")
+        }
+        return try await router.transcribe(audioBuffer: TranscriptionServer.wavBuffer(data))
+    }
+
+    /// `--replay`: waits for the model to load, as `AppStateManager.startEngine()` does
+    /// before warming up the LLM, then replays `path` once.
+    func replayOnLaunch(_ path: String) {
+        Task { @MainActor in
+            try? await Task.sleep(nanoseconds: 500_000_000) // let the model load begin
+            var iterations = 0
+            while stateManager.currentState == .initializing && iterations < 120 {
+                try? await Task.sleep(nanoseconds: 500_000_000)
+                iterations += 1
+            }
+            if let rejection = replay(fileAt: path) {
+                Logger.shared.error("AppDelegate: --replay \(path) failed — \(rejection)")
+            }
+        }
+    }
+}
+
+// MARK: - Dictation Templates
+extension AppDelegate {
+    /// The "template" sink: a template being filled takes the text; only the completed
+    /// template goes on to be recorded and pasted.
+    fileprivate func fillDictationTemplateIfActive(with text: String) -> Bool {
+        guard dictationAPI.activeTemplate != nil, !text.isEmpty else { return false }
+        Task { @MainActor in
+            self.fillDictationTemplate(with: text)
+        }
+        return true
+    }
+
+    /// Puts `text` into the next slot; the completed template is recorded and pasted
+    /// like a normal transcription.
+    @MainActor
+    private func fillDictationTemplate(with text: String) {
+        guard let fill = dictationAPI.fillTemplate(with: text) else { return }
+        guard fill.isComplete else {
+            Logger.shared.info("AppDelegate: Filled slot in '\(fill.name)', next '\(fill.nextSlot ?? "")'")
+            return
+        }
+        Logger.shared.info("AppDelegate: Completed template '\(fill.name)'")
+        appStateManagerDidTranscribe(text: fill.rendered)
+    }
+}
+
+// MARK: - Quitting
+extension AppDelegate {
+    /// With `quitWaitsForTranscription`, a dictation that is still recording or being
+    /// transcribed is finished and pasted before the app quits, for up to `quitWaitSeconds`.
+    /// A recording in progress is stopped so what was said so far is transcribed.
+    public func applicationShouldTerminate(_ sender: NSApplication) -> NSApplication.TerminateReply {
+        guard quitDrainer == nil else {
+            // Quit again while waiting: stop waiting.
+            quitDrainer?.cancel()
+            quitDrainer = nil
+            return .terminateNow
+        }
+        let state = stateManager.currentState
+        guard SettingsStore.shared.settings.quitWaitsForTranscription,
+              state == .recording || state == .processing else { return .terminateNow }
+
+        // Stopping clears the live preview, so keep it for the timeout prompt.
+        let preview = stateManager.partialTranscript
+        if state == .recording {
+            stateManager.stopRecording()
+        }
+        // No new dictation may start while this one finishes.
+        hotkeyService?.stop()
+        drainBeforeQuitting(preview: preview)
+        return .terminateLater
+    }
+
+    private func drainBeforeQuitting(preview: String?) {
+        let drainer = QuitDrainer(timeout: SettingsStore.shared.settings.quitWaitSeconds)
+        quitDrainer = drainer
+        drainer.begin(onProgress: { [weak self] remaining in
+            guard let button = self?.statusItem?.button else { return }
+            button.attributedTitle = NSAttributedString(string: " Finishing… \(remaining)s", attributes: [
+                .font: NSFont.monospacedDigitSystemFont(ofSize: 12, weight: .medium),
+            ])
+            button.imagePosition = .imageLeading
+        }, completion: { [weak self] outcome in
+            self?.quitDrainer = nil
+            self?.finishQuitting(after: outcome, preview: preview)
+        })
+    }
+
+    private func finishQuitting(after outcome: QuitDrainer.Outcome, preview: String?) {
+        guard outcome == .timedOut else {
+            // The paste keystroke is sent a moment after the state returns to idle.
+            DispatchQueue.main.asyncAfter(deadline: .now() + 0.3) {
+                NSApp.reply(toApplicationShouldTerminate: true)
             }
+            return
+        }
+
+        let alert = NSAlert()
+        alert.messageText = "VocaGlyph is still transcribing"
+        alert.informativeText = "Quitting now loses the dictation in progress."
+        alert.addButton(withTitle: "Keep Waiting")
+        alert.addButton(withTitle: "Quit Anyway")
+        let canCopyPreview = !(preview ?? "").isEmpty
+        if canCopyPreview {
+            alert.addButton(withTitle: "Copy Preview and Quit")
+        }
+        NSApp.activate(ignoringOtherApps: true)
+        let response = alert.runModal()
+
+        if response == .alertFirstButtonReturn, !stateManager.currentState.isResting {
+            drainBeforeQuitting(preview: preview)
+            return
+        }
+        if response == .alertThirdButtonReturn, canCopyPreview, let preview {
+            output?.copyToPasteboard(text: preview)
+            Logger.shared.info("AppDelegate: Copied the live preview before quitting")
+        }
+        NSApp.reply(toApplicationShouldTerminate: true)
+    }
+}
+
+// MARK: - Accessibility
+extension AppDelegate {
+    /// Called by `accessibilityWatcher` when the user grants Accessibility. Pasting and
+    /// context capture check the permission on every use; the event tap was refused at
+    /// launch and has to be created again.
+    @MainActor func accessibilityWasGranted() {
+        Logger.shared.info("AppDelegate: Accessibility granted — enabling paste, context capture and the hotkey")
+        hotkeyService?.restart(reason: "accessibility granted")
+        output?.resetClipboardFallback()
+    }
+}
+
+// MARK: - Idle Reduction
+extension AppDelegate {
+    /// Creates, retunes or removes `idleMonitor` to match the settings.
+    @MainActor func applyIdleReductionSetting() {
+        let settings = SettingsStore.shared.settings
+        guard settings.idleReductionEnabled else {
+            idleMonitor?.stop()
+            idleMonitor = nil
+            return
+        }
+        let timeout = TimeInterval(settings.idleReductionMinutes * 60)
+        if let idleMonitor {
+            idleMonitor.timeout = timeout
+            return
+        }
+        let monitor = IdleMonitor(timeout: timeout)
+        monitor.onIdle = { [weak self] in self?.reduceBackgroundWork() }
+        monitor.onResume = { [weak self] in self?.restoreBackgroundWork() }
+        monitor.start()
+        idleMonitor = monitor
+    }
+
+    /// Stops polling the regex rules file and, if enabled, frees the models. The level
+    /// meter and tray animation already run only while recording.
+    @MainActor func reduceBackgroundWork() {
+        RegexRulesService.shared.stopWatching()
+        guard SettingsStore.shared.settings.idleReductionUnloadsModel else { return }
+        unloadedModelsWhileIdle = true
+        whisper?.unloadModel()
+        parakeet?.unloadModel()
+        Task { await stateManager.unloadLocalLLMEngine() }
+    }
+
+    /// Undoes `reduceBackgroundWork()` as the next dictation starts. An unloaded model
+    /// reloads in the background while the user speaks; the local LLM reloads on first use.
+    @MainActor func restoreBackgroundWork() {
+        RegexRulesService.shared.reload()
+        RegexRulesService.shared.startWatching()
+        guard unloadedModelsWhileIdle else { return }
+        unloadedModelsWhileIdle = false
+        let model = SettingsStore.shared.settings.selectedModel
+        if model.hasPrefix("parakeet-") {
+            parakeet?.changeModel(to: model)
+        } else if !SettingsValidator.builtInTranscriptionModels.contains(model) {
+            whisper?.changeModel(to: model)
+        }
+    }
+}
+
+// MARK: - Debug Server
+extension AppDelegate {
+    /// Serves the `DebugServer` profiling endpoints for this run. A port already in use
+    /// is logged; the app runs on without them.
+    @MainActor func startDebugServer(port: UInt16) {
+        let server = DebugServer(port: port) { [weak self] in
+            self?.stateManager.resourceUsage() ?? .sample(model: nil, audioBuffer: .empty)
+        }
+        do {
+            try server.start()
+            debugServer = server
         } catch {
-            print("Failed to fetch old items for cleanup: \(error)")
+            Logger.shared.error("AppDelegate: Debug server not started — \(error.localizedDescription)")
+        }
+    }
+}
+
+// MARK: - OTLP Export
+extension AppDelegate {
+    /// Starts, restarts or stops `otlpExporter` to match the settings. An invalid
+    /// endpoint leaves export off; `SettingsValidator` reports it.
+    @MainActor func applyOTLPExportSetting() {
+        let settings = SettingsStore.shared.settings
+        let endpoint = settings.otlpExportEnabled ? URL(string: settings.otlpEndpoint) : nil
+        if let otlpExporter, otlpExporter.endpoint == endpoint { return }
+        otlpExporter?.stop()
+        otlpExporter = nil
+        guard let endpoint, endpoint.host != nil else { return }
+        let exporter = OTLPExporter(endpoint: endpoint, metrics: stateManager.metrics)
+        exporter.start()
+        otlpExporter = exporter
+    }
+}
+
+// MARK: - Plugins
+extension AppDelegate {
+    /// Starts or stops `pluginHost` to match `pluginsEnabled`, and hands its processor
+    /// stage to `stateManager`. Turning plugins on again re-reads the plugins folder.
+    @MainActor func applyPluginsSetting() {
+        pluginHost?.stop()
+        pluginHost = nil
+        stateManager.pluginProcessor = nil
+        guard SettingsStore.shared.settings.pluginsEnabled else { return }
+        let host = PluginHost()
+        host.reload()
+        pluginHost = host
+        stateManager.pluginProcessor = host.processor
+    }
+
+    /// The "plugins" sink: sends `text` to the sink plugins, unless incognito mode is on.
+    fileprivate func deliverToPlugins(_ text: String) {
+        guard !text.isEmpty, !SettingsStore.shared.settings.incognitoModeEnabled else { return }
+        pluginHost?.deliver(text)
+    }
+}
+
+// MARK: - Sleep & Wake
+extension AppDelegate {
+    /// Re-registers the hotkey, re-checks the microphone and re-checks permissions after
+    /// the Mac wakes or the screen unlocks.
+    @MainActor func recoverAfterWake(reason: String) {
+        Logger.shared.info("AppDelegate: Recovering after wake (\(reason))")
+        hotkeyService.restart(reason: "wake")
+        microphoneService.revalidate(reason: "wake")
+
+        var missing: [String] = []
+        if !permissionsService.isMicrophoneAuthorized { missing.append("Microphone") }
+        if !permissionsService.isAccessibilityTrusted { missing.append("Accessibility") }
+        if !missing.isEmpty {
+            Logger.shared.error("AppDelegate: Permissions missing after wake — \(missing.joined(separator: ", "))")
+            NotificationService.shared.post(.permissionsMissing(names: missing))
+        }
+    }
+}
+
+// MARK: - Preference Hot-Reload & Settings Updates
+extension AppDelegate: SettingsApplying {
+    /// Re-applies preferences edited outside the app. `keys` comes from `PreferencesWatcher`;
+    /// the actual diff is computed against the cached snapshot so in-process writes
+    /// (already applied by the Settings UI) are not applied twice.
+    func applyReloadedPreferences(_ keys: Set<String>) {
+        // External writes don't post didChangeNotification — refresh the cache first.
+        let previous = SettingsStore.shared.settings
+        SettingsStore.shared.reload()
+        let current = SettingsStore.shared.settings
+
+        // Hand-edited values fail silently otherwise — surface them in the log.
+        for issue in SettingsValidator.validate(current, downloadedModels: modelsAPI.downloadedModelIds()) {
+            Logger.shared.error("AppDelegate: Invalid preference '\(issue.field)' — \(issue.message)")
+        }
+
+        var result = SettingsUpdateResult()
+        settingsUpdater.apply(current.changedKeys(from: previous), previous: previous, result: &result)
+    }
+
+    // MARK: SettingsApplying
+
+    func applyHotkeyChange() -> HotkeyApplyOutcome {
+        let wasActive = hotkeyService.activeBackend != nil
+        hotkeyService.reloadFromDefaults()
+        if hotkeyService.activeBackend != nil { return .registered }
+        return wasActive ? .failed : .pending
+    }
+
+    func applyModelChange(_ model: String) {
+        guard model != stateManager.routedModel else { return }
+        // Mirrors ModelSettingsView's "Use Model" action.
+        Logger.shared.info("AppDelegate: selectedModel changed to '\(model)' — reloading engine.")
+        if model.hasPrefix("parakeet-") {
+            parakeet.changeModel(to: model)
+        } else if model != "apple-native" {
+            whisper.changeModel(to: model)
+        }
+        Task { await stateManager.switchTranscriptionEngine(toModel: model) }
+    }
+
+    func applyPostProcessingChange() {
+        stateManager.switchPostProcessingEngine()
+    }
+}
+
+// MARK: - Model Management
+extension AppDelegate {
+    /// With crash reporting on, offers to send reports left by previous runs. Sending opens
+    /// a pre-filled GitHub issue for the latest one; either way the local files are removed.
+    @MainActor func offerPendingCrashReports() {
+        guard SettingsStore.shared.settings.crashReportingEnabled, !isHiddenLaunch else { return }
+        let reports = CrashReporter.pendingReports()
+        guard let latest = reports.last else { return }
+
+        let alert = NSAlert()
+        alert.messageText = "VocaGlyph quit unexpectedly"
+        alert.informativeText = "A crash report was saved (\(latest.summary)). Send it to help fix the problem? You can review it before submitting; it contains no transcripts."
+        alert.addButton(withTitle: "Send Report…")
+        alert.addButton(withTitle: "Don't Send")
+        NSApp.activate(ignoringOtherApps: true)
+        let response = alert.runModal()
+
+        if response == .alertFirstButtonReturn, let url = CrashReporter.issueURL(for: latest) {
+            Logger.shared.info("AppDelegate: Opening crash report for submission")
+            NSWorkspace.shared.open(url)
+        } else {
+            Logger.shared.info("AppDelegate: Crash report discarded")
+        }
+        CrashReporter.discard(reports)
+    }
+
+    /// Offers dictations the last run transcribed but never output — it crashed or quit
+    /// in between — for copying. "Later" keeps them for the next launch.
+    @MainActor func offerUnrecoveredDictations() {
+        let pending = pendingOutputStore.pending
+        guard let latest = pending.last, !isHiddenLaunch else { return }
+
+        let alert = NSAlert()
+        alert.messageText = pending.count == 1
+            ? "You have 1 unrecovered dictation"
+            : "You have \(pending.count) unrecovered dictations"
+        let preview = latest.text.count > 200 ? String(latest.text.prefix(200)) + "…" : latest.text
+        alert.informativeText = "VocaGlyph stopped before pasting \(pending.count == 1 ? "it" : "them"). "
+            + "Copy to the clipboard?\n\n“\(preview)”"
+        alert.addButton(withTitle: "Copy")
+        alert.addButton(withTitle: "Discard")
+        alert.addButton(withTitle: "Later")
+        NSApp.activate(ignoringOtherApps: true)
+        let response = alert.runModal()
+
+        switch response {
+        case .alertFirstButtonReturn:
+            output?.copyToPasteboard(text: pending.map(\.text).joined(separator: "\n\n"))
+            Logger.shared.info("AppDelegate: Copied \(pending.count) unrecovered dictation(s)")
+            pendingOutputStore.removeAll()
+        case .alertSecondButtonReturn:
+            Logger.shared.info("AppDelegate: Discarded \(pending.count) unrecovered dictation(s)")
+            pendingOutputStore.removeAll()
+        default:
+            break
+        }
+    }
+
+    /// When the selected model can't be used (not shipped, or not on disk) and nothing is
+    /// downloaded, asks whether to download the model recommended for this Mac and switch
+    /// to it when ready. Asked once: showing it turns off `offerRecommendedModelDownload`,
+    /// so Apple Speech users are never nagged.
+    @MainActor func offerRecommendedModelDownloadIfNeeded() {
+        let model = LocaleDefaults.recommendedModel
+        let settings = SettingsStore.shared.settings
+        let downloaded = modelsAPI.downloadedModelIds()
+        let selectedModelUsable = !SettingsValidator.validate(settings, downloadedModels: downloaded)
+            .contains { $0.field == AppSettings.Key.selectedModel.rawValue }
+        guard settings.offerRecommendedModelDownload,
+              !isHiddenLaunch,
+              !selectedModelUsable,
+              !SettingsValidator.builtInTranscriptionModels.contains(model),
+              downloaded?.isEmpty ?? true else { return }
+
+        let alert = NSAlert()
+        alert.messageText = "Download the recommended model?"
+        alert.informativeText = "No transcription model is downloaded yet. '\(model)' is the best fit for this Mac. VocaGlyph can download it in the background and switch to it when it's ready."
+        alert.addButton(withTitle: "Download")
+        alert.addButton(withTitle: "Not Now")
+        SettingsStore.shared.update { $0.offerRecommendedModelDownload = false }
+        NSApp.activate(ignoringOtherApps: true)
+        let response = alert.runModal()
+
+        guard response == .alertFirstButtonReturn else {
+            Logger.shared.info("AppDelegate: Recommended model download declined")
+            return
+        }
+
+        Logger.shared.info("AppDelegate: Downloading recommended model '\(model)'")
+        Task {
+            do {
+                try await downloadModel(model) { _ in }
+                await MainActor.run {
+                    // Don't override a model the user picked while the download ran.
+                    guard self.settingsAPI.settings.selectedModel == settings.selectedModel else { return }
+                    self.modelsAPI.switchModel(to: model)
+                }
+            } catch {
+                Logger.shared.error("AppDelegate: Recommended model download failed — \(error.localizedDescription)")
+            }
+        }
+    }
+}
+
+// MARK: - First-Run Onboarding
+extension AppDelegate: OnboardingPerforming {
+    /// Current wizard progress. Permission steps are re-checked before returning.
+    func getOnboardingState() -> OnboardingState {
+        onboardingCoordinator.refresh()
+        return onboardingCoordinator.state
+    }
+
+    var isMicrophoneAuthorized: Bool { permissionsService.isMicrophoneAuthorized }
+
+    var isAccessibilityTrusted: Bool { permissionsService.isAccessibilityTrusted }
+
+    func requestMicrophoneAccess() async -> Bool {
+        await permissionsService.requestMicrophoneAccess()
+    }
+
+    func promptAccessibilityTrusted() -> Bool {
+        let trusted = permissionsService.promptAccessibilityTrusted()
+        if !trusted { accessibilityWatcher.start() }
+        return trusted
+    }
+
+    /// How long `downloadModel` waits for a download to start before giving up.
+    static let downloadStartTimeout: TimeInterval = 30
+
+    /// Starts the download on the owning service and polls it until the model is on disk.
+    /// Neither service exposes completion callbacks, so this mirrors the polling in
+    /// `AppStateManager.startEngine()`. Fails once the download fails or is cancelled, or
+    /// when it hasn't started within `downloadStartTimeout`.
+    func downloadModel(_ model: String, progress: @escaping (Double) -> Void) async throws {
+        guard whisper != nil, parakeet != nil else {
+            throw OnboardingError.servicesNotReady
+        }
+        if SettingsValidator.builtInTranscriptionModels.contains(model) || modelsAPI.downloadedModelIds()?.contains(model) == true {
+            progress(1.0)
+            return
+        }
+
+        let isParakeet = model.hasPrefix("parakeet-")
+        await MainActor.run {
+            if isParakeet { parakeet.downloadOnly(id: model) } else { whisper.downloadModel(model) }
+        }
+
+        // A download that never starts (no space, no source) leaves no failed state behind.
+        let startDeadline = Date().addingTimeInterval(Self.downloadStartTimeout)
+        var started = false
+        while true {
+            try await Task.sleep(nanoseconds: 500_000_000)
+            let status = await MainActor.run {
+                isParakeet ? parakeet.status(for: model) : whisper.status(for: model)
+            }
+            switch status.state {
+            case .downloaded:
+                progress(1.0)
+                return
+            case .downloading:
+                started = true
+                progress(Double(status.fractionCompleted))
+            case .queued, .paused:
+                started = true
+            case .failed where started, .notDownloaded where started, .corrupt:
+                throw OnboardingError.downloadFailed(model)
+            case .failed, .notDownloaded:
+                // Before it starts, `.failed` may be left over from an earlier attempt.
+                if Date() > startDeadline { throw OnboardingError.downloadFailed(model) }
+            }
+        }
+    }
+
+    /// Records a short clip through `stateManager` and transcribes it with the active
+    /// engine. Nothing is pasted or kept in History.
+    func runTestDictation(duration: TimeInterval) async throws -> String {
+        guard stateManager.engineRouter != nil else {
+            throw OnboardingError.servicesNotReady
+        }
+        return try await stateManager.recordTestDictation(duration: duration)
+    }
+}
+
+// MARK: - OnboardingError
+enum OnboardingError: LocalizedError {
+    case servicesNotReady
+    case busy
+    case noAudio
+    case downloadFailed(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .servicesNotReady: return "VocaGlyph is still starting up. Try again in a moment."
+        case .busy: return "A dictation is already in progress."
+        case .noAudio: return "No audio was captured from the microphone."
+        case .downloadFailed(let model): return "Downloading '\(model)' failed. Check your connection and try again."
         }
     }
 }
@@ -588,36 +2045,15 @@ extension AppDelegate: WhisperServiceDelegate {
                 // an active recording or transcription session. Transitioning from
                 // .recording/.processing → .initializing here would interrupt the
                 // active session and cause the processing overlay to flash away.
-                if self.stateManager.currentState == .idle {
-                    self.stateManager.setInitializing()
-                } else {
-                }
+                // The state machine ignores this outside a resting state.
+                self.stateManager.setInitializing()
             case "Ready", "Model not downloaded.", "Failed", "Model warming up...":
-                if self.stateManager.currentState == .initializing {
-                    self.stateManager.setIdle()
-                }
+                self.stateManager.send(.modelLoaded)
             default:
                 if state.hasPrefix("Downloading") || state.hasPrefix("Falling back") {
-                    if self.stateManager.currentState != .initializing {
-                        self.stateManager.setInitializing()
-                    }
+                    self.stateManager.setInitializing()
                 }
             }
         }
     }
 }
-
-// MARK: - SPUUpdaterDelegate (Sparkle)
-extension AppDelegate: SPUUpdaterDelegate {
-    /// Called by Sparkle before presenting an update to the user.
-    public func updater(_ updater: SPUUpdater, shouldPostponeRelaunchForUpdate item: SUAppcastItem, untilInvokingBlock installHandler: @escaping () -> Void) -> Bool {
-        return false // Install immediately; do not delay relaunch
-    }
-
-    /// Called by Sparkle right before it terminates the app to install the update.
-    /// Close all windows so the process exits cleanly — an open Settings or onboarding
-    /// window can prevent the app from fully terminating, which blocks the installer.
-    public func updaterWillRelaunchApplication(_ updater: SPUUpdater) {
-        NSApp.windows.forEach { $0.close() }
-    }
-}
@@ -0,0 +1,110 @@
+import Foundation
+
+// MARK: - AppState
+
+/// What the app is doing, as shown by the tray icon and the overlay. The single source
+/// of truth — `AppStateManager.currentState` — replaces the separate paused flag and
+/// ad-hoc "is it idle?" checks.
+enum AppState: Equatable {
+    case idle
+    /// A transcription model is loading or downloading.
+    case initializing
+    case recording
+    case processing
+    /// Idle while dictation is paused by voice command: recordings still run so
+    /// "resume dictation" can be heard, but no text is output.
+    case paused
+    /// The last session failed. Cleared by the next recording or model load.
+    case error(String)
+
+    /// Not busy: a recording may start, and the tray shows a resting icon.
+    var isResting: Bool {
+        switch self {
+        case .idle, .paused, .error: return true
+        case .initializing, .recording, .processing: return false
+        }
+    }
+
+    /// Stable lowercase name for external APIs, e.g. "recording".
+    var name: String {
+        switch self {
+        case .idle:         return "idle"
+        case .initializing: return "initializing"
+        case .recording:    return "recording"
+        case .processing:   return "processing"
+        case .paused:       return "paused"
+        case .error:        return "error"
+        }
+    }
+}
+
+// MARK: - AppStateEvent
+
+enum AppStateEvent: Equatable {
+    case beginModelLoad
+    case modelLoaded
+    case startRecording
+    case stopRecording
+    /// The session ended (output delivered, dropped or cancelled).
+    case finish
+    case pause
+    case resume
+    case fail(String)
+}
+
+// MARK: - AppStateMachine
+
+/// The allowed transitions between `AppState`s:
+///
+/// | Event            | From                              | To                |
+/// |------------------|-----------------------------------|-------------------|
+/// | `beginModelLoad` | resting, initializing             | initializing      |
+/// | `modelLoaded`    | initializing                      | resting           |
+/// | `startRecording` | resting                           | recording         |
+/// | `stopRecording`  | recording                         | processing        |
+/// | `finish`         | initializing, recording, processing, error | resting  |
+/// | `pause`/`resume` | any                               | flag; idle ⇄ paused |
+/// | `fail`           | any                               | error             |
+///
+/// "Resting" is `.paused` while paused, otherwise `.idle`. Events that aren't allowed
+/// leave the state unchanged, so a second key-up or a late model-load callback can't
+/// knock a session out of step.
+///
+/// There is at most one dictation in flight: `startRecording` is refused while one is
+/// processing, so no job ever waits behind another and there is nothing to preempt or
+/// drop. Allowing a recording during processing would need a job queue first.
+struct AppStateMachine {
+    private(set) var state: AppState = .idle
+    private(set) var isPaused = false
+
+    private var resting: AppState { isPaused ? .paused : .idle }
+
+    /// Applies `event`. Returns `false`, changing nothing, when it isn't allowed in the
+    /// current state.
+    @discardableResult
+    mutating func handle(_ event: AppStateEvent) -> Bool {
+        switch (event, state) {
+        case (.beginModelLoad, _) where state.isResting || state == .initializing:
+            state = .initializing
+        case (.modelLoaded, .initializing):
+            state = resting
+        case (.startRecording, _) where state.isResting:
+            state = .recording
+        case (.stopRecording, .recording):
+            state = .processing
+        case (.finish, .initializing), (.finish, .recording), (.finish, .processing), (.finish, .error):
+            state = resting
+        case (.pause, _):
+            isPaused = true
+            if state == .idle { state = .paused }
+        case (.resume, _):
+            isPaused = false
+            if state == .paused { state = .idle }
+        case (.fail(let message), _):
+            state = .error(message)
+        default:
+            return false
+        }
+        return true
+    }
+}
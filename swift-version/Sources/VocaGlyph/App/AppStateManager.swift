@@ -3,16 +3,21 @@ import Combine
 import AVFoundation
 import SwiftData
 
-enum AppState {
-    case idle
-    case initializing
-    case recording
-    case processing
-}
-
 protocol AppStateManagerDelegate: AnyObject {
+    /// Every `currentState` change, on the main thread.
     func appStateDidChange(newState: AppState)
     func appStateManagerDidTranscribe(text: String)
+    /// A voice command was spoken instead of dictated text. Pause and resume are already
+    /// applied to `isDictationPaused`; the delegate applies everything else.
+    func appStateManagerDidRecognize(command: VoiceCommand)
+    /// A long dictation in summary mode produced both versions; the delegate lets the
+    /// user choose which to paste.
+    func appStateManagerDidSummarize(verbatim: String, summary: String)
+    /// Once a second while recording. `remaining` counts down to `maxRecordingSeconds`,
+    /// when the recording stops by itself; `nil` when there is no limit.
+    func appStateManagerRecordingDidTick(elapsed: Int, remaining: Int?)
+    /// Once per recording, `recordingWarningSeconds` before the limit.
+    func appStateManagerRecordingWillReachLimit(remaining: Int)
 }
 
 class AppStateManager: ObservableObject, @unchecked Sendable {
@@ -34,6 +39,33 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
 
     var postProcessingEngine: (any PostProcessingEngine)?
 
+    /// Reads the text around the cursor when recording starts. `nil` disables capture.
+    var contextCaptureService: ContextCaptureService?
+
+    /// Source of the regex rules stage. `nil` runs no regex rules.
+    var regexRulesService: RegexRulesService?
+
+    /// Names the app that receives the output, for `AppProcessingRule`s.
+    var frontmostAppProvider: FocusedTextProvider = AccessibilityFocusedTextProvider()
+
+    /// Receives per-stage timings of every dictation.
+    var metrics: MetricsService = .shared
+
+    /// When the last recording stopped, the start of the `total` latency.
+    private var recordingStoppedAt: Date?
+
+    /// Text captured by `contextCaptureService` for the current session.
+    private(set) var capturedContext: String?
+
+    /// Text selected when the current session started, if selection rewriting is on.
+    /// The dictation then rewrites it instead of being pasted as is.
+    private(set) var capturedSelection: String?
+
+    /// Set by the "pause dictation" voice command. While paused, recordings are still
+    /// transcribed so "resume dictation" can be heard, but nothing else is output.
+    /// Not persisted — a relaunch always starts unpaused.
+    var isDictationPaused: Bool { stateMachine.isPaused }
+
     // MARK: - Memory Pressure
 
     /// Retained to keep the DispatchSource alive for the lifetime of AppStateManager.
@@ -56,6 +88,48 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
     /// Cleared automatically after 3 seconds.
     @Published var notReadyMessage: String? = nil
 
+    /// Microphone RMS level (0…1) while recording, for the overlay's level meter.
+    @Published var inputLevel: Float = 0
+
+    /// When the current recording started; `nil` when not recording.
+    @Published private(set) var recordingStartedAt: Date?
+
+    /// Whole seconds recorded so far, updated once a second while recording.
+    @Published private(set) var recordingElapsed = 0
+    /// Seconds left before the recording stops at `maxRecordingSeconds`; `nil` when
+    /// there is no limit.
+    @Published private(set) var recordingRemaining: Int?
+
+    /// Allowed limits besides 0, which means no limit.
+    static let maxRecordingSecondsRange = 30...3600
+    /// How long before the limit the overlay and tray start warning.
+    static let recordingWarningSeconds = 15
+
+    private var recordingTimer: Timer?
+    private var didWarnOfRecordingLimit = false
+
+    /// The live preview of the current recording when `liveTranscriptionPreview` or
+    /// `liveCaptionsEnabled` is on.
+    @Published private(set) var partialTranscript: String?
+
+    /// Supplies the audio captured so far, for the live preview. Set by `AppDelegate`.
+    var audioSnapshotProvider: (() -> AVAudioPCMBuffer?)?
+
+    /// Supplies the recorder's buffer size, for `resourceUsage()`. Set by `AppDelegate`.
+    var audioBufferUsageProvider: (() -> ResourceUsage.AudioBuffer)?
+
+    /// The processor plugins' stage, while plugins are on. Set by `AppDelegate`.
+    var pluginProcessor: PluginTextProcessor?
+
+    /// Seconds between live preview transcriptions.
+    static let partialTranscriptionInterval: TimeInterval = 1.5
+
+    private var partialTranscriptionTask: Task<Void, Never>?
+
+    /// "Template: slot" while a dictation template is being filled, shown above the pill
+    /// so the user knows what the next dictation is for. Set by `AppDelegate`.
+    @Published var templateSlotHint: String? = nil
+
     private func bindWhisperProgress() {
         whisperCancellables.removeAll()
         guard let whisper = sharedWhisper else { return }
@@ -88,7 +162,7 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
             .sink { [weak self] progress in
                 guard let self else { return }
                 if progress == 0.0 && self.currentState == .initializing {
-                    self.currentState = .idle
+                    self.send(.modelLoaded)
                 }
             }
             .store(in: &parakeetCancellables)
@@ -97,7 +171,7 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
     /// Flash a "model still loading" message in the overlay for 3 seconds.
     /// Called by HotkeyService when the hotkey fires during .initializing state.
     func flashNotReadyMessage() {
-        let selected = UserDefaults.standard.string(forKey: "selectedModel") ?? ""
+        let selected = SettingsStore.shared.settings.selectedModel
         let engineName = selected.hasPrefix("parakeet-") ? "Parakeet" : "WhisperKit"
         notReadyMessage = "\(engineName) is still loading. Try again in a moment."
         DispatchQueue.main.asyncAfter(deadline: .now() + 3.0) { [weak self] in
@@ -142,16 +216,55 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
     // We no longer track selectedEngine explicitly. We derive the engine 
     // from the model selection inside switchTranscriptionEngine.
     
-    @Published var currentState: AppState = .idle {
+    /// The model id most recently passed to `switchTranscriptionEngine(toModel:)`.
+    /// Lets `PreferencesWatcher` skip reloads for selections the UI already applied.
+    private(set) var routedModel: String?
+
+    /// Changed only through `send(_:)`, which enforces `AppStateMachine`'s transitions.
+    @Published private(set) var currentState: AppState = .idle {
         didSet {
+            processingWatchdog.stateDidChange(currentState)
             delegate?.appStateDidChange(newState: currentState)
         }
     }
+
+    /// Gives up on a dictation that stays in Processing past `processingTimeoutSeconds`.
+    private lazy var processingWatchdog = ProcessingWatchdog { [weak self] seconds in
+        self?.recoverFromStuckProcessing(after: seconds)
+    }
+    /// Bumped when the watchdog abandons a dictation, so its late result is dropped.
+    private var processingGeneration = 0
+    private var processingTask: Task<Void, Never>?
+    /// The audio being transcribed, until the text is in `pendingOutputs`; the watchdog
+    /// saves it if it gives up before then.
+    private var processingAudio: AVAudioPCMBuffer?
+    /// Set while onboarding's test dictation records: its audio is transcribed and handed
+    /// back here instead of going through text processing, history and pasting.
+    private var testDictation: CheckedContinuation<String, Error>?
+    /// Holds each finished transcription until it has been handled; `nil` keeps nothing
+    /// on disk (tests). Set by `AppDelegate`.
+    var pendingOutputs: PendingOutputStore?
+
+    private var stateMachine = AppStateMachine()
+
+    /// Applies a state event. Returns `false` when the current state doesn't allow it.
+    /// Must be called on the main thread.
+    @discardableResult
+    func send(_ event: AppStateEvent) -> Bool {
+        guard stateMachine.handle(event) else {
+            Logger.shared.debug("AppStateManager: Ignored \(event) in state \(currentState)")
+            return false
+        }
+        if stateMachine.state != currentState {
+            currentState = stateMachine.state
+        }
+        return true
+    }
     
     init() {}
     
     func startEngine() {
-        let initialModel = UserDefaults.standard.string(forKey: "selectedModel") ?? "apple-native"
+        let initialModel = SettingsStore.shared.settings.selectedModel
         Logger.shared.info("AppStateManager: startEngine called with model: \(initialModel)")
 
         // AC #1: sequence the engine loads — transcription engine first, then LLM warm-up.
@@ -177,7 +290,7 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
             }
 
             let elapsedSeconds = Double(iterations + 1) * 0.5
-            if currentState == .idle {
+            if currentState.isResting {
                 Logger.shared.info("AppStateManager: Engine '\(initialModel)' ready after ~\(String(format: "%.1f", elapsedSeconds))s — starting LLM warm-up now.")
                 warmUpLocalLLMIfNeeded()
             } else {
@@ -223,18 +336,37 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
         Logger.shared.info("AppStateManager: Memory pressure handler registered.")
     }
 
+    /// Memory used by the app, the selected model and the recording buffer right now.
+    @MainActor
+    func resourceUsage() -> ResourceUsage {
+        let selected = SettingsStore.shared.settings.selectedModel
+        var model: ResourceUsage.Model?
+        if !SettingsValidator.builtInTranscriptionModels.contains(selected) {
+            let isParakeet = selected.hasPrefix("parakeet-")
+            let path = isParakeet ? sharedParakeet?.status(for: selected).path : sharedWhisper?.status(for: selected).path
+            let isLoaded = isParakeet
+                ? sharedParakeet.map { $0.isReady && $0.activeModel == selected } ?? false
+                : sharedWhisper.map { $0.isReady && $0.activeModel == selected } ?? false
+            model = ResourceUsage.Model(id: selected,
+                                        bytes: path.map { DiskSpace.allocatedSize(of: URL(fileURLWithPath: $0)) } ?? 0,
+                                        isLoaded: isLoaded)
+        }
+        return ResourceUsage.sample(model: model, audioBuffer: audioBufferUsageProvider?() ?? .empty)
+    }
+
     /// Fires a background Task to preload + Metal-warm the local LLM when:
-    ///   1. `selectedTaskModel == "local-llm"` (user has chosen local AI)
-    ///   2. Post-processing is enabled
-    ///   3. Model weights already exist on disk (no download required)
+    ///   1. `selectedTaskModel == "local-llm"` and post-processing is enabled, or
+    ///      punctuation restoration is enabled
+    ///   2. Model weights already exist on disk (no download required)
     ///
     /// Runs at `.background` priority so it never contends with UI or audio.
     private func warmUpLocalLLMIfNeeded() {
         let selectedPostModel = UserDefaults.standard.string(forKey: "selectedTaskModel") ?? "apple-native"
         let postProcessingEnabled = UserDefaults.standard.bool(forKey: "enablePostProcessing")
 
-        guard selectedPostModel == "local-llm", postProcessingEnabled else {
-            Logger.shared.info("AppStateManager: Background LLM warm-up skipped (local-llm not selected or post-processing disabled)")
+        let usedForRefinement = selectedPostModel == "local-llm" && postProcessingEnabled
+        guard usedForRefinement || SettingsStore.shared.settings.punctuationRestorationEnabled else {
+            Logger.shared.info("AppStateManager: Background LLM warm-up skipped (local LLM not used for refinement or punctuation)")
             return
         }
 
@@ -253,6 +385,15 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
         }
     }
     
+    /// The AI post-processing engine if it can take a request now: cloud and Apple
+    /// Intelligence engines as soon as they are selected, the local LLM only once warmed
+    /// up. `nil` without Foundation Models, where the Apple engine is a stub.
+    var readyPostProcessingEngine: (any PostProcessingEngine)? {
+        guard let engine = postProcessingEngine, !(engine is AppleIntelligenceLegacyStub) else { return nil }
+        if engine is LocalLLMEngine, !localLLMIsWarmedUp { return nil }
+        return engine
+    }
+
     public func switchPostProcessingEngine() {
         let postProcessingEnabled = UserDefaults.standard.bool(forKey: "enablePostProcessing")
         guard postProcessingEnabled else {
@@ -268,6 +409,9 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
             let selectedCloudProvider = UserDefaults.standard.string(forKey: "selectedCloudProvider") ?? "gemini"
             if selectedCloudProvider == "anthropic" {
                 self.postProcessingEngine = AnthropicEngine()
+            } else if selectedCloudProvider == "openai" {
+                // Refuses to send anything until the user opts in under AI Refinement.
+                self.postProcessingEngine = OpenAICompatibleEngine()
             } else {
                 self.postProcessingEngine = GeminiEngine()
             }
@@ -298,32 +442,190 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
         }
     }
     
-    func startRecording() {
-        guard currentState == .idle else {
+    /// `capturingContext` is `false` for replayed recordings: the focused app didn't
+    /// hear them, so its text and selection mean nothing to the prompt.
+    func startRecording(capturingContext: Bool = true) {
+        guard currentState.isResting else {
             return
         }
-        currentState = .recording
+        // Capture before the overlay appears, while the target app still owns focus.
+        capturedContext = capturingContext ? contextCaptureService?.capture() : nil
+        capturedSelection = capturingContext ? contextCaptureService?.captureSelection() : nil
+        guard send(.startRecording) else { return }
+        startRecordingClock()
+        partialTranscript = nil
+        // Live captions stream the preview even when the overlay doesn't show it.
+        let settings = SettingsStore.shared.settings
+        if settings.liveTranscriptionPreview || settings.liveCaptionsEnabled {
+            startPartialTranscription()
+        }
     }
     
+    /// Records for `duration` seconds through the normal recording states (so the
+    /// shortcut, overlay and microphone handling behave as for any dictation) and returns
+    /// the raw transcript. Must be called on the main thread.
+    @MainActor
+    func recordTestDictation(duration: TimeInterval) async throws -> String {
+        guard currentState.isResting, testDictation == nil else { throw OnboardingError.busy }
+        return try await withCheckedThrowingContinuation { continuation in
+            testDictation = continuation
+            startRecording(capturingContext: false)
+            guard currentState == .recording else {
+                testDictation = nil
+                continuation.resume(throwing: OnboardingError.busy)
+                return
+            }
+            DispatchQueue.main.asyncAfter(deadline: .now() + duration) { [weak self] in
+                self?.stopRecording()
+            }
+        }
+    }
+
     func stopRecording() {
-        guard currentState == .recording else {
-            // This can happen when modifier-only hotkeys emit multiple flagsChanged events
-            // on key release (one per modifier key). The guard prevents the second event
-            // from triggering a second doStop() → nil buffer → setIdle() race.
+        // Ignored unless recording. This can happen when modifier-only hotkeys emit
+        // multiple flagsChanged events on key release (one per modifier key); the state
+        // machine keeps the second event from triggering a second doStop() → nil
+        // buffer → setIdle() race.
+        guard send(.stopRecording) else { return }
+        recordingStoppedAt = Date()
+        stopRecordingClock()
+        partialTranscriptionTask?.cancel()
+    }
+
+    // MARK: - Recording Clock
+
+    private func startRecordingClock(now: Date = Date()) {
+        recordingStartedAt = now
+        didWarnOfRecordingLimit = false
+        tickRecordingClock(now: now)
+        recordingTimer = Timer.scheduledTimer(withTimeInterval: 1, repeats: true) { [weak self] _ in
+            self?.tickRecordingClock(now: Date())
+        }
+    }
+
+    private func stopRecordingClock() {
+        recordingTimer?.invalidate()
+        recordingTimer = nil
+        recordingStartedAt = nil
+    }
+
+    /// Updates the elapsed and remaining time, warns near the limit and stops the
+    /// recording at it. Without a limit (`maxRecordingSeconds` 0) only the elapsed time
+    /// moves. Internal so tests can drive it without waiting.
+    func tickRecordingClock(now: Date) {
+        guard currentState == .recording, let startedAt = recordingStartedAt else { return }
+        let limit = SettingsStore.shared.settings.maxRecordingSeconds
+        recordingElapsed = max(0, Int(now.timeIntervalSince(startedAt)))
+        guard limit > 0 else {
+            recordingRemaining = nil
+            delegate?.appStateManagerRecordingDidTick(elapsed: recordingElapsed, remaining: nil)
             return
         }
-        currentState = .processing
+        let remaining = max(0, limit - recordingElapsed)
+        recordingRemaining = remaining
+        delegate?.appStateManagerRecordingDidTick(elapsed: recordingElapsed, remaining: remaining)
+
+        if remaining <= Self.recordingWarningSeconds, !didWarnOfRecordingLimit {
+            didWarnOfRecordingLimit = true
+            delegate?.appStateManagerRecordingWillReachLimit(remaining: remaining)
+        }
+        if remaining == 0 {
+            Logger.shared.info("AppStateManager: Recording reached the \(limit)s limit — stopping")
+            stopRecording()
+        }
+    }
+
+    /// "0:07", "1:42".
+    static func clockString(_ seconds: Int) -> String {
+        String(format: "%d:%02d", seconds / 60, seconds % 60)
+    }
+
+    /// Re-transcribes the audio so far every `partialTranscriptionInterval` until the
+    /// recording stops. One preview runs at a time; `processAudio` waits for it so the
+    /// engine never transcribes twice at once.
+    private func startPartialTranscription() {
+        guard let router = engineRouter, let snapshot = audioSnapshotProvider else { return }
+        partialTranscriptionTask = Task { [weak self] in
+            while !Task.isCancelled {
+                try? await Task.sleep(nanoseconds: UInt64(Self.partialTranscriptionInterval * 1_000_000_000))
+                // Less than a second of audio gives the engine too little to work with.
+                guard !Task.isCancelled, let buffer = snapshot(),
+                      Double(buffer.frameLength) / buffer.format.sampleRate >= 1 else { continue }
+                guard let text = try? await router.transcribe(audioBuffer: buffer), !Task.isCancelled else { continue }
+                let preview = text.trimmingCharacters(in: .whitespacesAndNewlines)
+                await MainActor.run { self?.partialTranscript = preview.isEmpty ? nil : preview }
+            }
+        }
     }
     
-    func setIdle() {
-        guard currentState != .idle else {
+    // MARK: - Programmatic Dictation Triggers
+
+    /// Starts a dictation session without a keyboard shortcut — used by
+    /// `ExternalTriggerService` (foot pedals, Karabiner, Shortcuts, scripts).
+    /// Mirrors the hotkey keyDown path: flashes the "not ready" banner while a
+    /// model is still loading instead of silently ignoring the request.
+    func startDictation() {
+        if currentState == .initializing {
+            flashNotReadyMessage()
             return
         }
-        currentState = .idle
+        startRecording()
+    }
+
+    /// Stops the current dictation session and hands the audio to `processAudio`.
+    /// No-op unless a recording is in progress.
+    func stopDictation() {
+        stopRecording()
+    }
+
+    /// Starts dictation when idle, stops it when recording. Useful for single-button
+    /// devices that cannot send separate press/release events.
+    func toggleDictation() {
+        if currentState == .recording {
+            stopDictation()
+        } else {
+            startDictation()
+        }
+    }
+
+    /// Applies a voice command. Must be called on the main thread.
+    func perform(_ command: VoiceCommand) {
+        switch command {
+        case .pauseDictation:  send(.pause)
+        case .resumeDictation: send(.resume)
+        case .switchLanguage, .useModel, .changeCase: break
+        }
+        delegate?.appStateManagerDidRecognize(command: command)
+    }
+
+    /// Ends the session or model load; the state returns to `.idle`, or `.paused`.
+    func setIdle() {
+        guard send(.finish) else { return }
+        clearSessionFeedback()
     }
     
     func setInitializing() {
-        currentState = .initializing
+        send(.beginModelLoad)
+    }
+
+    /// Ends the session with an error, shown by the tray icon until the next recording.
+    func setError(_ message: String) {
+        send(.fail(message))
+        clearSessionFeedback()
+    }
+
+    /// Resets the overlay's elapsed time, level and live preview. A test dictation that
+    /// ends here got no audio to transcribe.
+    private func clearSessionFeedback() {
+        if let testDictation {
+            self.testDictation = nil
+            testDictation.resume(throwing: OnboardingError.noAudio)
+        }
+        stopRecordingClock()
+        partialTranscript = nil
+        inputLevel = 0
+        partialTranscriptionTask?.cancel()
+        partialTranscriptionTask = nil
     }
     
     func processAudio(buffer: AVAudioPCMBuffer) {
@@ -333,29 +635,67 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
             setIdle()
             return
         }
+        if let testDictation {
+            self.testDictation = nil
+            partialTranscriptionTask?.cancel()
+            partialTranscriptionTask = nil
+            Task { @MainActor [weak self] in
+                do {
+                    testDictation.resume(returning: try await router.transcribe(audioBuffer: buffer))
+                } catch {
+                    testDictation.resume(throwing: error)
+                }
+                self?.setIdle()
+            }
+            return
+        }
 
-        let shouldPostProcess = UserDefaults.standard.bool(forKey: "enablePostProcessing")
-        let (postProcessPrompt, templateName) = buildActiveTemplatePrompt()
+        let settings = SettingsStore.shared.settings
+        let duration = Double(buffer.frameLength) / buffer.format.sampleRate
+        let (templatePrompt, templateName) = buildActiveTemplatePrompt()
+        let postProcessPrompt = TemplatePromptRenderer.appendingContext(
+            capturedContext,
+            to: templatePrompt,
+            template: settings.contextPromptTemplate.isEmpty
+                ? TemplatePromptRenderer.defaultContextTemplate : settings.contextPromptTemplate,
+            customVocabulary: settings.customTerms,
+            language: WhisperService.languageCode(for: settings.dictationLanguage).map(LLMTranslationEngine.languageName)
+        )
+        capturedContext = nil
+        let selection = capturedSelection
+        capturedSelection = nil
+        let pendingPreview = partialTranscriptionTask
+        partialTranscriptionTask = nil
+        let stoppedAt = recordingStoppedAt ?? Date()
+        recordingStoppedAt = nil
+        let metrics = self.metrics
+        let trace = UUID()
+        metrics.beginTranscription()
+        let generation = processingGeneration
+        processingAudio = buffer
+        processingWatchdog.noteProgress(audioSeconds: duration)
 
-        Task {
-            // ── Stage 1: Transcription (15s timeout) ─────────────────────────────
+        processingTask = Task {
+            defer { metrics.endTranscription() }
+
+            // A live preview may still be mid-transcription; let it finish first.
+            await pendingPreview?.value
+
+            // ── Stage 1: Transcription ───────────────────────────────────────────
+            // No fixed deadline: long dictations take a while, and a stalled decode is
+            // caught by `processingWatchdog`, which keeps the audio under Recovered/.
+            let decodeStartedAt = Date()
+            metrics.record(MetricsSpan(trace: trace, stage: .queue, start: stoppedAt, end: decodeStartedAt))
             let text: String
             do {
-                text = try await withThrowingTaskGroup(of: String.self) { group in
-                    group.addTask { try await router.transcribe(audioBuffer: buffer) }
-                    group.addTask {
-                        try await Task.sleep(nanoseconds: 15_000_000_000)
-                        throw NSError(domain: "TimeoutError", code: 408,
-                                      userInfo: [NSLocalizedDescriptionKey: "Transcription timed out after 15s"])
-                    }
-                    guard let result = try await group.next() else { throw CancellationError() }
-                    group.cancelAll()
-                    return result
-                }
-                Logger.shared.info("AppStateManager: Transcription complete: '\(text)'")
+                text = try await router.transcribe(audioBuffer: buffer)
+                metrics.recordDecode(MetricsSpan(trace: trace, stage: .decode, start: decodeStartedAt, end: Date()),
+                                     audioSeconds: duration)
+                Logger.shared.info("AppStateManager: Transcription complete: '\(Logger.transcript(text))'")
+                await MainActor.run { self.noteProgress(generation) }
             } catch {
                 Logger.shared.error("AppStateManager: Transcription failed — \(error.localizedDescription)")
-                DispatchQueue.main.async { self.setIdle() }
+                self.finish(generation) { self.setError("Transcription failed: \(error.localizedDescription)") }
                 return
             }
 
@@ -366,56 +706,158 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
             // user sees no output at all, which is the correct behaviour for silence.
             let trimmedText = text.trimmingCharacters(in: .whitespacesAndNewlines)
             guard !trimmedText.isEmpty, !AppStateManager.isSilenceHallucination(trimmedText) else {
-                Logger.shared.info("AppStateManager: Dropping empty/hallucinated transcription: '\(text)'")
-                DispatchQueue.main.async { self.setIdle() }
+                Logger.shared.info("AppStateManager: Dropping empty/hallucinated transcription: '\(Logger.transcript(text))'")
+                self.finish(generation) { self.setIdle() }
                 return
             }
 
+            // ── Stage 1.55: Pending Output ───────────────────────────────────────
+            // Kept on disk until the last main-thread step of this dictation has run, so
+            // a crash or quit before the text is output can offer it on the next launch.
+            // A dictation the watchdog abandons stays pending too.
+            let pendingID: UUID? = settings.privacyModeEnabled || settings.incognitoModeEnabled
+                ? nil : await MainActor.run { self.pendingOutputs?.add(trimmedText) }
+            await MainActor.run {
+                if generation == self.processingGeneration { self.processingAudio = nil }
+            }
+            defer {
+                if let pendingID { self.finish(generation) { self.pendingOutputs?.remove(pendingID) } }
+            }
 
-            // ── Stage 1.7: Word Replacement ───────────────────────────────────────
-            // Applies user-defined exact word/phrase substitutions before AI post-
-            // processing. Runs even when post-processing is disabled (AC #8).
-            let enabledReplacements = fetchEnabledWordReplacements()
-            var finalText = WordReplacementApplicator.apply(
-                to: trimmedText,
-                replacements: enabledReplacements
-            )
-            Logger.shared.info("AppStateManager: [WordReplacement] Applied \(enabledReplacements.count) pair(s). Result: '\(finalText)'")
-
-            // ── Stage 2: Post-Processing (30s timeout) ────────────────────────────
-            if shouldPostProcess,
-               let postProcessor = self.postProcessingEngine,
-               self.localLLMIsWarmedUp,   // AC #2: skip silently if LLM still warming up
-               !finalText.isEmpty {
-                Logger.shared.info("AppStateManager: [PostProcessing] Starting — template: '\(templateName)'")
-                Logger.shared.debug("AppStateManager: [PostProcessing] Full prompt: '\(postProcessPrompt)'")
-                do {
-                    let refined = try await withThrowingTaskGroup(of: String.self) { group in
-                        group.addTask { try await postProcessor.refine(text: finalText, prompt: postProcessPrompt) }
-                        group.addTask {
-                            try await Task.sleep(nanoseconds: 30_000_000_000)
-                            throw NSError(domain: "TimeoutError", code: 408,
-                                          userInfo: [NSLocalizedDescriptionKey: "Post-processing timed out after 30s"])
+            // ── Stage 1.6: Voice Commands ────────────────────────────────────────
+            // "Computer, switch to Spanish" changes a setting instead of being pasted.
+            // Only a whole utterance that starts with the prefix counts.
+            if settings.voiceCommandsEnabled,
+               let command = VoiceCommand.parse(trimmedText, prefix: settings.voiceCommandPrefix) {
+                Logger.shared.info("AppStateManager: Voice command — \(command.summary)")
+                self.finish(generation) {
+                    self.perform(command)
+                    self.setIdle()
+                }
+                return
+            }
+            if self.isDictationPaused {
+                Logger.shared.info("AppStateManager: Dictation paused — dropping transcription")
+                self.finish(generation) { self.setIdle() }
+                return
+            }
+
+            // ── Stage 1.65: Case Commands ────────────────────────────────────────
+            // "Make that title case" re-types the previous output; no prefix needed,
+            // but the utterance must say "that", "this" or "it". Only with voice
+            // commands on: it sends Backspaces to the focused app.
+            if settings.voiceCommandsEnabled, let textCase = TextCase.command(in: trimmedText) {
+                Logger.shared.info("AppStateManager: Case command — \(textCase.title)")
+                self.finish(generation) {
+                    self.perform(.changeCase(textCase))
+                    self.setIdle()
+                }
+                return
+            }
+
+            // ── Stage 1.7: Spell-Out Mode ────────────────────────────────────────
+            // "Spell out alpha bravo seven" pastes "AB7". The literal result skips the
+            // text pipeline — capitalization or an LLM would undo it.
+            if let spelled = SpellOut.spokenCommand(in: trimmedText) {
+                let literal = SpellOut.decode(spelled)
+                Logger.shared.info("AppStateManager: Spell-out mode — '\(Logger.transcript(literal))'")
+                self.finish(generation) {
+                    if !literal.isEmpty { self.delegate?.appStateManagerDidTranscribe(text: literal) }
+                    self.setIdle()
+                }
+                return
+            }
+
+            // ── Stage 1.71: Rewrite Selection ────────────────────────────────────
+            // With text selected, the dictation is an instruction for rewriting it, and
+            // pasting the result replaces the selection. Without a ready AI engine the
+            // instruction is not pasted — it would overwrite the selection — and the
+            // overlay says why.
+            if let selection {
+                guard let engine = self.readyPostProcessingEngine else {
+                    Logger.shared.error("AppStateManager: Selection rewrite needs AI post-processing — selection left unchanged")
+                    self.finish(generation) { self.setError("Rewrite selection needs AI post-processing") }
+                    return
+                }
+                let rewritten = await SelectionRewriter.rewrite(selection, instruction: trimmedText, engine: engine)
+                Logger.shared.info("AppStateManager: Selection rewrite \(rewritten == nil ? "failed" : "done") — '\(Logger.transcript(trimmedText))'")
+                self.finish(generation) {
+                    guard let rewritten else {
+                        self.setError("Could not rewrite the selection")
+                        return
+                    }
+                    self.delegate?.appStateManagerDidTranscribe(text: rewritten)
+                    self.setIdle()
+                }
+                return
+            }
+
+            // ── Stage 1.72: Per-App Rules ────────────────────────────────────────
+            // Resolved now, against the app the text is about to be pasted into.
+            var pipelineSettings = settings
+            var stageOverrides: [TextProcessingStage: Bool] = [:]
+            let targetApp = self.frontmostAppProvider.frontmostBundleIdentifier()
+            if let rule = AppProcessingRule.rule(for: targetApp, in: settings.appProcessingRules) {
+                Logger.shared.info("AppStateManager: Applying app rule — \(rule.line)")
+                stageOverrides = rule.stageOverrides
+                if let style = rule.outputStyle { pipelineSettings.outputStyle = style.rawValue }
+            }
+
+            // ── Stage 1.75: Spoken Output Style ──────────────────────────────────
+            // "Bullet list, milk eggs bread" formats this dictation as a list without
+            // changing the tray selection. The command words themselves are dropped.
+            var pipelineInput = trimmedText
+            if let command = OutputStyle.spokenCommand(in: trimmedText) {
+                Logger.shared.info("AppStateManager: Spoken output style '\(command.style.rawValue)'")
+                pipelineSettings.outputStyle = command.style.rawValue
+                stageOverrides[.outputStyle] = nil
+                pipelineInput = command.remainder
+                guard !pipelineInput.isEmpty else {
+                    self.finish(generation) { self.setIdle() }
+                    return
+                }
+            }
+
+            // ── Stage 2: Text Pipeline ────────────────────────────────────────────
+            // trim → filler removal → word replacements → custom terms → numbers →
+            // regex rules → spoken punctuation → punctuation restoration → capitalization →
+            // LLM cleanup (30s timeout) → translation → output style → profanity filter.
+            // Each stage is toggled in Settings; a failing stage keeps its input, so the
+            // raw transcription is the worst case.
+            let processingStartedAt = Date()
+            let restorer = pipelineSettings.punctuationRestorationEnabled
+                ? await self.punctuationRestorationEngine() : nil
+            let pipeline = self.makeTextPipeline(settings: pipelineSettings,
+                                                 prompt: postProcessPrompt,
+                                                 templateName: templateName,
+                                                 restorer: restorer,
+                                                 overrides: stageOverrides)
+            let finalText = await pipeline.run(pipelineInput)
+            await MainActor.run { self.noteProgress(generation) }
+            // Recorded on the way out, so it also covers the summary below.
+            defer { metrics.record(MetricsSpan(trace: trace, stage: .processing, start: processingStartedAt, end: Date())) }
+
+            // ── Stage 3: Summary ──────────────────────────────────────────────────
+            // Long dictations can also get an AI summary; the user then picks which
+            // version to paste. Without one, the text is pasted as usual.
+            if !finalText.isEmpty, DictationSummarizer.shouldSummarize(duration: duration, settings: settings) {
+                if let engine = self.readyPostProcessingEngine {
+                    if let summary = await DictationSummarizer.summarize(finalText, engine: engine) {
+                        Logger.shared.info("AppStateManager: Summarized \(Int(duration))s dictation (\(finalText.count) → \(summary.count) chars)")
+                        self.finish(generation) {
+                            self.delegate?.appStateManagerDidSummarize(verbatim: finalText, summary: summary)
+                            self.setIdle()
                         }
-                        guard let result = try await group.next() else { throw CancellationError() }
-                        group.cancelAll()
-                        return result
+                        return
                     }
-                    Logger.shared.info("AppStateManager: [PostProcessing] Done. Result: '\(refined)'")
-                    finalText = refined
-                } catch let error as AppleIntelligenceError {
-                    let engineName = type(of: postProcessor)
-                    Logger.shared.error("AppStateManager: [PostProcessing] \(engineName) failed — \(error.localizedDescription). Using raw transcription.")
-                } catch {
-                    let engineName = type(of: postProcessor)
-                    Logger.shared.error("AppStateManager: [PostProcessing] \(engineName) failed — \(error.localizedDescription). Using raw transcription.")
+                    Logger.shared.error("AppStateManager: No summary offered — \(type(of: engine)) did not return one")
+                } else {
+                    Logger.shared.info("AppStateManager: No summary offered — AI post-processing is off or its engine is not ready")
                 }
-            } else if shouldPostProcess && !self.localLLMIsWarmedUp {
-                // AC #2: LLM still loading in background — paste raw text immediately, no blocking.
-                Logger.shared.info("AppStateManager: [PostProcessing] Skipped — LLM still warming up. Pasting raw transcription.")
             }
 
-            DispatchQueue.main.async {
+            let outputQueuedAt = Date()
+            self.finish(generation) {
                 Logger.shared.info("AppStateManager: Dispatching back to main UI thread...")
                 if let del = self.delegate {
                     Logger.shared.info("AppStateManager: Delegate exists, calling appStateManagerDidTranscribe()")
@@ -423,13 +865,95 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
                 } else {
                     Logger.shared.info("AppStateManager: ERROR! Delegate is unexpectedly nil!")
                 }
+                let outputAt = Date()
+                metrics.record(MetricsSpan(trace: trace, stage: .output, start: outputQueuedAt, end: outputAt))
+                metrics.record(MetricsSpan(trace: trace, stage: .total, start: stoppedAt, end: outputAt))
                 self.setIdle()
             }
         }
     }
 
+    /// Runs `work` on the main thread, unless the watchdog has given up on the dictation
+    /// started as `generation`: its result would arrive long after the user moved on.
+    private func finish(_ generation: Int, _ work: @escaping () -> Void) {
+        DispatchQueue.main.async {
+            guard generation == self.processingGeneration else {
+                Logger.shared.info("AppStateManager: Dropping the late result of an abandoned dictation")
+                return
+            }
+            work()
+        }
+    }
+
+    // MARK: - Stuck Dictations
+
+    /// Restarts the watchdog's clock after a stage of the dictation started as
+    /// `generation` finished. Call on the main thread.
+    private func noteProgress(_ generation: Int) {
+        guard generation == processingGeneration else { return }
+        processingWatchdog.noteProgress()
+    }
+
+    /// Called by `processingWatchdog` on the main thread: abandons the dictation, reports
+    /// it, returns to idle and reloads the engine that stopped answering. Audio that was
+    /// never transcribed is saved under `AppDirs.recovered` — except in Privacy Mode and
+    /// incognito mode — so it can be replayed.
+    func recoverFromStuckProcessing(after seconds: Int) {
+        guard currentState == .processing else { return }
+        processingGeneration += 1
+        processingTask?.cancel()
+        processingTask = nil
+        let settings = SettingsStore.shared.settings
+        let audio = processingAudio
+        processingAudio = nil
+        let recovered = settings.privacyModeEnabled || settings.incognitoModeEnabled
+            ? nil : audio.flatMap { Self.saveRecoveredAudio($0) }
+        let model = routedModel ?? settings.selectedModel
+        Logger.shared.error("AppStateManager: Dictation stuck in Processing for \(seconds)s — recycling '\(model)'")
+        DictationStuckEvent(model: model, seconds: seconds, recoveredAudio: recovered).post(from: self)
+        setIdle()
+        Task { await recycleTranscriptionEngine(model: model) }
+    }
+
+    /// Writes `buffer` as a WAV file to `directory`; `nil` if that fails.
+    static func saveRecoveredAudio(_ buffer: AVAudioPCMBuffer,
+                                   to directory: URL = AppDirs.current.recovered,
+                                   date: Date = Date()) -> URL? {
+        let formatter = DateFormatter()
+        formatter.locale = Locale(identifier: "en_US_POSIX")
+        formatter.dateFormat = "yyyy-MM-dd-HHmmss"
+        let url = directory.appendingPathComponent("dictation-\(formatter.string(from: date)).wav")
+        do {
+            try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+            let file = try AVAudioFile(forWriting: url, settings: buffer.format.settings,
+                                       commonFormat: buffer.format.commonFormat,
+                                       interleaved: buffer.format.isInterleaved)
+            try file.write(from: buffer)
+        } catch {
+            Logger.shared.error("AppStateManager: Could not save the abandoned recording — \(error.localizedDescription)")
+            return nil
+        }
+        Logger.shared.info("AppStateManager: Saved the abandoned recording to \(url.path)")
+        return url
+    }
+
+    /// WhisperKit is unloaded and loaded again; the other engines are routed afresh,
+    /// which gives Apple Speech and whisper.cpp a new instance.
+    private func recycleTranscriptionEngine(model: String) async {
+        let usesWhisperKit = model != "apple-native" && model != WhisperCppEngine.modelID && !model.hasPrefix("parakeet-")
+        if usesWhisperKit, let whisper = sharedWhisper {
+            await MainActor.run {
+                whisper.unloadModel()
+                whisper.changeModel(to: model)
+            }
+        }
+        await switchTranscriptionEngine(toModel: model)
+    }
+
     public func switchTranscriptionEngine(toModel modelName: String) async {
         guard let router = engineRouter else { return }
+        routedModel = modelName
+        metrics.modelDidChange()
         
         Logger.shared.info("AppStateManager: Requested to switch transcription engine to model: '\(modelName)'")
         
@@ -443,6 +967,10 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
                 Logger.shared.error("AppStateManager: macOS too old for apple-native. Falling back to WhisperKit.")
                 if let whisper = sharedWhisper { await router.setEngine(whisper) }
             }
+        } else if modelName == WhisperCppEngine.modelID {
+            // Runs the user's whisper.cpp install; transcribe() explains what's missing.
+            Logger.shared.info("AppStateManager: Dynamically routing to WhisperCppEngine")
+            await router.setEngine(WhisperCppEngine())
         } else if modelName.hasPrefix("parakeet-") {
             // AC#5, AC#8: Route parakeet-v2 and parakeet-v3 to ParakeetService.
             // WhisperService remains loaded; EngineRouter switches exclusively to Parakeet.
@@ -452,7 +980,7 @@ class AppStateManager: ObservableObject, @unchecked Sendable {
                 // Must set currentState on MainActor: didSet → appStateDidChange → NSStatusBarButton.setImage
                 // all require the main thread, but switchTranscriptionEngine runs on a background thread.
                 if !parakeet.isReady {
-                    await MainActor.run { self.currentState = .initializing }
+                    await MainActor.run { self.setInitializing() }
                 }
                 // AC#5: Do NOT call parakeet.changeModel() here — the UI card's onUse handler
                 // already called it. This function only routes the engine, not loads a model.
@@ -575,10 +1103,66 @@ extension AppStateManager {
         return (prompt, template.name)
     }
 
+    /// Builds the `TextProcessingPipeline` for one dictation. The LLM stage is left
+    /// out when no post-processing engine is selected or it is still warming up.
+    func makeTextPipeline(settings: AppSettings, prompt: String, templateName: String,
+                          restorer: (any PostProcessingEngine)? = nil,
+                          overrides: [TextProcessingStage: Bool] = [:]) -> TextProcessingPipeline {
+        var cleanup: LLMCleanupProcessor?
+        if settings.enablePostProcessing, let engine = postProcessingEngine {
+            if localLLMIsWarmedUp {
+                Logger.shared.info("AppStateManager: [PostProcessing] Using \(type(of: engine)) — template: '\(templateName)'")
+                Logger.shared.debug("AppStateManager: [PostProcessing] Full prompt: '\(Logger.transcript(prompt))'")
+                cleanup = LLMCleanupProcessor(engine: engine, prompt: prompt)
+            } else {
+                // LLM still loading in background — paste without it, no blocking.
+                Logger.shared.info("AppStateManager: [PostProcessing] Skipped — LLM still warming up.")
+            }
+        }
+        let replacements = settings.wordReplacementsEnabled ? fetchEnabledWordReplacements() : []
+        return .standard(settings: settings,
+                         replacements: replacements,
+                         regexRules: regexRulesService?.compiledRules ?? [],
+                         cleanup: cleanup,
+                         translator: makeTranslationEngine(settings: settings),
+                         restorer: restorer,
+                         plugins: pluginProcessor,
+                         overrides: overrides)
+    }
+
+    /// The local LLM used for punctuation restoration, whatever AI Refinement uses, or
+    /// `nil` when its model isn't on disk — a dictation never starts a download.
+    func punctuationRestorationEngine() async -> (any PostProcessingEngine)? {
+        let engine = localLLMEngine
+        guard await engine.isModelDownloaded() else {
+            Logger.shared.info("AppStateManager: [PunctuationRestoration] Skipped — local model not downloaded.")
+            return nil
+        }
+        return engine
+    }
+
+    /// The engine for the selected `TranslationProvider`, or `nil` when translation is
+    /// off or its engine isn't available yet.
+    func makeTranslationEngine(settings: AppSettings) -> (any TranslationEngine)? {
+        switch TranslationProvider(rawValue: settings.translationProvider) ?? .off {
+        case .off:
+            return nil
+        case .remote:
+            return RemoteTranslationEngine(endpoint: settings.translationEndpoint)
+        case .llm:
+            // Shares the AI Refinement engine, so it needs that enabled and warmed up too.
+            guard let engine = postProcessingEngine, localLLMIsWarmedUp else {
+                Logger.shared.info("AppStateManager: [Translation] Skipped — no AI engine ready.")
+                return nil
+            }
+            return LLMTranslationEngine(engine: engine)
+        }
+    }
+
     /// Fetches all enabled `WordReplacement` pairs from SwiftData.
     ///
     /// Returns an empty array when no `modelContext` is available or when no
-    /// enabled pairs exist.  Called at the start of Stage 1.7 in `processAudio()`.
+    /// enabled pairs exist.  Feeds the replacements stage of `makeTextPipeline`.
     func fetchEnabledWordReplacements() -> [(word: String, replacement: String)] {
         guard let context = modelContext else { return [] }
         let descriptor = FetchDescriptor<WordReplacement>(
@@ -0,0 +1,143 @@
+import Foundation
+
+// MARK: - Event Names
+
+extension Notification.Name {
+    /// `ModelStatusChangedEvent` — a model's download state changed.
+    static let modelStatusChanged = Notification.Name("com.vocaglyph.model.statusChanged")
+    /// `ModelDownloadQueueChangedEvent` — models joined or left the download queue.
+    static let modelDownloadQueueChanged = Notification.Name("com.vocaglyph.model.downloadQueueChanged")
+    /// `HistoryChangedEvent` — history entries were added or cleared.
+    static let historyChanged = Notification.Name("com.vocaglyph.history.changed")
+    /// `ConfigReloadedEvent` — preferences edited outside the app were applied.
+    static let configReloaded = Notification.Name("com.vocaglyph.config.reloaded")
+    /// `AccessibilityGrantedEvent` — the user turned on Accessibility for VocaGlyph.
+    static let accessibilityGranted = Notification.Name("com.vocaglyph.permissions.accessibilityGranted")
+    /// `LogLineEvent` — a line was written to the app log.
+    static let logLine = Notification.Name("com.vocaglyph.logs.line")
+    /// `MetricsUpdatedEvent` — new dictation performance measurements.
+    static let metricsUpdated = Notification.Name("com.vocaglyph.metrics.updated")
+    /// `DictationStuckEvent` — the watchdog gave up on a dictation stuck in Processing.
+    static let dictationStuck = Notification.Name("com.vocaglyph.dictation.stuck")
+}
+
+// MARK: - AppEvent
+
+/// An in-process event with a typed payload, posted through `NotificationCenter`.
+///
+/// Every event and its payload is declared in this file. `post(from:to:)` and
+/// `init?(_:)` pack and unpack `userInfo`, so observers never guess keys or cast
+/// loose dictionaries. Each post also carries `AppEventSchema.version`.
+///
+/// The distributed notifications of `ExternalTriggerService` are separate: they come
+/// from other processes and can't carry a payload.
+protocol AppEvent: Equatable {
+    static var name: Notification.Name { get }
+}
+
+enum AppEventSchema {
+    /// Bump when any payload below changes shape.
+    static let version = 1
+    static let payloadKey = "payload"
+    static let versionKey = "schemaVersion"
+
+    /// Every event type, for documentation and tests.
+    static let events: [any AppEvent.Type] = [
+        ModelStatusChangedEvent.self,
+        ModelDownloadQueueChangedEvent.self,
+        HistoryChangedEvent.self,
+        ConfigReloadedEvent.self,
+        AccessibilityGrantedEvent.self,
+        LogLineEvent.self,
+        MetricsUpdatedEvent.self,
+        DictationStuckEvent.self,
+    ]
+}
+
+extension AppEvent {
+    func post(from object: Any? = nil, to center: NotificationCenter = .default) {
+        center.post(name: Self.name, object: object, userInfo: [
+            AppEventSchema.payloadKey: self,
+            AppEventSchema.versionKey: AppEventSchema.version,
+        ])
+    }
+
+    /// The payload of `notification`, or `nil` if it is a different event or was posted
+    /// with another schema version.
+    init?(_ notification: Notification) {
+        guard notification.name == Self.name,
+              notification.userInfo?[AppEventSchema.versionKey] as? Int == AppEventSchema.version,
+              let event = notification.userInfo?[AppEventSchema.payloadKey] as? Self else { return nil }
+        self = event
+    }
+
+    /// Calls `handler` with each decoded event. Remove the returned token when done.
+    static func observe(from object: Any? = nil,
+                        center: NotificationCenter = .default,
+                        queue: OperationQueue? = .main,
+                        using handler: @escaping (Self) -> Void) -> NSObjectProtocol {
+        center.addObserver(forName: name, object: object, queue: queue) { notification in
+            if let event = Self(notification) { handler(event) }
+        }
+    }
+}
+
+// MARK: - Events
+
+/// Posted on the main thread whenever a model's `ModelStatus` changes.
+struct ModelStatusChangedEvent: AppEvent {
+    static let name = Notification.Name.modelStatusChanged
+    let status: ModelStatus
+}
+
+/// Posted on the main thread when models join or leave the download queue.
+struct ModelDownloadQueueChangedEvent: AppEvent {
+    static let name = Notification.Name.modelDownloadQueueChanged
+    /// Waiting model ids, first in line first.
+    let queue: [String]
+}
+
+/// Posted on the main thread after `HistoryService` adds or clears entries, so lists
+/// such as the tray's Recent Transcriptions can refresh.
+struct HistoryChangedEvent: AppEvent {
+    static let name = Notification.Name.historyChanged
+}
+
+/// Posted on the main thread after `PreferencesWatcher` applies a batch of changed
+/// preferences.
+struct ConfigReloadedEvent: AppEvent {
+    static let name = Notification.Name.configReloaded
+    /// `AppSettings.Key` raw values, sorted.
+    let keys: [String]
+}
+
+/// Posted on the main thread by `AccessibilityWatcher` when Accessibility is granted
+/// while the app is running.
+struct AccessibilityGrantedEvent: AppEvent {
+    static let name = Notification.Name.accessibilityGranted
+}
+
+/// Posted on the main thread for every line `Logger` writes, already redacted, so the
+/// Settings log viewer can follow the log live. Start from `Logger.tail(lines:)`.
+struct LogLineEvent: AppEvent {
+    static let name = Notification.Name.logLine
+    let line: String
+}
+
+/// Posted on the main thread by `MetricsService`, at most every few seconds and only
+/// after a dictation changed the measurements.
+struct MetricsUpdatedEvent: AppEvent {
+    static let name = Notification.Name.metricsUpdated
+    let snapshot: MetricsSnapshot
+}
+
+/// Posted on the main thread by `AppStateManager` when `ProcessingWatchdog` abandons a
+/// dictation that made no progress for `seconds`; the engine for `model` is reloaded.
+/// A dictation abandoned during transcription has its audio saved to `recoveredAudio`;
+/// one abandoned later is already in `PendingOutputStore`.
+struct DictationStuckEvent: AppEvent {
+    static let name = Notification.Name.dictationStuck
+    let model: String
+    let seconds: Int
+    var recoveredAudio: URL? = nil
+}
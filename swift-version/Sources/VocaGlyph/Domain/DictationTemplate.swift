@@ -0,0 +1,37 @@
+import Foundation
+import SwiftData
+
+// MARK: - DictationTemplate
+
+/// A user-defined text with named `{slots}` that successive dictations fill in, e.g.
+/// "Standup update: yesterday {yesterday}, today {today}, blockers {blockers}".
+///
+/// Unlike `PostProcessingTemplate`, nothing is sent to an AI engine: the body is pasted
+/// as written once every slot has a value. See `TemplateFill`.
+@Model
+public final class DictationTemplate {
+
+    // MARK: - Stored Properties
+
+    @Attribute(.unique) public var id: UUID
+    public var name: String
+    public var body: String
+    public var createdAt: Date
+
+    // MARK: - Init
+
+    public init(
+        id: UUID = UUID(),
+        name: String,
+        body: String,
+        createdAt: Date = Date()
+    ) {
+        self.id = id
+        self.name = name
+        self.body = body
+        self.createdAt = createdAt
+    }
+
+    /// Slot names in the order they are first used in `body`.
+    var slots: [String] { TemplateFill.slots(in: body) }
+}
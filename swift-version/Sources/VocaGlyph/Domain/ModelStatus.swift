@@ -0,0 +1,44 @@
+import Foundation
+
+/// Snapshot of one transcription model's download state, returned by
+/// `ModelsAPI.statuses()` and posted with `ModelStatusChangedEvent`.
+struct ModelStatus: Codable, Equatable, Sendable {
+
+    enum State: String, Codable, Sendable {
+        case notDownloaded
+        /// Waiting for a download slot; see `queuePosition`.
+        case queued
+        case downloading
+        case paused
+        case downloaded
+        /// On disk but failed its checksum pass — see `WhisperService.repairModel(_:)`.
+        case corrupt
+        /// The last download attempt failed; `lastError` says why.
+        case failed
+    }
+
+    /// Model id as used by `selectedModel`, e.g. "large-v3_turbo" or "parakeet-v3".
+    var model: String
+    var state: State
+    /// 0–100. Only meaningful while queued, downloading or paused.
+    var percent: Int = 0
+    var bytesDownloaded: Int64 = 0
+    /// 0 when unknown (before the file listing arrives, or for Parakeet downloads).
+    var totalBytes: Int64 = 0
+    /// Folder the model is (or will be) stored in.
+    var path: String = ""
+    /// Host serving the files while downloading, e.g. "huggingface.co".
+    var source: String? = nil
+    var lastError: String? = nil
+    /// 1 for the next model to download. Set only while `state` is `.queued`.
+    var queuePosition: Int? = nil
+
+    static func notDownloaded(_ model: String, path: String = "") -> ModelStatus {
+        ModelStatus(model: model, state: .notDownloaded, path: path)
+    }
+
+    /// `percent` as a 0.0–1.0 fraction, for progress bars.
+    var fractionCompleted: Float {
+        Float(percent) / 100
+    }
+}
@@ -57,3 +57,15 @@ public final class PostProcessingTemplate {
         self.updatedAt = updatedAt
     }
 }
+
+// MARK: - Lookup
+
+extension PostProcessingTemplate {
+    /// The template whose id or name is `query`, ignoring case — how the control API's
+    /// `switch-profile` names a template.
+    static func matching(_ query: String, in templates: [PostProcessingTemplate]) -> PostProcessingTemplate? {
+        let query = query.trimmingCharacters(in: .whitespacesAndNewlines)
+        return templates.first { $0.id.uuidString.caseInsensitiveCompare(query) == .orderedSame }
+            ?? templates.first { $0.name.caseInsensitiveCompare(query) == .orderedSame }
+    }
+}
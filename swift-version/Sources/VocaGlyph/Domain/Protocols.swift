@@ -1,6 +1,15 @@
 import AVFoundation
 import Speech
 
+/// Where a dictation's audio comes from — the microphone today. `DictationPipeline`
+/// starts it when recording begins and collects the audio when it ends.
+protocol AudioSource: AnyObject {
+    func startCapture() throws
+    /// Stops capturing and returns everything captured, or `nil` when nothing was.
+    func stopCapture() -> AVAudioPCMBuffer?
+}
+
+/// The transcriber stage of `DictationPipeline`.
 public protocol TranscriptionEngine: Sendable {
     func transcribe(audioBuffer: AVAudioPCMBuffer) async throws -> String
 }
@@ -9,6 +18,26 @@ public protocol PostProcessingEngine: Sendable {
     func refine(text: String, prompt: String) async throws -> String
 }
 
+/// One stage of `TextProcessingPipeline`, run on every transcription before it
+/// reaches `OutputService`.
+public protocol TextProcessor: Sendable {
+    func process(_ text: String) async throws -> String
+}
+
+/// Receives finished dictation text, in `DictationPipeline.sinks` order.
+protocol TranscriptSink {
+    /// For logs.
+    var name: String { get }
+    /// Returns `true` when the text is used up here, so later sinks don't see it.
+    func receive(_ text: String) -> Bool
+}
+
+/// Translates text into another language. `source` is `nil` when the dictation
+/// language is auto-detected.
+public protocol TranslationEngine: Sendable {
+    func translate(_ text: String, from source: String?, to target: String) async throws -> String
+}
+
 public protocol EngineRouterDelegate: AnyObject {
     func engineRouterDidReceiveTranscription(_ text: String)
     func engineRouterDidUpdateState(_ state: String)
@@ -0,0 +1,112 @@
+import Foundation
+
+/// One answer to "can I dictate right now, and if not, why?", derived from the state
+/// machine, the selected model's `ModelStatus`, the last output and measured speed. Returned by
+/// `AppDelegate.runtimeStatus()`, sent with the control API's `status` and shown at the
+/// top of the tray menu and in the quick panel.
+enum RuntimeStatus: Equatable {
+    case ready
+    /// Microphone access was denied or is restricted, so nothing can be recorded.
+    case microphoneBlocked
+    /// The selected model isn't on disk (or is damaged), so nothing can be transcribed.
+    case modelMissing(model: String)
+    case downloading(model: String, percent: Int)
+    case loading(model: String)
+    case recording
+    case processing
+    case paused
+    /// The last result was only copied to the clipboard because Accessibility is off.
+    case pasteFallback
+    /// Recent dictations took longer to transcribe than to speak; `factor` is the median
+    /// real-time factor from `MetricsService`.
+    case slowTranscription(factor: Double)
+    case error(String)
+
+    /// Derives the status; earlier checks win, so a recording in progress is reported
+    /// even while another model downloads.
+    static func derive(state: AppState,
+                       dictationPaused: Bool,
+                       modelTitle: String,
+                       modelStatus: ModelStatus?,
+                       lastOutputWasClipboardOnly: Bool,
+                       microphoneBlocked: Bool = false,
+                       slowTranscriptionFactor: Double? = nil) -> RuntimeStatus {
+        switch state {
+        case .recording:          return .recording
+        case .processing:         return .processing
+        case .error(let message): return .error(message)
+        case .initializing:
+            if let modelStatus, [.downloading, .queued].contains(modelStatus.state) {
+                return .downloading(model: modelTitle, percent: modelStatus.percent)
+            }
+            return .loading(model: modelTitle)
+        case .idle, .paused:
+            break
+        }
+        if microphoneBlocked { return .microphoneBlocked }
+        if let modelStatus {
+            switch modelStatus.state {
+            case .downloading, .queued:
+                return .downloading(model: modelTitle, percent: modelStatus.percent)
+            case .notDownloaded, .paused, .corrupt, .failed:
+                return .modelMissing(model: modelTitle)
+            case .downloaded:
+                break
+            }
+        }
+        if dictationPaused || state == .paused { return .paused }
+        if lastOutputWasClipboardOnly { return .pasteFallback }
+        if let slowTranscriptionFactor { return .slowTranscription(factor: slowTranscriptionFactor) }
+        return .ready
+    }
+
+    /// Stable lowercase name for external APIs, e.g. "downloading".
+    var name: String {
+        switch self {
+        case .ready:             return "ready"
+        case .microphoneBlocked: return "microphone-blocked"
+        case .modelMissing:      return "model-missing"
+        case .downloading:       return "downloading"
+        case .loading:           return "loading"
+        case .recording:         return "recording"
+        case .processing:        return "processing"
+        case .paused:            return "paused"
+        case .pasteFallback:     return "paste-fallback"
+        case .slowTranscription: return "slow-transcription"
+        case .error:             return "error"
+        }
+    }
+
+    var message: String {
+        switch self {
+        case .ready:
+            return "Ready to dictate"
+        case .microphoneBlocked:
+            return "Microphone access is off — allow VocaGlyph in System Settings → Privacy & Security → Microphone"
+        case .modelMissing(let model):
+            return "\(model) isn't downloaded — choose or download a model in Settings"
+        case .downloading(let model, let percent):
+            return "Downloading \(model) — \(percent)%"
+        case .loading(let model):
+            return "Loading \(model)…"
+        case .recording:
+            return "Recording…"
+        case .processing:
+            return "Transcribing…"
+        case .paused:
+            return "Dictation paused — say \"resume dictation\" or use the menu"
+        case .pasteFallback:
+            return "Ready — results are copied only; allow Accessibility to paste"
+        case .slowTranscription(let factor):
+            return "Ready — transcription takes \(String(format: "%.1f", factor))× as long as you speak; a smaller model would be faster"
+        case .error(let message):
+            return message
+        }
+    }
+
+    /// 0–100 while downloading, otherwise `nil`.
+    var progress: Int? {
+        if case .downloading(_, let percent) = self { return percent }
+        return nil
+    }
+}
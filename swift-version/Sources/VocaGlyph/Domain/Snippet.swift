@@ -0,0 +1,37 @@
+import Foundation
+import SwiftData
+
+// MARK: - Snippet
+
+/// A named, reusable piece of text pinned from transcription history.
+///
+/// Snippets are copies, not references: they survive history retention and
+/// "Clear All History", and editing one never changes the history entry.
+@Model
+public final class Snippet {
+
+    // MARK: - Stored Properties
+
+    @Attribute(.unique) public var id: UUID
+    public var name: String
+    public var text: String
+    /// The history entry this snippet was pinned from, if any.
+    public var sourceItemID: UUID?
+    public var createdAt: Date
+
+    // MARK: - Init
+
+    public init(
+        id: UUID = UUID(),
+        name: String,
+        text: String,
+        sourceItemID: UUID? = nil,
+        createdAt: Date = Date()
+    ) {
+        self.id = id
+        self.name = name
+        self.text = text
+        self.sourceItemID = sourceItemID
+        self.createdAt = createdAt
+    }
+}
@@ -41,7 +41,7 @@ public actor AnthropicEngine: PostProcessingEngine {
         // Fetch API Key
         let apiKey: String
         do {
-            apiKey = try await keychainService.readKey(forService: "com.vocaglyph.api.anthropic")
+            apiKey = try await keychainService.readKey(forService: SecretKey.anthropicApiKey.service)
         } catch {
             throw AnthropicEngineError.missingConfiguration
         }
@@ -77,10 +77,10 @@ public actor AnthropicEngine: PostProcessingEngine {
 
         // ── Request log ─────────────────────────────────────────────────────
         PostProcessingLogger.shared.info("AnthropicEngine: [REQUEST] POST https://api.anthropic.com/v1/messages")
-        PostProcessingLogger.shared.info("AnthropicEngine: [REQUEST] System prompt: '\(prompt)'")
-        PostProcessingLogger.shared.info("AnthropicEngine: [REQUEST] Input (\(text.count) chars): '\(text)'")
+        PostProcessingLogger.shared.info("AnthropicEngine: [REQUEST] System prompt: '\(Logger.transcript(prompt))'")
+        PostProcessingLogger.shared.info("AnthropicEngine: [REQUEST] Input (\(text.count) chars): '\(Logger.transcript(text))'")
         if let bodyStr = String(data: jsonData, encoding: .utf8) {
-            PostProcessingLogger.shared.info("AnthropicEngine: [REQUEST] Body: \(bodyStr)")
+            PostProcessingLogger.shared.info("AnthropicEngine: [REQUEST] Body: \(Logger.transcript(bodyStr))")
         }
 
         let data: Data
@@ -89,7 +89,7 @@ public actor AnthropicEngine: PostProcessingEngine {
             (data, response) = try await session.data(for: request)
             // ── Response log ───────────────────────────────────────────────
             if let responseString = String(data: data, encoding: .utf8) {
-                PostProcessingLogger.shared.info("AnthropicEngine: [RESPONSE] HTTP \((response as? HTTPURLResponse)?.statusCode ?? -1): \(responseString)")
+                PostProcessingLogger.shared.info("AnthropicEngine: [RESPONSE] HTTP \((response as? HTTPURLResponse)?.statusCode ?? -1): \(Logger.transcript(responseString))")
             } else {
                 PostProcessingLogger.shared.info("AnthropicEngine: [RESPONSE] Unable to decode response as UTF-8.")
             }
@@ -134,7 +134,7 @@ public actor AnthropicEngine: PostProcessingEngine {
             result = text
         }
 
-        PostProcessingLogger.shared.info("AnthropicEngine: [RESULT] '\(result)'")
+        PostProcessingLogger.shared.info("AnthropicEngine: [RESULT] '\(Logger.transcript(result))'")
         return result
     }
 }
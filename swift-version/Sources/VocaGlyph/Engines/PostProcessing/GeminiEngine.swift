@@ -41,7 +41,7 @@ public actor GeminiEngine: PostProcessingEngine {
         // Fetch API Key
         let apiKey: String
         do {
-            apiKey = try await keychainService.readKey(forService: "com.vocaglyph.api.gemini")
+            apiKey = try await keychainService.readKey(forService: SecretKey.geminiApiKey.service)
         } catch {
             throw GeminiEngineError.missingConfiguration
         }
@@ -77,10 +77,10 @@ public actor GeminiEngine: PostProcessingEngine {
 
         // ── Request log ──────────────────────────────────────────────────────────
         PostProcessingLogger.shared.info("GeminiEngine: [REQUEST] POST gemini-2.5-flash:generateContent")
-        PostProcessingLogger.shared.info("GeminiEngine: [REQUEST] Prompt: '\(prompt)'")
-        PostProcessingLogger.shared.info("GeminiEngine: [REQUEST] Input (\(text.count) chars): '\(text)'")
+        PostProcessingLogger.shared.info("GeminiEngine: [REQUEST] Prompt: '\(Logger.transcript(prompt))'")
+        PostProcessingLogger.shared.info("GeminiEngine: [REQUEST] Input (\(text.count) chars): '\(Logger.transcript(text))'")
         if let bodyStr = String(data: jsonData, encoding: .utf8) {
-            PostProcessingLogger.shared.info("GeminiEngine: [REQUEST] Body: \(bodyStr)")
+            PostProcessingLogger.shared.info("GeminiEngine: [REQUEST] Body: \(Logger.transcript(bodyStr))")
         }
 
         let data: Data
@@ -89,7 +89,7 @@ public actor GeminiEngine: PostProcessingEngine {
             (data, response) = try await session.data(for: request)
             // ── Response log ─────────────────────────────────────────────────────
             if let responseString = String(data: data, encoding: .utf8) {
-                PostProcessingLogger.shared.info("GeminiEngine: [RESPONSE] HTTP \((response as? HTTPURLResponse)?.statusCode ?? -1): \(responseString)")
+                PostProcessingLogger.shared.info("GeminiEngine: [RESPONSE] HTTP \((response as? HTTPURLResponse)?.statusCode ?? -1): \(Logger.transcript(responseString))")
             } else {
                 PostProcessingLogger.shared.info("GeminiEngine: [RESPONSE] Unable to decode response as UTF-8.")
             }
@@ -145,7 +145,7 @@ public actor GeminiEngine: PostProcessingEngine {
             result = text
         }
 
-        PostProcessingLogger.shared.info("GeminiEngine: [RESULT] '\(result)'")
+        PostProcessingLogger.shared.info("GeminiEngine: [RESULT] '\(Logger.transcript(result))'")
         return result
     }
 }
@@ -11,9 +11,9 @@ import MLXLMCommon
 /// `downloadBase = ~/Library/Caches/VocaGlyph` → models land at
 /// `~/Library/Caches/VocaGlyph/models/mlx-community/Qwen3-0.6B-4bit/`
 /// This is the standard macOS location for re-downloadable cached data; no Full Disk Access required.
+/// A `--data-dir` / VOCAGLYPH_DATA_DIR override moves it to `<root>/Caches/`.
 private func vocaGlyphCacheDir() -> URL {
-    let url = FileManager.default.urls(for: .cachesDirectory, in: .userDomainMask)[0]
-        .appendingPathComponent("VocaGlyph", isDirectory: true)
+    let url = AppDirs.current.caches
     try? FileManager.default.createDirectory(at: url, withIntermediateDirectories: true)
     return url
 }
@@ -236,8 +236,8 @@ public actor LocalLLMEngine: PostProcessingEngine {
         // Read user-configured parameters from UserDefaults at inference time.
         let inferenceConfig = LLMInferenceConfiguration.fromUserDefaults()
         PostProcessingLogger.shared.info("LocalLLMEngine: [REQUEST] model=\(modelId) input=\(text.count) chars")
-        PostProcessingLogger.shared.debug("LocalLLMEngine: [REQUEST] Prompt: '\(prompt)'")
-        PostProcessingLogger.shared.info("LocalLLMEngine: [REQUEST] Input text: '\(text)'")
+        PostProcessingLogger.shared.debug("LocalLLMEngine: [REQUEST] Prompt: '\(Logger.transcript(prompt))'")
+        PostProcessingLogger.shared.info("LocalLLMEngine: [REQUEST] Input text: '\(Logger.transcript(text))'")
         PostProcessingLogger.shared.info("LocalLLMEngine: [PARAMS] temperature=\(inferenceConfig.temperature) topP=\(inferenceConfig.topP) repetitionPenalty=\(inferenceConfig.repetitionPenalty.map { String($0) } ?? "nil")")
         let fullPrompt = buildPrompt(system: prompt, userText: text)
         do {
@@ -251,7 +251,7 @@ public actor LocalLLMEngine: PostProcessingEngine {
                 options: .regularExpression
             )
             let trimmed = withoutThink.trimmingCharacters(in: .whitespacesAndNewlines)
-            PostProcessingLogger.shared.info("LocalLLMEngine: [RESULT] '\(trimmed)'")
+            PostProcessingLogger.shared.info("LocalLLMEngine: [RESULT] '\(Logger.transcript(trimmed))'")
             return trimmed
         } catch let error as LocalLLMEngineError {
             throw error
@@ -303,7 +303,19 @@ public actor LocalLLMEngine: PostProcessingEngine {
         return total
     }
 
+    /// Builds the ChatML prompt. Both parts carry text the user did not type as
+    /// instructions — the transcription and the captured surrounding context — so
+    /// control tokens are stripped first; otherwise a dictated `<|im_end|>` could close
+    /// the user turn and open a new system turn.
     private func buildPrompt(system: String, userText: String) -> String {
+        let system = Self.neutralizingControlTokens(system)
+        let userText = Self.neutralizingControlTokens(userText)
         return "<|im_start|>system\n\(system)<|im_end|>\n<|im_start|>user\n\(userText)<|im_end|>\n<|im_start|>assistant\n"
     }
+
+    /// Removes chat-template control tokens such as `<|im_start|>`, `<|im_end|>` and
+    /// `<|endoftext|>` from `text`.
+    static func neutralizingControlTokens(_ text: String) -> String {
+        text.replacingOccurrences(of: #"<\|[^|<>\s]*\|>"#, with: "", options: .regularExpression)
+    }
 }
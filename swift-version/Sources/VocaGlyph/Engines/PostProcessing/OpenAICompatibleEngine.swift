@@ -0,0 +1,131 @@
+import Foundation
+
+/// Errors that can occur during OpenAI-compatible engine processing
+public enum OpenAICompatibleEngineError: LocalizedError, Equatable {
+    case notOptedIn
+    case invalidURL
+    case networkError(String)
+    case apiError(statusCode: Int, message: String)
+    case invalidResponseFormat
+
+    public var errorDescription: String? {
+        switch self {
+        case .notOptedIn:
+            return "Sending transcripts to the OpenAI-compatible API is not enabled. Opt in under AI Refinement."
+        case .invalidURL:
+            return "The API endpoint URL is invalid. Check it in Settings."
+        case .networkError(let reason):
+            return "Network connection failed: \(reason)"
+        case .apiError(let statusCode, let message):
+            return "API Error (\(statusCode)): \(message)"
+        case .invalidResponseFormat:
+            return "The response from the API was not in the expected format."
+        }
+    }
+}
+
+/// Cloud API Post-Processing Engine for any OpenAI-compatible `/chat/completions` API
+/// (OpenAI, Groq, OpenRouter, a self-hosted server, …).
+///
+/// Only the transcribed text and the prompt are sent — never audio — and nothing is
+/// sent until the user has opted in with `openAICompatibleOptIn`. Endpoint and model
+/// are read per request, so edits in Settings apply to the next dictation. The API key
+/// lives in the Keychain and is optional for servers that don't need one.
+public actor OpenAICompatibleEngine: PostProcessingEngine {
+    private let store: SettingsStore
+    private let keychainService: KeychainService
+    private let session: URLSession
+
+    init(store: SettingsStore = .shared, keychainService: KeychainService = KeychainService(), session: URLSession = .shared) {
+        self.store = store
+        self.keychainService = keychainService
+        self.session = session
+    }
+
+    public func refine(text: String, prompt: String) async throws -> String {
+        let settings = store.settings
+        guard settings.openAICompatibleOptIn else {
+            throw OpenAICompatibleEngineError.notOptedIn
+        }
+        guard let base = URL(string: settings.openAICompatibleEndpoint.trimmingCharacters(in: .whitespacesAndNewlines)),
+              let scheme = base.scheme, ["http", "https"].contains(scheme), base.host != nil else {
+            throw OpenAICompatibleEngineError.invalidURL
+        }
+        let url = base.path.hasSuffix("/chat/completions") ? base : base.appendingPathComponent("chat/completions")
+
+        let payload: [String: Any] = [
+            "model": settings.openAICompatibleModel,
+            "temperature": 0.2,
+            "messages": [
+                ["role": "system", "content": prompt],
+                ["role": "user", "content": text],
+            ],
+        ]
+        guard let jsonData = try? JSONSerialization.data(withJSONObject: payload) else {
+            throw OpenAICompatibleEngineError.invalidResponseFormat
+        }
+
+        var request = URLRequest(url: url)
+        request.httpMethod = "POST"
+        request.setValue("application/json", forHTTPHeaderField: "Content-Type")
+        if let apiKey = try? await keychainService.secret(.openAICompatibleApiKey) {
+            request.setValue("Bearer \(apiKey)", forHTTPHeaderField: "Authorization")
+        }
+        request.httpBody = jsonData
+
+        // ── Request log ──────────────────────────────────────────────────────────
+        PostProcessingLogger.shared.info("OpenAICompatibleEngine: [REQUEST] POST \(url.host ?? "")\(url.path) model=\(settings.openAICompatibleModel)")
+        PostProcessingLogger.shared.info("OpenAICompatibleEngine: [REQUEST] System prompt: '\(Logger.transcript(prompt))'")
+        PostProcessingLogger.shared.info("OpenAICompatibleEngine: [REQUEST] Input (\(text.count) chars): '\(Logger.transcript(text))'")
+
+        let data: Data
+        let response: URLResponse
+        do {
+            (data, response) = try await session.data(for: request)
+            if let responseString = String(data: data, encoding: .utf8) {
+                PostProcessingLogger.shared.info("OpenAICompatibleEngine: [RESPONSE] HTTP \((response as? HTTPURLResponse)?.statusCode ?? -1): \(Logger.transcript(responseString))")
+            }
+        } catch {
+            Logger.shared.error("OpenAICompatibleEngine: Network connection failed: \(error.localizedDescription)")
+            throw OpenAICompatibleEngineError.networkError(error.localizedDescription)
+        }
+
+        guard let httpResponse = response as? HTTPURLResponse else {
+            throw OpenAICompatibleEngineError.invalidResponseFormat
+        }
+
+        if !(200...299).contains(httpResponse.statusCode) {
+            if let errorJson = try? JSONSerialization.jsonObject(with: data) as? [String: Any],
+               let errorObj = errorJson["error"] as? [String: Any],
+               let message = errorObj["message"] as? String {
+                throw OpenAICompatibleEngineError.apiError(statusCode: httpResponse.statusCode, message: message)
+            }
+            throw OpenAICompatibleEngineError.apiError(statusCode: httpResponse.statusCode, message: "Unknown API Error")
+        }
+
+        guard let json = try? JSONSerialization.jsonObject(with: data) as? [String: Any],
+              let choices = json["choices"] as? [[String: Any]],
+              let message = choices.first?["message"] as? [String: Any],
+              let extractedText = message["content"] as? String else {
+            throw OpenAICompatibleEngineError.invalidResponseFormat
+        }
+
+        // 1. Strip chatty preambles ("Here is the revised text:", "**Revised Text:**", etc.)
+        let sanitized = PostProcessingOutputSanitizer.sanitize(extractedText)
+
+        // 2. Validate for refusals and hallucinations — fall back to raw input if invalid.
+        let result: String
+        switch PostProcessingOutputSanitizer.validate(sanitized, against: text) {
+        case .valid(let cleaned):
+            result = cleaned
+        case .fallback(let reason):
+            PostProcessingLogger.shared.error(
+                "OpenAICompatibleEngine: Output validation failed (\(reason.rawValue)) — using raw transcription"
+            )
+            result = text
+        }
+
+        PostProcessingLogger.shared.info("OpenAICompatibleEngine: [RESULT] '\(Logger.transcript(result))'")
+        return result
+    }
+}
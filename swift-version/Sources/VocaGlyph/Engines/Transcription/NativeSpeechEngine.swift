@@ -113,7 +113,7 @@ public actor NativeSpeechEngine: TranscriptionEngine {
             switch result.stability {
             case .final:
                 let text = result.segments.map { $0.substring }.joined(separator: " ")
-                Logger.shared.info("NativeSpeechEngine: SpeechAnalyzer final result: '\(text)'")
+                Logger.shared.info("NativeSpeechEngine: SpeechAnalyzer final result: '\(Logger.transcript(text))'")
                 return text
             default:
                 volatileText = result.segments.map { $0.substring }.joined(separator: " ")
@@ -209,7 +209,7 @@ public actor NativeSpeechEngine: TranscriptionEngine {
 
                 if let result = result {
                     bestStringSoFar = result.bestTranscription.formattedString
-                    Logger.shared.debug("NativeSpeechEngine: partial='\(bestStringSoFar)' isFinal=\(result.isFinal)")
+                    Logger.shared.debug("NativeSpeechEngine: partial='\(Logger.transcript(bestStringSoFar))' isFinal=\(result.isFinal)")
                     if result.isFinal && !hasResumed {
                         hasResumed = true
                         try? FileManager.default.removeItem(at: self.tempFileURL)
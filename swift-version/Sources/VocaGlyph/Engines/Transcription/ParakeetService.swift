@@ -31,6 +31,15 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
             }
         }
 
+        /// Approximate download size, used for the free-space check before FluidAudio
+        /// downloads (it does not report sizes up front).
+        var approximateDownloadBytes: Int64 {
+            switch self {
+            case .v3: return 483_000_000
+            case .v2: return 464_000_000
+            }
+        }
+
         init?(modelId: String) {
             switch modelId {
             case "parakeet-v3": self = .v3
@@ -46,6 +55,8 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
     @Published var downloadState: String = "Not Initialized"
     @Published var activeModel: String = ""
     @Published var downloadedModels: Set<String> = []
+    /// Bytes used by the Parakeet model caches, refreshed with `downloadedModels`.
+    @Published private(set) var modelsDiskUsage: Int64 = 0
 
     /// Non-nil while a model is actively downloading or initializing.
     /// Used by ModelSettingsView to show a loading indicator on the correct card.
@@ -60,6 +71,11 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
 
     private var asrManager: AsrManager?
     private var currentVersion: ModelVersion?
+    /// The running `downloadOnly` Task, kept so it can be cancelled.
+    private var downloadTask: Task<Void, Never>?
+    /// Error of the last failed download per model id, cleared when a new one starts.
+    /// Only touched on the main thread.
+    private var failedDownloads: [String: String] = [:]
 
     // MARK: - Init
 
@@ -75,8 +91,10 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
     /// Dispatches asynchronously to the main thread only if called from a background context.
     private func restoreDownloadedModelsFromDisk() {
         var found: Set<String> = []
+        var usage: Int64 = 0
         for version in [ModelVersion.v3, ModelVersion.v2] {
             let dir = AsrModels.defaultCacheDirectory(for: version.asrModelVersion)
+            usage += DiskSpace.allocatedSize(of: dir)
             // A downloaded model directory will contain at least one .mlmodelc sub-directory.
             if let contents = try? FileManager.default.contentsOfDirectory(
                 at: dir, includingPropertiesForKeys: nil
@@ -91,8 +109,12 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
         // Only dispatch async when called from a background context.
         if Thread.isMainThread {
             downloadedModels = found
+            modelsDiskUsage = usage
         } else {
-            DispatchQueue.main.async { self.downloadedModels = found }
+            DispatchQueue.main.async {
+                self.downloadedModels = found
+                self.modelsDiskUsage = usage
+            }
         }
     }
 
@@ -105,12 +127,34 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
         restoreDownloadedModelsFromDisk()
     }
 
+    /// Current status of `id`. FluidAudio reports no byte counts, so `percent` follows
+    /// `loadingProgress` and the byte fields stay 0. Call on the main thread.
+    func status(for id: String) -> ModelStatus {
+        let path = ModelVersion(modelId: id)
+            .map { AsrModels.defaultCacheDirectory(for: $0.asrModelVersion).path } ?? ""
+        if downloadingModelId == id && !downloadedModels.contains(id) {
+            return ModelStatus(model: id, state: .downloading, percent: Int(loadingProgress * 100), path: path)
+        }
+        if downloadedModels.contains(id) {
+            return ModelStatus(model: id, state: .downloaded, percent: 100, path: path)
+        }
+        if let error = failedDownloads[id] {
+            return ModelStatus(model: id, state: .failed, path: path, lastError: error)
+        }
+        return .notDownloaded(id, path: path)
+    }
+
+    /// Posts `ModelStatusChangedEvent` for `id`. Call on the main thread.
+    private func postStatus(for id: String) {
+        ModelStatusChangedEvent(status: status(for: id)).post(from: self)
+    }
+
     /// Auto-initializes the selected Parakeet model on launch if it is already on disk.
     /// Called from `init()` — mirrors `WhisperService.autoInitialize()`.
     /// NEVER triggers a network download: the guard checks `downloadedModels` (populated
     /// by `restoreDownloadedModelsFromDisk()`) before calling `initialize()`.
     private func autoInitializeIfNeeded() async {
-        let selected = UserDefaults.standard.string(forKey: "selectedModel") ?? ""
+        let selected = SettingsStore.shared.settings.selectedModel
         guard selected.hasPrefix("parakeet-"),
               let version = ModelVersion(modelId: selected),
               downloadedModels.contains(selected) else {
@@ -137,13 +181,21 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
             return
         }
 
+        if !downloadedModels.contains(version.modelId), let message = insufficientSpaceMessage(for: version) {
+            await MainActor.run { self.downloadState = message }
+            return
+        }
+
         await MainActor.run {
             self.downloadState = "Downloading \(version.modelId)..."
             self.isReady = false
             self.downloadingModelId = version.modelId
+            self.failedDownloads.removeValue(forKey: version.modelId)
             self.loadingProgress = 0.1  // signals "started" to the overlay immediately
+            self.postStatus(for: version.modelId)
         }
 
+        let wasDownloaded = downloadedModels.contains(version.modelId)
         do {
             Logger.shared.info("ParakeetService: Calling AsrModels.downloadAndLoad(version: \(version.modelId))")
             await MainActor.run { self.loadingProgress = 0.25 }
@@ -170,6 +222,10 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
                 self.downloadedModels.insert(version.modelId)
                 self.downloadState = "Loading into ANE memory..."
                 self.loadingProgress = 0.65
+                self.postStatus(for: version.modelId)
+            }
+            if !wasDownloaded {
+                NotificationService.shared.post(.modelDownloaded(model: version.modelId))
             }
 
             // Step 2: Initialize the AsrManager with the loaded model files.
@@ -206,11 +262,24 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
                 self.isReady = false
                 self.downloadingModelId = nil
                 self.loadingProgress = 0.0
+                if !self.downloadedModels.contains(version.modelId) {
+                    self.failedDownloads[version.modelId] = error.localizedDescription
+                    self.postStatus(for: version.modelId)
+                }
             }
         }
     }
 
 
+    /// Frees the loaded CoreML models but keeps `activeModel`, so
+    /// `changeModel(to: activeModel)` brings them back. Used while the app is idle.
+    func unloadModel() {
+        guard asrManager != nil else { return }
+        Logger.shared.info("ParakeetService: Unloading '\(activeModel)' from memory")
+        asrManager = nil
+        isReady = false
+    }
+
     /// Parses a model ID string and fires an async initialization in the background.
     /// Called by "Use Model" — downloads if needed AND switches the active engine.
     func changeModel(to modelName: String) {
@@ -236,17 +305,48 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
             Logger.shared.info("ParakeetService: '\(id)' already downloaded or download in progress — skipping.")
             return
         }
-        Task {
+        if let message = insufficientSpaceMessage(for: version) {
+            downloadState = message
+            return
+        }
+        downloadTask = Task {
             await performDownloadOnly(version: version)
         }
     }
 
+    /// Cancels a `downloadOnly` download and deletes whatever FluidAudio wrote so far.
+    /// FluidAudio cannot resume partial downloads, so there is no pause for Parakeet.
+    /// Returns `false` when `id` is not downloading.
+    @discardableResult
+    func cancelDownload(id: String) -> Bool {
+        guard downloadingModelId == id,
+              let task = downloadTask,
+              let version = ModelVersion(modelId: id) else { return false }
+        task.cancel()
+        downloadTask = nil
+        Logger.shared.info("ParakeetService: Cancelled download for '\(id)'")
+
+        Task {
+            await task.value
+            let cacheDir = AsrModels.defaultCacheDirectory(for: version.asrModelVersion)
+            try? FileManager.default.removeItem(at: cacheDir)
+            await MainActor.run {
+                self.downloadedModels.remove(id)
+                self.downloadState = "Cancelled"
+                self.postStatus(for: id)
+            }
+        }
+        return true
+    }
+
     private func performDownloadOnly(version: ModelVersion) async {
         Logger.shared.info("ParakeetService: Download-only for '\(version.modelId)'...")
 
         await MainActor.run {
             self.downloadingModelId = version.modelId
+            self.failedDownloads.removeValue(forKey: version.modelId)
             self.loadingProgress = 0.25
+            self.postStatus(for: version.modelId)
         }
 
         // Trickle: 0.25 → 0.58 while waiting for FluidAudio download (files only).
@@ -268,19 +368,44 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
             await MainActor.run {
                 self.downloadedModels.insert(version.modelId)
                 self.downloadingModelId = nil
+                self.downloadTask = nil
                 self.loadingProgress = 0.0
+                self.postStatus(for: version.modelId)
             }
             Logger.shared.info("ParakeetService: Download-only complete for '\(version.modelId)'.")
+            NotificationService.shared.post(.modelDownloaded(model: version.modelId))
+            checkDownloadedModels()
         } catch {
             trickleTask.cancel()
+            if Task.isCancelled {
+                Logger.shared.info("ParakeetService: Download-only stopped for '\(version.modelId)'.")
+                await MainActor.run {
+                    self.downloadingModelId = nil
+                    self.loadingProgress = 0.0
+                }
+                return
+            }
             Logger.shared.error("ParakeetService: Download-only failed for '\(version.modelId)' — \(error.localizedDescription)")
             await MainActor.run {
                 self.downloadingModelId = nil
+                self.downloadTask = nil
                 self.loadingProgress = 0.0
+                self.failedDownloads[version.modelId] = error.localizedDescription
+                self.postStatus(for: version.modelId)
             }
         }
     }
 
+    /// Returns a user-facing error when the volume holding FluidAudio's cache cannot fit
+    /// `version`, or `nil` when there is enough space (or it cannot be determined).
+    private func insufficientSpaceMessage(for version: ModelVersion) -> String? {
+        let cacheDir = AsrModels.defaultCacheDirectory(for: version.asrModelVersion)
+        let required = version.approximateDownloadBytes
+        guard let available = DiskSpace.availableCapacity(at: cacheDir), available < required else { return nil }
+        Logger.shared.error("ParakeetService: '\(version.modelId)' needs ~\(required) bytes, \(available) available")
+        return ModelDownloadError.insufficientDiskSpace(required: required, available: available).errorDescription
+    }
+
     /// Deletes the cached CoreML model files for the given model ID.
     /// Uses `AsrModels.defaultCacheDirectory(for:)` — the same path FluidAudio writes to.
     @MainActor
@@ -312,6 +437,7 @@ class ParakeetService: ObservableObject, @unchecked Sendable {
             }
             // AC#3: Resync downloadedModels from disk after delete (mirrors WhisperService.checkDownloadedModels()).
             checkDownloadedModels()
+            postStatus(for: id)
         } catch {
             Logger.shared.error("ParakeetService: Failed to delete model '\(id)' — \(error.localizedDescription)")
         }
@@ -361,7 +487,7 @@ extension ParakeetService: TranscriptionEngine {
         Logger.shared.info("ParakeetService: Starting transcription (model: \(activeModel), frames: \(trimmedBuffer.frameLength))")
         let result = try await manager.transcribe(trimmedBuffer)
         let text = result.text.trimmingCharacters(in: .whitespacesAndNewlines)
-        Logger.shared.info("ParakeetService: Transcription complete — '\(text)'")
+        Logger.shared.info("ParakeetService: Transcription complete — '\(Logger.transcript(text))'")
         return text
     }
 }
@@ -0,0 +1,177 @@
+import Foundation
+import AVFoundation
+
+// MARK: - WhisperCppRuntime
+
+/// A whisper.cpp install found at run time. VocaGlyph doesn't link or bundle it: when
+/// it's missing the `whisper-cpp` model reports "install runtime" instead of the app
+/// failing to build or launch.
+struct WhisperCppRuntime: Equatable {
+
+    /// Homebrew on Apple silicon, then on Intel.
+    static let searchDirectories = ["/opt/homebrew/bin", "/usr/local/bin"]
+    /// `whisper-cli` since whisper.cpp 1.7.4; `whisper-cpp` is Homebrew's older name.
+    static let executableNames = ["whisper-cli", "whisper-cpp"]
+
+    let executable: URL
+
+    /// The first whisper.cpp command line tool in `directories`.
+    static func locate(in directories: [String] = searchDirectories) -> WhisperCppRuntime? {
+        for directory in directories {
+            for name in executableNames {
+                let path = (directory as NSString).appendingPathComponent(name)
+                if FileManager.default.isExecutableFile(atPath: path) {
+                    return WhisperCppRuntime(executable: URL(fileURLWithPath: path))
+                }
+            }
+        }
+        return nil
+    }
+}
+
+enum WhisperCppError: LocalizedError, Equatable {
+    case runtimeUnavailable
+    case modelMissing(String)
+    case failed(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .runtimeUnavailable:
+            return "Speech engine unavailable — install the whisper.cpp runtime (brew install whisper-cpp)."
+        case .modelMissing(let path):
+            return path.isEmpty
+                ? "No whisper.cpp model chosen. Pick a ggml model file on the Model tab."
+                : "whisper.cpp model not found at \(path)."
+        case .failed(let reason):
+            return "whisper.cpp failed: \(reason)"
+        }
+    }
+}
+
+// MARK: - WhisperCppEngine
+
+/// Transcribes with a ggml/GGUF Whisper model through the user's whisper.cpp install,
+/// for models that have no WhisperKit CoreML conversion. Each dictation is written to
+/// a temporary WAV file and run through the whisper.cpp command line tool.
+///
+/// The runtime is looked up on every call, so installing whisper.cpp takes effect
+/// without restarting VocaGlyph.
+final class WhisperCppEngine: TranscriptionEngine, @unchecked Sendable {
+
+    static let modelID = "whisper-cpp"
+
+    /// stderr read on another thread; written once before `group.wait()` returns.
+    private final class Captured: @unchecked Sendable {
+        var data = Data()
+    }
+
+    private let locateRuntime: () -> WhisperCppRuntime?
+    private let settings: () -> AppSettings
+    private let bookmarks: FileBookmarkStore
+
+    /// `bookmarks` reopens the model file the user picked, which a sandboxed build can't
+    /// otherwise read after a relaunch.
+    init(locateRuntime: @escaping () -> WhisperCppRuntime? = { WhisperCppRuntime.locate() },
+         settings: @escaping () -> AppSettings = { SettingsStore.shared.settings },
+         bookmarks: FileBookmarkStore = .shared) {
+        self.locateRuntime = locateRuntime
+        self.settings = settings
+        self.bookmarks = bookmarks
+    }
+
+    /// Whether a dictation can run now: the runtime is installed and the model exists.
+    var isAvailable: Bool {
+        let model = settings().whisperCppModelPath
+        let endAccess = bookmarks.beginAccess(toPath: model)
+        defer { endAccess() }
+        return locateRuntime() != nil && FileManager.default.fileExists(atPath: model)
+    }
+
+    func transcribe(audioBuffer: AVAudioPCMBuffer) async throws -> String {
+        guard let runtime = locateRuntime() else { throw WhisperCppError.runtimeUnavailable }
+        let current = settings()
+        let model = current.whisperCppModelPath
+        // Held until whisper.cpp, which inherits the sandbox, has read the model.
+        let endAccess = bookmarks.beginAccess(toPath: model)
+        defer { endAccess() }
+        guard !model.isEmpty, FileManager.default.fileExists(atPath: model) else {
+            throw WhisperCppError.modelMissing(model)
+        }
+
+        let audio = FileManager.default.temporaryDirectory
+            .appendingPathComponent("vocaglyph-whisper-cpp-\(UUID().uuidString).wav")
+        defer { try? FileManager.default.removeItem(at: audio) }
+        do {
+            // whisper.cpp reads 16-bit PCM WAV.
+            let settings: [String: Any] = [
+                AVFormatIDKey: kAudioFormatLinearPCM,
+                AVSampleRateKey: audioBuffer.format.sampleRate,
+                AVNumberOfChannelsKey: 1,
+                AVLinearPCMBitDepthKey: 16,
+                AVLinearPCMIsFloatKey: false,
+            ]
+            let file = try AVAudioFile(forWriting: audio, settings: settings,
+                                       commonFormat: audioBuffer.format.commonFormat,
+                                       interleaved: audioBuffer.format.isInterleaved)
+            try file.write(from: audioBuffer)
+        } catch {
+            throw WhisperCppError.failed("could not write audio: \(error.localizedDescription)")
+        }
+
+        let language = WhisperService.languageCode(for: current.dictationLanguage) ?? "auto"
+        Logger.shared.info("WhisperCppEngine: Transcribing \(audioBuffer.frameLength) frames with \(runtime.executable.lastPathComponent)")
+        let output = try await Self.run(runtime.executable, arguments: Self.arguments(model: model, audio: audio.path, language: language))
+        return Self.transcript(fromOutput: output)
+    }
+
+    // MARK: - Command Line
+
+    /// No timestamps or progress, so stdout is the transcript alone.
+    static func arguments(model: String, audio: String, language: String) -> [String] {
+        ["--model", model, "--file", audio, "--language", language, "--no-timestamps", "--no-prints"]
+    }
+
+    /// whisper.cpp prints one line per segment, each with a leading space.
+    static func transcript(fromOutput output: String) -> String {
+        output.split(whereSeparator: \.isNewline)
+            .map { $0.trimmingCharacters(in: .whitespaces) }
+            .filter { !$0.isEmpty }
+            .joined(separator: " ")
+    }
+
+    private static func run(_ executable: URL, arguments: [String]) async throws -> String {
+        let process = Process()
+        process.executableURL = executable
+        process.arguments = arguments
+        let stdout = Pipe()
+        let stderr = Pipe()
+        process.standardOutput = stdout
+        process.standardError = stderr
+        do {
+            try process.run()
+        } catch {
+            throw WhisperCppError.failed(error.localizedDescription)
+        }
+        return try await withCheckedThrowingContinuation { continuation in
+            // Both pipes are drained at once so neither can fill up and stall the tool.
+            DispatchQueue.global(qos: .userInitiated).async {
+                let errors = Captured()
+                let group = DispatchGroup()
+                DispatchQueue.global(qos: .userInitiated).async(group: group) {
+                    errors.data = stderr.fileHandleForReading.readDataToEndOfFile()
+                }
+                let output = stdout.fileHandleForReading.readDataToEndOfFile()
+                group.wait()
+                process.waitUntilExit()
+                guard process.terminationStatus == 0 else {
+                    let reason = String(decoding: errors.data, as: UTF8.self)
+                        .split(whereSeparator: \.isNewline).last.map(String.init)
+                        ?? "exited with status \(process.terminationStatus)"
+                    continuation.resume(throwing: WhisperCppError.failed(reason))
+                    return
+                }
+                continuation.resume(returning: String(decoding: output, as: UTF8.self))
+            }
+        }
+    }
+}
@@ -11,9 +11,17 @@ class WhisperService: ObservableObject, @unchecked Sendable {
     @Published private(set) var isReady = false
     weak var delegate: WhisperServiceDelegate?
     
-    @Published var downloadProgresses: [String: Float] = [:]
+    /// Models that are downloading, paused, or whose last download failed. Paused
+    /// downloads keep their partial files and `downloadModel` resumes from them.
+    /// Use `status(for:)` for any model, including downloaded ones.
+    @Published private(set) var downloadStatuses: [String: ModelStatus] = [:]
     @Published var downloadState: String = "Initializing Engine..."
     @Published var downloadedModels: Set<String> = []
+    /// Downloaded models with missing or damaged files, found by the background checksum
+    /// pass at launch. They are not loaded until repaired with `repairModel(_:)`.
+    @Published private(set) var corruptModels: Set<String> = []
+    /// Bytes used by all Whisper model folders, refreshed by `checkDownloadedModels()`.
+    @Published private(set) var modelsDiskUsage: Int64 = 0
     
     @Published var activeModel: String = ""
     @Published var loadingModel: String? = nil
@@ -25,20 +33,36 @@ class WhisperService: ObservableObject, @unchecked Sendable {
     @Published var loadingEstimatedSeconds: Int = 0
 
     private var loadingTimer: Timer?
-    /// Calibrated estimate for large-v3-turbo on Apple Silicon. Shown as ETA upper-bound.
-    private let estimatedLoadSeconds: Double = 35.0
+    /// Load-time estimate for this machine (see `HardwareRecommendation`). Shown as ETA upper-bound.
+    private let estimatedLoadSeconds: Double = HardwareRecommendation.current.estimatedLoadSeconds
     
-    // Fetch from UserDefaults or fallback to recommended model
+    // Fetch from settings (including any --model launch override) or fallback to recommended model
     private var defaultModelName: String {
-        UserDefaults.standard.string(forKey: "selectedModel") ?? "apple-native"
+        SettingsStore.shared.settings.selectedModel
     }
     
     // Convert UI string to WhisperKit locale code.
     // "Auto-Detect" (the default) returns nil — Whisper selects the language from audio.
     // "English (US)" returns "en" explicitly for users who want to lock to English.
     private var dictationLanguageCode: String? {
-        let saved = UserDefaults.standard.string(forKey: "dictationLanguage") ?? "Auto-Detect"
-        switch saved {
+        Self.languageCode(for: SettingsStore.shared.settings.dictationLanguage)
+    }
+
+    /// Dictation language labels offered in Settings, in menu order.
+    static let supportedDictationLanguages = [
+        "Auto-Detect", "English (US)", "Spanish (ES)", "French (FR)", "German (DE)", "Indonesian (ID)"
+    ]
+
+    /// Maps a WhisperKit code (e.g. "de") back to its Settings label. "auto" maps to "Auto-Detect".
+    static func languageLabel(forCode code: String) -> String? {
+        let normalized = code.lowercased()
+        if normalized == "auto" { return "Auto-Detect" }
+        return supportedDictationLanguages.first { languageCode(for: $0) == normalized }
+    }
+
+    /// Maps a Settings language label to its WhisperKit code; `nil` means auto-detect.
+    static func languageCode(for label: String) -> String? {
+        switch label {
         case "English (US)": return "en"
         case "Spanish (ES)": return "es"
         case "French (FR)": return "fr"
@@ -51,14 +75,15 @@ class WhisperService: ObservableObject, @unchecked Sendable {
     // The default HuggingFace repo for WhisperKit CoreML models
     static let defaultModelRepo = "argmaxinc/whisperkit-coreml"
 
-    // Base directory passed to WhisperKit.download(). No Full Disk Access or Documents
+    // Base directory for model downloads. No Full Disk Access or Documents
     // permission required — ~/Library/Application Support is sandbox-friendly.
     //
-    // HubApi appends `models/<repo-id>` automatically, resulting in:
+    // Models are stored under `models/<repo-id>` (the layout HubApi used), resulting in:
     //   VocaGlyph/models/argmaxinc/whisperkit-coreml/<model-variant>/
+    //
+    // A `--data-dir` / VOCAGLYPH_DATA_DIR override replaces the Application Support root.
     private var baseDirectoryPath: URL {
-        let appSupport = FileManager.default.urls(for: .applicationSupportDirectory, in: .userDomainMask)[0]
-        let baseDir = appSupport.appendingPathComponent("VocaGlyph", isDirectory: true)
+        let baseDir = AppDirs.current.root
         if !FileManager.default.fileExists(atPath: baseDir.path) {
             try? FileManager.default.createDirectory(at: baseDir, withIntermediateDirectories: true)
         }
@@ -74,12 +99,146 @@ class WhisperService: ObservableObject, @unchecked Sendable {
                                     isDirectory: true)
     }
     
+    /// Folder a variant is stored under in the repo, e.g. "large-v3_turbo" → "openai_whisper-large-v3_turbo".
+    static func folderName(for modelName: String) -> String {
+        modelName.hasPrefix("distil-whisper_") ? modelName : "openai_whisper-\(modelName)"
+    }
+
+    /// Folder holding `modelName`'s files: built-in models under `repoDestination`,
+    /// custom models wherever `ModelRegistry` places them.
+    private func modelFolder(for modelName: String) -> URL {
+        if let custom = ModelRegistry.shared.model(id: modelName) {
+            return ModelRegistry.shared.folderURL(for: custom)
+        }
+        return repoDestination.appendingPathComponent(Self.folderName(for: modelName))
+    }
+
+    /// HuggingFace repo and folder `modelName` downloads from; `nil` for imported models.
+    /// `mirrors` is `nil` unless a custom model lists its own.
+    private func downloadSource(for modelName: String) -> (repo: String, folder: String, mirrors: [URL]?)? {
+        if let custom = ModelRegistry.shared.model(id: modelName) {
+            guard let repo = custom.repo else { return nil }
+            return (repo, custom.folder, custom.mirrorURLs)
+        }
+        return (Self.defaultModelRepo, Self.folderName(for: modelName), nil)
+    }
+
+    private let modelDownloader = ModelDownloadService(
+        accessToken: { try? await KeychainService().secret(.huggingFaceToken) },
+        bytesPerSecond: { Int64(max(SettingsStore.shared.settings.downloadRateLimitMBps, 0)) * 1_000_000 }
+    )
+    /// In-flight download Tasks by model name. Only touched on the main thread.
+    private var downloadTasks: [String: Task<Void, Never>] = [:]
+    /// Models waiting for a download slot, first in line first. Only touched on the main thread.
+    private var downloadQueue: [String] = []
+    /// Damaged file paths (relative to the model folder) for each entry in `corruptModels`.
+    private var corruptFiles: [String: [String]] = [:]
+    
     init() {
         migrateOldModelsDirectoryIfNeeded()
         checkDownloadedModels()
         Task {
             await autoInitialize()
         }
+        verifyDownloadedModelsInBackground()
+        recoverPartialDownloadsInBackground()
+    }
+
+    /// Finds downloads an earlier run left unfinished. Those belonging to a known model are
+    /// reported as `.paused`, so the Model tab offers Resume and Cancel; folders no model
+    /// maps to any more (a removed custom model, a renamed variant) and stray temp files
+    /// are deleted.
+    private func recoverPartialDownloadsInBackground() {
+        let modelsRoot = AppDirs.current.models
+        let ids = SettingsValidator.knownTranscriptionModels
+            .subtracting(SettingsValidator.builtInTranscriptionModels)
+            .filter { !$0.hasPrefix("parakeet-") }
+            .union(ModelRegistry.shared.customModelIds)
+        var modelsByFolder: [String: String] = [:]
+        for id in ids {
+            modelsByFolder[modelFolder(for: id).standardizedFileURL.path] = id
+        }
+
+        Task.detached(priority: .utility) { [weak self] in
+            let (partials, strayBytes) = ModelDownloadService.findPartialDownloads(under: modelsRoot)
+            var reclaimed = strayBytes
+            var resumable: [ModelStatus] = []
+            for partial in partials {
+                if let id = modelsByFolder[partial.folder.standardizedFileURL.path] {
+                    resumable.append(ModelStatus(model: id,
+                                                 state: .paused,
+                                                 percent: Int(partial.fractionCompleted * 100),
+                                                 bytesDownloaded: partial.bytesOnDisk,
+                                                 totalBytes: partial.totalBytes ?? 0,
+                                                 path: partial.folder.path))
+                } else if (try? FileManager.default.removeItem(at: partial.folder)) != nil {
+                    reclaimed += partial.bytesOnDisk
+                    Logger.shared.info("WhisperService: Removed orphaned partial download \(partial.folder.path)")
+                }
+            }
+            if reclaimed > 0 {
+                Logger.shared.info("WhisperService: Reclaimed \(DiskSpace.format(reclaimed)) of stale download files")
+            }
+            guard !resumable.isEmpty else { return }
+            Logger.shared.info("WhisperService: Found resumable downloads: \(resumable.map(\.model))")
+            DispatchQueue.main.async {
+                guard let self else { return }
+                for status in resumable where self.downloadStatuses[status.model] == nil && self.downloadTasks[status.model] == nil {
+                    self.setDownloadStatus(status, for: status.model)
+                }
+            }
+        }
+    }
+
+    /// Checks every downloaded model against its manifest at background priority.
+    /// Models downloaded before manifests existed get one from HuggingFace first; when
+    /// offline they stay unverified until a later launch.
+    private func verifyDownloadedModelsInBackground() {
+        let models = getDownloadedModelsSync()
+        guard !models.isEmpty else { return }
+        let targets = models.sorted().map { ($0, modelFolder(for: $0), downloadSource(for: $0)) }
+        Task.detached(priority: .background) { [weak self] in
+            for (model, folder, source) in targets {
+                guard let self else { return }
+                var result = ModelChecksumVerifier.verify(modelFolder: folder)
+                if result == .unverified, let source,
+                   let manifest = try? await self.modelDownloader.fetchManifest(repo: source.repo, folder: source.folder, mirrors: source.mirrors) {
+                    try? manifest.write(to: folder)
+                    result = ModelChecksumVerifier.verify(modelFolder: folder)
+                }
+                switch result {
+                case .valid:
+                    Logger.shared.info("WhisperService: Verified model '\(model)'")
+                case .unverified:
+                    Logger.shared.info("WhisperService: Could not verify model '\(model)' — no manifest")
+                case .corrupt(let paths):
+                    Logger.shared.error("WhisperService: Model '\(model)' is corrupt — \(paths.count) damaged file(s): \(paths.prefix(3))")
+                    DispatchQueue.main.async {
+                        self.corruptFiles[model] = paths
+                        self.corruptModels.insert(model)
+                        self.postStatus(for: model)
+                    }
+                }
+            }
+        }
+    }
+
+    /// Deletes the damaged files of a corrupt model and downloads just those files again.
+    /// Call on the main thread.
+    func repairModel(_ modelName: String) {
+        guard corruptModels.contains(modelName) else { return }
+        guard downloadSource(for: modelName) != nil else {
+            Logger.shared.error("WhisperService: '\(modelName)' was imported from disk — import it again to repair")
+            return
+        }
+        let folder = modelFolder(for: modelName)
+        for path in corruptFiles[modelName] ?? [] {
+            try? FileManager.default.removeItem(at: folder.appendingPathComponent(path))
+        }
+        Logger.shared.info("WhisperService: Repairing model '\(modelName)'")
+        corruptFiles.removeValue(forKey: modelName)
+        corruptModels.remove(modelName)
+        downloadModel(modelName)
     }
 
     /// Removes the legacy doubled `VocaGlyph/models/models/...` directory that was created
@@ -87,8 +246,7 @@ class WhisperService: ObservableObject, @unchecked Sendable {
     /// appended another `models/<repo>` on top. Safe to delete — models will re-download
     /// into the corrected `VocaGlyph/models/argmaxinc/whisperkit-coreml/` layout.
     private func migrateOldModelsDirectoryIfNeeded() {
-        let appSupport = FileManager.default.urls(for: .applicationSupportDirectory, in: .userDomainMask)[0]
-        let oldDoubledDir = appSupport.appendingPathComponent("VocaGlyph/models/models", isDirectory: true)
+        let oldDoubledDir = AppDirs.current.models.appendingPathComponent("models", isDirectory: true)
         guard FileManager.default.fileExists(atPath: oldDoubledDir.path) else { return }
         do {
             try FileManager.default.removeItem(at: oldDoubledDir)
@@ -100,11 +258,43 @@ class WhisperService: ObservableObject, @unchecked Sendable {
     
     func checkDownloadedModels() {
         let downloaded = getDownloadedModelsSync()
+        let usage = DiskSpace.allocatedSize(of: repoDestination)
         DispatchQueue.main.async {
             self.downloadedModels = downloaded
+            self.modelsDiskUsage = usage
         }
     }
     
+    // MARK: - Model Status
+
+    /// Current status of `modelName`. Call on the main thread.
+    func status(for modelName: String) -> ModelStatus {
+        if let status = downloadStatuses[modelName] { return status }
+        let folder = modelFolder(for: modelName)
+        if corruptModels.contains(modelName) {
+            let count = corruptFiles[modelName]?.count ?? 0
+            return ModelStatus(model: modelName, state: .corrupt, path: folder.path,
+                               lastError: "\(count) damaged file(s)")
+        }
+        if downloadedModels.contains(modelName) {
+            let size = ModelManifest.load(from: folder)?.entries.reduce(Int64(0)) { $0 + $1.size } ?? 0
+            return ModelStatus(model: modelName, state: .downloaded, percent: 100,
+                               bytesDownloaded: size, totalBytes: size, path: folder.path)
+        }
+        return .notDownloaded(modelName, path: folder.path)
+    }
+
+    /// Records a download status (or clears it when `nil`) and posts `ModelStatusChangedEvent`.
+    /// Call on the main thread.
+    private func setDownloadStatus(_ status: ModelStatus?, for modelName: String) {
+        downloadStatuses[modelName] = status
+        postStatus(for: modelName)
+    }
+
+    private func postStatus(for modelName: String) {
+        ModelStatusChangedEvent(status: status(for: modelName)).post(from: self)
+    }
+
     private func getDownloadedModelsSync() -> Set<String> {
         let fileManager = FileManager.default
         // HubApi places model files directly under repoDestination (not in .cache/).
@@ -118,11 +308,12 @@ class WhisperService: ObservableObject, @unchecked Sendable {
             // Skip hidden directories like .cache
             guard !item.hasPrefix(".") else { continue }
             // Only count folders that contain actual model files (e.g. AudioEncoder.mlmodelc)
+            // and are not mid-download.
             let modelFolder = scanDir.appendingPathComponent(item)
             let hasModel = fileManager.fileExists(
                 atPath: modelFolder.appendingPathComponent("AudioEncoder.mlmodelc").path
             )
-            guard hasModel else { continue }
+            guard hasModel, !ModelDownloadService.isIncomplete(modelFolder) else { continue }
 
             // Strip known prefixes to get back to the UI variant name
             if item.hasPrefix("openai_whisper-") {
@@ -131,6 +322,17 @@ class WhisperService: ObservableObject, @unchecked Sendable {
                 downloaded.insert(item)
             }
         }
+
+        // Custom models from models.json live outside the default repo folder.
+        for custom in ModelRegistry.shared.customModels {
+            let modelFolder = ModelRegistry.shared.folderURL(for: custom)
+            let hasModel = fileManager.fileExists(
+                atPath: modelFolder.appendingPathComponent("AudioEncoder.mlmodelc").path
+            )
+            if hasModel && !ModelDownloadService.isIncomplete(modelFolder) {
+                downloaded.insert(custom.id)
+            }
+        }
         return downloaded
     }
     
@@ -179,6 +381,15 @@ class WhisperService: ObservableObject, @unchecked Sendable {
     private func initializeWhisper(modelName: String) async {
         Logger.shared.info("WhisperService: Initializing WhisperKit...")
         do {
+            if corruptModels.contains(modelName) {
+                DispatchQueue.main.async {
+                    self.downloadState = "Model files are damaged — repair to download them again."
+                    self.isReady = false
+                    self.loadingModel = nil
+                }
+                Logger.shared.error("WhisperService: Refusing to load corrupt model '\(modelName)'")
+                return
+            }
             let available = getDownloadedModelsSync()
             if !available.contains(modelName) {
                 // Do not auto-download. Just return or set state.
@@ -190,6 +401,23 @@ class WhisperService: ObservableObject, @unchecked Sendable {
                 Logger.shared.info("WhisperService: Cannot initialize model '\(modelName)', not downloaded.")
                 return
             }
+
+            // A truncated or missing CoreML bundle makes WhisperKit fail deep inside CoreML
+            // (or crash), so check the layout first and offer a repair instead.
+            let problems = ModelChecksumVerifier.structuralProblems(modelFolder: modelFolder(for: modelName))
+            if !problems.isEmpty {
+                Logger.shared.error("WhisperService: Model '\(modelName)' failed its load check — \(problems)")
+                DispatchQueue.main.async {
+                    self.corruptFiles[modelName] = problems
+                    self.corruptModels.insert(modelName)
+                    self.downloadState = "Model files are damaged — repair to download them again."
+                    self.isReady = false
+                    self.loadingModel = nil
+                    self.postStatus(for: modelName)
+                }
+                delegate?.whisperServiceDidUpdateState("Failed")
+                return
+            }
             
             DispatchQueue.main.async {
                 self.downloadState = "Loading into memory..."
@@ -198,12 +426,9 @@ class WhisperService: ObservableObject, @unchecked Sendable {
 
             startLoadingProgressTimer()
             
-            // HubApi stores complete model files at repoDestination/<folderName>.
-            // (The .cache subdirectory only contains download metadata, not the actual models.)
-            let folderName = modelName.hasPrefix("distil-whisper_")
-                ? modelName
-                : "openai_whisper-\(modelName)"
-            let modelPath = repoDestination.appendingPathComponent(folderName)
+            // Complete model files live at repoDestination/<folderName>.
+            // (The .cache subdirectory only holds metadata from older HubApi downloads.)
+            let modelPath = modelFolder(for: modelName)
             
             Logger.shared.info("WhisperService: Model available at \(modelPath). Loading into memory...")
 
@@ -245,9 +470,10 @@ class WhisperService: ObservableObject, @unchecked Sendable {
                 // (e.g. Parakeet) since this async load started. WhisperService loads in the
                 // background on every launch, and without this guard it would overwrite a
                 // Parakeet or apple-native selection made during or after startup.
-                let current = UserDefaults.standard.string(forKey: "selectedModel") ?? ""
+                // A --model launch override is for this run only and must not be persisted.
+                let current = SettingsStore.shared.settings.selectedModel
                 let isStillWhisperModel = !current.hasPrefix("parakeet-") && current != "apple-native"
-                if isStillWhisperModel {
+                if isStillWhisperModel && !SettingsStore.shared.isOverridden(.selectedModel) {
                     UserDefaults.standard.set(modelName, forKey: "selectedModel")
                 }
                 self.loadingModel = nil
@@ -306,6 +532,15 @@ class WhisperService: ObservableObject, @unchecked Sendable {
             self.loadingEstimatedSeconds = 0
         }
     }
+    /// Frees the loaded WhisperKit pipeline but keeps `activeModel`, so
+    /// `changeModel(to: activeModel)` brings it back. Used while the app is idle.
+    func unloadModel() {
+        guard whisperKit != nil else { return }
+        Logger.shared.info("WhisperService: Unloading '\(activeModel)' from memory")
+        whisperKit = nil
+        isReady = false
+    }
+
     func changeModel(to modelName: String) {
         Logger.shared.info("WhisperService: Requested model change to '\(modelName)'")
         // Only load the engine if the model is actually downloaded.
@@ -325,36 +560,126 @@ class WhisperService: ObservableObject, @unchecked Sendable {
     }
     
     /// Download a model from a HuggingFace repo that hosts WhisperKit CoreML files.
-    /// - Parameters:
-    ///   - modelName: The WhisperKit variant string (e.g. "large-v3_turbo", "distil-whisper_distil-large-v3")
-    ///   - repo: HuggingFace repo ID. Defaults to argmaxinc/whisperkit-coreml.
-    func downloadModel(_ modelName: String, from repo: String = WhisperService.defaultModelRepo) {
+    /// Built-in variants come from argmaxinc/whisperkit-coreml; custom models from the
+    /// repo listed in `models.json`.
+    ///
+    /// At most `maxConcurrentDownloads` models download at once; further requests wait in
+    /// a queue with state `.queued` and their `queuePosition`. Call on the main thread.
+    /// - Parameter modelName: The WhisperKit variant string (e.g. "large-v3_turbo",
+    ///   "distil-whisper_distil-large-v3") or a custom model id.
+    func downloadModel(_ modelName: String) {
+        guard downloadTasks[modelName] == nil, !downloadQueue.contains(modelName) else {
+            Logger.shared.info("WhisperService: Download for '\(modelName)' already in progress or queued — ignoring.")
+            return
+        }
+        guard downloadSource(for: modelName) != nil else {
+            Logger.shared.error("WhisperService: '\(modelName)' has no download source")
+            return
+        }
+        let limit = max(SettingsStore.shared.settings.maxConcurrentDownloads, 1)
+        guard downloadTasks.count < limit else {
+            downloadQueue.append(modelName)
+            Logger.shared.info("WhisperService: Queued download for '\(modelName)' at position \(downloadQueue.count)")
+            publishQueue()
+            return
+        }
+        startDownload(modelName)
+    }
+
+    /// Starts queued downloads while slots are free. Call on the main thread whenever a
+    /// download finishes, fails, pauses or is cancelled.
+    private func startQueuedDownloads() {
+        let limit = max(SettingsStore.shared.settings.maxConcurrentDownloads, 1)
+        var started = false
+        while downloadTasks.count < limit, !downloadQueue.isEmpty {
+            startDownload(downloadQueue.removeFirst())
+            started = true
+        }
+        if started { publishQueue() }
+    }
+
+    /// Updates the `.queued` status of every waiting model and posts `ModelDownloadQueueChangedEvent`.
+    private func publishQueue() {
+        for (index, modelName) in downloadQueue.enumerated() {
+            let previous = downloadStatuses[modelName]
+            setDownloadStatus(ModelStatus(model: modelName,
+                                          state: .queued,
+                                          percent: previous?.percent ?? 0,
+                                          bytesDownloaded: previous?.bytesDownloaded ?? 0,
+                                          totalBytes: previous?.totalBytes ?? 0,
+                                          path: modelFolder(for: modelName).path,
+                                          queuePosition: index + 1),
+                              for: modelName)
+        }
+        ModelDownloadQueueChangedEvent(queue: downloadQueue).post(from: self)
+    }
+
+    private func startDownload(_ modelName: String) {
+        guard let (repo, folder, mirrors) = downloadSource(for: modelName) else { return }
         Logger.shared.info("WhisperService: Starting download for model '\(modelName)' from '\(repo)'")
+        let path = modelFolder(for: modelName).path
+        // A resumed download starts from where it was paused.
+        let previous = downloadStatuses[modelName]
+        let initial = ModelStatus(model: modelName,
+                                  state: .downloading,
+                                  percent: previous?.percent ?? 0,
+                                  bytesDownloaded: previous?.bytesDownloaded ?? 0,
+                                  totalBytes: previous?.totalBytes ?? 0,
+                                  path: path)
         DispatchQueue.main.async {
             self.downloadState = "Downloading"
-            self.downloadProgresses[modelName] = 0.0
+            self.setDownloadStatus(initial, for: modelName)
         }
         
-        Task {
+        downloadTasks[modelName] = Task {
             do {
-                _ = try await WhisperKit.download(
-                    variant: modelName,
-                    downloadBase: baseDirectoryPath,
-                    from: repo,
-                    progressCallback: { progress in
+                // ModelDownloadService resumes partial files left by an interrupted run and
+                // switches mirrors when the current one fails or stalls.
+                try await modelDownloader.runDownload(
+                    repo: repo,
+                    folder: folder,
+                    destination: baseDirectoryPath.appendingPathComponent("models/\(repo)", isDirectory: true),
+                    mirrors: mirrors,
+                    progress: { progress in
+                        let percent = Int(progress.fractionCompleted * 100)
+                        // Only name the source once it is not the primary mirror.
+                        let primary = (mirrors ?? ModelDownloadService.defaultMirrors).first.map(ModelDownloadService.sourceName)
+                        let via = progress.source.isEmpty || progress.source == primary ? "" : " from \(progress.source)"
+                        let state: String
+                        if let resumedFrom = progress.resumedFromByte {
+                            state = "Resumed from \(DiskSpace.format(resumedFrom))\(via)... \(percent)%"
+                        } else {
+                            state = "Downloading\(via)... \(percent)%"
+                        }
+                        let status = ModelStatus(model: modelName,
+                                                 state: .downloading,
+                                                 percent: percent,
+                                                 bytesDownloaded: progress.bytesReceived,
+                                                 totalBytes: progress.totalBytes,
+                                                 path: path,
+                                                 source: progress.source.isEmpty ? nil : progress.source)
                         DispatchQueue.main.async {
-                            self.downloadProgresses[modelName] = Float(progress.fractionCompleted)
-                            self.downloadState = "Downloading... \(Int(progress.fractionCompleted * 100))%"
+                            // A late callback must not revive a paused or cancelled download.
+                            guard self.downloadStatuses[modelName]?.state == .downloading else { return }
+                            if self.downloadStatuses[modelName]?.percent != percent {
+                                self.setDownloadStatus(status, for: modelName)
+                            } else {
+                                self.downloadStatuses[modelName] = status
+                            }
+                            self.downloadState = state
                         }
                     }
                 )
                 
                 Logger.shared.info("WhisperService: Successfully downloaded model '\(modelName)'")
+                NotificationService.shared.post(.modelDownloaded(model: modelName))
                 checkDownloadedModels()
                 
                 DispatchQueue.main.async {
-                    self.downloadProgresses.removeValue(forKey: modelName)
+                    self.downloadTasks.removeValue(forKey: modelName)
+                    self.setDownloadStatus(nil, for: modelName)
                     self.downloadState = "Ready"
+                    self.startQueuedDownloads()
                 }
                 
                 // If this is the currently selected model, initialize it now
@@ -364,24 +689,94 @@ class WhisperService: ObservableObject, @unchecked Sendable {
                 }
                 
             } catch {
+                // pauseDownload / cancelDownload already updated the published state.
+                if Task.isCancelled {
+                    Logger.shared.info("WhisperService: Download for model '\(modelName)' stopped")
+                    return
+                }
                 Logger.shared.error("WhisperService: Download failed for model '\(modelName)': \(error)")
+                // Download errors (e.g. "Not enough disk space: need 3.1 GB, have 1.2 GB.")
+                // are worded for the user; anything else is reported generically.
+                let message = (error as? ModelDownloadError)?.errorDescription ?? "Failed"
                 DispatchQueue.main.async {
-                    self.downloadState = "Failed"
-                    self.downloadProgresses.removeValue(forKey: modelName)
+                    self.downloadTasks.removeValue(forKey: modelName)
+                    self.downloadState = message
+                    var failed = self.downloadStatuses[modelName] ?? .notDownloaded(modelName, path: path)
+                    failed.state = .failed
+                    failed.source = nil
+                    failed.lastError = error.localizedDescription
+                    self.setDownloadStatus(failed, for: modelName)
+                    self.startQueuedDownloads()
                 }
             }
         }
     }
     
+    /// Stops an in-flight or queued download, keeping its partial files so the next
+    /// `downloadModel` call resumes where it left off. Call on the main thread.
+    /// Returns `false` when `modelName` is not downloading or queued.
+    @discardableResult
+    func pauseDownload(_ modelName: String) -> Bool {
+        let task = downloadTasks.removeValue(forKey: modelName)
+        let wasQueued = removeFromQueue(modelName)
+        guard task != nil || wasQueued else { return false }
+        task?.cancel()
+        var paused = downloadStatuses[modelName] ?? .notDownloaded(modelName)
+        paused.state = .paused
+        paused.source = nil
+        setDownloadStatus(paused, for: modelName)
+        downloadState = "Paused"
+        Logger.shared.info("WhisperService: Paused download for model '\(modelName)'")
+        startQueuedDownloads()
+        return true
+    }
+    
+    /// Drops `modelName` from the download queue and renumbers the rest.
+    /// Returns `false` when it was not queued.
+    private func removeFromQueue(_ modelName: String) -> Bool {
+        guard let index = downloadQueue.firstIndex(of: modelName) else { return false }
+        downloadQueue.remove(at: index)
+        publishQueue()
+        return true
+    }
+
+    /// Stops an in-flight, queued or paused download and deletes its partial files.
+    /// Call on the main thread. Returns `false` when there was nothing to cancel.
+    @discardableResult
+    func cancelDownload(_ modelName: String) -> Bool {
+        let task = downloadTasks.removeValue(forKey: modelName)
+        let wasPaused = downloadStatuses[modelName]?.state == .paused
+        let wasQueued = removeFromQueue(modelName)
+        guard task != nil || wasPaused || wasQueued else { return false }
+        task?.cancel()
+        setDownloadStatus(nil, for: modelName)
+        downloadState = "Cancelled"
+        Logger.shared.info("WhisperService: Cancelled download for model '\(modelName)'")
+        startQueuedDownloads()
+        
+        let modelFolder = modelFolder(for: modelName)
+        Task {
+            // Let the download loop observe cancellation before its files are removed.
+            await task?.value
+            guard ModelDownloadService.isIncomplete(modelFolder) else { return }
+            do {
+                try FileManager.default.removeItem(at: modelFolder)
+                Logger.shared.info("WhisperService: Removed partial download at \(modelFolder.path)")
+            } catch {
+                Logger.shared.error("WhisperService: Failed to remove partial download \(modelFolder.path): \(error)")
+            }
+        }
+        return true
+    }
+    
     func deleteModel(_ modelName: String) {
         Logger.shared.info("WhisperService: Requested to delete model '\(modelName)'")
         let fileManager = FileManager.default
-        let folderName = modelName.hasPrefix("distil-whisper_")
-            ? modelName
-            : "openai_whisper-\(modelName)"
+        let folderName = Self.folderName(for: modelName)
 
-        // Primary: model files are at repoDestination/<folderName>
-        let primaryDir = repoDestination.appendingPathComponent(folderName)
+        // Primary: model files are at repoDestination/<folderName> (custom models: see ModelRegistry).
+        // An imported symlink is removed without touching the folder it points to.
+        let primaryDir = modelFolder(for: modelName)
         // Secondary: any incomplete/cache copies under .cache/
         let cacheDir = repoDestination
             .appendingPathComponent(".cache/huggingface/download/\(folderName)", isDirectory: true)
@@ -399,8 +794,19 @@ class WhisperService: ObservableObject, @unchecked Sendable {
             }
         }
 
+        // Imported models cannot be downloaded again, so drop them from the list too.
+        if let custom = ModelRegistry.shared.model(id: modelName), custom.repo == nil {
+            try? ModelRegistry.shared.remove(id: modelName)
+        }
+
         if deleted {
+            corruptFiles.removeValue(forKey: modelName)
+            corruptModels.remove(modelName)
+            downloadStatuses.removeValue(forKey: modelName)
             checkDownloadedModels()
+            DispatchQueue.main.async {
+                self.postStatus(for: modelName)
+            }
             if activeModel == modelName {
                 Logger.shared.info("WhisperService: Deleted model was the active model. Unloading WhisperKit...")
                 self.whisperKit = nil
@@ -454,18 +860,21 @@ extension WhisperService: TranscriptionEngine {
         // - Auto-Detect (nil): usePrefillPrompt=false + detectLanguage=true → standard auto-detect
         let isExplicitLanguage = langCode != nil
         // Start greedy (temperature 0) for fastest decode path.
-        // Cap fallback retries to 1 (default is 5) — each retry runs a full decoder pass.
+        // Cap fallback retries (WhisperKit default is 5) — each retry runs a full decoder pass.
         // For short dictation audio with a nearby microphone, the first greedy pass
-        // almost always succeeds, so 5 retries are wasteful.
+        // almost always succeeds. Retry and worker counts are chosen per machine by
+        // HardwareDefaultsService and can be overridden in settings.
+        let settings = SettingsStore.shared.settings
         let decodingOptions = DecodingOptions(
             language: langCode,
             temperature: 0.0,
-            temperatureFallbackCount: 1,
+            temperatureFallbackCount: settings.temperatureFallbackCount,
             usePrefillPrompt: isExplicitLanguage,
             usePrefillCache: true,
             detectLanguage: isExplicitLanguage ? false : nil, // nil = WhisperKit defaults (auto-detect when prefill off)
             skipSpecialTokens: true,
-            withoutTimestamps: true
+            withoutTimestamps: true,
+            concurrentWorkerCount: settings.transcriptionWorkerCount
             // Note: chunkingStrategy: .vad was removed — it runs a full neural VAD
             // pre-processing pass before encoding, adding ~200-600ms of latency on
             // short dictation clips. Our trimSilence() handles silence more cheaply.
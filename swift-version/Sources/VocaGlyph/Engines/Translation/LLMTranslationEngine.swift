@@ -0,0 +1,34 @@
+import Foundation
+
+/// Where translations come from.
+enum TranslationProvider: String, CaseIterable {
+    case off
+    /// The post-processing engine selected under AI Refinement — fully local with
+    /// the local LLM or Apple Intelligence.
+    case llm
+    /// A LibreTranslate-compatible server; see `RemoteTranslationEngine`.
+    case remote
+}
+
+/// Translates by asking a `PostProcessingEngine` for a translation instead of a cleanup.
+struct LLMTranslationEngine: TranslationEngine {
+    let engine: any PostProcessingEngine
+
+    func translate(_ text: String, from source: String?, to target: String) async throws -> String {
+        try await engine.refine(text: text, prompt: Self.prompt(from: source, to: target))
+    }
+
+    static func prompt(from source: String?, to target: String) -> String {
+        let from = source.map { " from \(languageName($0))" } ?? ""
+        return """
+            Translate the text\(from) into \(languageName(target)).
+            Keep the meaning, tone, names, numbers and line breaks.
+            Reply with the translation only.
+            """
+    }
+
+    /// English name for an ISO code, e.g. "de" → "German"; the code itself when unknown.
+    static func languageName(_ code: String) -> String {
+        Locale(identifier: "en").localizedString(forLanguageCode: code) ?? code
+    }
+}
@@ -0,0 +1,87 @@
+import Foundation
+
+/// Errors that can occur while translating with a remote server
+public enum RemoteTranslationError: LocalizedError, Equatable {
+    case invalidEndpoint
+    case networkError(String)
+    case apiError(statusCode: Int, message: String)
+    case invalidResponseFormat
+
+    public var errorDescription: String? {
+        switch self {
+        case .invalidEndpoint:
+            return "The translation server URL is invalid. Check it in Settings."
+        case .networkError(let reason):
+            return "Network connection failed: \(reason)"
+        case .apiError(let statusCode, let message):
+            return "Translation API Error (\(statusCode)): \(message)"
+        case .invalidResponseFormat:
+            return "The response from the translation server was not in the expected format."
+        }
+    }
+}
+
+/// Translation via a LibreTranslate-compatible `POST /translate` endpoint, either
+/// self-hosted or a paid instance. The API key is optional and read from the Keychain.
+public actor RemoteTranslationEngine: TranslationEngine {
+    private let endpoint: String
+    private let keychainService: KeychainService
+    private let session: URLSession
+
+    public init(endpoint: String, keychainService: KeychainService = KeychainService(), session: URLSession = .shared) {
+        self.endpoint = endpoint
+        self.keychainService = keychainService
+        self.session = session
+    }
+
+    public func translate(_ text: String, from source: String?, to target: String) async throws -> String {
+        guard let base = URL(string: endpoint.trimmingCharacters(in: .whitespacesAndNewlines)),
+              let scheme = base.scheme, ["http", "https"].contains(scheme), base.host != nil else {
+            throw RemoteTranslationError.invalidEndpoint
+        }
+        let url = base.path.hasSuffix("/translate") ? base : base.appendingPathComponent("translate")
+
+        var payload: [String: Any] = [
+            "q": text,
+            "source": source ?? "auto",
+            "target": target,
+            "format": "text",
+        ]
+        if let apiKey = try? await keychainService.secret(.translationApiKey) {
+            payload["api_key"] = apiKey
+        }
+
+        var request = URLRequest(url: url)
+        request.httpMethod = "POST"
+        request.setValue("application/json", forHTTPHeaderField: "Content-Type")
+        request.httpBody = try JSONSerialization.data(withJSONObject: payload)
+
+        PostProcessingLogger.shared.info("RemoteTranslationEngine: [REQUEST] POST \(url.host ?? "") \(source ?? "auto") → \(target)")
+        PostProcessingLogger.shared.info("RemoteTranslationEngine: [REQUEST] Input (\(text.count) chars): '\(Logger.transcript(text))'")
+
+        let data: Data
+        let response: URLResponse
+        do {
+            (data, response) = try await session.data(for: request)
+        } catch {
+            Logger.shared.error("RemoteTranslationEngine: Network connection failed: \(error.localizedDescription)")
+            throw RemoteTranslationError.networkError(error.localizedDescription)
+        }
+
+        guard let httpResponse = response as? HTTPURLResponse else {
+            throw RemoteTranslationError.invalidResponseFormat
+        }
+        let json = try? JSONSerialization.jsonObject(with: data) as? [String: Any]
+
+        if !(200...299).contains(httpResponse.statusCode) {
+            let message = json?["error"] as? String ?? "Unknown API Error"
+            throw RemoteTranslationError.apiError(statusCode: httpResponse.statusCode, message: message)
+        }
+
+        guard let translated = json?["translatedText"] as? String else {
+            throw RemoteTranslationError.invalidResponseFormat
+        }
+        PostProcessingLogger.shared.info("RemoteTranslationEngine: [RESULT] '\(Logger.transcript(translated))'")
+        return translated
+    }
+}
@@ -0,0 +1,59 @@
+import ApplicationServices
+import Foundation
+
+// MARK: - AccessibilityWatcher
+
+/// Notices when the user grants Accessibility in System Settings. macOS posts nothing
+/// when that happens, so the trust flag is polled while it is off; when it flips,
+/// `AccessibilityGrantedEvent` is posted, `onGranted` runs and polling stops.
+///
+/// Without this the event tap created at launch stays dead until the app is restarted,
+/// even though pasting and context capture would already work.
+final class AccessibilityWatcher {
+
+    private let isTrusted: () -> Bool
+    private let interval: TimeInterval
+    private let center: NotificationCenter
+    private var timer: Timer?
+
+    /// Called on the main thread once the permission is granted.
+    var onGranted: (() -> Void)?
+
+    init(isTrusted: @escaping () -> Bool = { AXIsProcessTrusted() },
+         interval: TimeInterval = 1,
+         center: NotificationCenter = .default) {
+        self.isTrusted = isTrusted
+        self.interval = interval
+        self.center = center
+    }
+
+    deinit {
+        timer?.invalidate()
+    }
+
+    var isWatching: Bool { timer != nil }
+
+    /// Starts polling unless Accessibility is already granted or polling is running.
+    /// Call on the main thread, e.g. right after prompting.
+    func start() {
+        guard !isWatching, !isTrusted() else { return }
+        Logger.shared.info("AccessibilityWatcher: Waiting for Accessibility to be granted")
+        timer = Timer.scheduledTimer(withTimeInterval: interval, repeats: true) { [weak self] _ in
+            self?.poll()
+        }
+    }
+
+    func stop() {
+        timer?.invalidate()
+        timer = nil
+    }
+
+    /// Checks once; the timer calls this, tests may too.
+    func poll() {
+        guard isWatching, isTrusted() else { return }
+        stop()
+        Logger.shared.info("AccessibilityWatcher: Accessibility granted")
+        AccessibilityGrantedEvent().post(from: self, to: center)
+        onGranted?()
+    }
+}
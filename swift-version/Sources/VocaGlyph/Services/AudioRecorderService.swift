@@ -32,7 +32,16 @@ class AudioRecorderService {
     /// the AVAudioEngine starts. `weak` prevents a retain cycle with AppDelegate.
     weak var microphoneService: MicrophoneService?
 
-    init() {
+    /// Called on a background queue with the RMS level (0…1) of each captured buffer,
+    /// for the overlay's level meter.
+    var onLevel: ((Float) -> Void)?
+
+    /// Checked before the engine starts, so a missing permission is reported as such
+    /// instead of as a silent or failed capture.
+    private let permissions: SystemPermissionsProvider
+
+    init(permissions: SystemPermissionsProvider = DefaultSystemPermissionsProvider()) {
+        self.permissions = permissions
         requestPermissions()
         // Watch for AVAudioEngine I/O reconfigurations (device changes, window focus
         // transitions, post-TCC permission grants). Without this the engine can become
@@ -60,11 +69,12 @@ class AudioRecorderService {
     }
 
     private func requestPermissions() {
-        switch AVCaptureDevice.authorizationStatus(for: .audio) {
+        switch permissions.getMicrophoneAuthorizationStatus() {
         case .authorized:
             Logger.shared.info("Microphone access ready.")
         case .notDetermined:
-            AVCaptureDevice.requestAccess(for: .audio) { granted in
+            Task { [permissions] in
+                let granted = await permissions.requestMicrophoneAccess()
                 Logger.shared.info("Microphone access granted: \(granted)")
             }
         default:
@@ -76,6 +86,13 @@ class AudioRecorderService {
     // Throws if the audio engine cannot be started so that callers can
     // immediately reset state rather than silently hanging.
     func startRecording() throws {
+        // Pre-flight: without access AVAudioEngine still starts but captures silence.
+        switch permissions.getMicrophoneAuthorizationStatus() {
+        case .authorized:    break
+        case .notDetermined: throw AudioRecorderError.microphoneAccessNotDetermined
+        default:             throw AudioRecorderError.microphoneAccessDenied
+        }
+
         // 0. Apply the user's microphone preference as the system default input.
         //    AVAudioEngine.inputNode always follows the system default on macOS, so
         //    we set it here (on the audio queue) before the engine graph is rebuilt.
@@ -145,12 +162,36 @@ class AudioRecorderService {
         let data = recordedData
         recordedData.removeAll()
         bufferLock.unlock()
+        onLevel?(0)
 
         // [DIAG] Step 2 — compare this count between SPM build and Xcode build for the same speech duration.
         // If significantly lower in the Xcode build → audio pipeline is being cut short (H1) or mic is silent (H3).
         let durationSecs = Float(data.count) / Float(targetSampleRate)
         Logger.shared.info("AudioRecorder: Stopped — captured \(data.count) frames at 16 kHz (≈\(String(format: "%.2f", durationSecs))s)")
 
+        return makeBuffer(from: data)
+    }
+
+    /// The audio captured so far, without stopping the recording. Used for the live
+    /// transcription preview; `nil` before any audio arrives.
+    func snapshot() -> AVAudioPCMBuffer? {
+        bufferLock.lock()
+        let data = recordedData
+        bufferLock.unlock()
+        return makeBuffer(from: data)
+    }
+
+    /// Memory held for the current recording's samples; empty between recordings.
+    func bufferUsage() -> ResourceUsage.AudioBuffer {
+        bufferLock.lock()
+        defer { bufferLock.unlock() }
+        return ResourceUsage.AudioBuffer(bytes: Int64(recordedData.capacity * MemoryLayout<Float>.size),
+                                         seconds: Double(recordedData.count) / targetSampleRate)
+    }
+
+    // MARK: - Private helpers
+
+    private func makeBuffer(from data: [Float]) -> AVAudioPCMBuffer? {
         guard !data.isEmpty else { return nil }
 
         guard let format = AVAudioFormat(
@@ -174,8 +215,6 @@ class AudioRecorderService {
         return buffer
     }
 
-    // MARK: - Private helpers
-
     private func processBuffer(buffer: AVAudioPCMBuffer) {
         // Fast path: already in the right format
         if buffer.format.sampleRate == targetSampleRate && buffer.format.channelCount == 1 {
@@ -229,16 +268,24 @@ class AudioRecorderService {
         bufferLock.lock()
         recordedData.append(contentsOf: slice)
         bufferLock.unlock()
+
+        if let onLevel, !slice.isEmpty {
+            onLevel(min(1, sqrt(slice.reduce(Float(0)) { $0 + $1 * $1 } / Float(slice.count))))
+        }
     }
 }
 
 // MARK: - Errors
 enum AudioRecorderError: Error, LocalizedError {
     case formatCreationFailed
+    case microphoneAccessNotDetermined
+    case microphoneAccessDenied
 
     var errorDescription: String? {
         switch self {
         case .formatCreationFailed: return "Could not create 16 kHz output format"
+        case .microphoneAccessNotDetermined: return "Allow microphone access, then try again"
+        case .microphoneAccessDenied: return "Microphone access is off. Allow VocaGlyph in System Settings → Privacy & Security → Microphone"
         }
     }
 }
@@ -0,0 +1,413 @@
+import CryptoKit
+import Darwin
+import Foundation
+
+// MARK: - Caption
+
+/// One message of the live caption stream, sent as a WebSocket text frame:
+///
+///     {"id":3,"text":"so today we","timestamp":1760600000.5,"type":"partial"}
+///     {"id":3,"text":"So today we're building a parser.","timestamp":1760600002.1,"type":"final"}
+///     {"id":4,"text":"","timestamp":1760600009.8,"type":"clear"}
+///
+/// `id` counts dictations: each `partial` replaces the text shown for its id and the
+/// `final` settles it. `clear` ends a dictation that had partials but no text in the
+/// end (cancelled, silence), so they don't stay on screen.
+struct Caption: Codable, Equatable {
+
+    enum Kind: String, Codable {
+        case partial
+        case final
+        case clear
+    }
+
+    let type: Kind
+    let id: Int
+    let text: String
+    /// Seconds since 1970.
+    let timestamp: TimeInterval
+}
+
+// MARK: - CaptionSequence
+
+/// Numbers the captions of consecutive dictations. `CaptionServer` keeps one on its queue.
+struct CaptionSequence {
+
+    private(set) var id = 0
+    /// Whether partials of `id` went out without a final yet.
+    private var isOpen = false
+    private var lastPartial: String?
+
+    /// `nil` for empty text, or text the previous partial already showed.
+    mutating func partial(_ text: String, at date: Date = Date()) -> Caption? {
+        let text = text.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !text.isEmpty, text != lastPartial else { return nil }
+        if !isOpen {
+            id += 1
+            isOpen = true
+        }
+        lastPartial = text
+        return Caption(type: .partial, id: id, text: text, timestamp: date.timeIntervalSince1970)
+    }
+
+    /// Empty text ends the dictation like `end(at:)`.
+    mutating func final(_ text: String, at date: Date = Date()) -> Caption? {
+        let text = text.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !text.isEmpty else { return end(at: date) }
+        if !isOpen { id += 1 }
+        isOpen = false
+        lastPartial = nil
+        return Caption(type: .final, id: id, text: text, timestamp: date.timeIntervalSince1970)
+    }
+
+    /// A `clear` when the dictation showed partials but got no final; `nil` otherwise.
+    mutating func end(at date: Date = Date()) -> Caption? {
+        guard isOpen else { return nil }
+        isOpen = false
+        lastPartial = nil
+        return Caption(type: .clear, id: id, text: "", timestamp: date.timeIntervalSince1970)
+    }
+}
+
+// MARK: - CaptionServer
+
+/// Optional live captions on `127.0.0.1`, for streamers: partial and final transcriptions
+/// go out as `Caption` JSON to every WebSocket client, e.g. an OBS browser source.
+///
+///     http://127.0.0.1:7979/     a transparent caption page to add as a browser source
+///     ws://127.0.0.1:7979/       the raw stream, for your own overlay
+///
+/// The page hides a final caption after six seconds; `?hold=N` changes that. Restyle it
+/// with the browser source's custom CSS (`#caption`).
+///
+/// Browsers only get the stream from the page above: a WebSocket request with any other
+/// `Origin` is refused, so websites open in a browser can't listen in. Tools that send no
+/// `Origin` (OBS plugins, scripts) are let through.
+///
+/// Partials come from the live preview, which runs while captions are on even if the
+/// overlay doesn't show it.
+final class CaptionServer {
+
+    enum Reply: Equatable {
+        /// Switch to WebSocket with this `Sec-WebSocket-Accept`.
+        case upgrade(accept: String)
+        case page
+        case badRequest
+        case forbidden
+        case notFound
+    }
+
+    static let portRange = 1024...65535
+    /// RFC 6455's key suffix for `Sec-WebSocket-Accept`.
+    static let webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+    private static let maxRequestBytes = 8 * 1024
+    /// Seconds a browser gets to send its request headers.
+    static let requestTimeout: TimeInterval = 5
+
+    let port: UInt16
+    private let queue = DispatchQueue(label: "com.vocaglyph.caption-server")
+    private var listener: Int32 = -1
+    private var source: DispatchSourceRead?
+    /// Open WebSocket connections, each with the source that notices it closing. Only
+    /// touched on `queue`.
+    private var clients: [Int32: DispatchSourceRead] = [:]
+    /// Only touched on `queue`.
+    private var sequence = CaptionSequence()
+
+    init(port: UInt16) {
+        self.port = port
+    }
+
+    deinit {
+        stop()
+        // Every block on `queue` holds `self` weakly, so none can be using these now.
+        clients.values.forEach { $0.cancel() }
+    }
+
+    var isRunning: Bool { source != nil }
+
+    var clientCount: Int { queue.sync { clients.count } }
+
+    func start() throws {
+        guard !isRunning else { return }
+        let fd = socket(AF_INET, SOCK_STREAM, 0)
+        guard fd >= 0 else { throw ControlSocketError.failed("socket") }
+        var reuse: Int32 = 1
+        setsockopt(fd, SOL_SOCKET, SO_REUSEADDR, &reuse, socklen_t(MemoryLayout<Int32>.size))
+
+        var address = sockaddr_in()
+        address.sin_len = UInt8(MemoryLayout<sockaddr_in>.size)
+        address.sin_family = sa_family_t(AF_INET)
+        address.sin_port = port.bigEndian
+        address.sin_addr.s_addr = inet_addr("127.0.0.1")
+        let bound = withUnsafePointer(to: &address) {
+            $0.withMemoryRebound(to: sockaddr.self, capacity: 1) {
+                bind(fd, $0, socklen_t(MemoryLayout<sockaddr_in>.size))
+            }
+        }
+        var failure: ControlSocketError?
+        if bound != 0 {
+            failure = .failed("bind")
+        } else if listen(fd, 8) != 0 {
+            failure = .failed("listen")
+        }
+        if let failure {
+            close(fd)
+            throw failure
+        }
+
+        listener = fd
+        let source = DispatchSource.makeReadSource(fileDescriptor: fd, queue: queue)
+        source.setEventHandler { [weak self] in self?.acceptClient() }
+        source.resume()
+        self.source = source
+        Logger.shared.info("CaptionServer: Serving http://127.0.0.1:\(port)/")
+    }
+
+    func stop() {
+        guard let source else { return }
+        source.cancel()
+        self.source = nil
+        close(listener)
+        listener = -1
+        // Async: waiting here would stall the main thread behind a slow `broadcast`.
+        queue.async { [weak self] in
+            guard let self else { return }
+            self.clients.values.forEach { $0.cancel() }
+            self.clients.removeAll()
+        }
+        Logger.shared.info("CaptionServer: Stopped")
+    }
+
+    // MARK: - Captions
+
+    /// The live preview of the dictation in progress.
+    func sendPartial(_ text: String) {
+        queue.async { [weak self] in
+            guard let self, let caption = self.sequence.partial(text) else { return }
+            self.broadcast(caption)
+        }
+    }
+
+    /// The dictation's finished text, after text processing.
+    func sendFinal(_ text: String) {
+        queue.async { [weak self] in
+            guard let self, let caption = self.sequence.final(text) else { return }
+            self.broadcast(caption)
+        }
+    }
+
+    /// Call when the app rests again; clears partials no final replaced.
+    func dictationEnded() {
+        queue.async { [weak self] in
+            guard let self, let caption = self.sequence.end() else { return }
+            self.broadcast(caption)
+        }
+    }
+
+    /// Writes `caption` to every client; ones that fail the write are dropped.
+    private func broadcast(_ caption: Caption) {
+        guard !clients.isEmpty, let json = try? JSONEncoder.caption.encode(caption) else { return }
+        let frame = Self.textFrame(json)
+        for client in clients.keys {
+            let written = frame.withUnsafeBytes { write(client, $0.baseAddress, $0.count) }
+            if written != frame.count { drop(client) }
+        }
+    }
+
+    // MARK: - Connections
+
+    private func acceptClient() {
+        let client = accept(listener, nil, nil)
+        guard client >= 0 else { return }
+        // A client that went away must fail the write, not kill the app.
+        var noSigPipe: Int32 = 1
+        setsockopt(client, SOL_SOCKET, SO_NOSIGPIPE, &noSigPipe, socklen_t(MemoryLayout<Int32>.size))
+        // A stalled browser must not hold up `broadcast` for everyone else.
+        var sendTimeout = timeval(tv_sec: 2, tv_usec: 0)
+        setsockopt(client, SOL_SOCKET, SO_SNDTIMEO, &sendTimeout, socklen_t(MemoryLayout<timeval>.size))
+        var receiveTimeout = timeval(tv_sec: Int(Self.requestTimeout), tv_usec: 0)
+        setsockopt(client, SOL_SOCKET, SO_RCVTIMEO, &receiveTimeout, socklen_t(MemoryLayout<timeval>.size))
+
+        let port = port
+        DispatchQueue.global(qos: .utility).async { [weak self] in
+            var request = Data()
+            var buffer = [UInt8](repeating: 0, count: 4096)
+            let deadline = Date().addingTimeInterval(Self.requestTimeout)
+            while request.count < Self.maxRequestBytes, Date() < deadline {
+                let count = read(client, &buffer, buffer.count)
+                guard count > 0 else { break }
+                request.append(buffer, count: count)
+                if request.range(of: Data("\r\n\r\n".utf8)) != nil { break }
+            }
+
+            let reply = Self.reply(to: String(decoding: request, as: UTF8.self), port: port)
+            let response = Self.response(for: reply)
+            response.withUnsafeBytes { _ = write(client, $0.baseAddress, $0.count) }
+            guard case .upgrade = reply, let self else {
+                close(client)
+                return
+            }
+            self.queue.async { self.register(client) }
+        }
+    }
+
+    private func register(_ client: Int32) {
+        guard isRunning else {
+            close(client)
+            return
+        }
+        let reader = DispatchSource.makeReadSource(fileDescriptor: client, queue: queue)
+        reader.setEventHandler { [weak self] in
+            var buffer = [UInt8](repeating: 0, count: 1024)
+            let count = read(client, &buffer, buffer.count)
+            // Caption clients only ever send a close frame (opcode 8); anything else is
+            // ignored.
+            if count <= 0 || buffer[0] & 0x0F == 0x8 {
+                self?.drop(client)
+            }
+        }
+        reader.setCancelHandler { close(client) }
+        reader.resume()
+        clients[client] = reader
+        Logger.shared.info("CaptionServer: Client connected (\(clients.count) open)")
+    }
+
+    /// Closes `client` through its reader's cancel handler.
+    private func drop(_ client: Int32) {
+        guard let reader = clients.removeValue(forKey: client) else { return }
+        reader.cancel()
+        Logger.shared.info("CaptionServer: Client disconnected (\(clients.count) open)")
+    }
+
+    // MARK: - HTTP
+
+    /// What to answer an HTTP request to this server on `port`.
+    static func reply(to request: String, port: UInt16) -> Reply {
+        let lines = request.components(separatedBy: "\r\n")
+        let parts = (lines.first ?? "").split(separator: " ")
+        guard parts.count >= 2, parts[0] == "GET" else { return .badRequest }
+        guard URLComponents(string: String(parts[1]))?.path == "/" else { return .notFound }
+
+        var headers: [String: String] = [:]
+        for line in lines.dropFirst() {
+            guard let colon = line.firstIndex(of: ":") else { continue }
+            headers[line[..<colon].lowercased()] = line[line.index(after: colon)...]
+                .trimmingCharacters(in: .whitespaces)
+        }
+        guard headers["upgrade"]?.lowercased() == "websocket" else { return .page }
+        guard let key = headers["sec-websocket-key"], !key.isEmpty else { return .badRequest }
+        if let origin = headers["origin"], !allowedOrigins(port: port).contains(origin) {
+            Logger.shared.error("CaptionServer: Refused a WebSocket request from \(origin)")
+            return .forbidden
+        }
+        return .upgrade(accept: acceptKey(for: key))
+    }
+
+    /// The origins of the caption page, as a browser reports them.
+    static func allowedOrigins(port: UInt16) -> Set<String> {
+        ["http://127.0.0.1:\(port)", "http://localhost:\(port)"]
+    }
+
+    /// `Sec-WebSocket-Accept` for a client's `Sec-WebSocket-Key`.
+    static func acceptKey(for key: String) -> String {
+        Data(Insecure.SHA1.hash(data: Data((key + webSocketGUID).utf8))).base64EncodedString()
+    }
+
+    private static func response(for reply: Reply) -> Data {
+        switch reply {
+        case .upgrade(let accept):
+            return Data("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: \(accept)\r\n\r\n".utf8)
+        case .page:
+            return httpResponse("200 OK", "text/html; charset=utf-8", page)
+        case .badRequest:
+            return httpResponse("400 Bad Request", "text/plain; charset=utf-8", "Bad request.\n")
+        case .forbidden:
+            return httpResponse("403 Forbidden", "text/plain; charset=utf-8", "Origin not allowed.\n")
+        case .notFound:
+            return httpResponse("404 Not Found", "text/plain; charset=utf-8", "Not found. Captions are at /.\n")
+        }
+    }
+
+    private static func httpResponse(_ status: String, _ contentType: String, _ body: String) -> Data {
+        let body = Data(body.utf8)
+        var response = Data("HTTP/1.1 \(status)\r\nContent-Type: \(contentType)\r\nContent-Length: \(body.count)\r\nConnection: close\r\n\r\n".utf8)
+        response.append(body)
+        return response
+    }
+
+    // MARK: - WebSocket
+
+    /// An unmasked, unfragmented text frame; servers never mask.
+    static func textFrame(_ payload: Data) -> Data {
+        var frame = Data([0x81])
+        switch payload.count {
+        case ..<126:
+            frame.append(UInt8(payload.count))
+        case ...0xFFFF:
+            frame.append(126)
+            withUnsafeBytes(of: UInt16(payload.count).bigEndian) { frame.append(contentsOf: $0) }
+        default:
+            frame.append(127)
+            withUnsafeBytes(of: UInt64(payload.count).bigEndian) { frame.append(contentsOf: $0) }
+        }
+        frame.append(payload)
+        return frame
+    }
+
+    // MARK: - Page
+
+    /// Captions along the bottom, on a transparent background. Reconnects when the app
+    /// restarts.
+    static let page = """
+        <!doctype html>
+        <html>
+        <head>
+        <meta charset="utf-8">
+        <title>VocaGlyph Captions</title>
+        <style>
+        html, body { margin: 0; background: transparent; overflow: hidden; }
+        #caption {
+          position: fixed; left: 5%; right: 5%; bottom: 6%;
+          text-align: center; color: #fff;
+          font: 600 42px/1.25 -apple-system, "Helvetica Neue", sans-serif;
+          text-shadow: 0 2px 6px rgba(0, 0, 0, 0.9);
+          transition: opacity 0.4s;
+        }
+        #caption.partial { opacity: 0.8; }
+        #caption.hidden { opacity: 0; }
+        </style>
+        </head>
+        <body>
+        <div id="caption" class="hidden"></div>
+        <script>
+        const caption = document.getElementById("caption");
+        const hold = Number(new URLSearchParams(location.search).get("hold") || 6) * 1000;
+        let timer;
+        function connect() {
+          const socket = new WebSocket(`ws://${location.host}/`);
+          socket.onmessage = (message) => {
+            const update = JSON.parse(message.data);
+            clearTimeout(timer);
+            if (update.type === "clear") { caption.className = "hidden"; return; }
+            caption.textContent = update.text;
+            caption.className = update.type;
+            if (update.type === "final") { timer = setTimeout(() => { caption.className = "hidden"; }, hold); }
+          };
+          socket.onclose = () => setTimeout(connect, 2000);
+        }
+        connect();
+        </script>
+        </body>
+        </html>
+
+        """
+}
+
+private extension JSONEncoder {
+    static let caption: JSONEncoder = {
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = .sortedKeys
+        return encoder
+    }()
+}
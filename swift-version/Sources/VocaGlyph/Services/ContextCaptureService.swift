@@ -0,0 +1,151 @@
+import Foundation
+import AppKit
+import ApplicationServices
+
+// MARK: - FocusedTextProvider
+
+/// Reads the frontmost app and the text around the insertion point. Abstracted so
+/// `ContextCaptureService` can be tested without Accessibility access.
+protocol FocusedTextProvider {
+    /// Bundle identifier of the app that will receive the pasted transcription.
+    func frontmostBundleIdentifier() -> String?
+    /// Up to `maxLength` characters immediately before the insertion point of the
+    /// focused text element, or `nil` if nothing readable is focused.
+    func textBeforeInsertionPoint(maxLength: Int) -> String?
+    /// The focused text element's selected text, or `nil` if nothing is selected or
+    /// readable.
+    func selectedText() -> String?
+}
+
+// MARK: - ContextCaptureService
+
+/// Captures the text the user is dictating into, so post-processing can match its
+/// wording, names and tone.
+///
+/// Controlled by three preferences in `AppSettings`:
+/// - `contextCaptureEnabled` — global on/off switch (Settings → Privacy).
+/// - `contextCaptureCharacterLimit` — how many characters before the cursor are read.
+/// - `contextCaptureExcludedApps` — bundle identifiers that are never read
+///   (password managers, banking apps, …).
+///
+/// Capture is off by default. It is skipped entirely — no Accessibility call is made —
+/// when disabled, when AI post-processing (its only consumer) is off, while incognito
+/// mode is on, or when the frontmost app is excluded.
+///
+/// With `selectionRewriteEnabled`, `captureSelection()` also reads the selected text so
+/// the dictation can be used as an instruction for rewriting it. It follows the same
+/// incognito and excluded-app rules, but not `contextCaptureEnabled`.
+final class ContextCaptureService {
+
+    static let defaultCharacterLimit = 200
+    static let characterLimitRange = 1...2000
+
+    private let store: SettingsStore
+    private let provider: FocusedTextProvider
+
+    init(store: SettingsStore = .shared, provider: FocusedTextProvider = AccessibilityFocusedTextProvider()) {
+        self.store = store
+        self.provider = provider
+    }
+
+    /// Returns the surrounding text allowed by the current preferences, or `nil` when
+    /// capture or post-processing is disabled, the app is excluded, or nothing could be
+    /// read.
+    func capture() -> String? {
+        let settings = store.settings
+        guard settings.contextCaptureEnabled, settings.enablePostProcessing,
+              !settings.incognitoModeEnabled else { return nil }
+
+        let bundleId = provider.frontmostBundleIdentifier()
+        if let bundleId, settings.contextCaptureExcludedApps.contains(bundleId) {
+            Logger.shared.info("ContextCaptureService: Skipped — '\(bundleId)' is excluded")
+            return nil
+        }
+
+        let limit = min(max(settings.contextCaptureCharacterLimit, Self.characterLimitRange.lowerBound),
+                        Self.characterLimitRange.upperBound)
+        guard let text = provider.textBeforeInsertionPoint(maxLength: limit) else { return nil }
+        let trimmed = String(text.suffix(limit)).trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !trimmed.isEmpty else { return nil }
+
+        Logger.shared.info("ContextCaptureService: Captured \(trimmed.count) char(s) from '\(bundleId ?? "unknown")'")
+        return trimmed
+    }
+
+    /// Returns the selected text when selection rewriting is on and allowed for the
+    /// frontmost app, or `nil` when nothing but whitespace is selected.
+    func captureSelection() -> String? {
+        let settings = store.settings
+        guard settings.selectionRewriteEnabled, !settings.incognitoModeEnabled else { return nil }
+
+        let bundleId = provider.frontmostBundleIdentifier()
+        if let bundleId, settings.contextCaptureExcludedApps.contains(bundleId) { return nil }
+
+        guard let selection = provider.selectedText(),
+              !selection.trimmingCharacters(in: .whitespacesAndNewlines).isEmpty else { return nil }
+        guard selection.count <= SelectionRewriter.maxSelectionLength else {
+            Logger.shared.info("ContextCaptureService: Selection of \(selection.count) chars is too long to rewrite")
+            return nil
+        }
+
+        Logger.shared.info("ContextCaptureService: Captured \(selection.count)-char selection from '\(bundleId ?? "unknown")'")
+        return selection
+    }
+}
+
+// MARK: - AccessibilityFocusedTextProvider
+
+/// Reads the focused element's value and selection through the Accessibility API.
+/// Returns `nil` when the process is not trusted or the element exposes no text.
+struct AccessibilityFocusedTextProvider: FocusedTextProvider {
+
+    func frontmostBundleIdentifier() -> String? {
+        NSWorkspace.shared.frontmostApplication?.bundleIdentifier
+    }
+
+    func textBeforeInsertionPoint(maxLength: Int) -> String? {
+        guard let element = focusedElement() else { return nil }
+
+        var value: CFTypeRef?
+        guard AXUIElementCopyAttributeValue(element, kAXValueAttribute as CFString, &value) == .success,
+              let text = value as? String else {
+            return nil
+        }
+
+        // Without a selection range, fall back to the end of the field.
+        var location = (text as NSString).length
+        var rangeValue: CFTypeRef?
+        if AXUIElementCopyAttributeValue(element, kAXSelectedTextRangeAttribute as CFString, &rangeValue) == .success,
+           let rangeValue, CFGetTypeID(rangeValue) == AXValueGetTypeID() {
+            var range = CFRange()
+            if AXValueGetValue(rangeValue as! AXValue, .cfRange, &range) {
+                location = min(max(range.location, 0), location)
+            }
+        }
+
+        let prefix = (text as NSString).substring(to: location)
+        return String(prefix.suffix(maxLength))
+    }
+
+    func selectedText() -> String? {
+        guard let element = focusedElement() else { return nil }
+        var value: CFTypeRef?
+        guard AXUIElementCopyAttributeValue(element, kAXSelectedTextAttribute as CFString, &value) == .success,
+              let text = value as? String, !text.isEmpty else {
+            return nil
+        }
+        return text
+    }
+
+    private func focusedElement() -> AXUIElement? {
+        guard AXIsProcessTrusted() else { return nil }
+
+        let systemWide = AXUIElementCreateSystemWide()
+        var focused: CFTypeRef?
+        guard AXUIElementCopyAttributeValue(systemWide, kAXFocusedUIElementAttribute as CFString, &focused) == .success,
+              let focused, CFGetTypeID(focused) == AXUIElementGetTypeID() else {
+            return nil
+        }
+        return (focused as! AXUIElement)
+    }
+}
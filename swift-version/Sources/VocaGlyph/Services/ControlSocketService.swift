@@ -0,0 +1,435 @@
+import Foundation
+import Darwin
+import Security
+
+// MARK: - Control Protocol
+
+/// One JSON object per connection, terminated by a newline:
+///
+///     {"token": "…", "command": "switch-model", "value": "parakeet-v3"}
+///
+/// answered with `{"ok": true, "status": {…}}` or `{"ok": false, "error": "…"}`.
+/// `metrics` also returns `"metrics": {…}`, a `MetricsSnapshot`. `replay` dictates the
+/// WAV file at `value` as if it had just been recorded.
+///
+/// The commands below are the stable surface for launcher extensions (Raycast, Alfred);
+/// `Scripts/vocaglyph-control` is a reference client:
+///
+///     dictate-to-clipboard            starts a dictation that is copied, not pasted;
+///                                     sent again while recording, stops it
+///     transcribe-file  value: path    transcribes a WAV file and returns
+///                                     `"text": "…"`; nothing is pasted or recorded
+///     toggle-pause                    pauses or resumes dictation (between recordings)
+///     switch-profile   value: name    makes the AI post-processing template with
+///                                     that name (or id) the active one
+///
+/// For hardware keys (a Stream Deck plugin), `key-down` and `key-up` report a key
+/// press: a tap toggles recording, a hold is push-to-talk (see `PushToTalkKey`).
+/// `subscribe` answers like `status` and then keeps the connection open, writing a
+/// `ControlEvent` line whenever the state changes, so the key can show it.
+enum ControlCommand: String, CaseIterable {
+    case start
+    case stop
+    case toggle
+    case status
+    case pasteLast = "paste-last"
+    case switchModel = "switch-model"
+    case switchLanguage = "switch-language"
+    case metrics
+    case replay
+    case dictateToClipboard = "dictate-to-clipboard"
+    case transcribeFile = "transcribe-file"
+    case togglePause = "toggle-pause"
+    case switchProfile = "switch-profile"
+    case keyDown = "key-down"
+    case keyUp = "key-up"
+    case subscribe
+}
+
+struct ControlRequest: Decodable {
+    let token: String?
+    let command: String
+    let value: String?
+
+    /// `value` without surrounding whitespace, or `nil` when that leaves nothing.
+    var trimmedValue: String? {
+        guard let value = value?.trimmingCharacters(in: .whitespacesAndNewlines), !value.isEmpty else { return nil }
+        return value
+    }
+}
+
+struct ControlStatus: Codable, Equatable {
+    let state: String
+    let model: String
+    let language: String
+    let paused: Bool
+    /// `RuntimeStatus.name`, e.g. "model-missing" or "downloading".
+    var activity = RuntimeStatus.ready.name
+    /// `RuntimeStatus.message`, ready to show as is.
+    var message = RuntimeStatus.ready.message
+    /// Download progress (0–100) while `activity` is "downloading".
+    var progress: Int? = nil
+    /// Name of the active AI post-processing template, for `switch-profile`.
+    var profile: String? = nil
+}
+
+struct ControlResponse: Codable, Equatable {
+    var ok: Bool
+    var error: String?
+    var status: ControlStatus?
+    var metrics: MetricsSnapshot?
+    /// The transcript returned by `transcribe-file`.
+    var text: String?
+
+    static func failure(_ message: String) -> ControlResponse {
+        ControlResponse(ok: false, error: message)
+    }
+}
+
+// MARK: - ControlEvent
+
+/// A state change pushed to `subscribe` connections, one JSON line each:
+///
+///     {"event":"state","icon":"recording","state":"recording","title":"REC"}
+struct ControlEvent: Codable, Equatable {
+    var event = "state"
+    /// `AppState.name`.
+    let state: String
+    /// `TrayIcon` name; a Stream Deck plugin ships one image per icon.
+    let icon: String
+    /// Short label for the key, e.g. "REC"; `nil` when there is nothing to add.
+    let title: String?
+
+    init(state: AppState, icon: TrayIcon) {
+        self.state = state.name
+        self.icon = icon.rawValue
+        title = icon.dockBadge
+    }
+}
+
+// MARK: - ControlAPITarget
+
+/// What the control API can do to the app; `AppDelegate` implements this.
+protocol ControlAPITarget: URLSchemeHandling {
+    @MainActor func controlStatus() -> ControlStatus
+    /// Returns the validation message when the change is rejected.
+    @MainActor func switchModel(to model: String) -> String?
+    @MainActor func switchLanguage(to language: String) -> String?
+    @MainActor func controlMetrics() -> MetricsSnapshot
+    /// Starts a dictation of the WAV file at `path`. Returns why it can't.
+    @MainActor func replay(fileAt path: String) -> String?
+    /// Starts a clipboard-only dictation, or stops the recording in progress.
+    @MainActor func dictateToClipboard()
+    /// Returns why dictation can't be paused or resumed now.
+    @MainActor func togglePause() -> String?
+    /// Returns why the profile can't be switched, e.g. an unknown name.
+    @MainActor func switchProfile(to profile: String) -> String?
+    /// The raw transcript of the WAV file at `path`.
+    @MainActor func transcribeFile(atPath path: String) async throws -> String
+    /// A hardware key went down (`pressed`) or up.
+    @MainActor func handleKey(pressed: Bool)
+}
+
+// MARK: - ControlSocketService
+
+enum ControlSocketError: LocalizedError {
+    case pathTooLong(String)
+    case systemCall(String, reason: String)
+
+    /// Captures `errno` for the call that just failed.
+    static func failed(_ call: String) -> ControlSocketError {
+        .systemCall(call, reason: String(cString: strerror(errno)))
+    }
+
+    var errorDescription: String? {
+        switch self {
+        case .pathTooLong(let path): return "Socket path is too long: \(path)"
+        case .systemCall(let call, let reason): return "\(call) failed: \(reason)"
+        }
+    }
+}
+
+/// Optional local JSON API on a Unix domain socket, so Stream Deck buttons, foot pedal
+/// daemons and editor plugins can drive dictation and read its state.
+///
+/// The socket and a token file live next to each other in Application Support, both
+/// readable by the current user only (0600). Every request must carry the token; it is
+/// generated on first start and kept across launches.
+///
+///     TOKEN=$(cat ~/Library/Application\ Support/VocaGlyph/control-token)
+///     echo "{\"token\":\"$TOKEN\",\"command\":\"toggle\"}" | nc -U ~/Library/Application\ Support/VocaGlyph/control.sock
+///
+/// Where that path would be too long for a socket — always the case in the App Sandbox —
+/// the socket moves to `$TMPDIR` (`AppDirs.sockets`); Settings shows where it is.
+final class ControlSocketService {
+
+    static let socketName = "control.sock"
+    static let tokenName = "control-token"
+    static let maxRequestBytes = 64 * 1024
+    /// How long a client may take to send its request line before it is dropped.
+    static let requestTimeout: TimeInterval = 10
+
+    let socketURL: URL
+    let tokenURL: URL
+    private weak var target: ControlAPITarget?
+    private let queue = DispatchQueue(label: "com.vocaglyph.control-socket")
+    private var listener: Int32 = -1
+    private var source: DispatchSourceRead?
+    /// Open `subscribe` connections. Only touched on `queue`.
+    private var subscribers: [Int32] = []
+
+    /// `directory` defaults to `AppDirs.current.root`, and `AppDirs.current.sockets` for
+    /// the socket.
+    init(directory: URL? = nil, target: ControlAPITarget) {
+        let root = directory ?? AppDirs.current.root
+        socketURL = (directory ?? AppDirs.current.sockets).appendingPathComponent(Self.socketName)
+        tokenURL = root.appendingPathComponent(Self.tokenName)
+        self.target = target
+    }
+
+    deinit {
+        stop()
+        // Every block on `queue` holds `self` weakly, so none can be using these now.
+        subscribers.forEach { close($0) }
+    }
+
+    var isRunning: Bool { source != nil }
+
+    func start() throws {
+        guard !isRunning else { return }
+        try FileManager.default.createDirectory(at: socketURL.deletingLastPathComponent(), withIntermediateDirectories: true)
+        _ = try loadOrCreateToken()
+
+        let path = socketURL.path
+        var address = sockaddr_un()
+        guard path.utf8.count < MemoryLayout.size(ofValue: address.sun_path) else {
+            throw ControlSocketError.pathTooLong(path)
+        }
+        address.sun_family = sa_family_t(AF_UNIX)
+        withUnsafeMutableBytes(of: &address.sun_path) { buffer in
+            buffer.copyBytes(from: path.utf8)
+        }
+
+        let fd = socket(AF_UNIX, SOCK_STREAM, 0)
+        guard fd >= 0 else { throw ControlSocketError.failed("socket") }
+        unlink(path) // A stale socket from a previous run.
+        let bound = withUnsafePointer(to: &address) {
+            $0.withMemoryRebound(to: sockaddr.self, capacity: 1) {
+                bind(fd, $0, socklen_t(MemoryLayout<sockaddr_un>.size))
+            }
+        }
+        var failure: ControlSocketError?
+        if bound != 0 {
+            failure = .failed("bind")
+        } else if chmod(path, 0o600) != 0 {
+            failure = .failed("chmod")
+        } else if listen(fd, 8) != 0 {
+            failure = .failed("listen")
+        }
+        if let failure {
+            close(fd)
+            throw failure
+        }
+
+        listener = fd
+        let source = DispatchSource.makeReadSource(fileDescriptor: fd, queue: queue)
+        source.setEventHandler { [weak self] in self?.acceptClient() }
+        source.resume()
+        self.source = source
+        Logger.shared.info("ControlSocketService: Listening on \(path)")
+    }
+
+    func stop() {
+        guard let source else { return }
+        source.cancel()
+        self.source = nil
+        close(listener)
+        listener = -1
+        unlink(socketURL.path)
+        // Async: waiting here would stall the main thread behind a slow `publish`.
+        queue.async { [weak self] in
+            guard let self else { return }
+            self.subscribers.forEach { close($0) }
+            self.subscribers.removeAll()
+        }
+        Logger.shared.info("ControlSocketService: Stopped")
+    }
+
+    var subscriberCount: Int { queue.sync { subscribers.count } }
+
+    /// Writes `event` to every `subscribe` connection; closed ones are dropped.
+    func publish(_ event: ControlEvent) {
+        queue.async { [weak self] in
+            guard let self, !self.subscribers.isEmpty,
+                  var data = try? JSONEncoder.control.encode(event) else { return }
+            data.append(UInt8(ascii: "\n"))
+            self.subscribers.removeAll { client in
+                let written = data.withUnsafeBytes { write(client, $0.baseAddress, $0.count) }
+                guard written != data.count else { return false }
+                close(client)
+                return true
+            }
+        }
+    }
+
+    /// The token clients must send, created with 32 random bytes on first use.
+    func loadOrCreateToken() throws -> String {
+        try Self.loadOrCreateToken(at: tokenURL)
+    }
+
+    /// The token in `tokenURL`, created there first if needed. `TranscriptionServer`
+    /// shares it, so one token file covers both sockets.
+    static func loadOrCreateToken(at tokenURL: URL) throws -> String {
+        if let existing = try? String(contentsOf: tokenURL, encoding: .utf8)
+            .trimmingCharacters(in: .whitespacesAndNewlines), !existing.isEmpty {
+            return existing
+        }
+        var bytes = [UInt8](repeating: 0, count: 32)
+        guard SecRandomCopyBytes(kSecRandomDefault, bytes.count, &bytes) == errSecSuccess else {
+            throw ControlSocketError.systemCall("SecRandomCopyBytes", reason: "no random bytes")
+        }
+        let token = bytes.map { String(format: "%02x", $0) }.joined()
+        try token.write(to: tokenURL, atomically: true, encoding: .utf8)
+        try FileManager.default.setAttributes([.posixPermissions: 0o600], ofItemAtPath: tokenURL.path)
+        return token
+    }
+
+    // MARK: - Connections
+
+    private func acceptClient() {
+        let client = accept(listener, nil, nil)
+        guard client >= 0 else { return }
+        // A subscriber that went away must fail the write, not kill the app, and one
+        // that stopped reading must not hold up `queue` for long.
+        var noSigPipe: Int32 = 1
+        setsockopt(client, SOL_SOCKET, SO_NOSIGPIPE, &noSigPipe, socklen_t(MemoryLayout<Int32>.size))
+        var sendTimeout = timeval(tv_sec: 2, tv_usec: 0)
+        setsockopt(client, SOL_SOCKET, SO_SNDTIMEO, &sendTimeout, socklen_t(MemoryLayout<timeval>.size))
+
+        // Read off `queue`: a client that never finishes its line must not hold up other
+        // connections or `publish`.
+        let tokenURL = tokenURL
+        let queue = queue
+        DispatchQueue.global(qos: .userInitiated).async { [weak self, weak target] in
+            let request = Self.readRequest(from: client, maxBytes: Self.maxRequestBytes, timeout: Self.requestTimeout) {
+                $0.contains(UInt8(ascii: "\n"))
+            }
+            let token = (try? Self.loadOrCreateToken(at: tokenURL)) ?? ""
+            let subscribes = (try? JSONDecoder().decode(ControlRequest.self, from: request))?.command
+                == ControlCommand.subscribe.rawValue
+            Task { @MainActor [weak self, weak target] in
+                let response = await Self.response(to: request, token: token, target: target)
+                queue.async {
+                    var data = (try? JSONEncoder.control.encode(response)) ?? Data()
+                    data.append(UInt8(ascii: "\n"))
+                    data.withUnsafeBytes { _ = write(client, $0.baseAddress, $0.count) }
+                    if subscribes && response.ok, let self, self.isRunning {
+                        self.subscribers.append(client)
+                    } else {
+                        close(client)
+                    }
+                }
+            }
+        }
+    }
+
+    /// Reads from `client` until `isComplete` holds for the chunk just read, `maxBytes`
+    /// have arrived, the client closes, or `timeout` seconds have passed. Blocks, so call
+    /// it off the accept queue. `TranscriptionServer` reads its requests the same way.
+    static func readRequest(from client: Int32, maxBytes: Int, timeout: TimeInterval,
+                            isComplete: (ArraySlice<UInt8>) -> Bool) -> Data {
+        // Bounds each read(); the deadline bounds a client that trickles bytes.
+        let seconds = max(1, Int(timeout.rounded(.up)))
+        var receiveTimeout = timeval(tv_sec: seconds, tv_usec: 0)
+        setsockopt(client, SOL_SOCKET, SO_RCVTIMEO, &receiveTimeout, socklen_t(MemoryLayout<timeval>.size))
+        let deadline = Date().addingTimeInterval(timeout)
+
+        var request = Data()
+        var buffer = [UInt8](repeating: 0, count: 64 * 1024)
+        while request.count < maxBytes, Date() < deadline {
+            let count = read(client, &buffer, min(buffer.count, maxBytes - request.count))
+            guard count > 0 else { break }
+            request.append(buffer, count: count)
+            if isComplete(buffer[..<count]) { break }
+        }
+        return request
+    }
+
+    // MARK: - Requests
+
+    /// `respond(to:token:target:)`, followed by the part of `transcribe-file` that waits
+    /// for the engine. This is what clients get.
+    @MainActor
+    static func response(to data: Data, token: String, target: ControlAPITarget?) async -> ControlResponse {
+        var response = respond(to: data, token: token, target: target)
+        guard response.ok, let target,
+              let request = try? JSONDecoder().decode(ControlRequest.self, from: data),
+              request.command == ControlCommand.transcribeFile.rawValue,
+              let path = request.trimmedValue else { return response }
+        do {
+            response.text = try await target.transcribeFile(atPath: path)
+        } catch {
+            return .failure(error.localizedDescription)
+        }
+        return response
+    }
+
+    /// Decodes, authenticates and runs one request. `transcribe-file` is only checked for
+    /// a value here; `response(to:token:target:)` runs it.
+    @MainActor
+    static func respond(to data: Data, token: String, target: ControlAPITarget?) -> ControlResponse {
+        guard let request = try? JSONDecoder().decode(ControlRequest.self, from: data) else {
+            return .failure("Invalid request: expected a JSON object with \"token\" and \"command\".")
+        }
+        guard !token.isEmpty, request.token == token else {
+            Logger.shared.error("ControlSocketService: Rejected request with a missing or wrong token")
+            return .failure("Unauthorized.")
+        }
+        guard let command = ControlCommand(rawValue: request.command) else {
+            let known = ControlCommand.allCases.map(\.rawValue).joined(separator: ", ")
+            return .failure("Unknown command '\(request.command)'. Known commands: \(known).")
+        }
+        guard let target else { return .failure("VocaGlyph is not ready.") }
+        Logger.shared.info("ControlSocketService: Received '\(command.rawValue)'")
+
+        switch command {
+        case .start:  target.handleTrigger(.start)
+        case .stop:   target.handleTrigger(.stop)
+        case .toggle: target.handleTrigger(.toggle)
+        case .status: break
+        case .metrics:
+            return ControlResponse(ok: true, status: target.controlStatus(), metrics: target.controlMetrics())
+        case .pasteLast:
+            guard target.pasteLastTranscription() else { return .failure("Nothing to paste.") }
+        case .dictateToClipboard:
+            target.dictateToClipboard()
+        case .togglePause:
+            if let rejection = target.togglePause() { return .failure(rejection) }
+        case .keyDown: target.handleKey(pressed: true)
+        case .keyUp:   target.handleKey(pressed: false)
+        case .subscribe: break
+        case .switchModel, .switchLanguage, .replay, .switchProfile, .transcribeFile:
+            guard let value = request.trimmedValue else {
+                return .failure("'\(command.rawValue)' needs a \"value\".")
+            }
+            let rejection: String?
+            switch command {
+            case .switchModel:    rejection = target.switchModel(to: value)
+            case .switchLanguage: rejection = target.switchLanguage(to: value)
+            case .switchProfile:  rejection = target.switchProfile(to: value)
+            case .replay:         rejection = target.replay(fileAt: value)
+            default:              rejection = nil
+            }
+            if let rejection { return .failure(rejection) }
+        }
+        return ControlResponse(ok: true, status: target.controlStatus())
+    }
+}
+
+private extension JSONEncoder {
+    static let control: JSONEncoder = {
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = .sortedKeys
+        return encoder
+    }()
+}
@@ -0,0 +1,172 @@
+import Foundation
+import Darwin
+
+// MARK: - CrashReport
+
+/// A crash written by `CrashReporter` on a previous run.
+struct CrashReport: Equatable {
+    let url: URL
+    let contents: String
+
+    var date: Date? {
+        (try? url.resourceValues(forKeys: [.creationDateKey]))?.creationDate
+    }
+
+    /// First line of the report, e.g. "Signal 6 (SIGABRT)".
+    var summary: String {
+        contents.split(separator: "\n", maxSplits: 1).first.map(String.init) ?? "Unknown crash"
+    }
+}
+
+// MARK: - CrashReporter
+
+/// Opt-in crash capture. With `crashReportingEnabled` on, fatal signals (SIGABRT from a
+/// native engine tearing down its Metal context, SIGSEGV, SIGBUS, SIGILL, SIGTRAP) and
+/// uncaught Objective-C exceptions are written with a symbolized stack to
+/// `~/Library/Logs/VocaGlyph/CrashReports/`.
+///
+/// Nothing leaves the Mac by itself: on the next launch the user is asked whether to
+/// send the report, which opens a pre-filled GitHub issue they can review first.
+///
+/// The signal handler only uses async-signal-safe calls (`open`, `write`,
+/// `backtrace_symbols_fd`), so everything it writes is prepared in `install()`.
+enum CrashReporter {
+
+    static let fatalSignals: [Int32] = [SIGABRT, SIGSEGV, SIGBUS, SIGILL, SIGTRAP, SIGFPE]
+    static let issuesURL = "https://github.com/nkristianto/VocaGlyph/issues/new"
+    /// Longest report body put in the issue URL; GitHub rejects very long URLs.
+    static let maxIssueBodyLength = 6000
+
+    static let directory: URL = AppDirs.current.logs
+        .appendingPathComponent("CrashReports", isDirectory: true)
+
+    private static var isInstalled = false
+
+    /// Installs the handlers. Call once, early in launch, and only when the user opted in.
+    static func install(directory: URL = directory) {
+        guard !isInstalled else { return }
+        isInstalled = true
+        try? FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+
+        let path = directory.appendingPathComponent("crash-\(Int(Date().timeIntervalSince1970)).crash").path
+        signalReportPath = strdup(path)
+        let header = environmentHeader()
+        signalHeader = strdup(header)
+        signalHeaderLength = header.utf8.count
+        signalFrames = .allocate(capacity: signalFrameCount)
+
+        NSSetUncaughtExceptionHandler { exception in
+            CrashReporter.write(CrashReporter.exceptionReport(exception), to: CrashReporter.directory)
+            // The abort() that follows shouldn't write a second, less useful report.
+            signalReportPath = nil
+        }
+        for signal in fatalSignals {
+            Darwin.signal(signal, crashSignalHandler)
+        }
+        Logger.shared.info("CrashReporter: Installed — reports go to \(directory.path)")
+    }
+
+    /// Restores the default handlers when the user opts out mid-session.
+    static func uninstall() {
+        guard isInstalled else { return }
+        isInstalled = false
+        NSSetUncaughtExceptionHandler(nil)
+        for signal in fatalSignals {
+            Darwin.signal(signal, SIG_DFL)
+        }
+        Logger.shared.info("CrashReporter: Uninstalled")
+    }
+
+    // MARK: - Reports
+
+    static func pendingReports(in directory: URL = directory) -> [CrashReport] {
+        let files = (try? FileManager.default.contentsOfDirectory(
+            at: directory, includingPropertiesForKeys: [.creationDateKey])) ?? []
+        return files
+            .filter { $0.pathExtension == "crash" }
+            .sorted { $0.lastPathComponent < $1.lastPathComponent }
+            .compactMap { url in
+                guard let contents = try? String(contentsOf: url, encoding: .utf8), !contents.isEmpty else { return nil }
+                return CrashReport(url: url, contents: contents)
+            }
+    }
+
+    static func discard(_ reports: [CrashReport]) {
+        reports.forEach { try? FileManager.default.removeItem(at: $0.url) }
+    }
+
+    /// A "new issue" URL with the report in a code block, cut to `maxIssueBodyLength`.
+    static func issueURL(for report: CrashReport) -> URL? {
+        var stack = report.contents
+        if stack.count > maxIssueBodyLength {
+            stack = String(stack.prefix(maxIssueBodyLength)) + "\n… (truncated)"
+        }
+        var components = URLComponents(string: issuesURL)
+        components?.queryItems = [
+            URLQueryItem(name: "title", value: "Crash: \(report.summary)"),
+            URLQueryItem(name: "body", value: "What were you doing when VocaGlyph quit?\n\n\n```\n\(stack)\n```"),
+        ]
+        return components?.url
+    }
+
+    // MARK: - Writing
+
+    static func exceptionReport(_ exception: NSException) -> String {
+        ["Uncaught exception \(exception.name.rawValue): \(exception.reason ?? "no reason")",
+         environmentHeader(),
+         exception.callStackSymbols.joined(separator: "\n")].joined(separator: "\n")
+    }
+
+    static func write(_ report: String, to directory: URL) {
+        try? FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        let url = directory.appendingPathComponent("crash-\(Int(Date().timeIntervalSince1970))-exception.crash")
+        try? report.write(to: url, atomically: true, encoding: .utf8)
+    }
+
+    private static func environmentHeader() -> String {
+        let info = Bundle.main.infoDictionary
+        let version = info?["CFBundleShortVersionString"] as? String ?? "?"
+        let build = info?["CFBundleVersion"] as? String ?? "?"
+        return "VocaGlyph \(version) (\(build)), macOS \(ProcessInfo.processInfo.operatingSystemVersionString)\n"
+    }
+}
+
+// MARK: - Signal Handler
+
+// Prepared by `install()`; the handler can't allocate or touch Swift objects.
+private var signalReportPath: UnsafeMutablePointer<CChar>?
+private var signalHeader: UnsafeMutablePointer<CChar>?
+private var signalHeaderLength = 0
+private var signalFrames: UnsafeMutablePointer<UnsafeMutableRawPointer?>?
+private let signalFrameCount: Int32 = 128
+
+private func crashSignalHandler(_ signal: Int32) {
+    if let path = signalReportPath {
+        let fd = open(path, O_WRONLY | O_CREAT | O_TRUNC, 0o644)
+        if fd >= 0 {
+            writeSignalName(signal, to: fd)
+            if let header = signalHeader { _ = write(fd, header, signalHeaderLength) }
+            if let frames = signalFrames {
+                backtrace_symbols_fd(frames, backtrace(frames, signalFrameCount), fd)
+            }
+            close(fd)
+        }
+    }
+    // Let the default action run so macOS still writes its own report.
+    Darwin.signal(signal, SIG_DFL)
+    raise(signal)
+}
+
+private func writeSignalName(_ signal: Int32, to fd: Int32) {
+    let line: StaticString
+    switch signal {
+    case SIGABRT: line = "Signal 6 (SIGABRT)\n"
+    case SIGSEGV: line = "Signal 11 (SIGSEGV)\n"
+    case SIGBUS:  line = "Signal 10 (SIGBUS)\n"
+    case SIGILL:  line = "Signal 4 (SIGILL)\n"
+    case SIGTRAP: line = "Signal 5 (SIGTRAP)\n"
+    case SIGFPE:  line = "Signal 8 (SIGFPE)\n"
+    default:      line = "Fatal signal\n"
+    }
+    _ = write(fd, line.utf8Start, line.utf8CodeUnitCount)
+}
@@ -0,0 +1,303 @@
+import Foundation
+import Darwin
+
+// MARK: - DebugServer
+
+/// Profiling endpoints on `127.0.0.1`, started only with the `--debug` launch flag, for
+/// chasing leaked threads (hotkey re-registration, audio readers) and CPU hotspots on a
+/// user's Mac without attaching a debugger:
+///
+///     open -a VocaGlyph --args --debug
+///     curl localhost:6060/debug/threads
+///     curl 'localhost:6060/debug/profile?seconds=10' > profile.txt
+///
+/// | Path                       | Response                                                 |
+/// |----------------------------|----------------------------------------------------------|
+/// | `/debug/threads`           | Every thread with its name, state and CPU time            |
+/// | `/debug/profile?seconds=N` | `sample` call trees of all threads over N seconds (≤ 60) |
+/// | `/debug/heap`              | `heap` object counts by class                             |
+/// | `/debug/leaks`             | `leaks` report                                            |
+/// | `/debug/resources`         | `ResourceUsage` as JSON                                   |
+///
+/// `heap` and `leaks` need a debuggable build; hardened release builds refuse them.
+///
+/// `leaks` output can include heap contents such as transcripts, so requests whose `Host`
+/// isn't this server are refused: a web page that rebinds its own host name to
+/// 127.0.0.1 could otherwise read them.
+final class DebugServer {
+
+    enum Endpoint: Equatable {
+        case index
+        case threads
+        case profile(seconds: Int)
+        case heap
+        case leaks
+        case resources
+    }
+
+    struct ThreadSummary: Equatable {
+        var name: String
+        /// "running", "waiting", … from `thread_basic_info.run_state`.
+        var state: String
+        var cpuSeconds: Double
+        /// Recent CPU use, 0–100.
+        var cpuPercent: Double
+    }
+
+    static let defaultPort: UInt16 = 6060
+    static let profileSecondsRange = 1...60
+    static let defaultProfileSeconds = 5
+    private static let maxRequestBytes = 8 * 1024
+    /// Seconds a client gets to send its request headers.
+    static let requestTimeout: TimeInterval = 5
+
+    let port: UInt16
+    private let resources: @MainActor () -> ResourceUsage
+    private let acceptQueue = DispatchQueue(label: "com.vocaglyph.debug-server")
+    private var listener: Int32 = -1
+    private var source: DispatchSourceRead?
+
+    init(port: UInt16 = DebugServer.defaultPort, resources: @escaping @MainActor () -> ResourceUsage) {
+        self.port = port
+        self.resources = resources
+    }
+
+    deinit {
+        stop()
+    }
+
+    var isRunning: Bool { source != nil }
+
+    func start() throws {
+        guard !isRunning else { return }
+        let fd = socket(AF_INET, SOCK_STREAM, 0)
+        guard fd >= 0 else { throw ControlSocketError.failed("socket") }
+        var reuse: Int32 = 1
+        setsockopt(fd, SOL_SOCKET, SO_REUSEADDR, &reuse, socklen_t(MemoryLayout<Int32>.size))
+
+        var address = sockaddr_in()
+        address.sin_len = UInt8(MemoryLayout<sockaddr_in>.size)
+        address.sin_family = sa_family_t(AF_INET)
+        address.sin_port = port.bigEndian
+        address.sin_addr.s_addr = inet_addr("127.0.0.1")
+        let bound = withUnsafePointer(to: &address) {
+            $0.withMemoryRebound(to: sockaddr.self, capacity: 1) {
+                bind(fd, $0, socklen_t(MemoryLayout<sockaddr_in>.size))
+            }
+        }
+        var failure: ControlSocketError?
+        if bound != 0 {
+            failure = .failed("bind")
+        } else if listen(fd, 8) != 0 {
+            failure = .failed("listen")
+        }
+        if let failure {
+            close(fd)
+            throw failure
+        }
+
+        listener = fd
+        let source = DispatchSource.makeReadSource(fileDescriptor: fd, queue: acceptQueue)
+        source.setEventHandler { [weak self] in self?.acceptClient() }
+        source.resume()
+        self.source = source
+        Logger.shared.info("DebugServer: Serving http://127.0.0.1:\(port)/debug/")
+    }
+
+    func stop() {
+        guard let source else { return }
+        source.cancel()
+        self.source = nil
+        close(listener)
+        listener = -1
+        Logger.shared.info("DebugServer: Stopped")
+    }
+
+    // MARK: - Connections
+
+    private func acceptClient() {
+        let client = accept(listener, nil, nil)
+        guard client >= 0 else { return }
+        // A client that went away must fail the write, not kill the app.
+        var noSigPipe: Int32 = 1
+        setsockopt(client, SOL_SOCKET, SO_NOSIGPIPE, &noSigPipe, socklen_t(MemoryLayout<Int32>.size))
+        // A client that never sends its request must not hold a thread forever.
+        var receiveTimeout = timeval(tv_sec: Int(Self.requestTimeout), tv_usec: 0)
+        setsockopt(client, SOL_SOCKET, SO_RCVTIMEO, &receiveTimeout, socklen_t(MemoryLayout<timeval>.size))
+
+        let port = port
+        // A profile takes seconds; don't hold up other requests.
+        DispatchQueue.global(qos: .utility).async { [weak self] in
+            guard let self else { close(client); return }
+            var request = Data()
+            var buffer = [UInt8](repeating: 0, count: 4096)
+            let deadline = Date().addingTimeInterval(Self.requestTimeout)
+            while request.count < Self.maxRequestBytes, Date() < deadline {
+                let count = read(client, &buffer, buffer.count)
+                guard count > 0 else { break }
+                request.append(buffer, count: count)
+                if request.range(of: Data("\r\n\r\n".utf8)) != nil { break }
+            }
+            let text = String(decoding: request, as: UTF8.self)
+            let (status, contentType, body) = Self.isAllowedHost(inRequest: text, port: port)
+                ? self.respond(to: Self.endpoint(forRequestLine: text.components(separatedBy: "\r\n").first ?? ""))
+                : ("403 Forbidden", "text/plain; charset=utf-8", Data("Forbidden.\n".utf8))
+            var response = Data("HTTP/1.1 \(status)\r\nContent-Type: \(contentType)\r\nContent-Length: \(body.count)\r\nConnection: close\r\n\r\n".utf8)
+            response.append(body)
+            response.withUnsafeBytes { _ = write(client, $0.baseAddress, $0.count) }
+            close(client)
+        }
+    }
+
+    private func respond(to endpoint: Endpoint?) -> (status: String, contentType: String, body: Data) {
+        let text = "text/plain; charset=utf-8"
+        guard let endpoint else {
+            return ("404 Not Found", text, Data("Not found. See /debug/ for the endpoints.\n".utf8))
+        }
+        Logger.shared.info("DebugServer: Serving \(endpoint)")
+        let pid = String(ProcessInfo.processInfo.processIdentifier)
+        switch endpoint {
+        case .index:
+            return ("200 OK", text, Data(Self.index.utf8))
+        case .threads:
+            return ("200 OK", text, Data(Self.format(Self.threads()).utf8))
+        case .profile(let seconds):
+            return ("200 OK", text, Data(Self.sample(pid: pid, seconds: seconds).utf8))
+        case .heap:
+            return ("200 OK", text, Data(Self.run("/usr/bin/heap", [pid]).utf8))
+        case .leaks:
+            return ("200 OK", text, Data(Self.run("/usr/bin/leaks", [pid]).utf8))
+        case .resources:
+            let usage = DispatchQueue.main.sync { MainActor.assumeIsolated { resources() } }
+            let encoder = JSONEncoder()
+            encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
+            return ("200 OK", "application/json", (try? encoder.encode(usage)) ?? Data())
+        }
+    }
+
+    private static let index = """
+        VocaGlyph debug endpoints
+          /debug/threads            threads with name, state and CPU time
+          /debug/profile?seconds=N  call trees of all threads over N seconds (1-60, default 5)
+          /debug/heap               heap object counts by class
+          /debug/leaks              leaked allocations
+          /debug/resources          memory usage as JSON
+
+        """
+
+    // MARK: - Routing
+
+    /// The endpoint for an HTTP request line such as `GET /debug/threads HTTP/1.1`.
+    static func endpoint(forRequestLine line: String) -> Endpoint? {
+        let parts = line.split(separator: " ")
+        guard parts.count >= 2, parts[0] == "GET",
+              let components = URLComponents(string: String(parts[1])) else { return nil }
+        switch components.path {
+        case "/", "/debug", "/debug/": return .index
+        case "/debug/threads":         return .threads
+        case "/debug/heap":            return .heap
+        case "/debug/leaks":           return .leaks
+        case "/debug/resources":       return .resources
+        case "/debug/profile":
+            let requested = components.queryItems?.first { $0.name == "seconds" }?.value
+                .flatMap { Int($0) } ?? defaultProfileSeconds
+            return .profile(seconds: min(max(requested, profileSecondsRange.lowerBound), profileSecondsRange.upperBound))
+        default:
+            return nil
+        }
+    }
+
+    /// Whether the `Host` header of `request` names this server. Requests without one,
+    /// or naming any other host, are refused.
+    static func isAllowedHost(inRequest request: String, port: UInt16) -> Bool {
+        for line in request.components(separatedBy: "\r\n").dropFirst() {
+            guard let colon = line.firstIndex(of: ":"), line[..<colon].lowercased() == "host" else { continue }
+            let host = line[line.index(after: colon)...].trimmingCharacters(in: .whitespaces).lowercased()
+            let allowed = ["127.0.0.1:\(port)", "localhost:\(port)"].contains(host)
+            if !allowed { Logger.shared.error("DebugServer: Refused a request for host '\(host)'") }
+            return allowed
+        }
+        return false
+    }
+
+    // MARK: - Threads
+
+    /// Every thread of this process, from `task_threads`.
+    static func threads() -> [ThreadSummary] {
+        var list: thread_act_array_t?
+        var count: mach_msg_type_number_t = 0
+        guard task_threads(mach_task_self_, &list, &count) == KERN_SUCCESS, let list else { return [] }
+        defer {
+            for index in 0..<Int(count) { mach_port_deallocate(mach_task_self_, list[index]) }
+            vm_deallocate(mach_task_self_, vm_address_t(UInt(bitPattern: list)),
+                          vm_size_t(Int(count) * MemoryLayout<thread_t>.stride))
+        }
+        return (0..<Int(count)).map { summary(of: list[$0]) }
+    }
+
+    private static func summary(of thread: thread_t) -> ThreadSummary {
+        var name = [CChar](repeating: 0, count: 256)
+        if let pthread = pthread_from_mach_thread_np(thread) {
+            pthread_getname_np(pthread, &name, name.count)
+        }
+        var info = thread_basic_info()
+        var count = mach_msg_type_number_t(MemoryLayout<thread_basic_info_data_t>.size / MemoryLayout<natural_t>.size)
+        let result = withUnsafeMutablePointer(to: &info) {
+            $0.withMemoryRebound(to: integer_t.self, capacity: Int(count)) {
+                thread_info(thread, thread_flavor_t(THREAD_BASIC_INFO), $0, &count)
+            }
+        }
+        let label = String(cString: name)
+        guard result == KERN_SUCCESS else {
+            return ThreadSummary(name: label, state: "unknown", cpuSeconds: 0, cpuPercent: 0)
+        }
+        let states = [TH_STATE_RUNNING: "running", TH_STATE_STOPPED: "stopped", TH_STATE_WAITING: "waiting",
+                      TH_STATE_UNINTERRUPTIBLE: "uninterruptible", TH_STATE_HALTED: "halted"]
+        let seconds = Double(info.user_time.seconds + info.system_time.seconds)
+            + Double(info.user_time.microseconds + info.system_time.microseconds) / 1_000_000
+        return ThreadSummary(name: label,
+                             state: states[info.run_state] ?? "unknown",
+                             cpuSeconds: seconds,
+                             cpuPercent: Double(info.cpu_usage) / Double(TH_USAGE_SCALE) * 100)
+    }
+
+    /// One line per thread, busiest first, under a count.
+    static func format(_ threads: [ThreadSummary]) -> String {
+        var lines = ["\(threads.count) threads"]
+        for thread in threads.sorted(by: { $0.cpuSeconds > $1.cpuSeconds }) {
+            let state = thread.state.padding(toLength: 16, withPad: " ", startingAt: 0)
+            let usage = String(format: "%6.1f%% %10.3fs", thread.cpuPercent, thread.cpuSeconds)
+            lines.append("\(state) \(usage)  \(thread.name.isEmpty ? "(unnamed)" : thread.name)")
+        }
+        return lines.joined(separator: "\n") + "\n"
+    }
+
+    // MARK: - Tools
+
+    /// `sample` writes its report to a file; it's read back and removed.
+    private static func sample(pid: String, seconds: Int) -> String {
+        let file = FileManager.default.temporaryDirectory
+            .appendingPathComponent("vocaglyph-profile-\(UUID().uuidString).txt")
+        defer { try? FileManager.default.removeItem(at: file) }
+        let output = run("/usr/bin/sample", [pid, String(seconds), "-mayDie", "-file", file.path])
+        return (try? String(contentsOf: file, encoding: .utf8)) ?? output
+    }
+
+    /// Output and errors of `tool`, waited for.
+    private static func run(_ tool: String, _ arguments: [String]) -> String {
+        let process = Process()
+        process.executableURL = URL(fileURLWithPath: tool)
+        process.arguments = arguments
+        let pipe = Pipe()
+        process.standardOutput = pipe
+        process.standardError = pipe
+        do {
+            try process.run()
+        } catch {
+            return "\(tool) failed: \(error.localizedDescription)\n"
+        }
+        let data = pipe.fileHandleForReading.readDataToEndOfFile()
+        process.waitUntilExit()
+        return String(decoding: data, as: UTF8.self)
+    }
+}
@@ -0,0 +1,132 @@
+import AVFoundation
+import Foundation
+
+// MARK: - DictationPipeline
+
+/// The dictation flow as swappable stages:
+///
+///     AudioSource → TranscriptionEngine → TextProcessor… → TranscriptSink…
+///
+/// This holds the two ends: the `source` recorded from and the `sinks` the finished
+/// text goes to. The middle is `AppStateManager.processAudio(buffer:)`, which runs
+/// `engineRouter` (the transcriber) and `TextProcessingPipeline` (the processors).
+///
+/// `AppDelegate` wires the stages, so a new input (a file, a stream) or output (a
+/// webhook) is a new `AudioSource` or `TranscriptSink` set there — the recording state
+/// handling doesn't change.
+final class DictationPipeline {
+
+    /// Set once the core services exist; capturing without one does nothing.
+    var source: AudioSource?
+    /// Used instead of `source` for the next capture only, e.g. a `ReplayAudioSource`.
+    var nextSource: AudioSource?
+    /// The source of the capture in progress, so it is also the one stopped.
+    private var capturing: AudioSource?
+    private(set) var sinks: [TranscriptSink]
+
+    /// Starting and stopping the source happen here, never on the main thread: a slow
+    /// `AVAudioEngine.start()` on first launch would block the run loop.
+    private let queue: DispatchQueue
+    /// A start is in flight; a stop that arrives meanwhile waits for it.
+    private var isStarting = false
+    private var pendingStop: (() -> Void)?
+
+    init(source: AudioSource? = nil,
+         sinks: [TranscriptSink] = [],
+         queue: DispatchQueue = DispatchQueue(label: "com.vocaglyph.audioQueue", qos: .userInteractive)) {
+        self.source = source
+        self.sinks = sinks
+        self.queue = queue
+    }
+
+    /// Adds `sink` before the sink at `index`, or last.
+    func addSink(_ sink: TranscriptSink, at index: Int? = nil) {
+        sinks.insert(sink, at: min(index ?? sinks.count, sinks.count))
+    }
+
+    // MARK: - Capture
+
+    /// Starts the source. `onFailure` runs on the main thread if it can't start.
+    /// Call on the main thread.
+    func startCapture(onFailure: @escaping (Error) -> Void) {
+        guard let source = nextSource ?? source else { return }
+        nextSource = nil
+        capturing = source
+        isStarting = true
+        queue.async { [weak self] in
+            do {
+                try source.startCapture()
+            } catch {
+                DispatchQueue.main.async {
+                    self?.isStarting = false
+                    self?.pendingStop = nil
+                    onFailure(error)
+                }
+                return
+            }
+            DispatchQueue.main.async {
+                guard let self else { return }
+                self.isStarting = false
+                // Run a stop that arrived while starting.
+                if let stop = self.pendingStop {
+                    self.pendingStop = nil
+                    stop()
+                }
+            }
+        }
+    }
+
+    /// Stops the source — after a start still in flight, so a quick tap can't stop
+    /// before it started — and passes the audio to `onAudio` on the main thread.
+    /// Call on the main thread.
+    func stopCapture(onAudio: @escaping (AVAudioPCMBuffer?) -> Void) {
+        guard let source = capturing ?? source else {
+            onAudio(nil)
+            return
+        }
+        capturing = nil
+        let stop = { [queue] in
+            queue.async {
+                let buffer = source.stopCapture()
+                DispatchQueue.main.async { onAudio(buffer) }
+            }
+        }
+        if isStarting {
+            pendingStop = stop
+        } else {
+            stop()
+        }
+    }
+
+    // MARK: - Output
+
+    /// Hands `text` to each sink in order until one uses it up.
+    func deliver(_ text: String) {
+        for sink in sinks where sink.receive(text) {
+            Logger.shared.debug("DictationPipeline: '\(sink.name)' took the transcription")
+            return
+        }
+    }
+}
+
+// MARK: - ClosureSink
+
+/// A sink that is one call on an existing service.
+struct ClosureSink: TranscriptSink {
+    let name: String
+    let handler: (String) -> Bool
+
+    init(name: String, handler: @escaping (String) -> Bool) {
+        self.name = name
+        self.handler = handler
+    }
+
+    func receive(_ text: String) -> Bool { handler(text) }
+}
+
+// MARK: - AudioRecorderService + AudioSource
+
+extension AudioRecorderService: AudioSource {
+    func startCapture() throws { try startRecording() }
+    func stopCapture() -> AVAudioPCMBuffer? { stopRecording() }
+}
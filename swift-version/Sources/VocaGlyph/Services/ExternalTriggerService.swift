@@ -0,0 +1,77 @@
+import Foundation
+
+// MARK: - ExternalTriggerCommand
+
+/// Commands accepted over the local command channel.
+enum ExternalTriggerCommand: String, CaseIterable {
+    case start
+    case stop
+    case toggle
+
+    /// Distributed notification name for this command, e.g. `com.vocaglyph.dictation.start`.
+    var notificationName: Notification.Name {
+        Notification.Name("\(ExternalTriggerService.notificationPrefix).\(rawValue)")
+    }
+}
+
+// MARK: - ExternalTriggerService
+
+/// Lets other processes start and stop dictation without a registered global hotkey.
+///
+/// The local command channel is `DistributedNotificationCenter`: any process on the
+/// same login session can post one of the `com.vocaglyph.dictation.*` names, so foot
+/// pedals, Karabiner `shell_command` rules and Shortcuts can drive recording with:
+///
+///     osascript -l JavaScript -e '$.NSDistributedNotificationCenter.defaultCenter.postNotificationNameObjectUserInfoDeliverImmediately("com.vocaglyph.dictation.toggle", $(), $(), true)'
+///
+/// Notifications carry no payload — the sandbox strips `userInfo` from distributed
+/// notifications, so the command is encoded in the name itself.
+///
+/// The channel is unauthenticated: the poster is not known, so any process running as the
+/// user can start a recording. It only listens while `externalTriggersEnabled` is on (off
+/// by default); `handle(_:)` also serves the token-guarded control API either way.
+final class ExternalTriggerService {
+
+    static let notificationPrefix = "com.vocaglyph.dictation"
+
+    private let stateManager: AppStateManager
+    private let center: NotificationCenter
+    private var observers: [NSObjectProtocol] = []
+
+    /// - Parameter center: Injected for tests; defaults to the distributed center.
+    init(stateManager: AppStateManager, center: NotificationCenter = DistributedNotificationCenter.default()) {
+        self.stateManager = stateManager
+        self.center = center
+    }
+
+    deinit {
+        stop()
+    }
+
+    /// Begins listening for external commands. Safe to call more than once.
+    func start() {
+        guard observers.isEmpty else { return }
+        for command in ExternalTriggerCommand.allCases {
+            let observer = center.addObserver(forName: command.notificationName, object: nil, queue: .main) { [weak self] _ in
+                self?.handle(command)
+            }
+            observers.append(observer)
+        }
+        Logger.shared.info("ExternalTriggerService: Listening for \(Self.notificationPrefix).{start,stop,toggle}")
+    }
+
+    func stop() {
+        observers.forEach { center.removeObserver($0) }
+        observers.removeAll()
+    }
+
+    /// Applies a command to the state manager. Must be called on the main thread.
+    func handle(_ command: ExternalTriggerCommand) {
+        Logger.shared.info("ExternalTriggerService: Received '\(command.rawValue)' (state: \(stateManager.currentState))")
+        switch command {
+        case .start:  stateManager.startDictation()
+        case .stop:   stateManager.stopDictation()
+        case .toggle: stateManager.toggleDictation()
+        }
+    }
+}
@@ -0,0 +1,68 @@
+import Foundation
+
+// MARK: - FileBookmarkStore
+
+/// Remembers files the user picked in an open panel, so a sandboxed build can reach them
+/// again after a relaunch. The sandbox grants access to a picked file only until the app
+/// quits; a security-scoped bookmark saved at pick time renews it.
+///
+/// Settings keep storing plain paths. Bookmarks are kept beside them in UserDefaults,
+/// keyed by path, and a path without a bookmark is used as is — which is all an
+/// unsandboxed build needs.
+final class FileBookmarkStore {
+
+    static let shared = FileBookmarkStore()
+    static let defaultsKey = "fileBookmarks"
+
+    private let defaults: UserDefaults
+    private let scoped: Bool
+
+    /// `scoped` creates security-scoped bookmarks; they need the sandbox entitlement, so
+    /// it defaults to whether the sandbox is active.
+    init(defaults: UserDefaults = .standard, scoped: Bool = AppSandbox.isActive) {
+        self.defaults = defaults
+        self.scoped = scoped
+    }
+
+    /// Saves a bookmark for `url`. Call while the open panel's access is still valid.
+    func remember(_ url: URL) {
+        do {
+            let data = try url.bookmarkData(options: scoped ? [.withSecurityScope, .securityScopeAllowOnlyReadAccess] : [],
+                                            includingResourceValuesForKeys: nil, relativeTo: nil)
+            var bookmarks = storedBookmarks
+            bookmarks[url.path] = data
+            defaults.set(bookmarks, forKey: Self.defaultsKey)
+        } catch {
+            Logger.shared.error("FileBookmarkStore: Could not bookmark \(url.path) — \(error.localizedDescription)")
+        }
+    }
+
+    func forget(_ path: String) {
+        var bookmarks = storedBookmarks
+        guard bookmarks.removeValue(forKey: path) != nil else { return }
+        defaults.set(bookmarks, forKey: Self.defaultsKey)
+    }
+
+    func hasBookmark(for path: String) -> Bool {
+        storedBookmarks[path] != nil
+    }
+
+    /// Opens access to the bookmarked file at `path` and returns the closure that closes
+    /// it again. Paths without a bookmark get a no-op closure.
+    func beginAccess(toPath path: String) -> () -> Void {
+        guard scoped, let data = storedBookmarks[path] else { return {} }
+        var isStale = false
+        guard let url = try? URL(resolvingBookmarkData: data, options: .withSecurityScope,
+                                 relativeTo: nil, bookmarkDataIsStale: &isStale) else {
+            Logger.shared.error("FileBookmarkStore: Bookmark for \(path) no longer resolves")
+            return {}
+        }
+        guard url.startAccessingSecurityScopedResource() else { return {} }
+        if isStale { remember(url) }
+        return { url.stopAccessingSecurityScopedResource() }
+    }
+
+    private var storedBookmarks: [String: Data] {
+        defaults.dictionary(forKey: Self.defaultsKey) as? [String: Data] ?? [:]
+    }
+}
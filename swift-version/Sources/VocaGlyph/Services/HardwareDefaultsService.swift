@@ -0,0 +1,75 @@
+import Foundation
+
+// MARK: - HardwareEvaluation
+
+/// What was detected and recommended the last time defaults were chosen. Persisted so a
+/// later release (new `rulesVersion`) or a migrated install (different Mac) can tell
+/// whether the tuned settings are still appropriate.
+struct HardwareEvaluation: Codable, Equatable {
+    var profile: HardwareProfile
+    var recommendation: HardwareRecommendation
+    var rulesVersion: Int
+    var evaluatedAt: Date
+}
+
+// MARK: - HardwareDefaultsService
+
+/// Chooses hardware-appropriate transcription settings on first run and re-evaluates
+/// them when the machine or the rules change.
+///
+/// - **First run**: the recommended worker and fallback counts are written to settings.
+/// - **Re-evaluation**: a field is only moved to the new recommendation when it still
+///   holds the previous recommendation — values the user changed are left alone.
+///
+/// The model recommendation is stored but never selected automatically; picking a
+/// model that is not downloaded would block dictation until the download finishes.
+enum HardwareDefaultsService {
+
+    static let evaluationKey = "hardwareEvaluation"
+
+    /// Detects the hardware and applies or refreshes tuned defaults. Call once at launch,
+    /// before the transcription engines read settings. Returns the evaluation in effect.
+    @discardableResult
+    static func applyIfNeeded(profile: HardwareProfile = .detect(),
+                              store: SettingsStore = .shared,
+                              defaults: UserDefaults = .standard,
+                              now: Date = Date()) -> HardwareEvaluation {
+        let recommendation = HardwareRecommendation(profile: profile)
+        let previous = storedEvaluation(defaults: defaults)
+
+        if let previous,
+           previous.profile == profile,
+           previous.rulesVersion == HardwareRecommendation.rulesVersion {
+            return previous
+        }
+
+        let evaluation = HardwareEvaluation(
+            profile: profile,
+            recommendation: recommendation,
+            rulesVersion: HardwareRecommendation.rulesVersion,
+            evaluatedAt: now
+        )
+
+        store.update { settings in
+            let old = previous?.recommendation
+            if old == nil || settings.transcriptionWorkerCount == old?.transcriptionWorkerCount {
+                settings.transcriptionWorkerCount = recommendation.transcriptionWorkerCount
+            }
+            if old == nil || settings.temperatureFallbackCount == old?.temperatureFallbackCount {
+                settings.temperatureFallbackCount = recommendation.temperatureFallbackCount
+            }
+        }
+
+        if let data = try? JSONEncoder().encode(evaluation) {
+            defaults.set(data, forKey: evaluationKey)
+        }
+        Logger.shared.info("HardwareDefaultsService: \(previous == nil ? "Detected" : "Re-evaluated") \(profile.chip), \(profile.performanceCores)P/\(profile.totalCores) cores, \(profile.memoryGB) GB — recommending '\(recommendation.recommendedModel)', \(recommendation.transcriptionWorkerCount) worker(s), \(recommendation.temperatureFallbackCount) fallback(s)")
+        return evaluation
+    }
+
+    /// The last stored evaluation, or `nil` before the first run completes.
+    static func storedEvaluation(defaults: UserDefaults = .standard) -> HardwareEvaluation? {
+        guard let data = defaults.data(forKey: evaluationKey) else { return nil }
+        return try? JSONDecoder().decode(HardwareEvaluation.self, from: data)
+    }
+}
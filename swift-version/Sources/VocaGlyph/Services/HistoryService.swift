@@ -0,0 +1,166 @@
+import Foundation
+import SwiftData
+
+// MARK: - HistoryError
+
+enum HistoryError: LocalizedError, Equatable {
+    /// The token was never issued, already used, or has expired.
+    case invalidConfirmationToken
+
+    var errorDescription: String? {
+        switch self {
+        case .invalidConfirmationToken:
+            return "The confirmation token is invalid or has expired. Request a new one and try again."
+        }
+    }
+}
+
+// MARK: - HistoryService
+
+/// Reads and writes the transcription history in the shared SwiftData store.
+///
+/// Retention is controlled by three preferences in `AppSettings`:
+/// - `historyRetention` — a `Retention` raw value.
+/// - `historyRetentionDays` — age limit for `.days`.
+/// - `historyRetentionEntries` — count limit for `.entries`.
+///
+/// The policy is enforced after every new entry and once at launch, so lowering a limit
+/// takes effect on the next dictation. `.disabled` stops recording but keeps existing
+/// entries; `clearHistory(confirmationToken:)` removes them.
+final class HistoryService {
+
+    enum Retention: String, CaseIterable {
+        /// Keep entries newer than `historyRetentionDays`.
+        case days
+        /// Keep the newest `historyRetentionEntries` entries.
+        case entries
+        case unlimited
+        /// Record nothing.
+        case disabled
+    }
+
+    static let retentionDaysRange = 1...3650
+    static let retentionEntriesRange = 1...100_000
+
+    /// How long a token from `clearHistoryConfirmationToken()` stays valid.
+    static let confirmationTokenLifetime: TimeInterval = 60
+
+    private let container: ModelContainer
+    private let store: SettingsStore
+    private let notificationCenter: NotificationCenter
+    private var pendingClear: (token: String, issuedAt: Date)?
+
+    init(container: ModelContainer, store: SettingsStore = .shared, notificationCenter: NotificationCenter = .default) {
+        self.container = container
+        self.store = store
+        self.notificationCenter = notificationCenter
+    }
+
+    // MARK: - Reading
+
+    /// The newest entries, newest first.
+    @MainActor
+    func recent(limit: Int) -> [TranscriptionItem] {
+        var descriptor = FetchDescriptor<TranscriptionItem>(
+            sortBy: [SortDescriptor(\.timestamp, order: .reverse)]
+        )
+        descriptor.fetchLimit = limit
+        return (try? container.mainContext.fetch(descriptor)) ?? []
+    }
+
+    @MainActor
+    func item(_ id: UUID) -> TranscriptionItem? {
+        let descriptor = FetchDescriptor<TranscriptionItem>(predicate: #Predicate { $0.id == id })
+        return try? container.mainContext.fetch(descriptor).first
+    }
+
+    // MARK: - Writing
+
+    /// Stores `text`, redacted per `TextRedactor`, and prunes what the retention policy no
+    /// longer allows. Returns `nil` when history is disabled.
+    @MainActor @discardableResult
+    func record(_ text: String) -> TranscriptionItem? {
+        let settings = store.settings
+        guard Retention(rawValue: settings.historyRetention) != .disabled else { return nil }
+        let context = container.mainContext
+        let item = TranscriptionItem(text: TextRedactor.redact(text, settings: settings))
+        context.insert(item)
+        enforceRetention()
+        do {
+            try context.save()
+        } catch {
+            Logger.shared.error("HistoryService: Failed to save transcription — \(error.localizedDescription)")
+        }
+        HistoryChangedEvent().post(from: self, to: notificationCenter)
+        return item
+    }
+
+    /// Deletes entries outside the retention policy. Returns how many were deleted.
+    @MainActor @discardableResult
+    func enforceRetention(now: Date = Date()) -> Int {
+        let settings = store.settings
+        let context = container.mainContext
+        let expired: [TranscriptionItem]
+        do {
+            switch Retention(rawValue: settings.historyRetention) ?? .days {
+            case .days:
+                let days = min(max(settings.historyRetentionDays, Self.retentionDaysRange.lowerBound),
+                               Self.retentionDaysRange.upperBound)
+                guard let cutoff = Calendar.current.date(byAdding: .day, value: -days, to: now) else { return 0 }
+                expired = try context.fetch(FetchDescriptor<TranscriptionItem>(
+                    predicate: #Predicate { $0.timestamp < cutoff }
+                ))
+            case .entries:
+                var descriptor = FetchDescriptor<TranscriptionItem>(
+                    sortBy: [SortDescriptor(\.timestamp, order: .reverse)]
+                )
+                descriptor.fetchOffset = min(max(settings.historyRetentionEntries, Self.retentionEntriesRange.lowerBound),
+                                             Self.retentionEntriesRange.upperBound)
+                expired = try context.fetch(descriptor)
+            case .unlimited, .disabled:
+                return 0
+            }
+        } catch {
+            Logger.shared.error("HistoryService: Failed to fetch entries for retention — \(error.localizedDescription)")
+            return 0
+        }
+        guard !expired.isEmpty else { return 0 }
+        for item in expired {
+            context.delete(item)
+        }
+        try? context.save()
+        Logger.shared.info("HistoryService: Removed \(expired.count) entries outside the '\(settings.historyRetention)' retention policy")
+        return expired.count
+    }
+
+    // MARK: - Clearing
+
+    /// Issues a one-time token that `clearHistory(confirmationToken:)` requires, so a
+    /// purge always takes two deliberate steps. A new token replaces the previous one.
+    func clearHistoryConfirmationToken(now: Date = Date()) -> String {
+        let token = UUID().uuidString
+        pendingClear = (token, now)
+        return token
+    }
+
+    /// Deletes every history entry. Returns how many were deleted.
+    @MainActor @discardableResult
+    func clearHistory(confirmationToken: String, now: Date = Date()) throws -> Int {
+        guard let pending = pendingClear,
+              pending.token == confirmationToken,
+              now.timeIntervalSince(pending.issuedAt) <= Self.confirmationTokenLifetime else {
+            throw HistoryError.invalidConfirmationToken
+        }
+        pendingClear = nil
+
+        let context = container.mainContext
+        let items = try context.fetch(FetchDescriptor<TranscriptionItem>())
+        for item in items {
+            context.delete(item)
+        }
+        try context.save()
+        Logger.shared.info("HistoryService: Cleared \(items.count) history entries")
+        HistoryChangedEvent().post(from: self, to: notificationCenter)
+        return items.count
+    }
+}
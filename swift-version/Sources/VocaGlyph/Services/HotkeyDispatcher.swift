@@ -0,0 +1,106 @@
+import Foundation
+
+// MARK: - HotkeyEvent
+
+/// What the shortcut listeners and the app report to `HotkeyDispatcher`.
+enum HotkeyEvent: Equatable {
+    /// The dictation shortcut went down.
+    case pressed
+    /// The dictation shortcut was let go.
+    case released
+    /// The incognito shortcut went down.
+    case incognitoPressed
+    /// The app is resting again (idle, paused or error), so a new press cycle may begin.
+    case appRested
+}
+
+// MARK: - HotkeyTarget
+
+/// The part of `AppStateManager` the shortcut drives.
+protocol HotkeyTarget: AnyObject {
+    var currentState: AppState { get }
+    func startRecording(capturingContext: Bool)
+    func stopRecording()
+    func flashNotReadyMessage()
+}
+
+extension AppStateManager: HotkeyTarget {}
+
+// MARK: - HotkeyDispatcher
+
+/// The single place hotkey state changes. Listener callbacks and `AppDelegate` only
+/// `send(_:)` events; they are handled one at a time, in order, on the main queue —
+/// the thread `AppStateManager` runs on — so the press cycle, the app state it reads
+/// and the transitions it starts can't interleave with a re-register or a restart.
+final class HotkeyDispatcher {
+
+    /// Where the current press of the shortcut is.
+    enum Phase: Equatable {
+        /// No press in progress; the next press may start a recording.
+        case ready
+        /// This press started the recording; releasing stops it.
+        case holding
+        /// Released (or ignored); waiting for the app to rest before the next press.
+        case finishing
+    }
+
+    private(set) var phase: Phase = .ready
+    /// Called when the incognito shortcut is pressed.
+    var onIncognitoToggle: (() -> Void)?
+
+    private weak var target: HotkeyTarget?
+    private let queue: DispatchQueue
+    private let now: () -> CFAbsoluteTime
+    /// Absolute time of the most recent recording start. Presses within `debounceInterval`
+    /// of it are key bounce or a double-tap, too quick for the audio engine (~100 ms).
+    private var lastActivationTime: CFAbsoluteTime = 0
+    private let debounceInterval: CFAbsoluteTime = 0.05
+
+    init(target: HotkeyTarget,
+         queue: DispatchQueue = .main,
+         now: @escaping () -> CFAbsoluteTime = CFAbsoluteTimeGetCurrent) {
+        self.target = target
+        self.queue = queue
+        self.now = now
+    }
+
+    /// Queues `event`. Safe from any thread, including the event tap callback.
+    func send(_ event: HotkeyEvent) {
+        queue.async { [weak self] in self?.handle(event) }
+    }
+
+    /// Applies `event`. Runs on `queue`; tests call it directly.
+    func handle(_ event: HotkeyEvent) {
+        guard let target else { return }
+        switch event {
+        case .pressed:
+            guard phase == .ready else { return } // auto-repeat or a press while processing
+            if target.currentState == .initializing {
+                target.flashNotReadyMessage()
+                return
+            }
+            let time = now()
+            guard time - lastActivationTime >= debounceInterval else { return }
+            // Another trigger (pedal, control API, replay) may already be dictating.
+            guard target.currentState.isResting else { return }
+            target.startRecording(capturingContext: true)
+            guard target.currentState == .recording else { return }
+            lastActivationTime = time
+            phase = .holding
+
+        case .released:
+            guard phase == .holding else { return }
+            target.stopRecording()
+            // A recording that failed to start is already resting and won't report it again.
+            phase = target.currentState.isResting ? .ready : .finishing
+
+        case .incognitoPressed:
+            onIncognitoToggle?()
+
+        case .appRested:
+            // A press still held stays `.holding`, so its release isn't mistaken for a
+            // new press cycle.
+            if phase == .finishing { phase = .ready }
+        }
+    }
+}
@@ -10,6 +10,35 @@ extension UserDefaults {
     /// Default shortcut: ⌃ ⇧ C  (keyCode 8, Control + Shift)
     static let defaultShortcutKeyCode: Int = 8
     static let defaultShortcutModifiers: UInt64 = CGEventFlags([.maskControl, .maskShift]).rawValue
+
+    static let hotkeyBackendKey = "hotkeyBackend"
+}
+
+// MARK: - Hotkey Backend
+
+/// How `HotkeyService` listens for the global shortcut. Both backends require the
+/// Accessibility permission and both support modifier-only shortcuts and key-up events.
+enum HotkeyBackend: String, CaseIterable, Identifiable {
+    /// `CGEvent` session tap — can consume the shortcut so it never reaches the focused app.
+    case eventTap
+    /// `NSEvent` global + local monitors — observe-only, so the shortcut is also delivered
+    /// to the focused app, but keeps working in setups where event taps are blocked
+    /// (e.g. security tools or remote-desktop software that disable taps).
+    case nsEvent
+
+    var id: String { rawValue }
+
+    var displayName: String {
+        switch self {
+        case .eventTap: return "Event Tap"
+        case .nsEvent:  return "NSEvent Monitor"
+        }
+    }
+
+    /// The backend stored in `UserDefaults`, falling back to `.eventTap`.
+    static var current: HotkeyBackend {
+        SettingsStore.shared.settings.hotkeyBackend
+    }
 }
 
 /// Sentinel key code indicating a modifier-only shortcut (no regular key required).
@@ -88,6 +117,11 @@ struct ShortcutDisplayHelper {
 class HotkeyService {
     private var eventTap: CFMachPort?
     private var runLoopSource: CFRunLoopSource?
+    private var globalMonitor: Any?
+    private var localMonitor: Any?
+
+    /// Backend currently running, or `nil` while stopped.
+    private(set) var activeBackend: HotkeyBackend?
 
     private var targetKeyCode: CGKeyCode = CGKeyCode(UserDefaults.defaultShortcutKeyCode)
     private var targetFlags: CGEventFlags = CGEventFlags(rawValue: UserDefaults.defaultShortcutModifiers)
@@ -98,45 +132,75 @@ class HotkeyService {
     private var lastRegisteredKeyCode: CGKeyCode? = nil
     private var lastRegisteredFlags: CGEventFlags? = nil
 
-    private let stateManager: AppStateManager
+    /// Incognito toggle shortcut; `nil` when unset.
+    private var incognitoKeyCode: CGKeyCode?
+    private var incognitoFlags: CGEventFlags = []
+
+    /// Called on the main thread when the incognito shortcut is pressed.
+    var onIncognitoToggle: (() -> Void)? {
+        get { dispatcher.onIncognitoToggle }
+        set { dispatcher.onIncognitoToggle = newValue }
+    }
+
+    /// Owns the press cycle; this class only matches events to the shortcut.
+    let dispatcher: HotkeyDispatcher
+    private var settingsSubscription: SettingsSubscription?
 
-    // --- Re-entry guards (accessed only on the CGEvent callback thread) ---
-    // isRecording: true from first keyDown until resetToIdle() fires on main thread.
-    private var isRecording = false
-    // lastActivationTime: absolute time of the most recent recording start.
-    // Debounce window prevents rapid re-triggers caused by audio engine startup
-    // latency (~100 ms), where sub-threshold presses always capture 0 frames.
-    private var lastActivationTime: CFAbsoluteTime = 0
-    private let debounceInterval: CFAbsoluteTime = 0.05  // 50 ms — guards against key bounce and rapid double-taps
+    // Which of the listener's events are the shortcut's. Only the listener's own
+    // thread touches these; whether a press records is `dispatcher`'s decision.
+    /// The shortcut's keyDown was consumed, so its keyUp is too.
+    private var shortcutKeyIsDown = false
+    /// A modifier-only shortcut is held, so letting go of it is a release.
+    private var shortcutModifiersAreDown = false
 
     init(stateManager: AppStateManager) {
-        self.stateManager = stateManager
+        dispatcher = HotkeyDispatcher(target: stateManager)
 
         loadShortcutFromDefaults()
-        NotificationCenter.default.addObserver(forName: UserDefaults.didChangeNotification, object: nil, queue: .main) { [weak self] _ in
-            self?.loadShortcutFromDefaults()
+        settingsSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.shortcutKeyCode != new.shortcutKeyCode
+                || old.shortcutModifiers != new.shortcutModifiers
+                || old.incognitoShortcutKeyCode != new.incognitoShortcutKeyCode
+                || old.incognitoShortcutModifiers != new.incognitoShortcutModifiers
+                || old.hotkeyBackend != new.hotkeyBackend else { return }
+            DispatchQueue.main.async { self?.reloadFromDefaults() }
         }
     }
 
-    /// Called by AppDelegate when AppState returns to .idle.
-    /// Resets the re-entry guard so the next hotkey press is accepted.
+    /// Re-reads the shortcut and backend from `SettingsStore`. Triggered by the store
+    /// subscription; also safe to call directly after `SettingsStore.reload()`.
     /// Must be called on the main thread.
+    func reloadFromDefaults() {
+        loadShortcutFromDefaults()
+        restartIfBackendChanged()
+    }
+
+    /// Swaps listeners when the user picks a different backend in Settings.
+    private func restartIfBackendChanged() {
+        guard let active = activeBackend, active != HotkeyBackend.current else { return }
+        Logger.shared.info("HotkeyService: Backend changed \(active.rawValue) → \(HotkeyBackend.current.rawValue), restarting")
+        stop()
+        start()
+    }
+
+    /// Called by AppDelegate when AppState returns to .idle.
+    /// Lets the dispatcher accept the next hotkey press.
     func resetToIdle() {
-        isRecording = false
+        dispatcher.send(.appRested)
     }
     
     private func loadShortcutFromDefaults() {
-        let keyCodeInt = UserDefaults.standard.object(forKey: UserDefaults.customShortcutKeyCodeKey) as? Int
-            ?? UserDefaults.defaultShortcutKeyCode
-        let modifiersRaw = UserDefaults.standard.object(forKey: UserDefaults.customShortcutModifiersKey) as? UInt64
-            ?? UserDefaults.defaultShortcutModifiers
+        let settings = SettingsStore.shared.settings
+        let newKeyCode = CGKeyCode(settings.shortcutKeyCode)
+        let newFlags = CGEventFlags(rawValue: settings.shortcutModifiers)
 
-        let newKeyCode = CGKeyCode(keyCodeInt)
-        let newFlags = CGEventFlags(rawValue: modifiersRaw)
+        incognitoKeyCode = (0...127).contains(settings.incognitoShortcutKeyCode)
+            ? CGKeyCode(settings.incognitoShortcutKeyCode) : nil
+        incognitoFlags = CGEventFlags(rawValue: settings.incognitoShortcutModifiers)
 
         // AC #4: skip re-registration if the resolved shortcut hasn't changed.
-        // UserDefaults.didChangeNotification fires for every stored key during startup
-        // (6+ times), producing redundant log lines and unnecessary re-registration work.
+        // Direct reloadFromDefaults() calls can repeat the same values, producing
+        // redundant log lines and unnecessary re-registration work.
         guard newKeyCode != lastRegisteredKeyCode || newFlags != lastRegisteredFlags else { return }
 
         self.targetKeyCode = newKeyCode
@@ -148,15 +212,31 @@ class HotkeyService {
         Logger.shared.info("Hotkey Service updated to listen for: \(display) (Code: \(targetKeyCode), Flags: \(targetFlags.rawValue))")
     }
     
-    func start() {
-        // Request accessibility permissions if needed (required for CGEvent tap)
-        let options = [kAXTrustedCheckOptionPrompt.takeUnretainedValue() as String: true] as CFDictionary
+    /// Re-installs the listeners, e.g. after the Mac wakes: the event tap often stops
+    /// delivering events across sleep. Doesn't prompt for Accessibility again.
+    /// Must be called on the main thread.
+    func restart(reason: String) {
+        Logger.shared.info("HotkeyService: Restarting (\(reason))")
+        stop()
+        start(promptForAccess: false)
+    }
+
+    func start(promptForAccess: Bool = true) {
+        // Request accessibility permissions if needed (required by both backends)
+        let options = [kAXTrustedCheckOptionPrompt.takeUnretainedValue() as String: promptForAccess] as CFDictionary
         let accessEnabled = AXIsProcessTrustedWithOptions(options)
         
         if !accessEnabled {
             Logger.shared.error("Accessibility permissions not granted. Hotkeys will not work until granted.")
         }
-        
+
+        switch HotkeyBackend.current {
+        case .eventTap: startEventTap()
+        case .nsEvent:  startEventMonitors()
+        }
+    }
+
+    private func startEventTap() {
         let eventMask = (1 << CGEventType.keyDown.rawValue)
             | (1 << CGEventType.keyUp.rawValue)
             | (1 << CGEventType.flagsChanged.rawValue)
@@ -177,6 +257,7 @@ class HotkeyService {
             userInfo: Unmanaged.passUnretained(self).toOpaque()
         ) else {
             Logger.shared.error("Failed to create event tap")
+            notifyHotkeyUnavailable()
             return
         }
         
@@ -184,9 +265,39 @@ class HotkeyService {
         self.runLoopSource = CFMachPortCreateRunLoopSource(kCFAllocatorDefault, tap, 0)
         CFRunLoopAddSource(CFRunLoopGetCurrent(), runLoopSource, .commonModes)
         CGEvent.tapEnable(tap: tap, enable: true)
+        activeBackend = .eventTap
         
         Logger.shared.info("Hotkey capture started")
     }
+
+    /// NSEvent backend. The global monitor sees events destined for other apps; the
+    /// local monitor covers the case where one of VocaGlyph's own windows is key.
+    /// Monitors cannot swallow events, so the shortcut still reaches the focused app.
+    private func startEventMonitors() {
+        let mask: NSEvent.EventTypeMask = [.keyDown, .keyUp, .flagsChanged]
+
+        globalMonitor = NSEvent.addGlobalMonitorForEvents(matching: mask) { [weak self] event in
+            guard let cgEvent = event.cgEvent else { return }
+            _ = self?.process(type: cgEvent.type, event: cgEvent)
+        }
+        localMonitor = NSEvent.addLocalMonitorForEvents(matching: mask) { [weak self] event in
+            guard let self, let cgEvent = event.cgEvent else { return event }
+            return self.process(type: cgEvent.type, event: cgEvent) ? nil : event
+        }
+
+        guard globalMonitor != nil else {
+            Logger.shared.error("Failed to install NSEvent global monitor")
+            notifyHotkeyUnavailable()
+            return
+        }
+        activeBackend = .nsEvent
+        Logger.shared.info("Hotkey capture started (NSEvent monitor)")
+    }
+
+    private func notifyHotkeyUnavailable() {
+        let display = ShortcutDisplayHelper.displayString(keyCode: targetKeyCode, flags: targetFlags)
+        NotificationService.shared.post(.hotkeyUnavailable(shortcut: display))
+    }
     
     func stop() {
         if let tap = eventTap {
@@ -195,6 +306,20 @@ class HotkeyService {
                 CFRunLoopRemoveSource(CFRunLoopGetCurrent(), source, .commonModes)
             }
         }
+        eventTap = nil
+        runLoopSource = nil
+        if let monitor = globalMonitor { NSEvent.removeMonitor(monitor) }
+        if let monitor = localMonitor { NSEvent.removeMonitor(monitor) }
+        globalMonitor = nil
+        localMonitor = nil
+        activeBackend = nil
+        // A restart can swallow the key-up of a held shortcut; release it here so the
+        // recording doesn't run until its time limit.
+        if shortcutKeyIsDown || shortcutModifiersAreDown {
+            shortcutKeyIsDown = false
+            shortcutModifiersAreDown = false
+            dispatcher.send(.released)
+        }
     }
     
     // MARK: - Modifier mask helpers
@@ -204,8 +329,12 @@ class HotkeyService {
 
     /// Returns true when `flags` contains exactly the modifiers in `targetFlags` (no more, no less).
     private func exactModifierMatch(_ flags: CGEventFlags) -> Bool {
+        exactModifierMatch(flags, target: targetFlags)
+    }
+
+    private func exactModifierMatch(_ flags: CGEventFlags, target: CGEventFlags) -> Bool {
         for mask in trackedMasks {
-            if targetFlags.contains(mask) != flags.contains(mask) { return false }
+            if target.contains(mask) != flags.contains(mask) { return false }
         }
         return true
     }
@@ -213,31 +342,48 @@ class HotkeyService {
     // MARK: - Event handler
 
     private func handleEvent(proxy: CGEventTapProxy, type: CGEventType, event: CGEvent) -> Unmanaged<CGEvent>? {
+        // macOS switches a tap off when a callback is slow (or across sleep) and tells us
+        // once; without re-enabling it the shortcut silently stops working.
+        if type == .tapDisabledByTimeout || type == .tapDisabledByUserInput {
+            if let eventTap { CGEvent.tapEnable(tap: eventTap, enable: true) }
+            Logger.shared.info("HotkeyService: Event tap was disabled by the system — re-enabled")
+            return Unmanaged.passUnretained(event)
+        }
+        return process(type: type, event: event) ? nil : Unmanaged.passUnretained(event)
+    }
+
+    /// Backend-agnostic shortcut matching. Returns `true` when the event belongs to the
+    /// shortcut and should be consumed (only honoured by backends that can consume).
+    private func process(type: CGEventType, event: CGEvent) -> Bool {
         let flags = event.flags
 
+        // ── Incognito toggle ─────────────────────────────────────────────────
+        if let incognitoKeyCode, type == .keyDown || type == .keyUp,
+           CGKeyCode(event.getIntegerValueField(.keyboardEventKeycode)) == incognitoKeyCode,
+           exactModifierMatch(flags, target: incognitoFlags) {
+            // Toggle on keyDown only; auto-repeat would flip it back and forth.
+            if type == .keyDown && event.getIntegerValueField(.keyboardEventAutorepeat) == 0 {
+                dispatcher.send(.incognitoPressed)
+            }
+            return true // consume
+        }
+
         // ── Modifier-only shortcut ───────────────────────────────────────────
         if targetKeyCode == kModifierOnlyKeyCode, type == .flagsChanged {
             let modifiersActive = exactModifierMatch(flags)
 
             if modifiersActive {
-                // All required modifiers are now held → start (if not already).
-                let now = CFAbsoluteTimeGetCurrent()
-                let withinDebounce = (now - lastActivationTime) < debounceInterval
-
-                if stateManager.currentState == .initializing {
-                    DispatchQueue.main.async { self.stateManager.flashNotReadyMessage() }
-                } else if !isRecording && !withinDebounce {
-                    isRecording = true
-                    lastActivationTime = now
-                    DispatchQueue.main.async { self.stateManager.startRecording() }
-                }
-                return nil // consume
-            } else if isRecording {
-                // At least one required modifier was released → stop.
-                DispatchQueue.main.async { self.stateManager.stopRecording() }
-                return nil
+                // All required modifiers are now held → press (repeats are ignored).
+                shortcutModifiersAreDown = true
+                dispatcher.send(.pressed)
+                return true // consume
+            } else if shortcutModifiersAreDown {
+                // At least one required modifier was released → release.
+                shortcutModifiersAreDown = false
+                dispatcher.send(.released)
+                return true
             }
-            return Unmanaged.passUnretained(event)
+            return false
         }
 
         // ── Regular (key + modifiers) shortcut ───────────────────────────────
@@ -246,38 +392,23 @@ class HotkeyService {
 
         if keyCode == targetKeyCode {
             if type == .keyDown && matchesMask {
-                let now = CFAbsoluteTimeGetCurrent()
-                let withinDebounce = (now - lastActivationTime) < debounceInterval
-
-                if stateManager.currentState == .initializing {
-                    DispatchQueue.main.async {
-                        self.stateManager.flashNotReadyMessage()
-                    }
-                } else if !isRecording && !withinDebounce {
-                    isRecording = true
-                    lastActivationTime = now
-                    DispatchQueue.main.async {
-                        self.stateManager.startRecording()
-                    }
-                }
-                return nil // Consume event
+                shortcutKeyIsDown = true
+                dispatcher.send(.pressed)
+                return true // Consume event
             } else if type == .keyUp {
-                // Stop only if we actually started a recording in this press cycle.
-                if isRecording {
-                    // Don't clear isRecording here — keep it true until the app
-                    // is fully idle (resetToIdle() is called from AppDelegate).
-                    // This prevents a new keyDown from sneaking in while processing.
-                    DispatchQueue.main.async {
-                        self.stateManager.stopRecording()
-                    }
-                    return nil
+                // The dispatcher ignores a release that didn't start a recording, and
+                // keeps the next press out until the app is resting again.
+                if shortcutKeyIsDown {
+                    shortcutKeyIsDown = false
+                    dispatcher.send(.released)
+                    return true
                 }
 
                 // Consume matching keyUp even if we weren't recording
-                if matchesMask { return nil }
+                if matchesMask { return true }
             }
         }
 
-        return Unmanaged.passUnretained(event)
+        return false
     }
 }
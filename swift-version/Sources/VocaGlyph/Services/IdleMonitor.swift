@@ -0,0 +1,76 @@
+import Foundation
+
+// MARK: - IdleMonitor
+
+/// Notices when nothing has been dictated for a while, so `AppDelegate` can stop
+/// background work that only matters while the app is in use. Every recording calls
+/// `noteActivity()`; once `timeout` passes without one, `onIdle` runs, and the next
+/// `noteActivity()` runs `onResume` before the dictation goes ahead.
+final class IdleMonitor {
+
+    /// Allowed values of the `idleReductionMinutes` setting.
+    static let minutesRange = 5...240
+
+    /// Seconds without dictation before `onIdle`. Takes effect at the next check.
+    var timeout: TimeInterval
+    var onIdle: (() -> Void)?
+    var onResume: (() -> Void)?
+
+    private(set) var isIdle = false
+    private(set) var lastActivity: Date
+    private let checkInterval: TimeInterval
+    private let now: () -> Date
+    private var timer: Timer?
+
+    /// Idleness is checked every `checkInterval` seconds, so `onIdle` can come up to that
+    /// much later than `timeout`; a coarse interval keeps the monitor itself cheap.
+    init(timeout: TimeInterval, checkInterval: TimeInterval = 30, now: @escaping () -> Date = Date.init) {
+        self.timeout = timeout
+        self.checkInterval = checkInterval
+        self.now = now
+        lastActivity = now()
+    }
+
+    deinit {
+        timer?.invalidate()
+    }
+
+    var isRunning: Bool { timer != nil }
+
+    /// Starts counting from now. Call on the main thread.
+    func start() {
+        guard timer == nil else { return }
+        lastActivity = now()
+        timer = Timer.scheduledTimer(withTimeInterval: checkInterval, repeats: true) { [weak self] _ in
+            self?.check()
+        }
+    }
+
+    /// Stops checking and resumes first if idle, so nothing stays reduced.
+    func stop() {
+        timer?.invalidate()
+        timer = nil
+        wake()
+    }
+
+    /// Called when a dictation starts.
+    func noteActivity() {
+        lastActivity = now()
+        wake()
+    }
+
+    /// The timer calls this; tests may too.
+    func check() {
+        guard !isIdle, now().timeIntervalSince(lastActivity) >= timeout else { return }
+        isIdle = true
+        Logger.shared.info("IdleMonitor: No dictation for \(Int(timeout / 60)) min — reducing background work")
+        onIdle?()
+    }
+
+    private func wake() {
+        guard isIdle else { return }
+        isIdle = false
+        Logger.shared.info("IdleMonitor: Dictation resumed — restoring background work")
+        onResume?()
+    }
+}
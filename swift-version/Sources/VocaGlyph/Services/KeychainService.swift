@@ -118,3 +118,81 @@ public actor KeychainService {
         }
     }
 }
+
+// MARK: - SecretKey
+
+/// Every secret VocaGlyph stores. Secrets live only in the Keychain — they are never
+/// written to `UserDefaults`, `AppSettings` or settings backups.
+public enum SecretKey: String, CaseIterable, Sendable {
+    case anthropicApiKey
+    case geminiApiKey
+    /// HuggingFace access token used for gated model repos.
+    case huggingFaceToken
+    /// API key for a LibreTranslate-compatible translation server.
+    case translationApiKey
+    /// API key for the OpenAI-compatible post-processing endpoint.
+    case openAICompatibleApiKey
+
+    /// Keychain service identifier. The API key ids predate this enum and must not change.
+    public var service: String {
+        switch self {
+        case .anthropicApiKey:    return "com.vocaglyph.api.anthropic"
+        case .geminiApiKey:       return "com.vocaglyph.api.gemini"
+        case .huggingFaceToken:   return "com.vocaglyph.token.huggingface"
+        case .translationApiKey:  return "com.vocaglyph.api.translation"
+        case .openAICompatibleApiKey: return "com.vocaglyph.api.openai-compatible"
+        }
+    }
+
+    public var displayName: String {
+        switch self {
+        case .anthropicApiKey:    return "Anthropic API Key"
+        case .geminiApiKey:       return "Gemini API Key"
+        case .huggingFaceToken:   return "HuggingFace Token"
+        case .translationApiKey:  return "Translation API Key"
+        case .openAICompatibleApiKey: return "OpenAI-Compatible API Key"
+        }
+    }
+}
+
+// MARK: - Typed Secrets
+
+extension KeychainService {
+
+    /// Returns the stored secret, or `nil` when none is saved.
+    ///
+    /// - Throws: `KeychainError` for failures other than a missing item.
+    public func secret(_ key: SecretKey) throws -> String? {
+        do {
+            return try readKey(forService: key.service)
+        } catch KeychainError.itemNotFound {
+            return nil
+        }
+    }
+
+    /// Saves `value` for `key`. A `nil` or blank value clears the secret instead.
+    ///
+    /// - Throws: `KeychainError` on failure. Clearing a secret that is not saved is not an error.
+    public func setSecret(_ value: String?, for key: SecretKey) throws {
+        let trimmed = value?.trimmingCharacters(in: .whitespacesAndNewlines) ?? ""
+        guard !trimmed.isEmpty else {
+            try clearSecret(key)
+            return
+        }
+        try saveKey(trimmed, forService: key.service)
+    }
+
+    /// Deletes the secret for `key`, if any.
+    public func clearSecret(_ key: SecretKey) throws {
+        do {
+            try deleteKey(forService: key.service)
+        } catch KeychainError.itemNotFound {
+            // Already cleared
+        }
+    }
+
+    /// Whether a secret is saved for `key`. Keychain errors count as "not saved".
+    public func hasSecret(_ key: SecretKey) -> Bool {
+        (try? secret(key)) != nil
+    }
+}
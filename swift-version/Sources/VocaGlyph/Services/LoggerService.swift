@@ -6,8 +6,8 @@ import SwiftUI
 private let vocaGlyphLogsDir: URL = {
     // ~/Library/Logs/VocaGlyph/ is the Apple-designated app log location.
     // No Full Disk Access required; logs appear automatically in Console.app.
-    let dir = FileManager.default.urls(for: .libraryDirectory, in: .userDomainMask)[0]
-        .appendingPathComponent("Logs/VocaGlyph", isDirectory: true)
+    // A `--data-dir` / VOCAGLYPH_DATA_DIR override moves them to `<root>/Logs/`.
+    let dir = AppDirs.current.logs
     try? FileManager.default.createDirectory(at: dir, withIntermediateDirectories: true)
     return dir
 }()
@@ -17,11 +17,14 @@ private let vocaGlyphLogsDir: URL = {
 /// A thread-safe, append-only log file.
 private final class LogFile: @unchecked Sendable {
     let url: URL
+    /// Post a `LogLineEvent` for every line written, for the in-app log viewer.
+    private let postsLines: Bool
     private let queue: DispatchQueue
     private let dateFormatter: DateFormatter
 
-    init(filename: String, queueLabel: String) {
+    init(filename: String, queueLabel: String, postsLines: Bool = false) {
         url = vocaGlyphLogsDir.appendingPathComponent(filename)
+        self.postsLines = postsLines
         queue = DispatchQueue(label: queueLabel, qos: .utility)
         dateFormatter = DateFormatter()
         dateFormatter.dateFormat = "yyyy-MM-dd HH:mm:ss.SSS"
@@ -31,18 +34,24 @@ private final class LogFile: @unchecked Sendable {
         }
     }
 
-    func write(level: String, message: String) {
-        let timestamp = dateFormatter.string(from: Date())
-        let line = "[\(timestamp)] [\(level)] \(message)\n"
+    func write(level: LogLevel, component: LogComponent?, message: String) {
+        // Each component logs from its `logLevels` entry; the rest log DEBUG only
+        // with the debug-logging flag. INFO and ERROR surface unless turned down.
+        let settings = SettingsStore.shared.settings
+        let component = component ?? LogComponent.component(forMessage: message)
+        guard LogLevels(settings: settings).allows(level, for: component) else { return }
 
-        // DEBUG is gated behind the debug-logging flag; INFO and ERROR always surface
-        let isDebug = level == "DEBUG"
-        let debugEnabled = UserDefaults.standard.bool(forKey: "enableDebugLogging")
-        guard !isDebug || debugEnabled else { return }
+        let timestamp = dateFormatter.string(from: Date())
+        let line = "[\(timestamp)] [\(level.label)] [\(component.rawValue)] \(TextRedactor.redactForLog(message, settings: settings))\n"
 
         // Print to console
         print(line, terminator: "")
 
+        if postsLines {
+            let event = LogLineEvent(line: String(line.dropLast()))
+            DispatchQueue.main.async { event.post() }
+        }
+
         // Write to disk
         guard let data = line.data(using: .utf8) else { return }
         queue.async { [url] in
@@ -62,6 +71,19 @@ private final class LogFile: @unchecked Sendable {
             try? FileManager.default.removeItem(at: url)
         }
     }
+
+    /// The last `count` lines, read after any pending writes. Only the final
+    /// `maxBytes` of the file are read, so very long lines can shorten the result.
+    func tail(_ count: Int, maxBytes: UInt64 = 512 * 1024) -> [String] {
+        queue.sync { [url] in
+            guard let handle = try? FileHandle(forReadingFrom: url) else { return [] }
+            defer { try? handle.close() }
+            let size = handle.seekToEndOfFile()
+            handle.seek(toFileOffset: size > maxBytes ? size - maxBytes : 0)
+            let text = String(decoding: handle.readDataToEndOfFile(), as: UTF8.self)
+            return Logger.lastLines(of: text, count: count, startsMidLine: size > maxBytes)
+        }
+    }
 }
 
 // MARK: - Logger (general app + transcription logs → vocaglyph.log)
@@ -71,25 +93,50 @@ class Logger {
 
     private let file = LogFile(
         filename: "vocaglyph.log",
-        queueLabel: "com.vocaglyph.logger"
+        queueLabel: "com.vocaglyph.logger",
+        postsLines: true
     )
 
     private init() {
         info("=== Application Started ===")
     }
 
-    func info(_ message: String)  { file.write(level: "INFO ", message: message) }
-    func error(_ message: String) { file.write(level: "ERROR", message: message) }
-    func debug(_ message: String) { file.write(level: "DEBUG", message: message) }
+    /// Without `component`, it is taken from the message's `"TypeName: "` prefix —
+    /// see `LogComponent.component(forMessage:)`.
+    func info(_ message: String, component: LogComponent? = nil)  { file.write(level: .info, component: component, message: message) }
+    func error(_ message: String, component: LogComponent? = nil) { file.write(level: .error, component: component, message: message) }
+    func debug(_ message: String, component: LogComponent? = nil) { file.write(level: .debug, component: component, message: message) }
 
     func clearLogs() { file.clear() }
     func getLogFileURL() -> URL  { file.url }
+
+    /// The last `count` lines of the log, oldest first, for the in-app log viewer.
+    /// Lines written afterwards arrive as `LogLineEvent`s.
+    func tail(lines count: Int) -> [String] { file.tail(count) }
+
+    /// The last `count` non-empty lines of `text`. With `startsMidLine` the first line
+    /// is a fragment and is dropped.
+    static func lastLines(of text: String, count: Int, startsMidLine: Bool = false) -> [String] {
+        var lines = text.split(separator: "\n", omittingEmptySubsequences: false).map(String.init)
+        if startsMidLine, !lines.isEmpty { lines.removeFirst() }
+        return Array(lines.filter { !$0.isEmpty }.suffix(max(count, 0)))
+    }
+
+    /// Wrap dictated text (and prompts built from it) in this before interpolating it into
+    /// a log line. It is rendered per the `transcriptLogging` setting; while incognito
+    /// mode is on only the length is logged.
+    static func transcript(_ text: String) -> String {
+        let settings = SettingsStore.shared.settings
+        guard !settings.incognitoModeEnabled else { return "<\(text.count) chars hidden — incognito>" }
+        return (TranscriptLogging(rawValue: settings.transcriptLogging) ?? .full).render(text)
+    }
 }
 
 // MARK: - PostProcessingLogger (API / local-model logs → postprocessing.log)
 
 /// Dedicated logger for post-processing engines.
 /// Writes to `~/.VocaGlyph/logs/postprocessing.log` independently of the main log.
+/// Its lines belong to the `model` log component.
 class PostProcessingLogger {
     static let shared = PostProcessingLogger()
 
@@ -100,9 +147,9 @@ class PostProcessingLogger {
 
     private init() {}
 
-    func info(_ message: String)  { file.write(level: "INFO ", message: message) }
-    func error(_ message: String) { file.write(level: "ERROR", message: message) }
-    func debug(_ message: String) { file.write(level: "DEBUG", message: message) }
+    func info(_ message: String)  { file.write(level: .info, component: .model, message: message) }
+    func error(_ message: String) { file.write(level: .error, component: .model, message: message) }
+    func debug(_ message: String) { file.write(level: .debug, component: .model, message: message) }
 
     func clearLogs() { file.clear() }
     func getLogFileURL() -> URL  { file.url }
@@ -0,0 +1,138 @@
+import AppKit
+import ServiceManagement
+
+// MARK: - LoginItemRegistering
+
+/// The parts of `SMAppService` that `LoginItemService` uses; mocked in tests.
+protocol LoginItemRegistering {
+    var status: SMAppService.Status { get }
+    func register() throws
+    func unregister() throws
+}
+
+extension SMAppService: LoginItemRegistering {}
+
+// MARK: - LoginItemService
+
+/// Launch at Login through `SMAppService.mainApp`, so VocaGlyph is listed under System
+/// Settings → General → Login Items and keeps launching after the app is moved.
+///
+/// Before `SMAppService`, the usual way to do this was a per-user LaunchAgent in
+/// `~/Library/LaunchAgents`. Such an agent is invisible in Login Items and breaks when
+/// the app moves, so `migrateLegacyLaunchAgents()` replaces it with the `SMAppService`
+/// registration and deletes the plist. Agents are recognised by the program they run,
+/// not by label, since any label may have been used.
+final class LoginItemService {
+
+    /// Marks a login launch when VocaGlyph is started by a LaunchAgent or script.
+    static let launchedAtLoginArgument = "--launched-at-login"
+
+    static let defaultLaunchAgentsDirectory = FileManager.default
+        .homeDirectoryForCurrentUser
+        .appendingPathComponent("Library/LaunchAgents", isDirectory: true)
+
+    private let app: LoginItemRegistering
+    private let launchAgentsDirectory: URL
+    private let fileManager: FileManager
+
+    init(app: LoginItemRegistering = SMAppService.mainApp,
+         launchAgentsDirectory: URL = LoginItemService.defaultLaunchAgentsDirectory,
+         fileManager: FileManager = .default) {
+        self.app = app
+        self.launchAgentsDirectory = launchAgentsDirectory
+        self.fileManager = fileManager
+    }
+
+    var isEnabled: Bool { app.status == .enabled }
+
+    /// Registered, but the user switched VocaGlyph off in Login Items; only they can
+    /// turn it back on there — see `openLoginItemsSettings()`.
+    var requiresApproval: Bool { app.status == .requiresApproval }
+
+    func setEnabled(_ enabled: Bool) throws {
+        guard enabled != isEnabled else { return }
+        if enabled {
+            try app.register()
+        } else {
+            try app.unregister()
+        }
+        Logger.shared.info("LoginItemService: Launch at Login \(enabled ? "enabled" : "disabled")")
+    }
+
+    func openLoginItemsSettings() {
+        SMAppService.openSystemSettingsLoginItems()
+    }
+
+    /// Whether `event` — by default the launch event being handled — says VocaGlyph was
+    /// opened as a login item. `SMAppService` registrations can't carry launch arguments,
+    /// but macOS marks the open-application event instead. Only meaningful during
+    /// `applicationDidFinishLaunching`.
+    static func isLoginLaunch(_ event: NSAppleEventDescriptor? = NSAppleEventManager.shared().currentAppleEvent) -> Bool {
+        guard let event, event.eventID == AEEventID(kAEOpenApplication) else { return false }
+        return event.paramDescriptor(forKeyword: AEKeyword(keyAEPropData))?.enumCodeValue == OSType(keyAELaunchedAsLogInItem)
+    }
+
+    // MARK: - Legacy LaunchAgents
+
+    /// Plists in `launchAgentsDirectory` whose program is VocaGlyph.
+    func legacyLaunchAgents() -> [URL] {
+        let files = (try? fileManager.contentsOfDirectory(at: launchAgentsDirectory, includingPropertiesForKeys: nil)) ?? []
+        return files
+            .filter { $0.pathExtension == "plist" }
+            .filter { url in
+                guard let data = try? Data(contentsOf: url),
+                      let plist = try? PropertyListSerialization.propertyList(from: data, format: nil) as? [String: Any] else {
+                    return false
+                }
+                return Self.launchesVocaGlyph(plist)
+            }
+            .sorted { $0.lastPathComponent < $1.lastPathComponent }
+    }
+
+    /// Replaces legacy LaunchAgents with the `SMAppService` login item. An agent that ran
+    /// at load turns Launch at Login on; the plists are removed only once that worked,
+    /// so a failed registration leaves the old behaviour in place. Returns `true` when
+    /// any plist was removed.
+    @discardableResult
+    func migrateLegacyLaunchAgents() -> Bool {
+        let agents = legacyLaunchAgents()
+        guard !agents.isEmpty else { return false }
+
+        let ranAtLoad = agents.contains { url in
+            let plist = (try? Data(contentsOf: url))
+                .flatMap { try? PropertyListSerialization.propertyList(from: $0, format: nil) as? [String: Any] }
+            return plist?["RunAtLoad"] as? Bool ?? false
+        }
+        if ranAtLoad {
+            do {
+                try setEnabled(true)
+            } catch {
+                Logger.shared.error("LoginItemService: Keeping legacy LaunchAgent — registration failed: \(error.localizedDescription)")
+                return false
+            }
+        }
+
+        var removed = false
+        for url in agents {
+            do {
+                try fileManager.removeItem(at: url)
+                removed = true
+                Logger.shared.info("LoginItemService: Migrated legacy LaunchAgent \(url.lastPathComponent)")
+            } catch {
+                Logger.shared.error("LoginItemService: Could not remove \(url.lastPathComponent): \(error.localizedDescription)")
+            }
+        }
+        return removed
+    }
+
+    /// Whether a LaunchAgent plist runs a VocaGlyph executable, via `Program` or
+    /// `ProgramArguments` (`open -a VocaGlyph` counts too).
+    static func launchesVocaGlyph(_ plist: [String: Any]) -> Bool {
+        var commandLine: [String] = []
+        if let program = plist["Program"] as? String { commandLine.append(program) }
+        commandLine += plist["ProgramArguments"] as? [String] ?? []
+        return commandLine.contains { argument in
+            argument.contains("VocaGlyph.app") || (argument as NSString).lastPathComponent == "VocaGlyph"
+        }
+    }
+}
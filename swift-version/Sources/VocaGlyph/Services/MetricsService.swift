@@ -0,0 +1,262 @@
+import Foundation
+
+// MARK: - Histogram
+
+/// Counts of values per bucket. `bounds` are the buckets' inclusive upper limits in
+/// ascending order; one more bucket holds everything above the last.
+struct Histogram: Codable, Equatable {
+    let bounds: [Double]
+    private(set) var counts: [Int]
+    private(set) var count = 0
+    private(set) var sum = 0.0
+    private(set) var max = 0.0
+
+    init(bounds: [Double]) {
+        self.bounds = bounds
+        counts = Array(repeating: 0, count: bounds.count + 1)
+    }
+
+    mutating func record(_ value: Double) {
+        let bucket = bounds.firstIndex { value <= $0 } ?? bounds.count
+        counts[bucket] += 1
+        count += 1
+        sum += value
+        max = Swift.max(max, value)
+    }
+
+    var mean: Double? { count > 0 ? sum / Double(count) : nil }
+
+    /// Upper limit of the bucket holding the `p`-th percentile (0…1), or `max` when that
+    /// is the open-ended bucket. `nil` when empty.
+    func percentile(_ p: Double) -> Double? {
+        guard count > 0 else { return nil }
+        let rank = Swift.max(1, Int((p * Double(count)).rounded(.up)))
+        var seen = 0
+        for (bucket, bucketCount) in counts.enumerated() {
+            seen += bucketCount
+            if seen >= rank { return bucket < bounds.count ? Swift.min(bounds[bucket], max) : max }
+        }
+        return max
+    }
+}
+
+// MARK: - MetricsStage
+
+/// The steps of one dictation after the recording stops.
+enum MetricsStage: String, CaseIterable, Codable {
+    /// Recording stopped → transcription starts (audio hand-off, a live preview finishing).
+    case queue
+    /// The transcription engine.
+    case decode
+    /// Text pipeline and summary.
+    case processing
+    /// Handing the text to `OutputService`.
+    case output
+    /// Recording stopped → output.
+    case total
+}
+
+// MARK: - MetricsSpan
+
+/// One stage of one dictation, with its timestamps for tracing.
+struct MetricsSpan: Equatable {
+    /// Shared by the stages of one dictation.
+    let trace: UUID
+    let stage: MetricsStage
+    let start: Date
+    let end: Date
+
+    var seconds: TimeInterval { end.timeIntervalSince(start) }
+}
+
+// MARK: - MetricsSnapshot
+
+/// Everything `MetricsService` has measured since launch. Returned by the control API's
+/// `metrics` command and posted with `MetricsUpdatedEvent`.
+struct MetricsSnapshot: Codable, Equatable {
+    /// Seconds per stage, keyed by `MetricsStage` raw value.
+    var latency: [String: Histogram]
+    /// Decode time divided by audio length; below 1 is faster than real time.
+    var realTimeFactor: Histogram
+    /// Dictations being transcribed right now.
+    var queueDepth: Int
+    /// Queue depth seen by each dictation as it arrived, itself included.
+    var queueDepthSeen: Histogram
+    var transcriptions: Int
+    /// Median real-time factor of the last few dictations, when it is slow enough to warn.
+    var slowTranscriptionFactor: Double?
+
+    func latency(_ stage: MetricsStage) -> Histogram? { latency[stage.rawValue] }
+}
+
+// MARK: - MetricsService
+
+/// Per-stage latency, real-time factor and queue depth of dictations, kept in memory for
+/// this session. `AppStateManager` records into it; `AppDelegate` publishes a
+/// `MetricsUpdatedEvent` every few seconds when something changed, and uses
+/// `slowTranscriptionFactor` for the "slow transcription" status.
+///
+/// Thread-safe: dictations are processed off the main thread.
+final class MetricsService: @unchecked Sendable {
+
+    static let shared = MetricsService()
+
+    /// Seconds.
+    static let latencyBounds: [Double] = [0.1, 0.25, 0.5, 1, 2, 5, 10]
+    static let realTimeFactorBounds: [Double] = [0.05, 0.1, 0.25, 0.5, 1, 2]
+    static let queueDepthBounds: [Double] = [1, 2, 3, 5]
+
+    /// A median real-time factor above this over the last `slowWindow` dictations
+    /// raises the warning; at least `slowMinimumSamples` are needed.
+    static let slowThreshold = 1.0
+    static let slowWindow = 5
+    static let slowMinimumSamples = 3
+
+    private let lock = NSLock()
+    private var latency: [MetricsStage: Histogram] = [:]
+    private var realTimeFactor = Histogram(bounds: MetricsService.realTimeFactorBounds)
+    private var recentRealTimeFactors: [Double] = []
+    private var queueDepth = 0
+    private var queueDepthSeen = Histogram(bounds: MetricsService.queueDepthBounds)
+    private var transcriptions = 0
+    private var revision = 0
+    private var publishedRevision = 0
+    private var timer: Timer?
+    private var spanObserver: ((MetricsSpan) -> Void)?
+
+    init() {}
+
+    deinit {
+        timer?.invalidate()
+    }
+
+    // MARK: - Recording
+
+    /// A dictation was handed over for transcription. Pair with `endTranscription()`.
+    func beginTranscription() {
+        update {
+            queueDepth += 1
+            queueDepthSeen.record(Double(queueDepth))
+        }
+    }
+
+    func endTranscription() {
+        update {
+            queueDepth = Swift.max(0, queueDepth - 1)
+            transcriptions += 1
+        }
+    }
+
+    /// Adds the span's duration to its stage and passes it to the span observer.
+    func record(_ span: MetricsSpan) {
+        var observer: ((MetricsSpan) -> Void)?
+        update {
+            latency[span.stage, default: Histogram(bounds: Self.latencyBounds)].record(span.seconds)
+            observer = spanObserver
+        }
+        observer?(span)
+    }
+
+    /// Records a `decode` span and the real-time factor for `audioSeconds` of audio.
+    func recordDecode(_ span: MetricsSpan, audioSeconds: TimeInterval) {
+        record(span)
+        guard audioSeconds > 0 else { return }
+        let factor = span.seconds / audioSeconds
+        update {
+            realTimeFactor.record(factor)
+            recentRealTimeFactors.append(factor)
+            if recentRealTimeFactors.count > Self.slowWindow { recentRealTimeFactors.removeFirst() }
+        }
+    }
+
+    /// Forgets the recent real-time factors, so a slow model's warning doesn't carry
+    /// over to the next one. The histograms keep the whole session.
+    func modelDidChange() {
+        update { recentRealTimeFactors = [] }
+    }
+
+    func reset() {
+        update {
+            latency = [:]
+            realTimeFactor = Histogram(bounds: Self.realTimeFactorBounds)
+            recentRealTimeFactors = []
+            queueDepthSeen = Histogram(bounds: Self.queueDepthBounds)
+            transcriptions = 0
+        }
+    }
+
+    /// Receives every recorded span, on the thread that recorded it; `nil` stops.
+    /// Used by `OTLPExporter` for traces.
+    func observeSpans(_ observer: ((MetricsSpan) -> Void)?) {
+        lock.lock()
+        spanObserver = observer
+        lock.unlock()
+    }
+
+    // MARK: - Reading
+
+    func snapshot() -> MetricsSnapshot {
+        lock.lock()
+        defer { lock.unlock() }
+        return MetricsSnapshot(
+            latency: Dictionary(uniqueKeysWithValues: latency.map { ($0.key.rawValue, $0.value) }),
+            realTimeFactor: realTimeFactor,
+            queueDepth: queueDepth,
+            queueDepthSeen: queueDepthSeen,
+            transcriptions: transcriptions,
+            slowTranscriptionFactor: Self.slowFactor(recentRealTimeFactors)
+        )
+    }
+
+    /// The warning for the current model, as in `MetricsSnapshot.slowTranscriptionFactor`.
+    var slowTranscriptionFactor: Double? {
+        lock.lock()
+        defer { lock.unlock() }
+        return Self.slowFactor(recentRealTimeFactors)
+    }
+
+    /// Median of `factors` when there are enough of them and it is above `slowThreshold`.
+    static func slowFactor(_ factors: [Double]) -> Double? {
+        guard factors.count >= slowMinimumSamples else { return nil }
+        let sorted = factors.sorted()
+        let median = sorted.count.isMultiple(of: 2)
+            ? (sorted[sorted.count / 2 - 1] + sorted[sorted.count / 2]) / 2
+            : sorted[sorted.count / 2]
+        return median > slowThreshold ? median : nil
+    }
+
+    // MARK: - Publishing
+
+    /// Posts `MetricsUpdatedEvent` every `interval` seconds when something changed.
+    /// Call on the main thread.
+    func startPublishing(interval: TimeInterval = 5) {
+        guard timer == nil else { return }
+        timer = Timer.scheduledTimer(withTimeInterval: interval, repeats: true) { [weak self] _ in
+            self?.publishIfChanged()
+        }
+    }
+
+    func stopPublishing() {
+        timer?.invalidate()
+        timer = nil
+    }
+
+    /// Returns `true` when an event was posted.
+    @discardableResult
+    func publishIfChanged(to center: NotificationCenter = .default) -> Bool {
+        lock.lock()
+        let changed = revision != publishedRevision
+        publishedRevision = revision
+        lock.unlock()
+        guard changed else { return false }
+        MetricsUpdatedEvent(snapshot: snapshot()).post(from: self, to: center)
+        return true
+    }
+
+    private func update(_ change: () -> Void) {
+        lock.lock()
+        change()
+        revision += 1
+        lock.unlock()
+    }
+}
@@ -296,14 +296,19 @@ final class MicrophoneService {
     @objc private func handleDeviceDisconnected(_ notification: Notification) {
         Task { @MainActor in
             Logger.shared.info("MicrophoneService: Audio device disconnected — refreshing list.")
-            self.refreshDevices(reason: "audio-device-disconnected")
-            // If the disconnected device was selected, fall back to system default.
-            if let uid = self.selectedUID, !uid.isEmpty {
-                let stillAvailable = self.availableInputs.contains { $0.uid == uid }
-                if !stillAvailable {
-                    Logger.shared.info("MicrophoneService: Selected device disappeared — reverting to system default.")
-                    self.selectedUID = nil
-                }
+            self.revalidate(reason: "audio-device-disconnected")
+        }
+    }
+
+    /// Re-scans the devices and, if the selected one is gone, falls back to the system
+    /// default. Used after a disconnect and after the Mac wakes from sleep.
+    func revalidate(reason: String) {
+        refreshDevices(reason: reason)
+        if let uid = selectedUID, !uid.isEmpty {
+            let stillAvailable = availableInputs.contains { $0.uid == uid }
+            if !stillAvailable {
+                Logger.shared.info("MicrophoneService: Selected device disappeared — reverting to system default.")
+                selectedUID = nil
             }
         }
     }
@@ -0,0 +1,149 @@
+import Foundation
+import CryptoKit
+
+// MARK: - ModelManifest
+
+/// Expected size and SHA256 of every file in a downloaded model folder. Written by
+/// `ModelDownloadService` as `.manifest.json` inside the folder, from HuggingFace's LFS
+/// metadata, and checked by `ModelChecksumVerifier`.
+struct ModelManifest: Codable, Equatable {
+
+    struct Entry: Codable, Equatable {
+        /// Path relative to the model folder, e.g. `AudioEncoder.mlmodelc/weights/weight.bin`.
+        var path: String
+        var size: Int64
+        /// Lower-case hex SHA256. `nil` for small files HuggingFace does not store in LFS.
+        var sha256: String?
+        /// When the file last matched `sha256`. Files modified after this are hashed again.
+        var verifiedAt: Date?
+    }
+
+    static let fileName = ".manifest.json"
+
+    var entries: [Entry]
+
+    static func load(from modelFolder: URL) -> ModelManifest? {
+        guard let data = try? Data(contentsOf: modelFolder.appendingPathComponent(fileName)) else { return nil }
+        let decoder = JSONDecoder()
+        decoder.dateDecodingStrategy = .iso8601
+        return try? decoder.decode(ModelManifest.self, from: data)
+    }
+
+    func write(to modelFolder: URL) throws {
+        let encoder = JSONEncoder()
+        encoder.dateEncodingStrategy = .iso8601
+        encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
+        try encoder.encode(self).write(to: modelFolder.appendingPathComponent(Self.fileName), options: .atomic)
+    }
+}
+
+// MARK: - ModelVerificationResult
+
+enum ModelVerificationResult: Equatable {
+    case valid
+    /// Manifest paths that are missing, the wrong size, or fail their checksum.
+    case corrupt([String])
+    /// No manifest — the model was downloaded before checksums were tracked.
+    case unverified
+}
+
+// MARK: - ModelChecksumVerifier
+
+/// Checks a downloaded model folder against its `ModelManifest`. A truncated or damaged
+/// CoreML weight file otherwise loads as garbage or crashes WhisperKit.
+///
+/// Hashing a 3 GB model takes several seconds, so files whose modification date is not
+/// newer than their `verifiedAt` are only size-checked. Call off the main thread.
+enum ModelChecksumVerifier {
+
+    /// Bytes hashed per read.
+    private static let chunkSize = 4 << 20
+
+    /// Smallest plausible encoder or decoder `weights/weight.bin`. The smallest shipped
+    /// model's are several MB; anything under this is truncated or a placeholder.
+    static let minimumWeightBytes: Int64 = 1 << 20
+
+    /// Components whose weights are checked against `minimumWeightBytes`. The
+    /// MelSpectrogram weights are legitimately tiny.
+    private static let weightedComponents = ["AudioEncoder.mlmodelc", "TextDecoder.mlmodelc"]
+
+    /// Fast structural check run before every load, without hashing: each component in
+    /// `ModelRegistry.requiredComponents` has a non-empty `coremldata.bin`, and the encoder
+    /// and decoder weights are at least `minimumWeightBytes` and not ggml/GGUF files.
+    /// Returns the offending paths relative to the folder; empty means the layout is sound.
+    static func structuralProblems(modelFolder: URL) -> [String] {
+        var problems: [String] = []
+        for component in ModelRegistry.requiredComponents {
+            let path = "\(component)/coremldata.bin"
+            if (fileSize(modelFolder.appendingPathComponent(path)) ?? 0) == 0 {
+                problems.append(path)
+            }
+        }
+        for component in weightedComponents {
+            let path = "\(component)/weights/weight.bin"
+            let url = modelFolder.appendingPathComponent(path)
+            if (fileSize(url) ?? 0) < minimumWeightBytes || ModelRegistry.isGGML(url) {
+                problems.append(path)
+            }
+        }
+        return problems
+    }
+
+    private static func fileSize(_ url: URL) -> Int64? {
+        guard let attributes = try? FileManager.default.attributesOfItem(atPath: url.path) else { return nil }
+        return (attributes[.size] as? NSNumber)?.int64Value
+    }
+
+    static func verify(modelFolder: URL, now: Date = Date()) -> ModelVerificationResult {
+        guard var manifest = ModelManifest.load(from: modelFolder) else { return .unverified }
+
+        var corrupt: [String] = []
+        var updated = false
+        for index in manifest.entries.indices {
+            let entry = manifest.entries[index]
+            let url = modelFolder.appendingPathComponent(entry.path)
+            guard let attributes = try? FileManager.default.attributesOfItem(atPath: url.path),
+                  (attributes[.size] as? NSNumber)?.int64Value == entry.size else {
+                corrupt.append(entry.path)
+                continue
+            }
+            guard let expected = entry.sha256 else { continue }
+
+            let modified = attributes[.modificationDate] as? Date ?? now
+            if let verifiedAt = entry.verifiedAt, modified <= verifiedAt { continue }
+
+            if (try? sha256(of: url)) == expected {
+                manifest.entries[index].verifiedAt = now
+                updated = true
+            } else {
+                corrupt.append(entry.path)
+            }
+        }
+
+        if updated {
+            try? manifest.write(to: modelFolder)
+        }
+        return corrupt.isEmpty ? .valid : .corrupt(corrupt)
+    }
+
+    /// Lower-case hex git blob SHA1 of the file at `url` — the `oid` HuggingFace lists
+    /// for files it keeps in git rather than LFS. Those are small, so it is read at once.
+    static func gitBlobSHA1(of url: URL) throws -> String {
+        let contents = try Data(contentsOf: url)
+        var hasher = Insecure.SHA1()
+        hasher.update(data: Data("blob \(contents.count)\0".utf8))
+        hasher.update(data: contents)
+        return hasher.finalize().map { String(format: "%02x", $0) }.joined()
+    }
+
+    /// Lower-case hex SHA256 of the file at `url`, read in chunks.
+    static func sha256(of url: URL) throws -> String {
+        let handle = try FileHandle(forReadingFrom: url)
+        defer { try? handle.close() }
+        var hasher = SHA256()
+        while let chunk = try handle.read(upToCount: chunkSize), !chunk.isEmpty {
+            hasher.update(data: chunk)
+        }
+        return hasher.finalize().map { String(format: "%02x", $0) }.joined()
+    }
+}
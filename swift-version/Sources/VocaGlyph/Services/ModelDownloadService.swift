@@ -0,0 +1,584 @@
+import Foundation
+
+// MARK: - ModelDownloadFile
+
+/// One file of a model folder, as listed by the HuggingFace tree API.
+struct ModelDownloadFile: Equatable {
+    /// Path relative to the repo root, e.g. `openai_whisper-large-v3/AudioEncoder.mlmodelc/weights/weight.bin`.
+    var path: String
+    var size: Int64
+    /// Lower-case hex SHA256 from the LFS pointer; `nil` for small non-LFS files.
+    var sha256: String? = nil
+    /// Lower-case hex git blob SHA1, checked for files without `sha256`.
+    var gitSHA1: String? = nil
+}
+
+// MARK: - ModelDownloadProgress
+
+struct ModelDownloadProgress: Equatable {
+    /// Bytes of the whole model on disk, including files and partial files from earlier runs.
+    var bytesReceived: Int64
+    var totalBytes: Int64
+    /// Byte offset this run resumed from, or `nil` when the download started from scratch.
+    var resumedFromByte: Int64?
+    /// Host currently serving the files, e.g. "huggingface.co" or "hf-mirror.com".
+    var source: String = ""
+
+    var fractionCompleted: Double {
+        guard totalBytes > 0 else { return 0 }
+        return min(Double(bytesReceived) / Double(totalBytes), 1)
+    }
+}
+
+// MARK: - PartialDownload
+
+/// A model folder an earlier run left unfinished, found by `ModelDownloadService.findPartialDownloads(under:)`.
+struct PartialDownload: Equatable {
+    var folder: URL
+    /// Bytes already on disk, complete and partial files together.
+    var bytesOnDisk: Int64
+    /// Size of the whole model, recorded in the `.incomplete` marker; `nil` for markers
+    /// written before the size was recorded.
+    var totalBytes: Int64?
+
+    var fractionCompleted: Double {
+        guard let totalBytes, totalBytes > 0 else { return 0 }
+        return min(Double(bytesOnDisk) / Double(totalBytes), 1)
+    }
+}
+
+// MARK: - ModelDownloadError
+
+enum ModelDownloadError: LocalizedError, Equatable {
+    case listingFailed(statusCode: Int)
+    case emptyModel(String)
+    case httpError(statusCode: Int, path: String)
+    case sizeMismatch(path: String, expected: Int64, actual: Int64)
+    case insufficientDiskSpace(required: Int64, available: Int64)
+    case checksumMismatch(path: String)
+    case noMirrors
+    case accessDenied(repo: String)
+
+    var errorDescription: String? {
+        switch self {
+        case .listingFailed(let statusCode):
+            return "Could not list model files (HTTP \(statusCode))."
+        case .emptyModel(let folder):
+            return "No files found for '\(folder)'."
+        case .httpError(let statusCode, let path):
+            return "Download of \(path) failed (HTTP \(statusCode))."
+        case .sizeMismatch(let path, let expected, let actual):
+            return "\(path) is \(actual) bytes, expected \(expected)."
+        case .insufficientDiskSpace(let required, let available):
+            return "Not enough disk space: need \(DiskSpace.format(required)), have \(DiskSpace.format(available))."
+        case .checksumMismatch(let path):
+            return "\(path) failed its SHA256 check."
+        case .noMirrors:
+            return "No download sources are configured."
+        case .accessDenied(let repo):
+            return "\(repo) is gated or private. Add a HuggingFace access token in Settings and accept the model's terms on huggingface.co."
+        }
+    }
+}
+
+// MARK: - ModelDownloadService
+
+/// Downloads a model folder from a HuggingFace repo, file by file, into the same
+/// `models/<repo>/<folder>/` layout `WhisperKit.download` produces.
+///
+/// Each file is streamed to a `<name>.download` temp file next to its final path and
+/// moved into place once complete. When a run is interrupted — dropped connection,
+/// app quit, Task cancelled — the temp file stays on disk and the next run continues
+/// from its last byte with an HTTP `Range` request instead of starting over.
+///
+/// While a run is in progress the model folder contains an `.incomplete` marker, so a
+/// half-downloaded model is never reported as downloaded. Every file is hashed before
+/// being moved into place — against its LFS SHA256, or for small files HuggingFace keeps
+/// in git, its blob SHA1 — and a finished run writes a `ModelManifest`.
+///
+/// Transient failures — dropped connections, stalls (no bytes for `stallTimeout`), 5xx
+/// and 429 responses — are retried up to `maxRetries` times with exponential backoff,
+/// each retry resuming with `Range`. Files can come from several mirrors that expose the
+/// HuggingFace API layout: once a mirror keeps failing, or returns a non-transient error,
+/// the file is resumed from the next mirror, which then serves the rest of the run. The
+/// file list and checksums only ever come from the first source (huggingface.co), so a
+/// mirror can serve bytes but cannot vouch for them.
+///
+/// `accessToken` (a HuggingFace token from the Keychain) is sent as a bearer token to
+/// huggingface.co only — never to third-party mirrors — so gated and private repos download.
+///
+/// `bytesPerSecond` caps the combined rate of all downloads so a background model
+/// download does not starve video calls; it is re-read for every file.
+final class ModelDownloadService: @unchecked Sendable {
+
+    /// HuggingFace first, then its community mirror for when huggingface.co is slow or
+    /// drops file transfers. The listing still has to come from huggingface.co.
+    static let defaultMirrors = [
+        URL(string: "https://huggingface.co")!,
+        URL(string: "https://hf-mirror.com")!
+    ]
+    static let tempFileExtension = "download"
+    static let incompleteMarker = ".incomplete"
+
+    private let session: URLSession
+    private let mirrors: [URL]
+    private let fileManager: FileManager
+    private let availableCapacity: (URL) -> Int64?
+    /// Seconds without receiving data before a mirror counts as stalled.
+    private let stallTimeout: TimeInterval
+    /// Retries of a transient failure on the same mirror before moving on.
+    private let maxRetries: Int
+    /// Delay before the first retry; doubled for each further retry.
+    private let retryBaseDelay: TimeInterval
+    /// HuggingFace access token, read for every request so a newly saved token applies
+    /// to the next file.
+    private let accessToken: () async -> String?
+    /// Current rate cap in bytes per second; 0 means unlimited.
+    private let bytesPerSecond: () -> Int64
+    private let rateLimiter = DownloadRateLimiter()
+    /// Bytes buffered before each write to disk and progress report.
+    private let chunkSize = 1 << 20
+
+    init(session: URLSession = .shared,
+         mirrors: [URL] = ModelDownloadService.defaultMirrors,
+         fileManager: FileManager = .default,
+         stallTimeout: TimeInterval = 30,
+         maxRetries: Int = 3,
+         retryBaseDelay: TimeInterval = 1,
+         accessToken: @escaping () async -> String? = { nil },
+         bytesPerSecond: @escaping () -> Int64 = { 0 },
+         availableCapacity: @escaping (URL) -> Int64? = DiskSpace.availableCapacity(at:)) {
+        self.session = session
+        self.mirrors = mirrors
+        self.fileManager = fileManager
+        self.stallTimeout = stallTimeout
+        self.maxRetries = maxRetries
+        self.retryBaseDelay = retryBaseDelay
+        self.accessToken = accessToken
+        self.bytesPerSecond = bytesPerSecond
+        self.availableCapacity = availableCapacity
+    }
+
+    /// `true` when `folder` holds a download that has not finished.
+    static func isIncomplete(_ folder: URL) -> Bool {
+        FileManager.default.fileExists(atPath: folder.appendingPathComponent(incompleteMarker).path)
+    }
+
+    // MARK: - Partial Downloads
+
+    /// Scans `root` for unfinished model folders (those with an `.incomplete` marker).
+    /// `.download` temp files outside such folders can never be resumed — `runDownload`
+    /// only resumes inside an incomplete folder — so they are deleted on the way. Returns
+    /// the partial folders and the bytes freed.
+    static func findPartialDownloads(under root: URL) -> (partials: [PartialDownload], reclaimedBytes: Int64) {
+        let fileManager = FileManager.default
+        guard let enumerator = fileManager.enumerator(at: root, includingPropertiesForKeys: [.fileSizeKey]) else {
+            return ([], 0)
+        }
+        var incompleteFolders: [URL] = []
+        var tempFiles: [URL] = []
+        for case let url as URL in enumerator {
+            if url.lastPathComponent == incompleteMarker {
+                incompleteFolders.append(url.deletingLastPathComponent())
+            } else if url.pathExtension == tempFileExtension {
+                tempFiles.append(url)
+            }
+        }
+
+        let folderPaths = incompleteFolders.map { $0.standardizedFileURL.path + "/" }
+        var reclaimed: Int64 = 0
+        for file in tempFiles where !folderPaths.contains(where: { file.standardizedFileURL.path.hasPrefix($0) }) {
+            let size = (try? file.resourceValues(forKeys: [.fileSizeKey]).fileSize) ?? 0
+            if (try? fileManager.removeItem(at: file)) != nil {
+                reclaimed += Int64(size)
+                Logger.shared.info("ModelDownloadService: Removed stray temp file \(file.path)")
+            }
+        }
+
+        let partials = incompleteFolders.map { folder in
+            PartialDownload(folder: folder,
+                            bytesOnDisk: DiskSpace.allocatedSize(of: folder),
+                            totalBytes: expectedBytes(ofIncomplete: folder))
+        }
+        return (partials, reclaimed)
+    }
+
+    /// Total model size recorded in `folder`'s `.incomplete` marker.
+    static func expectedBytes(ofIncomplete folder: URL) -> Int64? {
+        guard let data = FileManager.default.contents(atPath: folder.appendingPathComponent(incompleteMarker).path) else {
+            return nil
+        }
+        return Int64(String(decoding: data, as: UTF8.self).trimmingCharacters(in: .whitespacesAndNewlines))
+    }
+
+    // MARK: - Listing
+
+    /// Lists every file under `folder` in `repo` with its size and checksum, from the
+    /// first mirror only: the checksums are what the other mirrors are checked against.
+    func listFiles(repo: String, folder: String, mirrors: [URL]? = nil) async throws -> [ModelDownloadFile] {
+        guard let source = (mirrors ?? self.mirrors).first else { throw ModelDownloadError.noMirrors }
+        do {
+            return try await retrying("listing '\(folder)' on \(Self.sourceName(source))") {
+                try await self.listFiles(repo: repo, folder: folder, from: source)
+            }
+        } catch {
+            Logger.shared.error("ModelDownloadService: Listing '\(folder)' on \(Self.sourceName(source)) failed — \(error.localizedDescription)")
+            throw error
+        }
+    }
+
+    private func listFiles(repo: String, folder: String, from mirror: URL) async throws -> [ModelDownloadFile] {
+        var components = URLComponents(
+            url: mirror.appendingPathComponent("api/models/\(repo)/tree/main/\(folder)"),
+            resolvingAgainstBaseURL: false
+        )!
+        components.queryItems = [URLQueryItem(name: "recursive", value: "true")]
+
+        var request = URLRequest(url: components.url!)
+        request.timeoutInterval = stallTimeout
+        await authorize(&request)
+        let (data, response) = try await session.data(for: request)
+        let statusCode = (response as? HTTPURLResponse)?.statusCode ?? 0
+        switch statusCode {
+        case 200:
+            break
+        case 401, 403:
+            throw ModelDownloadError.accessDenied(repo: repo)
+        default:
+            throw ModelDownloadError.listingFailed(statusCode: statusCode)
+        }
+
+        struct Entry: Decodable {
+            struct LFS: Decodable {
+                /// SHA256 of the file contents (the entry's own `oid` is a git SHA1).
+                let oid: String
+            }
+            let type: String
+            let path: String
+            let size: Int64?
+            /// Git blob SHA1; for LFS files, that of the pointer rather than the contents.
+            let oid: String?
+            let lfs: LFS?
+        }
+        return try JSONDecoder().decode([Entry].self, from: data)
+            .filter { $0.type == "file" }
+            .map { entry in
+                ModelDownloadFile(path: entry.path, size: entry.size ?? 0,
+                                  sha256: entry.lfs?.oid.lowercased(),
+                                  gitSHA1: entry.lfs == nil ? entry.oid?.lowercased() : nil)
+            }
+    }
+
+    /// Builds a manifest for an already-downloaded `folder` — used for models downloaded
+    /// before manifests were written. Entries have no `verifiedAt`, so every file is hashed
+    /// on the next verification.
+    func fetchManifest(repo: String, folder: String, mirrors: [URL]? = nil) async throws -> ModelManifest {
+        let files = try await listFiles(repo: repo, folder: folder, mirrors: mirrors)
+        return ModelManifest(entries: files.map { manifestEntry(for: $0, folder: folder, verifiedAt: nil) })
+    }
+
+    // MARK: - Download
+
+    /// Downloads `folder` from `repo` into `destination/<folder>`, resuming any partial
+    /// files left by an earlier run. `destination` is the repo root (`models/<repo>`).
+    ///
+    /// `mirrors` overrides the service's mirror list for this model.
+    ///
+    /// - Throws: `ModelDownloadError`, URL errors, or `CancellationError` when the Task is
+    ///   cancelled. Partial files are kept in every case so the next call can resume.
+    func runDownload(repo: String,
+                     folder: String,
+                     destination: URL,
+                     mirrors: [URL]? = nil,
+                     progress: @escaping (ModelDownloadProgress) -> Void) async throws {
+        let mirrors = mirrors ?? self.mirrors
+        let files = try await listFiles(repo: repo, folder: folder, mirrors: mirrors)
+        var mirrorIndex = 0
+        guard !files.isEmpty else { throw ModelDownloadError.emptyModel(folder) }
+
+        let totalBytes = files.reduce(Int64(0)) { $0 + $1.size }
+        var completedBytes: Int64 = 0
+        var partialBytes: Int64 = 0
+        var pending: [ModelDownloadFile] = []
+        for file in files {
+            let target = destination.appendingPathComponent(file.path)
+            if fileSize(at: target) == file.size {
+                completedBytes += file.size
+            } else {
+                partialBytes += min(fileSize(at: tempURL(for: target)) ?? 0, file.size)
+                pending.append(file)
+            }
+        }
+
+        let onDisk = completedBytes + partialBytes
+        // Fail before writing anything rather than filling the disk at 95%.
+        let required = totalBytes - onDisk
+        if let available = availableCapacity(destination), available < required {
+            Logger.shared.error("ModelDownloadService: '\(folder)' needs \(required) bytes, \(available) available")
+            throw ModelDownloadError.insufficientDiskSpace(required: required, available: available)
+        }
+
+        let modelFolder = destination.appendingPathComponent(folder, isDirectory: true)
+        try fileManager.createDirectory(at: modelFolder, withIntermediateDirectories: true)
+        // The marker records the model's size so a later launch can show how far an
+        // interrupted download got (see `findPartialDownloads(under:)`).
+        let marker = modelFolder.appendingPathComponent(Self.incompleteMarker)
+        fileManager.createFile(atPath: marker.path, contents: Data(String(totalBytes).utf8))
+
+        let resumedFromByte: Int64? = onDisk > 0 ? onDisk : nil
+        if let resumedFromByte {
+            Logger.shared.info("ModelDownloadService: Resuming '\(folder)' from byte \(resumedFromByte) of \(totalBytes)")
+        } else {
+            Logger.shared.info("ModelDownloadService: Downloading '\(folder)' — \(files.count) file(s), \(totalBytes) bytes")
+        }
+        progress(ModelDownloadProgress(bytesReceived: onDisk, totalBytes: totalBytes,
+                                       resumedFromByte: resumedFromByte, source: Self.sourceName(mirrors[mirrorIndex])))
+
+        var verifiedAt: [String: Date] = [:]
+        for file in pending {
+            // Each mirror gets the file (with retries), starting with the one that last worked.
+            var attempts = 0
+            while true {
+                try Task.checkCancellation()
+                let base = completedBytes
+                let mirror = mirrors[mirrorIndex]
+                let source = Self.sourceName(mirror)
+                do {
+                    try await retrying("\(file.path) from \(source)") {
+                        try await self.downloadFile(file, repo: repo, from: mirror,
+                                                    to: destination.appendingPathComponent(file.path)) { fileBytes in
+                            progress(ModelDownloadProgress(bytesReceived: base + fileBytes,
+                                                           totalBytes: totalBytes,
+                                                           resumedFromByte: resumedFromByte,
+                                                           source: source))
+                        }
+                    }
+                    break
+                } catch {
+                    attempts += 1
+                    guard Self.isMirrorFailure(error), attempts < mirrors.count else { throw error }
+                    mirrorIndex = (mirrorIndex + 1) % mirrors.count
+                    Logger.shared.error("ModelDownloadService: \(source) failed for \(file.path) — \(error.localizedDescription). Switching to \(Self.sourceName(mirrors[mirrorIndex]))")
+                }
+            }
+            verifiedAt[file.path] = Date()
+            completedBytes += file.size
+        }
+
+        // Files skipped as already complete keep their previous verification, if any.
+        let previous = ModelManifest.load(from: modelFolder)
+        let entries = files.map { file -> ModelManifest.Entry in
+            var entry = manifestEntry(for: file, folder: folder, verifiedAt: verifiedAt[file.path])
+            if entry.verifiedAt == nil,
+               let old = previous?.entries.first(where: { $0.path == entry.path }),
+               old.sha256 == entry.sha256, old.size == entry.size {
+                entry.verifiedAt = old.verifiedAt
+            }
+            return entry
+        }
+        try ModelManifest(entries: entries).write(to: modelFolder)
+
+        try? fileManager.removeItem(at: marker)
+        Logger.shared.info("ModelDownloadService: Finished '\(folder)'")
+    }
+
+    /// Streams one file into its `.download` temp file, resuming from the temp file's
+    /// current length, then moves it to `target`.
+    private func downloadFile(_ file: ModelDownloadFile,
+                              repo: String,
+                              from mirror: URL,
+                              to target: URL,
+                              onBytes: (Int64) -> Void) async throws {
+        let temp = tempURL(for: target)
+        try fileManager.createDirectory(at: target.deletingLastPathComponent(), withIntermediateDirectories: true)
+
+        var offset = fileSize(at: temp) ?? 0
+        if offset > file.size {
+            // Left over from a different revision of the file — start over.
+            try? fileManager.removeItem(at: temp)
+            offset = 0
+        }
+
+        if offset < file.size {
+            var request = URLRequest(url: resolveURL(mirror: mirror, repo: repo, path: file.path))
+            // URLSession's timeout is an idle timeout, so this also catches stalled transfers.
+            request.timeoutInterval = stallTimeout
+            await authorize(&request)
+            if offset > 0 {
+                request.setValue("bytes=\(offset)-", forHTTPHeaderField: "Range")
+            }
+
+            let (bytes, response) = try await session.bytes(for: request)
+            let statusCode = (response as? HTTPURLResponse)?.statusCode ?? 0
+            switch statusCode {
+            case 206:
+                break
+            case 200:
+                if offset > 0 {
+                    Logger.shared.info("ModelDownloadService: Server ignored Range for \(file.path) — restarting file")
+                    offset = 0
+                }
+            default:
+                throw ModelDownloadError.httpError(statusCode: statusCode, path: file.path)
+            }
+
+            if !fileManager.fileExists(atPath: temp.path) {
+                fileManager.createFile(atPath: temp.path, contents: nil)
+            }
+            let handle = try FileHandle(forWritingTo: temp)
+            defer { try? handle.close() }
+            try handle.truncate(atOffset: UInt64(offset))
+            try handle.seekToEnd()
+
+            // A capped download flushes smaller chunks (about four per second) so the
+            // rate stays smooth instead of bursting a full chunk then sleeping.
+            let limit = bytesPerSecond()
+            let flushSize = limit > 0 ? max(16 << 10, min(chunkSize, Int(limit / 4))) : chunkSize
+            var written = offset
+            var buffer = Data()
+            buffer.reserveCapacity(flushSize)
+            onBytes(written)
+            for try await byte in bytes {
+                buffer.append(byte)
+                if buffer.count >= flushSize {
+                    try handle.write(contentsOf: buffer)
+                    written += Int64(buffer.count)
+                    onBytes(written)
+                    try await rateLimiter.consume(buffer.count, bytesPerSecond: limit)
+                    buffer.removeAll(keepingCapacity: true)
+                }
+            }
+            if !buffer.isEmpty {
+                try handle.write(contentsOf: buffer)
+                written += Int64(buffer.count)
+                onBytes(written)
+            }
+            offset = written
+        }
+
+        guard offset == file.size else {
+            if offset > file.size { try? fileManager.removeItem(at: temp) }
+            throw ModelDownloadError.sizeMismatch(path: file.path, expected: file.size, actual: offset)
+        }
+
+        if let expected = file.sha256, fileManager.fileExists(atPath: temp.path),
+           try ModelChecksumVerifier.sha256(of: temp) != expected {
+            // A corrupt partial cannot be resumed — the next run starts the file over.
+            try? fileManager.removeItem(at: temp)
+            Logger.shared.error("ModelDownloadService: SHA256 mismatch for \(file.path)")
+            throw ModelDownloadError.checksumMismatch(path: file.path)
+        }
+        if file.sha256 == nil, let expected = file.gitSHA1, fileManager.fileExists(atPath: temp.path),
+           try ModelChecksumVerifier.gitBlobSHA1(of: temp) != expected {
+            try? fileManager.removeItem(at: temp)
+            Logger.shared.error("ModelDownloadService: Git SHA1 mismatch for \(file.path)")
+            throw ModelDownloadError.checksumMismatch(path: file.path)
+        }
+
+        if fileManager.fileExists(atPath: target.path) {
+            try fileManager.removeItem(at: target)
+        }
+        if fileManager.fileExists(atPath: temp.path) {
+            try fileManager.moveItem(at: temp, to: target)
+        } else {
+            // Zero-byte file: nothing was streamed.
+            fileManager.createFile(atPath: target.path, contents: nil)
+        }
+    }
+
+    // MARK: - Helpers
+
+    /// Adds the access token, if any, to requests bound for huggingface.co.
+    private func authorize(_ request: inout URLRequest) async {
+        guard let host = request.url?.host,
+              host == "huggingface.co" || host.hasSuffix(".huggingface.co"),
+              let token = await accessToken(), !token.isEmpty else { return }
+        request.setValue("Bearer \(token)", forHTTPHeaderField: "Authorization")
+    }
+
+    private func resolveURL(mirror: URL, repo: String, path: String) -> URL {
+        mirror.appendingPathComponent("\(repo)/resolve/main/\(path)")
+    }
+
+    /// Runs `operation`, retrying transient failures up to `maxRetries` times after
+    /// `retryDelay(forAttempt:)`. Other errors, and the last transient one, are rethrown.
+    private func retrying<T>(_ description: String, _ operation: () async throws -> T) async throws -> T {
+        var attempt = 0
+        while true {
+            do {
+                return try await operation()
+            } catch {
+                guard Self.isTransient(error), attempt < maxRetries else { throw error }
+                attempt += 1
+                let delay = retryDelay(forAttempt: attempt)
+                Logger.shared.error("ModelDownloadService: \(description) failed — \(error.localizedDescription). Retry \(attempt) of \(maxRetries) in \(String(format: "%.1f", delay))s")
+                try await Task.sleep(nanoseconds: UInt64(delay * 1_000_000_000))
+            }
+        }
+    }
+
+    /// `retryBaseDelay` doubled per attempt, capped at 30 s, plus up to 25% jitter so
+    /// concurrent downloads do not retry in lockstep.
+    private func retryDelay(forAttempt attempt: Int) -> TimeInterval {
+        let delay = min(retryBaseDelay * pow(2, Double(attempt - 1)), 30)
+        return delay + delay * Double.random(in: 0...0.25)
+    }
+
+    /// Failures worth retrying on the same mirror: server errors, rate limiting, streams
+    /// cut short, and dropped or stalled connections.
+    static func isTransient(_ error: Error) -> Bool {
+        if let error = error as? ModelDownloadError {
+            switch error {
+            case .listingFailed(let statusCode), .httpError(let statusCode, _):
+                return statusCode >= 500 || statusCode == 429
+            case .sizeMismatch:
+                return true
+            case .emptyModel, .insufficientDiskSpace, .checksumMismatch, .noMirrors, .accessDenied:
+                return false
+            }
+        }
+        if let error = error as? URLError {
+            return [.networkConnectionLost, .timedOut, .cannotConnectToHost,
+                    .dnsLookupFailed, .notConnectedToInternet].contains(error.code)
+        }
+        return false
+    }
+
+    /// Host shown to the user for `mirror`.
+    static func sourceName(_ mirror: URL) -> String {
+        mirror.host ?? mirror.absoluteString
+    }
+
+    /// Errors that another mirror might not have: HTTP errors, bad data, timeouts and
+    /// network failures. Cancellation and local disk errors are not retried.
+    static func isMirrorFailure(_ error: Error) -> Bool {
+        if let error = error as? ModelDownloadError {
+            switch error {
+            case .listingFailed, .httpError, .sizeMismatch, .checksumMismatch:
+                return true
+            case .emptyModel, .insufficientDiskSpace, .noMirrors, .accessDenied:
+                return false
+            }
+        }
+        if let error = error as? URLError {
+            return error.code != .cancelled
+        }
+        return false
+    }
+
+    private func manifestEntry(for file: ModelDownloadFile, folder: String, verifiedAt: Date?) -> ModelManifest.Entry {
+        let prefix = folder + "/"
+        let path = file.path.hasPrefix(prefix) ? String(file.path.dropFirst(prefix.count)) : file.path
+        return ModelManifest.Entry(path: path, size: file.size, sha256: file.sha256, verifiedAt: verifiedAt)
+    }
+
+    private func tempURL(for target: URL) -> URL {
+        target.appendingPathExtension(Self.tempFileExtension)
+    }
+
+    private func fileSize(at url: URL) -> Int64? {
+        guard let attributes = try? fileManager.attributesOfItem(atPath: url.path),
+              let size = attributes[.size] as? NSNumber else { return nil }
+        return size.int64Value
+    }
+}
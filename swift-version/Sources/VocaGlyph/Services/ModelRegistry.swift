@@ -0,0 +1,222 @@
+import Foundation
+
+// MARK: - CustomModel
+
+/// A WhisperKit CoreML model added by the user — fine-tuned or community models that
+/// are not in the built-in Model tab list. Stored in `models.json`.
+struct CustomModel: Codable, Equatable, Identifiable {
+    /// Id used for `selectedModel`. Always starts with `ModelRegistry.idPrefix` so it can
+    /// never shadow a built-in model.
+    var id: String
+    var title: String
+    var description: String?
+    /// HuggingFace repo hosting the model folder in the WhisperKit layout, e.g.
+    /// "my-org/whisperkit-coreml-finetunes". `nil` for models imported from disk.
+    var repo: String?
+    /// Folder inside `repo`, or inside `models/imported/` for imported models.
+    var folder: String
+    /// Hosts to download `repo` from, in order of preference, e.g.
+    /// ["https://huggingface.co", "https://hf-mirror.com"]. Each must serve the HuggingFace
+    /// API layout. `nil` uses `ModelDownloadService.defaultMirrors`.
+    var mirrors: [String]? = nil
+
+    /// Valid entries of `mirrors`; `nil` when none are set.
+    var mirrorURLs: [URL]? {
+        let urls = (mirrors ?? []).compactMap { URL(string: $0) }.filter { $0.scheme == "https" || $0.scheme == "http" }
+        return urls.isEmpty ? nil : urls
+    }
+}
+
+// MARK: - ModelImportError
+
+enum ModelImportError: LocalizedError, Equatable {
+    case notFound(String)
+    case ggmlNotSupported
+    case missingComponents([String])
+    case invalidID(String)
+    case duplicate(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .notFound(let path):
+            return "Nothing found at \(path)."
+        case .ggmlNotSupported:
+            return "This is a ggml/GGUF model for whisper.cpp. Choose it on the whisper.cpp card instead, or convert it with whisperkittools to import it here."
+        case .missingComponents(let names):
+            return "Not a WhisperKit model folder — missing \(names.joined(separator: ", "))."
+        case .invalidID(let id):
+            return "Custom model ids must start with '\(ModelRegistry.idPrefix)' (got '\(id)')."
+        case .duplicate(let id):
+            return "A model with id '\(id)' already exists."
+        }
+    }
+}
+
+// MARK: - ModelRegistry
+
+/// User extensions to the transcription model list, persisted as `<data root>/models.json`:
+///
+///     { "models": [ { "id": "custom-medical", "title": "Medical (Fine-tuned)",
+///                     "repo": "my-org/whisperkit-medical", "folder": "openai_whisper-medical",
+///                     "mirrors": ["https://huggingface.co", "https://hf-mirror.com"] } ] }
+///
+/// Entries with a `repo` download like built-in models, failing over between `mirrors`. `importModel(from:)` copies or
+/// symlinks a local WhisperKit folder into `models/imported/` and registers it.
+final class ModelRegistry: ObservableObject, @unchecked Sendable {
+
+    static let shared = ModelRegistry()
+
+    static let idPrefix = "custom-"
+    static let fileName = "models.json"
+
+    /// Compiled CoreML bundles WhisperKit needs to load a model folder.
+    static let requiredComponents = ["MelSpectrogram.mlmodelc", "AudioEncoder.mlmodelc", "TextDecoder.mlmodelc"]
+
+    @Published private(set) var customModels: [CustomModel] = []
+
+    /// `<data root>/models` — the same root `WhisperService` downloads into.
+    let modelsRoot: URL
+    let registryURL: URL
+
+    var importedDirectory: URL {
+        modelsRoot.appendingPathComponent("imported", isDirectory: true)
+    }
+
+    private struct RegistryFile: Codable {
+        var models: [CustomModel]
+    }
+
+    /// `dataRoot` defaults to `AppDirs.current.root`.
+    init(dataRoot: URL? = nil) {
+        let dirs = dataRoot.map { AppDirs(root: $0) } ?? .current
+        modelsRoot = dirs.models
+        registryURL = dirs.root.appendingPathComponent(Self.fileName)
+        reload()
+    }
+
+    /// Re-reads `models.json`. Invalid entries are logged and skipped so one typo does
+    /// not hide every custom model.
+    func reload() {
+        guard let data = try? Data(contentsOf: registryURL) else {
+            customModels = []
+            return
+        }
+        do {
+            let file = try JSONDecoder().decode(RegistryFile.self, from: data)
+            var seen = Set<String>()
+            customModels = file.models.filter { model in
+                guard model.id.hasPrefix(Self.idPrefix), seen.insert(model.id).inserted else {
+                    Logger.shared.error("ModelRegistry: Skipping invalid or duplicate id '\(model.id)' in \(Self.fileName)")
+                    return false
+                }
+                return true
+            }
+        } catch {
+            Logger.shared.error("ModelRegistry: Could not parse \(registryURL.path) — \(error.localizedDescription)")
+            customModels = []
+        }
+    }
+
+    func model(id: String) -> CustomModel? {
+        customModels.first { $0.id == id }
+    }
+
+    var customModelIds: Set<String> {
+        Set(customModels.map(\.id))
+    }
+
+    /// Where the model's files live (or will be downloaded to).
+    func folderURL(for model: CustomModel) -> URL {
+        if let repo = model.repo {
+            return modelsRoot.appendingPathComponent(repo, isDirectory: true)
+                .appendingPathComponent(model.folder, isDirectory: true)
+        }
+        return importedDirectory.appendingPathComponent(model.folder, isDirectory: true)
+    }
+
+    // MARK: - Editing
+
+    /// Adds a model hosted on HuggingFace (a custom download URL).
+    func add(_ model: CustomModel) throws {
+        guard model.id.hasPrefix(Self.idPrefix) else { throw ModelImportError.invalidID(model.id) }
+        guard self.model(id: model.id) == nil,
+              !SettingsValidator.knownTranscriptionModels.contains(model.id) else {
+            throw ModelImportError.duplicate(model.id)
+        }
+        try save(customModels + [model])
+        Logger.shared.info("ModelRegistry: Added '\(model.id)'")
+    }
+
+    func remove(id: String) throws {
+        try save(customModels.filter { $0.id != id })
+        Logger.shared.info("ModelRegistry: Removed '\(id)'")
+    }
+
+    /// Copies (or symlinks) a local WhisperKit model folder into `models/imported/` and
+    /// registers it. Symlinking avoids duplicating multi-GB models kept elsewhere, but the
+    /// model disappears if the original is moved.
+    @discardableResult
+    func importModel(from source: URL, title: String? = nil, symlink: Bool = false) throws -> CustomModel {
+        try Self.validateModelFolder(source)
+
+        let folder = source.lastPathComponent
+        let id = Self.idPrefix + folder.lowercased()
+            .replacingOccurrences(of: #"[^a-z0-9._-]+"#, with: "-", options: .regularExpression)
+        guard model(id: id) == nil else { throw ModelImportError.duplicate(id) }
+
+        let fileManager = FileManager.default
+        let target = importedDirectory.appendingPathComponent(folder, isDirectory: true)
+        try fileManager.createDirectory(at: importedDirectory, withIntermediateDirectories: true)
+        if fileManager.fileExists(atPath: target.path) {
+            try fileManager.removeItem(at: target)
+        }
+        if symlink {
+            try fileManager.createSymbolicLink(at: target, withDestinationURL: source)
+        } else {
+            try fileManager.copyItem(at: source, to: target)
+        }
+
+        let model = CustomModel(id: id, title: title ?? folder, description: "Imported from \(source.path)", repo: nil, folder: folder)
+        try save(customModels + [model])
+        Logger.shared.info("ModelRegistry: Imported '\(id)' from \(source.path) (\(symlink ? "symlink" : "copy"))")
+        return model
+    }
+
+    /// Checks that `url` is a WhisperKit CoreML folder: every required `.mlmodelc` bundle
+    /// is present and has its compiled `coremldata.bin`. ggml/GGUF files (whisper.cpp) are
+    /// recognised by their magic bytes and rejected with an explanation.
+    static func validateModelFolder(_ url: URL) throws {
+        let fileManager = FileManager.default
+        var isDirectory: ObjCBool = false
+        guard fileManager.fileExists(atPath: url.path, isDirectory: &isDirectory) else {
+            throw ModelImportError.notFound(url.path)
+        }
+        guard isDirectory.boolValue else {
+            if isGGML(url) { throw ModelImportError.ggmlNotSupported }
+            throw ModelImportError.missingComponents(requiredComponents)
+        }
+        let missing = requiredComponents.filter { component in
+            !fileManager.fileExists(atPath: url.appendingPathComponent(component).appendingPathComponent("coremldata.bin").path)
+        }
+        guard missing.isEmpty else { throw ModelImportError.missingComponents(missing) }
+    }
+
+    /// `true` when the file starts with a ggml, ggjt or GGUF magic number.
+    static func isGGML(_ url: URL) -> Bool {
+        guard let handle = try? FileHandle(forReadingFrom: url) else { return false }
+        defer { try? handle.close() }
+        guard let header = try? handle.read(upToCount: 4), header.count == 4 else { return false }
+        // ggml / ggjt store the magic as a little-endian UInt32; GGUF as ASCII.
+        return ["lmgg", "tjgg", "GGUF"].contains(String(decoding: header, as: UTF8.self))
+    }
+
+    // MARK: - Persistence
+
+    private func save(_ models: [CustomModel]) throws {
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = [.prettyPrinted, .sortedKeys]
+        try FileManager.default.createDirectory(at: registryURL.deletingLastPathComponent(), withIntermediateDirectories: true)
+        try encoder.encode(RegistryFile(models: models)).write(to: registryURL, options: .atomic)
+        customModels = models
+    }
+}
@@ -0,0 +1,96 @@
+import Foundation
+import UserNotifications
+
+// MARK: - AppNotification
+
+/// Events worth a macOS notification because they happen while VocaGlyph's windows are
+/// hidden — the user is in another app and would otherwise never see them.
+enum AppNotification: Equatable {
+    /// Pasting needs Accessibility; without it the text is only on the clipboard.
+    case copiedToClipboard
+    /// The global shortcut could not be registered, e.g. another app holds it.
+    case hotkeyUnavailable(shortcut: String)
+    case modelDownloaded(model: String)
+    case transcriptionFailed(message: String)
+    /// A permission was revoked while the app was running, e.g. noticed after wake.
+    case permissionsMissing(names: [String])
+
+    /// One identifier per kind, so a repeat replaces the earlier banner instead of stacking.
+    var identifier: String {
+        switch self {
+        case .copiedToClipboard:   return "com.vocaglyph.notification.clipboard"
+        case .hotkeyUnavailable:   return "com.vocaglyph.notification.hotkey"
+        case .modelDownloaded(let model): return "com.vocaglyph.notification.download.\(model)"
+        case .transcriptionFailed: return "com.vocaglyph.notification.error"
+        case .permissionsMissing:  return "com.vocaglyph.notification.permissions"
+        }
+    }
+
+    var title: String {
+        switch self {
+        case .copiedToClipboard:   return "Copied to clipboard"
+        case .hotkeyUnavailable:   return "Shortcut unavailable"
+        case .modelDownloaded:     return "Model downloaded"
+        case .transcriptionFailed: return "Transcription failed"
+        case .permissionsMissing:  return "Permission needed"
+        }
+    }
+
+    var body: String {
+        switch self {
+        case .copiedToClipboard:
+            return "Press ⌘V to paste. Grant Accessibility access to let VocaGlyph paste for you."
+        case .hotkeyUnavailable(let shortcut):
+            return "\(shortcut) couldn't be registered — another app may be using it. Choose a different shortcut in Settings."
+        case .modelDownloaded(let model):
+            return "'\(model)' is ready to use."
+        case .transcriptionFailed(let message):
+            return message
+        case .permissionsMissing(let names):
+            return "VocaGlyph no longer has \(names.joined(separator: " and ")) access. Re-enable it in System Settings › Privacy & Security."
+        }
+    }
+}
+
+// MARK: - NotificationService
+
+/// Posts `AppNotification`s through the User Notifications framework when
+/// `systemNotificationsEnabled` is on. Permission is requested on first use; if the user
+/// declines, posting is silently skipped.
+final class NotificationService {
+
+    static let shared = NotificationService()
+
+    /// `nil` outside an app bundle (unit tests), where the notification center is unavailable.
+    private let center: UNUserNotificationCenter? =
+        Bundle.main.bundleIdentifier != nil ? .current() : nil
+    private var hasRequestedAuthorization = false
+
+    func post(_ notification: AppNotification) {
+        guard SettingsStore.shared.settings.systemNotificationsEnabled, let center else { return }
+        requestAuthorizationIfNeeded(center)
+
+        let content = UNMutableNotificationContent()
+        content.title = notification.title
+        content.body = notification.body
+        let request = UNNotificationRequest(identifier: notification.identifier, content: content, trigger: nil)
+        center.add(request) { error in
+            if let error {
+                Logger.shared.debug("NotificationService: Not posted — \(error.localizedDescription)")
+            }
+        }
+        Logger.shared.info("NotificationService: Posted '\(notification.title)'")
+    }
+
+    private func requestAuthorizationIfNeeded(_ center: UNUserNotificationCenter) {
+        guard !hasRequestedAuthorization else { return }
+        hasRequestedAuthorization = true
+        center.requestAuthorization(options: [.alert, .sound]) { granted, error in
+            if let error {
+                Logger.shared.error("NotificationService: Authorization failed — \(error.localizedDescription)")
+            } else if !granted {
+                Logger.shared.info("NotificationService: Notifications not allowed by the user")
+            }
+        }
+    }
+}
@@ -0,0 +1,194 @@
+import Foundation
+
+// MARK: - OTLPExporter
+
+/// Sends dictation metrics and traces to an OpenTelemetry collector over OTLP/HTTP with
+/// JSON bodies (`<endpoint>/v1/metrics` and `<endpoint>/v1/traces`), when
+/// `otlpExportEnabled` is on.
+///
+/// - Metrics: the `MetricsService` histograms, cumulative, each time
+///   `MetricsUpdatedEvent` is posted.
+/// - Traces: one trace per dictation, a `total` root span with the other stages as
+///   children, sent once the dictation is output.
+///
+/// Only timings and counts leave the Mac — never text. Failed requests are logged and
+/// dropped; the collector is optional.
+final class OTLPExporter {
+
+    static let serviceName = "VocaGlyph"
+    static let defaultEndpoint = "http://localhost:4318"
+
+    let endpoint: URL
+    private let metrics: MetricsService
+    private let session: URLSession
+    private let startedAt: Date
+    private let lock = NSLock()
+    /// Spans of dictations that haven't reached `total` yet, by trace.
+    private var pendingSpans: [UUID: [MetricsSpan]] = [:]
+    private var metricsObserver: NSObjectProtocol?
+
+    init(endpoint: URL, metrics: MetricsService = .shared, session: URLSession = .shared, startedAt: Date = Date()) {
+        self.endpoint = endpoint
+        self.metrics = metrics
+        self.session = session
+        self.startedAt = startedAt
+    }
+
+    deinit {
+        stop()
+    }
+
+    var isRunning: Bool { metricsObserver != nil }
+
+    func start() {
+        guard !isRunning else { return }
+        Logger.shared.info("OTLPExporter: Exporting to \(endpoint.absoluteString)")
+        metricsObserver = MetricsUpdatedEvent.observe(from: metrics) { [weak self] event in
+            self?.export(event.snapshot)
+        }
+        metrics.observeSpans { [weak self] span in self?.collect(span) }
+    }
+
+    func stop() {
+        guard let metricsObserver else { return }
+        NotificationCenter.default.removeObserver(metricsObserver)
+        self.metricsObserver = nil
+        metrics.observeSpans(nil)
+        lock.lock()
+        pendingSpans = [:]
+        lock.unlock()
+    }
+
+    // MARK: - Export
+
+    private func export(_ snapshot: MetricsSnapshot) {
+        send(Self.metricsPayload(snapshot, startedAt: startedAt, now: Date()), to: "v1/metrics")
+    }
+
+    private func collect(_ span: MetricsSpan) {
+        lock.lock()
+        pendingSpans[span.trace, default: []].append(span)
+        let finished = span.stage == .total ? pendingSpans.removeValue(forKey: span.trace) : nil
+        lock.unlock()
+        if let finished {
+            send(Self.tracesPayload(finished), to: "v1/traces")
+        }
+    }
+
+    private func send(_ payload: [String: Any], to path: String) {
+        guard let body = try? JSONSerialization.data(withJSONObject: payload) else { return }
+        var request = URLRequest(url: endpoint.appendingPathComponent(path))
+        request.httpMethod = "POST"
+        request.setValue("application/json", forHTTPHeaderField: "Content-Type")
+        request.httpBody = body
+        request.timeoutInterval = 10
+        session.dataTask(with: request) { _, response, error in
+            if let error {
+                Logger.shared.error("OTLPExporter: \(path) failed — \(error.localizedDescription)")
+            } else if let status = (response as? HTTPURLResponse)?.statusCode, !(200..<300).contains(status) {
+                Logger.shared.error("OTLPExporter: \(path) returned HTTP \(status)")
+            }
+        }.resume()
+    }
+
+    // MARK: - Payloads
+
+    /// An `ExportMetricsServiceRequest`: one latency histogram per stage, the real-time
+    /// factor histogram and queue depth and dictation count.
+    static func metricsPayload(_ snapshot: MetricsSnapshot, startedAt: Date, now: Date) -> [String: Any] {
+        let start = nanos(startedAt)
+        let time = nanos(now)
+        func histogram(_ histogram: Histogram, attributes: [[String: Any]]) -> [String: Any] {
+            [
+                "attributes": attributes,
+                "startTimeUnixNano": start,
+                "timeUnixNano": time,
+                "count": String(histogram.count),
+                "sum": histogram.sum,
+                "max": histogram.max,
+                "bucketCounts": histogram.counts.map(String.init),
+                "explicitBounds": histogram.bounds,
+            ]
+        }
+        let latencyPoints = MetricsStage.allCases.compactMap { stage in
+            snapshot.latency(stage).map { histogram($0, attributes: [attribute("stage", stage.rawValue)]) }
+        }
+        let metrics: [[String: Any]] = [
+            [
+                "name": "vocaglyph.dictation.latency",
+                "unit": "s",
+                "histogram": ["aggregationTemporality": 2, "dataPoints": latencyPoints],
+            ],
+            [
+                "name": "vocaglyph.transcription.real_time_factor",
+                "unit": "1",
+                "histogram": ["aggregationTemporality": 2,
+                              "dataPoints": [histogram(snapshot.realTimeFactor, attributes: [])]],
+            ],
+            [
+                "name": "vocaglyph.transcription.queue_depth",
+                "unit": "{dictation}",
+                "gauge": ["dataPoints": [["timeUnixNano": time, "asInt": String(snapshot.queueDepth)]]],
+            ],
+            [
+                "name": "vocaglyph.dictations",
+                "unit": "{dictation}",
+                "sum": ["aggregationTemporality": 2, "isMonotonic": true,
+                        "dataPoints": [["startTimeUnixNano": start, "timeUnixNano": time,
+                                        "asInt": String(snapshot.transcriptions)]]],
+            ],
+        ]
+        return ["resourceMetrics": [[
+            "resource": resource,
+            "scopeMetrics": [["scope": ["name": "com.vocaglyph.metrics"], "metrics": metrics]],
+        ]]]
+    }
+
+    /// An `ExportTraceServiceRequest` for one dictation's spans; `total` is the root.
+    static func tracesPayload(_ spans: [MetricsSpan]) -> [String: Any] {
+        guard let trace = spans.first?.trace else { return ["resourceSpans": []] }
+        let traceId = hex(trace)
+        let rootId = spanId(trace, .total)
+        let otlpSpans: [[String: Any]] = spans.map { span in
+            var otlp: [String: Any] = [
+                "traceId": traceId,
+                "spanId": spanId(trace, span.stage),
+                "name": span.stage == .total ? "dictation" : span.stage.rawValue,
+                "kind": 1,
+                "startTimeUnixNano": nanos(span.start),
+                "endTimeUnixNano": nanos(span.end),
+            ]
+            if span.stage != .total { otlp["parentSpanId"] = rootId }
+            return otlp
+        }
+        return ["resourceSpans": [[
+            "resource": resource,
+            "scopeSpans": [["scope": ["name": "com.vocaglyph.dictation"], "spans": otlpSpans]],
+        ]]]
+    }
+
+    private static var resource: [String: Any] {
+        let version = Bundle.main.infoDictionary?["CFBundleShortVersionString"] as? String ?? "dev"
+        return ["attributes": [attribute("service.name", serviceName), attribute("service.version", version)]]
+    }
+
+    private static func attribute(_ key: String, _ value: String) -> [String: Any] {
+        ["key": key, "value": ["stringValue": value]]
+    }
+
+    /// OTLP JSON carries 64-bit integers as strings.
+    static func nanos(_ date: Date) -> String {
+        String(UInt64((max(0, date.timeIntervalSince1970) * 1_000_000_000).rounded()))
+    }
+
+    /// 32 hex digits, as OTLP trace ids.
+    static func hex(_ uuid: UUID) -> String {
+        uuid.uuidString.replacingOccurrences(of: "-", with: "").lowercased()
+    }
+
+    /// 16 hex digits, unique per stage within a trace.
+    static func spanId(_ trace: UUID, _ stage: MetricsStage) -> String {
+        let index = MetricsStage.allCases.firstIndex(of: stage) ?? 0
+        return String(hex(trace).prefix(14)) + String(format: "%02x", index + 1)
+    }
+}
@@ -0,0 +1,185 @@
+import Foundation
+
+// MARK: - OnboardingStep
+
+/// Steps of the guided first-run flow, in the order they are presented.
+enum OnboardingStep: String, CaseIterable, Identifiable {
+    case microphone
+    case accessibility
+    case modelDownload
+    case testDictation
+
+    var id: String { rawValue }
+}
+
+// MARK: - OnboardingStepStatus
+
+enum OnboardingStepStatus: Equatable {
+    case pending
+    /// `progress` is 0.0 → 1.0 when known (model download), otherwise `nil`.
+    case inProgress(progress: Double?)
+    case completed
+    case failed(String)
+}
+
+// MARK: - OnboardingState
+
+/// Snapshot returned by `OnboardingCoordinator.state`.
+struct OnboardingState: Equatable {
+    var statuses: [OnboardingStep: OnboardingStepStatus]
+    /// Model the download step will fetch (see `HardwareRecommendation` and `LocaleDefaults`).
+    var recommendedModel: String
+    /// Transcript produced by the last successful test dictation.
+    var testTranscript: String?
+
+    /// The first step that is not completed, or `nil` when onboarding is finished.
+    var currentStep: OnboardingStep? {
+        OnboardingStep.allCases.first { statuses[$0] != .completed }
+    }
+
+    var isComplete: Bool { currentStep == nil }
+}
+
+// MARK: - OnboardingPerforming
+
+/// Side effects the onboarding steps need. Implemented by `AppDelegate`, which owns
+/// the permission, model and audio services; mocked in tests.
+protocol OnboardingPerforming: AnyObject {
+    var isMicrophoneAuthorized: Bool { get }
+    var isAccessibilityTrusted: Bool { get }
+    func requestMicrophoneAccess() async -> Bool
+    /// Shows the system Accessibility prompt. Returns the trust state at the time of the call.
+    func promptAccessibilityTrusted() -> Bool
+    /// Downloads `model` without switching to it, reporting 0.0 → 1.0 progress.
+    func downloadModel(_ model: String, progress: @escaping (Double) -> Void) async throws
+    /// Records for `duration` seconds and returns the transcription.
+    func runTestDictation(duration: TimeInterval) async throws -> String
+}
+
+// MARK: - OnboardingCoordinator
+
+/// Backend for the first-run wizard: runs each step, publishes progress for the UI, and
+/// remembers completed steps across launches in `UserDefaults` (`onboardingCompletedSteps`).
+///
+/// Permission steps are re-checked live on every `refresh()` — a permission revoked in
+/// System Settings moves the step back to `.pending` even if it was completed before.
+final class OnboardingCoordinator: ObservableObject {
+
+    static let completedStepsKey = "onboardingCompletedSteps"
+    static let testDictationDuration: TimeInterval = 4
+
+    @Published private(set) var state: OnboardingState
+
+    private weak var performer: OnboardingPerforming?
+    private let defaults: UserDefaults
+
+    init(performer: OnboardingPerforming,
+         defaults: UserDefaults = .standard,
+         recommendedModel: String = LocaleDefaults.recommendedModel) {
+        self.performer = performer
+        self.defaults = defaults
+        let completed = Set((defaults.stringArray(forKey: Self.completedStepsKey) ?? []).compactMap(OnboardingStep.init(rawValue:)))
+        var statuses: [OnboardingStep: OnboardingStepStatus] = [:]
+        for step in OnboardingStep.allCases {
+            statuses[step] = completed.contains(step) ? .completed : .pending
+        }
+        state = OnboardingState(statuses: statuses, recommendedModel: recommendedModel)
+        refresh()
+    }
+
+    /// Re-reads permission state. Call when the onboarding window regains focus.
+    func refresh() {
+        guard let performer else { return }
+        syncPermission(.microphone, granted: performer.isMicrophoneAuthorized)
+        syncPermission(.accessibility, granted: performer.isAccessibilityTrusted)
+    }
+
+    // MARK: - Steps
+
+    func requestMicrophone() async {
+        guard let performer else { return }
+        set(.microphone, .inProgress(progress: nil))
+        let granted = await performer.requestMicrophoneAccess()
+        set(.microphone, granted ? .completed : .failed("Microphone access was denied. Enable it in System Settings → Privacy & Security → Microphone."))
+    }
+
+    func promptAccessibility() {
+        guard let performer else { return }
+        if performer.promptAccessibilityTrusted() {
+            set(.accessibility, .completed)
+        } else {
+            // The grant happens in System Settings; `refresh()` picks it up.
+            set(.accessibility, .inProgress(progress: nil))
+        }
+    }
+
+    func downloadRecommendedModel() async {
+        guard let performer else { return }
+        let model = state.recommendedModel
+        set(.modelDownload, .inProgress(progress: 0))
+        do {
+            try await performer.downloadModel(model) { [weak self] fraction in
+                DispatchQueue.main.async {
+                    // A late progress tick must not overwrite .completed / .failed.
+                    guard let self, case .inProgress = self.state.statuses[.modelDownload] else { return }
+                    self.set(.modelDownload, .inProgress(progress: min(max(fraction, 0), 1)))
+                }
+            }
+            set(.modelDownload, .completed)
+        } catch {
+            Logger.shared.error("OnboardingCoordinator: Download of '\(model)' failed — \(error.localizedDescription)")
+            set(.modelDownload, .failed(error.localizedDescription))
+        }
+    }
+
+    func runTestDictation() async {
+        guard let performer else { return }
+        set(.testDictation, .inProgress(progress: nil))
+        do {
+            let transcript = try await performer.runTestDictation(duration: Self.testDictationDuration)
+            let trimmed = transcript.trimmingCharacters(in: .whitespacesAndNewlines)
+            guard !trimmed.isEmpty else {
+                set(.testDictation, .failed("Nothing was heard. Check your microphone and try again."))
+                return
+            }
+            onMain { state.testTranscript = trimmed }
+            set(.testDictation, .completed)
+        } catch {
+            set(.testDictation, .failed(error.localizedDescription))
+        }
+    }
+
+    /// Marks a step as done without running it (e.g. "Skip test").
+    func skip(_ step: OnboardingStep) {
+        set(step, .completed)
+    }
+
+    // MARK: - Helpers
+
+    private func syncPermission(_ step: OnboardingStep, granted: Bool) {
+        if granted {
+            if state.statuses[step] != .completed { set(step, .completed) }
+        } else if state.statuses[step] == .completed {
+            set(step, .pending)
+        }
+    }
+
+    private func set(_ step: OnboardingStep, _ status: OnboardingStepStatus) {
+        onMain {
+            state.statuses[step] = status
+            let completed = OnboardingStep.allCases.filter { state.statuses[$0] == .completed }.map(\.rawValue)
+            defaults.set(completed, forKey: Self.completedStepsKey)
+            if case .failed(let message) = status {
+                Logger.shared.info("OnboardingCoordinator: \(step.rawValue) failed — \(message)")
+            } else if status == .completed {
+                Logger.shared.info("OnboardingCoordinator: \(step.rawValue) completed")
+            }
+        }
+    }
+
+    /// `@Published` mutations must happen on the main thread; step methods are async
+    /// and may resume on a background executor.
+    private func onMain(_ work: () -> Void) {
+        if Thread.isMainThread { work() } else { DispatchQueue.main.sync(execute: work) }
+    }
+}
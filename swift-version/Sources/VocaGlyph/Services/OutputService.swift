@@ -2,12 +2,15 @@ import Cocoa
 import ApplicationServices
 import CoreGraphics
 
+/// Debug trace of the paste path, in the log directory (the sandbox doesn't allow `/tmp`).
+private let osDevLogURL = AppDirs.current.logs.appendingPathComponent("output-debug.log")
+
 func osDevLog(_ message: String) {
-    let url = URL(fileURLWithPath: "/tmp/vocaglyph_debug.log")
+    let url = osDevLogURL
     let formatter = DateFormatter()
     formatter.dateFormat = "HH:mm:ss.SSS"
     let time = formatter.string(from: Date())
-    let line = "[\(time)] OutputService: \(message)\n"
+    let line = "[\(time)] OutputService: \(TextRedactor.redactForLog(message, settings: SettingsStore.shared.settings))\n"
     if let handle = try? FileHandle(forWritingTo: url) {
         handle.seekToEndOfFile()
         if let data = line.data(using: .utf8) { handle.write(data) }
@@ -17,53 +20,56 @@ func osDevLog(_ message: String) {
     }
 }
 
+/// Where a paste went: the frontmost app and its focused element.
+struct InsertionTarget: Equatable {
+    let bundleIdentifier: String?
+    let element: AXUIElement?
+}
+
 class OutputService: @unchecked Sendable {
+
+    /// The text last pasted by `insert(_:)`, without its trailing space, so it can be
+    /// deleted and re-typed. `nil` until something is pasted, and when pasting fell
+    /// back to the clipboard.
+    private(set) var lastInsertion: String?
+    /// Where `lastInsertion` was pasted. Re-typing is refused once the focus is
+    /// elsewhere, and the target is forgotten then.
+    private var insertionTarget: InsertionTarget?
+    /// `true` when the last `insert(_:)` could only copy because Accessibility is off.
+    private(set) var lastOutputWasClipboardOnly = false
+
+    private let focusedTarget: () -> InsertionTarget?
+
+    /// `focusedTarget` reads the current focus; tests replace it.
+    init(focusedTarget: @escaping () -> InsertionTarget? = OutputService.currentTarget) {
+        self.focusedTarget = focusedTarget
+    }
     
     /// Main entry point for outputting the transcribed text.
     func handleTranscriptionValue(_ text: String) {
-        osDevLog("handleTranscriptionValue called! Input string length: \(text.count), text: '\(text)'")
+        osDevLog("handleTranscriptionValue called! Input string length: \(text.count), text: '\(Logger.transcript(text))'")
         
         guard !text.isEmpty else {
             osDevLog("String is empty, returning early.")
             return
         }
         
-        var processedText = text.trimmingCharacters(in: .whitespacesAndNewlines)
-        osDevLog("After trimming: '\(processedText)'")
-        
-        let shouldRemoveFillers = UserDefaults.standard.bool(forKey: "removeFillerWords")
-        if shouldRemoveFillers {
-            // Remove common conversational filler words.
-            // (?i) makes it case-insensitive.
-            // \b ensures we match whole words only (so we don't turn "plumber" into "plber" by removing "um").
-            // [\s,]* optionally matches trailing spaces and commas.
-            let pattern = "(?i)\\b(um|uh|ah|like|you know)\\b[\\s,]*"
-            
-            if let regex = try? NSRegularExpression(pattern: pattern, options: []) {
-                let range = NSRange(location: 0, length: processedText.utf16.count)
-                processedText = regex.stringByReplacingMatches(in: processedText, options: [], range: range, withTemplate: " ")
-            }
-            
-            // Clean up any double spaces introduced by replacement
-            processedText = processedText.replacingOccurrences(of: "  ", with: " ")
-            processedText = processedText.trimmingCharacters(in: .whitespacesAndNewlines)
-        }
-
-        // Auto-punctuation: capitalize first letter and append a terminal period if absent.
-        // Whisper and Apple engines produce punctuated output natively; the guard on existing
-        // terminal punctuation makes this a safe no-op for those engines while fixing Parakeet,
-        // which returns raw unpunctuated text from FluidAudio.
-        let shouldAutoPunctuate = UserDefaults.standard.bool(forKey: "autoPunctuation")
-        if shouldAutoPunctuate {
-            processedText = applyBasicPunctuation(processedText)
-        }
-        
+        // Filler removal and punctuation already ran in `TextProcessingPipeline`.
+        let processedText = text.trimmingCharacters(in: .whitespacesAndNewlines)
         if processedText.isEmpty { return }
         
-        Logger.shared.info("Transcription: \(processedText)")
-        
-        // 1. Copy text to the system pasteboard
-        copyToPasteboard(text: processedText + " ") // Add a trailing space for fluid dictation UX
+        Logger.shared.info("Transcription: \(Logger.transcript(processedText))")
+        insert(processedText)
+    }
+
+    /// Pastes `text` into the frontmost app exactly as given, without trimming. Used
+    /// for snippets, which the user has already worded.
+    func insert(_ text: String) {
+        guard !text.isEmpty else { return }
+
+        // 1. Copy text to the system pasteboard. It only carries the paste, so clipboard
+        //    history managers are asked to leave it out.
+        copyToPasteboard(text: text + " ", transient: true) // Add a trailing space for fluid dictation UX
         
         // 2. Play a subtle success sound
         NSSound(named: NSSound.Name("Pop"))?.play()
@@ -77,35 +83,103 @@ class OutputService: @unchecked Sendable {
             DispatchQueue.main.asyncAfter(deadline: .now() + 0.05) {
                 self.simulatePasteKeystroke()
             }
+            lastInsertion = text
+            insertionTarget = focusedTarget()
+            lastOutputWasClipboardOnly = false
         } else {
             Logger.shared.error("AXIsProcessTrusted() returned false. Falling back to clipboard only.")
+            lastInsertion = nil
+            insertionTarget = nil
+            lastOutputWasClipboardOnly = true
+            NotificationService.shared.post(.copiedToClipboard)
+        }
+    }
+
+    /// Clears `lastOutputWasClipboardOnly` once Accessibility is granted, so the status
+    /// no longer reports the clipboard fallback before the next paste.
+    func resetClipboardFallback() {
+        lastOutputWasClipboardOnly = false
+    }
+
+    // MARK: - Re-typing
+
+    /// Deletes the last insertion (and its trailing space) with Backspace and pastes
+    /// `transform` of it instead. Returns `false` when there is nothing to replace,
+    /// keystrokes can't be sent, or the focus has moved to another app or field since
+    /// the paste.
+    ///
+    /// Assumes the cursor is still right after the insertion — anything typed since then
+    /// in the same field would be deleted instead.
+    @discardableResult
+    func retypeLastInsertion(_ transform: (String) -> String) -> Bool {
+        guard let last = lastInsertion, AXIsProcessTrusted() else { return false }
+        guard Self.isSameTarget(insertionTarget, focusedTarget()) else {
+            Logger.shared.info("OutputService: Not re-typing — the focus moved since the last paste")
+            insertionTarget = nil
+            return false
         }
+        let replacement = transform(last)
+        guard replacement != last else { return true }
+
+        simulateBackspaces(last.count + 1)
+        insert(replacement)
+        Logger.shared.info("OutputService: Re-typed last insertion (\(last.count) → \(replacement.count) chars)")
+        return true
     }
     
+    /// Both known and equal. A target without a bundle identifier never matches: its app
+    /// can't be told apart from the next one.
+    static func isSameTarget(_ pasted: InsertionTarget?, _ current: InsertionTarget?) -> Bool {
+        guard let pasted, let current, pasted.bundleIdentifier != nil else { return false }
+        return pasted == current
+    }
+
+    /// The frontmost app and, with Accessibility access, its focused element.
+    static func currentTarget() -> InsertionTarget? {
+        guard let app = NSWorkspace.shared.frontmostApplication else { return nil }
+        var focused: CFTypeRef?
+        let result = AXUIElementCopyAttributeValue(AXUIElementCreateApplication(app.processIdentifier),
+                                                   kAXFocusedUIElementAttribute as CFString, &focused)
+        let element = result == .success && focused != nil ? (focused as! AXUIElement) : nil
+        return InsertionTarget(bundleIdentifier: app.bundleIdentifier, element: element)
+    }
+
     // MARK: - Text Processing Helpers
 
     /// Capitalizes the first character and appends a period if no terminal punctuation exists.
     /// Engine-safe: if the text already ends with `.`, `?`, or `!` (Whisper/Apple output),
-    /// this is a pure no-op — no double-punctuation occurs.
+    /// this is a pure no-op — no double-punctuation occurs. See `CapitalizationProcessor`.
     func applyBasicPunctuation(_ text: String) -> String {
-        var result = text
-        // Capitalize first letter
-        if let first = result.unicodeScalars.first,
-           CharacterSet.lowercaseLetters.contains(first) {
-            result = result.prefix(1).uppercased() + result.dropFirst()
-        }
-        // Append period only when no terminal punctuation is present
-        let terminators: Set<Character> = [".", "?", "!", ":", ";", ","]
-        if let last = result.last, !terminators.contains(last) {
-            result += "."
-        }
-        return result
+        CapitalizationProcessor.apply(text)
     }
 
-    private func copyToPasteboard(text: String) {
-        let pasteboard = NSPasteboard.general
+    // MARK: - Pasteboard
+
+    /// The nspasteboard.org markers clipboard history managers (Maccy, Paste, Alfred,
+    /// Raycast, …) check before recording an item. Their content is irrelevant.
+    static let transientPasteboardTypes: [NSPasteboard.PasteboardType] = [
+        NSPasteboard.PasteboardType("org.nspasteboard.TransientType"),
+        NSPasteboard.PasteboardType("org.nspasteboard.AutoGeneratedType"),
+    ]
+
+    /// Replaces the clipboard with `text`. `transient` is for copies that only exist to
+    /// be pasted; unless the user turned `hideFromClipboardHistory` off, they carry the
+    /// markers above. Copies the user asked for (history, quick panel) leave it `false`.
+    func copyToPasteboard(text: String, transient: Bool = false, pasteboard: NSPasteboard = .general) {
+        let hidden = transient && SettingsStore.shared.settings.hideFromClipboardHistory
         pasteboard.clearContents()
-        pasteboard.setString(text, forType: .string)
+        pasteboard.writeObjects([Self.pasteboardItem(text: text, hiddenFromHistory: hidden)])
+    }
+
+    static func pasteboardItem(text: String, hiddenFromHistory: Bool) -> NSPasteboardItem {
+        let item = NSPasteboardItem()
+        item.setString(text, forType: .string)
+        if hiddenFromHistory {
+            for type in transientPasteboardTypes {
+                item.setData(Data(), forType: type)
+            }
+        }
+        return item
     }
     
     private func simulatePasteKeystroke() {
@@ -129,4 +203,18 @@ class OutputService: @unchecked Sendable {
         
         Logger.shared.info("Cmd+V synthesized via CGEvent!")
     }
+
+    private func simulateBackspaces(_ count: Int) {
+        let src = CGEventSource(stateID: .hidSystemState)
+
+        // Virtual key code for Delete (Backspace) is 0x33
+        let keyDelete: CGKeyCode = 0x33
+
+        for _ in 0..<count {
+            guard let down = CGEvent(keyboardEventSource: src, virtualKey: keyDelete, keyDown: true),
+                  let up = CGEvent(keyboardEventSource: src, virtualKey: keyDelete, keyDown: false) else { return }
+            down.post(tap: .cgSessionEventTap)
+            up.post(tap: .cgSessionEventTap)
+        }
+    }
 }
@@ -0,0 +1,68 @@
+import Foundation
+
+// MARK: - PendingOutput
+
+/// A transcription that was finished but not yet output.
+struct PendingOutput: Codable, Equatable, Identifiable {
+    let id: UUID
+    let text: String
+    let date: Date
+}
+
+// MARK: - PendingOutputStore
+
+/// Keeps each transcription on disk from the moment the engine returns it until it has
+/// been output, so a crash or quit in between doesn't lose what the user said. Whatever
+/// is still in `pending-output.json` at launch was never output and is offered back.
+///
+/// Nothing is stored in Privacy Mode or incognito mode; `AppStateManager` checks before
+/// calling `add(_:)`. Call on the main thread.
+final class PendingOutputStore {
+
+    static let fileName = "pending-output.json"
+
+    let fileURL: URL
+    private let now: () -> Date
+
+    /// `dataRoot` defaults to `AppDirs.current.root`.
+    init(dataRoot: URL? = nil, now: @escaping () -> Date = Date.init) {
+        let root = dataRoot ?? AppDirs.current.root
+        fileURL = root.appendingPathComponent(Self.fileName)
+        self.now = now
+    }
+
+    /// Oldest first. An unreadable file counts as empty.
+    var pending: [PendingOutput] {
+        guard let data = try? Data(contentsOf: fileURL) else { return [] }
+        return (try? JSONDecoder().decode([PendingOutput].self, from: data)) ?? []
+    }
+
+    /// Stores `text` until `remove(_:)` is called with the returned id.
+    @discardableResult
+    func add(_ text: String) -> UUID {
+        let entry = PendingOutput(id: UUID(), text: text, date: now())
+        write(pending + [entry])
+        return entry.id
+    }
+
+    func remove(_ id: UUID) {
+        let remaining = pending.filter { $0.id != id }
+        remaining.isEmpty ? removeAll() : write(remaining)
+    }
+
+    func removeAll() {
+        try? FileManager.default.removeItem(at: fileURL)
+    }
+
+    private func write(_ entries: [PendingOutput]) {
+        do {
+            try FileManager.default.createDirectory(at: fileURL.deletingLastPathComponent(),
+                                                    withIntermediateDirectories: true)
+            try JSONEncoder().encode(entries).write(to: fileURL, options: .atomic)
+            // Dictated text: readable by this user only.
+            try FileManager.default.setAttributes([.posixPermissions: 0o600], ofItemAtPath: fileURL.path)
+        } catch {
+            Logger.shared.error("PendingOutputStore: Could not save — \(error.localizedDescription)")
+        }
+    }
+}
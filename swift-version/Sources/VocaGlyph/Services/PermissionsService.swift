@@ -17,6 +17,11 @@ final class PermissionsService {
         return provider.getMicrophoneAuthorizationStatus() == .authorized
     }
 
+    /// Denied or restricted: only the user can change it, in System Settings.
+    var isMicrophoneBlocked: Bool {
+        return [.denied, .restricted].contains(provider.getMicrophoneAuthorizationStatus())
+    }
+
     var isAccessibilityTrusted: Bool {
         return provider.checkAccessibilityTrusted()
     }
@@ -33,6 +38,16 @@ final class PermissionsService {
         return await provider.requestMicrophoneAccess()
     }
 
+    /// Asks for microphone access only if the user hasn't decided yet; never shows the
+    /// system prompt again after a denial.
+    func ensureMicrophoneAccess() async -> Bool {
+        switch provider.getMicrophoneAuthorizationStatus() {
+        case .authorized:    return true
+        case .notDetermined: return await provider.requestMicrophoneAccess()
+        default:             return false
+        }
+    }
+
     func requestSpeechRecognitionAccess() async -> Bool {
         return await provider.requestSpeechRecognitionAccess()
     }
@@ -0,0 +1,436 @@
+import Foundation
+
+// MARK: - PluginManifest
+
+/// `plugin.json` in a folder of its own under the plugins directory:
+///
+///     {"name": "shout", "kinds": ["processor"], "command": "shout.py", "timeoutSeconds": 5}
+///
+/// A `processor` rewrites every dictation after the built-in text stages (before output
+/// style and the profanity filter); a `sink` is sent every finished dictation before it
+/// is pasted. One plugin can be both.
+struct PluginManifest: Codable, Equatable {
+    enum Kind: String, Codable {
+        case processor
+        case sink
+    }
+
+    static let fileName = "plugin.json"
+    static let defaultTimeout: TimeInterval = 5
+
+    var name: String
+    var kinds: [Kind]
+    /// Relative to the plugin's folder, or absolute.
+    var command: String
+    var arguments: [String]?
+    /// Per request; a plugin that doesn't answer in time counts as failed.
+    var timeoutSeconds: Double?
+    /// `false` keeps the plugin installed but unloaded.
+    var enabled: Bool?
+}
+
+enum PluginError: LocalizedError, Equatable {
+    case invalidManifest(String, reason: String)
+    case timedOut(String)
+    case failed(String, reason: String)
+    case disabled(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .invalidManifest(let path, let reason): return "\(path): \(reason)"
+        case .timedOut(let name):                    return "Plugin '\(name)' did not answer in time."
+        case .failed(let name, let reason):          return "Plugin '\(name)' failed: \(reason)"
+        case .disabled(let name):                    return "Plugin '\(name)' is disabled after repeated failures."
+        }
+    }
+}
+
+// MARK: - PluginProcess
+
+/// One running plugin: a subprocess spoken to in JSON lines over stdin/stdout.
+///
+///     → {"id": 1, "method": "process", "text": "hello world"}
+///     ← {"id": 1, "text": "Hello world!"}
+///     → {"id": 2, "method": "output", "text": "Hello world!"}
+///     ← {"id": 2}
+///
+/// Any request may be answered with `{"id": N, "error": "…"}`. stderr goes to the app
+/// log. The plugin runs in its own folder with a minimal environment; it is restarted
+/// when it exits and disabled until the next reload after `maxConsecutiveFailures`
+/// failed requests, so a broken plugin never blocks or loses a dictation.
+final class PluginProcess: @unchecked Sendable {
+
+    static let maxConsecutiveFailures = 3
+    /// A response line longer than this ends the process.
+    static let maxLineBytes = 1 << 20
+    static let protocolVersion = 1
+
+    private struct Response: Decodable {
+        let id: Int
+        let text: String?
+        let error: String?
+    }
+
+    let manifest: PluginManifest
+    let folder: URL
+    var name: String { manifest.name }
+    var timeout: TimeInterval { manifest.timeoutSeconds ?? PluginManifest.defaultTimeout }
+
+    private let lock = NSLock()
+    private var subprocess: Process?
+    private var input: FileHandle?
+    private var received = Data()
+    private var nextId = 1
+    private var pending: [Int: CheckedContinuation<Response, Error>] = [:]
+    private var consecutiveFailures = 0
+
+    init(manifest: PluginManifest, folder: URL) {
+        self.manifest = manifest
+        self.folder = folder
+    }
+
+    deinit {
+        stop()
+    }
+
+    var isRunning: Bool {
+        lock.lock()
+        defer { lock.unlock() }
+        return subprocess?.isRunning == true
+    }
+
+    var isDisabled: Bool {
+        lock.lock()
+        defer { lock.unlock() }
+        return consecutiveFailures >= Self.maxConsecutiveFailures
+    }
+
+    // MARK: - Lifecycle
+
+    func start() throws {
+        lock.lock()
+        defer { lock.unlock() }
+        try startLocked()
+    }
+
+    private func startLocked() throws {
+        guard subprocess?.isRunning != true else { return }
+        // A plugin that exits between requests must fail the write, not kill the app.
+        signal(SIGPIPE, SIG_IGN)
+
+        let process = Process()
+        process.executableURL = manifest.command.hasPrefix("/")
+            ? URL(fileURLWithPath: manifest.command)
+            : folder.appendingPathComponent(manifest.command)
+        process.arguments = manifest.arguments ?? []
+        process.currentDirectoryURL = folder
+        process.environment = [
+            "PATH": "/usr/local/bin:/opt/homebrew/bin:/usr/bin:/bin:/usr/sbin:/sbin",
+            "HOME": NSHomeDirectory(),
+            "VOCAGLYPH_PLUGIN_PROTOCOL": String(Self.protocolVersion),
+        ]
+        let stdin = Pipe()
+        let stdout = Pipe()
+        let stderr = Pipe()
+        process.standardInput = stdin
+        process.standardOutput = stdout
+        process.standardError = stderr
+        stdout.fileHandleForReading.readabilityHandler = { [weak self] handle in
+            let data = handle.availableData
+            if !data.isEmpty { self?.receive(data) }
+        }
+        let name = self.name
+        stderr.fileHandleForReading.readabilityHandler = { handle in
+            let data = handle.availableData
+            guard !data.isEmpty else { return }
+            for line in String(decoding: data, as: UTF8.self).split(separator: "\n") {
+                Logger.shared.info("PluginHost: [\(name)] \(line)")
+            }
+        }
+        process.terminationHandler = { [weak self] process in
+            stdout.fileHandleForReading.readabilityHandler = nil
+            stderr.fileHandleForReading.readabilityHandler = nil
+            self?.didExit(process)
+        }
+
+        do {
+            try process.run()
+        } catch {
+            throw PluginError.failed(name, reason: error.localizedDescription)
+        }
+        subprocess = process
+        input = stdin.fileHandleForWriting
+        received = Data()
+        Logger.shared.info("PluginHost: Started '\(name)' (pid \(process.processIdentifier))")
+    }
+
+    /// Closes stdin so the plugin can exit by itself, and terminates it otherwise.
+    func stop() {
+        lock.lock()
+        let process = subprocess
+        try? input?.close()
+        input = nil
+        subprocess = nil
+        let waiting = pending
+        pending = [:]
+        lock.unlock()
+        waiting.values.forEach { $0.resume(throwing: PluginError.failed(name, reason: "stopped")) }
+        guard let process, process.isRunning else { return }
+        DispatchQueue.global().asyncAfter(deadline: .now() + 1) {
+            if process.isRunning { process.terminate() }
+        }
+    }
+
+    // MARK: - Requests
+
+    /// The plugin's rewrite of `text`.
+    func process(_ text: String) async throws -> String {
+        let response = try await send(method: "process", text: text)
+        guard let rewritten = response.text else {
+            throw PluginError.failed(name, reason: "response has no \"text\"")
+        }
+        return rewritten
+    }
+
+    /// Hands a finished dictation to a sink plugin.
+    func output(_ text: String) async throws {
+        _ = try await send(method: "output", text: text)
+    }
+
+    private func send(method: String, text: String) async throws -> Response {
+        do {
+            let response = try await request(method: method, text: text)
+            if let error = response.error { throw PluginError.failed(name, reason: error) }
+            lock.lock()
+            consecutiveFailures = 0
+            lock.unlock()
+            return response
+        } catch PluginError.disabled(let name) {
+            throw PluginError.disabled(name)
+        } catch {
+            noteFailure()
+            throw error
+        }
+    }
+
+    private func request(method: String, text: String) async throws -> Response {
+        try await withCheckedThrowingContinuation { continuation in
+            lock.lock()
+            guard consecutiveFailures < Self.maxConsecutiveFailures else {
+                lock.unlock()
+                continuation.resume(throwing: PluginError.disabled(name))
+                return
+            }
+            let id = nextId
+            nextId += 1
+            var line = (try? JSONSerialization.data(withJSONObject: ["id": id, "method": method, "text": text])) ?? Data()
+            line.append(UInt8(ascii: "\n"))
+            do {
+                try startLocked()
+                try input?.write(contentsOf: line)
+            } catch {
+                lock.unlock()
+                continuation.resume(throwing: error as? PluginError ?? PluginError.failed(name, reason: error.localizedDescription))
+                return
+            }
+            pending[id] = continuation
+            lock.unlock()
+
+            DispatchQueue.global().asyncAfter(deadline: .now() + timeout) { [weak self] in
+                self?.finish(id, with: .failure(PluginError.timedOut(self?.name ?? "")))
+            }
+        }
+    }
+
+    private func finish(_ id: Int, with result: Result<Response, Error>) {
+        lock.lock()
+        let continuation = pending.removeValue(forKey: id)
+        lock.unlock()
+        continuation?.resume(with: result)
+    }
+
+    private func noteFailure() {
+        lock.lock()
+        consecutiveFailures += 1
+        let disable = consecutiveFailures == Self.maxConsecutiveFailures
+        lock.unlock()
+        if disable {
+            Logger.shared.error("PluginHost: Disabled '\(name)' after \(Self.maxConsecutiveFailures) failures in a row")
+            stop()
+        }
+    }
+
+    // MARK: - Output
+
+    private func receive(_ data: Data) {
+        lock.lock()
+        received.append(data)
+        var lines: [Data] = []
+        while let newline = received.firstIndex(of: UInt8(ascii: "\n")) {
+            lines.append(received[received.startIndex..<newline])
+            received.removeSubrange(received.startIndex...newline)
+        }
+        let overflow = received.count > Self.maxLineBytes
+        lock.unlock()
+
+        for line in lines where !line.isEmpty {
+            guard let response = try? JSONDecoder().decode(Response.self, from: line) else {
+                Logger.shared.error("PluginHost: [\(name)] Ignoring a line that is not a response")
+                continue
+            }
+            finish(response.id, with: .success(response))
+        }
+        if overflow {
+            Logger.shared.error("PluginHost: [\(name)] Response too long — stopping")
+            stop()
+        }
+    }
+
+    private func didExit(_ exited: Process) {
+        lock.lock()
+        guard subprocess === exited else {
+            lock.unlock()
+            return
+        }
+        subprocess = nil
+        input = nil
+        let waiting = pending
+        pending = [:]
+        lock.unlock()
+        Logger.shared.info("PluginHost: '\(name)' exited with status \(exited.terminationStatus)")
+        let error = PluginError.failed(name, reason: "exited with status \(exited.terminationStatus)")
+        waiting.values.forEach { $0.resume(throwing: error) }
+    }
+}
+
+// MARK: - PluginTextProcessor
+
+/// The `plugins` stage of `TextProcessingPipeline`: every processor plugin in name
+/// order. A plugin that fails is skipped with its input kept.
+struct PluginTextProcessor: TextProcessor {
+    let plugins: [PluginProcess]
+
+    func process(_ text: String) async throws -> String {
+        var current = text
+        for plugin in plugins where !plugin.isDisabled {
+            do {
+                current = try await plugin.process(current)
+            } catch {
+                Logger.shared.error("PluginHost: [\(plugin.name)] \(error.localizedDescription) Keeping its input.")
+            }
+        }
+        return current
+    }
+}
+
+// MARK: - PluginHost
+
+/// Finds, starts and stops the plugins in `<data dir>/plugins/*/plugin.json` while the
+/// `pluginsEnabled` setting is on. `AppDelegate` owns one while enabled, passes
+/// `processor` to `AppStateManager` and forwards finished dictations to `deliver(_:)`.
+final class PluginHost {
+
+    static let directoryName = "plugins"
+
+    let directory: URL
+    private(set) var plugins: [PluginProcess] = []
+
+    /// `directory` defaults to `plugins` in `AppDirs.current.root`.
+    init(directory: URL? = nil) {
+        self.directory = directory
+            ?? AppDirs.current.root.appendingPathComponent(Self.directoryName, isDirectory: true)
+    }
+
+    deinit {
+        stop()
+    }
+
+    /// Stops the running plugins and starts the ones now on disk.
+    func reload() {
+        stop()
+        try? FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        let found = Self.discover(in: directory)
+        found.problems.forEach { Logger.shared.error("PluginHost: \($0)") }
+        plugins = found.plugins.map { PluginProcess(manifest: $0.manifest, folder: $0.folder) }
+        for plugin in plugins {
+            do {
+                try plugin.start()
+            } catch {
+                Logger.shared.error("PluginHost: \(error.localizedDescription)")
+            }
+        }
+        Logger.shared.info("PluginHost: Loaded \(plugins.count) plugin(s) from \(directory.path)")
+    }
+
+    func stop() {
+        plugins.forEach { $0.stop() }
+        plugins = []
+    }
+
+    /// The processor plugins as one pipeline stage; `nil` when there are none.
+    var processor: PluginTextProcessor? {
+        let processors = plugins.filter { $0.manifest.kinds.contains(.processor) }
+        return processors.isEmpty ? nil : PluginTextProcessor(plugins: processors)
+    }
+
+    /// Sends `text` to every sink plugin without waiting for them.
+    func deliver(_ text: String) {
+        for plugin in plugins where plugin.manifest.kinds.contains(.sink) && !plugin.isDisabled {
+            Task.detached {
+                do {
+                    try await plugin.output(text)
+                } catch {
+                    Logger.shared.error("PluginHost: [\(plugin.name)] \(error.localizedDescription)")
+                }
+            }
+        }
+    }
+
+    // MARK: - Discovery
+
+    /// Enabled plugins in `directory`, sorted by name, and why the others were skipped.
+    static func discover(in directory: URL) -> (plugins: [(manifest: PluginManifest, folder: URL)], problems: [String]) {
+        let folders = (try? FileManager.default.contentsOfDirectory(
+            at: directory, includingPropertiesForKeys: [.isDirectoryKey], options: [.skipsHiddenFiles])) ?? []
+        var plugins: [(manifest: PluginManifest, folder: URL)] = []
+        var problems: [String] = []
+        var names = Set<String>()
+        for folder in folders.sorted(by: { $0.lastPathComponent < $1.lastPathComponent }) {
+            let file = folder.appendingPathComponent(PluginManifest.fileName)
+            guard FileManager.default.fileExists(atPath: file.path) else { continue }
+            do {
+                let manifest = try load(file)
+                guard manifest.enabled != false else { continue }
+                guard names.insert(manifest.name).inserted else {
+                    throw PluginError.invalidManifest(file.path, reason: "another plugin is named '\(manifest.name)'")
+                }
+                plugins.append((manifest, folder))
+            } catch {
+                problems.append(error.localizedDescription)
+            }
+        }
+        return (plugins.sorted { $0.manifest.name < $1.manifest.name }, problems)
+    }
+
+    static func load(_ file: URL) throws -> PluginManifest {
+        let manifest: PluginManifest
+        do {
+            manifest = try JSONDecoder().decode(PluginManifest.self, from: Data(contentsOf: file))
+        } catch {
+            throw PluginError.invalidManifest(file.path, reason: "not a valid plugin manifest")
+        }
+        if manifest.name.trimmingCharacters(in: .whitespaces).isEmpty {
+            throw PluginError.invalidManifest(file.path, reason: "\"name\" is empty")
+        }
+        if manifest.kinds.isEmpty {
+            throw PluginError.invalidManifest(file.path, reason: "\"kinds\" is empty")
+        }
+        if manifest.command.isEmpty {
+            throw PluginError.invalidManifest(file.path, reason: "\"command\" is empty")
+        }
+        if let timeout = manifest.timeoutSeconds, !(0.1...60).contains(timeout) {
+            throw PluginError.invalidManifest(file.path, reason: "\"timeoutSeconds\" must be between 0.1 and 60")
+        }
+        return manifest
+    }
+}
@@ -0,0 +1,102 @@
+import Foundation
+
+// MARK: - PreferencesWatcher
+
+/// Observes VocaGlyph's preference keys and reports changes — including ones written
+/// from outside the app with `defaults write com.vocaglyph.app …` — so power users can
+/// edit settings without restarting.
+///
+/// `UserDefaults.didChangeNotification` only fires for in-process writes; key-value
+/// observation on `UserDefaults` is also delivered for external writes via cfprefsd,
+/// which is why this uses KVO per key.
+///
+/// Changes are coalesced over `debounceInterval` so a script writing several keys
+/// in a row produces a single `onChange` call.
+final class PreferencesWatcher: NSObject {
+
+    /// Keys whose changes need live re-application (hotkey re-register, model reload, …).
+    /// Keys read at use-time (e.g. `dictationLanguage`) are included so the reload
+    /// notification still reports them.
+    static let watchedKeys: [String] = [
+        UserDefaults.customShortcutKeyCodeKey,
+        UserDefaults.customShortcutModifiersKey,
+        UserDefaults.hotkeyBackendKey,
+        "selectedModel",
+        "dictationLanguage",
+        "enablePostProcessing",
+        "selectedTaskModel",
+        "selectedCloudProvider",
+        "selectedLocalLLMModel",
+        "enableDebugLogging",
+        "contextCaptureEnabled",
+        "contextCaptureCharacterLimit",
+        "contextCaptureExcludedApps"
+    ]
+
+    private let defaults: UserDefaults
+    private let keys: [String]
+    private let debounceInterval: TimeInterval
+    private var pendingKeys: Set<String> = []
+    private var pendingWorkItem: DispatchWorkItem?
+    private var isObserving = false
+
+    /// Called on the main thread with the set of keys that changed during the debounce window.
+    var onChange: ((Set<String>) -> Void)?
+
+    init(defaults: UserDefaults = .standard,
+         keys: [String] = PreferencesWatcher.watchedKeys,
+         debounceInterval: TimeInterval = 0.5) {
+        self.defaults = defaults
+        self.keys = keys
+        self.debounceInterval = debounceInterval
+        super.init()
+    }
+
+    deinit {
+        stop()
+    }
+
+    func start() {
+        guard !isObserving else { return }
+        for key in keys {
+            defaults.addObserver(self, forKeyPath: key, options: [.new], context: nil)
+        }
+        isObserving = true
+        Logger.shared.info("PreferencesWatcher: Watching \(keys.count) preference key(s)")
+    }
+
+    func stop() {
+        guard isObserving else { return }
+        for key in keys {
+            defaults.removeObserver(self, forKeyPath: key)
+        }
+        isObserving = false
+        pendingWorkItem?.cancel()
+        pendingWorkItem = nil
+    }
+
+    override func observeValue(forKeyPath keyPath: String?,
+                               of object: Any?,
+                               change: [NSKeyValueChangeKey: Any]?,
+                               context: UnsafeMutableRawPointer?) {
+        guard let keyPath else { return }
+        DispatchQueue.main.async { [weak self] in
+            self?.enqueue(keyPath)
+        }
+    }
+
+    private func enqueue(_ key: String) {
+        pendingKeys.insert(key)
+        pendingWorkItem?.cancel()
+        let work = DispatchWorkItem { [weak self] in
+            guard let self else { return }
+            let changed = self.pendingKeys
+            self.pendingKeys.removeAll()
+            Logger.shared.info("PreferencesWatcher: Preferences changed: \(changed.sorted().joined(separator: ", "))")
+            self.onChange?(changed)
+            ConfigReloadedEvent(keys: changed.sorted()).post()
+        }
+        pendingWorkItem = work
+        DispatchQueue.main.asyncAfter(deadline: .now() + debounceInterval, execute: work)
+    }
+}
@@ -0,0 +1,80 @@
+import Foundation
+
+// MARK: - ProcessingWatchdog
+
+/// Notices a dictation that never leaves Processing — an engine that stopped answering
+/// and ignores cancellation, or a text stage that hangs — so the app recovers instead of
+/// showing "Processing" until it is restarted.
+///
+/// `AppStateManager` forwards every state change. Entering `.processing` starts the
+/// clock, any other state stops it; after `processingTimeoutSeconds` `onStuck` runs with
+/// how long it waited. A limit of 0 turns the watchdog off.
+///
+/// The limit is for going without progress, not for the whole dictation: each finished
+/// stage calls `noteProgress(audioSeconds:)`, which starts the clock again. Transcription
+/// gets `secondsPerAudioSecond` more for every second recorded, so a long dictation on a
+/// slow model isn't mistaken for a stuck one.
+final class ProcessingWatchdog {
+
+    /// Allowed values of the `processingTimeoutSeconds` setting, besides 0.
+    static let timeoutRange = 30...600
+    /// Extra limit per second of audio while it is transcribed; large models on a base
+    /// Mac can run slower than real time.
+    static let secondsPerAudioSecond: Double = 2
+
+    private let timeout: () -> Int
+    private let queue: DispatchQueue
+    private let secondLength: TimeInterval
+    private let onStuck: (Int) -> Void
+    private var pending: DispatchWorkItem?
+
+    /// `timeout` is read each time the clock starts, so a changed setting applies to the
+    /// next dictation. `onStuck` runs on `queue`. `secondLength` is one second of the
+    /// limit; tests shorten it.
+    init(timeout: @escaping () -> Int = { SettingsStore.shared.settings.processingTimeoutSeconds },
+         queue: DispatchQueue = .main,
+         secondLength: TimeInterval = 1,
+         onStuck: @escaping (Int) -> Void) {
+        self.timeout = timeout
+        self.queue = queue
+        self.secondLength = secondLength
+        self.onStuck = onStuck
+    }
+
+    var isArmed: Bool { pending != nil }
+
+    func stateDidChange(_ state: AppState) {
+        guard state == .processing else {
+            disarm()
+            return
+        }
+        arm()
+    }
+
+    /// Starts the clock again while processing; does nothing otherwise. `audioSeconds` is
+    /// the length of audio the next stage transcribes, 0 for text stages.
+    func noteProgress(audioSeconds: Double = 0) {
+        guard isArmed else { return }
+        arm(extraSeconds: Int((max(0, audioSeconds) * Self.secondsPerAudioSecond).rounded(.up)))
+    }
+
+    private func arm(extraSeconds: Int = 0) {
+        disarm()
+        let limit = timeout()
+        guard limit > 0 else { return }
+        let seconds = limit + extraSeconds
+        let item = DispatchWorkItem { [weak self] in
+            guard let self else { return }
+            self.pending = nil
+            Logger.shared.error("ProcessingWatchdog: Still processing after \(seconds)s")
+            self.onStuck(seconds)
+        }
+        pending = item
+        queue.asyncAfter(deadline: .now() + Double(seconds) * secondLength, execute: item)
+    }
+
+    private func disarm() {
+        pending?.cancel()
+        pending = nil
+    }
+}
@@ -0,0 +1,73 @@
+import Foundation
+
+// MARK: - QuitDrainer
+
+/// Holds off quitting while a dictation is still being transcribed, so text the user
+/// just spoke isn't dropped. `AppDelegate` answers `applicationShouldTerminate` with
+/// `.terminateLater`, starts a drainer and forwards every state change to it; the
+/// drainer finishes when the app is back at rest, or gives up after `timeout`.
+final class QuitDrainer {
+
+    enum Outcome: Equatable {
+        case finished
+        case timedOut
+    }
+
+    /// Allowed values of the `quitWaitSeconds` setting.
+    static let timeoutRange = 5...120
+
+    private let timeout: Int
+    private let tickInterval: TimeInterval
+    private var remaining: Int
+    private var timer: Timer?
+    private var onProgress: ((Int) -> Void)?
+    private var completion: ((Outcome) -> Void)?
+
+    /// `tickInterval` is one "second" of the countdown; tests shorten it.
+    init(timeout: Int, tickInterval: TimeInterval = 1) {
+        self.timeout = timeout
+        self.tickInterval = tickInterval
+        remaining = timeout
+    }
+
+    var isDraining: Bool { completion != nil }
+
+    /// Starts the countdown. `onProgress` gets the seconds left after each tick;
+    /// `completion` is called once. Call on the main thread.
+    func begin(onProgress: @escaping (Int) -> Void, completion: @escaping (Outcome) -> Void) {
+        self.onProgress = onProgress
+        self.completion = completion
+        remaining = timeout
+        Logger.shared.info("QuitDrainer: Waiting up to \(timeout)s for the dictation in progress")
+        onProgress(remaining)
+        timer = Timer.scheduledTimer(withTimeInterval: tickInterval, repeats: true) { [weak self] _ in
+            self?.tick()
+        }
+    }
+
+    /// Forwarded from `appStateDidChange`; a resting state means the result was output.
+    func stateDidChange(_ state: AppState) {
+        guard isDraining, state.isResting else { return }
+        finish(.finished)
+    }
+
+    func cancel() {
+        timer?.invalidate()
+        timer = nil
+        onProgress = nil
+        completion = nil
+    }
+
+    private func tick() {
+        remaining -= 1
+        onProgress?(max(remaining, 0))
+        if remaining <= 0 { finish(.timedOut) }
+    }
+
+    private func finish(_ outcome: Outcome) {
+        let completion = completion
+        cancel()
+        Logger.shared.info("QuitDrainer: \(outcome == .finished ? "Dictation finished" : "Timed out")")
+        completion?(outcome)
+    }
+}
@@ -0,0 +1,151 @@
+import Foundation
+
+// MARK: - RegexRule
+
+/// One find/replace rule from `regex-rules.json`.
+struct RegexRule: Codable, Equatable {
+    /// `NSRegularExpression` pattern.
+    var pattern: String
+    /// Replacement template; `$1`, `$2` … insert capture groups, `\$` a literal dollar.
+    var replacement: String
+    /// Defaults to `true`.
+    var caseInsensitive: Bool?
+    /// Defaults to `true`. Lets a rule be parked without deleting it.
+    var enabled: Bool?
+    /// Free-form description; ignored by the app.
+    var note: String?
+}
+
+// MARK: - RegexRulesService
+
+/// Loads the user's regex rules and keeps them current while the app runs.
+///
+/// Rules live in `<data root>/regex-rules.json` and run in order, each on the output
+/// of the previous one, in the `regexRules` stage of `TextProcessingPipeline`:
+///
+///     { "rules": [ { "pattern": "(\\d+) percent", "replacement": "$1%" },
+///                  { "pattern": "\\bget hub\\b", "replacement": "GitHub", "note": "Whisper mishears it" } ] }
+///
+/// The file is polled rather than watched with a file-system source: editors either
+/// replace it (atomic save) or rewrite it in place, and one `stat` per second covers
+/// both. Invalid patterns are logged, listed in `problems` and skipped.
+final class RegexRulesService: ObservableObject, @unchecked Sendable {
+
+    static let shared = RegexRulesService()
+
+    static let fileName = "regex-rules.json"
+
+    let rulesURL: URL
+
+    @Published private(set) var rules: [RegexRule] = []
+    /// Enabled rules whose pattern compiled.
+    @Published private(set) var activeRuleCount = 0
+    /// Human-readable reasons rules were skipped in the last load.
+    @Published private(set) var problems: [String] = []
+
+    private struct RulesFile: Codable {
+        var rules: [RegexRule]
+    }
+
+    private let lock = NSLock()
+    private var compiled: [RegexRuleProcessor.Rule] = []
+    private var lastModified: Date?
+    private var timer: Timer?
+
+    /// `dataRoot` defaults to `AppDirs.current.root`.
+    init(dataRoot: URL? = nil) {
+        let root = dataRoot ?? AppDirs.current.root
+        rulesURL = root.appendingPathComponent(Self.fileName)
+        reload()
+    }
+
+    deinit {
+        timer?.invalidate()
+    }
+
+    /// Compiled, enabled rules in file order. Safe to read from any thread.
+    var compiledRules: [RegexRuleProcessor.Rule] {
+        lock.lock()
+        defer { lock.unlock() }
+        return compiled
+    }
+
+    // MARK: - Loading
+
+    /// Re-reads the rules file. A missing file means no rules; a file that fails to
+    /// parse keeps the previous rules so a half-saved edit does not drop them all.
+    func reload() {
+        lastModified = modificationDate()
+        guard let data = try? Data(contentsOf: rulesURL) else {
+            apply(rules: [], problems: [])
+            return
+        }
+        let file: RulesFile
+        do {
+            file = try JSONDecoder().decode(RulesFile.self, from: data)
+        } catch {
+            Logger.shared.error("RegexRulesService: Could not parse \(rulesURL.path) — \(error.localizedDescription)")
+            problems = ["\(Self.fileName) could not be parsed: \(error.localizedDescription)"]
+            return
+        }
+        var problems: [String] = []
+        for (index, rule) in file.rules.enumerated() where (try? NSRegularExpression(pattern: rule.pattern)) == nil {
+            Logger.shared.error("RegexRulesService: Skipping rule \(index + 1) — invalid pattern '\(rule.pattern)'")
+            problems.append("Rule \(index + 1): invalid pattern '\(rule.pattern)'")
+        }
+        apply(rules: file.rules, problems: problems)
+        Logger.shared.info("RegexRulesService: Loaded \(activeRuleCount) of \(file.rules.count) rule(s) from \(Self.fileName)")
+    }
+
+    /// Polls the rules file and reloads it when its modification date changes.
+    func startWatching(interval: TimeInterval = 1) {
+        guard timer == nil else { return }
+        timer = Timer.scheduledTimer(withTimeInterval: interval, repeats: true) { [weak self] _ in
+            guard let self, self.modificationDate() != self.lastModified else { return }
+            self.reload()
+        }
+    }
+
+    func stopWatching() {
+        timer?.invalidate()
+        timer = nil
+    }
+
+    /// Writes an example file if none exists, so "Open Rules File" always has
+    /// something to open.
+    func createFileIfNeeded() throws {
+        guard !FileManager.default.fileExists(atPath: rulesURL.path) else { return }
+        let example = RulesFile(rules: [
+            RegexRule(pattern: #"(\d+) percent"#, replacement: "$1%",
+                      note: "Example: \"50 percent\" becomes \"50%\". Capture groups are $1, $2, …"),
+            RegexRule(pattern: #"\bget hub\b"#, replacement: "GitHub", enabled: false,
+                      note: "Example: fix a word the model keeps mishearing. Set enabled to true to use it."),
+        ])
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = [.prettyPrinted, .sortedKeys, .withoutEscapingSlashes]
+        try FileManager.default.createDirectory(at: rulesURL.deletingLastPathComponent(), withIntermediateDirectories: true)
+        try encoder.encode(example).write(to: rulesURL, options: .atomic)
+        reload()
+    }
+
+    // MARK: - Helpers
+
+    private func apply(rules: [RegexRule], problems: [String]) {
+        let compiled = rules.compactMap { rule -> RegexRuleProcessor.Rule? in
+            guard rule.enabled ?? true else { return nil }
+            let options: NSRegularExpression.Options = (rule.caseInsensitive ?? true) ? [.caseInsensitive] : []
+            guard let regex = try? NSRegularExpression(pattern: rule.pattern, options: options) else { return nil }
+            return RegexRuleProcessor.Rule(regex: regex, template: rule.replacement)
+        }
+        lock.lock()
+        self.compiled = compiled
+        lock.unlock()
+        self.rules = rules
+        self.activeRuleCount = compiled.count
+        self.problems = problems
+    }
+
+    private func modificationDate() -> Date? {
+        (try? FileManager.default.attributesOfItem(atPath: rulesURL.path))?[.modificationDate] as? Date
+    }
+}
@@ -0,0 +1,35 @@
+import AVFoundation
+import Foundation
+
+// MARK: - ReplayAudioSource
+
+/// A recorded WAV file in place of the microphone, for `--replay` and the control API's
+/// `replay` command. The file is decoded up front, so a bad file is reported before
+/// the dictation starts, and every replay of it hands the engine the same samples.
+final class ReplayAudioSource: AudioSource {
+
+    let file: URL
+    private let buffer: AVAudioPCMBuffer
+
+    /// Throws `TranscriptionServerError.invalidAudio` when `file` can't be read as WAV.
+    init(file: URL) throws {
+        let data: Data
+        do {
+            data = try Data(contentsOf: file)
+        } catch {
+            throw TranscriptionServerError.invalidAudio("cannot read \(file.path)")
+        }
+        self.file = file
+        buffer = try TranscriptionServer.wavBuffer(data)
+    }
+
+    var duration: TimeInterval {
+        Double(buffer.frameLength) / buffer.format.sampleRate
+    }
+
+    func startCapture() throws {
+        Logger.shared.info("ReplayAudioSource: Replaying \(file.lastPathComponent) (\(String(format: "%.1f", duration))s)")
+    }
+
+    func stopCapture() -> AVAudioPCMBuffer? { buffer }
+}
@@ -0,0 +1,149 @@
+import Foundation
+
+// MARK: - SettingsBackup
+
+/// A settings snapshot archived on disk by `SettingsBackupService`.
+struct SettingsBackup: Equatable, Identifiable {
+    let url: URL
+    let createdAt: Date
+
+    var id: String { url.lastPathComponent }
+}
+
+// MARK: - SettingsBackupError
+
+enum SettingsBackupError: LocalizedError, Equatable {
+    case unreadable(String)
+    case invalid([SettingsValidationIssue])
+
+    var errorDescription: String? {
+        switch self {
+        case .unreadable(let name):
+            return "Settings backup '\(name)' could not be read."
+        case .invalid(let issues):
+            let fields = issues.map { $0.field.isEmpty ? $0.message : "\($0.field): \($0.message)" }
+            return "Settings backup is invalid — \(fields.joined(separator: "; "))"
+        }
+    }
+}
+
+// MARK: - SettingsBackupService
+
+/// Archives `AppSettings` snapshots as timestamped JSON files so a reset or an
+/// experiment with advanced settings can be undone.
+///
+/// Backups live in `AppDirs.current.backups` (`~/Library/Application Support/VocaGlyph/Backups/`
+/// or `<data-dir>/Backups/`) and are named
+/// `settings-<yyyyMMdd-HHmmss>.json`. Only the newest `maxBackups` files are kept.
+///
+/// This type only reads and writes files; applying a restored snapshot goes through
+/// `SettingsUpdater` so the hotkey and engines are re-configured.
+final class SettingsBackupService {
+
+    static let filePrefix = "settings-"
+    static let maxBackups = 20
+
+    private let directory: URL
+    private let fileManager: FileManager
+    private let now: () -> Date
+
+    static let defaultDirectory: URL = AppDirs.current.backups
+
+    init(directory: URL = SettingsBackupService.defaultDirectory,
+         fileManager: FileManager = .default,
+         now: @escaping () -> Date = Date.init) {
+        self.directory = directory
+        self.fileManager = fileManager
+        self.now = now
+    }
+
+    private static let timestampFormatter: DateFormatter = {
+        let formatter = DateFormatter()
+        formatter.locale = Locale(identifier: "en_US_POSIX")
+        formatter.timeZone = TimeZone(identifier: "UTC")
+        formatter.dateFormat = "yyyyMMdd-HHmmss"
+        return formatter
+    }()
+
+    // MARK: - Backup
+
+    /// Writes `settings` to a new timestamped file and prunes old backups.
+    @discardableResult
+    func backup(_ settings: AppSettings) throws -> SettingsBackup {
+        try fileManager.createDirectory(at: directory, withIntermediateDirectories: true)
+
+        let date = now()
+        var url = fileURL(for: date, suffix: nil)
+        // Two backups in the same second (reset straight after a restore) must not overwrite.
+        var counter = 1
+        while fileManager.fileExists(atPath: url.path) {
+            counter += 1
+            url = fileURL(for: date, suffix: counter)
+        }
+
+        let data = try JSONSerialization.data(
+            withJSONObject: settings.dictionaryRepresentation(),
+            options: [.prettyPrinted, .sortedKeys]
+        )
+        try data.write(to: url, options: .atomic)
+        Logger.shared.info("SettingsBackupService: Saved \(url.lastPathComponent)")
+
+        prune()
+        return SettingsBackup(url: url, createdAt: date)
+    }
+
+    // MARK: - List
+
+    /// Existing backups, newest first.
+    func listBackups() -> [SettingsBackup] {
+        guard let files = try? fileManager.contentsOfDirectory(at: directory, includingPropertiesForKeys: nil) else {
+            return []
+        }
+        return files
+            .filter { $0.lastPathComponent.hasPrefix(Self.filePrefix) && $0.pathExtension == "json" }
+            .compactMap { url in
+                let stamp = url.deletingPathExtension().lastPathComponent
+                    .dropFirst(Self.filePrefix.count)
+                    .prefix(15)
+                guard let date = Self.timestampFormatter.date(from: String(stamp)) else { return nil }
+                return SettingsBackup(url: url, createdAt: date)
+            }
+            .sorted { ($0.createdAt, $0.url.lastPathComponent) > ($1.createdAt, $1.url.lastPathComponent) }
+    }
+
+    // MARK: - Load
+
+    /// Reads and validates a backup. Keys missing from the file take their defaults, so
+    /// backups written by older versions still load.
+    func load(_ backup: SettingsBackup) throws -> AppSettings {
+        guard let data = try? Data(contentsOf: backup.url),
+              let dictionary = (try? JSONSerialization.jsonObject(with: data)) as? [String: Any] else {
+            throw SettingsBackupError.unreadable(backup.id)
+        }
+
+        let issues = SettingsValidator.validate(json: data)
+        guard issues.isEmpty else { throw SettingsBackupError.invalid(issues) }
+
+        var settings = AppSettings.defaults
+        for (name, value) in dictionary {
+            guard let key = AppSettings.Key(rawValue: name) else { continue }
+            _ = settings.assign(value, to: key)
+        }
+        return settings
+    }
+
+    // MARK: - Helpers
+
+    private func fileURL(for date: Date, suffix: Int?) -> URL {
+        var name = Self.filePrefix + Self.timestampFormatter.string(from: date)
+        if let suffix { name += "-\(suffix)" }
+        return directory.appendingPathComponent(name).appendingPathExtension("json")
+    }
+
+    private func prune() {
+        let stale = listBackups().dropFirst(Self.maxBackups)
+        for backup in stale {
+            try? fileManager.removeItem(at: backup.url)
+        }
+    }
+}
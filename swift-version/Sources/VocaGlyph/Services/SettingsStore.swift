@@ -0,0 +1,762 @@
+import Foundation
+
+// MARK: - AppSettings
+
+/// Typed snapshot of the user-facing preferences that services read on hot paths
+/// (every log line, every hotkey reload, every transcription).
+///
+/// Defaults mirror the `@AppStorage` declarations in the Settings views, so a fresh
+/// install behaves the same whether a value is read here or by SwiftUI.
+struct AppSettings: Equatable {
+
+    /// UserDefaults keys backing each field. Raw values are frozen — renaming one loses user data.
+    enum Key: String, CaseIterable {
+        case selectedModel
+        case dictationLanguage
+        case autoPunctuation
+        case removeFillerWords
+        case privacyModeEnabled
+        case enableDebugLogging
+        case enablePostProcessing
+        case selectedTaskModel
+        case selectedCloudProvider
+        case selectedLocalLLMModel
+        case shortcutKeyCode = "customShortcutKeyCode"
+        case shortcutModifiers = "customShortcutModifiers"
+        case hotkeyBackend
+        case contextCaptureEnabled
+        case contextCaptureCharacterLimit
+        case contextCaptureExcludedApps
+        case transcriptionWorkerCount
+        case temperatureFallbackCount
+        case maxConcurrentDownloads
+        case downloadRateLimitMBps
+        case offerRecommendedModelDownload
+        case historyRetention
+        case historyRetentionDays
+        case historyRetentionEntries
+        case redactionEnabled
+        case redactionRules
+        case redactionCustomPatterns
+        case redactLogs
+        case incognitoModeEnabled
+        case incognitoShortcutKeyCode
+        case incognitoShortcutModifiers
+        case wordReplacementsEnabled
+        case spokenPunctuationEnabled
+        case regexRulesEnabled
+        case numberNormalizationEnabled
+        case profanityFilter
+        case profanityCustomWords
+        case outputStyle
+        case translationProvider
+        case translationTargetLanguage
+        case translationEndpoint
+        case customTerms
+        case termCorrectionEnabled
+        case contextPromptTemplate
+        case openAICompatibleEndpoint
+        case openAICompatibleModel
+        case openAICompatibleOptIn
+        case voiceCommandsEnabled
+        case voiceCommandPrefix
+        case punctuationRestorationEnabled
+        case appProcessingRules
+        case summarizeLongDictations
+        case summaryMinimumSeconds
+        case selectionRewriteEnabled
+        case liveTranscriptionPreview
+        case maxRecordingSeconds
+        case automaticUpdateChecks
+        case crashReportingEnabled
+        case systemNotificationsEnabled
+        case controlAPIEnabled
+        case trayIconTheme
+        case trayIconFlashesWhileRecording
+        case showDockIcon
+        case alwaysOnTop
+        case quitWaitsForTranscription
+        case quitWaitSeconds
+        case idleReductionEnabled
+        case idleReductionMinutes
+        case idleReductionUnloadsModel
+        case logLevels
+        case transcriptLogging
+        case otlpExportEnabled
+        case otlpEndpoint
+        case pluginsEnabled
+        case transcriptionServiceEnabled
+        case whisperCppModelPath
+        case processingTimeoutSeconds
+        case hideFromClipboardHistory
+        case liveCaptionsEnabled
+        case liveCaptionsPort
+        case externalTriggersEnabled
+    }
+
+    var selectedModel: String = "apple-native"
+    var dictationLanguage: String = "Auto-Detect"
+    var autoPunctuation: Bool = true
+    var removeFillerWords: Bool = false
+    var privacyModeEnabled: Bool = false
+    var enableDebugLogging: Bool = false
+    var enablePostProcessing: Bool = false
+    var selectedTaskModel: String = "apple-native"
+    var selectedCloudProvider: String = "gemini"
+    var selectedLocalLLMModel: String = "mlx-community/Qwen2.5-1.5B-Instruct-4bit"
+    var shortcutKeyCode: Int = UserDefaults.defaultShortcutKeyCode
+    var shortcutModifiers: UInt64 = UserDefaults.defaultShortcutModifiers
+    var hotkeyBackend: HotkeyBackend = .eventTap
+    /// Off until the user opts in: the text read may go to a cloud post-processing engine.
+    var contextCaptureEnabled: Bool = false
+    var contextCaptureCharacterLimit: Int = ContextCaptureService.defaultCharacterLimit
+    /// Bundle identifiers of apps whose text is never read for context.
+    var contextCaptureExcludedApps: [String] = []
+    /// WhisperKit decoder workers. Tuned per machine by `HardwareDefaultsService`.
+    var transcriptionWorkerCount: Int = 4
+    var temperatureFallbackCount: Int = 1
+    /// Model downloads run at once; more are queued (see `WhisperService.downloadModel(_:)`).
+    var maxConcurrentDownloads: Int = 1
+    /// Download rate cap in MB/s shared by all model downloads; 0 means unlimited.
+    var downloadRateLimitMBps: Int = 0
+    /// Ask at launch, once, to download the recommended model when the selected model
+    /// can't be used and none is downloaded. Cleared when the prompt is shown.
+    var offerRecommendedModelDownload: Bool = true
+    /// How history is pruned: "days", "entries", "unlimited" or "disabled" (see `HistoryService.Retention`).
+    var historyRetention: String = "days"
+    /// Days of history kept when `historyRetention` is "days".
+    var historyRetentionDays: Int = 30
+    /// Newest entries kept when `historyRetention` is "entries".
+    var historyRetentionEntries: Int = 500
+    /// Redact sensitive text before it is saved to history (see `TextRedactor`).
+    var redactionEnabled: Bool = false
+    /// Built-in `TextRedactor.Rule` raw values applied when `redactionEnabled` is on.
+    var redactionRules: [String] = ["creditCard", "email"]
+    /// Extra regular expressions redacted when `redactionEnabled` is on.
+    var redactionCustomPatterns: [String] = []
+    /// Also redact log messages when `redactionEnabled` is on.
+    var redactLogs: Bool = true
+    /// Suspends history, context capture and transcription logging until turned off.
+    var incognitoModeEnabled: Bool = false
+    /// Key code of the incognito toggle shortcut; -1 when unset.
+    var incognitoShortcutKeyCode: Int = -1
+    /// CGEventFlags raw value for `incognitoShortcutKeyCode`.
+    var incognitoShortcutModifiers: UInt64 = 0
+    /// Applies the user's word replacements in the text pipeline.
+    var wordReplacementsEnabled: Bool = true
+    /// Turns spoken commands such as "comma" or "new line" into punctuation.
+    var spokenPunctuationEnabled: Bool = false
+    /// Applies the rules in regex-rules.json in the text pipeline.
+    var regexRulesEnabled: Bool = true
+    /// Writes spoken numbers, times and dates as digits.
+    var numberNormalizationEnabled: Bool = false
+    /// A `ProfanityFilter.Mode` raw value.
+    var profanityFilter: String = "off"
+    /// Extra words for `ProfanityFilter`, on top of the built-in list.
+    var profanityCustomWords: [String] = []
+    /// An `OutputStyle` raw value, applied to every dictation unless a spoken command overrides it.
+    var outputStyle: String = "plain"
+    /// A `TranslationProvider` raw value.
+    var translationProvider: String = "off"
+    /// ISO 639-1 code dictations are translated into, e.g. "de".
+    var translationTargetLanguage: String = ""
+    /// Base URL of a LibreTranslate-compatible server, for the `.remote` provider.
+    var translationEndpoint: String = ""
+    /// Names and jargon `TermCorrector` snaps near-miss transcriptions to, e.g. "Kubernetes".
+    var customTerms: [String] = []
+    /// Fuzzy-corrects transcriptions towards `customTerms`.
+    var termCorrectionEnabled: Bool = true
+    /// Template appended to the AI refinement prompt; empty uses `TemplatePromptRenderer.defaultContextTemplate`.
+    var contextPromptTemplate: String = ""
+    /// Base URL of the OpenAI-compatible API, e.g. "https://api.openai.com/v1".
+    var openAICompatibleEndpoint: String = ""
+    /// Model name sent to the OpenAI-compatible API.
+    var openAICompatibleModel: String = "gpt-4o-mini"
+    /// The user agreed to send transcripts to `openAICompatibleEndpoint`. Off until they opt in.
+    var openAICompatibleOptIn: Bool = false
+    /// Treat utterances that start with `voiceCommandPrefix` as app commands.
+    var voiceCommandsEnabled: Bool = false
+    /// Word(s) that start a voice command, e.g. "computer, switch to Spanish".
+    var voiceCommandPrefix: String = "computer"
+    /// Punctuate unpunctuated transcripts with the local AI model.
+    var punctuationRestorationEnabled: Bool = false
+    /// Per-app pipeline overrides, one `AppProcessingRule` line each.
+    var appProcessingRules: [String] = []
+    /// Offer an AI summary alongside the verbatim text for long dictations.
+    var summarizeLongDictations: Bool = false
+    /// Recording length, in seconds, from which `summarizeLongDictations` applies.
+    var summaryMinimumSeconds: Int = 60
+    /// With text selected, dictation is an instruction for rewriting the selection.
+    var selectionRewriteEnabled: Bool = false
+    /// Re-transcribes the audio so far every couple of seconds and shows it in the recording overlay.
+    var liveTranscriptionPreview: Bool = false
+    /// Recording stops automatically after this many seconds; the overlay warns shortly
+    /// before. 0 (the default) means no limit.
+    var maxRecordingSeconds: Int = 0
+    /// Lets Sparkle check the appcast in the background and offer new versions. Seeded
+    /// from Sparkle's own preference (and its permission prompt) until first stored.
+    var automaticUpdateChecks: Bool = true
+    /// Captures crashes locally so they can be sent after the next launch, with consent.
+    var crashReportingEnabled: Bool = false
+    /// Posts macOS notifications for clipboard fallback, shortcut problems, finished downloads and errors.
+    var systemNotificationsEnabled: Bool = true
+    /// Serves the local JSON control API on a Unix domain socket, guarded by a token.
+    var controlAPIEnabled: Bool = false
+    /// Menu bar icon set: `standard`, `monochrome` or `highContrast`.
+    var trayIconTheme: String = "standard"
+    /// Blink the menu bar icon while recording.
+    var trayIconFlashesWhileRecording: Bool = false
+    /// Keep the Dock icon and Cmd-Tab entry while no window is open.
+    var showDockIcon: Bool = false
+    /// Float the Settings and onboarding windows and the HUD above other apps, full-screen ones included.
+    var alwaysOnTop: Bool = false
+    /// On quit, let a dictation that is still recording or transcribing finish first.
+    var quitWaitsForTranscription: Bool = true
+    /// Longest wait for that dictation before asking whether to quit anyway.
+    var quitWaitSeconds: Int = 20
+    /// Reduce background work after `idleReductionMinutes` without dictation.
+    var idleReductionEnabled: Bool = false
+    /// Minutes without dictation before background work is reduced.
+    var idleReductionMinutes: Int = 15
+    /// Also unload the transcription and local LLM models while idle.
+    var idleReductionUnloadsModel: Bool = false
+    /// Per-component minimum log levels, one `LogLevels` line (`component=level`) each.
+    var logLevels: [String] = []
+    /// How transcripts appear in the logs — a `TranscriptLogging` value.
+    var transcriptLogging: String = "full"
+    /// Export dictation metrics and traces to an OpenTelemetry collector (OTLP/HTTP JSON).
+    var otlpExportEnabled: Bool = false
+    /// Base URL of the OTLP/HTTP collector; /v1/metrics and /v1/traces are appended.
+    var otlpEndpoint: String = "http://localhost:4318"
+    /// Load the plugins in the plugins folder as text processors and output sinks.
+    var pluginsEnabled: Bool = false
+    /// Serve the loaded model to other local apps on transcribe.sock.
+    var transcriptionServiceEnabled: Bool = false
+    /// ggml/GGUF model file for the whisper-cpp engine.
+    var whisperCppModelPath: String = ""
+    /// Seconds a dictation may go without progress in Processing before `ProcessingWatchdog`
+    /// gives up on it and reloads the engine; transcription gets more for long recordings.
+    /// 0 turns the watchdog off.
+    var processingTimeoutSeconds: Int = 90
+    /// Marks the clipboard copy `OutputService` pastes from as transient, so clipboard
+    /// history managers skip dictations. Deliberate copies are never marked.
+    var hideFromClipboardHistory: Bool = true
+    /// Streams partial and final transcriptions to `CaptionServer` clients, e.g. an OBS browser source.
+    var liveCaptionsEnabled: Bool = false
+    /// Port of `CaptionServer` on 127.0.0.1.
+    var liveCaptionsPort: Int = 7979
+    /// Starts and stops dictation on `com.vocaglyph.dictation.*` distributed notifications, which any local process can post.
+    var externalTriggersEnabled: Bool = false
+
+    static let defaults = AppSettings()
+
+    /// Reads every field from `defaults`, falling back to the built-in default for
+    /// keys that were never written.
+    init(from defaults: UserDefaults) {
+        let fallback = AppSettings.defaults
+        func string(_ key: Key, _ value: String) -> String { defaults.string(forKey: key.rawValue) ?? value }
+        func bool(_ key: Key, _ value: Bool) -> Bool { defaults.object(forKey: key.rawValue) as? Bool ?? value }
+
+        selectedModel = string(.selectedModel, fallback.selectedModel)
+        dictationLanguage = string(.dictationLanguage, fallback.dictationLanguage)
+        autoPunctuation = bool(.autoPunctuation, fallback.autoPunctuation)
+        removeFillerWords = bool(.removeFillerWords, fallback.removeFillerWords)
+        privacyModeEnabled = bool(.privacyModeEnabled, fallback.privacyModeEnabled)
+        enableDebugLogging = bool(.enableDebugLogging, fallback.enableDebugLogging)
+        enablePostProcessing = bool(.enablePostProcessing, fallback.enablePostProcessing)
+        selectedTaskModel = string(.selectedTaskModel, fallback.selectedTaskModel)
+        selectedCloudProvider = string(.selectedCloudProvider, fallback.selectedCloudProvider)
+        selectedLocalLLMModel = string(.selectedLocalLLMModel, fallback.selectedLocalLLMModel)
+        // Shortcut values are written as Double by @AppStorage and as Int/UInt64 elsewhere.
+        if let number = defaults.object(forKey: Key.shortcutKeyCode.rawValue) as? NSNumber {
+            shortcutKeyCode = number.intValue
+        }
+        if let number = defaults.object(forKey: Key.shortcutModifiers.rawValue) as? NSNumber {
+            shortcutModifiers = number.uint64Value
+        }
+        hotkeyBackend = defaults.string(forKey: Key.hotkeyBackend.rawValue).flatMap(HotkeyBackend.init(rawValue:)) ?? fallback.hotkeyBackend
+        contextCaptureEnabled = bool(.contextCaptureEnabled, fallback.contextCaptureEnabled)
+        if let number = defaults.object(forKey: Key.contextCaptureCharacterLimit.rawValue) as? NSNumber {
+            contextCaptureCharacterLimit = number.intValue
+        }
+        contextCaptureExcludedApps = defaults.stringArray(forKey: Key.contextCaptureExcludedApps.rawValue) ?? fallback.contextCaptureExcludedApps
+        if let number = defaults.object(forKey: Key.transcriptionWorkerCount.rawValue) as? NSNumber {
+            transcriptionWorkerCount = number.intValue
+        }
+        if let number = defaults.object(forKey: Key.temperatureFallbackCount.rawValue) as? NSNumber {
+            temperatureFallbackCount = number.intValue
+        }
+        if let number = defaults.object(forKey: Key.maxConcurrentDownloads.rawValue) as? NSNumber {
+            maxConcurrentDownloads = number.intValue
+        }
+        if let number = defaults.object(forKey: Key.downloadRateLimitMBps.rawValue) as? NSNumber {
+            downloadRateLimitMBps = number.intValue
+        }
+        offerRecommendedModelDownload = bool(.offerRecommendedModelDownload, fallback.offerRecommendedModelDownload)
+        historyRetention = string(.historyRetention, fallback.historyRetention)
+        if let number = defaults.object(forKey: Key.historyRetentionDays.rawValue) as? NSNumber {
+            historyRetentionDays = number.intValue
+        }
+        if let number = defaults.object(forKey: Key.historyRetentionEntries.rawValue) as? NSNumber {
+            historyRetentionEntries = number.intValue
+        }
+        redactionEnabled = bool(.redactionEnabled, fallback.redactionEnabled)
+        redactionRules = defaults.stringArray(forKey: Key.redactionRules.rawValue) ?? fallback.redactionRules
+        redactionCustomPatterns = defaults.stringArray(forKey: Key.redactionCustomPatterns.rawValue) ?? fallback.redactionCustomPatterns
+        redactLogs = bool(.redactLogs, fallback.redactLogs)
+        incognitoModeEnabled = bool(.incognitoModeEnabled, fallback.incognitoModeEnabled)
+        if let number = defaults.object(forKey: Key.incognitoShortcutKeyCode.rawValue) as? NSNumber {
+            incognitoShortcutKeyCode = number.intValue
+        }
+        if let number = defaults.object(forKey: Key.incognitoShortcutModifiers.rawValue) as? NSNumber {
+            incognitoShortcutModifiers = number.uint64Value
+        }
+        wordReplacementsEnabled = bool(.wordReplacementsEnabled, fallback.wordReplacementsEnabled)
+        spokenPunctuationEnabled = bool(.spokenPunctuationEnabled, fallback.spokenPunctuationEnabled)
+        regexRulesEnabled = bool(.regexRulesEnabled, fallback.regexRulesEnabled)
+        numberNormalizationEnabled = bool(.numberNormalizationEnabled, fallback.numberNormalizationEnabled)
+        profanityFilter = string(.profanityFilter, fallback.profanityFilter)
+        profanityCustomWords = defaults.stringArray(forKey: Key.profanityCustomWords.rawValue) ?? fallback.profanityCustomWords
+        outputStyle = string(.outputStyle, fallback.outputStyle)
+        translationProvider = string(.translationProvider, fallback.translationProvider)
+        translationTargetLanguage = string(.translationTargetLanguage, fallback.translationTargetLanguage)
+        translationEndpoint = string(.translationEndpoint, fallback.translationEndpoint)
+        customTerms = defaults.stringArray(forKey: Key.customTerms.rawValue) ?? fallback.customTerms
+        termCorrectionEnabled = bool(.termCorrectionEnabled, fallback.termCorrectionEnabled)
+        contextPromptTemplate = string(.contextPromptTemplate, fallback.contextPromptTemplate)
+        openAICompatibleEndpoint = string(.openAICompatibleEndpoint, fallback.openAICompatibleEndpoint)
+        openAICompatibleModel = string(.openAICompatibleModel, fallback.openAICompatibleModel)
+        openAICompatibleOptIn = bool(.openAICompatibleOptIn, fallback.openAICompatibleOptIn)
+        voiceCommandsEnabled = bool(.voiceCommandsEnabled, fallback.voiceCommandsEnabled)
+        voiceCommandPrefix = string(.voiceCommandPrefix, fallback.voiceCommandPrefix)
+        punctuationRestorationEnabled = bool(.punctuationRestorationEnabled, fallback.punctuationRestorationEnabled)
+        appProcessingRules = defaults.stringArray(forKey: Key.appProcessingRules.rawValue) ?? fallback.appProcessingRules
+        summarizeLongDictations = bool(.summarizeLongDictations, fallback.summarizeLongDictations)
+        if let number = defaults.object(forKey: Key.summaryMinimumSeconds.rawValue) as? NSNumber {
+            summaryMinimumSeconds = number.intValue
+        }
+        selectionRewriteEnabled = bool(.selectionRewriteEnabled, fallback.selectionRewriteEnabled)
+        liveTranscriptionPreview = bool(.liveTranscriptionPreview, fallback.liveTranscriptionPreview)
+        if let number = defaults.object(forKey: Key.maxRecordingSeconds.rawValue) as? NSNumber {
+            maxRecordingSeconds = number.intValue
+        }
+        automaticUpdateChecks = bool(.automaticUpdateChecks, fallback.automaticUpdateChecks)
+        crashReportingEnabled = bool(.crashReportingEnabled, fallback.crashReportingEnabled)
+        systemNotificationsEnabled = bool(.systemNotificationsEnabled, fallback.systemNotificationsEnabled)
+        controlAPIEnabled = bool(.controlAPIEnabled, fallback.controlAPIEnabled)
+        trayIconTheme = string(.trayIconTheme, fallback.trayIconTheme)
+        trayIconFlashesWhileRecording = bool(.trayIconFlashesWhileRecording, fallback.trayIconFlashesWhileRecording)
+        showDockIcon = bool(.showDockIcon, fallback.showDockIcon)
+        alwaysOnTop = bool(.alwaysOnTop, fallback.alwaysOnTop)
+        quitWaitsForTranscription = bool(.quitWaitsForTranscription, fallback.quitWaitsForTranscription)
+        if let number = defaults.object(forKey: Key.quitWaitSeconds.rawValue) as? NSNumber {
+            quitWaitSeconds = number.intValue
+        }
+        idleReductionEnabled = bool(.idleReductionEnabled, fallback.idleReductionEnabled)
+        if let number = defaults.object(forKey: Key.idleReductionMinutes.rawValue) as? NSNumber {
+            idleReductionMinutes = number.intValue
+        }
+        idleReductionUnloadsModel = bool(.idleReductionUnloadsModel, fallback.idleReductionUnloadsModel)
+        logLevels = defaults.stringArray(forKey: Key.logLevels.rawValue) ?? fallback.logLevels
+        transcriptLogging = string(.transcriptLogging, fallback.transcriptLogging)
+        otlpExportEnabled = bool(.otlpExportEnabled, fallback.otlpExportEnabled)
+        otlpEndpoint = string(.otlpEndpoint, fallback.otlpEndpoint)
+        pluginsEnabled = bool(.pluginsEnabled, fallback.pluginsEnabled)
+        transcriptionServiceEnabled = bool(.transcriptionServiceEnabled, fallback.transcriptionServiceEnabled)
+        whisperCppModelPath = string(.whisperCppModelPath, fallback.whisperCppModelPath)
+        if let number = defaults.object(forKey: Key.processingTimeoutSeconds.rawValue) as? NSNumber {
+            processingTimeoutSeconds = number.intValue
+        }
+        hideFromClipboardHistory = bool(.hideFromClipboardHistory, fallback.hideFromClipboardHistory)
+        liveCaptionsEnabled = bool(.liveCaptionsEnabled, fallback.liveCaptionsEnabled)
+        if let number = defaults.object(forKey: Key.liveCaptionsPort.rawValue) as? NSNumber {
+            liveCaptionsPort = number.intValue
+        }
+        externalTriggersEnabled = bool(.externalTriggersEnabled, fallback.externalTriggersEnabled)
+    }
+
+    init() {}
+
+    /// The keys whose values differ between `self` and `other`.
+    func changedKeys(from other: AppSettings) -> Set<Key> {
+        var keys: Set<Key> = []
+        if selectedModel != other.selectedModel { keys.insert(.selectedModel) }
+        if dictationLanguage != other.dictationLanguage { keys.insert(.dictationLanguage) }
+        if autoPunctuation != other.autoPunctuation { keys.insert(.autoPunctuation) }
+        if removeFillerWords != other.removeFillerWords { keys.insert(.removeFillerWords) }
+        if privacyModeEnabled != other.privacyModeEnabled { keys.insert(.privacyModeEnabled) }
+        if enableDebugLogging != other.enableDebugLogging { keys.insert(.enableDebugLogging) }
+        if enablePostProcessing != other.enablePostProcessing { keys.insert(.enablePostProcessing) }
+        if selectedTaskModel != other.selectedTaskModel { keys.insert(.selectedTaskModel) }
+        if selectedCloudProvider != other.selectedCloudProvider { keys.insert(.selectedCloudProvider) }
+        if selectedLocalLLMModel != other.selectedLocalLLMModel { keys.insert(.selectedLocalLLMModel) }
+        if shortcutKeyCode != other.shortcutKeyCode { keys.insert(.shortcutKeyCode) }
+        if shortcutModifiers != other.shortcutModifiers { keys.insert(.shortcutModifiers) }
+        if hotkeyBackend != other.hotkeyBackend { keys.insert(.hotkeyBackend) }
+        if contextCaptureEnabled != other.contextCaptureEnabled { keys.insert(.contextCaptureEnabled) }
+        if contextCaptureCharacterLimit != other.contextCaptureCharacterLimit { keys.insert(.contextCaptureCharacterLimit) }
+        if contextCaptureExcludedApps != other.contextCaptureExcludedApps { keys.insert(.contextCaptureExcludedApps) }
+        if transcriptionWorkerCount != other.transcriptionWorkerCount { keys.insert(.transcriptionWorkerCount) }
+        if temperatureFallbackCount != other.temperatureFallbackCount { keys.insert(.temperatureFallbackCount) }
+        if maxConcurrentDownloads != other.maxConcurrentDownloads { keys.insert(.maxConcurrentDownloads) }
+        if downloadRateLimitMBps != other.downloadRateLimitMBps { keys.insert(.downloadRateLimitMBps) }
+        if offerRecommendedModelDownload != other.offerRecommendedModelDownload { keys.insert(.offerRecommendedModelDownload) }
+        if historyRetention != other.historyRetention { keys.insert(.historyRetention) }
+        if historyRetentionDays != other.historyRetentionDays { keys.insert(.historyRetentionDays) }
+        if historyRetentionEntries != other.historyRetentionEntries { keys.insert(.historyRetentionEntries) }
+        if redactionEnabled != other.redactionEnabled { keys.insert(.redactionEnabled) }
+        if redactionRules != other.redactionRules { keys.insert(.redactionRules) }
+        if redactionCustomPatterns != other.redactionCustomPatterns { keys.insert(.redactionCustomPatterns) }
+        if redactLogs != other.redactLogs { keys.insert(.redactLogs) }
+        if incognitoModeEnabled != other.incognitoModeEnabled { keys.insert(.incognitoModeEnabled) }
+        if incognitoShortcutKeyCode != other.incognitoShortcutKeyCode { keys.insert(.incognitoShortcutKeyCode) }
+        if incognitoShortcutModifiers != other.incognitoShortcutModifiers { keys.insert(.incognitoShortcutModifiers) }
+        if wordReplacementsEnabled != other.wordReplacementsEnabled { keys.insert(.wordReplacementsEnabled) }
+        if spokenPunctuationEnabled != other.spokenPunctuationEnabled { keys.insert(.spokenPunctuationEnabled) }
+        if regexRulesEnabled != other.regexRulesEnabled { keys.insert(.regexRulesEnabled) }
+        if numberNormalizationEnabled != other.numberNormalizationEnabled { keys.insert(.numberNormalizationEnabled) }
+        if profanityFilter != other.profanityFilter { keys.insert(.profanityFilter) }
+        if profanityCustomWords != other.profanityCustomWords { keys.insert(.profanityCustomWords) }
+        if outputStyle != other.outputStyle { keys.insert(.outputStyle) }
+        if translationProvider != other.translationProvider { keys.insert(.translationProvider) }
+        if translationTargetLanguage != other.translationTargetLanguage { keys.insert(.translationTargetLanguage) }
+        if translationEndpoint != other.translationEndpoint { keys.insert(.translationEndpoint) }
+        if customTerms != other.customTerms { keys.insert(.customTerms) }
+        if termCorrectionEnabled != other.termCorrectionEnabled { keys.insert(.termCorrectionEnabled) }
+        if contextPromptTemplate != other.contextPromptTemplate { keys.insert(.contextPromptTemplate) }
+        if openAICompatibleEndpoint != other.openAICompatibleEndpoint { keys.insert(.openAICompatibleEndpoint) }
+        if openAICompatibleModel != other.openAICompatibleModel { keys.insert(.openAICompatibleModel) }
+        if openAICompatibleOptIn != other.openAICompatibleOptIn { keys.insert(.openAICompatibleOptIn) }
+        if voiceCommandsEnabled != other.voiceCommandsEnabled { keys.insert(.voiceCommandsEnabled) }
+        if voiceCommandPrefix != other.voiceCommandPrefix { keys.insert(.voiceCommandPrefix) }
+        if punctuationRestorationEnabled != other.punctuationRestorationEnabled { keys.insert(.punctuationRestorationEnabled) }
+        if appProcessingRules != other.appProcessingRules { keys.insert(.appProcessingRules) }
+        if summarizeLongDictations != other.summarizeLongDictations { keys.insert(.summarizeLongDictations) }
+        if summaryMinimumSeconds != other.summaryMinimumSeconds { keys.insert(.summaryMinimumSeconds) }
+        if selectionRewriteEnabled != other.selectionRewriteEnabled { keys.insert(.selectionRewriteEnabled) }
+        if liveTranscriptionPreview != other.liveTranscriptionPreview { keys.insert(.liveTranscriptionPreview) }
+        if maxRecordingSeconds != other.maxRecordingSeconds { keys.insert(.maxRecordingSeconds) }
+        if automaticUpdateChecks != other.automaticUpdateChecks { keys.insert(.automaticUpdateChecks) }
+        if crashReportingEnabled != other.crashReportingEnabled { keys.insert(.crashReportingEnabled) }
+        if systemNotificationsEnabled != other.systemNotificationsEnabled { keys.insert(.systemNotificationsEnabled) }
+        if controlAPIEnabled != other.controlAPIEnabled { keys.insert(.controlAPIEnabled) }
+        if trayIconTheme != other.trayIconTheme { keys.insert(.trayIconTheme) }
+        if trayIconFlashesWhileRecording != other.trayIconFlashesWhileRecording { keys.insert(.trayIconFlashesWhileRecording) }
+        if showDockIcon != other.showDockIcon { keys.insert(.showDockIcon) }
+        if alwaysOnTop != other.alwaysOnTop { keys.insert(.alwaysOnTop) }
+        if quitWaitsForTranscription != other.quitWaitsForTranscription { keys.insert(.quitWaitsForTranscription) }
+        if quitWaitSeconds != other.quitWaitSeconds { keys.insert(.quitWaitSeconds) }
+        if idleReductionEnabled != other.idleReductionEnabled { keys.insert(.idleReductionEnabled) }
+        if idleReductionMinutes != other.idleReductionMinutes { keys.insert(.idleReductionMinutes) }
+        if idleReductionUnloadsModel != other.idleReductionUnloadsModel { keys.insert(.idleReductionUnloadsModel) }
+        if logLevels != other.logLevels { keys.insert(.logLevels) }
+        if transcriptLogging != other.transcriptLogging { keys.insert(.transcriptLogging) }
+        if otlpExportEnabled != other.otlpExportEnabled { keys.insert(.otlpExportEnabled) }
+        if otlpEndpoint != other.otlpEndpoint { keys.insert(.otlpEndpoint) }
+        if pluginsEnabled != other.pluginsEnabled { keys.insert(.pluginsEnabled) }
+        if transcriptionServiceEnabled != other.transcriptionServiceEnabled { keys.insert(.transcriptionServiceEnabled) }
+        if whisperCppModelPath != other.whisperCppModelPath { keys.insert(.whisperCppModelPath) }
+        if processingTimeoutSeconds != other.processingTimeoutSeconds { keys.insert(.processingTimeoutSeconds) }
+        if hideFromClipboardHistory != other.hideFromClipboardHistory { keys.insert(.hideFromClipboardHistory) }
+        if liveCaptionsEnabled != other.liveCaptionsEnabled { keys.insert(.liveCaptionsEnabled) }
+        if liveCaptionsPort != other.liveCaptionsPort { keys.insert(.liveCaptionsPort) }
+        if externalTriggersEnabled != other.externalTriggersEnabled { keys.insert(.externalTriggersEnabled) }
+        return keys
+    }
+
+    /// Writes only the given keys to `defaults`. Shortcut values are stored as Double to
+    /// match the `@AppStorage` bindings in `RecordingSetupSection` and `PrivacySettingsSection`.
+    func write(_ keys: Set<Key>, to defaults: UserDefaults) {
+        for key in keys {
+            defaults.set(storedValue(for: key), forKey: key.rawValue)
+        }
+    }
+
+    /// Every field as a property-list value keyed by its UserDefaults key — the same
+    /// shape `SettingsValidator.validate(json:)` and `assign(_:to:)` accept.
+    func dictionaryRepresentation() -> [String: Any] {
+        Dictionary(uniqueKeysWithValues: Key.allCases.map { ($0.rawValue, storedValue(for: $0)) })
+    }
+
+    /// The value written to `UserDefaults` for `key`.
+    private func storedValue(for key: Key) -> Any {
+        switch key {
+        case .selectedModel:         return selectedModel
+        case .dictationLanguage:     return dictationLanguage
+        case .autoPunctuation:       return autoPunctuation
+        case .removeFillerWords:     return removeFillerWords
+        case .privacyModeEnabled:    return privacyModeEnabled
+        case .enableDebugLogging:    return enableDebugLogging
+        case .enablePostProcessing:  return enablePostProcessing
+        case .selectedTaskModel:     return selectedTaskModel
+        case .selectedCloudProvider: return selectedCloudProvider
+        case .selectedLocalLLMModel: return selectedLocalLLMModel
+        case .shortcutKeyCode:       return shortcutKeyCode
+        case .shortcutModifiers:     return Double(shortcutModifiers)
+        case .hotkeyBackend:         return hotkeyBackend.rawValue
+        case .contextCaptureEnabled: return contextCaptureEnabled
+        case .contextCaptureCharacterLimit: return contextCaptureCharacterLimit
+        case .contextCaptureExcludedApps:   return contextCaptureExcludedApps
+        case .transcriptionWorkerCount: return transcriptionWorkerCount
+        case .temperatureFallbackCount: return temperatureFallbackCount
+        case .maxConcurrentDownloads: return maxConcurrentDownloads
+        case .downloadRateLimitMBps: return downloadRateLimitMBps
+        case .offerRecommendedModelDownload: return offerRecommendedModelDownload
+        case .historyRetention: return historyRetention
+        case .historyRetentionDays: return historyRetentionDays
+        case .historyRetentionEntries: return historyRetentionEntries
+        case .redactionEnabled: return redactionEnabled
+        case .redactionRules: return redactionRules
+        case .redactionCustomPatterns: return redactionCustomPatterns
+        case .redactLogs: return redactLogs
+        case .incognitoModeEnabled: return incognitoModeEnabled
+        case .incognitoShortcutKeyCode: return incognitoShortcutKeyCode
+        case .incognitoShortcutModifiers: return Double(incognitoShortcutModifiers)
+        case .wordReplacementsEnabled: return wordReplacementsEnabled
+        case .spokenPunctuationEnabled: return spokenPunctuationEnabled
+        case .regexRulesEnabled: return regexRulesEnabled
+        case .numberNormalizationEnabled: return numberNormalizationEnabled
+        case .profanityFilter: return profanityFilter
+        case .profanityCustomWords: return profanityCustomWords
+        case .outputStyle: return outputStyle
+        case .translationProvider: return translationProvider
+        case .translationTargetLanguage: return translationTargetLanguage
+        case .translationEndpoint: return translationEndpoint
+        case .customTerms: return customTerms
+        case .termCorrectionEnabled: return termCorrectionEnabled
+        case .contextPromptTemplate: return contextPromptTemplate
+        case .openAICompatibleEndpoint: return openAICompatibleEndpoint
+        case .openAICompatibleModel: return openAICompatibleModel
+        case .openAICompatibleOptIn: return openAICompatibleOptIn
+        case .voiceCommandsEnabled: return voiceCommandsEnabled
+        case .voiceCommandPrefix: return voiceCommandPrefix
+        case .punctuationRestorationEnabled: return punctuationRestorationEnabled
+        case .appProcessingRules: return appProcessingRules
+        case .summarizeLongDictations: return summarizeLongDictations
+        case .summaryMinimumSeconds: return summaryMinimumSeconds
+        case .selectionRewriteEnabled: return selectionRewriteEnabled
+        case .liveTranscriptionPreview: return liveTranscriptionPreview
+        case .maxRecordingSeconds: return maxRecordingSeconds
+        case .automaticUpdateChecks: return automaticUpdateChecks
+        case .crashReportingEnabled: return crashReportingEnabled
+        case .systemNotificationsEnabled: return systemNotificationsEnabled
+        case .controlAPIEnabled: return controlAPIEnabled
+        case .trayIconTheme: return trayIconTheme
+        case .trayIconFlashesWhileRecording: return trayIconFlashesWhileRecording
+        case .showDockIcon: return showDockIcon
+        case .alwaysOnTop: return alwaysOnTop
+        case .quitWaitsForTranscription: return quitWaitsForTranscription
+        case .quitWaitSeconds: return quitWaitSeconds
+        case .idleReductionEnabled: return idleReductionEnabled
+        case .idleReductionMinutes: return idleReductionMinutes
+        case .idleReductionUnloadsModel: return idleReductionUnloadsModel
+        case .logLevels: return logLevels
+        case .transcriptLogging: return transcriptLogging
+        case .otlpExportEnabled: return otlpExportEnabled
+        case .otlpEndpoint: return otlpEndpoint
+        case .pluginsEnabled: return pluginsEnabled
+        case .transcriptionServiceEnabled: return transcriptionServiceEnabled
+        case .whisperCppModelPath: return whisperCppModelPath
+        case .processingTimeoutSeconds: return processingTimeoutSeconds
+        case .hideFromClipboardHistory: return hideFromClipboardHistory
+        case .liveCaptionsEnabled: return liveCaptionsEnabled
+        case .liveCaptionsPort: return liveCaptionsPort
+        case .externalTriggersEnabled: return externalTriggersEnabled
+        }
+    }
+}
+
+// MARK: - SettingsStore
+
+/// Thread-safe, in-memory cache of `AppSettings`.
+///
+/// - `settings` returns the cached snapshot without touching `UserDefaults`.
+/// - `update(_:)` mutates a copy, writes only the changed keys and notifies subscribers.
+/// - The cache re-reads `UserDefaults` on `didChangeNotification` (covers `@AppStorage`
+///   writes from the Settings UI) and when `reload()` is called (used by
+///   `PreferencesWatcher` for external `defaults write` edits).
+///
+/// Launch overrides (`--model`, `--language`, `--hotkey`) are layered over the persisted
+/// values for the current run only. They are never written to `UserDefaults`, and an
+/// override is dropped as soon as the user changes that field.
+///
+/// Subscribers are called on the thread that triggered the change, after the lock is
+/// released, with the old and new snapshots.
+final class SettingsStore: @unchecked Sendable {
+
+    static let shared = SettingsStore()
+
+    typealias Subscriber = (_ old: AppSettings, _ new: AppSettings) -> Void
+
+    private let defaults: UserDefaults
+    private let notificationCenter: NotificationCenter
+    // Recursive: `defaults.set` posts didChangeNotification synchronously, re-entering
+    // `reload()` on the same thread while `update(_:)` still holds the lock.
+    private let lock = NSRecursiveLock()
+    private var cached: AppSettings
+    /// Values as stored in `UserDefaults`, before launch overrides.
+    private var persisted: AppSettings
+    private var overrides: [AppSettings.Key: Any] = [:]
+    private var subscribers: [UUID: Subscriber] = [:]
+    private var isWriting = false
+    private var defaultsObserver: NSObjectProtocol?
+
+    init(defaults: UserDefaults = .standard, notificationCenter: NotificationCenter = .default) {
+        self.defaults = defaults
+        self.notificationCenter = notificationCenter
+        self.persisted = AppSettings(from: defaults)
+        self.cached = persisted
+        defaultsObserver = notificationCenter.addObserver(
+            forName: UserDefaults.didChangeNotification,
+            object: defaults,
+            queue: nil
+        ) { [weak self] _ in
+            self?.reload()
+        }
+    }
+
+    deinit {
+        if let defaultsObserver {
+            notificationCenter.removeObserver(defaultsObserver)
+        }
+    }
+
+    // MARK: - Read
+
+    /// The current cached snapshot.
+    var settings: AppSettings {
+        lock.lock(); defer { lock.unlock() }
+        return cached
+    }
+
+    // MARK: - Write
+
+    /// Applies `mutate` to a copy of the current settings and persists the changed keys.
+    /// Returns the set of keys that actually changed.
+    @discardableResult
+    func update(_ mutate: (inout AppSettings) -> Void) -> Set<AppSettings.Key> {
+        lock.lock()
+        let old = cached
+        var new = old
+        mutate(&new)
+        let changed = new.changedKeys(from: old)
+        guard !changed.isEmpty else {
+            lock.unlock()
+            return []
+        }
+        cached = new
+        // Suppress the reload that our own write triggers via didChangeNotification.
+        isWriting = true
+        new.write(changed, to: defaults)
+        isWriting = false
+        persisted = AppSettings(from: defaults)
+        changed.forEach { overrides[$0] = nil }
+        lock.unlock()
+
+        notify(old: old, new: new)
+        return changed
+    }
+
+    /// Re-reads `UserDefaults` and notifies subscribers if anything changed.
+    func reload() {
+        lock.lock()
+        guard !isWriting else {
+            lock.unlock()
+            return
+        }
+        let newPersisted = AppSettings(from: defaults)
+        // A persisted change to an overridden field means the user picked a new value.
+        newPersisted.changedKeys(from: persisted).forEach { overrides[$0] = nil }
+        persisted = newPersisted
+
+        let old = cached
+        let new = composed()
+        guard new != old else {
+            lock.unlock()
+            return
+        }
+        cached = new
+        lock.unlock()
+
+        notify(old: old, new: new)
+    }
+
+    // MARK: - Launch Overrides
+
+    /// Layers `values` over the persisted settings for this run. Values with the wrong
+    /// type are ignored (and logged).
+    func applyLaunchOverrides(_ values: [AppSettings.Key: Any]) {
+        guard !values.isEmpty else { return }
+        lock.lock()
+        overrides.merge(values) { _, new in new }
+        let old = cached
+        let new = composed()
+        cached = new
+        lock.unlock()
+
+        Logger.shared.info("SettingsStore: Launch overrides active for \(values.keys.map(\.rawValue).sorted().joined(separator: ", "))")
+        if new != old { notify(old: old, new: new) }
+    }
+
+    /// `true` while `key` is served from a launch override rather than `UserDefaults`.
+    func isOverridden(_ key: AppSettings.Key) -> Bool {
+        lock.lock(); defer { lock.unlock() }
+        return overrides[key] != nil
+    }
+
+    /// Persisted values with overrides applied. Caller must hold the lock.
+    private func composed() -> AppSettings {
+        var result = persisted
+        for (key, value) in overrides {
+            if let message = result.assign(value, to: key) {
+                Logger.shared.error("SettingsStore: Ignoring override for \(key.rawValue) — \(message)")
+                overrides[key] = nil
+            }
+        }
+        return result
+    }
+
+    // MARK: - Subscriptions
+
+    /// Registers `handler` for future changes. Keep the returned token alive; the
+    /// subscription ends when it is cancelled or deallocated.
+    func subscribe(_ handler: @escaping Subscriber) -> SettingsSubscription {
+        let id = UUID()
+        lock.lock()
+        subscribers[id] = handler
+        lock.unlock()
+        return SettingsSubscription { [weak self] in
+            guard let self else { return }
+            self.lock.lock()
+            self.subscribers[id] = nil
+            self.lock.unlock()
+        }
+    }
+
+    private func notify(old: AppSettings, new: AppSettings) {
+        lock.lock()
+        let handlers = Array(subscribers.values)
+        lock.unlock()
+        handlers.forEach { $0(old, new) }
+    }
+}
+
+// MARK: - SettingsSubscription
+
+/// Cancellation token returned by `SettingsStore.subscribe(_:)`.
+final class SettingsSubscription {
+    private var onCancel: (() -> Void)?
+
+    init(onCancel: @escaping () -> Void) {
+        self.onCancel = onCancel
+    }
+
+    deinit {
+        cancel()
+    }
+
+    func cancel() {
+        onCancel?()
+        onCancel = nil
+    }
+}
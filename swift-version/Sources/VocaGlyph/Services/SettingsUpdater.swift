@@ -0,0 +1,142 @@
+import Foundation
+
+// MARK: - SettingsApplying
+
+/// How a shortcut change took effect.
+enum HotkeyApplyOutcome: Equatable {
+    /// The listener is running with the new shortcut.
+    case registered
+    /// No listener is running (e.g. Accessibility not granted yet); the shortcut is used
+    /// as soon as one starts.
+    case pending
+    /// The listener was running and could not be re-installed.
+    case failed
+}
+
+/// Side effects that must run when certain settings change. Implemented by `AppDelegate`,
+/// which owns the long-lived services; mocked in tests.
+protocol SettingsApplying: AnyObject {
+    /// Re-registers the global shortcut.
+    func applyHotkeyChange() -> HotkeyApplyOutcome
+    /// Loads and routes to `model` (mirrors the Model tab's "Use Model" action).
+    func applyModelChange(_ model: String)
+    /// Re-creates the post-processing engine from the current settings.
+    func applyPostProcessingChange()
+}
+
+// MARK: - SettingsUpdateResult
+
+/// Outcome of `SettingsUpdater.update(_:)`.
+struct SettingsUpdateResult: Equatable {
+    /// Keys written and applied.
+    var appliedKeys: Set<AppSettings.Key> = []
+    /// Keys written but not in effect yet (the shortcut while no listener is running).
+    var pendingKeys: Set<AppSettings.Key> = []
+    /// Keys that were written but then reverted because applying them failed.
+    var revertedKeys: Set<AppSettings.Key> = []
+    /// Validation or apply failures, one per field.
+    var issues: [SettingsValidationIssue] = []
+
+    var succeeded: Bool { issues.isEmpty }
+}
+
+// MARK: - SettingsUpdater
+
+/// Single entry point for changing several settings at once.
+///
+/// 1. Validates the snapshot — if the update introduces an issue, nothing is written. A
+///    field that was already invalid (say, a selected model deleted since) doesn't block
+///    unrelated changes.
+/// 2. Writes every changed field in one `SettingsStore.update` call.
+/// 3. Applies side effects (hotkey, model, post-processing). A shortcut the running
+///    listener cannot take is rolled back and reported; with no listener running it is
+///    kept and reported as pending.
+///
+/// Model loads are asynchronous; a model change is reported as applied once the load
+/// has been started.
+final class SettingsUpdater {
+
+    private let store: SettingsStore
+    private weak var applier: SettingsApplying?
+    private let downloadedModels: () -> Set<String>?
+
+    static let hotkeyKeys: Set<AppSettings.Key> = [.shortcutKeyCode, .shortcutModifiers, .hotkeyBackend]
+    static let postProcessingKeys: Set<AppSettings.Key> = [.enablePostProcessing, .selectedTaskModel, .selectedCloudProvider, .selectedLocalLLMModel]
+
+    init(store: SettingsStore = .shared,
+         applier: SettingsApplying,
+         downloadedModels: @escaping () -> Set<String>? = { nil }) {
+        self.store = store
+        self.applier = applier
+        self.downloadedModels = downloadedModels
+    }
+
+    /// Validates, persists and applies `settings`. Must be called on the main thread.
+    @discardableResult
+    func update(_ settings: AppSettings) -> SettingsUpdateResult {
+        var result = SettingsUpdateResult()
+
+        let issues = issues(in: settings)
+        guard issues.isEmpty else {
+            Logger.shared.info("SettingsUpdater: Rejected update — \(issues.count) invalid field(s)")
+            result.issues = issues
+            return result
+        }
+
+        let previous = store.settings
+        let changed = store.update { $0 = settings }
+        guard !changed.isEmpty else { return result }
+        result.appliedKeys = changed
+        Logger.shared.info("SettingsUpdater: Applying \(changed.map(\.rawValue).sorted().joined(separator: ", "))")
+
+        apply(changed, previous: previous, result: &result)
+        return result
+    }
+
+    /// The issues `update(_:)` would reject `settings` for: those the current settings
+    /// don't already have.
+    func issues(in settings: AppSettings) -> [SettingsValidationIssue] {
+        let models = downloadedModels()
+        let existing = Set(SettingsValidator.validate(store.settings, downloadedModels: models).map(\.id))
+        return SettingsValidator.validate(settings, downloadedModels: models)
+            .filter { !existing.contains($0.id) }
+    }
+
+    /// Runs side effects for `changed`. Shared with the external-edit reload path,
+    /// where `previous` is the snapshot before `SettingsStore.reload()`.
+    func apply(_ changed: Set<AppSettings.Key>, previous: AppSettings, result: inout SettingsUpdateResult) {
+        guard let applier else { return }
+        let current = store.settings
+
+        let hotkeyChanges = changed.intersection(Self.hotkeyKeys)
+        let hotkeyOutcome: HotkeyApplyOutcome = hotkeyChanges.isEmpty ? .registered : applier.applyHotkeyChange()
+        if hotkeyOutcome == .pending {
+            result.appliedKeys.subtract(hotkeyChanges)
+            result.pendingKeys.formUnion(hotkeyChanges)
+            Logger.shared.info("SettingsUpdater: Shortcut saved; applies once the hotkey listener starts")
+        }
+        if hotkeyOutcome == .failed {
+            store.update {
+                $0.shortcutKeyCode = previous.shortcutKeyCode
+                $0.shortcutModifiers = previous.shortcutModifiers
+                $0.hotkeyBackend = previous.hotkeyBackend
+            }
+            _ = applier.applyHotkeyChange()
+            result.appliedKeys.subtract(hotkeyChanges)
+            result.revertedKeys.formUnion(hotkeyChanges)
+            result.issues.append(SettingsValidationIssue(
+                field: AppSettings.Key.hotkeyBackend.rawValue,
+                message: "Could not register the shortcut — previous shortcut restored."
+            ))
+            Logger.shared.error("SettingsUpdater: Hotkey registration failed — reverted shortcut settings")
+        }
+
+        if changed.contains(.selectedModel) {
+            applier.applyModelChange(current.selectedModel)
+        }
+
+        if !changed.isDisjoint(with: Self.postProcessingKeys) {
+            applier.applyPostProcessingChange()
+        }
+    }
+}
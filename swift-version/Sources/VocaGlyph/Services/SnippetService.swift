@@ -0,0 +1,72 @@
+import Foundation
+import SwiftData
+
+// MARK: - SnippetService
+
+/// Pins history entries as named `Snippet`s and looks them up for pasting.
+final class SnippetService {
+
+    private let container: ModelContainer
+
+    init(container: ModelContainer) {
+        self.container = container
+    }
+
+    /// Every snippet, sorted by name.
+    @MainActor
+    func all() -> [Snippet] {
+        let descriptor = FetchDescriptor<Snippet>(sortBy: [SortDescriptor(\.name)])
+        return (try? container.mainContext.fetch(descriptor)) ?? []
+    }
+
+    @MainActor
+    func snippet(_ id: UUID) -> Snippet? {
+        let descriptor = FetchDescriptor<Snippet>(predicate: #Predicate { $0.id == id })
+        return try? container.mainContext.fetch(descriptor).first
+    }
+
+    /// The snippet pinned from history entry `itemID`, if any.
+    @MainActor
+    func snippet(pinnedFrom itemID: UUID) -> Snippet? {
+        let descriptor = FetchDescriptor<Snippet>(predicate: #Predicate { $0.sourceItemID == itemID })
+        return try? container.mainContext.fetch(descriptor).first
+    }
+
+    /// Saves `item`'s text as a snippet called `name`. Pinning an entry twice renames the
+    /// existing snippet.
+    @MainActor @discardableResult
+    func pin(_ item: TranscriptionItem, name: String) -> Snippet {
+        let context = container.mainContext
+        let snippet: Snippet
+        if let existing = self.snippet(pinnedFrom: item.id) {
+            existing.name = name
+            snippet = existing
+        } else {
+            snippet = Snippet(name: name, text: item.text, sourceItemID: item.id)
+            context.insert(snippet)
+        }
+        save(context)
+        Logger.shared.info("SnippetService: Pinned history entry \(item.id) as '\(name)'")
+        return snippet
+    }
+
+    /// Returns `false` if no snippet has `id`.
+    @MainActor @discardableResult
+    func delete(_ id: UUID) -> Bool {
+        guard let snippet = snippet(id) else { return false }
+        let context = container.mainContext
+        context.delete(snippet)
+        save(context)
+        Logger.shared.info("SnippetService: Deleted snippet \(id)")
+        return true
+    }
+
+    @MainActor
+    private func save(_ context: ModelContext) {
+        do {
+            try context.save()
+        } catch {
+            Logger.shared.error("SnippetService: Failed to save — \(error.localizedDescription)")
+        }
+    }
+}
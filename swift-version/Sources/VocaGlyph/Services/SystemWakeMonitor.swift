@@ -0,0 +1,82 @@
+import AppKit
+
+// MARK: - SystemWakeMonitor
+
+/// Reports when the Mac comes back from sleep, the screens wake, the screen is unlocked
+/// or the user switches back to this login session — the moments after which the event
+/// tap, the audio device and even permissions are often no longer what they were.
+///
+/// Those notifications arrive in bursts (wake, then screens, then unlock), so they are
+/// coalesced: `onWake` runs once, `settleDelay` after the last one, giving Core Audio
+/// and the window server time to settle.
+final class SystemWakeMonitor {
+
+    static let workspaceNotifications: [Notification.Name] = [
+        NSWorkspace.didWakeNotification,
+        NSWorkspace.screensDidWakeNotification,
+        NSWorkspace.sessionDidBecomeActiveNotification,
+    ]
+    /// Posted by loginwindow when the lock screen is dismissed.
+    static let screenUnlockedNotification = Notification.Name("com.apple.screenIsUnlocked")
+
+    private let workspaceCenter: NotificationCenter
+    private let distributedCenter: NotificationCenter
+    private let settleDelay: TimeInterval
+    private var observers: [(center: NotificationCenter, token: NSObjectProtocol)] = []
+    private var pendingReasons: [String] = []
+    private var pendingWorkItem: DispatchWorkItem?
+
+    /// Called on the main thread with the names of the notifications seen, for the log.
+    var onWake: ((String) -> Void)?
+
+    init(workspaceCenter: NotificationCenter = NSWorkspace.shared.notificationCenter,
+         distributedCenter: NotificationCenter = DistributedNotificationCenter.default(),
+         settleDelay: TimeInterval = 2) {
+        self.workspaceCenter = workspaceCenter
+        self.distributedCenter = distributedCenter
+        self.settleDelay = settleDelay
+    }
+
+    deinit {
+        stop()
+    }
+
+    func start() {
+        guard observers.isEmpty else { return }
+        for name in Self.workspaceNotifications {
+            observe(name, on: workspaceCenter)
+        }
+        observe(Self.screenUnlockedNotification, on: distributedCenter)
+        Logger.shared.info("SystemWakeMonitor: Watching for wake and unlock")
+    }
+
+    func stop() {
+        observers.forEach { $0.center.removeObserver($0.token) }
+        observers.removeAll()
+        pendingWorkItem?.cancel()
+        pendingWorkItem = nil
+        pendingReasons.removeAll()
+    }
+
+    private func observe(_ name: Notification.Name, on center: NotificationCenter) {
+        let token = center.addObserver(forName: name, object: nil, queue: .main) { [weak self] notification in
+            self?.enqueue(notification.name)
+        }
+        observers.append((center, token))
+    }
+
+    private func enqueue(_ name: Notification.Name) {
+        let reason = name.rawValue
+        if !pendingReasons.contains(reason) { pendingReasons.append(reason) }
+        pendingWorkItem?.cancel()
+        let work = DispatchWorkItem { [weak self] in
+            guard let self else { return }
+            let reasons = self.pendingReasons.joined(separator: ", ")
+            self.pendingReasons.removeAll()
+            Logger.shared.info("SystemWakeMonitor: Woke (\(reasons))")
+            self.onWake?(reasons)
+        }
+        pendingWorkItem = work
+        DispatchQueue.main.asyncAfter(deadline: .now() + settleDelay, execute: work)
+    }
+}
@@ -0,0 +1,197 @@
+import Foundation
+import SwiftData
+
+// MARK: - TemplateError
+
+enum TemplateError: LocalizedError, Equatable {
+    case emptyName
+    /// The body has no `{slot}` to dictate into.
+    case noSlots
+
+    var errorDescription: String? {
+        switch self {
+        case .emptyName:
+            return "Give the template a name."
+        case .noSlots:
+            return "Add at least one {slot} to the template text."
+        }
+    }
+}
+
+// MARK: - TemplateFill
+
+/// One run through a `DictationTemplate`: each dictation fills the next empty slot, and
+/// once all are filled `rendered` is the text to paste.
+///
+/// A slot used more than once ("{name} … {name}") is dictated once and appears at every
+/// use. Values are trimmed and lose a single trailing period, since the template's own
+/// punctuation follows them.
+struct TemplateFill: Equatable {
+    let templateID: UUID
+    let name: String
+    let body: String
+    let slots: [String]
+    private(set) var values: [String: String] = [:]
+
+    init(templateID: UUID, name: String, body: String) {
+        self.templateID = templateID
+        self.name = name
+        self.body = body
+        self.slots = Self.slots(in: body)
+    }
+
+    /// The slot the next dictation fills, or `nil` once the template is complete.
+    var nextSlot: String? { slots.first { values[$0] == nil } }
+
+    var isComplete: Bool { nextSlot == nil }
+
+    /// Fills the next slot with `text`. Ignored once complete.
+    mutating func fill(_ text: String) {
+        guard let slot = nextSlot else { return }
+        var value = text.trimmingCharacters(in: .whitespacesAndNewlines)
+        if value.hasSuffix(".") && !value.hasSuffix("...") { value.removeLast() }
+        values[slot] = value
+    }
+
+    /// `body` with every filled slot replaced; unfilled slots are left as written.
+    var rendered: String {
+        guard let regex = Self.slotRegex else { return body }
+        let ns = body as NSString
+        var result = body
+        for match in regex.matches(in: body, range: NSRange(location: 0, length: ns.length)).reversed() {
+            let slot = ns.substring(with: match.range(at: 1)).trimmingCharacters(in: .whitespaces)
+            guard let value = values[slot], let range = Range(match.range, in: result) else { continue }
+            result.replaceSubrange(range, with: value)
+        }
+        return result
+    }
+
+    /// Slot names in `body` in order of first use: "{a} {b} {a}" → ["a", "b"].
+    static func slots(in body: String) -> [String] {
+        guard let regex = slotRegex else { return [] }
+        let ns = body as NSString
+        var slots: [String] = []
+        for match in regex.matches(in: body, range: NSRange(location: 0, length: ns.length)) {
+            let slot = ns.substring(with: match.range(at: 1)).trimmingCharacters(in: .whitespaces)
+            if !slot.isEmpty && !slots.contains(slot) { slots.append(slot) }
+        }
+        return slots
+    }
+
+    private static let slotRegex = try? NSRegularExpression(pattern: #"\{([^{}\n]+)\}"#)
+}
+
+// MARK: - TemplateService
+
+/// Stores `DictationTemplate`s and tracks the one being filled, if any.
+///
+/// Only one template is filled at a time; starting another replaces it. While a fill is
+/// active, `AppDelegate` routes each dictation into it instead of pasting.
+final class TemplateService {
+
+    private let container: ModelContainer
+
+    /// The template being filled. Read and changed on the main thread.
+    private(set) var activeFill: TemplateFill?
+
+    init(container: ModelContainer) {
+        self.container = container
+    }
+
+    // MARK: - CRUD
+
+    /// Every template, sorted by name.
+    @MainActor
+    func all() -> [DictationTemplate] {
+        let descriptor = FetchDescriptor<DictationTemplate>(sortBy: [SortDescriptor(\.name)])
+        return (try? container.mainContext.fetch(descriptor)) ?? []
+    }
+
+    @MainActor
+    func template(_ id: UUID) -> DictationTemplate? {
+        let descriptor = FetchDescriptor<DictationTemplate>(predicate: #Predicate { $0.id == id })
+        return try? container.mainContext.fetch(descriptor).first
+    }
+
+    @MainActor @discardableResult
+    func create(name: String, body: String) throws -> DictationTemplate {
+        let (name, body) = try validated(name: name, body: body)
+        let template = DictationTemplate(name: name, body: body)
+        let context = container.mainContext
+        context.insert(template)
+        save(context)
+        Logger.shared.info("TemplateService: Created template '\(name)' with \(template.slots.count) slots")
+        return template
+    }
+
+    /// Returns `false` if no template has `id`. A fill already in progress keeps the
+    /// body it started with.
+    @MainActor @discardableResult
+    func update(_ id: UUID, name: String, body: String) throws -> Bool {
+        guard let template = template(id) else { return false }
+        let (name, body) = try validated(name: name, body: body)
+        template.name = name
+        template.body = body
+        save(container.mainContext)
+        Logger.shared.info("TemplateService: Updated template \(id)")
+        return true
+    }
+
+    /// Returns `false` if no template has `id`. Deleting the template being filled
+    /// cancels the fill.
+    @MainActor @discardableResult
+    func delete(_ id: UUID) -> Bool {
+        guard let template = template(id) else { return false }
+        let context = container.mainContext
+        context.delete(template)
+        save(context)
+        if activeFill?.templateID == id { activeFill = nil }
+        Logger.shared.info("TemplateService: Deleted template \(id)")
+        return true
+    }
+
+    // MARK: - Filling
+
+    /// Starts filling template `id`, replacing any fill in progress. Returns `nil` if no
+    /// template has `id`.
+    @MainActor @discardableResult
+    func start(_ id: UUID) -> TemplateFill? {
+        guard let template = template(id) else { return nil }
+        activeFill = TemplateFill(templateID: template.id, name: template.name, body: template.body)
+        Logger.shared.info("TemplateService: Started '\(template.name)' — next slot '\(activeFill?.nextSlot ?? "")'")
+        return activeFill
+    }
+
+    /// Fills the next slot of the active template with `text`. Returns the updated fill,
+    /// or `nil` when none is active. A completed fill is returned once and then cleared.
+    func fill(with text: String) -> TemplateFill? {
+        guard var fill = activeFill else { return nil }
+        fill.fill(text)
+        activeFill = fill.isComplete ? nil : fill
+        return fill
+    }
+
+    func cancel() {
+        guard let fill = activeFill else { return }
+        activeFill = nil
+        Logger.shared.info("TemplateService: Cancelled '\(fill.name)'")
+    }
+
+    // MARK: - Helpers
+
+    private func validated(name: String, body: String) throws -> (String, String) {
+        let name = name.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !name.isEmpty else { throw TemplateError.emptyName }
+        guard !TemplateFill.slots(in: body).isEmpty else { throw TemplateError.noSlots }
+        return (name, body)
+    }
+
+    @MainActor
+    private func save(_ context: ModelContext) {
+        do {
+            try context.save()
+        } catch {
+            Logger.shared.error("TemplateService: Failed to save — \(error.localizedDescription)")
+        }
+    }
+}
@@ -0,0 +1,334 @@
+import Foundation
+
+// MARK: - TextProcessingStage
+
+/// The built-in stages of `TextProcessingPipeline`, in the order they run.
+enum TextProcessingStage: String, CaseIterable {
+    case trim
+    case fillerRemoval
+    case replacements
+    case termCorrection
+    case numberNormalization
+    case regexRules
+    case punctuationCommands
+    /// After spoken punctuation, so a dictation punctuated by voice is left alone.
+    case punctuationRestoration
+    case capitalization
+    case llmCleanup
+    case translation
+    /// Third-party processors from `PluginHost`, after the built-in rewrites.
+    case plugins
+    /// After the LLM, so its rewrite can't undo the list or comment layout.
+    case outputStyle
+    /// Last, so profanity an LLM reintroduces is filtered too.
+    case profanity
+
+    /// Whether the stage runs under `settings`. `.trim` always runs — every later
+    /// stage assumes input without surrounding whitespace.
+    func isEnabled(in settings: AppSettings) -> Bool {
+        switch self {
+        case .trim:                return true
+        case .fillerRemoval:       return settings.removeFillerWords
+        case .replacements:        return settings.wordReplacementsEnabled
+        case .termCorrection:      return settings.termCorrectionEnabled && !settings.customTerms.isEmpty
+        case .numberNormalization: return settings.numberNormalizationEnabled
+        case .regexRules:          return settings.regexRulesEnabled
+        case .punctuationCommands: return settings.spokenPunctuationEnabled
+        case .punctuationRestoration: return settings.punctuationRestorationEnabled
+        case .capitalization:      return settings.autoPunctuation
+        case .llmCleanup:          return settings.enablePostProcessing
+        case .translation:         return (TranslationProvider(rawValue: settings.translationProvider) ?? .off) != .off
+                                       && !settings.translationTargetLanguage.isEmpty
+        case .plugins:             return settings.pluginsEnabled
+        case .outputStyle:         return (OutputStyle(rawValue: settings.outputStyle) ?? .plain) != .plain
+        case .profanity:           return (ProfanityFilter.Mode(rawValue: settings.profanityFilter) ?? .off) != .off
+        }
+    }
+}
+
+// MARK: - TextProcessingPipeline
+
+/// Ordered list of `TextProcessor`s applied between the transcription engine and
+/// `OutputService`.
+///
+/// A stage that throws is logged and skipped: the pipeline carries on with that
+/// stage's input, so a failing or timed-out LLM never costs the user their dictation.
+struct TextProcessingPipeline {
+
+    let stages: [(stage: TextProcessingStage, processor: any TextProcessor)]
+
+    /// Builds the standard pipeline from the user's settings. `cleanup` is `nil` when
+    /// no post-processing engine is ready; the LLM stage is then left out. The same
+    /// goes for `translator` and the translation stage, and `restorer` and punctuation
+    /// restoration. `overrides` force stages on or off regardless of `settings` — see
+    /// `AppProcessingRule`.
+    static func standard(settings: AppSettings,
+                         replacements: [(word: String, replacement: String)],
+                         regexRules: [RegexRuleProcessor.Rule] = [],
+                         cleanup: LLMCleanupProcessor?,
+                         translator: (any TranslationEngine)? = nil,
+                         restorer: (any PostProcessingEngine)? = nil,
+                         plugins: PluginTextProcessor? = nil,
+                         overrides: [TextProcessingStage: Bool] = [:]) -> TextProcessingPipeline {
+        let stages = TextProcessingStage.allCases
+            .filter { $0 == .trim || (overrides[$0] ?? $0.isEnabled(in: settings)) }
+            .compactMap { stage -> (stage: TextProcessingStage, processor: any TextProcessor)? in
+                switch stage {
+                case .trim:
+                    return (stage, TrimProcessor())
+                case .fillerRemoval:
+                    return (stage, FillerRemovalProcessor())
+                case .replacements:
+                    return (stage, WordReplacementProcessor(replacements: replacements))
+                case .termCorrection:
+                    return (stage, TermCorrectionProcessor(terms: settings.customTerms))
+                case .numberNormalization:
+                    let language = WhisperService.languageCode(for: settings.dictationLanguage)
+                    return (stage, NumberNormalizationProcessor(language: .forCode(language)))
+                case .regexRules:
+                    return (stage, RegexRuleProcessor(rules: regexRules))
+                case .punctuationCommands:
+                    return (stage, PunctuationCommandProcessor())
+                case .punctuationRestoration:
+                    guard let restorer else { return nil }
+                    return (stage, PunctuationRestorationProcessor(engine: restorer))
+                case .capitalization:
+                    return (stage, CapitalizationProcessor())
+                case .llmCleanup:
+                    guard let cleanup else { return nil }
+                    return (stage, cleanup)
+                case .translation:
+                    guard let translator else { return nil }
+                    return (stage, TranslationProcessor(engine: translator,
+                                                        source: WhisperService.languageCode(for: settings.dictationLanguage),
+                                                        target: settings.translationTargetLanguage))
+                case .plugins:
+                    guard let plugins else { return nil }
+                    return (stage, plugins)
+                case .outputStyle:
+                    return (stage, OutputStyleProcessor(style: OutputStyle(rawValue: settings.outputStyle) ?? .plain))
+                case .profanity:
+                    return (stage, ProfanityProcessor(mode: ProfanityFilter.Mode(rawValue: settings.profanityFilter) ?? .off,
+                                                      customWords: settings.profanityCustomWords))
+                }
+            }
+        return TextProcessingPipeline(stages: stages)
+    }
+
+    func run(_ text: String) async -> String {
+        var current = text
+        for (stage, processor) in stages {
+            do {
+                current = try await processor.process(current)
+                Logger.shared.debug("TextProcessingPipeline: [\(stage.rawValue)] '\(Logger.transcript(current))'")
+            } catch {
+                Logger.shared.error("TextProcessingPipeline: [\(stage.rawValue)] failed — \(error.localizedDescription). Keeping its input.")
+            }
+        }
+        return current
+    }
+}
+
+// MARK: - Built-in Processors
+
+/// Trims surrounding whitespace and newlines.
+struct TrimProcessor: TextProcessor {
+    func process(_ text: String) async throws -> String {
+        text.trimmingCharacters(in: .whitespacesAndNewlines)
+    }
+}
+
+/// Strips hesitations, stutters and other disfluencies via `FillerWordRemover`.
+struct FillerRemovalProcessor: TextProcessor {
+    func process(_ text: String) async throws -> String {
+        FillerWordRemover.remove(from: text)
+    }
+}
+
+/// Applies the user's enabled word replacements via `WordReplacementApplicator`.
+struct WordReplacementProcessor: TextProcessor {
+    let replacements: [(word: String, replacement: String)]
+
+    func process(_ text: String) async throws -> String {
+        WordReplacementApplicator.apply(to: text, replacements: replacements)
+    }
+}
+
+/// Snaps near-miss spellings to the user's custom terms via `TermCorrector`.
+struct TermCorrectionProcessor: TextProcessor {
+    let terms: [String]
+
+    func process(_ text: String) async throws -> String {
+        TermCorrector.correct(text, terms: terms)
+    }
+}
+
+/// Writes spoken numbers, times and dates as digits via `NumberNormalizer`. `language`
+/// is `nil` for dictation languages without rules; the text then passes through.
+struct NumberNormalizationProcessor: TextProcessor {
+    let language: NumberNormalizer.Language?
+
+    func process(_ text: String) async throws -> String {
+        guard let language else { return text }
+        return NumberNormalizer.normalize(text, language: language)
+    }
+}
+
+/// Applies the user's regex rules (see `RegexRulesService`) in order.
+struct RegexRuleProcessor: TextProcessor, @unchecked Sendable {
+
+    struct Rule {
+        let regex: NSRegularExpression
+        /// `NSRegularExpression` template — `$1` inserts the first capture group.
+        let template: String
+    }
+
+    // NSRegularExpression is immutable and safe to share across threads.
+    let rules: [Rule]
+
+    func process(_ text: String) async throws -> String {
+        rules.reduce(text) { current, rule in
+            let range = NSRange(current.startIndex..., in: current)
+            return rule.regex.stringByReplacingMatches(in: current, range: range, withTemplate: rule.template)
+        }
+    }
+}
+
+/// Turns spoken punctuation ("comma", "question mark", "new line") into symbols.
+///
+/// Engines often punctuate the command word itself ("hello comma, world"), so a
+/// trailing `.` or `,` after a command is dropped with it.
+struct PunctuationCommandProcessor: TextProcessor {
+
+    /// Spoken phrase → replacement. Longer phrases come first so "new paragraph"
+    /// is not split by a shorter command.
+    static let commands: [(phrase: String, symbol: String)] = [
+        ("new paragraph", "\n\n"),
+        ("new line", "\n"),
+        ("question mark", "?"),
+        ("exclamation mark", "!"),
+        ("exclamation point", "!"),
+        ("full stop", "."),
+        ("period", "."),
+        ("comma", ","),
+        ("semicolon", ";"),
+        ("colon", ":"),
+    ]
+
+    func process(_ text: String) async throws -> String {
+        var result = text
+        for command in Self.commands {
+            let isLineBreak = command.symbol.hasPrefix("\n")
+            // Punctuation attaches to the previous word; line breaks also eat the
+            // space that follows so the next line does not start with one.
+            let pattern = "[ \\t]*\\b\(NSRegularExpression.escapedPattern(for: command.phrase))\\b[.,]?"
+                + (isLineBreak ? "[ \\t]*" : "")
+            guard let regex = try? NSRegularExpression(pattern: pattern, options: .caseInsensitive) else { continue }
+            let range = NSRange(result.startIndex..., in: result)
+            result = regex.stringByReplacingMatches(
+                in: result, range: range,
+                withTemplate: NSRegularExpression.escapedTemplate(for: command.symbol)
+            )
+        }
+        return result
+    }
+}
+
+/// Capitalizes the first letter and appends a period when the text has no terminal
+/// punctuation.
+///
+/// Whisper and Apple engines produce punctuated output natively, so this is a no-op
+/// for them; it fixes Parakeet, which returns raw unpunctuated text.
+struct CapitalizationProcessor: TextProcessor {
+
+    func process(_ text: String) async throws -> String {
+        Self.apply(text)
+    }
+
+    static func apply(_ text: String) -> String {
+        var result = text
+        if let first = result.unicodeScalars.first,
+           CharacterSet.lowercaseLetters.contains(first) {
+            result = result.prefix(1).uppercased() + result.dropFirst()
+        }
+        // Append period only when no terminal punctuation or line break is present
+        let terminators: Set<Character> = [".", "?", "!", ":", ";", ","]
+        if let last = result.last, !terminators.contains(last), !last.isNewline {
+            result += "."
+        }
+        return result
+    }
+}
+
+/// Asks a local model to punctuate a transcript that came back without punctuation.
+/// Output that changes any word is discarded — see `PunctuationRestorer`.
+struct PunctuationRestorationProcessor: TextProcessor {
+    let engine: any PostProcessingEngine
+    var timeout: TimeInterval = 20
+
+    func process(_ text: String) async throws -> String {
+        guard PunctuationRestorer.needsRestoration(text) else { return text }
+        let output = try await LLMCleanupProcessor(engine: engine, prompt: PunctuationRestorer.prompt, timeout: timeout)
+            .process(text)
+        guard let restored = PunctuationRestorer.validated(output, against: text) else {
+            Logger.shared.info("PunctuationRestorationProcessor: Model changed the words — keeping the transcript as is")
+            return text
+        }
+        return restored
+    }
+}
+
+/// Sends the text through the selected post-processing engine with the active
+/// template's prompt, giving up after `timeout`.
+struct LLMCleanupProcessor: TextProcessor {
+    let engine: any PostProcessingEngine
+    let prompt: String
+    var timeout: TimeInterval = 30
+
+    func process(_ text: String) async throws -> String {
+        guard !text.isEmpty else { return text }
+        return try await withThrowingTaskGroup(of: String.self) { group in
+            group.addTask { try await engine.refine(text: text, prompt: prompt) }
+            group.addTask {
+                try await Task.sleep(nanoseconds: UInt64(timeout * 1_000_000_000))
+                throw NSError(domain: "TimeoutError", code: 408,
+                              userInfo: [NSLocalizedDescriptionKey: "Post-processing timed out after \(Int(timeout))s"])
+            }
+            guard let result = try await group.next() else { throw CancellationError() }
+            group.cancelAll()
+            return result
+        }
+    }
+}
+
+/// Masks or drops profanity via `ProfanityFilter`.
+struct ProfanityProcessor: TextProcessor {
+    let mode: ProfanityFilter.Mode
+    let customWords: [String]
+
+    func process(_ text: String) async throws -> String {
+        ProfanityFilter.filter(text, mode: mode, customWords: customWords)
+    }
+}
+
+/// Translates the text into `target`. Skipped when the dictation is already in
+/// that language.
+struct TranslationProcessor: TextProcessor {
+    let engine: any TranslationEngine
+    let source: String?
+    let target: String
+
+    func process(_ text: String) async throws -> String {
+        guard !text.isEmpty, source != target else { return text }
+        return try await engine.translate(text, from: source, to: target)
+    }
+}
+
+/// Shapes the text into the selected `OutputStyle`.
+struct OutputStyleProcessor: TextProcessor {
+    let style: OutputStyle
+
+    func process(_ text: String) async throws -> String {
+        style.apply(text)
+    }
+}
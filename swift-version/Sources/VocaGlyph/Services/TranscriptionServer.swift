@@ -0,0 +1,367 @@
+import AVFoundation
+import Darwin
+import Foundation
+
+// MARK: - Transcription Protocol
+
+/// JSON-RPC 2.0, one request per connection, terminated by a newline:
+///
+///     {"jsonrpc": "2.0", "id": 1, "method": "transcribe",
+///      "params": {"token": "…", "audio": "<base64>", "format": "wav"}}
+///
+/// answered with `{"jsonrpc": "2.0", "id": 1, "result": {"text": "…", "durationSeconds": 2.5}}`
+/// or `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32001, "message": "…"}}`.
+///
+/// `format` is `wav` (any WAV `AVAudioFile` reads) or `pcm` (raw 16-bit little-endian
+/// mono at `sampleRate`, 16000 by default).
+enum TranscriptionRPC {
+
+    /// A request id, which JSON-RPC allows to be a number or a string.
+    enum ID: Codable, Equatable {
+        case number(Int)
+        case string(String)
+
+        init(from decoder: Decoder) throws {
+            let container = try decoder.singleValueContainer()
+            if let number = try? container.decode(Int.self) {
+                self = .number(number)
+            } else {
+                self = .string(try container.decode(String.self))
+            }
+        }
+
+        func encode(to encoder: Encoder) throws {
+            var container = encoder.singleValueContainer()
+            switch self {
+            case .number(let number): try container.encode(number)
+            case .string(let string): try container.encode(string)
+            }
+        }
+    }
+
+    struct Params: Decodable {
+        let token: String?
+        /// Base64 audio bytes.
+        let audio: String?
+        let format: String?
+        let sampleRate: Double?
+    }
+
+    struct Request: Decodable {
+        let id: ID?
+        let method: String
+        let params: Params?
+    }
+
+    struct Result: Codable, Equatable {
+        let text: String
+        let durationSeconds: Double
+    }
+
+    struct Failure: Codable, Equatable {
+        let code: Int
+        let message: String
+
+        static let parseError = -32700
+        static let invalidRequest = -32600
+        static let methodNotFound = -32601
+        static let invalidParams = -32602
+        static let unauthorized = -32001
+        static let transcriptionFailed = -32002
+    }
+
+    struct Response: Codable, Equatable {
+        var jsonrpc = "2.0"
+        var id: ID?
+        var result: Result?
+        var error: Failure?
+
+        static func failure(_ id: ID?, _ code: Int, _ message: String) -> Response {
+            Response(id: id, error: Failure(code: code, message: message))
+        }
+    }
+}
+
+enum TranscriptionServerError: LocalizedError {
+    case notReady
+    case invalidAudio(String)
+
+    var errorDescription: String? {
+        switch self {
+        case .notReady:                return "No transcription model is loaded."
+        case .invalidAudio(let reason): return "Invalid audio: \(reason)"
+        }
+    }
+}
+
+// MARK: - TranscriptionServer
+
+/// Optional local transcription service on a Unix domain socket, so other apps on this
+/// Mac can reuse the model VocaGlyph already has loaded instead of shipping their own.
+/// Audio goes straight to the active engine: no text processing, history or pasting.
+///
+/// The socket sits next to the control API's (see `AppDirs.sockets`) and takes the same
+/// token:
+///
+///     DIR=~/Library/Application\ Support/VocaGlyph
+///     AUDIO=$(base64 -i clip.wav)
+///     echo "{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"transcribe\",\"params\":{\"token\":\"$(cat $DIR/control-token)\",\"audio\":\"$AUDIO\"}}" \
+///         | nc -U $DIR/transcribe.sock
+///
+/// Requests are transcribed one at a time.
+final class TranscriptionServer {
+
+    static let socketName = "transcribe.sock"
+    /// About 25 minutes of 16 kHz 16-bit audio once base64-encoded.
+    static let maxRequestBytes = 64 * 1024 * 1024
+    static let defaultSampleRate: Double = 16_000
+    /// How long a client may take to send its request before it is dropped.
+    static let requestTimeout: TimeInterval = 60
+
+    let socketURL: URL
+    let tokenURL: URL
+    private let transcribe: (AVAudioPCMBuffer) async throws -> String
+    private let queue = DispatchQueue(label: "com.vocaglyph.transcription-server")
+    /// The request accepted last; the next one waits for it. Only touched on `queue`.
+    private var lastRequest: Task<Void, Never>?
+    private var listener: Int32 = -1
+    private var source: DispatchSourceRead?
+
+    /// `directory` defaults to `AppDirs.current.root`, and `AppDirs.current.sockets` for
+    /// the socket.
+    init(directory: URL? = nil, transcribe: @escaping (AVAudioPCMBuffer) async throws -> String) {
+        let root = directory ?? AppDirs.current.root
+        socketURL = (directory ?? AppDirs.current.sockets).appendingPathComponent(Self.socketName)
+        tokenURL = root.appendingPathComponent(ControlSocketService.tokenName)
+        self.transcribe = transcribe
+    }
+
+    deinit {
+        stop()
+    }
+
+    var isRunning: Bool { source != nil }
+
+    func start() throws {
+        guard !isRunning else { return }
+        try FileManager.default.createDirectory(at: socketURL.deletingLastPathComponent(), withIntermediateDirectories: true)
+        _ = try ControlSocketService.loadOrCreateToken(at: tokenURL)
+
+        let path = socketURL.path
+        var address = sockaddr_un()
+        guard path.utf8.count < MemoryLayout.size(ofValue: address.sun_path) else {
+            throw ControlSocketError.pathTooLong(path)
+        }
+        address.sun_family = sa_family_t(AF_UNIX)
+        withUnsafeMutableBytes(of: &address.sun_path) { buffer in
+            buffer.copyBytes(from: path.utf8)
+        }
+
+        let fd = socket(AF_UNIX, SOCK_STREAM, 0)
+        guard fd >= 0 else { throw ControlSocketError.failed("socket") }
+        unlink(path) // A stale socket from a previous run.
+        let bound = withUnsafePointer(to: &address) {
+            $0.withMemoryRebound(to: sockaddr.self, capacity: 1) {
+                bind(fd, $0, socklen_t(MemoryLayout<sockaddr_un>.size))
+            }
+        }
+        var failure: ControlSocketError?
+        if bound != 0 {
+            failure = .failed("bind")
+        } else if chmod(path, 0o600) != 0 {
+            failure = .failed("chmod")
+        } else if listen(fd, 8) != 0 {
+            failure = .failed("listen")
+        }
+        if let failure {
+            close(fd)
+            throw failure
+        }
+
+        listener = fd
+        let source = DispatchSource.makeReadSource(fileDescriptor: fd, queue: queue)
+        source.setEventHandler { [weak self] in self?.acceptClient() }
+        source.resume()
+        self.source = source
+        Logger.shared.info("TranscriptionServer: Listening on \(path)")
+    }
+
+    func stop() {
+        guard let source else { return }
+        source.cancel()
+        self.source = nil
+        close(listener)
+        listener = -1
+        unlink(socketURL.path)
+        Logger.shared.info("TranscriptionServer: Stopped")
+    }
+
+    // MARK: - Connections
+
+    private func acceptClient() {
+        let client = accept(listener, nil, nil)
+        guard client >= 0 else { return }
+
+        // Read off `queue`, so a slow or silent client doesn't block other connections.
+        let tokenURL = tokenURL
+        let transcribe = transcribe
+        let queue = queue
+        DispatchQueue.global(qos: .userInitiated).async { [weak self] in
+            let request = ControlSocketService.readRequest(from: client, maxBytes: Self.maxRequestBytes,
+                                                           timeout: Self.requestTimeout) {
+                $0.contains(UInt8(ascii: "\n"))
+            }
+            let token = (try? ControlSocketService.loadOrCreateToken(at: tokenURL)) ?? ""
+            queue.async {
+                guard let self else {
+                    close(client)
+                    return
+                }
+                let previous = self.lastRequest
+                self.lastRequest = Task {
+                    await previous?.value
+                    let response = await Self.respond(to: request, token: token, transcribe: transcribe)
+                    queue.async {
+                        var data = (try? JSONEncoder.transcription.encode(response)) ?? Data()
+                        data.append(UInt8(ascii: "\n"))
+                        data.withUnsafeBytes { _ = write(client, $0.baseAddress, $0.count) }
+                        close(client)
+                    }
+                }
+            }
+        }
+    }
+
+    // MARK: - Requests
+
+    /// Decodes, authenticates and runs one request.
+    static func respond(to data: Data, token: String,
+                        transcribe: (AVAudioPCMBuffer) async throws -> String) async -> TranscriptionRPC.Response {
+        typealias Failure = TranscriptionRPC.Failure
+        guard let request = try? JSONDecoder().decode(TranscriptionRPC.Request.self, from: data) else {
+            return .failure(nil, Failure.parseError, "Invalid request: expected a JSON-RPC object with \"method\".")
+        }
+        let id = request.id
+        guard !token.isEmpty, request.params?.token == token else {
+            Logger.shared.error("TranscriptionServer: Rejected request with a missing or wrong token")
+            return .failure(id, Failure.unauthorized, "Unauthorized.")
+        }
+        guard request.method == "transcribe" else {
+            return .failure(id, Failure.methodNotFound, "Unknown method '\(request.method)'. Known methods: transcribe.")
+        }
+        guard let encoded = request.params?.audio, let audio = Data(base64Encoded: encoded) else {
+            return .failure(id, Failure.invalidParams, "'transcribe' needs base64 \"audio\".")
+        }
+
+        let buffer: AVAudioPCMBuffer
+        do {
+            switch request.params?.format ?? "wav" {
+            case "wav":
+                buffer = try wavBuffer(audio)
+            case "pcm":
+                buffer = try pcmBuffer(audio, sampleRate: request.params?.sampleRate ?? defaultSampleRate)
+            case let format:
+                return .failure(id, Failure.invalidParams, "Unknown format '\(format)'. Known formats: wav, pcm.")
+            }
+        } catch {
+            return .failure(id, Failure.invalidParams, error.localizedDescription)
+        }
+
+        let duration = Double(buffer.frameLength) / buffer.format.sampleRate
+        Logger.shared.info("TranscriptionServer: Transcribing \(String(format: "%.1f", duration))s of audio")
+        do {
+            let text = try await transcribe(buffer)
+            return TranscriptionRPC.Response(id: id, result: TranscriptionRPC.Result(text: text, durationSeconds: duration))
+        } catch {
+            Logger.shared.error("TranscriptionServer: Transcription failed — \(error.localizedDescription)")
+            return .failure(id, Failure.transcriptionFailed, error.localizedDescription)
+        }
+    }
+
+    // MARK: - Audio
+
+    /// The 16 kHz mono float format every `TranscriptionEngine` expects.
+    static let engineFormat = AVAudioFormat(commonFormat: .pcmFormatFloat32, sampleRate: defaultSampleRate,
+                                            channels: 1, interleaved: false)!
+
+    /// Raw 16-bit little-endian mono samples.
+    static func pcmBuffer(_ data: Data, sampleRate: Double) throws -> AVAudioPCMBuffer {
+        guard (8_000...192_000).contains(sampleRate) else {
+            throw TranscriptionServerError.invalidAudio("sampleRate must be between 8000 and 192000")
+        }
+        let frames = data.count / 2
+        guard frames > 0,
+              let format = AVAudioFormat(commonFormat: .pcmFormatFloat32, sampleRate: sampleRate, channels: 1, interleaved: false),
+              let buffer = AVAudioPCMBuffer(pcmFormat: format, frameCapacity: AVAudioFrameCount(frames)),
+              let channel = buffer.floatChannelData?[0] else {
+            throw TranscriptionServerError.invalidAudio("no samples")
+        }
+        data.withUnsafeBytes { bytes in
+            for frame in 0..<frames {
+                let sample = Int16(littleEndian: bytes.loadUnaligned(fromByteOffset: frame * 2, as: Int16.self))
+                channel[frame] = Float(sample) / 32_768
+            }
+        }
+        buffer.frameLength = AVAudioFrameCount(frames)
+        return try converted(buffer)
+    }
+
+    /// A WAV file's samples. `AVAudioFile` only reads from disk, so the bytes go through
+    /// a temporary file.
+    static func wavBuffer(_ data: Data) throws -> AVAudioPCMBuffer {
+        let file = FileManager.default.temporaryDirectory
+            .appendingPathComponent("vocaglyph-transcribe-\(UUID().uuidString).wav")
+        defer { try? FileManager.default.removeItem(at: file) }
+        do {
+            try data.write(to: file)
+            let audioFile = try AVAudioFile(forReading: file)
+            guard audioFile.length > 0,
+                  let buffer = AVAudioPCMBuffer(pcmFormat: audioFile.processingFormat,
+                                                frameCapacity: AVAudioFrameCount(audioFile.length)) else {
+                throw TranscriptionServerError.invalidAudio("no samples")
+            }
+            try audioFile.read(into: buffer)
+            return try converted(buffer)
+        } catch let error as TranscriptionServerError {
+            throw error
+        } catch {
+            throw TranscriptionServerError.invalidAudio("not a readable WAV file")
+        }
+    }
+
+    /// `buffer` in `engineFormat`, mixed down and resampled as needed.
+    static func converted(_ buffer: AVAudioPCMBuffer) throws -> AVAudioPCMBuffer {
+        if buffer.format == engineFormat { return buffer }
+        guard let converter = AVAudioConverter(from: buffer.format, to: engineFormat) else {
+            throw TranscriptionServerError.invalidAudio("unsupported sample format")
+        }
+        converter.downmix = true
+        let capacity = AVAudioFrameCount((Double(buffer.frameLength) * engineFormat.sampleRate / buffer.format.sampleRate).rounded(.up)) + 1
+        guard let output = AVAudioPCMBuffer(pcmFormat: engineFormat, frameCapacity: capacity) else {
+            throw TranscriptionServerError.invalidAudio("too long")
+        }
+        var consumed = false
+        var conversionError: NSError?
+        let status = converter.convert(to: output, error: &conversionError) { _, outStatus in
+            if consumed {
+                outStatus.pointee = .endOfStream
+                return nil
+            }
+            consumed = true
+            outStatus.pointee = .haveData
+            return buffer
+        }
+        guard status != .error, output.frameLength > 0 else {
+            throw TranscriptionServerError.invalidAudio(conversionError?.localizedDescription ?? "conversion failed")
+        }
+        return output
+    }
+}
+
+private extension JSONEncoder {
+    static let transcription: JSONEncoder = {
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = .sortedKeys
+        return encoder
+    }()
+}
@@ -0,0 +1,85 @@
+import Foundation
+
+// MARK: - URLCommand
+
+/// Commands accepted as `voicetotext://<command>` (or `vocaglyph://<command>`), so
+/// Shortcuts, Raycast and `open` can drive dictation without sending keystrokes:
+///
+///     open voicetotext://toggle
+enum URLCommand: String, CaseIterable {
+    case start
+    case stop
+    case toggle
+    /// Pastes the most recent transcription into the frontmost app again.
+    case pasteLast = "paste-last"
+    case openSettings = "open-settings"
+
+    static let schemes: Set<String> = ["voicetotext", "vocaglyph"]
+
+    /// Accepts the command as host (`voicetotext://toggle`) or path (`voicetotext:///toggle`),
+    /// case-insensitively. Returns `nil` for other schemes and unknown commands.
+    init?(url: URL) {
+        guard let scheme = url.scheme?.lowercased(), Self.schemes.contains(scheme) else { return nil }
+        let name = url.host ?? url.pathComponents.first { $0 != "/" }
+        guard let name, let command = URLCommand(rawValue: name.lowercased()) else { return nil }
+        self = command
+    }
+
+    /// The matching local-channel command for the dictation controls.
+    var triggerCommand: ExternalTriggerCommand? {
+        switch self {
+        case .start:  return .start
+        case .stop:   return .stop
+        case .toggle: return .toggle
+        case .pasteLast, .openSettings: return nil
+        }
+    }
+}
+
+// MARK: - URLSchemeHandling
+
+/// The app-side actions a URL can trigger; `AppDelegate` implements this.
+protocol URLSchemeHandling: AnyObject {
+    func handleTrigger(_ command: ExternalTriggerCommand)
+    @MainActor func pasteLastTranscription() -> Bool
+    func openSettings()
+}
+
+// MARK: - URLSchemeHandler
+
+/// Routes opened URLs to `URLSchemeHandling`. Unknown URLs are logged and ignored.
+final class URLSchemeHandler {
+
+    private weak var target: URLSchemeHandling?
+
+    init(target: URLSchemeHandling) {
+        self.target = target
+    }
+
+    /// Returns `false` when the URL isn't a known command.
+    @MainActor @discardableResult
+    func handle(_ url: URL) -> Bool {
+        guard let command = URLCommand(url: url) else {
+            Logger.shared.error("URLSchemeHandler: Ignoring unknown URL '\(url.absoluteString)'")
+            return false
+        }
+        guard let target else { return false }
+        Logger.shared.info("URLSchemeHandler: Received '\(command.rawValue)'")
+
+        if let trigger = command.triggerCommand {
+            target.handleTrigger(trigger)
+            return true
+        }
+        switch command {
+        case .pasteLast:
+            if !target.pasteLastTranscription() {
+                Logger.shared.info("URLSchemeHandler: Nothing to paste")
+            }
+        case .openSettings:
+            target.openSettings()
+        case .start, .stop, .toggle:
+            break
+        }
+        return true
+    }
+}
@@ -0,0 +1,144 @@
+import AppKit
+import Combine
+import Sparkle
+
+// MARK: - UpdateStatus
+
+/// Where the current update cycle is, as reported by Sparkle's delegate callbacks.
+enum UpdateStatus: Equatable {
+    case idle
+    case checking
+    case upToDate
+    case available(version: String)
+    case downloading(version: String)
+    case installing(version: String)
+    case failed(String)
+
+    /// Title for the status-bar menu item; it doubles as the "update available" badge.
+    var menuTitle: String {
+        switch self {
+        case .checking:                   return "Checking for Updates…"
+        case .available(let version):     return "Update to \(version) Available…"
+        case .downloading(let version):   return "Downloading \(version)…"
+        case .installing(let version):    return "Installing \(version)…"
+        case .idle, .upToDate, .failed:   return "Check for Updates…"
+        }
+    }
+}
+
+// MARK: - UpdateService
+
+/// Owns the Sparkle updater: it checks the appcast (`SUFeedURL`), downloads the new
+/// bundle, checks its EdDSA signature against `SUPublicEDKey` and swaps it in once the
+/// user agrees in Sparkle's dialog.
+///
+/// Publishes `status` for the tray menu, and keeps Sparkle's background checks and the
+/// `automaticUpdateChecks` setting in sync both ways. Until the setting is stored it is
+/// seeded from Sparkle, so Sparkle's own permission prompt still runs and its answer is
+/// kept. Sparkle calls its delegate on the main thread.
+final class UpdateService: NSObject, ObservableObject {
+
+    @Published private(set) var canCheckForUpdates = false
+    @Published private(set) var status: UpdateStatus = .idle
+
+    private var controller: SPUStandardUpdaterController!
+    private var cancellable: AnyCancellable?
+    private var automaticChecksCancellable: AnyCancellable?
+    private var settingsSubscription: SettingsSubscription?
+
+    var updater: SPUUpdater { controller.updater }
+
+    override init() {
+        super.init()
+        controller = SPUStandardUpdaterController(
+            startingUpdater: true,
+            updaterDelegate: self,
+            userDriverDelegate: nil
+        )
+        cancellable = controller.updater.publisher(for: \.canCheckForUpdates)
+            .receive(on: DispatchQueue.main)
+            .sink { [weak self] in self?.canCheckForUpdates = $0 }
+
+        if UserDefaults.standard.object(forKey: AppSettings.Key.automaticUpdateChecks.rawValue) == nil {
+            let sparkleValue = controller.updater.automaticallyChecksForUpdates
+            SettingsStore.shared.update { $0.automaticUpdateChecks = sparkleValue }
+            Logger.shared.info("UpdateService: Seeded automatic update checks from Sparkle (\(sparkleValue))")
+        } else {
+            applyAutomaticChecks(SettingsStore.shared.settings.automaticUpdateChecks)
+        }
+        // Sparkle's permission prompt and its own settings write straight to the updater.
+        automaticChecksCancellable = controller.updater.publisher(for: \.automaticallyChecksForUpdates)
+            .dropFirst()
+            .receive(on: DispatchQueue.main)
+            .sink { enabled in
+                guard SettingsStore.shared.settings.automaticUpdateChecks != enabled else { return }
+                SettingsStore.shared.update { $0.automaticUpdateChecks = enabled }
+            }
+        settingsSubscription = SettingsStore.shared.subscribe { [weak self] old, new in
+            guard old.automaticUpdateChecks != new.automaticUpdateChecks else { return }
+            DispatchQueue.main.async { self?.applyAutomaticChecks(new.automaticUpdateChecks) }
+        }
+    }
+
+    /// User-initiated check; Sparkle shows its own window for the result.
+    func checkForUpdates(_ sender: Any?) {
+        status = .checking
+        controller.checkForUpdates(sender)
+    }
+
+    private func applyAutomaticChecks(_ enabled: Bool) {
+        guard controller.updater.automaticallyChecksForUpdates != enabled else { return }
+        controller.updater.automaticallyChecksForUpdates = enabled
+        Logger.shared.info("UpdateService: Automatic update checks \(enabled ? "enabled" : "disabled")")
+    }
+}
+
+// MARK: - SPUUpdaterDelegate
+
+extension UpdateService: SPUUpdaterDelegate {
+    func updater(_ updater: SPUUpdater, didFindValidUpdate item: SUAppcastItem) {
+        Logger.shared.info("UpdateService: Update available — \(item.displayVersionString)")
+        status = .available(version: item.displayVersionString)
+    }
+
+    func updaterDidNotFindUpdate(_ updater: SPUUpdater) {
+        status = .upToDate
+    }
+
+    func updater(_ updater: SPUUpdater, willDownloadUpdate item: SUAppcastItem, with request: NSMutableURLRequest) {
+        status = .downloading(version: item.displayVersionString)
+    }
+
+    func updater(_ updater: SPUUpdater, failedToDownloadUpdate item: SUAppcastItem, error: Error) {
+        Logger.shared.error("UpdateService: Download of \(item.displayVersionString) failed — \(error.localizedDescription)")
+        status = .failed(error.localizedDescription)
+    }
+
+    func updater(_ updater: SPUUpdater, willInstallUpdate item: SUAppcastItem) {
+        status = .installing(version: item.displayVersionString)
+    }
+
+    func updater(_ updater: SPUUpdater, didAbortWithError error: Error) {
+        // "No update found" also ends up here; only real failures are logged.
+        let nsError = error as NSError
+        guard nsError.domain != SUSparkleErrorDomain || nsError.code != Int(SUError.noUpdateError.rawValue) else { return }
+        Logger.shared.error("UpdateService: Update cycle aborted — \(error.localizedDescription)")
+        status = .failed(error.localizedDescription)
+    }
+
+    /// A user-initiated check that was cancelled reports nothing else.
+    func updater(_ updater: SPUUpdater, didFinishUpdateCycleFor updateCheck: SPUUpdateCheck, error: Error?) {
+        if status == .checking { status = .idle }
+    }
+
+    func updater(_ updater: SPUUpdater, shouldPostponeRelaunchForUpdate item: SUAppcastItem, untilInvokingBlock installHandler: @escaping () -> Void) -> Bool {
+        return false // Install immediately; do not delay relaunch
+    }
+
+    /// Called by Sparkle right before it terminates the app to install the update.
+    /// Close all windows so the process exits cleanly — an open Settings or onboarding
+    /// window can prevent the app from fully terminating, which blocks the installer.
+    func updaterWillRelaunchApplication(_ updater: SPUUpdater) {
+        NSApp.windows.forEach { $0.close() }
+    }
+}
@@ -0,0 +1,18 @@
+import SwiftUI
+
+/// The `SettingsValidator` messages for `fields`, shown under the control that sets them so
+/// a value that will not be applied — say, a hand-edited language or a model deleted
+/// since — is explained where it can be fixed.
+struct SettingsIssueText: View {
+    let issues: [SettingsValidationIssue]
+    let fields: Set<AppSettings.Key>
+
+    var body: some View {
+        ForEach(issues.filter { issue in fields.contains { $0.rawValue == issue.field } }) { issue in
+            Label(issue.message, systemImage: "exclamationmark.triangle.fill")
+                .font(.system(size: 11))
+                .foregroundStyle(.red)
+                .fixedSize(horizontal: false, vertical: true)
+        }
+    }
+}
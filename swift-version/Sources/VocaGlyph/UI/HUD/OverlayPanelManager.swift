@@ -33,7 +33,7 @@ class OverlayPanelManager: ObservableObject {
             defer: false
         )
         
-        panel.level = .floating // float above other windows
+        panel.level = Self.level(alwaysOnTop: SettingsStore.shared.settings.alwaysOnTop) // float above other windows
         panel.collectionBehavior = [.canJoinAllSpaces, .fullScreenAuxiliary] // show on all spaces
         panel.isOpaque = false
         panel.backgroundColor = .clear
@@ -44,26 +44,35 @@ class OverlayPanelManager: ObservableObject {
         self.panel = panel
     }
     
+    /// `.statusBar` keeps the HUD above full-screen apps that cover `.floating` windows.
+    func setAlwaysOnTop(_ enabled: Bool) {
+        panel?.level = Self.level(alwaysOnTop: enabled)
+    }
+
+    private static func level(alwaysOnTop: Bool) -> NSWindow.Level {
+        alwaysOnTop ? .statusBar : .floating
+    }
+
     func updateVisibility(for state: AppState) {
         guard let panel = panel else { return }
         
-        if state == .idle {
+        if state.isResting {
             // Immediately animate displayState to .idle so the SwiftUI transition
             // (fade + scale defined on the view) plays right now — the spinner
             // disappears the instant transcription finishes, which is right before
             // the text is pasted into the focused app.
             withAnimation(.easeOut(duration: 0.2)) {
-                displayState = .idle
+                displayState = state
             }
             // Keep the panel window open long enough for the transition to finish,
             // then close it.  0.25 s > the 0.2 s animation so the window never
             // disappears before the animation completes.
             DispatchQueue.main.asyncAfter(deadline: .now() + 0.25) { [weak self, weak panel] in
                 guard let self, let panel else { return }
-                // Guard: only close if state is still idle to avoid closing a panel
+                // Guard: only close if state is still resting to avoid closing a panel
                 // that has already been re-shown for a new recording session.
                 if let hc = panel.contentViewController as? NSHostingController<RecordingOverlayView>,
-                   hc.rootView.stateManager.currentState == .idle {
+                   hc.rootView.stateManager.currentState.isResting {
                     panel.orderOut(nil)
                 }
             }
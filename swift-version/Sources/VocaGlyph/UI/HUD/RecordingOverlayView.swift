@@ -86,7 +86,19 @@ struct RecordingOverlayView: View {
                             .padding(.vertical, 4)
 
                         } else if displayState == .recording {
-                            WaveformView()
+                            WaveformView(level: stateManager.inputLevel)
+
+                            // Elapsed time, or the time left once the limit is close.
+                            if let remaining = stateManager.recordingRemaining,
+                               remaining <= AppStateManager.recordingWarningSeconds {
+                                Text("\(AppStateManager.clockString(remaining)) left")
+                                    .font(.system(size: 11, weight: .semibold).monospacedDigit())
+                                    .foregroundStyle(.orange)
+                            } else {
+                                Text(AppStateManager.clockString(stateManager.recordingElapsed))
+                                    .font(.system(size: 11, weight: .medium).monospacedDigit())
+                                    .foregroundStyle(.white.opacity(0.7))
+                            }
                         } else if displayState == .processing {
                             WaveformView()
 
@@ -106,8 +118,9 @@ struct RecordingOverlayView: View {
                     .padding(.vertical, 14)
                     .frame(width: 230, height: displayState == .initializing ? 72 : 48)
 
-                    // ── "Not ready" banner (overlaid at top of pill) ─────────
-                    if let message = stateManager.notReadyMessage {
+                    // ── "Not ready" / template slot banner (overlaid at top of pill) ──
+                    if let message = stateManager.notReadyMessage
+                        ?? (displayState == .recording ? stateManager.templateSlotHint : nil) {
                         VStack {
                             Text(message)
                                 .font(.system(size: 11, weight: .medium))
@@ -125,6 +138,27 @@ struct RecordingOverlayView: View {
                         .offset(y: -52)
                         .animation(.easeInOut(duration: 0.2), value: stateManager.notReadyMessage != nil)
                     }
+
+                    // ── Live preview (below the pill) ────────────────────────
+                    if displayState == .recording || displayState == .processing,
+                       SettingsStore.shared.settings.liveTranscriptionPreview,
+                       let preview = stateManager.partialTranscript {
+                        VStack {
+                            Spacer()
+                            Text(preview)
+                                .font(.system(size: 11))
+                                .foregroundStyle(.white)
+                                .lineLimit(2)
+                                .truncationMode(.head)
+                                .padding(.horizontal, 12)
+                                .padding(.vertical, 6)
+                                .background(Color.black.opacity(0.82))
+                                .clipShape(RoundedRectangle(cornerRadius: 8))
+                        }
+                        .frame(width: 260)
+                        .offset(y: 52)
+                        .animation(.easeInOut(duration: 0.2), value: preview)
+                    }
                 }
                 .background(
                     Group {
@@ -157,7 +191,11 @@ struct RecordingOverlayView: View {
     }
 }
 
+/// Animated bars. With a `level` the bars follow the microphone, so silence looks
+/// flat; without one (while processing) they move at full height.
 struct WaveformView: View {
+    var level: Float? = nil
+
     let barCount = 28
     @State private var heights: [CGFloat] = Array(repeating: 10, count: 28)
     @State private var opacities: [Double] = Array(repeating: 0.8, count: 28)
@@ -175,8 +213,10 @@ struct WaveformView: View {
         }
         .frame(height: 16)
         .onReceive(timer) { _ in
+            // Speech RMS rarely goes above ~0.1, so scale it up to fill the bars.
+            let scale = level.map { min(1, max(0.15, CGFloat($0) * 10)) } ?? 1
             for i in 0..<barCount {
-                heights[i] = CGFloat.random(in: 4...16)
+                heights[i] = max(2, CGFloat.random(in: 4...16) * scale)
                 opacities[i] = Double.random(in: 0.5...1.0)
             }
         }
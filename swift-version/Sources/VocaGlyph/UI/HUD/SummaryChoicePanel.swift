@@ -0,0 +1,118 @@
+import Cocoa
+import SwiftUI
+
+/// Floating chooser shown after a long dictation in summary mode: the AI summary and
+/// the verbatim text side by side, each with a paste button.
+///
+/// The panel is non-activating, so clicking it leaves the app the user was dictating
+/// into frontmost and the paste lands there.
+final class SummaryChoicePanel {
+    static let shared = SummaryChoicePanel()
+
+    private var panel: NSPanel?
+
+    /// Shows both versions. `onChoose` receives the text to paste, or `nil` when the
+    /// user dismisses the panel without pasting.
+    func present(verbatim: String, summary: String, onChoose: @escaping (String?) -> Void) {
+        dismiss()
+
+        let view = SummaryChoiceView(verbatim: verbatim, summary: summary) { [weak self] choice in
+            self?.dismiss()
+            onChoose(choice)
+        }
+        let panel = NSPanel(
+            contentRect: NSRect(x: 0, y: 0, width: 560, height: 300),
+            styleMask: [.borderless, .nonactivatingPanel],
+            backing: .buffered,
+            defer: false
+        )
+        panel.level = .floating
+        panel.collectionBehavior = [.canJoinAllSpaces, .fullScreenAuxiliary]
+        panel.isOpaque = false
+        panel.backgroundColor = .clear
+        panel.hasShadow = true
+        panel.contentViewController = NSHostingController(rootView: view)
+
+        // Same placement as the recording overlay: centred below the menu bar of the
+        // screen the mouse is on.
+        let mouseLocation = NSEvent.mouseLocation
+        if let screen = NSScreen.screens.first(where: { $0.frame.contains(mouseLocation) }) ?? NSScreen.main {
+            let x = screen.visibleFrame.midX - panel.frame.width / 2
+            let y = screen.visibleFrame.maxY - panel.frame.height - 16
+            panel.setFrameOrigin(NSPoint(x: x, y: y))
+        }
+        panel.orderFrontRegardless()
+        self.panel = panel
+    }
+
+    func dismiss() {
+        panel?.orderOut(nil)
+        panel = nil
+    }
+}
+
+// MARK: - SummaryChoiceView
+
+struct SummaryChoiceView: View {
+    let verbatim: String
+    let summary: String
+    let onChoose: (String?) -> Void
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 12) {
+            HStack {
+                Text("Long dictation — paste which version?")
+                    .font(.system(size: 13, weight: .semibold))
+                    .foregroundStyle(Theme.navy)
+                Spacer()
+                Button(action: { onChoose(nil) }) {
+                    Image(systemName: "xmark.circle.fill")
+                        .foregroundStyle(Theme.textMuted)
+                }
+                .buttonStyle(.plain)
+                .help("Dismiss — the verbatim text stays in History")
+            }
+
+            HStack(alignment: .top, spacing: 12) {
+                column(title: "Summary", text: summary, button: "Paste Summary", prominent: true)
+                column(title: "Verbatim", text: verbatim, button: "Paste Verbatim", prominent: false)
+            }
+        }
+        .padding(16)
+        .frame(width: 560, height: 300)
+        .background(Theme.background)
+        .clipShape(.rect(cornerRadius: 12))
+        .overlay(
+            RoundedRectangle(cornerRadius: 12)
+                .stroke(Theme.textMuted.opacity(0.2), lineWidth: 1)
+        )
+    }
+
+    @ViewBuilder
+    private func column(title: String, text: String, button: String, prominent: Bool) -> some View {
+        VStack(alignment: .leading, spacing: 8) {
+            Text(title)
+                .font(.system(size: 11, weight: .medium))
+                .foregroundStyle(Theme.textMuted)
+            ScrollView {
+                Text(text)
+                    .font(.system(size: 12))
+                    .foregroundStyle(Theme.navy)
+                    .frame(maxWidth: .infinity, alignment: .leading)
+                    .textSelection(.enabled)
+            }
+            .padding(8)
+            .background(Color.white)
+            .clipShape(.rect(cornerRadius: 8))
+
+            if prominent {
+                Button(button) { onChoose(text) }
+                    .buttonStyle(.borderedProminent)
+            } else {
+                Button(button) { onChoose(text) }
+                    .buttonStyle(.bordered)
+            }
+        }
+        .frame(maxWidth: .infinity)
+    }
+}
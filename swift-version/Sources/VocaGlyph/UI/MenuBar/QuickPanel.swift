@@ -0,0 +1,202 @@
+import Cocoa
+import SwiftUI
+
+// MARK: - QuickPanelTarget
+
+/// What the quick panel reads and changes; `AppDelegate` implements this.
+protocol QuickPanelTarget: ControlAPITarget {
+    /// Models to offer, in tray order.
+    @MainActor func quickPanelModels() -> [(id: String, title: String)]
+    /// Newest history entry, or the last insertion when history is off.
+    @MainActor func lastTranscriptionText() -> String?
+    /// `false` while a recording or model load is in progress.
+    @MainActor func canChangeDictationPause() -> Bool
+    @MainActor func setDictationPaused(_ paused: Bool)
+    @MainActor func copyToClipboard(_ text: String)
+}
+
+// MARK: - QuickPanelModel
+
+/// Snapshot of what the quick panel shows, refreshed when it opens and whenever
+/// settings or history change while it is visible.
+@MainActor
+final class QuickPanelModel: ObservableObject {
+    struct Option: Identifiable, Equatable {
+        let id: String
+        let title: String
+    }
+
+    /// `RuntimeStatus.message`, e.g. "Downloading Whisper Small — 42%".
+    @Published private(set) var statusMessage = ""
+    @Published private(set) var models: [Option] = []
+    @Published private(set) var selectedModel = ""
+    @Published private(set) var selectedLanguage = ""
+    @Published private(set) var isPaused = false
+    @Published private(set) var canTogglePause = false
+    @Published private(set) var lastResult: String?
+    /// Why the last change was rejected, shown under the pickers.
+    @Published private(set) var message: String?
+
+    let languages = WhisperService.supportedDictationLanguages
+    private weak var target: QuickPanelTarget?
+
+    init(target: QuickPanelTarget) {
+        self.target = target
+    }
+
+    func refresh() {
+        guard let target else { return }
+        let status = target.controlStatus()
+        statusMessage = status.message
+        models = target.quickPanelModels().map { Option(id: $0.id, title: $0.title) }
+        selectedModel = status.model
+        selectedLanguage = status.language
+        isPaused = status.paused
+        canTogglePause = target.canChangeDictationPause()
+        lastResult = target.lastTranscriptionText()
+    }
+
+    func selectModel(_ id: String) {
+        guard id != selectedModel else { return }
+        message = target?.switchModel(to: id)
+        refresh()
+    }
+
+    func selectLanguage(_ label: String) {
+        guard label != selectedLanguage else { return }
+        message = target?.switchLanguage(to: label)
+        refresh()
+    }
+
+    func setPaused(_ paused: Bool) {
+        target?.setDictationPaused(paused)
+        refresh()
+    }
+
+    func copyLastResult() {
+        guard let lastResult else { return }
+        target?.copyToClipboard(lastResult)
+    }
+
+    func openSettings() {
+        target?.openSettings()
+    }
+}
+
+// MARK: - QuickPanel
+
+/// Compact popover under the status item for routine changes — model, language, pause —
+/// and the last result, without opening the Settings window.
+@MainActor
+final class QuickPanel: NSObject, NSPopoverDelegate {
+
+    private let popover = NSPopover()
+    private let model: QuickPanelModel
+    private var settingsSubscription: SettingsSubscription?
+    private var historyObserver: NSObjectProtocol?
+
+    init(target: QuickPanelTarget) {
+        model = QuickPanelModel(target: target)
+        super.init()
+        popover.behavior = .transient
+        popover.delegate = self
+        popover.contentViewController = NSHostingController(rootView: QuickPanelView(model: model) { [weak self] in
+            self?.close()
+        })
+    }
+
+    var isShown: Bool { popover.isShown }
+
+    func show(relativeTo button: NSStatusBarButton) {
+        model.refresh()
+        settingsSubscription = SettingsStore.shared.subscribe { [weak self] _, _ in
+            DispatchQueue.main.async { self?.model.refresh() }
+        }
+        historyObserver = HistoryChangedEvent.observe { [weak self] _ in
+            Task { @MainActor in self?.model.refresh() }
+        }
+        // An accessory app has to be active for the popover's pickers to take clicks.
+        NSApp.activate(ignoringOtherApps: true)
+        popover.show(relativeTo: button.bounds, of: button, preferredEdge: .minY)
+    }
+
+    func close() {
+        popover.performClose(nil)
+    }
+
+    func popoverDidClose(_ notification: Notification) {
+        settingsSubscription = nil
+        if let historyObserver {
+            NotificationCenter.default.removeObserver(historyObserver)
+            self.historyObserver = nil
+        }
+    }
+}
+
+// MARK: - QuickPanelView
+
+struct QuickPanelView: View {
+    @ObservedObject var model: QuickPanelModel
+    let onClose: () -> Void
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 12) {
+            VStack(alignment: .leading, spacing: 2) {
+                Text("VocaGlyph")
+                    .font(.system(size: 13, weight: .semibold))
+                    .foregroundStyle(Theme.navy)
+                Text(model.statusMessage)
+                    .font(.system(size: 11))
+                    .foregroundStyle(Theme.textMuted)
+            }
+
+            Picker("Model", selection: Binding(get: { model.selectedModel }, set: { model.selectModel($0) })) {
+                ForEach(model.models) { option in
+                    Text(option.title).tag(option.id)
+                }
+            }
+
+            Picker("Language", selection: Binding(get: { model.selectedLanguage }, set: { model.selectLanguage($0) })) {
+                ForEach(model.languages, id: \.self) { label in
+                    Text(label).tag(label)
+                }
+            }
+
+            Toggle("Pause Dictation", isOn: Binding(get: { model.isPaused }, set: { model.setPaused($0) }))
+                .toggleStyle(.switch)
+                .disabled(!model.canTogglePause)
+
+            if let message = model.message {
+                Text(message)
+                    .font(.system(size: 11))
+                    .foregroundStyle(.red)
+            }
+
+            Divider()
+
+            VStack(alignment: .leading, spacing: 6) {
+                Text("Last Result")
+                    .font(.system(size: 11, weight: .medium))
+                    .foregroundStyle(Theme.textMuted)
+                Text(model.lastResult ?? "Nothing dictated yet.")
+                    .font(.system(size: 12))
+                    .foregroundStyle(model.lastResult == nil ? Theme.textMuted : Theme.navy)
+                    .lineLimit(4)
+                    .frame(maxWidth: .infinity, alignment: .leading)
+                    .textSelection(.enabled)
+            }
+
+            HStack {
+                Button("Copy") { model.copyLastResult() }
+                    .disabled(model.lastResult == nil)
+                Spacer()
+                Button("Settings…") {
+                    onClose()
+                    model.openSettings()
+                }
+            }
+        }
+        .padding(16)
+        .frame(width: 300)
+    }
+}
@@ -1,8 +1,22 @@
 import SwiftUI
 
-/// Developer Options section: debug logging toggle and log-file reveal button.
+/// Developer Options section: debug logging toggle, per-component log levels, the log
+/// viewer and reveal button, dictation performance, OpenTelemetry export, the local
+/// control API, dictation notifications, the transcription service, live captions and plugins.
 struct DeveloperOptionsSection: View {
     @AppStorage("enableDebugLogging") private var isDebugEnabled: Bool = false
+    @AppStorage("controlAPIEnabled") private var isControlAPIEnabled: Bool = false
+    @AppStorage("externalTriggersEnabled") private var isExternalTriggersEnabled: Bool = false
+    @AppStorage("transcriptionServiceEnabled") private var isTranscriptionServiceEnabled: Bool = false
+    @AppStorage("liveCaptionsEnabled") private var isLiveCaptionsEnabled: Bool = false
+    @AppStorage("liveCaptionsPort") private var liveCaptionsPort: Int = 7979
+    @AppStorage("pluginsEnabled") private var isPluginsEnabled: Bool = false
+    @AppStorage("otlpExportEnabled") private var isOTLPExportEnabled: Bool = false
+    @AppStorage("otlpEndpoint") private var otlpEndpoint: String = OTLPExporter.defaultEndpoint
+    @State private var logLevels = LogLevels(settings: SettingsStore.shared.settings)
+    @State private var isShowingLogs = false
+    @State private var metrics = MetricsService.shared.snapshot()
+    @State private var metricsObserver: NSObjectProtocol?
 
     var body: some View {
         VStack(alignment: .leading, spacing: 16) {
@@ -33,6 +47,39 @@ struct DeveloperOptionsSection: View {
                 }
                 .padding(16)
 
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Component Log Levels
+                VStack(alignment: .leading, spacing: 8) {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Component Log Levels")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Log one part of the app in more or less detail than the rest. Default follows Debug Logging above.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    ForEach(LogComponent.allCases, id: \.self) { component in
+                        HStack {
+                            Text(component.rawValue)
+                                .font(.system(size: 13, design: .monospaced))
+                                .foregroundStyle(Theme.navy)
+                            Spacer()
+                            Picker("", selection: levelBinding(component)) {
+                                Text("Default").tag(LogLevel?.none)
+                                ForEach(LogLevel.allCases, id: \.self) { level in
+                                    Text(level.name.capitalized).tag(LogLevel?.some(level))
+                                }
+                            }
+                            .labelsHidden()
+                            .frame(width: 120)
+                        }
+                    }
+                }
+                .padding(16)
+
                 Divider()
                     .background(Theme.textMuted.opacity(0.1))
                     .padding(.horizontal, 16)
@@ -48,6 +95,20 @@ struct DeveloperOptionsSection: View {
                             .foregroundStyle(Theme.textMuted)
                     }
                     Spacer()
+                    Button("Show Logs") {
+                        Logger.shared.debug("Settings: Clicked Show Logs")
+                        isShowingLogs = true
+                    }
+                    .buttonStyle(.plain)
+                    .font(.system(size: 13, weight: .medium))
+                    .foregroundStyle(Theme.accent)
+                    .padding(.horizontal, 12)
+                    .padding(.vertical, 6)
+                    .background(Theme.accent.opacity(0.1))
+                    .clipShape(RoundedRectangle(cornerRadius: 6))
+                    .sheet(isPresented: $isShowingLogs) {
+                        LogViewerView(onClose: { isShowingLogs = false })
+                    }
                     Button("Reveal in Finder") {
                         Logger.shared.debug("Settings: Clicked Reveal in Finder")
                         NSWorkspace.shared.selectFile(Logger.shared.getLogFileURL().path, inFileViewerRootedAtPath: "")
@@ -61,6 +122,209 @@ struct DeveloperOptionsSection: View {
                     .clipShape(RoundedRectangle(cornerRadius: 6))
                 }
                 .padding(16)
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Dictation Performance
+                VStack(alignment: .leading, spacing: 8) {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Dictation Performance")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text(metrics.transcriptions == 0
+                             ? "Timings appear after your first dictation."
+                             : "Median and 95th percentile of \(metrics.transcriptions) dictations this session.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    ForEach(MetricsStage.allCases, id: \.self) { stage in
+                        if let histogram = metrics.latency(stage) {
+                            metricsRow(stage.rawValue, histogram: histogram, unit: "s")
+                        }
+                    }
+                    if metrics.realTimeFactor.count > 0 {
+                        metricsRow("real-time factor", histogram: metrics.realTimeFactor, unit: "×")
+                    }
+                }
+                .padding(16)
+                .onAppear {
+                    metrics = MetricsService.shared.snapshot()
+                    metricsObserver = MetricsUpdatedEvent.observe { metrics = $0.snapshot }
+                }
+                .onDisappear {
+                    if let metricsObserver { NotificationCenter.default.removeObserver(metricsObserver) }
+                    metricsObserver = nil
+                }
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // OpenTelemetry Export
+                VStack(alignment: .leading, spacing: 8) {
+                    HStack {
+                        VStack(alignment: .leading, spacing: 2) {
+                            Text("Export to OpenTelemetry")
+                                .fontWeight(.semibold)
+                                .foregroundStyle(Theme.navy)
+                            Text("Send these timings and per-dictation traces to an OTLP/HTTP collector. No text is sent.")
+                                .font(.system(size: 12))
+                                .foregroundStyle(Theme.textMuted)
+                        }
+                        Spacer()
+                        Toggle("", isOn: $isOTLPExportEnabled.logged(name: "OTLP Export"))
+                            .labelsHidden()
+                            .toggleStyle(.switch)
+                    }
+                    if isOTLPExportEnabled {
+                        TextField(OTLPExporter.defaultEndpoint, text: $otlpEndpoint)
+                            .textFieldStyle(.roundedBorder)
+                            .font(.system(size: 13, design: .monospaced))
+                    }
+                }
+                .padding(16)
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Local Control API
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Local Control API")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Let Stream Deck, foot pedals and editor plugins drive dictation through a JSON socket at \(AppDirs.current.sockets.appendingPathComponent(ControlSocketService.socketName).path). Requests need the token in \(ControlSocketService.tokenName).")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $isControlAPIEnabled.logged(name: "Local Control API"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Dictation Notifications
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Dictation Notifications")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Start and stop dictation when a script or Karabiner rule posts \(ExternalTriggerService.notificationPrefix).start, .stop or .toggle. These need no token, so any app on this Mac can start a recording.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $isExternalTriggersEnabled.logged(name: "Dictation Notifications"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Transcription Service
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Transcription Service")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Let other apps on this Mac transcribe WAV or PCM audio with the loaded model over JSON-RPC on \(TranscriptionServer.socketName). Uses the same token as the control API.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $isTranscriptionServiceEnabled.logged(name: "Transcription Service"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Live Captions
+                VStack(alignment: .leading, spacing: 8) {
+                    HStack {
+                        VStack(alignment: .leading, spacing: 2) {
+                            Text("Live Captions")
+                                .fontWeight(.semibold)
+                                .foregroundStyle(Theme.navy)
+                            Text("Stream partial and final transcriptions over WebSocket on this Mac. Add the caption page as an OBS browser source, or connect your own overlay to the same address.")
+                                .font(.system(size: 12))
+                                .foregroundStyle(Theme.textMuted)
+                                .fixedSize(horizontal: false, vertical: true)
+                        }
+                        Spacer()
+                        Toggle("", isOn: $isLiveCaptionsEnabled.logged(name: "Live Captions"))
+                            .labelsHidden()
+                            .toggleStyle(.switch)
+                    }
+                    if isLiveCaptionsEnabled {
+                        HStack(spacing: 8) {
+                            Text("Port")
+                                .font(.system(size: 12))
+                                .foregroundStyle(Theme.textMuted)
+                            TextField("7979", value: $liveCaptionsPort, format: .number.grouping(.never))
+                                .textFieldStyle(.roundedBorder)
+                                .font(.system(size: 13, design: .monospaced))
+                                .frame(width: 80)
+                            Text("http://127.0.0.1:\(String(liveCaptionsPort))/")
+                                .font(.system(size: 12, design: .monospaced))
+                                .foregroundStyle(Theme.textMuted)
+                                .textSelection(.enabled)
+                        }
+                    }
+                }
+                .padding(16)
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Plugins
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Plugins")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Run processor and output plugins from the plugins folder. Each one is a program that exchanges JSON lines over stdin and stdout. Turn off and on again to reload.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Button("Open Folder") {
+                        Logger.shared.debug("Settings: Clicked Open Plugins Folder")
+                        let directory = PluginHost().directory
+                        try? FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+                        NSWorkspace.shared.open(directory)
+                    }
+                    .buttonStyle(.plain)
+                    .font(.system(size: 13, weight: .medium))
+                    .foregroundStyle(Theme.accent)
+                    .padding(.horizontal, 12)
+                    .padding(.vertical, 6)
+                    .background(Theme.accent.opacity(0.1))
+                    .clipShape(RoundedRectangle(cornerRadius: 6))
+                    Toggle("", isOn: $isPluginsEnabled.logged(name: "Plugins"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
             }
             .background(Color.white)
             .clipShape(.rect(cornerRadius: 12))
@@ -70,4 +334,29 @@ struct DeveloperOptionsSection: View {
             )
         }
     }
+
+    private func metricsRow(_ name: String, histogram: Histogram, unit: String) -> some View {
+        let format = { (value: Double?) in value.map { String(format: "%.2f\(unit)", $0) } ?? "—" }
+        return HStack {
+            Text(name)
+                .font(.system(size: 13, design: .monospaced))
+                .foregroundStyle(Theme.navy)
+            Spacer()
+            Text("p50 \(format(histogram.percentile(0.5)))   p95 \(format(histogram.percentile(0.95)))")
+                .font(.system(size: 12, design: .monospaced))
+                .foregroundStyle(Theme.textMuted)
+        }
+    }
+
+    private func levelBinding(_ component: LogComponent) -> Binding<LogLevel?> {
+        Binding(
+            get: { logLevels.overrides[component] },
+            set: { level in
+                logLevels.overrides[component] = level
+                let lines = logLevels.lines
+                Logger.shared.debug("Settings: Log levels set to \(lines)")
+                SettingsStore.shared.update { $0.logLevels = lines }
+            }
+        )
+    }
 }
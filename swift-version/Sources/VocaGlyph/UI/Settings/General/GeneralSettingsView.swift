@@ -6,6 +6,7 @@ struct GeneralSettingsView: View {
     @ObservedObject var whisper: WhisperService
     @ObservedObject var stateManager: AppStateManager
     @Bindable var microphoneService: MicrophoneService
+    let settingsAPI: SettingsAPI
 
     var body: some View {
         VStack(alignment: .leading, spacing: 0) {
@@ -32,6 +33,7 @@ struct GeneralSettingsView: View {
                     SystemIntegrationSection()
                     PrivacySettingsSection()
                     DeveloperOptionsSection()
+                    SettingsBackupSection(settingsAPI: settingsAPI)
                 }
                 .padding(40)
                 .padding(.bottom, 20)
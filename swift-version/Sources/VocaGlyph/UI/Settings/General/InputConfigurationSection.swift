@@ -1,7 +1,7 @@
 import SwiftUI
 
 /// Input Configuration section: global shortcut, dictation language,
-/// microphone selection, auto-punctuation, and filler word removal.
+/// microphone selection, auto-punctuation, filler word removal and voice commands.
 struct InputConfigurationSection: View {
     @Bindable var microphoneService: MicrophoneService
 
@@ -10,6 +10,8 @@ struct InputConfigurationSection: View {
     @AppStorage("dictationLanguage") private var dictationLanguage: String = "Auto-Detect"
     @AppStorage("autoPunctuation") private var autoPunctuation: Bool = true
     @AppStorage("removeFillerWords") private var removeFillerWords: Bool = false
+    @AppStorage("voiceCommandsEnabled") private var voiceCommandsEnabled: Bool = false
+    @AppStorage("voiceCommandPrefix") private var voiceCommandPrefix: String = "computer"
 
     private var currentShortcutDisplay: String {
         let flags = CGEventFlags(rawValue: UInt64(customShortcutModifiersRaw))
@@ -196,7 +198,7 @@ struct InputConfigurationSection: View {
                         Text("Remove Filler Words")
                             .fontWeight(.semibold)
                             .foregroundStyle(Theme.navy)
-                        Text("Automatically strip conversational words (um, uh, like)")
+                        Text("Strip um/uh, stutters like \"I I think\" and a trailing \"so…\"")
                             .font(.system(size: 12))
                             .foregroundStyle(Theme.textMuted)
                     }
@@ -206,6 +208,33 @@ struct InputConfigurationSection: View {
                         .toggleStyle(.switch)
                 }
                 .padding(16)
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Voice Commands
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Voice Commands")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Say \"\(voiceCommandPrefix), switch to Spanish\", \"…use the small model\" or \"…pause dictation\". \"Make that title case\" re-types your last dictation.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    if voiceCommandsEnabled {
+                        TextField("computer", text: $voiceCommandPrefix)
+                            .textFieldStyle(.roundedBorder)
+                            .frame(width: 110)
+                            .help("Word that starts a command")
+                    }
+                    Toggle("", isOn: $voiceCommandsEnabled.logged(name: "Voice Commands"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
             }
             .background(Color.white)
             .clipShape(.rect(cornerRadius: 12))
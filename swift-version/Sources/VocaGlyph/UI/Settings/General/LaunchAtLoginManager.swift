@@ -1,22 +1,36 @@
-import ServiceManagement
+import Foundation
 
 /// Encapsulates Launch-at-Login registration logic so it can be unit-tested
 /// without touching the real SMAppService on a real machine.
 @Observable @MainActor
 final class LaunchAtLoginManager {
-    var isEnabled: Bool = SMAppService.mainApp.status == .enabled
+    private let service: LoginItemService
+    var isEnabled: Bool
+    /// Switched off by the user in System Settings → Login Items.
+    var requiresApproval: Bool
+
+    init(service: LoginItemService = LoginItemService()) {
+        self.service = service
+        isEnabled = service.isEnabled
+        requiresApproval = service.requiresApproval
+    }
 
     func setEnabled(_ newValue: Bool) {
         do {
-            if newValue {
-                try SMAppService.mainApp.register()
-            } else {
-                try SMAppService.mainApp.unregister()
-            }
-            isEnabled = newValue
+            try service.setEnabled(newValue)
         } catch {
-            // Revert to actual service state on failure
-            isEnabled = SMAppService.mainApp.status == .enabled
+            Logger.shared.error("LaunchAtLoginManager: \(error.localizedDescription)")
         }
+        // Reflect the actual service state, whether or not the change worked.
+        refresh()
+    }
+
+    func refresh() {
+        isEnabled = service.isEnabled
+        requiresApproval = service.requiresApproval
+    }
+
+    func openLoginItemsSettings() {
+        service.openLoginItemsSettings()
     }
 }
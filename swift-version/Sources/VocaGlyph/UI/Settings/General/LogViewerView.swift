@@ -0,0 +1,64 @@
+import SwiftUI
+
+/// The end of the app log, following new lines live — so a problem can be reported
+/// without finding the log file. Opened from Developer Tools.
+struct LogViewerView: View {
+    /// Lines kept on screen; older ones scroll out.
+    static let lineLimit = 500
+
+    var onClose: () -> Void
+
+    @State private var lines: [String] = []
+    @State private var observer: NSObjectProtocol?
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 12) {
+            HStack {
+                Text("Recent Logs")
+                    .font(.system(size: 16, weight: .bold))
+                    .foregroundStyle(Theme.navy)
+                Spacer()
+                Button("Copy All") {
+                    NSPasteboard.general.clearContents()
+                    NSPasteboard.general.setString(lines.joined(separator: "\n"), forType: .string)
+                }
+                Button("Done", action: onClose)
+                    .keyboardShortcut(.defaultAction)
+            }
+
+            ScrollViewReader { proxy in
+                ScrollView {
+                    LazyVStack(alignment: .leading, spacing: 2) {
+                        ForEach(lines.indices, id: \.self) { index in
+                            Text(lines[index])
+                                .font(.system(size: 11, design: .monospaced))
+                                .foregroundStyle(lines[index].contains("[ERROR]") ? Color.red : Theme.navy)
+                                .textSelection(.enabled)
+                                .id(index)
+                        }
+                    }
+                    .frame(maxWidth: .infinity, alignment: .leading)
+                    .padding(8)
+                }
+                .background(Color.white)
+                .clipShape(.rect(cornerRadius: 8))
+                .onChange(of: lines.count) { _, count in
+                    proxy.scrollTo(count - 1, anchor: .bottom)
+                }
+            }
+        }
+        .padding(20)
+        .frame(width: 720, height: 480)
+        .onAppear {
+            lines = Logger.shared.tail(lines: Self.lineLimit)
+            observer = LogLineEvent.observe { event in
+                lines.append(event.line)
+                if lines.count > Self.lineLimit { lines.removeFirst(lines.count - Self.lineLimit) }
+            }
+        }
+        .onDisappear {
+            if let observer { NotificationCenter.default.removeObserver(observer) }
+            observer = nil
+        }
+    }
+}
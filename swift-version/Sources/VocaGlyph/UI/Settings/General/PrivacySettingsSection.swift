@@ -1,16 +1,59 @@
 import SwiftUI
 
-/// Privacy section: Privacy Mode toggle.
+/// Privacy section: Privacy Mode toggle and context-capture preferences.
 ///
 /// When Privacy Mode is enabled the app skips writing new transcription results
 /// to the local SwiftData history store. Transcription output is still typed
 /// into the target app as normal — only the history record is suppressed.
 ///
+/// History retention bounds what is kept: entries older than N days, all but the
+/// newest N entries, nothing, or everything. `HistoryService` enforces it.
+///
+/// Incognito mode goes further than Privacy Mode: while on, dictations also skip
+/// context capture and transcripts are kept out of the logs. It has its own optional
+/// shortcut and a distinct menu bar icon.
+///
+/// Redaction masks credit card numbers, email addresses and custom patterns before
+/// a transcription reaches history (and, optionally, the logs). See `TextRedactor`.
+///
+/// Dictations reach the target app through the clipboard. That copy is marked as
+/// transient so clipboard history managers don't record it; users who want their
+/// dictations in the history can turn the marking off.
+///
 /// Application logs already respect the existing "Enable Debug Logging" toggle
-/// (off by default). Keeping that toggle off also prevents transcribed text
-/// from appearing in the log file.
+/// (off by default). Transcripts in the logs can also be truncated or replaced by a
+/// hash at all times — see `TranscriptLogging`.
+///
+/// Context capture reads the text before the cursor (via Accessibility) so AI
+/// post-processing can match names and tone. It is off until the user opts in, and
+/// only runs while AI post-processing is on. Users can change how
+/// much is read, or exclude individual apps by bundle identifier. Rewriting selected
+/// text reads the selection the same way and honours the same exclusions.
 struct PrivacySettingsSection: View {
     @AppStorage("privacyModeEnabled") private var isPrivacyModeEnabled: Bool = false
+    @AppStorage("historyRetention") private var historyRetention: String = HistoryService.Retention.days.rawValue
+    @AppStorage("historyRetentionDays") private var historyRetentionDays: Int = 30
+    @AppStorage("historyRetentionEntries") private var historyRetentionEntries: Int = 500
+    @AppStorage("incognitoModeEnabled") private var isIncognitoModeEnabled: Bool = false
+    @AppStorage("transcriptLogging") private var transcriptLogging: String = TranscriptLogging.full.rawValue
+    @AppStorage("incognitoShortcutKeyCode") private var incognitoShortcutKeyCode: Int = -1
+    @AppStorage("incognitoShortcutModifiers") private var incognitoShortcutModifiersRaw: Double = 0
+    @AppStorage("redactionEnabled") private var isRedactionEnabled: Bool = false
+    @AppStorage("redactLogs") private var redactLogs: Bool = true
+    @AppStorage("hideFromClipboardHistory") private var hideFromClipboardHistory: Bool = true
+    @State private var redactionRules: Set<String> = Set(SettingsStore.shared.settings.redactionRules)
+    @State private var customPatternsText: String = SettingsStore.shared.settings.redactionCustomPatterns.joined(separator: "\n")
+    @State private var invalidPatterns: [String] = []
+    @AppStorage("contextCaptureEnabled") private var isContextCaptureEnabled: Bool = false
+    @AppStorage("selectionRewriteEnabled") private var isSelectionRewriteEnabled: Bool = false
+    @AppStorage("contextCaptureCharacterLimit") private var contextCharacterLimit: Int = ContextCaptureService.defaultCharacterLimit
+    @State private var excludedAppsText: String = SettingsStore.shared.settings.contextCaptureExcludedApps.joined(separator: ", ")
+
+    private var incognitoShortcutDisplay: String {
+        guard incognitoShortcutKeyCode >= 0 else { return "No shortcut" }
+        let flags = CGEventFlags(rawValue: UInt64(incognitoShortcutModifiersRaw))
+        return ShortcutDisplayHelper.displayString(keyCode: CGKeyCode(incognitoShortcutKeyCode), flags: flags)
+    }
 
     var body: some View {
         VStack(alignment: .leading, spacing: 16) {
@@ -41,6 +84,244 @@ struct PrivacySettingsSection: View {
                         .toggleStyle(.switch)
                 }
                 .padding(16)
+
+                Divider()
+
+                // Clipboard history
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Hide Dictations from Clipboard History")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Ask clipboard managers such as Maccy, Paste or Raycast to skip the copy used to paste a dictation. Text you copy from history is kept as usual.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $hideFromClipboardHistory.logged(name: "Hide Dictations from Clipboard History"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider()
+
+                // Incognito Mode
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Incognito Mode")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Skip history, surrounding text and transcript logging until you turn it off. Also in the menu bar.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    ShortcutRecorderButton(
+                        displayLabel: incognitoShortcutDisplay,
+                        onShortcutRecorded: { keyCode, modifiers in
+                            // A toggle needs a key press; modifier-only chords are for dictation.
+                            guard keyCode != kModifierOnlyKeyCode else { return }
+                            Logger.shared.debug("Settings: Recorded incognito shortcut keyCode=\(keyCode) modifiers=\(modifiers.rawValue)")
+                            incognitoShortcutKeyCode = Int(keyCode)
+                            incognitoShortcutModifiersRaw = Double(modifiers.rawValue)
+                        },
+                        onReset: {
+                            Logger.shared.debug("Settings: Cleared incognito shortcut")
+                            incognitoShortcutKeyCode = -1
+                            incognitoShortcutModifiersRaw = 0
+                        },
+                        resetHelp: "Remove shortcut"
+                    )
+                    Toggle("", isOn: $isIncognitoModeEnabled.logged(name: "Incognito Mode"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider()
+
+                // Transcripts in Logs
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Transcripts in Logs")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("How dictated text and surrounding text are written to the log file. A hash lets you match log lines without revealing the words.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Picker("", selection: $transcriptLogging.logged(name: "Transcripts in Logs")) {
+                        ForEach(TranscriptLogging.allCases, id: \.self) { mode in
+                            Text(mode.title).tag(mode.rawValue)
+                        }
+                    }
+                    .labelsHidden()
+                    .fixedSize()
+                }
+                .padding(16)
+
+                Divider()
+
+                // History Retention
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Keep History")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Older transcriptions are deleted automatically after each dictation.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Picker("", selection: $historyRetention.logged(name: "History Retention")) {
+                        Text("For a number of days").tag(HistoryService.Retention.days.rawValue)
+                        Text("A number of entries").tag(HistoryService.Retention.entries.rawValue)
+                        Text("Forever").tag(HistoryService.Retention.unlimited.rawValue)
+                        Text("Don't keep history").tag(HistoryService.Retention.disabled.rawValue)
+                    }
+                    .labelsHidden()
+                    .fixedSize()
+                }
+                .padding(16)
+
+                if historyRetention == HistoryService.Retention.days.rawValue {
+                    retentionStepper(label: "Days to keep", value: $historyRetentionDays,
+                                     range: HistoryService.retentionDaysRange, step: 1)
+                } else if historyRetention == HistoryService.Retention.entries.rawValue {
+                    retentionStepper(label: "Entries to keep", value: $historyRetentionEntries,
+                                     range: HistoryService.retentionEntriesRange, step: 50)
+                }
+
+                Divider()
+
+                // Redaction
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Redact Sensitive Text")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Mask card numbers, email addresses and your own patterns before a transcription is saved to history. Pasted text is unchanged.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $isRedactionEnabled.logged(name: "Redaction"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                if isRedactionEnabled {
+                    Divider()
+
+                    VStack(alignment: .leading, spacing: 8) {
+                        Toggle("Credit card numbers", isOn: ruleBinding(.creditCard))
+                        Toggle("Email addresses", isOn: ruleBinding(.email))
+                        Toggle("Also redact application logs", isOn: $redactLogs.logged(name: "Redact Logs"))
+                    }
+                    .foregroundStyle(Theme.navy)
+                    .padding(16)
+
+                    Divider()
+
+                    VStack(alignment: .leading, spacing: 6) {
+                        Text("Custom patterns")
+                            .foregroundStyle(Theme.navy)
+                        TextField("\\bACME-\\d{6}\\b", text: $customPatternsText, axis: .vertical)
+                            .textFieldStyle(.roundedBorder)
+                            .font(.system(size: 12, design: .monospaced))
+                            .lineLimit(1...5)
+                            .onSubmit(saveCustomPatterns)
+                        if invalidPatterns.isEmpty {
+                            Text("One regular expression per line (⌥↩ for a new line). Matches are replaced with \(TextRedactor.customPlaceholder).")
+                                .font(.system(size: 12))
+                                .foregroundStyle(Theme.textMuted)
+                        } else {
+                            Text("Not saved — invalid: \(invalidPatterns.joined(separator: ", "))")
+                                .font(.system(size: 12))
+                                .foregroundStyle(.red)
+                        }
+                    }
+                    .padding(16)
+                }
+
+                Divider()
+
+                // Context Capture
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Read Surrounding Text")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Send the text before your cursor to AI post-processing so it can match names and tone. With a cloud provider, that text leaves your Mac. Only used while AI post-processing is on.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $isContextCaptureEnabled.logged(name: "Context Capture"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                if isContextCaptureEnabled {
+                    Divider()
+
+                    HStack {
+                        Text("Characters to read")
+                            .foregroundStyle(Theme.navy)
+                        Spacer()
+                        Stepper(value: $contextCharacterLimit,
+                                in: ContextCaptureService.characterLimitRange,
+                                step: 50) {
+                            Text("\(contextCharacterLimit)")
+                                .monospacedDigit()
+                                .foregroundStyle(Theme.textMuted)
+                        }
+                    }
+                    .padding(16)
+
+                    Divider()
+
+                    VStack(alignment: .leading, spacing: 6) {
+                        Text("Excluded apps")
+                            .foregroundStyle(Theme.navy)
+                        TextField("com.agilebits.onepassword7, com.apple.keychainaccess", text: $excludedAppsText)
+                            .textFieldStyle(.roundedBorder)
+                            .onSubmit(saveExcludedApps)
+                        Text("Comma-separated bundle identifiers. Text is never read from these apps.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    .padding(16)
+                }
+
+                Divider()
+
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Rewrite Selected Text")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("With text selected, dictate an instruction (\"make this more formal\") and AI post-processing replaces the selection.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $isSelectionRewriteEnabled.logged(name: "Rewrite Selected Text"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
             }
             .background(Color.white)
             .clipShape(.rect(cornerRadius: 12))
@@ -50,4 +331,55 @@ struct PrivacySettingsSection: View {
             )
         }
     }
+
+    @ViewBuilder
+    private func retentionStepper(label: String, value: Binding<Int>, range: ClosedRange<Int>, step: Int) -> some View {
+        Divider()
+
+        HStack {
+            Text(label)
+                .foregroundStyle(Theme.navy)
+            Spacer()
+            Stepper(value: value, in: range, step: step) {
+                Text("\(value.wrappedValue)")
+                    .monospacedDigit()
+                    .foregroundStyle(Theme.textMuted)
+            }
+        }
+        .padding(16)
+    }
+
+    private func ruleBinding(_ rule: TextRedactor.Rule) -> Binding<Bool> {
+        Binding(
+            get: { redactionRules.contains(rule.rawValue) },
+            set: { isOn in
+                if isOn { redactionRules.insert(rule.rawValue) } else { redactionRules.remove(rule.rawValue) }
+                let rules = TextRedactor.Rule.allCases.map(\.rawValue).filter(redactionRules.contains)
+                Logger.shared.debug("Settings: Redaction rules set to \(rules)")
+                SettingsStore.shared.update { $0.redactionRules = rules }
+            }
+        )
+    }
+
+    private func saveCustomPatterns() {
+        let patterns = customPatternsText
+            .split(whereSeparator: \.isNewline)
+            .map { $0.trimmingCharacters(in: .whitespaces) }
+            .filter { !$0.isEmpty }
+        invalidPatterns = patterns.filter { !TextRedactor.isValidPattern($0) }
+        guard invalidPatterns.isEmpty else { return }
+        Logger.shared.debug("Settings: \(patterns.count) custom redaction patterns saved")
+        SettingsStore.shared.update { $0.redactionCustomPatterns = patterns }
+        customPatternsText = patterns.joined(separator: "\n")
+    }
+
+    private func saveExcludedApps() {
+        let bundleIds = excludedAppsText
+            .split(separator: ",")
+            .map { $0.trimmingCharacters(in: .whitespaces) }
+            .filter { !$0.isEmpty }
+        Logger.shared.debug("Settings: Context capture excluded apps set to \(bundleIds)")
+        SettingsStore.shared.update { $0.contextCaptureExcludedApps = bundleIds }
+        excludedAppsText = bundleIds.joined(separator: ", ")
+    }
 }
@@ -1,18 +1,55 @@
 import SwiftUI
 
-/// Recording Setup section: global shortcut, dictation language, and microphone selection.
+/// Recording Setup section: global shortcut, hotkey backend, dictation language, microphone
+/// selection, the overlay's live transcription preview and the maximum recording length.
 struct RecordingSetupSection: View {
     @Bindable var microphoneService: MicrophoneService
 
     @AppStorage(UserDefaults.customShortcutKeyCodeKey) private var customShortcutKeyCode: Int = UserDefaults.defaultShortcutKeyCode
     @AppStorage(UserDefaults.customShortcutModifiersKey) private var customShortcutModifiersRaw: Double = Double(UserDefaults.defaultShortcutModifiers)
     @AppStorage("dictationLanguage") private var dictationLanguage: String = "Auto-Detect"
+    @AppStorage(UserDefaults.hotkeyBackendKey) private var hotkeyBackend: String = HotkeyBackend.eventTap.rawValue
+    @AppStorage("liveTranscriptionPreview") private var liveTranscriptionPreview: Bool = false
+    @AppStorage("maxRecordingSeconds") private var maxRecordingSeconds: Int = 0
+    @AppStorage("quitWaitsForTranscription") private var quitWaitsForTranscription: Bool = true
+    @AppStorage("quitWaitSeconds") private var quitWaitSeconds: Int = 20
+    @AppStorage("processingTimeoutSeconds") private var processingTimeoutSeconds: Int = 90
+    @AppStorage("idleReductionEnabled") private var idleReductionEnabled: Bool = false
+    @AppStorage("idleReductionMinutes") private var idleReductionMinutes: Int = 15
+    @AppStorage("idleReductionUnloadsModel") private var idleReductionUnloadsModel: Bool = false
+    /// Why the last recorded shortcut was refused; cleared by the next valid one.
+    @State private var rejectedShortcutIssues: [SettingsValidationIssue] = []
 
     private var currentShortcutDisplay: String {
         let flags = CGEventFlags(rawValue: UInt64(customShortcutModifiersRaw))
         return ShortcutDisplayHelper.displayString(keyCode: CGKeyCode(customShortcutKeyCode), flags: flags)
     }
 
+    /// The stored shortcut and language checked the way `SettingsUpdater` checks them,
+    /// plus the reason the last recorded shortcut was refused.
+    private var issues: [SettingsValidationIssue] {
+        var settings = SettingsStore.shared.settings
+        settings.shortcutKeyCode = customShortcutKeyCode
+        settings.shortcutModifiers = UInt64(customShortcutModifiersRaw)
+        settings.dictationLanguage = dictationLanguage
+        return rejectedShortcutIssues + SettingsValidator.validate(settings)
+    }
+
+    /// Saves a recorded shortcut only if the validator accepts it.
+    private func recordShortcut(keyCode: CGKeyCode, modifiers: CGEventFlags) {
+        var settings = SettingsStore.shared.settings
+        settings.shortcutKeyCode = Int(keyCode)
+        settings.shortcutModifiers = modifiers.rawValue
+        rejectedShortcutIssues = SettingsValidator.validate(settings)
+            .filter { [AppSettings.Key.shortcutKeyCode.rawValue, AppSettings.Key.shortcutModifiers.rawValue].contains($0.field) }
+        guard rejectedShortcutIssues.isEmpty else {
+            Logger.shared.info("Settings: Refused shortcut keyCode=\(keyCode) modifiers=\(modifiers.rawValue) — \(rejectedShortcutIssues[0].message)")
+            return
+        }
+        customShortcutKeyCode = Int(keyCode)
+        customShortcutModifiersRaw = Double(modifiers.rawValue)
+    }
+
     var body: some View {
         VStack(alignment: .leading, spacing: 16) {
             Label {
@@ -34,17 +71,18 @@ struct RecordingSetupSection: View {
                         Text("Click the shortcut to record a new one")
                             .font(.system(size: 12))
                             .foregroundStyle(Theme.textMuted)
+                        SettingsIssueText(issues: issues, fields: [.shortcutKeyCode, .shortcutModifiers])
                     }
                     Spacer()
                     ShortcutRecorderButton(
                         displayLabel: currentShortcutDisplay,
                         onShortcutRecorded: { keyCode, modifiers in
                             Logger.shared.debug("Settings: Recorded new shortcut keyCode=\(keyCode) modifiers=\(modifiers.rawValue)")
-                            customShortcutKeyCode = Int(keyCode)
-                            customShortcutModifiersRaw = Double(modifiers.rawValue)
+                            recordShortcut(keyCode: keyCode, modifiers: modifiers)
                         },
                         onReset: {
                             Logger.shared.debug("Settings: Reset shortcut to default")
+                            rejectedShortcutIssues = []
                             customShortcutKeyCode = UserDefaults.defaultShortcutKeyCode
                             customShortcutModifiersRaw = Double(UserDefaults.defaultShortcutModifiers)
                         }
@@ -54,6 +92,53 @@ struct RecordingSetupSection: View {
 
                 Divider().background(Theme.textMuted.opacity(0.1))
 
+                // Hotkey Backend
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Shortcut Detection")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text(hotkeyBackend == HotkeyBackend.nsEvent.rawValue
+                             ? "Shortcut is also passed through to the focused app"
+                             : "Shortcut is captured before reaching other apps")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Menu {
+                        ForEach(HotkeyBackend.allCases) { backend in
+                            Button(backend.displayName) {
+                                Logger.shared.debug("Settings: Changed Hotkey Backend from '\(hotkeyBackend)' to '\(backend.rawValue)'")
+                                hotkeyBackend = backend.rawValue
+                            }
+                        }
+                    } label: {
+                        HStack {
+                            Text((HotkeyBackend(rawValue: hotkeyBackend) ?? .eventTap).displayName)
+                                .font(.system(size: 13))
+                                .foregroundStyle(Theme.navy)
+                            Spacer()
+                            Image(systemName: "chevron.down")
+                                .font(.system(size: 10, weight: .bold))
+                                .foregroundStyle(Theme.textMuted)
+                        }
+                        .padding(.horizontal, 12)
+                        .padding(.vertical, 8)
+                        .background(Theme.background)
+                        .clipShape(RoundedRectangle(cornerRadius: 8))
+                        .overlay(
+                            RoundedRectangle(cornerRadius: 8)
+                                .stroke(Theme.accent.opacity(0.4), lineWidth: 1)
+                        )
+                        .contentShape(Rectangle())
+                    }
+                    .buttonStyle(.plain)
+                    .frame(width: 160)
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
                 // Dictation Language
                 HStack {
                     VStack(alignment: .leading, spacing: 2) {
@@ -63,6 +148,7 @@ struct RecordingSetupSection: View {
                         Text(dictationLanguage == "Auto-Detect" ? "Whisper detects language automatically" : "Primary language for transcription")
                             .font(.system(size: 12))
                             .foregroundStyle(Theme.textMuted)
+                        SettingsIssueText(issues: issues, fields: [.dictationLanguage])
                     }
                     Spacer()
                     Menu {
@@ -163,6 +249,138 @@ struct RecordingSetupSection: View {
                     .frame(width: 160)
                 }
                 .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Live Preview
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Live Preview")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Show a rough transcript under the recording overlay as you speak. Uses more CPU.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $liveTranscriptionPreview.logged(name: "Live Preview"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Maximum Recording Length
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Maximum Recording Length")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Optionally stop recording by itself after this long. The overlay counts down the last \(AppStateManager.recordingWarningSeconds) seconds.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Stepper(value: $maxRecordingSeconds,
+                            in: 0...AppStateManager.maxRecordingSecondsRange.upperBound, step: 30) {
+                        Text(maxRecordingSeconds == 0 ? "No limit" : AppStateManager.clockString(maxRecordingSeconds))
+                            .monospacedDigit()
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Finish Dictation Before Quitting
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Finish Dictation Before Quitting")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("When you quit mid-dictation, wait for the text to be pasted. After the time limit you're asked whether to quit anyway.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $quitWaitsForTranscription.logged(name: "Finish Dictation Before Quitting"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                    Stepper(value: $quitWaitSeconds, in: QuitDrainer.timeoutRange, step: 5) {
+                        Text("\(quitWaitSeconds)s")
+                            .monospacedDigit()
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    .disabled(!quitWaitsForTranscription)
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Cancel Stuck Transcriptions
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Cancel Stuck Transcriptions")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("If processing makes no progress for this long, cancel it and reload the model. Long recordings get extra time, and an unfinished recording is saved so you can replay it.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Stepper(value: $processingTimeoutSeconds, in: 0...ProcessingWatchdog.timeoutRange.upperBound, step: 30) {
+                        Text(processingTimeoutSeconds == 0 ? "Off" : "\(processingTimeoutSeconds)s")
+                            .monospacedDigit()
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Reduce Background Work When Idle
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Reduce Background Work When Idle")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("After this long without dictation, stop background checks to save battery. Everything resumes with your next dictation.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $idleReductionEnabled.logged(name: "Reduce Background Work When Idle"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                    Stepper(value: $idleReductionMinutes, in: IdleMonitor.minutesRange, step: 5) {
+                        Text("\(idleReductionMinutes) min")
+                            .monospacedDigit()
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    .disabled(!idleReductionEnabled)
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Unload Models When Idle
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Unload Models When Idle")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Also free the memory used by the models. The first dictation afterwards waits for the model to load again.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $idleReductionUnloadsModel.logged(name: "Unload Models When Idle"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                        .disabled(!idleReductionEnabled)
+                }
+                .padding(16)
             }
             .background(Color.white)
             .clipShape(.rect(cornerRadius: 12))
@@ -0,0 +1,149 @@
+import SwiftUI
+
+/// Backups section: restore an archived settings snapshot or reset everything to the
+/// factory defaults.
+///
+/// Both go through `SettingsAPI`, so the settings being replaced are archived first and
+/// the result is validated and applied like any other change. A backup that would not
+/// apply (say, one naming a model deleted since) is refused before anything is archived.
+struct SettingsBackupSection: View {
+    let settingsAPI: SettingsAPI
+
+    @State private var backups: [SettingsBackup] = []
+    @State private var isConfirmingReset = false
+    @State private var statusMessage: String?
+    @State private var isStatusError = false
+
+    private static let dateFormatter: DateFormatter = {
+        let formatter = DateFormatter()
+        formatter.dateStyle = .medium
+        formatter.timeStyle = .short
+        return formatter
+    }()
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 16) {
+            Label {
+                Text("Backups")
+                    .font(.system(size: 18, weight: .bold))
+                    .foregroundStyle(Theme.navy)
+            } icon: {
+                Image(systemName: "clock.arrow.circlepath")
+                    .foregroundStyle(Theme.navy)
+            }
+
+            VStack(spacing: 0) {
+                // Restore
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Restore Settings")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Go back to settings saved before a reset or restore. The current settings are backed up first, so this can be undone too.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Menu("Restore") {
+                        if backups.isEmpty {
+                            Text("No backups yet")
+                        }
+                        ForEach(backups) { backup in
+                            Button(Self.dateFormatter.string(from: backup.createdAt)) { restore(backup) }
+                        }
+                    }
+                    .fixedSize()
+                    .disabled(backups.isEmpty)
+                }
+                .padding(16)
+
+                Divider()
+
+                // Reset
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Reset to Defaults")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Put every setting back to how VocaGlyph ships. History, models and word replacements are kept.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                            .fixedSize(horizontal: false, vertical: true)
+                    }
+                    Spacer()
+                    Button("Reset…") {
+                        Logger.shared.debug("Settings: Clicked Reset to Defaults")
+                        isConfirmingReset = true
+                    }
+                    .buttonStyle(.plain)
+                    .font(.system(size: 13, weight: .medium))
+                    .foregroundStyle(Theme.accent)
+                    .padding(.horizontal, 12)
+                    .padding(.vertical, 6)
+                    .background(Theme.accent.opacity(0.1))
+                    .clipShape(RoundedRectangle(cornerRadius: 6))
+                }
+                .padding(16)
+
+                if let statusMessage {
+                    Divider()
+
+                    Text(statusMessage)
+                        .font(.system(size: 12))
+                        .foregroundStyle(isStatusError ? .red : Theme.textMuted)
+                        .fixedSize(horizontal: false, vertical: true)
+                        .frame(maxWidth: .infinity, alignment: .leading)
+                        .padding(16)
+                }
+            }
+            .background(Color.white)
+            .clipShape(.rect(cornerRadius: 12))
+            .overlay(
+                RoundedRectangle(cornerRadius: 12)
+                    .stroke(Theme.textMuted.opacity(0.2), lineWidth: 1)
+            )
+        }
+        .onAppear { backups = settingsAPI.listBackups() }
+        .alert("Reset all settings?", isPresented: $isConfirmingReset) {
+            Button("Reset", role: .destructive) { reset() }
+            Button("Cancel", role: .cancel) {}
+        } message: {
+            Text("Your current settings are backed up first and can be restored from this section.")
+        }
+    }
+
+    private func reset() {
+        do {
+            let result = try settingsAPI.reset()
+            report(result, success: "Settings reset to defaults.")
+        } catch {
+            showStatus("Settings were not reset — \(error.localizedDescription)", isError: true)
+        }
+        backups = settingsAPI.listBackups()
+    }
+
+    private func restore(_ backup: SettingsBackup) {
+        Logger.shared.debug("Settings: Restoring \(backup.id)")
+        do {
+            let result = try settingsAPI.restoreBackup(backup)
+            report(result, success: "Restored settings from \(Self.dateFormatter.string(from: backup.createdAt)).")
+        } catch {
+            showStatus(error.localizedDescription, isError: true)
+        }
+        backups = settingsAPI.listBackups()
+    }
+
+    private func report(_ result: SettingsUpdateResult, success: String) {
+        if let issue = result.issues.first {
+            showStatus(issue.message, isError: true)
+        } else {
+            showStatus(success, isError: false)
+        }
+    }
+
+    private func showStatus(_ message: String, isError: Bool) {
+        statusMessage = message
+        isStatusError = isError
+    }
+}
@@ -1,8 +1,16 @@
 import SwiftUI
 
-/// System Integration section: Launch at Login toggle.
+/// System Integration section: Launch at Login, the Dock icon, keeping windows on top,
+/// notifications, automatic update checks, opt-in crash reporting and the menu bar icon.
 struct SystemIntegrationSection: View {
     @State private var loginManager = LaunchAtLoginManager()
+    @AppStorage("showDockIcon") private var showDockIcon: Bool = false
+    @AppStorage("alwaysOnTop") private var alwaysOnTop: Bool = false
+    @AppStorage("systemNotificationsEnabled") private var systemNotificationsEnabled: Bool = true
+    @AppStorage("automaticUpdateChecks") private var automaticUpdateChecks: Bool = true
+    @AppStorage("crashReportingEnabled") private var crashReportingEnabled: Bool = false
+    @AppStorage("trayIconTheme") private var trayIconTheme: String = TrayIconTheme.standard.rawValue
+    @AppStorage("trayIconFlashesWhileRecording") private var trayIconFlashesWhileRecording: Bool = false
 
     var body: some View {
         VStack(alignment: .leading, spacing: 16) {
@@ -25,8 +33,18 @@ struct SystemIntegrationSection: View {
                         Text("Automatically start VocaGlyph when you log into macOS")
                             .font(.system(size: 12))
                             .foregroundStyle(Theme.textMuted)
+                        if loginManager.requiresApproval {
+                            Text("Turned off in System Settings → General → Login Items.")
+                                .font(.system(size: 12))
+                                .foregroundStyle(.orange)
+                        }
                     }
                     Spacer()
+                    if loginManager.requiresApproval {
+                        Button("Open Login Items") {
+                            loginManager.openLoginItemsSettings()
+                        }
+                    }
                     Toggle("", isOn: Binding(
                         get: { loginManager.isEnabled },
                         set: { loginManager.setEnabled($0) }
@@ -35,6 +53,143 @@ struct SystemIntegrationSection: View {
                     .toggleStyle(.switch)
                 }
                 .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Dock Icon
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Show in Dock")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Keep VocaGlyph in the Dock and Cmd-Tab, with a badge while recording or processing.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $showDockIcon.logged(name: "Show in Dock"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Always on Top
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Keep Windows on Top")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Float Settings and the recording overlay above other apps, including full-screen ones. Handy while setting up or troubleshooting.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $alwaysOnTop.logged(name: "Keep Windows on Top"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Notifications
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Notifications")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Notify when text is only copied to the clipboard, the shortcut can't be registered, a model finishes downloading or transcription fails.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $systemNotificationsEnabled.logged(name: "Notifications"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Automatic Update Checks
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Check for Updates Automatically")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Look for new versions in the background. Updates are verified and only installed when you agree.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $automaticUpdateChecks.logged(name: "Automatic Update Checks"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Crash Reports
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Crash Reports")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Save a report if VocaGlyph crashes. You're asked before anything is sent.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $crashReportingEnabled.logged(name: "Crash Reports"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Menu Bar Icon
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Menu Bar Icon")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("High Contrast gives every state its own shape and colorblind-safe colors. Monochrome matches the system icons.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Picker("", selection: $trayIconTheme.logged(name: "Menu Bar Icon")) {
+                        ForEach(TrayIconTheme.allCases, id: \.self) { theme in
+                            Text(theme.title).tag(theme.rawValue)
+                        }
+                    }
+                    .labelsHidden()
+                    .fixedSize()
+                }
+                .padding(16)
+
+                Divider().background(Theme.textMuted.opacity(0.1))
+
+                // Flash While Recording
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Flash Icon While Recording")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Blink the menu bar icon so a running recording is hard to miss. Off when Reduce Motion is on.")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $trayIconFlashesWhileRecording.logged(name: "Flash Icon While Recording"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
             }
             .background(Color.white)
             .clipShape(.rect(cornerRadius: 12))
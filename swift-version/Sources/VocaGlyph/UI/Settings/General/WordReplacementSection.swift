@@ -22,16 +22,25 @@ struct WordReplacementSection: View {
 
     @State private var viewModel: WordReplacementViewModel?
 
+    @AppStorage("wordReplacementsEnabled") private var wordReplacementsEnabled: Bool = true
+
     var body: some View {
         VStack(alignment: .leading, spacing: 16) {
             // ── Header ────────────────────────────────────────────────────────
-            Label {
-                Text("Word Replacements")
-                    .font(.system(size: 18, weight: .bold))
-                    .foregroundStyle(Theme.navy)
-            } icon: {
-                Image(systemName: "arrow.left.arrow.right")
-                    .foregroundStyle(Theme.navy)
+            HStack {
+                Label {
+                    Text("Word Replacements")
+                        .font(.system(size: 18, weight: .bold))
+                        .foregroundStyle(Theme.navy)
+                } icon: {
+                    Image(systemName: "arrow.left.arrow.right")
+                        .foregroundStyle(Theme.navy)
+                }
+                Spacer()
+                Toggle("", isOn: $wordReplacementsEnabled.logged(name: "Word Replacements"))
+                    .labelsHidden()
+                    .toggleStyle(.switch)
+                    .help("Apply these replacements to every dictation")
             }
 
             // ── Card Body ─────────────────────────────────────────────────────
@@ -12,10 +12,13 @@ struct HistoryMenuState: Identifiable {
 struct HistorySettingsView: View {
     @Environment(\.modelContext) private var modelContext
     @Query(sort: \TranscriptionItem.timestamp, order: .reverse) private var items: [TranscriptionItem]
+    @Query private var snippets: [Snippet]
     @State private var searchText = ""
     @State private var activeMenu: HistoryMenuState? = nil
     @State private var itemToDelete: TranscriptionItem? = nil
     @State private var showClearAllConfirmation = false
+    @State private var itemToPin: TranscriptionItem? = nil
+    @State private var snippetName = ""
     @State private var isSearchExpanded = false
     @FocusState private var isSearchFocused: Bool
 
@@ -245,9 +248,19 @@ struct HistorySettingsView: View {
                 HistoryActionMenu(
                     item: menu.item,
                     buttonFrame: menu.buttonFrame,
+                    isPinned: pinnedSnippet(for: menu.item) != nil,
                     onRetranscribe: {
                         activeMenu = nil
                     },
+                    onTogglePin: {
+                        activeMenu = nil
+                        if let snippet = pinnedSnippet(for: menu.item) {
+                            SnippetService(container: modelContext.container).delete(snippet.id)
+                        } else {
+                            snippetName = ""
+                            itemToPin = menu.item
+                        }
+                    },
                     onShare: {
                         activeMenu = nil
                         let sharingPicker = NSSharingServicePicker(items: [menu.item.text])
@@ -325,6 +338,23 @@ struct HistorySettingsView: View {
             }
         }
         .coordinateSpace(name: "historyView")
+        .alert("Pin as Snippet", isPresented: Binding(
+            get: { itemToPin != nil },
+            set: { if !$0 { itemToPin = nil } }
+        )) {
+            TextField("Name", text: $snippetName)
+            Button("Pin") {
+                if let item = itemToPin {
+                    let trimmed = snippetName.trimmingCharacters(in: .whitespacesAndNewlines)
+                    SnippetService(container: modelContext.container)
+                        .pin(item, name: trimmed.isEmpty ? AppDelegate.menuTitle(for: item.text) : trimmed)
+                }
+                itemToPin = nil
+            }
+            Button("Cancel", role: .cancel) { itemToPin = nil }
+        } message: {
+            Text("Snippets are listed under Snippets in the menu bar, ready to paste.")
+        }
         .animation(.easeInOut(duration: 0.2), value: itemToDelete != nil)
         .animation(.easeInOut(duration: 0.2), value: showClearAllConfirmation)
     }
@@ -335,6 +365,10 @@ struct HistorySettingsView: View {
         pasteboard.setString(text, forType: .string)
     }
 
+    private func pinnedSnippet(for item: TranscriptionItem) -> Snippet? {
+        snippets.first { $0.sourceItemID == item.id }
+    }
+
     private func deleteItem(_ item: TranscriptionItem) {
         modelContext.delete(item)
         try? modelContext.save()
@@ -352,11 +386,13 @@ struct HistorySettingsView: View {
 struct HistoryActionMenu: View {
     let item: TranscriptionItem
     let buttonFrame: CGRect
+    let isPinned: Bool
     let onRetranscribe: () -> Void
+    let onTogglePin: () -> Void
     let onShare: () -> Void
     let onDelete: () -> Void
 
-    private let cardHeight: CGFloat = 148
+    private let cardHeight: CGFloat = 196
     private let cardWidth: CGFloat = 200
     private let gap: CGFloat = 6
 
@@ -382,6 +418,13 @@ struct HistoryActionMenu: View {
                     action: onRetranscribe
                 )
                 Divider().padding(.horizontal, 12)
+                menuRow(
+                    icon: isPinned ? "pin.slash" : "pin",
+                    label: isPinned ? "Unpin snippet" : "Pin as snippet",
+                    color: Theme.navy,
+                    action: onTogglePin
+                )
+                Divider().padding(.horizontal, 12)
                 menuRow(
                     icon: "square.and.arrow.up",
                     label: "Share",
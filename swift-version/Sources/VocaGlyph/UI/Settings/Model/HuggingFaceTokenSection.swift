@@ -0,0 +1,64 @@
+import SwiftUI
+
+/// HuggingFace access token field for gated or private custom model repos.
+/// The token lives in the Keychain and is only sent to huggingface.co.
+struct HuggingFaceTokenSection: View {
+    @ObservedObject var viewModel: SettingsViewModel
+    @State private var token: String = ""
+
+    private var isSaved: Bool {
+        viewModel.savedSecrets.contains(.huggingFaceToken)
+    }
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 4) {
+            Text("HuggingFace Token")
+                .font(.system(size: 11, weight: .medium))
+                .foregroundStyle(Theme.navy)
+
+            HStack(spacing: 8) {
+                SecureField(isSaved ? "hf_... (Saved in Keychain)" : "hf_...", text: $token)
+                    .textFieldStyle(.roundedBorder)
+                    .font(.system(size: 13, design: .monospaced))
+
+                Button(action: {
+                    if let str = NSPasteboard.general.string(forType: .string) {
+                        token = str
+                    }
+                }) {
+                    Image(systemName: "doc.on.clipboard")
+                        .font(.system(size: 12))
+                }
+                .buttonStyle(.plain)
+                .help("Paste from clipboard")
+
+                if isSaved {
+                    Button(action: { Task { @MainActor in await viewModel.clearSecret(.huggingFaceToken) } }) {
+                        Text("Delete").font(.system(size: 12, weight: .medium))
+                    }
+                    .buttonStyle(.bordered)
+                    .tint(.red)
+
+                    Image(systemName: "checkmark.seal.fill")
+                        .foregroundStyle(.green)
+                        .help("Token is securely stored in Keychain")
+                } else {
+                    Button(action: {
+                        Task { @MainActor in
+                            await viewModel.saveSecret(token.trimmingCharacters(in: .whitespacesAndNewlines), for: .huggingFaceToken)
+                            token = ""
+                        }
+                    }) {
+                        Text("Save Securely").font(.system(size: 12, weight: .medium))
+                    }
+                    .buttonStyle(.borderedProminent)
+                    .disabled(token.isEmpty)
+                }
+            }
+
+            Text("Needed for gated or private repos. Accept the model's terms on huggingface.co first.")
+                .font(.system(size: 11))
+                .foregroundStyle(Theme.textMuted)
+        }
+    }
+}
@@ -18,6 +18,19 @@ struct ModelCardView: View {
     var isDownloadInProgress: Bool = false
     /// Optional speed/recommendation badge shown inline in the title row.
     var recommendationBadge: String? = nil
+    /// Progress a paused download stopped at. Shows a Resume button instead of Download.
+    var pausedProgress: Float? = nil
+    /// Position in the download queue while waiting for another download to finish.
+    var queuePosition: Int? = nil
+    /// When set, a pause button is shown next to the download percentage.
+    var onPauseDownload: (() -> Void)? = nil
+    /// When set, a cancel button is shown while downloading or paused.
+    var onCancelDownload: (() -> Void)? = nil
+    /// Downloaded files failed verification. Shows a Repair button instead of Use Model.
+    var isCorrupt: Bool = false
+    var onRepair: (() -> Void)? = nil
+    /// Label of the button that runs `onDownload` for a model that isn't on disk yet.
+    var downloadTitle: String = "Download"
     let onSelect: () -> Void
     let onUse: () -> Void
     let onDownload: () -> Void
@@ -109,7 +122,7 @@ struct ModelCardView: View {
             .frame(maxWidth: .infinity, alignment: .leading)
 
             // ── Trailing: action buttons — vertically centered ────────
-            if !isActive {
+            if !isActive || isCorrupt {
                 actionButtons
                     .padding(.trailing, 14)
             }
@@ -162,6 +175,8 @@ struct ModelCardView: View {
     private var actionButtons: some View {
         if isDownloadInProgress {
             downloadInProgressView
+        } else if isCorrupt, downloadProgress == nil, let onRepair {
+            repairButton(onRepair)
         } else if !isDownloaded {
             downloadingOrDownloadButton
         } else {
@@ -186,34 +201,102 @@ struct ModelCardView: View {
 
     @ViewBuilder
     private var downloadingOrDownloadButton: some View {
-        if let progress = downloadProgress {
-            HStack(spacing: 6) {
-                if #available(macOS 14.0, *) {
-                    Image(systemName: "arrow.down.circle")
-                        .foregroundStyle(Theme.accent)
-                        .symbolEffect(.pulse, options: .repeating)
-                } else {
-                    Image(systemName: "arrow.down.circle")
+        HStack(spacing: 6) {
+            if let progress = downloadProgress {
+                HStack(spacing: 6) {
+                    if #available(macOS 14.0, *) {
+                        Image(systemName: "arrow.down.circle")
+                            .foregroundStyle(Theme.accent)
+                            .symbolEffect(.pulse, options: .repeating)
+                    } else {
+                        Image(systemName: "arrow.down.circle")
+                            .foregroundStyle(Theme.accent)
+                    }
+                    Text("\(Int(progress * 100))%")
+                        .font(.system(size: 12, weight: .bold))
                         .foregroundStyle(Theme.accent)
+                        .contentTransition(.numericText())
                 }
-                Text("\(Int(progress * 100))%")
-                    .font(.system(size: 12, weight: .bold))
-                    .foregroundStyle(Theme.accent)
-                    .contentTransition(.numericText())
+                .padding(.vertical, 4).padding(.horizontal, 8)
+                .background(Theme.accent.opacity(0.1))
+                .clipShape(.rect(cornerRadius: 6))
+
+                if let onPauseDownload {
+                    Button(action: {
+                        Logger.shared.debug("Settings: Clicked Pause for \(title)")
+                        onPauseDownload()
+                    }) {
+                        Image(systemName: "pause.fill")
+                            .font(.system(size: 11, weight: .semibold))
+                    }
+                    .buttonStyle(.bordered)
+                    .help("Pause download")
+                }
+                cancelDownloadButton
+            } else if let position = queuePosition {
+                HStack(spacing: 6) {
+                    Image(systemName: "clock")
+                    Text("Queued #\(position)")
+                        .font(.system(size: 12, weight: .bold))
+                }
+                .foregroundStyle(Theme.accent)
+                .padding(.vertical, 4).padding(.horizontal, 8)
+                .background(Theme.accent.opacity(0.1))
+                .clipShape(.rect(cornerRadius: 6))
+                .help("Starts when the download ahead of it finishes")
+                cancelDownloadButton
+            } else if let paused = pausedProgress {
+                Button(action: {
+                    Logger.shared.debug("Settings: Clicked Resume for \(title)")
+                    onDownload()
+                }) {
+                    Label("Resume \(Int(paused * 100))%", systemImage: "play.circle")
+                        .font(.system(size: 11, weight: .semibold))
+                }
+                .buttonStyle(.bordered)
+                .tint(Theme.accent)
+                cancelDownloadButton
+            } else {
+                Button(action: {
+                    Logger.shared.debug("Settings: Clicked \(downloadTitle) for \(title)")
+                    onDownload()
+                }) {
+                    Label(downloadTitle, systemImage: "arrow.down.circle")
+                        .font(.system(size: 11, weight: .semibold))
+                }
+                .buttonStyle(.bordered)
+                .tint(Theme.accent)
             }
-            .padding(.vertical, 4).padding(.horizontal, 8)
-            .background(Theme.accent.opacity(0.1))
-            .clipShape(.rect(cornerRadius: 6))
-        } else {
+        }
+    }
+
+    @ViewBuilder
+    private func repairButton(_ onRepair: @escaping () -> Void) -> some View {
+        Button(action: {
+            Logger.shared.debug("Settings: Clicked Repair for \(title)")
+            onRepair()
+        }) {
+            Label("Repair", systemImage: "exclamationmark.arrow.triangle.2.circlepath")
+                .font(.system(size: 11, weight: .semibold))
+        }
+        .buttonStyle(.bordered)
+        .tint(Color.orange)
+        .help("Some model files are damaged. Download them again.")
+    }
+
+    @ViewBuilder
+    private var cancelDownloadButton: some View {
+        if let onCancelDownload {
             Button(action: {
-                Logger.shared.debug("Settings: Clicked Download for \(title)")
-                onDownload()
+                Logger.shared.debug("Settings: Clicked Cancel Download for \(title)")
+                onCancelDownload()
             }) {
-                Label("Download", systemImage: "arrow.down.circle")
+                Image(systemName: "xmark")
                     .font(.system(size: 11, weight: .semibold))
             }
             .buttonStyle(.bordered)
-            .tint(Theme.accent)
+            .tint(Color.red)
+            .help("Cancel download and delete partial files")
         }
     }
 
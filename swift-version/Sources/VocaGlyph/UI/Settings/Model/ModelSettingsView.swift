@@ -6,12 +6,27 @@ struct ModelSettingsView: View {
     @ObservedObject var whisper: WhisperService
     @ObservedObject var parakeet: ParakeetService
     @ObservedObject var stateManager: AppStateManager
+    @ObservedObject var viewModel: SettingsViewModel
     @AppStorage("selectedModel") private var selectedModel: String = "apple-native"
+    @AppStorage("downloadRateLimitMBps") private var downloadRateLimitMBps: Int = 0
+    @AppStorage("whisperCppModelPath") private var whisperCppModelPath: String = ""
     @State private var focusedModel: String = "apple-native"
 
+    @ObservedObject private var registry = ModelRegistry.shared
+    @State private var importError: String? = nil
+
     @State private var modelToDeleteTitle: String? = nil
     @State private var modelDeleteAction: (() -> Void)? = nil
 
+    /// The selected model checked the way `SettingsUpdater` checks it, so one that was
+    /// deleted or never downloaded is explained instead of silently not loading.
+    private var issues: [SettingsValidationIssue] {
+        var settings = SettingsStore.shared.settings
+        settings.selectedModel = selectedModel
+        settings.whisperCppModelPath = whisperCppModelPath
+        return SettingsValidator.validate(settings, downloadedModels: whisper.downloadedModels.union(parakeet.downloadedModels))
+    }
+
     var body: some View {
         ZStack {
             VStack(alignment: .leading, spacing: 0) {
@@ -23,6 +38,15 @@ struct ModelSettingsView: View {
                     Text("Choose the transcription engine that powers your dictation")
                         .font(.system(size: 14))
                         .foregroundStyle(Theme.textMuted)
+                    HStack(spacing: 16) {
+                        Label("Downloaded models use \(DiskSpace.format(whisper.modelsDiskUsage + parakeet.modelsDiskUsage))",
+                              systemImage: "internaldrive")
+                        downloadRateLimitPicker
+                    }
+                    .font(.system(size: 11))
+                    .foregroundStyle(Theme.textMuted)
+                    .padding(.top, 2)
+                    SettingsIssueText(issues: issues, fields: [.selectedModel, .whisperCppModelPath])
                 }
                 .padding(.horizontal, 40)
                 .padding(.top, 40)
@@ -105,6 +129,10 @@ struct ModelSettingsView: View {
                             // Card container
                             VStack(spacing: 0) {
                                 appleNativeCard
+                                Divider()
+                                    .background(Theme.textMuted.opacity(0.15))
+                                    .padding(.horizontal, 12)
+                                whisperCppCard
                                 Divider()
                                     .background(Theme.textMuted.opacity(0.15))
                                     .padding(.horizontal, 12)
@@ -152,10 +180,21 @@ struct ModelSettingsView: View {
                             )
                             .shadow(color: Color.black.opacity(0.05), radius: 8, x: 0, y: 2)
                         }
+
+                        // MARK: Custom Section
+                        customModelsSection
+
+                        // MARK: Resources Section
+                        ResourcesSection(stateManager: stateManager)
                     }
                     .padding(.trailing, 8)
                     .padding(.bottom, 20)
-                    .onAppear { focusedModel = selectedModel }
+                    .onAppear {
+                        focusedModel = selectedModel
+                        // Pick up hand edits to models.json.
+                        registry.reload()
+                        whisper.checkDownloadedModels()
+                    }
                 }
                 .padding(.horizontal, 40)
                 .padding(.top, 24)
@@ -199,6 +238,85 @@ struct ModelSettingsView: View {
         .animation(.easeInOut(duration: 0.2), value: modelToDeleteTitle != nil)
     }
 
+    // MARK: - Custom Models
+
+    @ViewBuilder
+    private var customModelsSection: some View {
+        VStack(alignment: .leading, spacing: 10) {
+            HStack(alignment: .top) {
+                VStack(alignment: .leading, spacing: 2) {
+                    Label {
+                        Text("Custom")
+                            .font(.system(size: 18, weight: .bold))
+                            .foregroundStyle(Theme.navy)
+                    } icon: {
+                        Image(systemName: "shippingbox")
+                            .foregroundStyle(Theme.navy)
+                    }
+                    Text("Fine-tuned WhisperKit CoreML models from models.json or imported from disk")
+                        .font(.system(size: 13))
+                        .italic()
+                        .foregroundStyle(Theme.textMuted)
+                        .padding(.top, 4)
+                }
+                Spacer()
+                Button(action: importModel) {
+                    Label("Import Model…", systemImage: "square.and.arrow.down")
+                        .font(.system(size: 11, weight: .semibold))
+                }
+                .buttonStyle(.bordered)
+            }
+
+            if let importError {
+                Text(importError)
+                    .font(.system(size: 11))
+                    .foregroundStyle(.red)
+            }
+
+            HuggingFaceTokenSection(viewModel: viewModel)
+
+            if !registry.customModels.isEmpty {
+                VStack(spacing: 0) {
+                    ForEach(Array(registry.customModels.enumerated()), id: \.element.id) { index, model in
+                        if index > 0 {
+                            Divider()
+                                .background(Theme.textMuted.opacity(0.15))
+                                .padding(.horizontal, 12)
+                        }
+                        whisperCard(id: model.id, title: model.title,
+                                    description: model.description ?? (model.repo.map { "From \($0)" } ?? ""),
+                                    size: "")
+                    }
+                }
+                .background(Color.white)
+                .clipShape(RoundedRectangle(cornerRadius: 12))
+                .overlay(
+                    RoundedRectangle(cornerRadius: 12)
+                        .stroke(Theme.textMuted.opacity(0.2), lineWidth: 1)
+                )
+                .shadow(color: Color.black.opacity(0.05), radius: 8, x: 0, y: 2)
+            }
+        }
+    }
+
+    private func importModel() {
+        let panel = NSOpenPanel()
+        panel.canChooseDirectories = true
+        panel.canChooseFiles = true   // so a picked ggml file gets an explanation, not silence
+        panel.allowsMultipleSelection = false
+        panel.prompt = "Import"
+        panel.message = "Choose a WhisperKit model folder (it contains AudioEncoder.mlmodelc)."
+        guard panel.runModal() == .OK, let url = panel.url else { return }
+        do {
+            try registry.importModel(from: url)
+            importError = nil
+            whisper.checkDownloadedModels()
+        } catch {
+            Logger.shared.error("Settings: Model import failed — \(error.localizedDescription)")
+            importError = error.localizedDescription
+        }
+    }
+
     // MARK: - Card Builders
 
     @ViewBuilder
@@ -224,6 +342,68 @@ struct ModelSettingsView: View {
         }
     }
 
+    /// A ggml model run by the user's whisper.cpp install. Nothing is downloaded: the
+    /// button picks the model file, and the card explains when whisper.cpp is missing.
+    @ViewBuilder
+    private var whisperCppCard: some View {
+        let id = WhisperCppEngine.modelID
+        let isInstalled = WhisperCppRuntime.locate() != nil
+        let hasModel = FileManager.default.fileExists(atPath: whisperCppModelPath)
+        ModelCardView(
+            title: "whisper.cpp (ggml model)",
+            description: !isInstalled
+                ? WhisperCppError.runtimeUnavailable.localizedDescription
+                : hasModel
+                    ? "Runs \(URL(fileURLWithPath: whisperCppModelPath).lastPathComponent) with your whisper.cpp install, on-device."
+                    : "Runs a ggml Whisper model with your whisper.cpp install, on-device. Choose the model file to use.",
+            size: (try? FileManager.default.attributesOfItem(atPath: whisperCppModelPath)[.size] as? Int64)
+                .map { DiskSpace.format($0) } ?? "—",
+            isSelected: focusedModel == id,
+            isDownloaded: isInstalled && hasModel,
+            isActive: selectedModel == id,
+            isLoading: false,
+            downloadProgress: nil,
+            downloadTitle: "Choose Model…",
+            onSelect: { focusedModel = id },
+            onUse: {
+                selectedModel = id
+                Task { await stateManager.switchTranscriptionEngine(toModel: id) }
+            },
+            onDownload: chooseWhisperCppModel,
+            onDeleteRequest: nil
+        )
+    }
+
+    private func chooseWhisperCppModel() {
+        let panel = NSOpenPanel()
+        panel.canChooseDirectories = false
+        panel.canChooseFiles = true
+        panel.allowsMultipleSelection = false
+        panel.prompt = "Choose"
+        panel.message = "Choose a ggml or GGUF Whisper model, e.g. ggml-large-v3-turbo.bin."
+        guard panel.runModal() == .OK, let url = panel.url else { return }
+        Logger.shared.info("Settings: whisper.cpp model set to \(url.path)")
+        FileBookmarkStore.shared.forget(whisperCppModelPath)
+        FileBookmarkStore.shared.remember(url)
+        whisperCppModelPath = url.path
+    }
+
+    /// Caps Whisper model downloads so they don't saturate the connection during calls.
+    /// Parakeet downloads go through FluidAudio and are not limited.
+    private var downloadRateLimitPicker: some View {
+        Picker(selection: $downloadRateLimitMBps) {
+            Text("Unlimited").tag(0)
+            ForEach([1, 2, 5, 10, 25], id: \.self) { rate in
+                Text("\(rate) MB/s").tag(rate)
+            }
+        } label: {
+            Label("Download speed", systemImage: "speedometer")
+        }
+        .pickerStyle(.menu)
+        .fixedSize()
+        .help("Limits how fast Whisper models download")
+    }
+
     @ViewBuilder
     private func whisperCard(
         id: String,
@@ -232,6 +412,7 @@ struct ModelSettingsView: View {
         size: String,
         recommendationBadge: String? = nil
     ) -> some View {
+        let status = whisper.status(for: id)
         ModelCardView(
             title: title,
             description: description,
@@ -240,8 +421,14 @@ struct ModelSettingsView: View {
             isDownloaded: whisper.downloadedModels.contains(id),
             isActive: selectedModel == id && whisper.activeModel == id,
             isLoading: whisper.loadingModel == id,
-            downloadProgress: whisper.downloadProgresses[id],
+            downloadProgress: status.state == .downloading ? status.fractionCompleted : nil,
             recommendationBadge: recommendationBadge,
+            pausedProgress: status.state == .paused ? status.fractionCompleted : nil,
+            queuePosition: status.queuePosition,
+            onPauseDownload: { whisper.pauseDownload(id) },
+            onCancelDownload: { whisper.cancelDownload(id) },
+            isCorrupt: whisper.corruptModels.contains(id),
+            onRepair: { whisper.repairModel(id) },
             onSelect: { focusedModel = id },
             onUse: {
                 selectedModel = id
@@ -285,6 +472,7 @@ struct ModelSettingsView: View {
             downloadProgress: isDownloading && parakeet.loadingProgress < 0.65
                 ? Float(parakeet.loadingProgress) : nil,
             recommendationBadge: recommendationBadge,
+            onCancelDownload: { parakeet.cancelDownload(id: id) },
             onSelect: { focusedModel = id },
             onUse: {
                 selectedModel = id
@@ -0,0 +1,89 @@
+import SwiftUI
+
+/// Resources panel at the end of the Model tab: the app's memory, the selected model,
+/// the recording buffer and Metal, refreshed every few seconds while visible — so a
+/// model can be picked that fits this Mac's RAM.
+struct ResourcesSection: View {
+    @ObservedObject var stateManager: AppStateManager
+    @State private var usage: ResourceUsage?
+
+    private let refresh = Timer.publish(every: 2, on: .main, in: .common).autoconnect()
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 10) {
+            VStack(alignment: .leading, spacing: 2) {
+                Label {
+                    Text("Resources")
+                        .font(.system(size: 18, weight: .bold))
+                        .foregroundStyle(Theme.navy)
+                } icon: {
+                    Image(systemName: "memorychip")
+                        .foregroundStyle(Theme.navy)
+                }
+                if let usage {
+                    Text("Models up to about \(DiskSpace.format(usage.comfortableModelBytes)) fit comfortably in this Mac's \(DiskSpace.format(usage.physicalMemoryBytes)) of memory")
+                        .font(.system(size: 13))
+                        .italic()
+                        .foregroundStyle(Theme.textMuted)
+                        .padding(.top, 4)
+                }
+            }
+
+            if let usage {
+                VStack(spacing: 0) {
+                    row("VocaGlyph", detail: "Resident \(DiskSpace.format(usage.residentBytes))",
+                        value: usage.footprintBytes)
+                    if let model = usage.model {
+                        divider
+                        row("Model", detail: "\(model.id) — \(model.isLoaded ? "loaded" : "not loaded")",
+                            value: model.bytes)
+                    }
+                    divider
+                    row("Recording Buffer",
+                        detail: usage.audioBuffer.seconds > 0
+                            ? String(format: "%.1f s of audio", usage.audioBuffer.seconds)
+                            : "Empty between recordings",
+                        value: usage.audioBuffer.bytes)
+                    if let gpu = usage.gpu {
+                        divider
+                        row("Metal", detail: "\(gpu.name), recommended under \(DiskSpace.format(gpu.recommendedWorkingSetBytes))",
+                            value: gpu.allocatedBytes)
+                    }
+                }
+                .background(Color.white)
+                .clipShape(RoundedRectangle(cornerRadius: 12))
+                .overlay(
+                    RoundedRectangle(cornerRadius: 12)
+                        .stroke(Theme.textMuted.opacity(0.2), lineWidth: 1)
+                )
+                .shadow(color: Color.black.opacity(0.05), radius: 8, x: 0, y: 2)
+            }
+        }
+        .onAppear { usage = stateManager.resourceUsage() }
+        .onReceive(refresh) { _ in usage = stateManager.resourceUsage() }
+    }
+
+    private var divider: some View {
+        Divider()
+            .background(Theme.textMuted.opacity(0.15))
+            .padding(.horizontal, 12)
+    }
+
+    private func row(_ title: String, detail: String, value: Int64) -> some View {
+        HStack {
+            VStack(alignment: .leading, spacing: 2) {
+                Text(title)
+                    .fontWeight(.semibold)
+                    .foregroundStyle(Theme.navy)
+                Text(detail)
+                    .font(.system(size: 12))
+                    .foregroundStyle(Theme.textMuted)
+            }
+            Spacer()
+            Text(DiskSpace.format(value))
+                .font(.system(size: 13, design: .monospaced))
+                .foregroundStyle(Theme.navy)
+        }
+        .padding(16)
+    }
+}
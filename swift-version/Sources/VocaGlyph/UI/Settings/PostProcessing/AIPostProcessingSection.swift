@@ -86,7 +86,7 @@ struct AIPostProcessingSection: View {
                     selectedTaskModel = "apple-native"
                     stateManager.switchPostProcessingEngine()
                 }
-                Button("Cloud API (Gemini/Anthropic)") {
+                Button("Cloud API") {
                     Logger.shared.debug("Settings: Changed AI Processing Model to 'cloud-api'")
                     selectedTaskModel = "cloud-api"
                     stateManager.switchPostProcessingEngine()
@@ -99,7 +99,7 @@ struct AIPostProcessingSection: View {
             } label: {
                 HStack {
                     let display = selectedTaskModel == "apple-native" ? "Apple Intelligence"
-                        : selectedTaskModel == "cloud-api" ? "Cloud API (Gemini/Anthropic)"
+                        : selectedTaskModel == "cloud-api" ? "Cloud API"
                         : selectedTaskModel == "local-llm" ? "Local AI (Qwen)"
                         : selectedTaskModel
                     Text(display)
@@ -179,9 +179,16 @@ struct AIPostProcessingSection: View {
                     selectedCloudProvider = "anthropic"
                     stateManager.switchPostProcessingEngine()
                 }
+                Button("OpenAI-Compatible") {
+                    Logger.shared.debug("Settings: Changed Cloud Provider to 'openai'")
+                    selectedCloudProvider = "openai"
+                    stateManager.switchPostProcessingEngine()
+                }
             } label: {
                 HStack {
-                    Text(selectedCloudProvider == "anthropic" ? "Anthropic Claude" : "Google Gemini")
+                    Text(selectedCloudProvider == "anthropic" ? "Anthropic Claude"
+                        : selectedCloudProvider == "openai" ? "OpenAI-Compatible"
+                        : "Google Gemini")
                         .font(.system(size: 13))
                         .foregroundStyle(Theme.navy)
                     Spacer()
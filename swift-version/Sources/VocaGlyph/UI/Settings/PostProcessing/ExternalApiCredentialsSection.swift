@@ -1,10 +1,16 @@
 import SwiftUI
 
-/// External API Credentials section — Gemini and Anthropic keychain fields.
+/// External API Credentials section — Gemini and Anthropic keychain fields, and the
+/// endpoint, model, key and opt-in for an OpenAI-compatible API.
 struct ExternalApiCredentialsSection: View {
     @ObservedObject var viewModel: SettingsViewModel
     let selectedCloudProvider: String
 
+    @AppStorage("openAICompatibleEndpoint") private var openAICompatibleEndpoint: String = ""
+    @AppStorage("openAICompatibleModel") private var openAICompatibleModel: String = "gpt-4o-mini"
+    @AppStorage("openAICompatibleOptIn") private var openAICompatibleOptIn: Bool = false
+    @State private var openAICompatibleApiKey: String = ""
+
     var body: some View {
         VStack(alignment: .leading, spacing: 8) {
             Text("External API Credentials")
@@ -21,6 +27,10 @@ struct ExternalApiCredentialsSection: View {
             if selectedCloudProvider == "gemini" {
                 geminiKeyField
             }
+
+            if selectedCloudProvider == "openai" {
+                openAICompatibleFields
+            }
         }
         .padding(16)
         .background(Color.white)
@@ -102,6 +112,77 @@ struct ExternalApiCredentialsSection: View {
         .padding(.top, 4)
     }
 
+    // MARK: - OpenAI-Compatible
+
+    @ViewBuilder
+    private var openAICompatibleFields: some View {
+        let isKeySaved = viewModel.savedSecrets.contains(.openAICompatibleApiKey)
+        VStack(alignment: .leading, spacing: 8) {
+            Text("Endpoint & Model")
+                .font(.system(size: 11, weight: .medium))
+                .foregroundStyle(Theme.navy)
+
+            HStack(spacing: 8) {
+                TextField("https://api.openai.com/v1", text: $openAICompatibleEndpoint)
+                    .textFieldStyle(.roundedBorder)
+                    .font(.system(size: 13, design: .monospaced))
+                TextField("gpt-4o-mini", text: $openAICompatibleModel)
+                    .textFieldStyle(.roundedBorder)
+                    .font(.system(size: 13, design: .monospaced))
+                    .frame(width: 160)
+            }
+
+            Text("API Key")
+                .font(.system(size: 11, weight: .medium))
+                .foregroundStyle(Theme.navy)
+
+            HStack(spacing: 8) {
+                SecureField(isKeySaved ? "sk-... (Saved in Keychain)" : "sk-... (optional for local servers)", text: $openAICompatibleApiKey)
+                    .textFieldStyle(.roundedBorder)
+                    .font(.system(size: 13, design: .monospaced))
+
+                pasteButton { openAICompatibleApiKey = $0 }
+
+                if isKeySaved {
+                    Button(action: { Task { @MainActor in await viewModel.clearSecret(.openAICompatibleApiKey) } }) {
+                        Text("Delete").font(.system(size: 12, weight: .medium))
+                    }
+                    .buttonStyle(.bordered)
+                    .tint(.red)
+
+                    Image(systemName: "checkmark.seal.fill")
+                        .foregroundStyle(.green)
+                        .help("Key is securely stored in Keychain")
+                } else {
+                    Button(action: {
+                        Task { @MainActor in
+                            await viewModel.saveSecret(openAICompatibleApiKey.trimmingCharacters(in: .whitespacesAndNewlines), for: .openAICompatibleApiKey)
+                            openAICompatibleApiKey = ""
+                        }
+                    }) {
+                        Text("Save Securely").font(.system(size: 12, weight: .medium))
+                    }
+                    .buttonStyle(.borderedProminent)
+                    .disabled(openAICompatibleApiKey.isEmpty)
+                }
+            }
+
+            Toggle(isOn: $openAICompatibleOptIn.logged(name: "OpenAI-Compatible Opt-In")) {
+                VStack(alignment: .leading, spacing: 2) {
+                    Text("Send transcripts to this endpoint")
+                        .font(.system(size: 12, weight: .semibold))
+                        .foregroundStyle(Theme.navy)
+                    Text("Your dictated text leaves this Mac and is processed by the service above. Audio is never sent.")
+                        .font(.system(size: 11))
+                        .foregroundStyle(.orange)
+                }
+            }
+            .toggleStyle(.switch)
+            .tint(Theme.accent)
+        }
+        .padding(.top, 4)
+    }
+
     // MARK: - Helpers
 
     @ViewBuilder
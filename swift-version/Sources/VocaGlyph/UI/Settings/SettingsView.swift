@@ -37,6 +37,7 @@ struct SettingsView: View {
     @ObservedObject var parakeet: ParakeetService
     @ObservedObject var stateManager: AppStateManager
     var microphoneService: MicrophoneService
+    let settingsAPI: SettingsAPI
     @State private var settingsViewModel = SettingsViewModel()
 
     @State private var selectedTab: SettingsTab? = .general
@@ -59,9 +60,10 @@ struct SettingsView: View {
                 case .history:
                     HistorySettingsView()
                 case .general:
-                    GeneralSettingsView(whisper: whisper, stateManager: stateManager, microphoneService: microphoneService)
+                    GeneralSettingsView(whisper: whisper, stateManager: stateManager, microphoneService: microphoneService,
+                                        settingsAPI: settingsAPI)
                 case .model:
-                    ModelSettingsView(whisper: whisper, parakeet: parakeet, stateManager: stateManager)
+                    ModelSettingsView(whisper: whisper, parakeet: parakeet, stateManager: stateManager, viewModel: settingsViewModel)
                 case .textProcessing:
                     TextProcessingSettingsView(whisper: whisper, stateManager: stateManager, viewModel: settingsViewModel)
                 case .none:
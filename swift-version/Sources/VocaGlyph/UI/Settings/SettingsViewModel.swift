@@ -12,13 +12,17 @@ public final class SettingsViewModel: ObservableObject {
     @Published public var isAnthropicKeySaved: Bool = false
     @Published public var isGeminiKeySaved: Bool = false
     
+    /// Secrets currently saved in the Keychain. Drives "Saved" badges for fields bound
+    /// through `saveSecret(_:for:)` / `clearSecret(_:)`.
+    @Published public private(set) var savedSecrets: Set<SecretKey> = []
+    
     @Published public var errorMessage: String?
     
     private let keychainService: KeychainService
     
     // Constants for Keychain service identifiers
-    private let anthropicServiceId = "com.vocaglyph.api.anthropic"
-    private let geminiServiceId = "com.vocaglyph.api.gemini"
+    private let anthropicServiceId = SecretKey.anthropicApiKey.service
+    private let geminiServiceId = SecretKey.geminiApiKey.service
     
     public init(keychainService: KeychainService = KeychainService()) {
         self.keychainService = keychainService
@@ -46,6 +50,12 @@ public final class SettingsViewModel: ObservableObject {
         } catch {
             self.isGeminiKeySaved = false
         }
+        
+        var saved = Set<SecretKey>()
+        for key in SecretKey.allCases where await keychainService.hasSecret(key) {
+            saved.insert(key)
+        }
+        self.savedSecrets = saved
     }
     
     /// Saves the Anthropic API Key securely.
@@ -55,6 +65,7 @@ public final class SettingsViewModel: ObservableObject {
         do {
             try await keychainService.saveKey(anthropicApiKey, forService: anthropicServiceId)
             self.isAnthropicKeySaved = true
+            self.savedSecrets.insert(.anthropicApiKey)
             self.anthropicApiKey = "" // Clear field after save
         } catch {
             self.errorMessage = "Failed to save Anthropic Key: \(error.localizedDescription)"
@@ -67,6 +78,7 @@ public final class SettingsViewModel: ObservableObject {
         do {
             try await keychainService.deleteKey(forService: anthropicServiceId)
             self.isAnthropicKeySaved = false
+            self.savedSecrets.remove(.anthropicApiKey)
             self.anthropicApiKey = ""
         } catch {
             self.errorMessage = "Failed to delete Anthropic Key: \(error.localizedDescription)"
@@ -80,6 +92,7 @@ public final class SettingsViewModel: ObservableObject {
         do {
             try await keychainService.saveKey(geminiApiKey, forService: geminiServiceId)
             self.isGeminiKeySaved = true
+            self.savedSecrets.insert(.geminiApiKey)
             self.geminiApiKey = "" // Clear field after save
         } catch {
             self.errorMessage = "Failed to save Gemini Key: \(error.localizedDescription)"
@@ -92,9 +105,45 @@ public final class SettingsViewModel: ObservableObject {
         do {
             try await keychainService.deleteKey(forService: geminiServiceId)
             self.isGeminiKeySaved = false
+            self.savedSecrets.remove(.geminiApiKey)
             self.geminiApiKey = ""
         } catch {
             self.errorMessage = "Failed to delete Gemini Key: \(error.localizedDescription)"
         }
     }
+    
+    // MARK: - Generic Secrets
+    
+    /// Saves `value` to the Keychain for `key`. A blank value clears the secret.
+    public func saveSecret(_ value: String, for key: SecretKey) async {
+        errorMessage = nil
+        do {
+            try await keychainService.setSecret(value, for: key)
+            await refreshSavedState(for: key)
+        } catch {
+            self.errorMessage = "Failed to save \(key.displayName): \(error.localizedDescription)"
+        }
+    }
+    
+    /// Removes the secret for `key` from the Keychain.
+    public func clearSecret(_ key: SecretKey) async {
+        errorMessage = nil
+        do {
+            try await keychainService.clearSecret(key)
+            await refreshSavedState(for: key)
+        } catch {
+            self.errorMessage = "Failed to delete \(key.displayName): \(error.localizedDescription)"
+        }
+    }
+    
+    /// Keeps `savedSecrets` and the legacy per-provider flags in sync.
+    private func refreshSavedState(for key: SecretKey) async {
+        let isSaved = await keychainService.hasSecret(key)
+        if isSaved { savedSecrets.insert(key) } else { savedSecrets.remove(key) }
+        switch key {
+        case .anthropicApiKey: isAnthropicKeySaved = isSaved
+        case .geminiApiKey:    isGeminiKeySaved = isSaved
+        default:               break
+        }
+    }
 }
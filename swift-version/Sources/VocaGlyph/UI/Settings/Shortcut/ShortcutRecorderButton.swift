@@ -6,6 +6,7 @@ struct ShortcutRecorderButton: View {
     let displayLabel: String
     let onShortcutRecorded: (CGKeyCode, CGEventFlags) -> Void
     let onReset: () -> Void
+    var resetHelp: String = "Reset to default (⌃ ⇧ C)"
 
     @State private var isRecording = false
     @State private var localMonitor: Any?
@@ -60,7 +61,7 @@ struct ShortcutRecorderButton: View {
                     .foregroundStyle(Theme.textMuted)
             }
             .buttonStyle(.plain)
-            .help("Reset to default (⌃ ⇧ C)")
+            .help(resetHelp)
         }
         .onDisappear { stopRecording() }
     }
@@ -0,0 +1,70 @@
+import SwiftUI
+
+// MARK: - ContextTemplateSection
+
+/// Editor for the text appended to every refinement prompt. Saved to the
+/// `contextPromptTemplate` setting; an unchanged default is stored as empty so
+/// later improvements to the built-in template still reach the user.
+struct ContextTemplateSection: View {
+    @State private var draft: String = {
+        let saved = SettingsStore.shared.settings.contextPromptTemplate
+        return saved.isEmpty ? TemplatePromptRenderer.defaultContextTemplate : saved
+    }()
+    @State private var savedDraft: String = ""
+
+    private var unknownPlaceholders: [String] {
+        TemplatePromptRenderer.unknownPlaceholders(in: draft)
+    }
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            Text("Context Template")
+                .fontWeight(.semibold)
+                .foregroundStyle(Theme.navy)
+            Text("Appended to the refinement prompt. Use \(TemplatePromptRenderer.contextPlaceholders.joined(separator: ", ")); a paragraph whose placeholder is empty is left out.")
+                .font(.system(size: 12))
+                .foregroundStyle(Theme.textMuted)
+
+            TextEditor(text: $draft)
+                .font(.system(size: 12, design: .monospaced))
+                .foregroundStyle(Theme.navy)
+                .scrollContentBackground(.hidden)
+                .padding(.horizontal, 12)
+                .padding(.vertical, 8)
+                .background(Color(hex: "#F8F7F4"))
+                .clipShape(.rect(cornerRadius: 8))
+                .frame(height: 160)
+
+            if !unknownPlaceholders.isEmpty {
+                Label("Unknown placeholder: \(unknownPlaceholders.joined(separator: ", "))",
+                      systemImage: "exclamationmark.triangle.fill")
+                    .font(.system(size: 12))
+                    .foregroundStyle(.orange)
+            }
+
+            HStack {
+                Button("Reset to Default") {
+                    draft = TemplatePromptRenderer.defaultContextTemplate
+                    save()
+                }
+                .buttonStyle(.bordered)
+                .disabled(draft == TemplatePromptRenderer.defaultContextTemplate)
+
+                Spacer()
+
+                Button("Save", action: save)
+                    .buttonStyle(.borderedProminent)
+                    .disabled(draft == savedDraft || !unknownPlaceholders.isEmpty)
+            }
+        }
+        .padding(16)
+        .onAppear { savedDraft = draft }
+    }
+
+    private func save() {
+        let isDefault = draft == TemplatePromptRenderer.defaultContextTemplate
+        Logger.shared.debug("Settings: Context template saved (\(isDefault ? "default" : "\(draft.count) chars"))")
+        SettingsStore.shared.update { $0.contextPromptTemplate = isDefault ? "" : draft }
+        savedDraft = draft
+    }
+}
@@ -0,0 +1,100 @@
+import SwiftUI
+
+/// Per-App Rules section: `AppProcessingRule` lines that switch stages or the output
+/// style for one app, e.g. code comments only in VS Code.
+struct AppRulesSection: View {
+    @State private var rules: [String] = SettingsStore.shared.settings.appProcessingRules
+    @State private var newRule: String = ""
+    @State private var error: String?
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            Label {
+                Text("Per-App Rules")
+                    .font(.system(size: 18, weight: .bold))
+                    .foregroundStyle(Theme.navy)
+            } icon: {
+                Image(systemName: "macwindow.on.rectangle")
+                    .foregroundStyle(Theme.navy)
+            }
+
+            Text("Apply when the app is frontmost as text is pasted. +stage / -stage switch a stage, style= sets the output style.")
+                .font(.system(size: 13))
+                .italic()
+                .foregroundStyle(Theme.textMuted)
+
+            VStack(spacing: 0) {
+                ForEach(rules, id: \.self) { rule in
+                    HStack {
+                        Text(rule)
+                            .font(.system(size: 12, design: .monospaced))
+                            .foregroundStyle(Theme.navy)
+                        Spacer()
+                        Button(action: { remove(rule) }) {
+                            Image(systemName: "trash")
+                                .font(.system(size: 12))
+                                .foregroundStyle(Theme.textMuted)
+                        }
+                        .buttonStyle(.plain)
+                        .help("Remove rule")
+                    }
+                    .padding(.horizontal, 16)
+                    .padding(.vertical, 10)
+
+                    Divider()
+                        .background(Theme.textMuted.opacity(0.1))
+                        .padding(.horizontal, 16)
+                }
+
+                VStack(alignment: .leading, spacing: 4) {
+                    HStack(spacing: 8) {
+                        TextField("com.microsoft.VSCode: style=codeComment, -llmCleanup", text: $newRule)
+                            .textFieldStyle(.roundedBorder)
+                            .font(.system(size: 12, design: .monospaced))
+                            .onSubmit(add)
+                        Button("Add", action: add)
+                            .buttonStyle(.bordered)
+                            .disabled(newRule.trimmingCharacters(in: .whitespaces).isEmpty)
+                    }
+                    if let error {
+                        Text(error)
+                            .font(.system(size: 11))
+                            .foregroundStyle(.red)
+                    }
+                }
+                .padding(16)
+            }
+            .background(Color.white)
+            .clipShape(.rect(cornerRadius: 12))
+            .overlay(
+                RoundedRectangle(cornerRadius: 12)
+                    .stroke(Theme.textMuted.opacity(0.2), lineWidth: 1)
+            )
+        }
+    }
+
+    private func add() {
+        let line = newRule.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !line.isEmpty else { return }
+        do {
+            let rule = try AppProcessingRule(parsing: line)
+            // A new rule for an app replaces its old one.
+            let others = rules.filter { (try? AppProcessingRule(parsing: $0))?.bundleIdentifier != rule.bundleIdentifier }
+            save(others + [rule.line])
+            newRule = ""
+            error = nil
+        } catch {
+            self.error = error.localizedDescription
+        }
+    }
+
+    private func remove(_ rule: String) {
+        save(rules.filter { $0 != rule })
+    }
+
+    private func save(_ updated: [String]) {
+        Logger.shared.debug("Settings: \(updated.count) per-app rules saved")
+        SettingsStore.shared.update { $0.appProcessingRules = updated }
+        rules = updated
+    }
+}
@@ -1,10 +1,20 @@
 import SwiftUI
 
-/// Basic Cleanup section: Auto-Punctuation and Remove Filler Words toggles.
-/// These are lightweight rules that always run, regardless of AI settings.
+/// Basic Cleanup section: Auto-Punctuation, Restore Punctuation, Spoken Punctuation,
+/// Numbers & Dates, Remove Filler Words and Profanity Filter.
+/// These are lightweight rules that always run, regardless of AI settings — except
+/// Restore Punctuation, which needs the local AI model on disk.
 struct BasicCleanupSection: View {
     @AppStorage("autoPunctuation") private var autoPunctuation: Bool = true
     @AppStorage("removeFillerWords") private var removeFillerWords: Bool = false
+    @AppStorage("spokenPunctuationEnabled") private var spokenPunctuationEnabled: Bool = false
+    @AppStorage("punctuationRestorationEnabled") private var punctuationRestorationEnabled: Bool = false
+    @AppStorage("summarizeLongDictations") private var summarizeLongDictations: Bool = false
+    @AppStorage("summaryMinimumSeconds") private var summaryMinimumSeconds: Int = 60
+    @AppStorage("enablePostProcessing") private var enablePostProcessing: Bool = false
+    @AppStorage("numberNormalizationEnabled") private var numberNormalizationEnabled: Bool = false
+    @AppStorage("profanityFilter") private var profanityFilter: String = ProfanityFilter.Mode.off.rawValue
+    @State private var profanityWordsText: String = SettingsStore.shared.settings.profanityCustomWords.joined(separator: ", ")
 
     var body: some View {
         VStack(alignment: .leading, spacing: 8) {
@@ -40,6 +50,116 @@ struct BasicCleanupSection: View {
                 }
                 .padding(16)
 
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Restore Punctuation
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Restore Punctuation")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Punctuate run-on transcripts with the local AI model, without changing words")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $punctuationRestorationEnabled.logged(name: "Restore Punctuation"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Summaries
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Summarize Long Dictations")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Also write an AI summary and choose which version to paste")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                        if summarizeLongDictations && !enablePostProcessing {
+                            Text("Turn on AI Refinement above — summaries use its engine.")
+                                .font(.system(size: 11))
+                                .foregroundStyle(.orange)
+                                .fixedSize(horizontal: false, vertical: true)
+                        }
+                    }
+                    Spacer()
+                    Toggle("", isOn: $summarizeLongDictations.logged(name: "Summarize Long Dictations"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                if summarizeLongDictations {
+                    Divider()
+                        .background(Theme.textMuted.opacity(0.1))
+                        .padding(.horizontal, 16)
+
+                    HStack {
+                        Text("Minimum length")
+                            .foregroundStyle(Theme.navy)
+                        Spacer()
+                        Stepper(value: $summaryMinimumSeconds,
+                                in: DictationSummarizer.minimumSecondsRange,
+                                step: 10) {
+                            Text("\(summaryMinimumSeconds) s")
+                                .monospacedDigit()
+                                .foregroundStyle(Theme.textMuted)
+                        }
+                    }
+                    .padding(16)
+                }
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Spoken Punctuation
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Spoken Punctuation")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Turn \"comma\", \"question mark\" or \"new line\" into punctuation")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $spokenPunctuationEnabled.logged(name: "Spoken Punctuation"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Numbers & Dates
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Numbers & Dates")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text("Write spoken numbers, times and dates as digits (English and Spanish)")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Toggle("", isOn: $numberNormalizationEnabled.logged(name: "Numbers & Dates"))
+                        .labelsHidden()
+                        .toggleStyle(.switch)
+                }
+                .padding(16)
+
                 Divider()
                     .background(Theme.textMuted.opacity(0.1))
                     .padding(.horizontal, 16)
@@ -50,7 +170,7 @@ struct BasicCleanupSection: View {
                         Text("Remove Filler Words")
                             .fontWeight(.semibold)
                             .foregroundStyle(Theme.navy)
-                        Text("Automatically strip conversational words (um, uh, like)")
+                        Text("Strip um/uh, stutters like \"I I think\" and a trailing \"so…\"")
                             .font(.system(size: 12))
                             .foregroundStyle(Theme.textMuted)
                     }
@@ -60,6 +180,40 @@ struct BasicCleanupSection: View {
                         .toggleStyle(.switch)
                 }
                 .padding(16)
+
+                Divider()
+                    .background(Theme.textMuted.opacity(0.1))
+                    .padding(.horizontal, 16)
+
+                // Profanity Filter
+                VStack(alignment: .leading, spacing: 8) {
+                    HStack {
+                        VStack(alignment: .leading, spacing: 2) {
+                            Text("Profanity Filter")
+                                .fontWeight(.semibold)
+                                .foregroundStyle(Theme.navy)
+                            Text("Mask or remove swear words, e.g. when sharing your screen")
+                                .font(.system(size: 12))
+                                .foregroundStyle(Theme.textMuted)
+                        }
+                        Spacer()
+                        Picker("", selection: $profanityFilter.logged(name: "Profanity Filter")) {
+                            Text("Off").tag(ProfanityFilter.Mode.off.rawValue)
+                            Text("Mask (s***)").tag(ProfanityFilter.Mode.mask.rawValue)
+                            Text("Remove").tag(ProfanityFilter.Mode.drop.rawValue)
+                        }
+                        .labelsHidden()
+                        .fixedSize()
+                    }
+
+                    if profanityFilter != ProfanityFilter.Mode.off.rawValue {
+                        TextField("Extra words, comma separated", text: $profanityWordsText)
+                            .textFieldStyle(.roundedBorder)
+                            .font(.system(size: 12))
+                            .onSubmit(saveProfanityWords)
+                    }
+                }
+                .padding(16)
             }
             .background(Color.white)
             .clipShape(.rect(cornerRadius: 12))
@@ -69,4 +223,14 @@ struct BasicCleanupSection: View {
             )
         }
     }
+
+    private func saveProfanityWords() {
+        let words = profanityWordsText
+            .split(separator: ",")
+            .map { $0.trimmingCharacters(in: .whitespaces) }
+            .filter { !$0.isEmpty }
+        Logger.shared.debug("Settings: \(words.count) custom profanity words saved")
+        SettingsStore.shared.update { $0.profanityCustomWords = words }
+        profanityWordsText = words.joined(separator: ", ")
+    }
 }
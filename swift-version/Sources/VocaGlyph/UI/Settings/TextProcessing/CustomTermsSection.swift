@@ -0,0 +1,94 @@
+import SwiftUI
+
+/// Custom Terms section: the names and jargon `TermCorrector` snaps near-misses to.
+/// Unlike word replacements, a term needs no misspelling — "Cooper Netties" and
+/// "cubanetes" both become "Kubernetes".
+struct CustomTermsSection: View {
+    @AppStorage("termCorrectionEnabled") private var termCorrectionEnabled: Bool = true
+    @State private var terms: [String] = SettingsStore.shared.settings.customTerms
+    @State private var newTerm: String = ""
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack {
+                Label {
+                    Text("Custom Terms")
+                        .font(.system(size: 18, weight: .bold))
+                        .foregroundStyle(Theme.navy)
+                } icon: {
+                    Image(systemName: "character.book.closed")
+                        .foregroundStyle(Theme.navy)
+                }
+                Spacer()
+                Toggle("", isOn: $termCorrectionEnabled.logged(name: "Custom Terms"))
+                    .labelsHidden()
+                    .toggleStyle(.switch)
+            }
+
+            Text("Names and jargon the engine keeps mishearing. Similar-sounding words are corrected to them.")
+                .font(.system(size: 13))
+                .italic()
+                .foregroundStyle(Theme.textMuted)
+
+            VStack(spacing: 0) {
+                ForEach(terms, id: \.self) { term in
+                    HStack {
+                        Text(term)
+                            .font(.system(size: 13))
+                            .foregroundStyle(Theme.navy)
+                        Spacer()
+                        Button(action: { remove(term) }) {
+                            Image(systemName: "trash")
+                                .font(.system(size: 12))
+                                .foregroundStyle(Theme.textMuted)
+                        }
+                        .buttonStyle(.plain)
+                        .help("Remove \(term)")
+                    }
+                    .padding(.horizontal, 16)
+                    .padding(.vertical, 10)
+
+                    Divider()
+                        .background(Theme.textMuted.opacity(0.1))
+                        .padding(.horizontal, 16)
+                }
+
+                HStack(spacing: 8) {
+                    TextField("Add a term, e.g. Kubernetes", text: $newTerm)
+                        .textFieldStyle(.roundedBorder)
+                        .font(.system(size: 13))
+                        .onSubmit(add)
+                    Button("Add", action: add)
+                        .buttonStyle(.bordered)
+                        .disabled(newTerm.trimmingCharacters(in: .whitespaces).isEmpty)
+                }
+                .padding(16)
+            }
+            .background(Color.white)
+            .clipShape(.rect(cornerRadius: 12))
+            .overlay(
+                RoundedRectangle(cornerRadius: 12)
+                    .stroke(Theme.textMuted.opacity(0.2), lineWidth: 1)
+            )
+        }
+    }
+
+    private func add() {
+        let term = newTerm.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !term.isEmpty else { return }
+        if !terms.contains(where: { $0.caseInsensitiveCompare(term) == .orderedSame }) {
+            save(terms + [term])
+        }
+        newTerm = ""
+    }
+
+    private func remove(_ term: String) {
+        save(terms.filter { $0 != term })
+    }
+
+    private func save(_ updated: [String]) {
+        Logger.shared.debug("Settings: \(updated.count) custom terms saved")
+        SettingsStore.shared.update { $0.customTerms = updated }
+        terms = updated
+    }
+}
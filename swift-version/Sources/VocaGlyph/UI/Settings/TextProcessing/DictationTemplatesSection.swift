@@ -0,0 +1,137 @@
+import SwiftData
+import SwiftUI
+
+/// Dictation Templates section: texts with `{slots}` that successive dictations fill in.
+/// Templates are started from the tray menu.
+struct DictationTemplatesSection: View {
+    @Environment(\.modelContext) private var modelContext
+    @Query(sort: \DictationTemplate.name) private var templates: [DictationTemplate]
+
+    @State private var name: String = ""
+    @State private var templateBody: String = ""
+    /// The template loaded into the form for editing; `nil` when adding a new one.
+    @State private var editingID: UUID?
+    @State private var error: String?
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            Label {
+                Text("Dictation Templates")
+                    .font(.system(size: 18, weight: .bold))
+                    .foregroundStyle(Theme.navy)
+            } icon: {
+                Image(systemName: "doc.text.below.ecg")
+                    .foregroundStyle(Theme.navy)
+            }
+
+            Text("Start one from the menu bar; each dictation fills the next {slot}, and the finished text is pasted.")
+                .font(.system(size: 13))
+                .italic()
+                .foregroundStyle(Theme.textMuted)
+
+            VStack(spacing: 0) {
+                ForEach(templates) { template in
+                    HStack(alignment: .top) {
+                        VStack(alignment: .leading, spacing: 2) {
+                            Text(template.name)
+                                .fontWeight(.semibold)
+                                .foregroundStyle(Theme.navy)
+                            Text(template.body)
+                                .font(.system(size: 12, design: .monospaced))
+                                .foregroundStyle(Theme.textMuted)
+                                .lineLimit(2)
+                        }
+                        Spacer()
+                        Button(action: { edit(template) }) {
+                            Image(systemName: "pencil")
+                                .font(.system(size: 12))
+                                .foregroundStyle(Theme.textMuted)
+                        }
+                        .buttonStyle(.plain)
+                        .help("Edit template")
+                        Button(action: { delete(template) }) {
+                            Image(systemName: "trash")
+                                .font(.system(size: 12))
+                                .foregroundStyle(Theme.textMuted)
+                        }
+                        .buttonStyle(.plain)
+                        .help("Delete template")
+                    }
+                    .padding(.horizontal, 16)
+                    .padding(.vertical, 10)
+
+                    Divider()
+                        .background(Theme.textMuted.opacity(0.1))
+                        .padding(.horizontal, 16)
+                }
+
+                VStack(alignment: .leading, spacing: 8) {
+                    TextField("Standup", text: $name)
+                        .textFieldStyle(.roundedBorder)
+                    TextField("Yesterday {yesterday}, today {today}, blockers {blockers}", text: $templateBody)
+                        .textFieldStyle(.roundedBorder)
+                        .font(.system(size: 12, design: .monospaced))
+                        .onSubmit(save)
+                    HStack(spacing: 8) {
+                        if let error {
+                            Text(error)
+                                .font(.system(size: 11))
+                                .foregroundStyle(.red)
+                        }
+                        Spacer()
+                        if editingID != nil {
+                            Button("Cancel", action: resetForm)
+                                .buttonStyle(.bordered)
+                        }
+                        Button(editingID == nil ? "Add" : "Save", action: save)
+                            .buttonStyle(.bordered)
+                            .disabled(name.trimmingCharacters(in: .whitespaces).isEmpty)
+                    }
+                }
+                .padding(16)
+            }
+            .background(Color.white)
+            .clipShape(.rect(cornerRadius: 12))
+            .overlay(
+                RoundedRectangle(cornerRadius: 12)
+                    .stroke(Theme.textMuted.opacity(0.2), lineWidth: 1)
+            )
+        }
+    }
+
+    private var service: TemplateService {
+        TemplateService(container: modelContext.container)
+    }
+
+    private func edit(_ template: DictationTemplate) {
+        editingID = template.id
+        name = template.name
+        templateBody = template.body
+        error = nil
+    }
+
+    private func save() {
+        do {
+            if let editingID {
+                try service.update(editingID, name: name, body: templateBody)
+            } else {
+                try service.create(name: name, body: templateBody)
+            }
+            resetForm()
+        } catch {
+            self.error = error.localizedDescription
+        }
+    }
+
+    private func delete(_ template: DictationTemplate) {
+        if editingID == template.id { resetForm() }
+        service.delete(template.id)
+    }
+
+    private func resetForm() {
+        editingID = nil
+        name = ""
+        templateBody = ""
+        error = nil
+    }
+}
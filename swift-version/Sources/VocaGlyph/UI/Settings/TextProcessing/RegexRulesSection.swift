@@ -0,0 +1,80 @@
+import SwiftUI
+
+/// Regex Rules section: status of `regex-rules.json` and a button to edit it.
+/// The file is reloaded automatically when saved, so there is no Apply button.
+struct RegexRulesSection: View {
+    @ObservedObject var service: RegexRulesService = .shared
+
+    @AppStorage("regexRulesEnabled") private var regexRulesEnabled: Bool = true
+    @State private var openError: String?
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            HStack {
+                Label {
+                    Text("Regex Rules")
+                        .font(.system(size: 18, weight: .bold))
+                        .foregroundStyle(Theme.navy)
+                } icon: {
+                    Image(systemName: "chevron.left.forwardslash.chevron.right")
+                        .foregroundStyle(Theme.navy)
+                }
+                Spacer()
+                Toggle("", isOn: $regexRulesEnabled.logged(name: "Regex Rules"))
+                    .labelsHidden()
+                    .toggleStyle(.switch)
+            }
+
+            Text("Ordered find/replace rules with capture groups, run after word replacements.")
+                .font(.system(size: 13))
+                .italic()
+                .foregroundStyle(Theme.textMuted)
+
+            VStack(alignment: .leading, spacing: 8) {
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text(service.rules.isEmpty ? "No rules yet" : "\(service.activeRuleCount) active rule(s)")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text(service.rulesURL.path)
+                            .font(.system(size: 11, design: .monospaced))
+                            .foregroundStyle(Theme.textMuted)
+                            .lineLimit(1)
+                            .truncationMode(.middle)
+                    }
+                    Spacer()
+                    Button("Open Rules File") { openRulesFile() }
+                        .buttonStyle(.bordered)
+                }
+
+                ForEach(service.problems, id: \.self) { problem in
+                    Label(problem, systemImage: "exclamationmark.triangle.fill")
+                        .font(.system(size: 12))
+                        .foregroundStyle(.orange)
+                }
+                if let openError {
+                    Text(openError)
+                        .font(.system(size: 12))
+                        .foregroundStyle(.red)
+                }
+            }
+            .padding(16)
+            .background(Color.white)
+            .clipShape(.rect(cornerRadius: 12))
+            .overlay(
+                RoundedRectangle(cornerRadius: 12)
+                    .stroke(Theme.textMuted.opacity(0.2), lineWidth: 1)
+            )
+        }
+    }
+
+    private func openRulesFile() {
+        do {
+            try service.createFileIfNeeded()
+            openError = nil
+            NSWorkspace.shared.open(service.rulesURL)
+        } catch {
+            openError = "Could not create \(RegexRulesService.fileName): \(error.localizedDescription)"
+        }
+    }
+}
@@ -2,7 +2,8 @@ import SwiftUI
 
 /// Coordinator view for the Writing Assistant settings tab.
 /// Owns the new-template overlay and template editor overlay state.
-/// Sections appear in order: AI Refinement → Basic Cleanup → Word Replacements.
+/// Sections appear in order: AI Refinement → Basic Cleanup → Word Replacements → Custom Terms
+/// → Regex Rules → Translation → Per-App Rules → Dictation Templates.
 struct TextProcessingSettingsView: View {
     @ObservedObject var whisper: WhisperService
     @ObservedObject var stateManager: AppStateManager
@@ -60,6 +61,21 @@ struct TextProcessingSettingsView: View {
 
                         // 3. Word Replacements
                         WordReplacementSection()
+
+                        // 4. Custom Terms
+                        CustomTermsSection()
+
+                        // 5. Regex Rules
+                        RegexRulesSection()
+
+                        // 6. Translation
+                        TranslationSection(viewModel: viewModel)
+
+                        // 7. Per-App Rules
+                        AppRulesSection()
+
+                        // 8. Dictation Templates
+                        DictationTemplatesSection()
                     }
                     .padding(40)
                     .padding(.bottom, 20)
@@ -158,6 +174,8 @@ private struct TemplateAwareAISection: View {
                     cloudAPISubSection
                     Divider().background(Theme.textMuted.opacity(0.1))
                     TemplateListSection(onEdit: onEditTemplate, onAddTemplate: onAddTemplate)
+                    Divider().background(Theme.textMuted.opacity(0.1))
+                    ContextTemplateSection()
                 }
             }
             .background(Color.white)
@@ -212,7 +230,7 @@ private struct TemplateAwareAISection: View {
                     selectedTaskModel = "apple-native"
                     stateManager.switchPostProcessingEngine()
                 }
-                Button("Cloud API (Gemini/Anthropic)") {
+                Button("Cloud API") {
                     Logger.shared.debug("Settings: Changed AI Processing Model to 'cloud-api'")
                     selectedTaskModel = "cloud-api"
                     stateManager.switchPostProcessingEngine()
@@ -225,7 +243,7 @@ private struct TemplateAwareAISection: View {
             } label: {
                 HStack {
                     let display = selectedTaskModel == "apple-native" ? "Apple Intelligence"
-                        : selectedTaskModel == "cloud-api" ? "Cloud API (Gemini/Anthropic)"
+                        : selectedTaskModel == "cloud-api" ? "Cloud API"
                         : selectedTaskModel == "local-llm" ? "Local AI (Qwen)"
                         : selectedTaskModel
                     Text(display).font(.system(size: 13)).foregroundStyle(Theme.navy)
@@ -296,9 +314,16 @@ private struct TemplateAwareAISection: View {
                     selectedCloudProvider = "anthropic"
                     stateManager.switchPostProcessingEngine()
                 }
+                Button("OpenAI-Compatible") {
+                    Logger.shared.debug("Settings: Changed Cloud Provider to 'openai'")
+                    selectedCloudProvider = "openai"
+                    stateManager.switchPostProcessingEngine()
+                }
             } label: {
                 HStack {
-                    Text(selectedCloudProvider == "anthropic" ? "Anthropic Claude" : "Google Gemini")
+                    Text(selectedCloudProvider == "anthropic" ? "Anthropic Claude"
+                        : selectedCloudProvider == "openai" ? "OpenAI-Compatible"
+                        : "Google Gemini")
                         .font(.system(size: 13)).foregroundStyle(Theme.navy)
                     Spacer()
                     Image(systemName: "chevron.down")
@@ -0,0 +1,136 @@
+import SwiftUI
+
+/// Translation section: provider, target language and, for a translation server,
+/// its URL and optional API key.
+struct TranslationSection: View {
+    @ObservedObject var viewModel: SettingsViewModel
+
+    @AppStorage("translationProvider") private var translationProvider: String = TranslationProvider.off.rawValue
+    @AppStorage("translationTargetLanguage") private var translationTargetLanguage: String = ""
+    @AppStorage("translationEndpoint") private var translationEndpoint: String = ""
+    @State private var apiKey: String = ""
+
+    /// Offered in the target picker; any other code can still be set via settings import.
+    private static let targetLanguages = ["en", "es", "fr", "de", "id", "it", "pt", "nl", "ja", "ko", "zh", "ru"]
+
+    private var provider: TranslationProvider {
+        TranslationProvider(rawValue: translationProvider) ?? .off
+    }
+
+    var body: some View {
+        VStack(alignment: .leading, spacing: 8) {
+            Label {
+                Text("Translation")
+                    .font(.system(size: 18, weight: .bold))
+                    .foregroundStyle(Theme.navy)
+            } icon: {
+                Image(systemName: "globe")
+                    .foregroundStyle(Theme.navy)
+            }
+
+            Text("Speak in one language and paste in another. Runs after AI refinement.")
+                .font(.system(size: 13))
+                .italic()
+                .foregroundStyle(Theme.textMuted)
+
+            VStack(spacing: 0) {
+                HStack {
+                    VStack(alignment: .leading, spacing: 2) {
+                        Text("Translate With")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Text(provider == .llm ? "Uses the AI Refinement engine — enable it above"
+                             : "Off, your AI engine, or a LibreTranslate-compatible server")
+                            .font(.system(size: 12))
+                            .foregroundStyle(Theme.textMuted)
+                    }
+                    Spacer()
+                    Picker("", selection: $translationProvider.logged(name: "Translation Provider")) {
+                        Text("Off").tag(TranslationProvider.off.rawValue)
+                        Text("AI Engine").tag(TranslationProvider.llm.rawValue)
+                        Text("Translation Server").tag(TranslationProvider.remote.rawValue)
+                    }
+                    .labelsHidden()
+                    .fixedSize()
+                }
+                .padding(16)
+
+                if provider != .off {
+                    Divider()
+                        .background(Theme.textMuted.opacity(0.1))
+                        .padding(.horizontal, 16)
+
+                    HStack {
+                        Text("Target Language")
+                            .fontWeight(.semibold)
+                            .foregroundStyle(Theme.navy)
+                        Spacer()
+                        Picker("", selection: $translationTargetLanguage.logged(name: "Translation Target Language")) {
+                            Text("Choose…").tag("")
+                            ForEach(Self.targetLanguages, id: \.self) { code in
+                                Text(Locale.current.localizedString(forLanguageCode: code) ?? code).tag(code)
+                            }
+                        }
+                        .labelsHidden()
+                        .fixedSize()
+                    }
+                    .padding(16)
+                }
+
+                if provider == .remote {
+                    Divider()
+                        .background(Theme.textMuted.opacity(0.1))
+                        .padding(.horizontal, 16)
+
+                    serverRows
+                        .padding(16)
+                }
+            }
+            .background(Color.white)
+            .clipShape(.rect(cornerRadius: 12))
+            .overlay(
+                RoundedRectangle(cornerRadius: 12)
+                    .stroke(Theme.textMuted.opacity(0.2), lineWidth: 1)
+            )
+        }
+    }
+
+    @ViewBuilder
+    private var serverRows: some View {
+        let isKeySaved = viewModel.savedSecrets.contains(.translationApiKey)
+        VStack(alignment: .leading, spacing: 8) {
+            TextField("https://libretranslate.example.com", text: $translationEndpoint)
+                .textFieldStyle(.roundedBorder)
+                .font(.system(size: 13, design: .monospaced))
+
+            HStack(spacing: 8) {
+                SecureField(isKeySaved ? "API key (Saved in Keychain)" : "API key (optional)", text: $apiKey)
+                    .textFieldStyle(.roundedBorder)
+                    .font(.system(size: 13, design: .monospaced))
+
+                if isKeySaved {
+                    Button(action: { Task { @MainActor in await viewModel.clearSecret(.translationApiKey) } }) {
+                        Text("Delete").font(.system(size: 12, weight: .medium))
+                    }
+                    .buttonStyle(.bordered)
+                    .tint(.red)
+                } else {
+                    Button(action: {
+                        Task { @MainActor in
+                            await viewModel.saveSecret(apiKey.trimmingCharacters(in: .whitespacesAndNewlines), for: .translationApiKey)
+                            apiKey = ""
+                        }
+                    }) {
+                        Text("Save Securely").font(.system(size: 12, weight: .medium))
+                    }
+                    .buttonStyle(.borderedProminent)
+                    .disabled(apiKey.isEmpty)
+                }
+            }
+
+            Text("Dictated text is sent to this server. Self-host LibreTranslate to keep it on your network.")
+                .font(.system(size: 11))
+                .foregroundStyle(Theme.textMuted)
+        }
+    }
+}
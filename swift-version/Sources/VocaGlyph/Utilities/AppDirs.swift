@@ -0,0 +1,116 @@
+import Foundation
+
+// MARK: - AppDirs
+
+/// Where VocaGlyph keeps its files. Services ask here instead of building their own
+/// paths, so a `--data-dir` override (or a test) moves everything at once:
+///
+///     root      ~/Library/Application Support/VocaGlyph/   rules, models.json, sockets, …
+///     models    <root>/models/
+///     backups   <root>/Backups/
+///     recovered <root>/Recovered/                          audio of abandoned dictations
+///     sockets   <root>/, or $TMPDIR when a socket path there would be too long
+///     history   <root>/history.store                       (SwiftData)
+///     logs      ~/Library/Logs/VocaGlyph/                  <override>/Logs/
+///     caches    ~/Library/Caches/VocaGlyph/                <override>/Caches/
+///
+/// Older builds kept the history store in SwiftData's default location;
+/// `AppDelegate.makeModelContainer` adopts it into `root` before opening the store.
+struct AppDirs: Equatable {
+
+    static let historyStoreName = "history.store"
+    /// SQLite companions of a SwiftData store, moved along with it.
+    static let storeSidecarSuffixes = ["-wal", "-shm"]
+    /// Longest path a Unix socket binds to: `sun_path` holds 104 bytes with the NUL.
+    static let maxSocketPathLength = 103
+    static let socketNames = [ControlSocketService.socketName, TranscriptionServer.socketName]
+
+    let root: URL
+    let logs: URL
+    let caches: URL
+
+    var models: URL { root.appendingPathComponent("models", isDirectory: true) }
+    var backups: URL { root.appendingPathComponent("Backups", isDirectory: true) }
+    var recovered: URL { root.appendingPathComponent("Recovered", isDirectory: true) }
+    var historyStore: URL { root.appendingPathComponent(Self.historyStoreName) }
+    /// In the App Sandbox the container prefix alone takes about 70 bytes, leaving too
+    /// little of `sun_path` for `root`.
+    var sockets: URL { Self.socketDirectory(preferred: root, fallback: FileManager.default.temporaryDirectory) }
+
+    init(root: URL, logs: URL, caches: URL) {
+        self.root = root
+        self.logs = logs
+        self.caches = caches
+    }
+
+    /// Everything under `root`, the layout of a `--data-dir` override. Tests pass a
+    /// temporary directory.
+    init(root: URL) {
+        self.init(root: root,
+                  logs: root.appendingPathComponent("Logs", isDirectory: true),
+                  caches: root.appendingPathComponent("Caches", isDirectory: true))
+    }
+
+    /// The standard macOS locations.
+    static func standard(fileManager: FileManager = .default) -> AppDirs {
+        let library = fileManager.urls(for: .libraryDirectory, in: .userDomainMask)[0]
+        return AppDirs(
+            root: fileManager.urls(for: .applicationSupportDirectory, in: .userDomainMask)[0]
+                .appendingPathComponent("VocaGlyph", isDirectory: true),
+            logs: library.appendingPathComponent("Logs/VocaGlyph", isDirectory: true),
+            caches: fileManager.urls(for: .cachesDirectory, in: .userDomainMask)[0]
+                .appendingPathComponent("VocaGlyph", isDirectory: true)
+        )
+    }
+
+    /// This process's layout: the `--data-dir` override if one is set, otherwise the
+    /// standard locations.
+    static let current: AppDirs = DataDirectoryOverride.root.map { AppDirs(root: $0) } ?? .standard()
+
+    /// `preferred` when every socket in `socketNames` fits under it, else `fallback`.
+    static func socketDirectory(preferred: URL, fallback: URL) -> URL {
+        let longestName = socketNames.map(\.utf8.count).max() ?? 0
+        return preferred.path.utf8.count + 1 + longestName <= maxSocketPathLength ? preferred : fallback
+    }
+
+    // MARK: - Migration
+
+    /// Where SwiftData kept the history store before it moved to `historyStore`; `nil`
+    /// under a `--data-dir` override, which is a fresh, deliberately separate instance.
+    static var legacyHistoryStore: URL? {
+        guard DataDirectoryOverride.root == nil else { return nil }
+        return FileManager.default.urls(for: .applicationSupportDirectory, in: .userDomainMask)[0]
+            .appendingPathComponent("default.store")
+    }
+
+    /// Moves the SwiftData store at `store` (and its sidecars) to `historyStore`, unless
+    /// one is already there. The store goes first; if anything fails, whatever was moved
+    /// goes back, so a half-moved store is never left behind. Returns whether it moved.
+    @discardableResult
+    func adoptHistoryStore(at store: URL, fileManager: FileManager = .default) -> Bool {
+        guard fileManager.fileExists(atPath: store.path),
+              !fileManager.fileExists(atPath: historyStore.path) else { return false }
+        var moves = [(from: store, to: historyStore)]
+        for suffix in Self.storeSidecarSuffixes {
+            let sidecar = URL(fileURLWithPath: store.path + suffix)
+            guard fileManager.fileExists(atPath: sidecar.path) else { continue }
+            moves.append((sidecar, URL(fileURLWithPath: historyStore.path + suffix)))
+        }
+
+        var moved: [(from: URL, to: URL)] = []
+        do {
+            try fileManager.createDirectory(at: root, withIntermediateDirectories: true)
+            for move in moves {
+                try fileManager.moveItem(at: move.from, to: move.to)
+                moved.append(move)
+            }
+        } catch {
+            Logger.shared.error("AppDirs: Could not move history store — \(error.localizedDescription)")
+            for move in moved.reversed() {
+                try? fileManager.moveItem(at: move.to, to: move.from)
+            }
+            return false
+        }
+        return true
+    }
+}
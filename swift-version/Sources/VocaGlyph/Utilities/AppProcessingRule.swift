@@ -0,0 +1,90 @@
+import Foundation
+
+// MARK: - AppProcessingRule
+
+/// Per-app overrides for `TextProcessingPipeline`, applied when that app is frontmost
+/// as the text is output — e.g. code comments only in VS Code.
+///
+/// Rules are stored one per line in the `appProcessingRules` setting:
+///
+///     com.microsoft.VSCode: style=codeComment, -llmCleanup
+///     md.obsidian: style=bullets, +numberNormalization
+///
+/// - `+stage` / `-stage` force a `TextProcessingStage` on or off. A forced-on stage
+///   still needs what it works with — `+llmCleanup` does nothing without an AI engine.
+/// - `style=<OutputStyle>` picks the output style; a spoken style command still wins.
+///
+/// `trim` always runs and can't be switched off.
+struct AppProcessingRule: Equatable {
+
+    enum ParseError: LocalizedError, Equatable {
+        case missingBundleIdentifier
+        case unknownStage(String)
+        case unknownStyle(String)
+        case unknownToken(String)
+
+        var errorDescription: String? {
+            switch self {
+            case .missingBundleIdentifier: return "Start the rule with a bundle identifier and a colon."
+            case .unknownStage(let name):  return "Unknown stage '\(name)'."
+            case .unknownStyle(let name):  return "Unknown output style '\(name)'."
+            case .unknownToken(let token): return "'\(token)' is not +stage, -stage or style=…"
+            }
+        }
+    }
+
+    var bundleIdentifier: String
+    var stageOverrides: [TextProcessingStage: Bool] = [:]
+    var outputStyle: OutputStyle?
+
+    init(bundleIdentifier: String, stageOverrides: [TextProcessingStage: Bool] = [:], outputStyle: OutputStyle? = nil) {
+        self.bundleIdentifier = bundleIdentifier
+        self.stageOverrides = stageOverrides
+        self.outputStyle = outputStyle
+    }
+
+    init(parsing line: String) throws {
+        guard let colon = line.firstIndex(of: ":") else { throw ParseError.missingBundleIdentifier }
+        let bundleIdentifier = line[..<colon].trimmingCharacters(in: .whitespaces)
+        guard !bundleIdentifier.isEmpty else { throw ParseError.missingBundleIdentifier }
+        self.bundleIdentifier = bundleIdentifier
+
+        let tokens = line[line.index(after: colon)...]
+            .split(whereSeparator: { $0 == "," || $0.isWhitespace })
+            .map(String.init)
+        for token in tokens {
+            if token.hasPrefix("style=") {
+                let name = String(token.dropFirst("style=".count))
+                guard let style = OutputStyle(rawValue: name) else { throw ParseError.unknownStyle(name) }
+                outputStyle = style
+            } else if let sign = token.first, sign == "+" || sign == "-" {
+                let name = String(token.dropFirst())
+                guard let stage = TextProcessingStage(rawValue: name), stage != .trim else {
+                    throw ParseError.unknownStage(name)
+                }
+                stageOverrides[stage] = sign == "+"
+            } else {
+                throw ParseError.unknownToken(token)
+            }
+        }
+    }
+
+    /// The rule's line form, stages in pipeline order.
+    var line: String {
+        var tokens: [String] = []
+        if let outputStyle { tokens.append("style=\(outputStyle.rawValue)") }
+        for stage in TextProcessingStage.allCases {
+            if let enabled = stageOverrides[stage] { tokens.append((enabled ? "+" : "-") + stage.rawValue) }
+        }
+        return "\(bundleIdentifier): " + tokens.joined(separator: ", ")
+    }
+
+    /// The first valid rule for `bundleIdentifier`. Lines that don't parse are skipped —
+    /// `SettingsValidator` reports them.
+    static func rule(for bundleIdentifier: String?, in lines: [String]) -> AppProcessingRule? {
+        guard let bundleIdentifier else { return nil }
+        return lines.lazy
+            .compactMap { try? AppProcessingRule(parsing: $0) }
+            .first { $0.bundleIdentifier == bundleIdentifier }
+    }
+}
@@ -0,0 +1,25 @@
+import Foundation
+
+// MARK: - AppSandbox
+
+/// Whether this process runs in the App Sandbox. The Xcode build is sandboxed and
+/// uses the hardened runtime; `swift run` builds are not. The few places that behave
+/// differently check here:
+///
+/// - Files the user picks in an open panel are reachable after a relaunch only through
+///   a security-scoped bookmark (`FileBookmarkStore`).
+/// - `~/Library/...` and `~` resolve inside the app's container, so migrations of files
+///   an unsandboxed build left in the real home directory have nothing to find.
+/// - Child processes (whisper.cpp, plugins) inherit the sandbox; tools installed outside
+///   the container, e.g. by Homebrew, can't be run.
+enum AppSandbox {
+
+    static let containerEnvironmentKey = "APP_SANDBOX_CONTAINER_ID"
+
+    static let isActive = isActive(environment: ProcessInfo.processInfo.environment)
+
+    /// The sandbox sets `APP_SANDBOX_CONTAINER_ID` in every process it confines.
+    static func isActive(environment: [String: String]) -> Bool {
+        !(environment[containerEnvironmentKey] ?? "").isEmpty
+    }
+}
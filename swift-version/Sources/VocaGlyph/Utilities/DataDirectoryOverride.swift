@@ -0,0 +1,62 @@
+import Foundation
+
+// MARK: - DataDirectoryOverride
+
+/// Resolves an optional user-chosen root for VocaGlyph's on-disk data, so models can
+/// live on an external drive.
+///
+/// Sources, highest priority first:
+/// 1. Launch flag: `--data-dir <path>` (e.g. `open -a VocaGlyph --args --data-dir /Volumes/SSD/VocaGlyph`)
+/// 2. Environment variable: `VOCAGLYPH_DATA_DIR`
+///
+/// When set, `AppDirs.current` replaces the default macOS locations with sub-directories
+/// of the root:
+///
+///     <root>/models/…   ← ~/Library/Application Support/VocaGlyph/models/…  (WhisperKit)
+///     <root>/Caches/…   ← ~/Library/Caches/VocaGlyph/…                      (MLX LLMs)
+///     <root>/Logs/…     ← ~/Library/Logs/VocaGlyph/…
+///
+/// Parakeet models stay in FluidAudio's own cache directory, which it does not expose
+/// for configuration. Settings stay in the app's `UserDefaults`, and sockets that fall
+/// back to `$TMPDIR` stay there, so an override does not make a separate instance that
+/// can run next to the default one.
+public enum DataDirectoryOverride {
+
+    static let launchArgument = "--data-dir"
+    static let environmentKey = "VOCAGLYPH_DATA_DIR"
+
+    /// The override root for this process, or `nil` to use the standard locations.
+    /// Resolved once — changing the root at runtime would split data across two places.
+    public static let root: URL? = {
+        let url = resolve(
+            arguments: ProcessInfo.processInfo.arguments,
+            environment: ProcessInfo.processInfo.environment
+        )
+        if let url {
+            try? FileManager.default.createDirectory(at: url, withIntermediateDirectories: true)
+        }
+        return url
+    }()
+
+    // MARK: - Resolution
+
+    /// Pure resolution logic, separated from `ProcessInfo` for testing.
+    /// Accepts both `--data-dir <path>` and `--data-dir=<path>`; `~` is expanded.
+    static func resolve(arguments: [String], environment: [String: String]) -> URL? {
+        var path: String?
+
+        if let index = arguments.firstIndex(of: launchArgument), index + 1 < arguments.count {
+            path = arguments[index + 1]
+        } else if let inline = arguments.first(where: { $0.hasPrefix(launchArgument + "=") }) {
+            path = String(inline.dropFirst(launchArgument.count + 1))
+        } else {
+            path = environment[environmentKey]
+        }
+
+        guard let raw = path?.trimmingCharacters(in: .whitespacesAndNewlines), !raw.isEmpty else {
+            return nil
+        }
+        let expanded = (raw as NSString).expandingTildeInPath
+        return URL(fileURLWithPath: expanded, isDirectory: true).standardizedFileURL
+    }
+}
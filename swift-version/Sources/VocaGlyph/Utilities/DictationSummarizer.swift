@@ -0,0 +1,36 @@
+import Foundation
+
+// MARK: - DictationSummarizer
+
+/// Summary mode for long dictations: past `summaryMinimumSeconds` of audio the AI
+/// engine also writes a summary, and the user picks which version to paste.
+///
+/// The summary is made from the pipeline's final text, so replacements and custom
+/// terms carry over. Without a ready AI engine, or if summarizing fails, the verbatim
+/// text is pasted as usual.
+enum DictationSummarizer {
+
+    static let minimumSecondsRange = 10...3600
+
+    static let prompt = """
+    Summarize this dictated text in the same language, as a few short sentences or \
+    bullet points. Keep names, numbers, decisions and action items. Output only the summary.
+    """
+
+    /// Whether a dictation of `duration` seconds gets a summary under `settings`.
+    static func shouldSummarize(duration: TimeInterval, settings: AppSettings) -> Bool {
+        settings.summarizeLongDictations && duration >= TimeInterval(settings.summaryMinimumSeconds)
+    }
+
+    /// The summary, or `nil` when the engine fails, times out or returns nothing.
+    static func summarize(_ text: String, engine: any PostProcessingEngine, timeout: TimeInterval = 30) async -> String? {
+        do {
+            let summary = try await LLMCleanupProcessor(engine: engine, prompt: prompt, timeout: timeout).process(text)
+                .trimmingCharacters(in: .whitespacesAndNewlines)
+            return summary.isEmpty ? nil : summary
+        } catch {
+            Logger.shared.error("DictationSummarizer: Summary failed — \(error.localizedDescription)")
+            return nil
+        }
+    }
+}
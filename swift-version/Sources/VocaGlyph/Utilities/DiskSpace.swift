@@ -0,0 +1,38 @@
+import Foundation
+
+// MARK: - DiskSpace
+
+/// Free-space and usage queries for the model directories.
+enum DiskSpace {
+
+    /// Bytes available for new files on the volume holding `url`. Uses the
+    /// "important usage" capacity, which counts purgeable space macOS will free on demand.
+    /// `url` does not need to exist yet — the nearest existing ancestor is queried.
+    static func availableCapacity(at url: URL) -> Int64? {
+        var candidate = url
+        while !FileManager.default.fileExists(atPath: candidate.path), candidate.pathComponents.count > 1 {
+            candidate.deleteLastPathComponent()
+        }
+        let values = try? candidate.resourceValues(forKeys: [.volumeAvailableCapacityForImportantUsageKey])
+        return values?.volumeAvailableCapacityForImportantUsage
+    }
+
+    /// Total allocated size of all files under `directory`, or 0 when it does not exist.
+    static func allocatedSize(of directory: URL) -> Int64 {
+        let keys: [URLResourceKey] = [.isRegularFileKey, .totalFileAllocatedSizeKey, .fileSizeKey]
+        guard let enumerator = FileManager.default.enumerator(at: directory, includingPropertiesForKeys: keys) else {
+            return 0
+        }
+        var total: Int64 = 0
+        for case let url as URL in enumerator {
+            guard let values = try? url.resourceValues(forKeys: Set(keys)), values.isRegularFile == true else { continue }
+            total += Int64(values.totalFileAllocatedSize ?? values.fileSize ?? 0)
+        }
+        return total
+    }
+
+    /// "3.1 GB"-style label used in errors and the Settings UI.
+    static func format(_ bytes: Int64) -> String {
+        ByteCountFormatter.string(fromByteCount: bytes, countStyle: .file)
+    }
+}
@@ -0,0 +1,36 @@
+import Foundation
+
+// MARK: - DownloadRateLimiter
+
+/// Token bucket shared by every download of a `ModelDownloadService`, so the cap applies
+/// to the total rate however many models download at once.
+///
+/// Readers call `consume(_:bytesPerSecond:)` after receiving each chunk. The bucket holds
+/// up to one second of tokens; a chunk larger than the balance puts it in debt and the
+/// caller sleeps until the debt is repaid. A rate of 0 disables limiting.
+actor DownloadRateLimiter {
+
+    private var tokens: Double = 0
+    private var lastRefill = Date()
+    private var lastRate: Double = 0
+
+    func consume(_ byteCount: Int, bytesPerSecond: Int64) async throws {
+        let rate = Double(bytesPerSecond)
+        guard rate > 0 else { return }
+
+        let now = Date()
+        if rate != lastRate {
+            // A new or changed cap starts with a full second of burst.
+            tokens = rate
+            lastRate = rate
+        } else {
+            tokens = min(rate, tokens + now.timeIntervalSince(lastRefill) * rate)
+        }
+        lastRefill = now
+        tokens -= Double(byteCount)
+
+        if tokens < 0 {
+            try await Task.sleep(nanoseconds: UInt64(-tokens / rate * 1_000_000_000))
+        }
+    }
+}
@@ -0,0 +1,169 @@
+import Foundation
+
+// MARK: - FillerWordRemover
+
+/// Stateless utility that strips spoken disfluencies from a transcript with token rules,
+/// so the result doesn't depend on how the engine or an LLM prompt treats them.
+///
+/// Rules, applied in order to each line:
+/// 1. **Hesitations** ("um", "uh", "erm", …) are always removed, with their comma.
+/// 2. **Discourse fillers** ("like", "you know", "I mean") are removed only when set off
+///    by commas — "is, like, working" loses it, "I like it" keeps it.
+/// 3. **Stutters** — a word repeated back to back ("I I think") or cut off with a hyphen
+///    ("th- the") — collapse to one. Words commonly doubled on purpose ("had had",
+///    "very very") are kept.
+/// 4. **Trailing "so"** that trails off ("…done, so" or "so…") is dropped.
+enum FillerWordRemover {
+
+    static let hesitations: Set<String> = [
+        "um", "umm", "uh", "uhh", "uhm", "ah", "ahh", "er", "erm", "hmm", "mm",
+    ]
+
+    static let discourseFillers: [[String]] = [["you", "know"], ["i", "mean"], ["like"]]
+
+    /// Words that are often repeated deliberately and so never count as a stutter.
+    static let intentionalRepeats: Set<String> = [
+        "had", "that", "very", "really", "no", "bye", "ha", "well", "so",
+    ]
+
+    /// Words after which a trailing "so" is meant ("I think so…"), not a filler.
+    static let soTakingWords: Set<String> = [
+        "think", "hope", "guess", "suppose", "believe", "say", "said", "do", "did", "not",
+    ]
+
+    static func remove(from text: String) -> String {
+        text.components(separatedBy: "\n")
+            .map(removeFromLine)
+            .joined(separator: "\n")
+    }
+
+    // MARK: - Tokens
+
+    /// A whitespace-separated word split into punctuation and its alphanumeric core,
+    /// e.g. `"(um,"` → `"("`, `"um"`, `","`.
+    private struct Token {
+        var leading: String
+        var core: String
+        var trailing: String
+
+        var lowered: String { core.lowercased() }
+        var text: String { leading + core + trailing }
+
+        init(_ word: Substring) {
+            let isWordCharacter: (Character) -> Bool = { $0.isLetter || $0.isNumber }
+            let start = word.firstIndex(where: isWordCharacter) ?? word.endIndex
+            let end = word.lastIndex(where: isWordCharacter).map(word.index(after:)) ?? start
+            leading = String(word[..<start])
+            core = String(word[start..<end])
+            trailing = String(word[end...])
+        }
+    }
+
+    private static let terminalPunctuation: Set<Character> = [".", "?", "!", "…"]
+
+    private static func removeFromLine(_ line: String) -> String {
+        var tokens = line.split(whereSeparator: \.isWhitespace).map(Token.init)
+        guard !tokens.isEmpty else { return line }
+        let startedUppercase = tokens[0].core.first?.isUppercase ?? false
+        let originalFirst = tokens[0].text
+
+        removeHesitations(&tokens)
+        removeDiscourseFillers(&tokens)
+        collapseStutters(&tokens)
+        removeTrailingSo(&tokens)
+
+        guard !tokens.isEmpty else { return "" }
+        // "Um, so we…" → "So we…": keep the sentence start capitalised.
+        if startedUppercase, tokens[0].text != originalFirst, let first = tokens[0].core.first {
+            tokens[0].core = first.uppercased() + tokens[0].core.dropFirst()
+        }
+        return tokens.map(\.text).joined(separator: " ")
+    }
+
+    // MARK: - Rules
+
+    private static func removeHesitations(_ tokens: inout [Token]) {
+        var index = 0
+        while index < tokens.count {
+            let token = tokens[index]
+            guard token.leading.isEmpty, hesitations.contains(token.lowered) else {
+                index += 1
+                continue
+            }
+            tokens.remove(at: index)
+            // "I think um." keeps its full stop.
+            if index > 0 { carryTerminalPunctuation(of: token, to: &tokens[index - 1]) }
+        }
+    }
+
+    private static func removeDiscourseFillers(_ tokens: inout [Token]) {
+        var index = 0
+        while index < tokens.count {
+            let afterComma = index == 0 || tokens[index - 1].trailing.hasSuffix(",")
+            guard afterComma,
+                  let phrase = discourseFillers.first(where: { matches($0, in: tokens, at: index) }) else {
+                index += 1
+                continue
+            }
+            tokens.removeSubrange(index..<index + phrase.count)
+        }
+    }
+
+    /// `phrase` starts at `index`, its words are plain except the last, which ends in a comma.
+    private static func matches(_ phrase: [String], in tokens: [Token], at index: Int) -> Bool {
+        guard index + phrase.count <= tokens.count else { return false }
+        for (offset, word) in phrase.enumerated() {
+            let token = tokens[index + offset]
+            let isLast = offset == phrase.count - 1
+            guard token.leading.isEmpty, token.lowered == word,
+                  isLast ? token.trailing == "," : token.trailing.isEmpty else { return false }
+        }
+        return true
+    }
+
+    private static func collapseStutters(_ tokens: inout [Token]) {
+        var index = 0
+        while index + 1 < tokens.count {
+            let token = tokens[index]
+            let next = tokens[index + 1]
+            let repeated = token.trailing.isEmpty
+                && next.leading.isEmpty
+                && token.lowered == next.lowered
+                && !intentionalRepeats.contains(token.lowered)
+            let cutOff = token.trailing == "-"
+                && next.lowered.hasPrefix(token.lowered)
+            if (repeated || cutOff) && !token.core.isEmpty {
+                // Keep the later word — it carries the punctuation that follows.
+                tokens.remove(at: index)
+            } else {
+                index += 1
+            }
+        }
+    }
+
+    private static func removeTrailingSo(_ tokens: inout [Token]) {
+        guard tokens.count > 1, let last = tokens.last, last.leading.isEmpty, last.lowered == "so" else { return }
+        let previousToken = tokens[tokens.count - 2]
+        let trailsOff = last.trailing.contains("…") || last.trailing.contains("...")
+        let afterPause = previousToken.trailing.hasSuffix(",")
+        guard afterPause || (trailsOff && !soTakingWords.contains(previousToken.lowered)) else { return }
+
+        tokens.removeLast()
+        var previous = tokens[tokens.count - 1]
+        if previous.trailing.hasSuffix(",") { previous.trailing.removeLast() }
+        if !trailsOff { carryTerminalPunctuation(of: last, to: &previous) }
+        tokens[tokens.count - 1] = previous
+    }
+
+    // MARK: - Helpers
+
+    /// Moves a removed token's sentence-ending punctuation onto `previous` when it has none.
+    private static func carryTerminalPunctuation(of removed: Token, to previous: inout Token) {
+        let terminal = removed.trailing.filter { terminalPunctuation.contains($0) }
+        guard !terminal.isEmpty, !previous.trailing.contains(where: terminalPunctuation.contains) else { return }
+        while previous.trailing.hasSuffix(",") || previous.trailing.hasSuffix(";") {
+            previous.trailing.removeLast()
+        }
+        previous.trailing += terminal
+    }
+}
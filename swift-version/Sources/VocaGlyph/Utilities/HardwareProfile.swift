@@ -0,0 +1,96 @@
+import Foundation
+
+// MARK: - HardwareProfile
+
+/// The parts of the host Mac that decide how heavy a transcription setup it can run.
+struct HardwareProfile: Codable, Equatable {
+    /// CPU brand string, e.g. "Apple M2 Pro" or "Intel(R) Core(TM) i7-9750H".
+    var chip: String
+    var isAppleSilicon: Bool
+    /// Performance cores on Apple Silicon; all physical cores on Intel.
+    var performanceCores: Int
+    var totalCores: Int
+    /// Installed RAM, rounded to whole gigabytes.
+    var memoryGB: Int
+
+    /// Reads the profile of the current machine via `sysctl` and `ProcessInfo`.
+    static func detect() -> HardwareProfile {
+        let info = ProcessInfo.processInfo
+        let isAppleSilicon = sysctlInt("hw.optional.arm64") == 1
+        let physical = sysctlInt("hw.physicalcpu") ?? info.processorCount
+        let performance = isAppleSilicon ? (sysctlInt("hw.perflevel0.physicalcpu") ?? physical) : physical
+        return HardwareProfile(
+            chip: sysctlString("machdep.cpu.brand_string") ?? (isAppleSilicon ? "Apple Silicon" : "Intel"),
+            isAppleSilicon: isAppleSilicon,
+            performanceCores: performance,
+            totalCores: info.processorCount,
+            memoryGB: Int((Double(info.physicalMemory) / 1_073_741_824).rounded())
+        )
+    }
+
+    private static func sysctlInt(_ name: String) -> Int? {
+        var value: Int64 = 0
+        var size = MemoryLayout<Int64>.size
+        guard sysctlbyname(name, &value, &size, nil, 0) == 0 else { return nil }
+        return Int(value)
+    }
+
+    private static func sysctlString(_ name: String) -> String? {
+        var size = 0
+        guard sysctlbyname(name, nil, &size, nil, 0) == 0, size > 0 else { return nil }
+        var buffer = [CChar](repeating: 0, count: size)
+        guard sysctlbyname(name, &buffer, &size, nil, 0) == 0 else { return nil }
+        return String(cString: buffer)
+    }
+}
+
+// MARK: - HardwareRecommendation
+
+/// Settings chosen for a `HardwareProfile`, replacing constants that were tuned on an
+/// M4 MacBook Pro and were too aggressive for 8 GB machines and Intel Macs.
+///
+/// | Machine                        | Model          | Workers | Fallbacks |
+/// |--------------------------------|----------------|---------|-----------|
+/// | Intel                          | apple-native   | 2       | 0         |
+/// | Apple Silicon, < 16 GB         | parakeet-v3    | min(P-cores, 4) | 1 |
+/// | Apple Silicon, ≥ 16 GB         | large-v3_turbo | min(P-cores, 8) | 1 |
+struct HardwareRecommendation: Codable, Equatable {
+    /// Bump when the rules below change so existing installs are re-evaluated.
+    static let rulesVersion = 1
+
+    /// Multilingual transcription model suited to this machine. `LocaleDefaults` may swap
+    /// it for an English-only variant before it is suggested.
+    var recommendedModel: String
+    /// WhisperKit `concurrentWorkerCount`.
+    var transcriptionWorkerCount: Int
+    /// WhisperKit `temperatureFallbackCount` — extra decoder passes when greedy decoding fails.
+    var temperatureFallbackCount: Int
+    /// Upper bound shown as the model-load ETA.
+    var estimatedLoadSeconds: Double
+
+    init(profile: HardwareProfile) {
+        let cores = max(profile.performanceCores, 1)
+        if !profile.isAppleSilicon {
+            recommendedModel = "apple-native"
+            transcriptionWorkerCount = min(cores, 2)
+            temperatureFallbackCount = 0
+            estimatedLoadSeconds = 90
+        } else if profile.memoryGB < 16 {
+            recommendedModel = "parakeet-v3"
+            transcriptionWorkerCount = min(cores, 4)
+            temperatureFallbackCount = 1
+            estimatedLoadSeconds = 50
+        } else {
+            recommendedModel = "large-v3_turbo"
+            transcriptionWorkerCount = min(cores, 8)
+            temperatureFallbackCount = 1
+            estimatedLoadSeconds = 35
+        }
+    }
+
+    /// The recommendation for this machine, from the stored evaluation when available.
+    static var current: HardwareRecommendation {
+        HardwareDefaultsService.storedEvaluation()?.recommendation
+            ?? HardwareRecommendation(profile: .detect())
+    }
+}
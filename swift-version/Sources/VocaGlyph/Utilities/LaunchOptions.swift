@@ -0,0 +1,185 @@
+import Foundation
+import CoreGraphics
+
+// MARK: - LaunchOptions
+
+/// Command-line flags that override persisted settings for a single run, for scripted
+/// launches and debugging:
+///
+///     open -a VocaGlyph --args --model small --language de --hotkey cmd+shift+d --hidden
+///
+/// | Flag                  | Effect                                                      |
+/// |-----------------------|-------------------------------------------------------------|
+/// | `--model <id>`        | Transcription model id (e.g. `small`, `parakeet-v3`)         |
+/// | `--language <code>`   | Whisper language code (`en`, `de`, …) or `auto`              |
+/// | `--hotkey <combo>`    | Shortcut such as `cmd+shift+d` or `ctrl+alt` (modifier-only)  |
+/// | `--hidden`            | Start without presenting any window                          |
+/// | `--launched-at-login` | Login launch: as `--hidden`, for LaunchAgents and scripts    |
+/// | `--debug[=<port>]`    | Serve `DebugServer` on localhost (default port 6060)         |
+/// | `--replay <file.wav>` | Dictate a recorded WAV file once the model has loaded        |
+///
+/// Both `--flag value` and `--flag=value` are accepted. Unknown flags are ignored so
+/// AppKit's own launch arguments (`-NSDocumentRevisionsDebugMode`, …) and `--data-dir`
+/// pass through untouched.
+struct LaunchOptions: Equatable {
+    var model: String?
+    var language: String?
+    var shortcutKeyCode: Int?
+    var shortcutModifiers: UInt64?
+    var hidden = false
+    /// `--launched-at-login`. Login items registered with `SMAppService` can't pass
+    /// arguments; see `LoginItemService.isLoginLaunch()` for those.
+    var launchedAtLogin = false
+    /// `--debug`: the localhost port for `DebugServer`; `nil` when not requested.
+    var debugPort: UInt16?
+    /// `--replay`: a WAV file to run through the dictation pipeline, as the control API's
+    /// `replay` command does.
+    var replayFile: String?
+    /// Human-readable problems with recognised flags (bad values, missing values).
+    var errors: [String] = []
+
+    var isEmpty: Bool {
+        model == nil && language == nil && shortcutKeyCode == nil && !hidden
+    }
+
+    /// The overrides to pass to `SettingsStore.applyLaunchOverrides(_:)`.
+    var settingsOverrides: [AppSettings.Key: Any] {
+        var values: [AppSettings.Key: Any] = [:]
+        if let model { values[.selectedModel] = model }
+        if let language { values[.dictationLanguage] = language }
+        if let shortcutKeyCode { values[.shortcutKeyCode] = NSNumber(value: shortcutKeyCode) }
+        if let shortcutModifiers { values[.shortcutModifiers] = NSNumber(value: shortcutModifiers) }
+        return values
+    }
+
+    // MARK: - Parsing
+
+    static func parse(_ arguments: [String]) -> LaunchOptions {
+        var options = LaunchOptions()
+        var index = arguments.startIndex
+
+        /// Returns the value for `flag` from either `--flag=value` or the next argument.
+        func value(for flag: String, inline: String?) -> String? {
+            if let inline { return inline }
+            let next = arguments.index(after: index)
+            guard next < arguments.endIndex, !arguments[next].hasPrefix("--") else {
+                options.errors.append("\(flag) requires a value.")
+                return nil
+            }
+            index = next
+            return arguments[next]
+        }
+
+        while index < arguments.endIndex {
+            let argument = arguments[index]
+            let parts = argument.split(separator: "=", maxSplits: 1).map(String.init)
+            let flag = parts[0]
+            let inline = parts.count > 1 ? parts[1] : nil
+
+            switch flag {
+            case "--model":
+                if let model = value(for: flag, inline: inline) {
+                    if SettingsValidator.isKnownTranscriptionModel(model) {
+                        options.model = model
+                    } else {
+                        options.errors.append("--model: unknown model '\(model)'.")
+                    }
+                }
+            case "--language":
+                if let code = value(for: flag, inline: inline) {
+                    if let label = WhisperService.languageLabel(forCode: code)
+                        ?? WhisperService.supportedDictationLanguages.first(where: { $0 == code }) {
+                        options.language = label
+                    } else {
+                        options.errors.append("--language: unsupported language '\(code)'.")
+                    }
+                }
+            case "--hotkey":
+                if let combo = value(for: flag, inline: inline) {
+                    if let shortcut = parseShortcut(combo) {
+                        options.shortcutKeyCode = Int(shortcut.keyCode)
+                        options.shortcutModifiers = shortcut.modifiers.rawValue
+                    } else {
+                        options.errors.append("--hotkey: cannot parse '\(combo)'.")
+                    }
+                }
+            case "--hidden":
+                options.hidden = true
+            case LoginItemService.launchedAtLoginArgument:
+                options.launchedAtLogin = true
+                options.hidden = true
+            case "--debug":
+                // Only the inline form takes a port, so `--debug` can precede other flags.
+                if let inline {
+                    if let port = UInt16(inline), port > 0 {
+                        options.debugPort = port
+                    } else {
+                        options.errors.append("--debug: invalid port '\(inline)'.")
+                    }
+                } else {
+                    options.debugPort = DebugServer.defaultPort
+                }
+            case "--replay":
+                if let path = value(for: flag, inline: inline) {
+                    options.replayFile = (path as NSString).expandingTildeInPath
+                }
+            default:
+                break
+            }
+            index = arguments.index(after: index)
+        }
+
+        return options
+    }
+
+    // MARK: - Shortcut Parsing
+
+    private static let modifierAliases: [String: CGEventFlags] = [
+        "cmd": .maskCommand, "command": .maskCommand, "⌘": .maskCommand,
+        "shift": .maskShift, "⇧": .maskShift,
+        "ctrl": .maskControl, "control": .maskControl, "⌃": .maskControl,
+        "alt": .maskAlternate, "opt": .maskAlternate, "option": .maskAlternate, "⌥": .maskAlternate,
+        "caps": .maskAlphaShift, "capslock": .maskAlphaShift, "⇪": .maskAlphaShift
+    ]
+
+    private static let keyAliases: [String: CGKeyCode] = [
+        "space": 49, "return": 36, "enter": 36, "tab": 48, "delete": 51, "backspace": 51,
+        "esc": 53, "escape": 53, "left": 123, "right": 124, "down": 125, "up": 126
+    ]
+
+    /// Parses `cmd+shift+d`-style combos. At least one modifier is required; a combo with
+    /// only modifiers becomes a modifier-only shortcut (`kModifierOnlyKeyCode`).
+    static func parseShortcut(_ combo: String) -> (keyCode: CGKeyCode, modifiers: CGEventFlags)? {
+        let tokens = combo.lowercased()
+            .split(separator: "+")
+            .map { $0.trimmingCharacters(in: .whitespaces) }
+            .filter { !$0.isEmpty }
+        guard !tokens.isEmpty else { return nil }
+
+        var modifiers = CGEventFlags()
+        var keyCode: CGKeyCode?
+
+        for token in tokens {
+            if let modifier = modifierAliases[token] {
+                modifiers.insert(modifier)
+            } else if keyCode == nil, let code = keyCodeFor(token) {
+                keyCode = code
+            } else {
+                return nil // unknown token or a second non-modifier key
+            }
+        }
+
+        guard !modifiers.isEmpty else { return nil }
+        return (keyCode ?? kModifierOnlyKeyCode, modifiers)
+    }
+
+    /// Reverse lookup of `ShortcutDisplayHelper.keyName(for:)` plus a few spelled-out aliases.
+    private static func keyCodeFor(_ token: String) -> CGKeyCode? {
+        if let alias = keyAliases[token] { return alias }
+        for code in CGKeyCode(0)...CGKeyCode(127) {
+            let name = ShortcutDisplayHelper.keyName(for: code)
+            if !name.hasPrefix("Key"), name.lowercased() == token { return code }
+        }
+        return nil
+    }
+}
@@ -0,0 +1,80 @@
+import Foundation
+
+// MARK: - LocaleDefaults
+
+/// Picks the initial dictation language from the macOS preferred languages, so a
+/// German-speaking user starts with German instead of auto-detection guessing per clip.
+///
+/// Runs once, on a real first launch: a stored `dictationLanguage`, `selectedModel`
+/// (seeded by every earlier launch) or onboarding progress means an existing install,
+/// whose "Auto-Detect" is left alone.
+enum LocaleDefaults {
+
+    /// Models that only transcribe English. Never recommended for other languages.
+    static let englishOnlyModels: Set<String> = ["parakeet-v2", "distil-whisper_distil-large-v3"]
+
+    /// Maps the first supported entry of `preferredLanguages` (BCP-47, e.g. "de-CH",
+    /// "id-ID", "zh-Hans-CN") to a Settings label. Unsupported languages fall back to
+    /// "Auto-Detect".
+    static func dictationLanguage(forPreferredLanguages preferredLanguages: [String]) -> String {
+        for identifier in preferredLanguages {
+            let code = Locale(identifier: identifier).language.languageCode?.identifier
+                ?? String(identifier.prefix(2))
+            if let label = WhisperService.languageLabel(forCode: code) {
+                return label
+            }
+        }
+        return "Auto-Detect"
+    }
+
+    /// Adjusts a hardware recommendation for `language`: English dictation on a smaller
+    /// Mac gets the lighter English-only Parakeet model; any other language always gets a
+    /// multilingual model.
+    static func recommendedModel(_ hardwareModel: String, language: String) -> String {
+        let isEnglish = WhisperService.languageCode(for: language) == "en"
+        if isEnglish, hardwareModel == "parakeet-v3" {
+            return "parakeet-v2"
+        }
+        if !isEnglish, englishOnlyModels.contains(hardwareModel) {
+            return hardwareModel == "parakeet-v2" ? "parakeet-v3" : "large-v3_turbo"
+        }
+        return hardwareModel
+    }
+
+    /// The model onboarding and the Model tab should suggest for this machine and language.
+    static var recommendedModel: String {
+        recommendedModel(HardwareRecommendation.current.recommendedModel,
+                         language: SettingsStore.shared.settings.dictationLanguage)
+    }
+
+    /// Keys any earlier launch has written. Must be checked before the launch seeds
+    /// `selectedModel`.
+    static let existingInstallKeys = [
+        AppSettings.Key.dictationLanguage.rawValue,
+        AppSettings.Key.selectedModel.rawValue,
+        OnboardingCoordinator.completedStepsKey,
+    ]
+
+    /// True when none of `existingInstallKeys` is stored.
+    static func isFirstLaunch(defaults: UserDefaults = .standard) -> Bool {
+        existingInstallKeys.allSatisfy { defaults.object(forKey: $0) == nil }
+    }
+
+    /// Writes the locale-derived language on first run. Returns the label written, or
+    /// `nil` on an existing install.
+    @discardableResult
+    static func applyIfNeeded(store: SettingsStore = .shared,
+                              defaults: UserDefaults = .standard,
+                              preferredLanguages: [String] = Locale.preferredLanguages) -> String? {
+        guard isFirstLaunch(defaults: defaults) else { return nil }
+
+        let language = dictationLanguage(forPreferredLanguages: preferredLanguages)
+        // Write even "Auto-Detect" so this runs only once.
+        store.update { $0.dictationLanguage = language }
+        if defaults.object(forKey: AppSettings.Key.dictationLanguage.rawValue) == nil {
+            defaults.set(language, forKey: AppSettings.Key.dictationLanguage.rawValue)
+        }
+        Logger.shared.info("LocaleDefaults: First launch — dictation language '\(language)' from preferred languages \(preferredLanguages.prefix(3))")
+        return language
+    }
+}
@@ -0,0 +1,140 @@
+import Foundation
+
+// MARK: - LogLevel
+
+enum LogLevel: Int, CaseIterable, Comparable {
+    case debug
+    case info
+    case error
+
+    /// As written in log lines, padded to the same width.
+    var label: String {
+        switch self {
+        case .debug: return "DEBUG"
+        case .info:  return "INFO "
+        case .error: return "ERROR"
+        }
+    }
+
+    /// As written in the `logLevels` setting.
+    var name: String { label.trimmingCharacters(in: .whitespaces).lowercased() }
+
+    init?(name: String) {
+        guard let level = Self.allCases.first(where: { $0.name == name.lowercased() }) else { return nil }
+        self = level
+    }
+
+    static func < (lhs: LogLevel, rhs: LogLevel) -> Bool { lhs.rawValue < rhs.rawValue }
+}
+
+// MARK: - LogComponent
+
+/// The part of the app a log line is about, written as a field on every line so one
+/// subsystem can be followed — or turned up — on its own.
+enum LogComponent: String, CaseIterable {
+    case app
+    case audio
+    case whisper
+    case hotkey
+    case model
+    case output
+
+    /// Components of the types that log with a `"TypeName: "` prefix. Anything not
+    /// listed is `.app`.
+    private static let prefixes: [String: LogComponent] = [
+        "AudioRecorder": .audio,
+        "AudioRecorderService": .audio,
+        "MicrophoneService": .audio,
+        "WhisperService": .whisper,
+        "ParakeetService": .whisper,
+        "NativeSpeechEngine": .whisper,
+        "EngineRouter": .whisper,
+        "HotkeyService": .hotkey,
+        "ExternalTriggerService": .hotkey,
+        "ModelDownloadService": .model,
+        "ModelRegistry": .model,
+        "ModelChecksumVerifier": .model,
+        "ModelsAPI": .model,
+        "LocalLLMEngine": .model,
+        "OutputService": .output,
+        "ContextCaptureService": .output,
+        "TextProcessingPipeline": .output,
+        "Transcription": .output,
+    ]
+
+    /// The component named by a message's `"TypeName: "` prefix.
+    static func component(forMessage message: String) -> LogComponent {
+        guard let colon = message.firstIndex(of: ":") else { return .app }
+        return prefixes[String(message[..<colon])] ?? .app
+    }
+}
+
+// MARK: - LogLevels
+
+/// The lowest level logged per component, from the `logLevels` setting:
+///
+///     audio=debug
+///     whisper=error
+///
+/// Components without an entry log from `.debug` when debug logging is on and from
+/// `.info` otherwise.
+struct LogLevels: Equatable {
+
+    enum ParseError: LocalizedError, Equatable {
+        case malformed(String)
+        case unknownComponent(String)
+        case unknownLevel(String)
+
+        var errorDescription: String? {
+            switch self {
+            case .malformed(let line):       return "'\(line)' is not component=level."
+            case .unknownComponent(let name): return "Unknown log component '\(name)'."
+            case .unknownLevel(let name):    return "Unknown log level '\(name)'."
+            }
+        }
+    }
+
+    var defaultLevel: LogLevel
+    var overrides: [LogComponent: LogLevel] = [:]
+
+    init(defaultLevel: LogLevel, overrides: [LogComponent: LogLevel] = [:]) {
+        self.defaultLevel = defaultLevel
+        self.overrides = overrides
+    }
+
+    /// Throws `ParseError` for the first line that isn't `component=level`.
+    init(parsing lines: [String], debugLogging: Bool) throws {
+        defaultLevel = debugLogging ? .debug : .info
+        for line in lines {
+            let parts = line.split(separator: "=", omittingEmptySubsequences: false)
+                .map { $0.trimmingCharacters(in: .whitespaces) }
+            guard parts.count == 2 else { throw ParseError.malformed(line) }
+            guard let component = LogComponent(rawValue: parts[0].lowercased()) else {
+                throw ParseError.unknownComponent(parts[0])
+            }
+            guard let level = LogLevel(name: parts[1]) else { throw ParseError.unknownLevel(parts[1]) }
+            overrides[component] = level
+        }
+    }
+
+    /// Invalid lines are skipped; `SettingsValidator` reports them.
+    init(settings: AppSettings) {
+        self.init(defaultLevel: settings.enableDebugLogging ? .debug : .info)
+        for line in settings.logLevels {
+            if let parsed = try? LogLevels(parsing: [line], debugLogging: false) {
+                overrides.merge(parsed.overrides) { _, new in new }
+            }
+        }
+    }
+
+    func allows(_ level: LogLevel, for component: LogComponent) -> Bool {
+        level >= (overrides[component] ?? defaultLevel)
+    }
+
+    /// Back to `logLevels` lines, sorted by component.
+    var lines: [String] {
+        LogComponent.allCases.compactMap { component in
+            overrides[component].map { "\(component.rawValue)=\($0.name)" }
+        }
+    }
+}
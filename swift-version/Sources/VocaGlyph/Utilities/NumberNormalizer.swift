@@ -0,0 +1,364 @@
+import Foundation
+
+// MARK: - NumberNormalizer
+
+/// Stateless utility that rewrites spoken numbers, ordinals, times and dates as digits.
+///
+///     "twenty five people"              → "25 people"
+///     "the twenty first floor"          → "the 21st floor"
+///     "three thirty pm"                 → "3:30 PM"
+///     "march third twenty twenty five"  → "March 3, 2025"
+///     "tres de marzo de dos mil veinticinco" → "3 de marzo de 2025"
+///
+/// Conservative by design:
+/// - A lone number below ten ("one of them", "first try") stays a word unless it is part
+///   of a date or time.
+/// - Two adjacent groups such as "nineteen eighty four" read as a year (1984); other
+///   sequences ("one two three") become separate numbers.
+/// - Month names only become dates next to a day ordinal or a year, so "we march ten
+///   miles" keeps its verb.
+///
+/// Rules are per language (`Language.english`, `Language.spanish`). Text in other
+/// languages passes through unchanged.
+enum NumberNormalizer {
+
+    /// How a number word combines with its neighbours.
+    enum Kind {
+        /// 0–9
+        case unit
+        /// 10–19
+        case teen
+        /// 20, 30 … 90
+        case tens
+        /// A single word for 21–29 ("veintitrés").
+        case tensUnit
+        /// "hundred" — multiplies the value so far.
+        case hundredMultiplier
+        /// A single word for a multiple of 100 ("doscientos").
+        case hundreds
+        /// "thousand", "million", "mil" …
+        case scale
+        /// "oh" in "nineteen oh five" or "three oh five" — a leading zero.
+        case zeroPrefix
+    }
+
+    struct Language {
+        let code: String
+        let cardinals: [String: (value: Int, kind: Kind)]
+        let ordinals: [String: (value: Int, kind: Kind)]
+        /// Words skipped between two number words, e.g. "and" in "one hundred and five".
+        let connectors: Set<String>
+        /// Lowercased month names, January first.
+        let months: [String]
+        /// Word linking a day to its month: "of" ("the third of March"), "de".
+        let dayMonthLink: String
+        /// Whether `formatDates` rewrites "month day(, year)" into "Month D, YYYY".
+        let usesMonthFirstDates: Bool
+        /// Whether times ("three thirty pm", "seven o'clock") are recognised.
+        let recognisesTimes: Bool
+        let ordinalSuffix: @Sendable (Int) -> String
+
+        func entry(for word: String) -> (value: Int, kind: Kind, isOrdinal: Bool)? {
+            if let cardinal = cardinals[word] { return (cardinal.value, cardinal.kind, false) }
+            if let ordinal = ordinals[word] { return (ordinal.value, ordinal.kind, true) }
+            return nil
+        }
+
+        /// Rules for a WhisperKit language code. `nil` (auto-detect) uses English.
+        static func forCode(_ code: String?) -> Language? {
+            switch code {
+            case nil, "en": return .english
+            case "es":      return .spanish
+            default:        return nil
+            }
+        }
+    }
+
+    // MARK: - Normalize
+
+    static func normalize(_ text: String, language: Language) -> String {
+        formatDates(convertNumbers(in: text, language: language), language: language)
+    }
+
+    // MARK: - Number Runs
+
+    private struct Group {
+        var total = 0
+        var current = 0
+        var last: Kind?
+        var isOrdinal = false
+        /// Started with "oh": rendered with a leading zero.
+        var hasLeadingZero = false
+        var value: Int { total + current }
+
+        func accepts(_ kind: Kind) -> Bool {
+            guard !isOrdinal else { return false }
+            if hasLeadingZero { return last == .zeroPrefix && kind == .unit }
+            guard let last else { return true }
+            switch kind {
+            case .unit:
+                return [.tens, .hundredMultiplier, .hundreds, .scale].contains(last)
+            case .teen, .tens, .tensUnit:
+                return [.hundredMultiplier, .hundreds, .scale].contains(last)
+            case .hundredMultiplier:
+                return [.unit, .teen].contains(last)
+            case .hundreds:
+                return last == .scale
+            case .scale:
+                return last != .scale && last != .zeroPrefix
+            case .zeroPrefix:
+                return false
+            }
+        }
+
+        mutating func add(_ value: Int, _ kind: Kind, isOrdinal: Bool) {
+            switch kind {
+            case .hundredMultiplier:
+                current = max(current, 1) * 100
+            case .scale:
+                total += max(current, 1) * value
+                current = 0
+            case .zeroPrefix:
+                hasLeadingZero = true
+            default:
+                current += value
+            }
+            last = kind
+            self.isOrdinal = isOrdinal
+        }
+    }
+
+    private static let wordRegex = try! NSRegularExpression(pattern: #"\p{L}+(?:['’]\p{L}+)?"#)
+    private static let meridiemRegex = try! NSRegularExpression(
+        pattern: #"^\s+([ap])\.?\s?m\b\.?"#, options: .caseInsensitive)
+    private static let oClockRegex = try! NSRegularExpression(
+        pattern: #"^\s+o['’]clock\b"#, options: .caseInsensitive)
+
+    private static func convertNumbers(in text: String, language: Language) -> String {
+        let ns = text as NSString
+        let words = wordRegex.matches(in: text, range: NSRange(location: 0, length: ns.length)).map(\.range)
+        let lowered = words.map { ns.substring(with: $0).lowercased() }
+
+        /// Number words may only be separated by spaces or a hyphen ("twenty-five").
+        func adjacent(_ a: Int, _ b: Int) -> Bool {
+            let gap = ns.substring(with: NSRange(location: NSMaxRange(words[a]),
+                                                 length: words[b].location - NSMaxRange(words[a])))
+            return gap.allSatisfy { $0 == " " || $0 == "-" } && !gap.isEmpty
+        }
+
+        var result = ""
+        var cursor = 0
+        var index = 0
+        while index < words.count {
+            guard let first = language.entry(for: lowered[index]), first.kind != .zeroPrefix else {
+                index += 1
+                continue
+            }
+
+            // Collect the run of number words starting here, split into groups.
+            var groups = [Group()]
+            var end = index
+            var position = index
+            while position < words.count {
+                var candidate = position
+                if position > index {
+                    guard adjacent(position - 1, position) else { break }
+                    if language.connectors.contains(lowered[position]),
+                       position + 1 < words.count, adjacent(position, position + 1) {
+                        candidate = position + 1
+                    }
+                }
+                guard let entry = language.entry(for: lowered[candidate]) else { break }
+                if candidate != position {
+                    // Connectors only join within a group ("one hundred and five", "treinta y dos").
+                    guard groups[groups.count - 1].accepts(entry.kind) else { break }
+                }
+                if entry.kind == .zeroPrefix {
+                    // "oh" needs a number before it and a digit after it.
+                    guard candidate + 1 < words.count, adjacent(candidate, candidate + 1),
+                          language.cardinals[lowered[candidate + 1]]?.kind == .unit else { break }
+                }
+                if !groups[groups.count - 1].accepts(entry.kind) {
+                    // A new group starts with a plain number: "nineteen | eighty four".
+                    guard !groups[groups.count - 1].isOrdinal,
+                          [.unit, .teen, .tens, .tensUnit, .zeroPrefix].contains(entry.kind) else { break }
+                    groups.append(Group())
+                }
+                groups[groups.count - 1].add(entry.value, entry.kind, isOrdinal: entry.isOrdinal)
+                end = candidate
+                position = candidate + 1
+            }
+
+            let runStart = words[index].location
+            var runEnd = NSMaxRange(words[end])
+            let following = NSRange(location: runEnd, length: ns.length - runEnd)
+            let previousWord = index > 0 ? lowered[index - 1] : nil
+            let isDateContext = previousWord.map { language.months.contains($0) } == true
+                || (end + 2 < words.count && lowered[end + 1] == language.dayMonthLink
+                    && language.months.contains(lowered[end + 2]))
+
+            var rendered: String?
+            if language.recognisesTimes, groups.count <= 2, (1...12).contains(groups[0].value),
+               !groups.contains(where: \.isOrdinal),
+               groups.count == 1 || (0...59).contains(groups[1].value) {
+                let minutes = groups.count == 2 ? String(format: "%02d", groups[1].value) : "00"
+                if let meridiem = meridiemRegex.firstMatch(in: text, range: following) {
+                    let letter = ns.substring(with: meridiem.range(at: 1)).uppercased()
+                    rendered = groups.count == 2 ? "\(groups[0].value):\(minutes) \(letter)M" : "\(groups[0].value) \(letter)M"
+                    runEnd = NSMaxRange(meridiem.range)
+                } else if let oClock = oClockRegex.firstMatch(in: text, range: following), groups.count == 1 {
+                    rendered = "\(groups[0].value):00"
+                    runEnd = NSMaxRange(oClock.range)
+                }
+            }
+            if rendered == nil, groups.count == 2, !groups.contains(where: \.isOrdinal),
+               (10...99).contains(groups[0].value), !groups[0].hasLeadingZero,
+               (0...99).contains(groups[1].value), groups[1].value >= 10 || groups[1].hasLeadingZero {
+                rendered = "\(groups[0].value)\(String(format: "%02d", groups[1].value))"
+            }
+            if rendered == nil {
+                if groups.count == 1, groups[0].value < 10, !isDateContext {
+                    index = end + 1
+                    continue
+                }
+                rendered = groups.map { group in
+                    if group.isOrdinal { return "\(group.value)\(language.ordinalSuffix(group.value))" }
+                    return group.hasLeadingZero ? "0\(group.value)" : String(group.value)
+                }.joined(separator: " ")
+            }
+
+            result += ns.substring(with: NSRange(location: cursor, length: runStart - cursor))
+            result += rendered ?? ns.substring(with: NSRange(location: runStart, length: runEnd - runStart))
+            cursor = runEnd
+            index = end + 1
+            while index < words.count, words[index].location < cursor { index += 1 }
+        }
+        result += ns.substring(from: cursor)
+        return result
+    }
+
+    // MARK: - Dates
+
+    private static func formatDates(_ text: String, language: Language) -> String {
+        guard language.usesMonthFirstDates else { return text }
+        let months = language.months.joined(separator: "|")
+        let patterns = [
+            // "March 3rd", "March 3rd, 2025", "March 3 2025"
+            #"\b(\#(months))\s+(\d{1,2})(st|nd|rd|th)?(?:,?\s+(\d{4}))?\b"#,
+            // "the 3rd of March", "the 3rd of March 2025"
+            #"\bthe\s+(\d{1,2})(st|nd|rd|th)\s+\#(language.dayMonthLink)\s+(\#(months))(?:,?\s+(\d{4}))?\b"#,
+        ]
+        var result = text
+        for (index, pattern) in patterns.enumerated() {
+            guard let regex = try? NSRegularExpression(pattern: pattern, options: .caseInsensitive) else { continue }
+            let ns = result as NSString
+            let matches = regex.matches(in: result, range: NSRange(location: 0, length: ns.length))
+            for match in matches.reversed() {
+                func group(_ i: Int) -> String? {
+                    let range = match.range(at: i)
+                    return range.location == NSNotFound ? nil : ns.substring(with: range)
+                }
+                let (month, day, suffix, year) = index == 0
+                    ? (group(1), group(2), group(3), group(4))
+                    : (group(3), group(1), group(2), group(4))
+                // A month name alone is too ambiguous ("may", "march") — require a day
+                // ordinal or a year.
+                guard let month, let day, let dayValue = Int(day), (1...31).contains(dayValue),
+                      suffix != nil || year != nil else { continue }
+                var formatted = "\(month.prefix(1).uppercased())\(month.dropFirst().lowercased()) \(dayValue)"
+                if let year { formatted += ", \(year)" }
+                result = (result as NSString).replacingCharacters(in: match.range, with: formatted)
+            }
+        }
+        return result
+    }
+}
+
+// MARK: - Languages
+
+extension NumberNormalizer.Language {
+
+    static let english: NumberNormalizer.Language = {
+        var cardinals: [String: (value: Int, kind: NumberNormalizer.Kind)] = [
+            "hundred": (100, .hundredMultiplier),
+            "thousand": (1_000, .scale), "million": (1_000_000, .scale), "billion": (1_000_000_000, .scale),
+            "oh": (0, .zeroPrefix),
+        ]
+        let units = ["zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine"]
+        let teens = ["ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+                     "seventeen", "eighteen", "nineteen"]
+        let tens = ["twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"]
+        for (value, word) in units.enumerated() { cardinals[word] = (value, .unit) }
+        for (value, word) in teens.enumerated() { cardinals[word] = (value + 10, .teen) }
+        for (value, word) in tens.enumerated() { cardinals[word] = ((value + 2) * 10, .tens) }
+
+        var ordinals: [String: (value: Int, kind: NumberNormalizer.Kind)] = [
+            "first": (1, .unit), "second": (2, .unit), "third": (3, .unit), "fourth": (4, .unit),
+            "fifth": (5, .unit), "sixth": (6, .unit), "seventh": (7, .unit), "eighth": (8, .unit),
+            "ninth": (9, .unit), "twelfth": (12, .teen),
+            "hundredth": (100, .hundredMultiplier), "thousandth": (1_000, .scale), "millionth": (1_000_000, .scale),
+        ]
+        for (value, word) in teens.enumerated() where word != "twelve" { ordinals[word + "th"] = (value + 10, .teen) }
+        for (value, word) in tens.enumerated() {
+            ordinals[String(word.dropLast()) + "ieth"] = ((value + 2) * 10, .tens)
+        }
+
+        return NumberNormalizer.Language(
+            code: "en",
+            cardinals: cardinals,
+            ordinals: ordinals,
+            connectors: ["and"],
+            months: ["january", "february", "march", "april", "may", "june", "july",
+                     "august", "september", "october", "november", "december"],
+            dayMonthLink: "of",
+            usesMonthFirstDates: true,
+            recognisesTimes: true,
+            ordinalSuffix: { value in
+                if (11...13).contains(value % 100) { return "th" }
+                switch value % 10 {
+                case 1:  return "st"
+                case 2:  return "nd"
+                case 3:  return "rd"
+                default: return "th"
+                }
+            }
+        )
+    }()
+
+    static let spanish: NumberNormalizer.Language = {
+        var cardinals: [String: (value: Int, kind: NumberNormalizer.Kind)] = [
+            "un": (1, .unit), "una": (1, .unit),
+            "cien": (100, .hundreds), "ciento": (100, .hundreds), "quinientos": (500, .hundreds),
+            "setecientos": (700, .hundreds), "novecientos": (900, .hundreds),
+            "mil": (1_000, .scale), "millón": (1_000_000, .scale), "millones": (1_000_000, .scale),
+        ]
+        let units = ["cero", "uno", "dos", "tres", "cuatro", "cinco", "seis", "siete", "ocho", "nueve"]
+        let teens = ["diez", "once", "doce", "trece", "catorce", "quince", "dieciséis",
+                     "diecisiete", "dieciocho", "diecinueve"]
+        let twenties = ["veintiuno", "veintidós", "veintitrés", "veinticuatro", "veinticinco",
+                        "veintiséis", "veintisiete", "veintiocho", "veintinueve"]
+        let tens = ["veinte", "treinta", "cuarenta", "cincuenta", "sesenta", "setenta", "ochenta", "noventa"]
+        for (value, word) in units.enumerated() { cardinals[word] = (value, .unit) }
+        for (value, word) in teens.enumerated() { cardinals[word] = (value + 10, .teen) }
+        for (value, word) in twenties.enumerated() { cardinals[word] = (value + 21, .tensUnit) }
+        cardinals["veintiún"] = (21, .tensUnit)
+        for (value, word) in tens.enumerated() { cardinals[word] = ((value + 2) * 10, .tens) }
+        for (value, prefix) in [(2, "dos"), (3, "tres"), (4, "cuatro"), (6, "seis"), (8, "ocho")] {
+            cardinals[prefix + "cientos"] = (value * 100, .hundreds)
+        }
+
+        return NumberNormalizer.Language(
+            code: "es",
+            cardinals: cardinals,
+            ordinals: ["primero": (1, .unit)],
+            connectors: ["y"],
+            months: ["enero", "febrero", "marzo", "abril", "mayo", "junio", "julio",
+                     "agosto", "septiembre", "octubre", "noviembre", "diciembre"],
+            dayMonthLink: "de",
+            usesMonthFirstDates: false,
+            recognisesTimes: false,
+            // "primero de marzo" is written "1 de marzo" — no suffix.
+            ordinalSuffix: { _ in "" }
+        )
+    }()
+}
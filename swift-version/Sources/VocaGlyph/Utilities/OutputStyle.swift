@@ -0,0 +1,148 @@
+import Foundation
+
+// MARK: - OutputStyle
+
+/// Shape the final text is put into before output: prose, email sentences, a
+/// hyphen bullet list or `//` code comment lines.
+///
+/// The style comes from the `outputStyle` setting (tray menu) or, for a single
+/// dictation, from a spoken command at its start — "bullet list, milk eggs bread".
+enum OutputStyle: String, CaseIterable {
+    case plain
+    case email
+    case bullets
+    case codeComment
+
+    /// Column `codeComment` wraps at, including the `// ` prefix.
+    static let commentWidth = 80
+
+    var title: String {
+        switch self {
+        case .plain:       return "Plain"
+        case .email:       return "Email"
+        case .bullets:     return "Bullet List"
+        case .codeComment: return "Code Comment"
+        }
+    }
+
+    // MARK: - Spoken Commands
+
+    /// Phrases that select a style when spoken first. "Email" or "bullets" alone is not
+    /// enough — "email John about…" and "bullets were flying…" are ordinary dictation.
+    static let spokenCommands: [(phrase: String, style: OutputStyle)] = [
+        ("plain style", .plain),
+        ("email style", .email), ("email mode", .email),
+        ("bullet list", .bullets), ("bullet points", .bullets),
+        ("code comment", .codeComment), ("comment style", .codeComment),
+    ]
+
+    /// Splits a leading style command off `text`, e.g. "Bullet list: milk, eggs" →
+    /// `(.bullets, "milk, eggs")`. Returns `nil` when the text doesn't start with one.
+    static func spokenCommand(in text: String) -> (style: OutputStyle, remainder: String)? {
+        let trimmed = text.trimmingCharacters(in: .whitespacesAndNewlines)
+        for (phrase, style) in spokenCommands {
+            guard let range = trimmed.range(of: phrase, options: [.caseInsensitive, .anchored]) else { continue }
+            let rest = trimmed[range.upperBound...]
+            // Whole words only: "bulletsproof" is not a command.
+            if let next = rest.first, next.isLetter || next.isNumber { continue }
+            let remainder = rest.drop(while: { $0.isWhitespace || $0.isPunctuation })
+            return (style, String(remainder))
+        }
+        return nil
+    }
+
+    // MARK: - Formatting
+
+    func apply(_ text: String) -> String {
+        let text = text.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !text.isEmpty else { return text }
+        switch self {
+        case .plain:
+            return text
+        case .email:
+            return Self.paragraphs(of: text)
+                .map { Self.sentences(of: $0).map(Self.asSentence).joined(separator: " ") }
+                .joined(separator: "\n\n")
+        case .bullets:
+            return Self.listItems(of: text)
+                .map { "- " + Self.capitalizingFirst(Self.droppingTrailingPeriod($0)) }
+                .joined(separator: "\n")
+        case .codeComment:
+            return text.components(separatedBy: .newlines)
+                .flatMap { Self.wrapped($0, width: Self.commentWidth - 3) }
+                .map { $0.isEmpty ? "//" : "// " + $0 }
+                .joined(separator: "\n")
+        }
+    }
+
+    // MARK: - Helpers
+
+    private static func paragraphs(of text: String) -> [String] {
+        text.components(separatedBy: .newlines)
+            .map { $0.trimmingCharacters(in: .whitespaces) }
+            .filter { !$0.isEmpty }
+    }
+
+    /// Splits after `.`, `?` or `!` followed by whitespace.
+    private static func sentences(of text: String) -> [String] {
+        var sentences: [String] = []
+        var current = ""
+        var previous: Character?
+        for character in text {
+            if character.isWhitespace, let previous, ".?!".contains(previous) {
+                sentences.append(current)
+                current = ""
+            } else if !(character.isWhitespace && current.isEmpty) {
+                current.append(character)
+            }
+            previous = character
+        }
+        if !current.isEmpty { sentences.append(current) }
+        return sentences
+    }
+
+    /// One item per line or sentence; a single comma-separated sentence ("milk, eggs and
+    /// bread") becomes one item per entry.
+    private static func listItems(of text: String) -> [String] {
+        let items = paragraphs(of: text).flatMap(sentences(of:))
+        guard items.count == 1, let only = items.first, only.contains(",") else { return items }
+        return droppingTrailingPeriod(only)
+            .replacingOccurrences(of: " and ", with: ", ")
+            .split(separator: ",")
+            .map { $0.trimmingCharacters(in: .whitespaces) }
+            .filter { !$0.isEmpty }
+    }
+
+    private static func asSentence(_ text: String) -> String {
+        let capitalized = capitalizingFirst(text)
+        guard let last = capitalized.last, !".?!".contains(last) else { return capitalized }
+        return capitalized.trimmingCharacters(in: CharacterSet(charactersIn: ",;:")) + "."
+    }
+
+    private static func capitalizingFirst(_ text: String) -> String {
+        guard let first = text.first, first.isLowercase else { return text }
+        return first.uppercased() + text.dropFirst()
+    }
+
+    private static func droppingTrailingPeriod(_ text: String) -> String {
+        text.hasSuffix(".") && !text.hasSuffix("...") ? String(text.dropLast()) : text
+    }
+
+    /// Greedy word wrap; a single word longer than `width` gets a line of its own.
+    private static func wrapped(_ line: String, width: Int) -> [String] {
+        var lines: [String] = []
+        var current = ""
+        for word in line.split(separator: " ") {
+            if current.isEmpty {
+                current = String(word)
+            } else if current.count + 1 + word.count <= width {
+                current += " \(word)"
+            } else {
+                lines.append(current)
+                current = String(word)
+            }
+        }
+        lines.append(current)
+        return lines
+    }
+}
@@ -0,0 +1,87 @@
+import Foundation
+
+// MARK: - ProfanityFilter
+
+/// Stateless utility that masks or removes profanity, for dictating on a shared screen
+/// or into work documents.
+///
+/// Matches whole words case-insensitively, including common inflections ("-s", "-ed",
+/// "-er", "-ing"), so "Scunthorpe" and "assess" are left alone. The built-in English list
+/// can be extended with `profanityCustomWords`.
+///
+/// - **mask** keeps the first letter: "shit" → "s***".
+/// - **drop** removes the word and the space before it.
+enum ProfanityFilter {
+
+    enum Mode: String, CaseIterable {
+        case off
+        case mask
+        case drop
+    }
+
+    static let builtInWords = [
+        "fuck", "motherfucker", "shit", "shitty", "bullshit", "bitch", "bastard", "asshole",
+        "ass", "dick", "dickhead", "cunt", "damn", "goddamn", "crap", "piss", "prick",
+        "wanker", "twat", "bollocks",
+    ]
+
+    /// Filters `text` per the user's mode and word list.
+    static func filter(_ text: String, settings: AppSettings) -> String {
+        filter(text,
+               mode: Mode(rawValue: settings.profanityFilter) ?? .off,
+               customWords: settings.profanityCustomWords)
+    }
+
+    static func filter(_ text: String, mode: Mode, customWords: [String] = []) -> String {
+        guard mode != .off, let regex = regex(customWords: customWords) else { return text }
+        let ns = text as NSString
+        let matches = regex.matches(in: text, range: NSRange(location: 0, length: ns.length))
+        guard !matches.isEmpty else { return text }
+
+        var result = text
+        // Back to front so earlier ranges stay valid.
+        for match in matches.reversed() {
+            let word = match.range(at: 1)
+            guard let range = Range(mode == .drop ? match.range : word, in: result) else { continue }
+            switch mode {
+            case .mask:
+                let original = result[range]
+                result.replaceSubrange(range, with: String(original.prefix(1)) + String(repeating: "*", count: original.count - 1))
+            case .drop:
+                result.replaceSubrange(range, with: "")
+            case .off:
+                break
+            }
+        }
+        if mode == .drop {
+            // A dropped first word leaves the following space at the start.
+            result = result.trimmingCharacters(in: .whitespaces)
+        }
+        return result
+    }
+
+    // MARK: - Helpers
+
+    private static let cacheLock = NSLock()
+    private static var cache: (words: [String], regex: NSRegularExpression)?
+
+    /// Group 1 is the word; the whole match also covers the whitespace before it, which
+    /// `.drop` removes together with the word.
+    private static func regex(customWords: [String]) -> NSRegularExpression? {
+        let words = (builtInWords + customWords)
+            .map { $0.trimmingCharacters(in: .whitespacesAndNewlines) }
+            .filter { !$0.isEmpty }
+        cacheLock.lock()
+        defer { cacheLock.unlock() }
+        if let cache, cache.words == words { return cache.regex }
+
+        let alternatives = words
+            .sorted { $0.count > $1.count }
+            .map(NSRegularExpression.escapedPattern(for:))
+            .joined(separator: "|")
+        let pattern = #"\s*\b((?:"# + alternatives + #")(?:s|es|ed|er|ers|ing|in)?)\b"#
+        guard let regex = try? NSRegularExpression(pattern: pattern, options: .caseInsensitive) else { return nil }
+        cache = (words, regex)
+        return regex
+    }
+}
@@ -0,0 +1,49 @@
+import Foundation
+
+// MARK: - PunctuationRestorer
+
+/// Helpers for the punctuation restoration stage, which asks the small local model to
+/// punctuate transcripts from engines or languages that come back as one unpunctuated
+/// stream (Parakeet, Whisper on some non-English audio).
+///
+/// The model may only add punctuation and change letter case: `validated(_:against:)`
+/// rejects any output whose words differ from the input, so a model that rewrites or
+/// drops words never reaches the user.
+enum PunctuationRestorer {
+
+    /// Shorter dictations are left to `CapitalizationProcessor`'s closing period.
+    static let minimumWords = 8
+
+    static let prompt = """
+    You add punctuation to speech transcripts. Insert commas, periods, question marks and \
+    apostrophes, and capitalize the start of each sentence and proper nouns. Do not add, \
+    remove, reorder or change any words. Output only the punctuated transcript.
+    """
+
+    /// `true` when `text` has at least `minimumWords` words and no punctuation before
+    /// its last character.
+    static func needsRestoration(_ text: String) -> Bool {
+        let trimmed = text.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard trimmed.split(whereSeparator: \.isWhitespace).count >= minimumWords else { return false }
+        return !trimmed.dropLast().contains(where: { ".,?!;:".contains($0) })
+    }
+
+    /// `output` when it has the same words as `input`, ignoring case and punctuation;
+    /// otherwise `nil`.
+    static func validated(_ output: String, against input: String) -> String? {
+        let cleaned = output.trimmingCharacters(in: .whitespacesAndNewlines)
+        guard !cleaned.isEmpty, words(of: cleaned) == words(of: input) else { return nil }
+        return cleaned
+    }
+
+    // MARK: - Helpers
+
+    /// Lowercased words with punctuation removed. Apostrophes are dropped too, so
+    /// "dont" → "don't" still counts as the same word.
+    private static func words(of text: String) -> [String] {
+        text.lowercased()
+            .split(whereSeparator: { $0.isWhitespace || $0 == "-" })
+            .map { $0.filter { $0.isLetter || $0.isNumber } }
+            .filter { !$0.isEmpty }
+    }
+}
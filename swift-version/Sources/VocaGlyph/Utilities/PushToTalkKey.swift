@@ -0,0 +1,42 @@
+import Foundation
+
+// MARK: - PushToTalkKey
+
+/// Turns a hardware key's down/up reports (the control API's `key-down` / `key-up`,
+/// sent by a Stream Deck plugin) into dictation commands:
+///
+/// - A **tap** starts recording and leaves it running; the next press stops it.
+/// - A **hold** of at least `holdThreshold` is push-to-talk: letting go stops it.
+///
+/// Only decides; the caller passes the command on like any other trigger.
+final class PushToTalkKey {
+
+    /// Shorter presses are taps.
+    static let holdThreshold: CFTimeInterval = 0.4
+
+    private let now: () -> CFAbsoluteTime
+    /// When the press that started the recording went down; `nil` otherwise.
+    private var pressedAt: CFAbsoluteTime?
+
+    init(now: @escaping () -> CFAbsoluteTime = CFAbsoluteTimeGetCurrent) {
+        self.now = now
+    }
+
+    /// `isRecording` is whether a dictation is recording right now.
+    func keyDown(isRecording: Bool) -> ExternalTriggerCommand {
+        if isRecording {
+            // The press that ends a tapped recording; its release does nothing.
+            pressedAt = nil
+            return .stop
+        }
+        pressedAt = now()
+        return .start
+    }
+
+    /// `.stop` after a hold, `nil` after a tap or a stray release.
+    func keyUp() -> ExternalTriggerCommand? {
+        guard let pressedAt else { return nil }
+        self.pressedAt = nil
+        return now() - pressedAt >= Self.holdThreshold ? .stop : nil
+    }
+}
@@ -0,0 +1,91 @@
+import Foundation
+import Metal
+
+// MARK: - ResourceUsage
+
+/// How much memory VocaGlyph is using and what for, so a model can be chosen that fits
+/// the Mac's RAM. Sampled by `AppStateManager.resourceUsage()`; shown in the Model tab's
+/// Resources panel and returned by `DictationAPI.resourceUsage()`.
+struct ResourceUsage: Codable, Equatable {
+
+    struct Model: Codable, Equatable {
+        var id: String
+        /// On-disk size of the model folder — roughly what it takes in memory once loaded.
+        var bytes: Int64
+        var isLoaded: Bool
+    }
+
+    /// The samples `AudioRecorderService` holds for the current recording.
+    struct AudioBuffer: Codable, Equatable {
+        /// Reserved for samples, which can be more than `seconds` needs.
+        var bytes: Int64
+        var seconds: Double
+
+        static let empty = AudioBuffer(bytes: 0, seconds: 0)
+    }
+
+    /// The default Metal device; on Apple Silicon its memory is the same RAM.
+    struct GPU: Codable, Equatable {
+        var name: String
+        var allocatedBytes: Int64
+        /// What Metal suggests staying under before performance suffers.
+        var recommendedWorkingSetBytes: Int64
+        var hasUnifiedMemory: Bool
+    }
+
+    /// Resident set size.
+    var residentBytes: Int64
+    /// What Activity Monitor shows as "Memory"; includes compressed pages.
+    var footprintBytes: Int64
+    var physicalMemoryBytes: Int64
+    /// The selected transcription model; `nil` for the built-in Apple engine.
+    var model: Model?
+    var audioBuffer: AudioBuffer
+    /// `nil` when Metal is unavailable.
+    var gpu: GPU?
+
+    /// Largest model that keeps the app under half the RAM once it replaces the loaded
+    /// one — the rest is left for macOS and other apps.
+    var comfortableModelBytes: Int64 {
+        let withoutModel = footprintBytes - (model?.isLoaded == true ? model?.bytes ?? 0 : 0)
+        return max(0, physicalMemoryBytes / 2 - max(0, withoutModel))
+    }
+
+    // MARK: - Sampling
+
+    /// Samples this process and the default Metal device now.
+    static func sample(model: Model?, audioBuffer: AudioBuffer) -> ResourceUsage {
+        let memory = processMemory()
+        return ResourceUsage(
+            residentBytes: memory?.resident ?? 0,
+            footprintBytes: memory?.footprint ?? 0,
+            physicalMemoryBytes: Int64(ProcessInfo.processInfo.physicalMemory),
+            model: model,
+            audioBuffer: audioBuffer,
+            gpu: gpu()
+        )
+    }
+
+    /// Resident size and physical footprint of this process, from `task_info`.
+    static func processMemory() -> (resident: Int64, footprint: Int64)? {
+        var info = task_vm_info_data_t()
+        var count = mach_msg_type_number_t(MemoryLayout<task_vm_info_data_t>.size / MemoryLayout<natural_t>.size)
+        let result = withUnsafeMutablePointer(to: &info) {
+            $0.withMemoryRebound(to: integer_t.self, capacity: Int(count)) {
+                task_info(mach_task_self_, task_flavor_t(TASK_VM_INFO), $0, &count)
+            }
+        }
+        guard result == KERN_SUCCESS else { return nil }
+        return (Int64(info.resident_size), Int64(info.phys_footprint))
+    }
+
+    private static let device = MTLCreateSystemDefaultDevice()
+
+    static func gpu() -> GPU? {
+        guard let device else { return nil }
+        return GPU(name: device.name,
+                   allocatedBytes: Int64(device.currentAllocatedSize),
+                   recommendedWorkingSetBytes: Int64(device.recommendedMaxWorkingSetSize),
+                   hasUnifiedMemory: device.hasUnifiedMemory)
+    }
+}
@@ -0,0 +1,36 @@
+import Foundation
+
+// MARK: - SelectionRewriter
+
+/// "Rewrite selection" mode: with text selected when recording starts, the dictation is
+/// an instruction ("make this more formal", "translate to German") and the AI engine's
+/// result is pasted over the selection.
+///
+/// Selections longer than `maxSelectionLength` are not captured, so a select-all in a
+/// long document never ends up in a prompt.
+enum SelectionRewriter {
+
+    static let maxSelectionLength = 4000
+
+    static func prompt(instruction: String) -> String {
+        """
+        Rewrite the text below following this instruction: \(instruction)
+        Output only the rewritten text — no quotes, notes or explanations. Keep the \
+        language of the text unless the instruction says otherwise.
+        """
+    }
+
+    /// The rewritten selection, or `nil` when the engine fails, times out or returns
+    /// nothing — the selection is then left alone.
+    static func rewrite(_ selection: String, instruction: String, engine: any PostProcessingEngine,
+                        timeout: TimeInterval = 30) async -> String? {
+        let processor = LLMCleanupProcessor(engine: engine, prompt: prompt(instruction: instruction), timeout: timeout)
+        do {
+            let result = try await processor.process(selection).trimmingCharacters(in: .whitespacesAndNewlines)
+            return result.isEmpty ? nil : result
+        } catch {
+            Logger.shared.error("SelectionRewriter: Rewrite failed — \(error.localizedDescription)")
+            return nil
+        }
+    }
+}
@@ -0,0 +1,526 @@
+import Foundation
+import CoreGraphics
+
+// MARK: - SettingsValidationIssue
+
+/// A single field-level problem found by `SettingsValidator`.
+struct SettingsValidationIssue: Equatable, Identifiable {
+    /// The UserDefaults key the issue belongs to (an `AppSettings.Key` raw value, or the
+    /// offending key name for unknown JSON fields).
+    let field: String
+    /// Human-readable explanation, suitable for showing under the field in Settings.
+    let message: String
+
+    var id: String { "\(field): \(message)" }
+}
+
+// MARK: - SettingsValidator
+
+/// Stateless validation of settings before they are saved or applied.
+///
+/// Checks:
+/// - **Shortcut**: key code is a known key (or the modifier-only sentinel), modifiers are
+///   ones `HotkeyService` tracks, and at least one modifier is present.
+/// - **Transcription model**: id is one VocaGlyph ships and, when `downloadedModels` is
+///   supplied, is present on disk; `whisper-cpp` needs a model file.
+/// - **Language**: label is one of `WhisperService.supportedDictationLanguages`.
+/// - **Post-processing**: engine mode and cloud provider are recognised values, and the
+///   OpenAI-compatible endpoint, when set, is an http(s) URL.
+/// - **Context capture**: character limit is within `ContextCaptureService.characterLimitRange`
+///   and excluded app entries are non-empty.
+/// - **Decoder tuning**: worker and fallback counts are within `decoderWorkerRange` /
+///   `temperatureFallbackRange`.
+/// - **Downloads**: concurrent download limit is within `concurrentDownloadRange` and the
+///   rate cap is not negative.
+/// - **History**: retention mode is a `HistoryService.Retention` value and its day / entry
+///   limits are within `HistoryService.retentionDaysRange` / `retentionEntriesRange`.
+/// - **Redaction**: rule names are `TextRedactor.Rule` values and custom patterns compile.
+/// - **Profanity filter**: mode is a `ProfanityFilter.Mode` value.
+/// - **Output style**: an `OutputStyle` value.
+/// - **Custom terms**: no blank entries.
+/// - **Context prompt template**: only known `{…}` placeholders.
+/// - **Translation**: a `TranslationProvider` value; when on, a language code target and,
+///   for `.remote`, an http(s) endpoint.
+/// - **Per-app rules**: every line parses as an `AppProcessingRule`, one per app.
+/// - **Summaries**: minimum length is within `DictationSummarizer.minimumSecondsRange`.
+/// - **Recording limit**: 0 (none) or within `AppStateManager.maxRecordingSecondsRange`.
+/// - **Voice commands**: when on, the prefix has at least one word.
+/// - **Incognito shortcut**: when set, a valid key with at least one tracked modifier that
+///   differs from the dictation shortcut.
+/// - **Tray icon**: a `TrayIconTheme` value.
+/// - **Quit wait**: within `QuitDrainer.timeoutRange`.
+/// - **Processing watchdog**: 0 (off) or within `ProcessingWatchdog.timeoutRange`.
+/// - **Idle reduction**: `idleReductionMinutes` within `IdleMonitor.minutesRange`.
+/// - **Log levels**: every line parses as `LogLevels` `component=level`.
+/// - **Transcript logging**: a `TranscriptLogging` value.
+/// - **OTLP export**: when enabled, the endpoint is an http(s) URL.
+/// - **Live captions**: port within `CaptionServer.portRange`.
+///
+/// An empty result means the settings are valid.
+enum SettingsValidator {
+
+    // MARK: - Allowed Values
+
+    /// Transcription model ids offered in the Model settings tab.
+    static let knownTranscriptionModels: Set<String> = [
+        "apple-native",
+        WhisperCppEngine.modelID,
+        "parakeet-v3", "parakeet-v2",
+        "small", "medium",
+        "large-v3", "large-v3_turbo", "large-v3-v20240930_626MB",
+        "distil-whisper_distil-large-v3"
+    ]
+
+    /// Built-in models plus custom models from `models.json` (see `ModelRegistry`).
+    static func isKnownTranscriptionModel(_ model: String) -> Bool {
+        knownTranscriptionModels.contains(model) || ModelRegistry.shared.model(id: model) != nil
+    }
+
+    /// Models that need no download. whisper.cpp runs a model file the user picks.
+    static let builtInTranscriptionModels: Set<String> = ["apple-native", WhisperCppEngine.modelID]
+
+    /// Values of `selectedTaskModel`.
+    static let postProcessingModes: Set<String> = ["apple-native", "local-llm", "cloud-api"]
+
+    /// Values of `selectedCloudProvider`.
+    static let cloudProviders: Set<String> = ["gemini", "anthropic", "openai"]
+
+    /// Allowed WhisperKit `concurrentWorkerCount` values.
+    static let decoderWorkerRange = 1...16
+
+    /// Allowed WhisperKit `temperatureFallbackCount` values.
+    static let temperatureFallbackRange = 0...5
+
+    /// Allowed `maxConcurrentDownloads` values.
+    static let concurrentDownloadRange = 1...4
+
+    /// Modifier masks that `HotkeyService` can match.
+    static let allowedModifierMask: CGEventFlags = [.maskAlphaShift, .maskControl, .maskShift, .maskCommand, .maskAlternate]
+
+    // MARK: - Validate Settings
+
+    /// Validates a typed settings snapshot.
+    /// - Parameter downloadedModels: Ids present on disk; pass `nil` to skip the download check.
+    static func validate(_ settings: AppSettings, downloadedModels: Set<String>? = nil) -> [SettingsValidationIssue] {
+        var issues: [SettingsValidationIssue] = []
+        func add(_ key: AppSettings.Key, _ message: String) {
+            issues.append(SettingsValidationIssue(field: key.rawValue, message: message))
+        }
+
+        // Shortcut
+        let keyCode = settings.shortcutKeyCode
+        let isModifierOnly = keyCode == Int(kModifierOnlyKeyCode)
+        if !isModifierOnly && !(0...127).contains(keyCode) {
+            add(.shortcutKeyCode, "Key code \(keyCode) is not a valid keyboard key.")
+        }
+        let flags = CGEventFlags(rawValue: settings.shortcutModifiers)
+        let trackedFlags = flags.intersection(allowedModifierMask)
+        if flags.subtracting(allowedModifierMask).rawValue != 0 {
+            add(.shortcutModifiers, "Shortcut contains unsupported modifier flags.")
+        }
+        if trackedFlags.isEmpty {
+            add(.shortcutModifiers, isModifierOnly
+                ? "A modifier-only shortcut needs at least one modifier key."
+                : "Shortcut needs at least one modifier key (⌃, ⌥, ⇧, ⌘ or ⇪).")
+        }
+
+        // Transcription model
+        let model = settings.selectedModel
+        if !isKnownTranscriptionModel(model) {
+            add(.selectedModel, "Unknown transcription model '\(model)'.")
+        } else if let downloadedModels,
+                  !builtInTranscriptionModels.contains(model),
+                  !downloadedModels.contains(model) {
+            add(.selectedModel, "Model '\(model)' is not downloaded.")
+        } else if model == WhisperCppEngine.modelID,
+                  settings.whisperCppModelPath.trimmingCharacters(in: .whitespaces).isEmpty {
+            add(.whisperCppModelPath, "Choose a ggml model file for whisper.cpp.")
+        }
+
+        // Language
+        if !WhisperService.supportedDictationLanguages.contains(settings.dictationLanguage) {
+            add(.dictationLanguage, "Unsupported dictation language '\(settings.dictationLanguage)'.")
+        }
+
+        // Post-processing
+        if !postProcessingModes.contains(settings.selectedTaskModel) {
+            add(.selectedTaskModel, "Unknown post-processing engine '\(settings.selectedTaskModel)'.")
+        }
+        if !cloudProviders.contains(settings.selectedCloudProvider) {
+            add(.selectedCloudProvider, "Unknown cloud provider '\(settings.selectedCloudProvider)'.")
+        }
+        let openAIEndpoint = settings.openAICompatibleEndpoint
+        if !openAIEndpoint.isEmpty {
+            let url = URL(string: openAIEndpoint)
+            if url?.host == nil || !["http", "https"].contains(url?.scheme ?? "") {
+                add(.openAICompatibleEndpoint, "'\(openAIEndpoint)' is not an http(s) URL.")
+            }
+        }
+        if settings.selectedLocalLLMModel.trimmingCharacters(in: .whitespaces).isEmpty {
+            add(.selectedLocalLLMModel, "Local model id must not be empty.")
+        }
+
+        // Decoder tuning
+        if !decoderWorkerRange.contains(settings.transcriptionWorkerCount) {
+            add(.transcriptionWorkerCount, "Worker count must be between \(decoderWorkerRange.lowerBound) and \(decoderWorkerRange.upperBound).")
+        }
+        if !temperatureFallbackRange.contains(settings.temperatureFallbackCount) {
+            add(.temperatureFallbackCount, "Fallback count must be between \(temperatureFallbackRange.lowerBound) and \(temperatureFallbackRange.upperBound).")
+        }
+
+        // Context capture
+        let limitRange = ContextCaptureService.characterLimitRange
+        if !limitRange.contains(settings.contextCaptureCharacterLimit) {
+            add(.contextCaptureCharacterLimit, "Context length must be between \(limitRange.lowerBound) and \(limitRange.upperBound) characters.")
+        }
+        if settings.contextCaptureExcludedApps.contains(where: { $0.trimmingCharacters(in: .whitespaces).isEmpty }) {
+            add(.contextCaptureExcludedApps, "Excluded app entries must not be empty.")
+        }
+
+        // Downloads
+        if !concurrentDownloadRange.contains(settings.maxConcurrentDownloads) {
+            add(.maxConcurrentDownloads, "Concurrent downloads must be between \(concurrentDownloadRange.lowerBound) and \(concurrentDownloadRange.upperBound).")
+        }
+        if settings.downloadRateLimitMBps < 0 {
+            add(.downloadRateLimitMBps, "Download rate limit must be 0 (unlimited) or more.")
+        }
+
+        // History
+        if HistoryService.Retention(rawValue: settings.historyRetention) == nil {
+            add(.historyRetention, "Unknown history retention '\(settings.historyRetention)'.")
+        }
+        let daysRange = HistoryService.retentionDaysRange
+        if !daysRange.contains(settings.historyRetentionDays) {
+            add(.historyRetentionDays, "History must be kept between \(daysRange.lowerBound) and \(daysRange.upperBound) days.")
+        }
+        let entriesRange = HistoryService.retentionEntriesRange
+        if !entriesRange.contains(settings.historyRetentionEntries) {
+            add(.historyRetentionEntries, "History must keep between \(entriesRange.lowerBound) and \(entriesRange.upperBound) entries.")
+        }
+
+        // Redaction
+        for rule in settings.redactionRules where TextRedactor.Rule(rawValue: rule) == nil {
+            add(.redactionRules, "Unknown redaction rule '\(rule)'.")
+        }
+        for pattern in settings.redactionCustomPatterns where !TextRedactor.isValidPattern(pattern) {
+            add(.redactionCustomPatterns, "'\(pattern)' is not a valid regular expression.")
+        }
+
+        // Profanity filter
+        if ProfanityFilter.Mode(rawValue: settings.profanityFilter) == nil {
+            add(.profanityFilter, "Unknown profanity filter mode '\(settings.profanityFilter)'.")
+        }
+
+        // Output style
+        if OutputStyle(rawValue: settings.outputStyle) == nil {
+            add(.outputStyle, "Unknown output style '\(settings.outputStyle)'.")
+        }
+
+        // Custom terms
+        if settings.customTerms.contains(where: { $0.trimmingCharacters(in: .whitespaces).isEmpty }) {
+            add(.customTerms, "Custom terms must not be empty.")
+        }
+
+        // Context prompt template
+        for placeholder in TemplatePromptRenderer.unknownPlaceholders(in: settings.contextPromptTemplate) {
+            add(.contextPromptTemplate, "Unknown placeholder \(placeholder); use {context}, {custom_vocab} or {language}.")
+        }
+
+        // Translation
+        if let provider = TranslationProvider(rawValue: settings.translationProvider) {
+            let target = settings.translationTargetLanguage
+            if provider != .off, target.range(of: "^[a-z]{2,3}(-[A-Za-z]{2,4})?$", options: .regularExpression) == nil {
+                add(.translationTargetLanguage, "'\(target)' is not a language code such as 'de' or 'pt-BR'.")
+            }
+            let endpoint = URL(string: settings.translationEndpoint)
+            if provider == .remote, endpoint?.host == nil || !["http", "https"].contains(endpoint?.scheme ?? "") {
+                add(.translationEndpoint, "'\(settings.translationEndpoint)' is not an http(s) URL.")
+            }
+        } else {
+            add(.translationProvider, "Unknown translation provider '\(settings.translationProvider)'.")
+        }
+
+        // Per-app rules
+        var ruleApps: Set<String> = []
+        for line in settings.appProcessingRules {
+            do {
+                let rule = try AppProcessingRule(parsing: line)
+                if !ruleApps.insert(rule.bundleIdentifier).inserted {
+                    add(.appProcessingRules, "More than one rule for '\(rule.bundleIdentifier)'.")
+                }
+            } catch {
+                add(.appProcessingRules, "'\(line)': \(error.localizedDescription)")
+            }
+        }
+
+        // Summaries
+        let summaryRange = DictationSummarizer.minimumSecondsRange
+        if !summaryRange.contains(settings.summaryMinimumSeconds) {
+            add(.summaryMinimumSeconds, "Summary length must be between \(summaryRange.lowerBound) and \(summaryRange.upperBound) seconds.")
+        }
+
+        // Recording limit
+        let recordingRange = AppStateManager.maxRecordingSecondsRange
+        if settings.maxRecordingSeconds != 0, !recordingRange.contains(settings.maxRecordingSeconds) {
+            add(.maxRecordingSeconds, "Maximum recording length must be 0 (no limit) or between \(recordingRange.lowerBound) and \(recordingRange.upperBound) seconds.")
+        }
+
+        // Voice commands
+        if settings.voiceCommandsEnabled,
+           !settings.voiceCommandPrefix.contains(where: { $0.isLetter || $0.isNumber }) {
+            add(.voiceCommandPrefix, "Voice commands need a prefix word, e.g. \"computer\".")
+        }
+
+        // Incognito shortcut
+        let incognitoKeyCode = settings.incognitoShortcutKeyCode
+        if incognitoKeyCode != -1 {
+            let incognitoFlags = CGEventFlags(rawValue: settings.incognitoShortcutModifiers)
+            if !(0...127).contains(incognitoKeyCode) {
+                add(.incognitoShortcutKeyCode, "Key code \(incognitoKeyCode) is not a valid keyboard key.")
+            } else if incognitoKeyCode == keyCode && incognitoFlags == flags {
+                add(.incognitoShortcutKeyCode, "The incognito shortcut must differ from the dictation shortcut.")
+            }
+            if incognitoFlags.intersection(allowedModifierMask).isEmpty
+                || incognitoFlags.subtracting(allowedModifierMask).rawValue != 0 {
+                add(.incognitoShortcutModifiers, "Incognito shortcut needs at least one supported modifier key.")
+            }
+        }
+
+        // Tray icon
+        if TrayIconTheme(rawValue: settings.trayIconTheme) == nil {
+            add(.trayIconTheme, "Unknown menu bar icon theme '\(settings.trayIconTheme)'.")
+        }
+
+        // Quit wait
+        let quitWaitRange = QuitDrainer.timeoutRange
+        if !quitWaitRange.contains(settings.quitWaitSeconds) {
+            add(.quitWaitSeconds, "Quit wait must be between \(quitWaitRange.lowerBound) and \(quitWaitRange.upperBound) seconds.")
+        }
+
+        // Processing watchdog
+        let watchdogRange = ProcessingWatchdog.timeoutRange
+        if settings.processingTimeoutSeconds != 0, !watchdogRange.contains(settings.processingTimeoutSeconds) {
+            add(.processingTimeoutSeconds,
+                "Processing limit must be 0 (off) or between \(watchdogRange.lowerBound) and \(watchdogRange.upperBound) seconds.")
+        }
+
+        // Idle reduction
+        let idleRange = IdleMonitor.minutesRange
+        if !idleRange.contains(settings.idleReductionMinutes) {
+            add(.idleReductionMinutes, "Idle time must be between \(idleRange.lowerBound) and \(idleRange.upperBound) minutes.")
+        }
+
+        // Live captions
+        let captionPortRange = CaptionServer.portRange
+        if !captionPortRange.contains(settings.liveCaptionsPort) {
+            add(.liveCaptionsPort, "Caption port must be between \(captionPortRange.lowerBound) and \(captionPortRange.upperBound).")
+        }
+
+        // Log levels
+        for line in settings.logLevels {
+            do {
+                _ = try LogLevels(parsing: [line], debugLogging: settings.enableDebugLogging)
+            } catch {
+                add(.logLevels, error.localizedDescription)
+            }
+        }
+
+        // Transcript logging
+        if TranscriptLogging(rawValue: settings.transcriptLogging) == nil {
+            add(.transcriptLogging, "Unknown transcript logging mode '\(settings.transcriptLogging)'.")
+        }
+
+        // OTLP export
+        if settings.otlpExportEnabled {
+            let endpoint = URL(string: settings.otlpEndpoint)
+            if endpoint?.host == nil || !["http", "https"].contains(endpoint?.scheme ?? "") {
+                add(.otlpEndpoint, "'\(settings.otlpEndpoint)' is not an http(s) URL.")
+            }
+        }
+
+        return issues
+    }
+
+    // MARK: - Validate JSON
+
+    /// Validates a JSON object of `{ "<UserDefaults key>": value }` pairs, e.g. a settings
+    /// export. Missing keys take their defaults; type mismatches and unknown keys are reported
+    /// alongside the semantic checks from `validate(_:downloadedModels:)`.
+    static func validate(json: Data, downloadedModels: Set<String>? = nil) -> [SettingsValidationIssue] {
+        guard let object = try? JSONSerialization.jsonObject(with: json),
+              let dictionary = object as? [String: Any] else {
+            return [SettingsValidationIssue(field: "", message: "Settings must be a JSON object.")]
+        }
+
+        var issues: [SettingsValidationIssue] = []
+        var settings = AppSettings.defaults
+
+        for (name, value) in dictionary.sorted(by: { $0.key < $1.key }) {
+            guard let key = AppSettings.Key(rawValue: name) else {
+                issues.append(SettingsValidationIssue(field: name, message: "Unknown setting."))
+                continue
+            }
+            if let message = settings.assign(value, to: key) {
+                issues.append(SettingsValidationIssue(field: name, message: message))
+            }
+        }
+
+        return issues + validate(settings, downloadedModels: downloadedModels)
+    }
+}
+
+// MARK: - AppSettings + Untyped Assignment
+
+extension AppSettings {
+    /// Assigns an untyped (JSON / plist) value to `key`. Returns an error message when the
+    /// value has the wrong type; the field keeps its previous value in that case.
+    mutating func assign(_ value: Any, to key: Key) -> String? {
+        func string() -> String? { value as? String }
+        func bool() -> Bool? {
+            // JSONSerialization yields NSNumber for both numbers and booleans.
+            guard let number = value as? NSNumber, CFGetTypeID(number) == CFBooleanGetTypeID() else { return nil }
+            return number.boolValue
+        }
+        func number() -> NSNumber? {
+            guard let number = value as? NSNumber, CFGetTypeID(number) != CFBooleanGetTypeID() else { return nil }
+            return number
+        }
+
+        switch key {
+        case .selectedModel:         guard let v = string() else { return "Expected a string." }; selectedModel = v
+        case .dictationLanguage:     guard let v = string() else { return "Expected a string." }; dictationLanguage = v
+        case .selectedTaskModel:     guard let v = string() else { return "Expected a string." }; selectedTaskModel = v
+        case .selectedCloudProvider: guard let v = string() else { return "Expected a string." }; selectedCloudProvider = v
+        case .selectedLocalLLMModel: guard let v = string() else { return "Expected a string." }; selectedLocalLLMModel = v
+        case .autoPunctuation:       guard let v = bool() else { return "Expected true or false." }; autoPunctuation = v
+        case .removeFillerWords:     guard let v = bool() else { return "Expected true or false." }; removeFillerWords = v
+        case .privacyModeEnabled:    guard let v = bool() else { return "Expected true or false." }; privacyModeEnabled = v
+        case .enableDebugLogging:    guard let v = bool() else { return "Expected true or false." }; enableDebugLogging = v
+        case .enablePostProcessing:  guard let v = bool() else { return "Expected true or false." }; enablePostProcessing = v
+        case .contextCaptureEnabled: guard let v = bool() else { return "Expected true or false." }; contextCaptureEnabled = v
+        case .contextCaptureCharacterLimit:
+            guard let v = number() else { return "Expected a number." }
+            contextCaptureCharacterLimit = v.intValue
+        case .contextCaptureExcludedApps:
+            guard let v = value as? [String] else { return "Expected a list of bundle identifiers." }
+            contextCaptureExcludedApps = v
+        case .shortcutKeyCode:
+            guard let v = number() else { return "Expected a number." }
+            shortcutKeyCode = v.intValue
+        case .shortcutModifiers:
+            guard let v = number(), v.doubleValue >= 0 else { return "Expected a non-negative number." }
+            shortcutModifiers = v.uint64Value
+        case .hotkeyBackend:
+            guard let raw = string(), let backend = HotkeyBackend(rawValue: raw) else {
+                return "Expected one of: \(HotkeyBackend.allCases.map(\.rawValue).joined(separator: ", "))."
+            }
+            hotkeyBackend = backend
+        case .transcriptionWorkerCount:
+            guard let v = number() else { return "Expected a number." }
+            transcriptionWorkerCount = v.intValue
+        case .temperatureFallbackCount:
+            guard let v = number() else { return "Expected a number." }
+            temperatureFallbackCount = v.intValue
+        case .maxConcurrentDownloads:
+            guard let v = number() else { return "Expected a number." }
+            maxConcurrentDownloads = v.intValue
+        case .downloadRateLimitMBps:
+            guard let v = number() else { return "Expected a number." }
+            downloadRateLimitMBps = v.intValue
+        case .offerRecommendedModelDownload: guard let v = bool() else { return "Expected true or false." }; offerRecommendedModelDownload = v
+        case .historyRetention: guard let v = string() else { return "Expected a string." }; historyRetention = v
+        case .historyRetentionDays:
+            guard let v = number() else { return "Expected a number." }
+            historyRetentionDays = v.intValue
+        case .historyRetentionEntries:
+            guard let v = number() else { return "Expected a number." }
+            historyRetentionEntries = v.intValue
+        case .redactionEnabled: guard let v = bool() else { return "Expected true or false." }; redactionEnabled = v
+        case .redactionRules:
+            guard let v = value as? [String] else { return "Expected a list of strings." }
+            redactionRules = v
+        case .redactionCustomPatterns:
+            guard let v = value as? [String] else { return "Expected a list of strings." }
+            redactionCustomPatterns = v
+        case .redactLogs: guard let v = bool() else { return "Expected true or false." }; redactLogs = v
+        case .incognitoModeEnabled: guard let v = bool() else { return "Expected true or false." }; incognitoModeEnabled = v
+        case .incognitoShortcutKeyCode:
+            guard let v = number() else { return "Expected a number." }
+            incognitoShortcutKeyCode = v.intValue
+        case .incognitoShortcutModifiers:
+            guard let v = number(), v.doubleValue >= 0 else { return "Expected a non-negative number." }
+            incognitoShortcutModifiers = v.uint64Value
+        case .wordReplacementsEnabled: guard let v = bool() else { return "Expected true or false." }; wordReplacementsEnabled = v
+        case .spokenPunctuationEnabled: guard let v = bool() else { return "Expected true or false." }; spokenPunctuationEnabled = v
+        case .regexRulesEnabled: guard let v = bool() else { return "Expected true or false." }; regexRulesEnabled = v
+        case .numberNormalizationEnabled: guard let v = bool() else { return "Expected true or false." }; numberNormalizationEnabled = v
+        case .profanityFilter: guard let v = string() else { return "Expected a string." }; profanityFilter = v
+        case .profanityCustomWords:
+            guard let v = value as? [String] else { return "Expected a list of strings." }
+            profanityCustomWords = v
+        case .outputStyle: guard let v = string() else { return "Expected a string." }; outputStyle = v
+        case .translationProvider: guard let v = string() else { return "Expected a string." }; translationProvider = v
+        case .translationTargetLanguage: guard let v = string() else { return "Expected a string." }; translationTargetLanguage = v
+        case .translationEndpoint: guard let v = string() else { return "Expected a string." }; translationEndpoint = v
+        case .customTerms:
+            guard let v = value as? [String] else { return "Expected a list of strings." }
+            customTerms = v
+        case .termCorrectionEnabled: guard let v = bool() else { return "Expected true or false." }; termCorrectionEnabled = v
+        case .contextPromptTemplate: guard let v = string() else { return "Expected a string." }; contextPromptTemplate = v
+        case .openAICompatibleEndpoint: guard let v = string() else { return "Expected a string." }; openAICompatibleEndpoint = v
+        case .openAICompatibleModel: guard let v = string() else { return "Expected a string." }; openAICompatibleModel = v
+        case .openAICompatibleOptIn: guard let v = bool() else { return "Expected true or false." }; openAICompatibleOptIn = v
+        case .voiceCommandsEnabled: guard let v = bool() else { return "Expected true or false." }; voiceCommandsEnabled = v
+        case .voiceCommandPrefix: guard let v = string() else { return "Expected a string." }; voiceCommandPrefix = v
+        case .punctuationRestorationEnabled: guard let v = bool() else { return "Expected true or false." }; punctuationRestorationEnabled = v
+        case .appProcessingRules:
+            guard let v = value as? [String] else { return "Expected a list of strings." }
+            appProcessingRules = v
+        case .summarizeLongDictations: guard let v = bool() else { return "Expected true or false." }; summarizeLongDictations = v
+        case .summaryMinimumSeconds:
+            guard let v = number() else { return "Expected a number." }
+            summaryMinimumSeconds = v.intValue
+        case .selectionRewriteEnabled: guard let v = bool() else { return "Expected true or false." }; selectionRewriteEnabled = v
+        case .liveTranscriptionPreview: guard let v = bool() else { return "Expected true or false." }; liveTranscriptionPreview = v
+        case .maxRecordingSeconds:
+            guard let v = number() else { return "Expected a number." }
+            maxRecordingSeconds = v.intValue
+        case .automaticUpdateChecks: guard let v = bool() else { return "Expected true or false." }; automaticUpdateChecks = v
+        case .crashReportingEnabled: guard let v = bool() else { return "Expected true or false." }; crashReportingEnabled = v
+        case .systemNotificationsEnabled: guard let v = bool() else { return "Expected true or false." }; systemNotificationsEnabled = v
+        case .controlAPIEnabled: guard let v = bool() else { return "Expected true or false." }; controlAPIEnabled = v
+        case .trayIconTheme: guard let v = string() else { return "Expected a string." }; trayIconTheme = v
+        case .trayIconFlashesWhileRecording: guard let v = bool() else { return "Expected true or false." }; trayIconFlashesWhileRecording = v
+        case .showDockIcon: guard let v = bool() else { return "Expected true or false." }; showDockIcon = v
+        case .alwaysOnTop: guard let v = bool() else { return "Expected true or false." }; alwaysOnTop = v
+        case .quitWaitsForTranscription: guard let v = bool() else { return "Expected true or false." }; quitWaitsForTranscription = v
+        case .quitWaitSeconds:
+            guard let v = number() else { return "Expected a number." }
+            quitWaitSeconds = v.intValue
+        case .idleReductionEnabled: guard let v = bool() else { return "Expected true or false." }; idleReductionEnabled = v
+        case .idleReductionMinutes:
+            guard let v = number() else { return "Expected a number." }
+            idleReductionMinutes = v.intValue
+        case .idleReductionUnloadsModel: guard let v = bool() else { return "Expected true or false." }; idleReductionUnloadsModel = v
+        case .logLevels:
+            guard let v = value as? [String] else { return "Expected a list of strings." }
+            logLevels = v
+        case .transcriptLogging: guard let v = string() else { return "Expected a string." }; transcriptLogging = v
+        case .otlpExportEnabled: guard let v = bool() else { return "Expected true or false." }; otlpExportEnabled = v
+        case .otlpEndpoint: guard let v = string() else { return "Expected a string." }; otlpEndpoint = v
+        case .pluginsEnabled: guard let v = bool() else { return "Expected true or false." }; pluginsEnabled = v
+        case .transcriptionServiceEnabled: guard let v = bool() else { return "Expected true or false." }; transcriptionServiceEnabled = v
+        case .whisperCppModelPath: guard let v = string() else { return "Expected a string." }; whisperCppModelPath = v
+        case .processingTimeoutSeconds:
+            guard let v = number() else { return "Expected a number." }
+            processingTimeoutSeconds = v.intValue
+        case .hideFromClipboardHistory: guard let v = bool() else { return "Expected true or false." }; hideFromClipboardHistory = v
+        case .liveCaptionsEnabled: guard let v = bool() else { return "Expected true or false." }; liveCaptionsEnabled = v
+        case .liveCaptionsPort:
+            guard let v = number() else { return "Expected a number." }
+            liveCaptionsPort = v.intValue
+        case .externalTriggersEnabled: guard let v = bool() else { return "Expected true or false." }; externalTriggersEnabled = v
+        }
+        return nil
+    }
+}
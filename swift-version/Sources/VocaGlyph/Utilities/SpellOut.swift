@@ -0,0 +1,125 @@
+import Foundation
+
+// MARK: - SpellOut
+
+/// Literal transcription for emails, URLs, licence keys and codes, where normal
+/// dictation would turn "alpha bravo seven" into words.
+///
+/// A dictation that starts with "spell out" (or "spelling mode") is decoded token by token
+/// and skips the text pipeline entirely:
+/// - NATO words and single letters become capitals: "alpha bravo" → "AB". "Lowercase"
+///   or "small" before one gives a small letter, "capital" a capital.
+/// - Digit words and digits become digits; "double" / "triple" repeat the next token:
+///   "double seven" → "77".
+/// - Symbol words become symbols: "at" → "@", "dot" → ".", "dash" → "-".
+/// - Any other word is kept as transcribed: "john at example dot com" →
+///   "john@example.com", "AB7" stays "AB7".
+///
+/// Nothing is separated by spaces unless "space" is spoken.
+enum SpellOut {
+
+    /// Phrases that switch a single dictation to spell-out mode when spoken first. Bare
+    /// "spelling" is not one — "spelling mistakes in the draft…" is ordinary prose.
+    static let spokenCommands = ["spell out", "spelling mode"]
+
+    static let nato: [String: Character] = [
+        "alpha": "a", "alfa": "a", "bravo": "b", "charlie": "c", "delta": "d", "echo": "e",
+        "foxtrot": "f", "golf": "g", "hotel": "h", "india": "i", "juliet": "j", "juliett": "j",
+        "kilo": "k", "lima": "l", "mike": "m", "november": "n", "oscar": "o", "papa": "p",
+        "quebec": "q", "romeo": "r", "sierra": "s", "tango": "t", "uniform": "u",
+        "victor": "v", "whiskey": "w", "whisky": "w", "xray": "x", "yankee": "y", "zulu": "z",
+    ]
+
+    static let digits: [String: String] = [
+        "zero": "0", "oh": "0", "one": "1", "two": "2", "three": "3", "four": "4",
+        "five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9", "niner": "9",
+    ]
+
+    /// Spoken symbols; two-word names are matched before one-word ones.
+    static let symbols: [String: String] = [
+        "at sign": "@", "at": "@",
+        "dot": ".", "period": ".", "full stop": ".",
+        "dash": "-", "hyphen": "-", "minus": "-",
+        "underscore": "_", "forward slash": "/", "slash": "/", "backslash": "\\",
+        "colon": ":", "plus": "+", "hash": "#", "pound sign": "#",
+        "ampersand": "&", "equals": "=", "question mark": "?", "percent": "%",
+        "tilde": "~", "space": " ",
+    ]
+
+    private static let upperModifiers: Set<String> = ["capital", "cap", "uppercase", "upper"]
+    private static let lowerModifiers: Set<String> = ["lowercase", "lower", "small"]
+    private static let repeatModifiers: [String: Int] = ["double": 2, "triple": 3]
+
+    /// Splits a leading spell-out command off `text`, e.g. "Spell out: alpha bravo" →
+    /// "alpha bravo". Returns `nil` when the text doesn't start with one.
+    static func spokenCommand(in text: String) -> String? {
+        let trimmed = text.trimmingCharacters(in: .whitespacesAndNewlines)
+        for phrase in spokenCommands {
+            guard let range = trimmed.range(of: phrase, options: [.caseInsensitive, .anchored]) else { continue }
+            let rest = trimmed[range.upperBound...]
+            // Whole words only: "spell outside" isn't caught by "spell out".
+            if let next = rest.first, next.isLetter || next.isNumber { continue }
+            return String(rest.drop(while: { $0.isWhitespace || $0.isPunctuation }))
+        }
+        return nil
+    }
+
+    static func decode(_ text: String) -> String {
+        let original = tokens(of: text)
+        let tokens = original.map { $0.lowercased() }
+        var output = ""
+        var letterCase: Bool?   // true = upper, false = lower, nil = default
+        var repeatCount = 1
+        var index = 0
+
+        while index < tokens.count {
+            let token = tokens[index]
+            if upperModifiers.contains(token) || lowerModifiers.contains(token),
+               index + 1 < tokens.count, isLetter(tokens[index + 1]) {
+                letterCase = upperModifiers.contains(token)
+                index += 1
+                continue
+            }
+            if let count = repeatModifiers[token], index + 1 < tokens.count {
+                repeatCount = count
+                index += 1
+                continue
+            }
+
+            let piece: String
+            if index + 1 < tokens.count, let symbol = symbols[token + " " + tokens[index + 1]] {
+                piece = symbol
+                index += 1
+            } else if let symbol = symbols[token] {
+                piece = symbol
+            } else if let digit = digits[token] {
+                piece = digit
+            } else if isLetter(token) {
+                let letter = String(nato[token] ?? Character(token))
+                piece = letterCase == false ? letter : letter.uppercased()
+            } else {
+                piece = original[index]
+            }
+            output += String(repeating: piece, count: repeatCount)
+            letterCase = nil
+            repeatCount = 1
+            index += 1
+        }
+        return output
+    }
+
+    // MARK: - Helpers
+
+    private static func isLetter(_ token: String) -> Bool {
+        nato[token] != nil || (token.count == 1 && token.first!.isLetter)
+    }
+
+    /// Words split on whitespace and commas, without the sentence punctuation the engine
+    /// adds at the end of a word. "X-ray" becomes "xray".
+    private static func tokens(of text: String) -> [String] {
+        text.replacingOccurrences(of: "x-ray", with: "xray", options: .caseInsensitive)
+            .split(whereSeparator: { $0.isWhitespace || $0 == "," })
+            .map { String($0).trimmingCharacters(in: CharacterSet(charactersIn: ".;!?")) }
+            .filter { !$0.isEmpty }
+    }
+}
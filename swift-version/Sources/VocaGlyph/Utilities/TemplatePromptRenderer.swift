@@ -43,6 +43,79 @@ public enum TemplatePromptRenderer {
         """
     }
 
+    // MARK: - Context Template
+
+    /// Placeholders a context template may use.
+    public static let contextPlaceholders = ["{context}", "{custom_vocab}", "{language}"]
+
+    /// Used while the `contextPromptTemplate` setting is empty.
+    public static let defaultContextTemplate = """
+        The transcription is in {language}.
+
+        Spell these names and terms exactly as written: {custom_vocab}.
+
+        For reference only, this is the text that comes right before the transcription. \
+        Use it to match names, terminology and tone. Do not repeat or modify it.
+        <context>
+        {context}
+        </context>
+        """
+
+    /// Appends the rendered context template to a rendered prompt. `context` is the text
+    /// captured before the cursor (see `ContextCaptureService`), `customVocabulary` the
+    /// user's custom terms and `language` the dictation language's name, `nil` for
+    /// auto-detect.
+    ///
+    /// Returns `prompt` unchanged when it is empty (post-processing is skipped) or
+    /// when the template renders to nothing.
+    public static func appendingContext(_ context: String?,
+                                        to prompt: String,
+                                        template: String = defaultContextTemplate,
+                                        customVocabulary: [String] = [],
+                                        language: String? = nil) -> String {
+        guard !prompt.isEmpty else { return prompt }
+        let rendered = renderContextTemplate(template, context: context,
+                                             customVocabulary: customVocabulary, language: language)
+        return rendered.isEmpty ? prompt : "\(prompt)\n\n\(rendered)"
+    }
+
+    /// Fills in `template`'s placeholders. Paragraphs are separated by blank lines; one
+    /// that uses a placeholder without a value is left out whole, so an empty context
+    /// never leaves a dangling `<context>` block.
+    public static func renderContextTemplate(_ template: String,
+                                             context: String?,
+                                             customVocabulary: [String],
+                                             language: String?) -> String {
+        let values: [String: String] = [
+            "{language}": language ?? "",
+            "{custom_vocab}": customVocabulary.joined(separator: ", "),
+            // Last, so placeholder-like text inside the captured context is left alone.
+            "{context}": context?.trimmingCharacters(in: .whitespacesAndNewlines) ?? "",
+        ]
+        let order = ["{language}", "{custom_vocab}", "{context}"]
+
+        return template
+            .components(separatedBy: "\n\n")
+            .filter { paragraph in
+                !order.contains { paragraph.contains($0) && values[$0]!.isEmpty }
+            }
+            .map { paragraph in
+                order.reduce(paragraph) { $0.replacingOccurrences(of: $1, with: values[$1]!) }
+            }
+            .map { $0.trimmingCharacters(in: .whitespacesAndNewlines) }
+            .filter { !$0.isEmpty }
+            .joined(separator: "\n\n")
+    }
+
+    /// `{…}` tokens in `template` that aren't one of `contextPlaceholders`.
+    public static func unknownPlaceholders(in template: String) -> [String] {
+        guard let regex = try? NSRegularExpression(pattern: #"\{[A-Za-z_]+\}"#) else { return [] }
+        let range = NSRange(template.startIndex..., in: template)
+        return regex.matches(in: template, range: range)
+            .compactMap { Range($0.range, in: template).map { String(template[$0]) } }
+            .filter { !contextPlaceholders.contains($0) }
+    }
+
     // MARK: - Length Guard
 
     /// Returns the character count of the template's prompt text.
@@ -0,0 +1,173 @@
+import Foundation
+
+// MARK: - TermCorrector
+
+/// Stateless utility that snaps misheard names and jargon to the user's term dictionary,
+/// e.g. "Cooper Netties" → "Kubernetes", for when the engine doesn't know the word at all.
+///
+/// Runs of up to two more words than the term are compared with letters only, so split
+/// words are caught ("get hub" → "GitHub"); the best-scoring run wins. A run matches a
+/// term when either
+/// - its spelling is close (edit-distance similarity of at least `spellingThreshold`), or
+/// - it sounds the same (equal `phoneticKey` of three or more sounds) and is at least
+///   loosely similar in spelling (`soundThreshold`).
+///
+/// Runs never span punctuation, and runs shorter than four letters are left alone.
+enum TermCorrector {
+
+    static let spellingThreshold = 0.8
+    static let soundThreshold = 0.5
+    static let minimumLetters = 4
+
+    static func correct(_ text: String, terms: [String]) -> String {
+        let entries = terms
+            .map { $0.trimmingCharacters(in: .whitespacesAndNewlines) }
+            .filter { !$0.isEmpty }
+            .map(Entry.init)
+        guard !entries.isEmpty else { return text }
+        return text.components(separatedBy: "\n")
+            .map { correctLine($0, entries: entries) }
+            .joined(separator: "\n")
+    }
+
+    // MARK: - Matching
+
+    private struct Entry {
+        let term: String
+        let letters: String
+        let sound: String
+        let wordCount: Int
+
+        init(_ term: String) {
+            self.term = term
+            letters = TermCorrector.letters(of: term)
+            sound = TermCorrector.phoneticKey(letters)
+            wordCount = term.split(whereSeparator: \.isWhitespace).count
+        }
+    }
+
+    /// A word split into punctuation and its alphanumeric core.
+    private struct Word {
+        let leading: Substring
+        let core: Substring
+        let trailing: Substring
+
+        init(_ word: Substring) {
+            let isWordCharacter: (Character) -> Bool = { $0.isLetter || $0.isNumber }
+            let start = word.firstIndex(where: isWordCharacter) ?? word.endIndex
+            let end = word.lastIndex(where: isWordCharacter).map(word.index(after:)) ?? start
+            leading = word[..<start]
+            core = word[start..<end]
+            trailing = word[end...]
+        }
+    }
+
+    private static func correctLine(_ line: String, entries: [Entry]) -> String {
+        let words = line.split(whereSeparator: \.isWhitespace).map(Word.init)
+        guard !words.isEmpty else { return line }
+        let maxRun = (entries.map(\.wordCount).max() ?? 1) + 2
+
+        var output: [String] = []
+        var index = 0
+        while index < words.count {
+            let best = bestRun(in: words, at: index, maxLength: maxRun, entries: entries)
+            // A run starting one word later that scores at least as well means this word
+            // isn't part of the term: "a kubernetes" keeps its "a".
+            let later = bestRun(in: words, at: index + 1, maxLength: maxRun, entries: entries)
+            if let best, best.score > later?.score ?? 0 {
+                let run = words[index..<index + best.length]
+                output.append(String(run.first!.leading) + best.entry.term + String(run.last!.trailing))
+                index += best.length
+            } else {
+                let word = words[index]
+                output.append(String(word.leading + word.core + word.trailing))
+                index += 1
+            }
+        }
+        return output.joined(separator: " ")
+    }
+
+    /// The best-scoring run starting at `index`; on a tie the shorter one, so
+    /// "tensorflow is" keeps its "is".
+    private static func bestRun(in words: [Word], at index: Int, maxLength: Int,
+                                entries: [Entry]) -> (length: Int, entry: Entry, score: Double)? {
+        guard index < words.count else { return nil }
+        return (1...min(maxLength, words.count - index))
+            .compactMap { length in
+                match(words[index..<index + length], entries: entries).map { (length, $0.entry, $0.score) }
+            }
+            .max { $0.score < $1.score || ($0.score == $1.score && $0.length > $1.length) }
+    }
+
+    private static func match(_ run: ArraySlice<Word>, entries: [Entry]) -> (entry: Entry, score: Double)? {
+        // Punctuation may only sit before the first word and after the last.
+        guard run.dropFirst().allSatisfy({ $0.leading.isEmpty }),
+              run.dropLast().allSatisfy({ $0.trailing.isEmpty }) else { return nil }
+        let candidate = letters(of: run.map(\.core).joined())
+        guard candidate.count >= minimumLetters else { return nil }
+        let sound = phoneticKey(candidate)
+
+        return entries
+            .filter { run.count <= $0.wordCount + 2 }
+            .map { (entry: $0, score: similarity(candidate, $0.letters)) }
+            .filter { $0.score >= spellingThreshold
+                || ($0.score >= soundThreshold && $0.entry.sound.count >= 3 && $0.entry.sound == sound) }
+            .max { $0.score < $1.score }
+    }
+
+    // MARK: - Helpers
+
+    /// Lowercased letters and digits only: "Cooper Netties" → "coopernetties".
+    static func letters(of text: String) -> String {
+        String(text.lowercased().filter { $0.isLetter || $0.isNumber })
+    }
+
+    /// 1 minus the edit distance over the longer length: 1 is identical, 0 shares nothing.
+    static func similarity(_ a: String, _ b: String) -> Double {
+        let longest = max(a.count, b.count)
+        guard longest > 0 else { return 1 }
+        return 1 - Double(levenshtein(a, b)) / Double(longest)
+    }
+
+    static func levenshtein(_ a: String, _ b: String) -> Int {
+        let a = Array(a), b = Array(b)
+        guard !a.isEmpty else { return b.count }
+        guard !b.isEmpty else { return a.count }
+        var previous = Array(0...b.count)
+        for i in 1...a.count {
+            var current = [i] + Array(repeating: 0, count: b.count)
+            for j in 1...b.count {
+                current[j] = min(previous[j] + 1,
+                                 current[j - 1] + 1,
+                                 previous[j - 1] + (a[i - 1] == b[j - 1] ? 0 : 1))
+            }
+            previous = current
+        }
+        return previous[b.count]
+    }
+
+    /// Soundex-style digits for every consonant sound, including the first letter, so
+    /// "coopernetties" and "kubernetes" both give "216532". Vowels separate repeats;
+    /// "h" and "w" don't.
+    static func phoneticKey(_ letters: String) -> String {
+        var key = ""
+        var last: Character?
+        for letter in letters {
+            guard let code = soundCodes[letter] else {
+                if letter != "h" && letter != "w" { last = nil }
+                continue
+            }
+            if code != last { key.append(code) }
+            last = code
+        }
+        return key
+    }
+
+    private static let soundCodes: [Character: Character] = {
+        var codes: [Character: Character] = [:]
+        for (group, code) in [("bfpv", "1"), ("cgjkqsxz", "2"), ("dt", "3"), ("l", "4"), ("mn", "5"), ("r", "6")] as [(String, Character)] {
+            for letter in group { codes[letter] = code }
+        }
+        return codes
+    }()
+}
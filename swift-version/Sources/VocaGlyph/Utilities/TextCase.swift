@@ -0,0 +1,109 @@
+import Foundation
+
+// MARK: - TextCase
+
+/// Case conversions for the text that was just output, e.g. "make that title case" or
+/// "computer, all caps" — both only with voice commands on. `OutputService` deletes the
+/// last insertion and re-types it, if the same field still has the focus.
+enum TextCase: String, CaseIterable {
+    case upper
+    case lower
+    case title
+    case snake
+    case camel
+    case kebab
+
+    var title: String {
+        switch self {
+        case .upper: return "ALL CAPS"
+        case .lower: return "lowercase"
+        case .title: return "Title Case"
+        case .snake: return "snake_case"
+        case .camel: return "camelCase"
+        case .kebab: return "kebab-case"
+        }
+    }
+
+    /// Spoken names, as normalized words.
+    static let spokenNames: [String: TextCase] = [
+        "all caps": .upper, "uppercase": .upper, "upper case": .upper, "caps": .upper,
+        "lowercase": .lower, "lower case": .lower, "no caps": .lower,
+        "title case": .title, "title": .title,
+        "snake case": .snake, "snake": .snake,
+        "camel case": .camel, "camel": .camel,
+        "kebab case": .kebab, "kebab": .kebab,
+    ]
+
+    /// Words that point at the text just output.
+    private static let references: Set<String> = ["that", "this", "it"]
+
+    /// Parses a whole utterance that asks for the last output in another case: "make that
+    /// title case", "all caps that", "change it to snake case". With `requireReference`
+    /// (no voice-command prefix) "that", "this" or "it" must be said, so dictating the
+    /// words "title case" alone still pastes them.
+    static func command(in text: String, requireReference: Bool = true) -> TextCase? {
+        var words = text.lowercased()
+            .replacingOccurrences(of: "-", with: " ")
+            .replacingOccurrences(of: "_", with: " ")
+            .split(whereSeparator: \.isWhitespace)
+            .map { $0.filter { $0.isLetter || $0.isNumber } }
+            .filter { !$0.isEmpty && $0 != "please" }
+
+        var referenced = false
+        if let first = words.first, ["make", "change", "convert", "turn"].contains(first) {
+            words.removeFirst()
+        }
+        if let first = words.first, references.contains(first) {
+            words.removeFirst()
+            referenced = true
+        }
+        if words.first == "to" || words.first == "into" { words.removeFirst() }
+        if let last = words.last, references.contains(last) {
+            words.removeLast()
+            referenced = true
+        }
+        guard referenced || !requireReference else { return nil }
+        return spokenNames[words.joined(separator: " ")]
+    }
+
+    // MARK: - Conversion
+
+    func apply(_ text: String) -> String {
+        switch self {
+        case .upper:
+            return text.uppercased()
+        case .lower:
+            return text.lowercased()
+        case .title:
+            return text.split(separator: " ", omittingEmptySubsequences: false)
+                .map { Self.capitalizingFirst(String($0)) }
+                .joined(separator: " ")
+        case .snake:
+            return Self.identifierWords(of: text).joined(separator: "_")
+        case .kebab:
+            return Self.identifierWords(of: text).joined(separator: "-")
+        case .camel:
+            let words = Self.identifierWords(of: text)
+            guard let first = words.first else { return "" }
+            return first + words.dropFirst().map(Self.capitalizingFirst).joined()
+        }
+    }
+
+    // MARK: - Helpers
+
+    /// Lowercased letters and digits split on everything else: "Hello, World!" →
+    /// ["hello", "world"].
+    private static func identifierWords(of text: String) -> [String] {
+        text.lowercased()
+            .split(whereSeparator: { !($0.isLetter || $0.isNumber) })
+            .map(String.init)
+    }
+
+    /// Capitalizes the first letter and lowercases the rest, except words already
+    /// written with inner capitals ("iPhone", "NASA") which are left alone.
+    private static func capitalizingFirst(_ word: String) -> String {
+        guard let first = word.first else { return word }
+        if word.dropFirst().contains(where: \.isUppercase) { return word }
+        return first.uppercased() + word.dropFirst()
+    }
+}
@@ -0,0 +1,115 @@
+import Foundation
+
+// MARK: - TextRedactor
+
+/// Stateless utility that masks sensitive text before it is persisted.
+///
+/// Applied to every transcription `HistoryService` stores and, when `redactLogs` is on,
+/// to every line the log files receive. The text typed into the target app is never
+/// changed. Built-in rules:
+/// - **creditCard**: 13–19 digits, optionally grouped by spaces or dashes, that pass
+///   the Luhn check — so phone numbers and order ids are left alone.
+/// - **email**: anything shaped like `name@domain.tld`.
+///
+/// Custom patterns are `NSRegularExpression` syntax, matched case-insensitively.
+/// Invalid patterns are skipped here and reported by `SettingsValidator`.
+enum TextRedactor {
+
+    enum Rule: String, CaseIterable {
+        case creditCard
+        case email
+
+        var placeholder: String {
+            switch self {
+            case .creditCard: return "[credit card]"
+            case .email:      return "[email]"
+            }
+        }
+
+        fileprivate var pattern: String {
+            switch self {
+            case .creditCard: return #"(?<!\d)\d(?:[ -]?\d){12,18}(?!\d)"#
+            case .email:      return #"[A-Z0-9._%+-]+@[A-Z0-9.-]+\.[A-Z]{2,}"#
+            }
+        }
+    }
+
+    /// Replacement for matches of custom patterns.
+    static let customPlaceholder = "[redacted]"
+
+    /// Applies the user's rules when `redactionEnabled` is on; otherwise returns `text`.
+    static func redact(_ text: String, settings: AppSettings) -> String {
+        guard settings.redactionEnabled else { return text }
+        return redact(text,
+                      rules: settings.redactionRules.compactMap(Rule.init(rawValue:)),
+                      customPatterns: settings.redactionCustomPatterns)
+    }
+
+    /// Like `redact(_:settings:)`, but only when log redaction is also on.
+    static func redactForLog(_ message: String, settings: AppSettings) -> String {
+        settings.redactLogs ? redact(message, settings: settings) : message
+    }
+
+    static func redact(_ text: String, rules: [Rule], customPatterns: [String] = []) -> String {
+        var current = text
+        for rule in rules {
+            guard let regex = regex(for: rule.pattern) else { continue }
+            current = replaceMatches(of: regex, in: current) { match in
+                rule == .creditCard && !passesLuhnCheck(match) ? nil : rule.placeholder
+            }
+        }
+        for pattern in customPatterns where !pattern.isEmpty {
+            guard let regex = regex(for: pattern) else { continue }
+            current = replaceMatches(of: regex, in: current) { _ in customPlaceholder }
+        }
+        return current
+    }
+
+    /// `true` when `pattern` compiles; used by `SettingsValidator`.
+    static func isValidPattern(_ pattern: String) -> Bool {
+        (try? NSRegularExpression(pattern: pattern, options: .caseInsensitive)) != nil
+    }
+
+    /// Luhn checksum over the digits of `candidate`, ignoring separators.
+    static func passesLuhnCheck(_ candidate: String) -> Bool {
+        let digits = candidate.compactMap(\.wholeNumberValue)
+        guard digits.count >= 13 else { return false }
+        let sum = digits.reversed().enumerated().reduce(0) { sum, pair in
+            let (index, digit) = pair
+            guard index % 2 == 1 else { return sum + digit }
+            let doubled = digit * 2
+            return sum + (doubled > 9 ? doubled - 9 : doubled)
+        }
+        return sum % 10 == 0
+    }
+
+    // MARK: - Helpers
+
+    /// Replaces each match with `replacement(match)`, or keeps it when that returns `nil`.
+    private static func replaceMatches(of regex: NSRegularExpression, in text: String,
+                                       with replacement: (String) -> String?) -> String {
+        let matches = regex.matches(in: text, range: NSRange(text.startIndex..., in: text))
+        guard !matches.isEmpty else { return text }
+        var result = text
+        // Back to front so earlier ranges stay valid.
+        for match in matches.reversed() {
+            guard let range = Range(match.range, in: result),
+                  let placeholder = replacement(String(result[range])) else { continue }
+            result.replaceSubrange(range, with: placeholder)
+        }
+        return result
+    }
+
+    private static let cacheLock = NSLock()
+    private static var cache: [String: NSRegularExpression] = [:]
+
+    /// Compiled patterns are cached: log redaction runs on every log line.
+    private static func regex(for pattern: String) -> NSRegularExpression? {
+        cacheLock.lock()
+        defer { cacheLock.unlock() }
+        if let cached = cache[pattern] { return cached }
+        guard let regex = try? NSRegularExpression(pattern: pattern, options: .caseInsensitive) else { return nil }
+        cache[pattern] = regex
+        return regex
+    }
+}
@@ -0,0 +1,43 @@
+import CryptoKit
+import Foundation
+
+// MARK: - TranscriptLogging
+
+/// How dictated text, captured context and prompts built from them appear in the logs,
+/// chosen with the `transcriptLogging` setting. Applied by `Logger.transcript(_:)`.
+///
+/// - `full`: verbatim, as before.
+/// - `truncated`: the first few characters and the length — enough to tell dictations apart.
+/// - `hashed`: a short SHA256 prefix and the length, so the same text can be matched up
+///   across log lines without being readable.
+///
+/// Incognito mode still hides the text entirely, whatever the choice.
+enum TranscriptLogging: String, CaseIterable {
+    case full
+    case truncated
+    case hashed
+
+    /// Characters kept by `truncated`.
+    static let truncatedLength = 12
+
+    var title: String {
+        switch self {
+        case .full:      return "Full text"
+        case .truncated: return "First few characters"
+        case .hashed:    return "Hash only"
+        }
+    }
+
+    func render(_ text: String) -> String {
+        switch self {
+        case .full:
+            return text
+        case .truncated:
+            guard text.count > Self.truncatedLength else { return "<\(text.count) chars>" }
+            return "\(text.prefix(Self.truncatedLength))… <\(text.count) chars>"
+        case .hashed:
+            let digest = SHA256.hash(data: Data(text.utf8)).map { String(format: "%02x", $0) }.joined()
+            return "<sha256:\(digest.prefix(12)), \(text.count) chars>"
+        }
+    }
+}
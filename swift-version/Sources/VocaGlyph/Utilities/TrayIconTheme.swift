@@ -0,0 +1,130 @@
+import AppKit
+
+// MARK: - TrayIcon
+
+/// What the menu bar icon is telling the user.
+enum TrayIcon: String, CaseIterable {
+    case idle
+    /// Idle while dictation is paused by voice command.
+    case paused
+    /// Idle with incognito mode on.
+    case incognito
+    case initializing
+    case recording
+    case processing
+    case error
+
+    /// Short Dock badge when `showDockIcon` is on; `nil` clears it.
+    var dockBadge: String? {
+        switch self {
+        case .idle, .incognito: return nil
+        case .paused:           return "Paused"
+        case .initializing:     return "Loading"
+        case .recording:        return "REC"
+        case .processing:       return "…"
+        case .error:            return "!"
+        }
+    }
+
+    /// The icon for `state`; `paused` and `incognito` only matter while resting.
+    static func `for`(_ state: AppState, dictationPaused: Bool, incognito: Bool) -> TrayIcon {
+        switch state {
+        case .initializing: return .initializing
+        case .recording:    return .recording
+        case .processing:   return .processing
+        case .error:        return .error
+        case .idle, .paused:
+            if dictationPaused || state == .paused { return .paused }
+            return incognito ? .incognito : .idle
+        }
+    }
+}
+
+// MARK: - TrayIconTheme
+
+/// The menu bar icon set, chosen with the `trayIconTheme` setting.
+///
+/// - `standard`: the app icon at rest and colored symbols for each state.
+/// - `monochrome`: the same symbols as template images, tinted by the menu bar like
+///   system icons.
+/// - `highContrast`: a different shape for every state, bold, in colors from the
+///   Okabe–Ito palette so no two states rely on telling red from green.
+enum TrayIconTheme: String, CaseIterable {
+    case standard
+    case monochrome
+    case highContrast
+
+    var title: String {
+        switch self {
+        case .standard:     return "Standard"
+        case .monochrome:   return "Monochrome"
+        case .highContrast: return "High Contrast"
+        }
+    }
+
+    /// SF Symbol and color for `icon`. `nil` for the standard idle icon, which is the app's
+    /// own artwork; a `nil` color means a template image.
+    func symbol(for icon: TrayIcon) -> (name: String, color: NSColor?)? {
+        switch self {
+        case .standard:
+            switch icon {
+            case .idle:         return nil
+            case .paused:       return ("pause.circle.fill", .systemOrange)
+            case .incognito:    return ("eye.slash.fill", .systemPurple)
+            case .initializing: return ("gearshape.fill", .systemYellow)
+            case .recording:    return ("waveform.circle.fill", .systemRed)
+            case .processing:   return ("hourglass.circle.fill", .systemOrange)
+            case .error:        return ("exclamationmark.triangle.fill", .systemRed)
+            }
+        case .monochrome:
+            return (TrayIconTheme.standard.symbol(for: icon)?.name ?? "mic.fill", nil)
+        case .highContrast:
+            switch icon {
+            case .idle:         return ("mic.fill", nil)
+            case .paused:       return ("pause.circle.fill", Self.okabeIto(0xE69F00))
+            case .incognito:    return ("eye.slash.fill", Self.okabeIto(0xCC79A7))
+            case .initializing: return ("arrow.triangle.2.circlepath.circle.fill", Self.okabeIto(0x56B4E9))
+            case .recording:    return ("record.circle.fill", Self.okabeIto(0xD55E00))
+            case .processing:   return ("hourglass.circle.fill", Self.okabeIto(0x0072B2))
+            case .error:        return ("exclamationmark.octagon.fill", nil)
+            }
+        }
+    }
+
+    func image(for icon: TrayIcon) -> NSImage? {
+        let description = icon == .idle ? "VocaGlyph" : "VocaGlyph (\(icon.rawValue))"
+        guard let symbol = symbol(for: icon) else { return Self.appIcon() }
+        guard var image = NSImage(systemSymbolName: symbol.name, accessibilityDescription: description) else { return nil }
+        if self == .highContrast {
+            image = image.withSymbolConfiguration(.init(pointSize: 15, weight: .bold)) ?? image
+        }
+        guard let color = symbol.color else {
+            image.isTemplate = true
+            return image
+        }
+        return image.withSymbolConfiguration(.init(paletteColors: [color])) ?? image
+    }
+
+    // MARK: - Helpers
+
+    private static func okabeIto(_ rgb: Int) -> NSColor {
+        NSColor(srgbRed: CGFloat((rgb >> 16) & 0xFF) / 255,
+                 green: CGFloat((rgb >> 8) & 0xFF) / 255,
+                 blue: CGFloat(rgb & 0xFF) / 255,
+                 alpha: 1)
+    }
+
+    private static func appIcon() -> NSImage? {
+        let imgUrl = Bundle.main.url(forResource: "appbaricon", withExtension: "png")
+                  ?? Bundle.module.url(forResource: "appbaricon", withExtension: "png")
+        guard let imgUrl, let nsImage = NSImage(contentsOf: imgUrl) else {
+            return NSImage(systemSymbolName: "mic.fill", accessibilityDescription: "VocaGlyph")
+        }
+        // Resize to menu bar icon dimensions
+        nsImage.size = NSSize(width: 18, height: 18)
+        // isTemplate = false for full-color PNGs.
+        // Use true only if the icon is a black+transparent template design.
+        nsImage.isTemplate = false
+        return nsImage
+    }
+}
@@ -0,0 +1,106 @@
+import Foundation
+
+// MARK: - VoiceCommand
+
+/// An app-control phrase spoken after the command prefix instead of dictated text,
+/// e.g. "computer, switch to Spanish" or "computer use the small model".
+///
+/// The whole utterance must be the command — "computer science is hard" doesn't match
+/// anything and is pasted as usual.
+enum VoiceCommand: Equatable {
+    /// Sets `dictationLanguage` to a `WhisperService.supportedDictationLanguages` label.
+    case switchLanguage(String)
+    /// Sets `selectedModel` to a transcription model id.
+    case useModel(String)
+    /// Drops dictated text until `resumeDictation`; commands still work.
+    case pauseDictation
+    case resumeDictation
+    /// Re-types the text just output in another case.
+    case changeCase(TextCase)
+
+    /// Spoken language names, keyed to their Settings label.
+    static let languages: [String: String] = [
+        "english": "English (US)",
+        "spanish": "Spanish (ES)", "español": "Spanish (ES)",
+        "french": "French (FR)", "français": "French (FR)",
+        "german": "German (DE)", "deutsch": "German (DE)",
+        "indonesian": "Indonesian (ID)", "bahasa": "Indonesian (ID)",
+        "auto detect": "Auto-Detect", "autodetect": "Auto-Detect", "automatic": "Auto-Detect",
+    ]
+
+    /// Spoken model names, keyed to their model id.
+    static let models: [String: String] = [
+        "small": "small",
+        "medium": "medium",
+        "large": "large-v3",
+        "turbo": "large-v3_turbo", "large turbo": "large-v3_turbo",
+        "distil": "distil-whisper_distil-large-v3", "distilled": "distil-whisper_distil-large-v3",
+        "parakeet": "parakeet-v3", "parakeet v3": "parakeet-v3", "parakeet v2": "parakeet-v2",
+        "apple": "apple-native", "native": "apple-native", "apple native": "apple-native",
+    ]
+
+    var summary: String {
+        switch self {
+        case .switchLanguage(let label): return "switch language to \(label)"
+        case .useModel(let id):          return "use model \(id)"
+        case .pauseDictation:            return "pause dictation"
+        case .resumeDictation:           return "resume dictation"
+        case .changeCase(let textCase):  return "change last output to \(textCase.title)"
+        }
+    }
+
+    /// Parses `text` as `prefix` followed by a command. Returns `nil` when the prefix is
+    /// missing or empty, or when what follows it isn't a known command.
+    static func parse(_ text: String, prefix: String) -> VoiceCommand? {
+        let prefixWords = words(of: prefix)
+        let spoken = words(of: text)
+        guard !prefixWords.isEmpty, spoken.starts(with: prefixWords) else { return nil }
+        var rest = Array(spoken.dropFirst(prefixWords.count))
+        // Politeness doesn't change the command.
+        rest.removeAll { $0 == "please" }
+        return command(rest.joined(separator: " "))
+    }
+
+    // MARK: - Helpers
+
+    private static func command(_ phrase: String) -> VoiceCommand? {
+        switch phrase {
+        case "pause dictation", "pause", "stop dictation", "stop listening":
+            return .pauseDictation
+        case "resume dictation", "resume", "start dictation", "start listening":
+            return .resumeDictation
+        default:
+            break
+        }
+
+        // "all caps", "make that title case"
+        if let textCase = TextCase.command(in: phrase, requireReference: false) { return .changeCase(textCase) }
+
+        // "use the small model", "switch to the large turbo model", "use parakeet"
+        for verb in ["switch to", "change to", "use"] where phrase.hasPrefix(verb + " ") {
+            var name = String(phrase.dropFirst(verb.count + 1))
+            if name.hasPrefix("the ") { name.removeFirst(4) }
+            let isModel = name.hasSuffix(" model")
+            if isModel { name.removeLast(6) }
+            if let id = models[name] { return .useModel(id) }
+            // "switch to Spanish", "use English"; a language never ends in "model".
+            if !isModel, let label = languages[name] { return .switchLanguage(label) }
+        }
+
+        // "dictate in French", "speak German"
+        for verb in ["dictate in", "speak", "language"] where phrase.hasPrefix(verb + " ") {
+            if let label = languages[String(phrase.dropFirst(verb.count + 1))] { return .switchLanguage(label) }
+        }
+        return nil
+    }
+
+    /// Lowercased words without punctuation, with hyphens as spaces: "Auto-detect." →
+    /// ["auto", "detect"].
+    private static func words(of text: String) -> [String] {
+        text.lowercased()
+            .replacingOccurrences(of: "-", with: " ")
+            .split(whereSeparator: \.isWhitespace)
+            .map { $0.filter { $0.isLetter || $0.isNumber } }
+            .filter { !$0.isEmpty }
+    }
+}
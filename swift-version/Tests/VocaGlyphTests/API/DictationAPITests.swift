@@ -0,0 +1,72 @@
+import XCTest
+import SwiftData
+@testable import VocaGlyph
+
+@MainActor
+final class DictationAPITests: XCTestCase {
+
+    private var container: ModelContainer!
+    private var stateManager: AppStateManager!
+    private var sut: DictationAPI!
+
+    override func setUpWithError() throws {
+        let schema = Schema([DictationTemplate.self])
+        container = try ModelContainer(for: schema, configurations: [ModelConfiguration(schema: schema, isStoredInMemoryOnly: true)])
+        stateManager = AppStateManager()
+        sut = DictationAPI(stateManager: stateManager,
+                           templates: TemplateService(container: container),
+                           triggers: { nil },
+                           output: { nil })
+    }
+
+    override func tearDownWithError() throws {
+        sut = nil
+        stateManager = nil
+        container = nil
+    }
+
+    func testPauseOnlyChangesWhenNeeded() {
+        XCTAssertTrue(sut.canChangePause)
+        sut.setPaused(true)
+        XCTAssertTrue(stateManager.isDictationPaused)
+        sut.setPaused(true)
+        XCTAssertTrue(stateManager.isDictationPaused)
+        sut.togglePause()
+        XCTAssertFalse(stateManager.isDictationPaused)
+    }
+
+    func testNothingHappensBeforeCoreServicesStart() {
+        sut.handleTrigger(.start)
+        XCTAssertEqual(stateManager.currentState, .idle)
+        XCTAssertFalse(sut.changeCaseOfLastOutput(to: .upper))
+    }
+
+    func testClipboardDictationThatCannotStartIsNotKept() {
+        sut.toggleClipboardDictation()
+        XCTAssertEqual(stateManager.currentState, .idle)
+        XCTAssertFalse(sut.isDictatingToClipboard)
+    }
+
+    func testFillingATemplateUpdatesTheSlotHint() throws {
+        let template = try XCTUnwrap(sut.createTemplate(name: "Standup", body: "Yesterday {yesterday}, today {today}."))
+        XCTAssertFalse(sut.startTemplate(UUID()))
+        XCTAssertTrue(sut.startTemplate(template.id))
+        XCTAssertEqual(stateManager.templateSlotHint, "Standup: yesterday")
+
+        XCTAssertEqual(sut.fillTemplate(with: "reviews")?.isComplete, false)
+        XCTAssertEqual(stateManager.templateSlotHint, "Standup: today")
+        let done = try XCTUnwrap(sut.fillTemplate(with: "tests"))
+        XCTAssertEqual(done.rendered, "Yesterday reviews, today tests.")
+        XCTAssertNil(sut.activeTemplate)
+        XCTAssertNil(stateManager.templateSlotHint)
+    }
+
+    func testCancellingClearsTheSlotHint() throws {
+        let template = try XCTUnwrap(sut.createTemplate(name: "Bug", body: "Steps: {steps}"))
+        sut.startTemplate(template.id)
+        sut.cancelTemplate()
+        XCTAssertNil(sut.activeTemplate)
+        XCTAssertNil(stateManager.templateSlotHint)
+        XCTAssertEqual(sut.allTemplates().count, 1)
+    }
+}
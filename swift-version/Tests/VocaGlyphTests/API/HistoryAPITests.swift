@@ -0,0 +1,67 @@
+import XCTest
+import SwiftData
+@testable import VocaGlyph
+
+@MainActor
+final class HistoryAPITests: XCTestCase {
+
+    private var container: ModelContainer!
+    private var output: OutputService?
+    private var sut: HistoryAPI!
+
+    override func setUpWithError() throws {
+        let schema = Schema([TranscriptionItem.self, Snippet.self])
+        container = try ModelContainer(for: schema, configurations: [ModelConfiguration(schema: schema, isStoredInMemoryOnly: true)])
+        output = OutputService()
+        sut = HistoryAPI(history: HistoryService(container: container),
+                         snippets: SnippetService(container: container),
+                         output: { [unowned self] in self.output })
+    }
+
+    override func tearDownWithError() throws {
+        sut = nil
+        output = nil
+        container = nil
+    }
+
+    func testLastTextIsTheNewestEntry() throws {
+        let now = Date()
+        container.mainContext.insert(TranscriptionItem(text: "Older", timestamp: now.addingTimeInterval(-60)))
+        container.mainContext.insert(TranscriptionItem(text: "Newest", timestamp: now))
+        try container.mainContext.save()
+
+        XCTAssertEqual(sut.recent(limit: 1).map(\.text), ["Newest"])
+        XCTAssertEqual(sut.lastText(), "Newest")
+    }
+
+    func testNothingToPasteWithoutHistoryOrOutput() {
+        XCTAssertNil(sut.lastText())
+        XCTAssertFalse(sut.pasteLast())
+
+        output = nil
+        XCTAssertFalse(sut.copy(UUID()))
+        XCTAssertFalse(sut.pasteSnippet(UUID()))
+    }
+
+    func testPinnedSnippetsCanBeDeleted() throws {
+        let item = TranscriptionItem(text: "Kind regards")
+        container.mainContext.insert(item)
+        try container.mainContext.save()
+
+        let snippet = try XCTUnwrap(sut.pin(item.id, name: "Sign-off"))
+        XCTAssertEqual(sut.allSnippets().map(\.name), ["Sign-off"])
+        XCTAssertTrue(sut.deleteSnippet(snippet.id))
+        XCTAssertTrue(sut.allSnippets().isEmpty)
+        XCTAssertNil(sut.pin(UUID()))
+    }
+
+    func testClearNeedsTheConfirmationToken() throws {
+        container.mainContext.insert(TranscriptionItem(text: "Secret"))
+        try container.mainContext.save()
+
+        XCTAssertThrowsError(try sut.clear(confirmationToken: "guess"))
+        let token = try XCTUnwrap(sut.clearConfirmationToken())
+        XCTAssertEqual(try sut.clear(confirmationToken: token), 1)
+        XCTAssertTrue(sut.recent(limit: 5).isEmpty)
+    }
+}
@@ -0,0 +1,110 @@
+import XCTest
+@testable import VocaGlyph
+
+private final class MockModelEngine: ModelEngineService {
+    var downloadedModels: Set<String> = []
+    var corruptModels: Set<String> = []
+    var modelsDiskUsage: Int64 = 0
+    var canPause = false
+    var cancelled: [String] = []
+    var repaired: [String] = []
+
+    func status(for model: String) -> ModelStatus {
+        var status = ModelStatus.notDownloaded(model)
+        if downloadedModels.contains(model) { status.state = .downloaded }
+        return status
+    }
+
+    func pauseDownload(_ model: String) -> Bool { canPause }
+
+    func cancelDownload(_ model: String) -> Bool {
+        cancelled.append(model)
+        return true
+    }
+
+    func repairModel(_ model: String) { repaired.append(model) }
+    func checkDownloadedModels() {}
+}
+
+final class ModelsAPITests: XCTestCase {
+
+    private let suiteName = "ModelsAPITests"
+    private var defaults: UserDefaults!
+    private var root: URL!
+    private var whisper: MockModelEngine?
+    private var parakeet: MockModelEngine?
+    private var sut: ModelsAPI!
+
+    override func setUpWithError() throws {
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+        root = FileManager.default.temporaryDirectory
+            .appendingPathComponent("ModelsAPITests-\(UUID().uuidString)", isDirectory: true)
+        whisper = MockModelEngine()
+        parakeet = MockModelEngine()
+
+        let store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+        let updater = SettingsUpdater(store: store, applier: MockSettingsApplier(), downloadedModels: { [unowned self] in
+            self.sut.downloadedModelIds()
+        })
+        let settings = SettingsAPI(store: store, updater: updater, backups: SettingsBackupService(directory: root))
+        sut = ModelsAPI(whisper: { [unowned self] in self.whisper },
+                        parakeet: { [unowned self] in self.parakeet },
+                        settings: settings,
+                        registry: ModelRegistry(dataRoot: root))
+    }
+
+    override func tearDownWithError() throws {
+        defaults.removePersistentDomain(forName: suiteName)
+        try? FileManager.default.removeItem(at: root)
+        sut = nil
+    }
+
+    func testDownloadedModelsNeedBothEngines() {
+        whisper?.downloadedModels = ["small"]
+        parakeet?.downloadedModels = ["parakeet-v3"]
+        XCTAssertEqual(sut.downloadedModelIds(), ["small", "parakeet-v3"])
+
+        parakeet = nil
+        XCTAssertNil(sut.downloadedModelIds())
+        XCTAssertNil(sut.status(for: "parakeet-v3"))
+    }
+
+    func testSandboxedImportCopiesInsteadOfLinking() throws {
+        let source = root.appendingPathComponent("source/Whisper-Sandboxed", isDirectory: true)
+        for component in ModelRegistry.requiredComponents {
+            let bundle = source.appendingPathComponent(component, isDirectory: true)
+            try FileManager.default.createDirectory(at: bundle, withIntermediateDirectories: true)
+            try Data([0x01]).write(to: bundle.appendingPathComponent("coremldata.bin"))
+        }
+
+        let model = try sut.importModel(atPath: source.path, symlink: true, sandboxed: true)
+
+        let folder = ModelRegistry(dataRoot: root).folderURL(for: model)
+        let type = try FileManager.default.attributesOfItem(atPath: folder.path)[.type] as? FileAttributeType
+        XCTAssertEqual(type, .typeDirectory)
+    }
+
+    func testRequestsAreRoutedByModelId() {
+        XCTAssertTrue(sut.cancelDownload("parakeet-v3"))
+        XCTAssertTrue(sut.cancelDownload("small"))
+        XCTAssertEqual(parakeet?.cancelled, ["parakeet-v3"])
+        XCTAssertEqual(whisper?.cancelled, ["small"])
+
+        XCTAssertNil(sut.status(for: "apple-native"), "Built-in models need no download")
+        XCTAssertFalse(sut.statuses().contains { $0.model == "apple-native" })
+    }
+
+    func testOnlyCorruptModelsAreRepaired() {
+        whisper?.corruptModels = ["small"]
+        XCTAssertFalse(sut.repair("base"))
+        XCTAssertTrue(sut.repair("small"))
+        XCTAssertEqual(whisper?.repaired, ["small"])
+    }
+
+    func testSwitchModelRejectsModelsThatAreNotDownloaded() {
+        XCTAssertNotNil(sut.switchModel(to: "small"))
+        whisper?.downloadedModels = ["small"]
+        XCTAssertNil(sut.switchModel(to: "small"))
+    }
+}
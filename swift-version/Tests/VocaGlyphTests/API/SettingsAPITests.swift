@@ -0,0 +1,71 @@
+import XCTest
+@testable import VocaGlyph
+
+final class SettingsAPITests: XCTestCase {
+
+    private let suiteName = "SettingsAPITests"
+    private var defaults: UserDefaults!
+    private var store: SettingsStore!
+    private var applier: MockSettingsApplier!
+    private var backupDirectory: URL!
+    private var sut: SettingsAPI!
+
+    override func setUp() {
+        super.setUp()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+        store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+        applier = MockSettingsApplier()
+        backupDirectory = FileManager.default.temporaryDirectory
+            .appendingPathComponent("SettingsAPITests-\(UUID().uuidString)", isDirectory: true)
+        sut = SettingsAPI(store: store,
+                          updater: SettingsUpdater(store: store, applier: applier),
+                          backups: SettingsBackupService(directory: backupDirectory))
+    }
+
+    override func tearDown() {
+        defaults.removePersistentDomain(forName: suiteName)
+        try? FileManager.default.removeItem(at: backupDirectory)
+        sut = nil
+        super.tearDown()
+    }
+
+    func testSwitchLanguageIsValidated() {
+        XCTAssertNil(sut.switchLanguage(to: "French (FR)"))
+        XCTAssertEqual(sut.settings.dictationLanguage, "French (FR)")
+
+        XCTAssertNotNil(sut.switchLanguage(to: "Klingon"))
+        XCTAssertEqual(store.settings.dictationLanguage, "French (FR)")
+    }
+
+    func testResetBacksUpAndRestoreUndoesIt() throws {
+        sut.change { $0.removeFillerWords = !AppSettings.defaults.removeFillerWords }
+        let changed = sut.settings
+
+        try sut.reset()
+        XCTAssertEqual(sut.settings, .defaults)
+
+        let backup = try XCTUnwrap(sut.listBackups().first)
+        try sut.restoreBackup(backup)
+        XCTAssertEqual(sut.settings, changed)
+        XCTAssertEqual(sut.listBackups().count, 2, "The defaults being replaced are backed up too")
+    }
+
+    func testRestoringAnInapplicableBackupArchivesNothing() throws {
+        sut = SettingsAPI(store: store,
+                          updater: SettingsUpdater(store: store, applier: applier, downloadedModels: { [] }),
+                          backups: SettingsBackupService(directory: backupDirectory))
+        sut.change { $0.removeFillerWords = !AppSettings.defaults.removeFillerWords }
+        var withModel = AppSettings.defaults
+        withModel.selectedModel = "small"
+        let backupService = SettingsBackupService(directory: backupDirectory)
+        let backup = try backupService.backup(withModel)
+
+        XCTAssertThrowsError(try sut.restoreBackup(backup)) { error in
+            guard case SettingsBackupError.invalid(let issues) = error else { return XCTFail("\(error)") }
+            XCTAssertEqual(issues.map(\.field), ["selectedModel"])
+        }
+        XCTAssertEqual(sut.listBackups().count, 1)
+        XCTAssertNotEqual(sut.settings.removeFillerWords, AppSettings.defaults.removeFillerWords)
+    }
+}
@@ -0,0 +1,34 @@
+import XCTest
+@testable import VocaGlyph
+
+final class AppEventTests: XCTestCase {
+
+    func testPostedPayloadRoundTrips() {
+        let center = NotificationCenter()
+        var received: [ModelStatusChangedEvent] = []
+        let token = ModelStatusChangedEvent.observe(center: center, queue: nil) { received.append($0) }
+        defer { center.removeObserver(token) }
+
+        let event = ModelStatusChangedEvent(status: ModelStatus(model: "small", state: .downloading, percent: 40))
+        event.post(to: center)
+
+        XCTAssertEqual(received, [event])
+    }
+
+    func testOtherEventsAndSchemaVersionsAreIgnored() {
+        let queueChanged = Notification(name: .modelDownloadQueueChanged, object: nil, userInfo: [
+            AppEventSchema.payloadKey: ModelDownloadQueueChangedEvent(queue: ["small"]),
+            AppEventSchema.versionKey: AppEventSchema.version,
+        ])
+        XCTAssertNil(ModelStatusChangedEvent(queueChanged))
+        XCTAssertEqual(ModelDownloadQueueChangedEvent(queueChanged)?.queue, ["small"])
+
+        let legacy = Notification(name: .configReloaded, object: nil, userInfo: ["keys": ["selectedModel"]])
+        XCTAssertNil(ConfigReloadedEvent(legacy))
+    }
+
+    func testEveryEventHasItsOwnName() {
+        let names = AppEventSchema.events.map { $0.name }
+        XCTAssertEqual(Set(names).count, names.count)
+    }
+}
@@ -0,0 +1,73 @@
+import XCTest
+@testable import VocaGlyph
+
+final class AppStateMachineTests: XCTestCase {
+
+    func testDictationCycle() {
+        var machine = AppStateMachine()
+        XCTAssertTrue(machine.handle(.startRecording))
+        XCTAssertEqual(machine.state, .recording)
+        XCTAssertTrue(machine.handle(.stopRecording))
+        XCTAssertEqual(machine.state, .processing)
+        XCTAssertTrue(machine.handle(.finish))
+        XCTAssertEqual(machine.state, .idle)
+    }
+
+    func testOutOfOrderEventsAreIgnored() {
+        var machine = AppStateMachine()
+        XCTAssertFalse(machine.handle(.stopRecording))
+        XCTAssertFalse(machine.handle(.finish))
+        XCTAssertFalse(machine.handle(.modelLoaded))
+        XCTAssertEqual(machine.state, .idle)
+
+        machine.handle(.startRecording)
+        XCTAssertFalse(machine.handle(.startRecording))
+        XCTAssertFalse(machine.handle(.beginModelLoad), "a model load must not interrupt a session")
+        XCTAssertEqual(machine.state, .recording)
+    }
+
+    func testModelLoading() {
+        var machine = AppStateMachine()
+        machine.handle(.beginModelLoad)
+        XCTAssertEqual(machine.state, .initializing)
+        XCTAssertFalse(machine.handle(.startRecording))
+        machine.handle(.modelLoaded)
+        XCTAssertEqual(machine.state, .idle)
+    }
+
+    func testPausedIsTheRestingStateWhilePaused() {
+        var machine = AppStateMachine()
+        machine.handle(.pause)
+        XCTAssertEqual(machine.state, .paused)
+
+        // Recording still works so "resume dictation" can be heard.
+        machine.handle(.startRecording)
+        machine.handle(.stopRecording)
+        machine.handle(.finish)
+        XCTAssertEqual(machine.state, .paused)
+
+        machine.handle(.resume)
+        XCTAssertEqual(machine.state, .idle)
+        XCTAssertFalse(machine.isPaused)
+    }
+
+    func testPauseDuringSessionTakesEffectWhenItEnds() {
+        var machine = AppStateMachine()
+        machine.handle(.startRecording)
+        machine.handle(.pause)
+        XCTAssertEqual(machine.state, .recording)
+        machine.handle(.finish)
+        XCTAssertEqual(machine.state, .paused)
+    }
+
+    func testErrorIsClearedByNextRecording() {
+        var machine = AppStateMachine()
+        machine.handle(.startRecording)
+        machine.handle(.fail("Transcription failed"))
+        XCTAssertEqual(machine.state, .error("Transcription failed"))
+        XCTAssertTrue(machine.state.isResting)
+
+        machine.handle(.startRecording)
+        XCTAssertEqual(machine.state, .recording)
+    }
+}
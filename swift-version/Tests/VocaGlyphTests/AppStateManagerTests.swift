@@ -5,6 +5,9 @@ import AVFoundation
 class MockAppStateManagerDelegate: AppStateManagerDelegate {
     var lastStateReceived: AppState?
     var lastTranscribedText: String?
+    var lastCommand: VoiceCommand?
+    var lastTick: (elapsed: Int, remaining: Int?)?
+    var limitWarnings: [Int] = []
     
     func appStateDidChange(newState: AppState) {
         lastStateReceived = newState
@@ -13,6 +16,22 @@ class MockAppStateManagerDelegate: AppStateManagerDelegate {
     func appStateManagerDidTranscribe(text: String) {
         lastTranscribedText = text
     }
+
+    func appStateManagerDidRecognize(command: VoiceCommand) {
+        lastCommand = command
+    }
+
+    func appStateManagerDidSummarize(verbatim: String, summary: String) {
+        lastTranscribedText = summary
+    }
+
+    func appStateManagerRecordingDidTick(elapsed: Int, remaining: Int?) {
+        lastTick = (elapsed, remaining)
+    }
+
+    func appStateManagerRecordingWillReachLimit(remaining: Int) {
+        limitWarnings.append(remaining)
+    }
 }
 
 final class AppStateManagerTests: XCTestCase {
@@ -56,6 +75,105 @@ final class AppStateManagerTests: XCTestCase {
         XCTAssertEqual(mockDelegate.lastStateReceived, .idle)
     }
     
+    func testRecordingStartTimeIsTrackedForTheOverlay() {
+        let manager = AppStateManager()
+        manager.startRecording()
+        XCTAssertNotNil(manager.recordingStartedAt)
+
+        manager.inputLevel = 0.2
+        manager.setIdle()
+        XCTAssertNil(manager.recordingStartedAt)
+        XCTAssertEqual(manager.inputLevel, 0)
+    }
+
+    func testClockString() {
+        XCTAssertEqual(AppStateManager.clockString(7), "0:07")
+        XCTAssertEqual(AppStateManager.clockString(102), "1:42")
+    }
+
+    func testRecordingClockWarnsOnceThenStopsAtLimit() throws {
+        let manager = AppStateManager()
+        let mockDelegate = MockAppStateManagerDelegate()
+        manager.delegate = mockDelegate
+        let limit = 60
+        let previousLimit = SettingsStore.shared.settings.maxRecordingSeconds
+        SettingsStore.shared.update { $0.maxRecordingSeconds = limit }
+        defer { SettingsStore.shared.update { $0.maxRecordingSeconds = previousLimit } }
+
+        manager.startRecording()
+        let start = try XCTUnwrap(manager.recordingStartedAt)
+
+        manager.tickRecordingClock(now: start.addingTimeInterval(5))
+        XCTAssertEqual(mockDelegate.lastTick?.elapsed, 5)
+        XCTAssertEqual(mockDelegate.lastTick?.remaining, limit - 5)
+        XCTAssertTrue(mockDelegate.limitWarnings.isEmpty)
+
+        let warningAt = TimeInterval(limit - AppStateManager.recordingWarningSeconds)
+        manager.tickRecordingClock(now: start.addingTimeInterval(warningAt))
+        manager.tickRecordingClock(now: start.addingTimeInterval(warningAt + 1))
+        XCTAssertEqual(mockDelegate.limitWarnings, [AppStateManager.recordingWarningSeconds])
+
+        manager.tickRecordingClock(now: start.addingTimeInterval(TimeInterval(limit)))
+        XCTAssertEqual(manager.currentState, .processing)
+    }
+
+    func testRecordingClockWithoutLimitNeverStops() throws {
+        let manager = AppStateManager()
+        let mockDelegate = MockAppStateManagerDelegate()
+        manager.delegate = mockDelegate
+        let previousLimit = SettingsStore.shared.settings.maxRecordingSeconds
+        SettingsStore.shared.update { $0.maxRecordingSeconds = 0 }
+        defer { SettingsStore.shared.update { $0.maxRecordingSeconds = previousLimit } }
+
+        manager.startRecording()
+        let start = try XCTUnwrap(manager.recordingStartedAt)
+
+        manager.tickRecordingClock(now: start.addingTimeInterval(7200))
+        XCTAssertEqual(mockDelegate.lastTick?.elapsed, 7200)
+        XCTAssertNil(mockDelegate.lastTick?.remaining)
+        XCTAssertTrue(mockDelegate.limitWarnings.isEmpty)
+        XCTAssertEqual(manager.currentState, .recording)
+    }
+
+    @MainActor
+    func testTestDictationReturnsTranscriptWithoutDelivering() async throws {
+        let manager = AppStateManager()
+        let mockEngine = MockTranscriptionEngine()
+        mockEngine.returnedText = "Testing one two"
+        manager.engineRouter = EngineRouter(engine: mockEngine)
+        let mockDelegate = MockAppStateManagerDelegate()
+        manager.delegate = mockDelegate
+
+        let dictation = Task { try await manager.recordTestDictation(duration: 0.1) }
+        try await Task.sleep(nanoseconds: 300_000_000)
+        XCTAssertEqual(manager.currentState, .processing)
+
+        let format = AVAudioFormat(standardFormatWithSampleRate: 16000, channels: 1)!
+        manager.processAudio(buffer: AVAudioPCMBuffer(pcmFormat: format, frameCapacity: 1024)!)
+
+        let transcript = try await dictation.value
+        XCTAssertEqual(transcript, "Testing one two")
+        XCTAssertNil(mockDelegate.lastTranscribedText)
+        XCTAssertTrue(manager.currentState.isResting)
+    }
+
+    func testPerformPauseAndResumeTogglesPausedAndNotifiesDelegate() {
+        let manager = AppStateManager()
+        let mockDelegate = MockAppStateManagerDelegate()
+        manager.delegate = mockDelegate
+
+        manager.perform(.pauseDictation)
+        XCTAssertTrue(manager.isDictationPaused)
+        XCTAssertEqual(mockDelegate.lastCommand, .pauseDictation)
+
+        manager.perform(.resumeDictation)
+        XCTAssertFalse(manager.isDictationPaused)
+
+        manager.perform(.useModel("small"))
+        XCTAssertFalse(manager.isDictationPaused)
+        XCTAssertEqual(mockDelegate.lastCommand, .useModel("small"))
+    }
+
     func testSwitchTranscriptionEngine() async {
         let manager = AppStateManager()
         let router = EngineRouter(engine: MockTranscriptionEngine())
@@ -168,6 +286,20 @@ final class AppStateManagerTests: XCTestCase {
         XCTAssertEqual(mockPostProcessor.didCallRefineWithText, "Raw text")
         XCTAssertEqual(mockDelegate.lastTranscribedText, "Raw text")
     }
+
+    func testReadyPostProcessingEngineOnlyWaitsForTheLocalLLM() {
+        let manager = AppStateManager()
+        XCTAssertNil(manager.readyPostProcessingEngine)
+
+        let cloud = MockPostProcessingEngine()
+        manager.postProcessingEngine = cloud
+        XCTAssertTrue(manager.readyPostProcessingEngine as? MockPostProcessingEngine === cloud)
+
+        manager.postProcessingEngine = LocalLLMEngine()
+        XCTAssertNil(manager.readyPostProcessingEngine, "The local LLM is not ready until warmed up")
+        manager.localLLMIsWarmedUp = true
+        XCTAssertNotNil(manager.readyPostProcessingEngine)
+    }
 }
 
 class MockPostProcessingEngine: PostProcessingEngine, @unchecked Sendable {
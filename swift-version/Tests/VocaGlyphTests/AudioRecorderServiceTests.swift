@@ -18,4 +18,16 @@ final class AudioRecorderServiceTests: XCTestCase {
         let output = service.stopRecording()
         XCTAssertNil(output)
     }
+
+    func testStartRecordingWithoutMicrophoneAccessThrowsBeforeTouchingTheEngine() {
+        let permissions = MockSystemPermissionsProvider()
+        permissions.microphoneStatus = .denied
+        let service = AudioRecorderService(permissions: permissions)
+
+        XCTAssertThrowsError(try service.startRecording()) { error in
+            guard case AudioRecorderError.microphoneAccessDenied = error else {
+                return XCTFail("Expected microphoneAccessDenied, got \(error)")
+            }
+        }
+    }
 }
@@ -0,0 +1,116 @@
+import XCTest
+@testable import VocaGlyph
+
+final class OpenAICompatibleEngineTests: XCTestCase {
+    var session: URLSession!
+    var keychain: KeychainService!
+    var defaults: UserDefaults!
+    var store: SettingsStore!
+    private let suiteName = "OpenAICompatibleEngineTests"
+
+    override func setUp() async throws {
+        let configuration = URLSessionConfiguration.ephemeral
+        configuration.protocolClasses = [MockURLProtocol.self]
+        session = URLSession(configuration: configuration)
+        keychain = KeychainService()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+        store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+        store.update {
+            $0.openAICompatibleEndpoint = "https://llm.example.com/v1"
+            $0.openAICompatibleModel = "test-model"
+            $0.openAICompatibleOptIn = true
+        }
+    }
+
+    override func tearDown() async throws {
+        try? await keychain.clearSecret(.openAICompatibleApiKey)
+        defaults.removePersistentDomain(forName: suiteName)
+        MockURLProtocol.requestHandler = nil
+    }
+
+    private func makeEngine() -> OpenAICompatibleEngine {
+        OpenAICompatibleEngine(store: store, keychainService: keychain, session: session)
+    }
+
+    private func completion(_ content: String) -> Data {
+        """
+        {"id": "chatcmpl-1", "choices": [{"index": 0, "message": {"role": "assistant", "content": "\(content)"}}]}
+        """.data(using: .utf8)!
+    }
+
+    func testWithoutOptInNothingIsSent() async {
+        store.update { $0.openAICompatibleOptIn = false }
+        MockURLProtocol.requestHandler = { _ in
+            XCTFail("No request may be made before the user opts in")
+            throw URLError(.cancelled)
+        }
+
+        do {
+            _ = try await makeEngine().refine(text: "Hello", prompt: "Fix grammar")
+            XCTFail("Expected notOptedIn error")
+        } catch let error as OpenAICompatibleEngineError {
+            XCTAssertEqual(error, .notOptedIn)
+        } catch {
+            XCTFail("Unexpected error: \(error)")
+        }
+    }
+
+    func testSuccessfulRefinePostsToChatCompletions() async throws {
+        try await keychain.setSecret("test_openai_key", for: .openAICompatibleApiKey)
+        MockURLProtocol.requestHandler = { [completion] request in
+            XCTAssertEqual(request.url?.absoluteString, "https://llm.example.com/v1/chat/completions")
+            XCTAssertEqual(request.httpMethod, "POST")
+            XCTAssertEqual(request.value(forHTTPHeaderField: "Authorization"), "Bearer test_openai_key")
+            let response = HTTPURLResponse(url: request.url!, statusCode: 200, httpVersion: nil, headerFields: nil)!
+            return (response, completion("Hello there."))
+        }
+
+        let result = try await makeEngine().refine(text: "hello there", prompt: "Fix grammar")
+
+        XCTAssertEqual(result, "Hello there.")
+    }
+
+    func testMissingKeySendsNoAuthorizationHeader() async throws {
+        try? await keychain.clearSecret(.openAICompatibleApiKey)
+        MockURLProtocol.requestHandler = { [completion] request in
+            XCTAssertNil(request.value(forHTTPHeaderField: "Authorization"))
+            let response = HTTPURLResponse(url: request.url!, statusCode: 200, httpVersion: nil, headerFields: nil)!
+            return (response, completion("Hello."))
+        }
+
+        let result = try await makeEngine().refine(text: "hello", prompt: "Fix grammar")
+
+        XCTAssertEqual(result, "Hello.")
+    }
+
+    func testInvalidEndpointThrows() async {
+        store.update { $0.openAICompatibleEndpoint = "" }
+
+        do {
+            _ = try await makeEngine().refine(text: "Hello", prompt: "Fix grammar")
+            XCTFail("Expected invalidURL error")
+        } catch let error as OpenAICompatibleEngineError {
+            XCTAssertEqual(error, .invalidURL)
+        } catch {
+            XCTFail("Unexpected error: \(error)")
+        }
+    }
+
+    func testAPIErrorResponseThrowsError() async {
+        MockURLProtocol.requestHandler = { request in
+            let body = #"{"error": {"message": "Incorrect API key provided", "type": "invalid_request_error"}}"#
+            let response = HTTPURLResponse(url: request.url!, statusCode: 401, httpVersion: nil, headerFields: nil)!
+            return (response, body.data(using: .utf8)!)
+        }
+
+        do {
+            _ = try await makeEngine().refine(text: "Hello", prompt: "Fix grammar")
+            XCTFail("Expected apiError")
+        } catch let error as OpenAICompatibleEngineError {
+            XCTAssertEqual(error, .apiError(statusCode: 401, message: "Incorrect API key provided"))
+        } catch {
+            XCTFail("Unexpected error: \(error)")
+        }
+    }
+}
@@ -419,6 +419,29 @@ final class ParakeetServiceTests: XCTestCase {
                       "AC#8: Flash message for whisper model must contain 'WhisperKit', got: '\(stateManager.notReadyMessage ?? "")'")
     }
 
+    // MARK: - Model Status
+
+    func testStatus_matchesDownloadedModels() {
+        let sut = ParakeetService()
+        let status = sut.status(for: "parakeet-v2")
+
+        XCTAssertEqual(status.model, "parakeet-v2")
+        XCTAssertEqual(status.state, sut.downloadedModels.contains("parakeet-v2") ? .downloaded : .notDownloaded)
+        XCTAssertFalse(status.path.isEmpty, "Status must report FluidAudio's cache folder")
+        XCTAssertNil(status.lastError)
+    }
+
+    func testModelStatus_roundTripsThroughJSON() throws {
+        let status = ModelStatus(model: "large-v3", state: .downloading, percent: 42,
+                                 bytesDownloaded: 1_300_000_000, totalBytes: 3_100_000_000,
+                                 path: "/tmp/openai_whisper-large-v3", source: "huggingface.co")
+
+        let decoded = try JSONDecoder().decode(ModelStatus.self, from: JSONEncoder().encode(status))
+
+        XCTAssertEqual(decoded, status)
+        XCTAssertEqual(decoded.fractionCompleted, 0.42, accuracy: 0.001)
+    }
+
     // MARK: - INTEGRATION (skipped in unit test run)
     // func testParakeetV3DownloadAndTranscribe() async throws { ... }
     // Requires: Apple Silicon + network + microphone. Run manually via the app.
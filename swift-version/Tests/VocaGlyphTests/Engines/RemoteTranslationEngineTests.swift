@@ -0,0 +1,70 @@
+import XCTest
+@testable import VocaGlyph
+
+final class RemoteTranslationEngineTests: XCTestCase {
+    var session: URLSession!
+
+    override func setUp() async throws {
+        let configuration = URLSessionConfiguration.ephemeral
+        configuration.protocolClasses = [MockURLProtocol.self]
+        session = URLSession(configuration: configuration)
+    }
+
+    override func tearDown() async throws {
+        MockURLProtocol.requestHandler = nil
+    }
+
+    func testSuccessfulTranslationReturnsText() async throws {
+        MockURLProtocol.requestHandler = { request in
+            XCTAssertEqual(request.url?.absoluteString, "https://translate.example.com/translate")
+            XCTAssertEqual(request.httpMethod, "POST")
+            let response = HTTPURLResponse(url: request.url!, statusCode: 200, httpVersion: nil, headerFields: nil)!
+            return (response, #"{"translatedText": "Hallo Welt"}"#.data(using: .utf8)!)
+        }
+
+        let engine = RemoteTranslationEngine(endpoint: "https://translate.example.com", session: session)
+        let result = try await engine.translate("Hello world", from: "en", to: "de")
+        XCTAssertEqual(result, "Hallo Welt")
+    }
+
+    func testAPIErrorResponseThrowsError() async {
+        MockURLProtocol.requestHandler = { request in
+            let response = HTTPURLResponse(url: request.url!, statusCode: 403, httpVersion: nil, headerFields: nil)!
+            return (response, #"{"error": "Invalid API key"}"#.data(using: .utf8)!)
+        }
+
+        let engine = RemoteTranslationEngine(endpoint: "https://translate.example.com/translate", session: session)
+        do {
+            _ = try await engine.translate("Hello", from: nil, to: "de")
+            XCTFail("Expected apiError")
+        } catch let error as RemoteTranslationError {
+            XCTAssertEqual(error, .apiError(statusCode: 403, message: "Invalid API key"))
+        } catch {
+            XCTFail("Unexpected error: \(error)")
+        }
+    }
+
+    func testInvalidEndpointThrowsError() async {
+        let engine = RemoteTranslationEngine(endpoint: "not a url", session: session)
+        do {
+            _ = try await engine.translate("Hello", from: nil, to: "de")
+            XCTFail("Expected invalidEndpoint")
+        } catch let error as RemoteTranslationError {
+            XCTAssertEqual(error, .invalidEndpoint)
+        } catch {
+            XCTFail("Unexpected error: \(error)")
+        }
+    }
+
+    // MARK: - LLM Translation
+
+    func testLLMTranslationSendsATranslationPrompt() async throws {
+        let llm = MockPostProcessingEngine()
+        llm.returnedText = "Hola"
+
+        let result = try await LLMTranslationEngine(engine: llm).translate("Hello", from: "en", to: "es")
+        XCTAssertEqual(result, "Hola")
+        XCTAssertEqual(llm.didCallRefineWithText, "Hello")
+        XCTAssertTrue(llm.didCallRefineWithPrompt?.contains("from English into Spanish") ?? false)
+    }
+}
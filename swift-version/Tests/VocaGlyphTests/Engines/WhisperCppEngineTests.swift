@@ -0,0 +1,72 @@
+import XCTest
+import AVFoundation
+@testable import VocaGlyph
+
+final class WhisperCppEngineTests: XCTestCase {
+
+    private var directory: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory
+            .appendingPathComponent("WhisperCppEngineTests-\(UUID().uuidString)", isDirectory: true)
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    private func silence() -> AVAudioPCMBuffer {
+        let format = AVAudioFormat(standardFormatWithSampleRate: 16_000, channels: 1)!
+        let buffer = AVAudioPCMBuffer(pcmFormat: format, frameCapacity: 1_600)!
+        buffer.frameLength = 1_600
+        return buffer
+    }
+
+    func testLocateFindsAnExecutableTool() throws {
+        XCTAssertNil(WhisperCppRuntime.locate(in: [directory.path]))
+
+        let tool = directory.appendingPathComponent("whisper-cli")
+        try Data().write(to: tool)
+        XCTAssertNil(WhisperCppRuntime.locate(in: [directory.path]), "not executable")
+
+        try FileManager.default.setAttributes([.posixPermissions: 0o755], ofItemAtPath: tool.path)
+        XCTAssertEqual(WhisperCppRuntime.locate(in: [directory.path])?.executable.path, tool.path)
+    }
+
+    func testMissingRuntimeSaysToInstallIt() async {
+        let engine = WhisperCppEngine(locateRuntime: { nil })
+        XCTAssertFalse(engine.isAvailable)
+        do {
+            _ = try await engine.transcribe(audioBuffer: silence())
+            XCTFail("expected an error")
+        } catch {
+            XCTAssertEqual(error as? WhisperCppError, .runtimeUnavailable)
+        }
+    }
+
+    func testMissingModelIsReported() async {
+        var settings = AppSettings.defaults
+        settings.whisperCppModelPath = directory.appendingPathComponent("ggml-base.bin").path
+        let engine = WhisperCppEngine(locateRuntime: { WhisperCppRuntime(executable: URL(fileURLWithPath: "/usr/bin/true")) },
+                                      settings: { settings })
+        do {
+            _ = try await engine.transcribe(audioBuffer: silence())
+            XCTFail("expected an error")
+        } catch {
+            XCTAssertEqual(error as? WhisperCppError, .modelMissing(settings.whisperCppModelPath))
+        }
+    }
+
+    func testOutputSegmentsAreJoined() {
+        XCTAssertEqual(WhisperCppEngine.transcript(fromOutput: " Hello there.\n General Kenobi.\n\n"),
+                       "Hello there. General Kenobi.")
+        XCTAssertEqual(WhisperCppEngine.transcript(fromOutput: "\n"), "")
+    }
+
+    func testArgumentsAskForPlainText() {
+        let arguments = WhisperCppEngine.arguments(model: "/m.bin", audio: "/a.wav", language: "auto")
+        XCTAssertEqual(arguments, ["--model", "/m.bin", "--file", "/a.wav", "--language", "auto",
+                                   "--no-timestamps", "--no-prints"])
+    }
+}
@@ -0,0 +1,49 @@
+import XCTest
+import SwiftData
+@testable import VocaGlyph
+
+@MainActor
+final class HistoryActionsTests: XCTestCase {
+    var container: ModelContainer!
+    var context: ModelContext!
+    var appDelegate: AppDelegate!
+
+    override func setUpWithError() throws {
+        let schema = Schema([TranscriptionItem.self])
+        let modelConfiguration = ModelConfiguration(schema: schema, isStoredInMemoryOnly: true)
+        container = try ModelContainer(for: schema, configurations: [modelConfiguration])
+        context = container.mainContext
+
+        appDelegate = AppDelegate()
+        appDelegate.sharedModelContainer = container
+        appDelegate.output = OutputService()
+    }
+
+    override func tearDownWithError() throws {
+        container = nil
+        context = nil
+        appDelegate = nil
+    }
+
+    func testRecentTranscriptionsAreNewestFirstAndLimited() throws {
+        let now = Date()
+        for minutes in 0..<7 {
+            context.insert(TranscriptionItem(text: "Dictation \(minutes)", timestamp: now.addingTimeInterval(Double(-minutes) * 60)))
+        }
+        try context.save()
+
+        let recent = appDelegate.historyAPI.recent(limit: AppDelegate.recentTranscriptionsMenuLimit)
+
+        XCTAssertEqual(recent.map(\.text), ["Dictation 0", "Dictation 1", "Dictation 2", "Dictation 3", "Dictation 4"])
+    }
+
+    func testUnknownHistoryEntryIsRejected() {
+        XCTAssertFalse(appDelegate.historyAPI.copy(UUID()))
+        XCTAssertFalse(appDelegate.historyAPI.repaste(UUID()))
+    }
+
+    func testMenuTitleUsesFirstLineAndTruncates() {
+        XCTAssertEqual(AppDelegate.menuTitle(for: "  Short note\nsecond line"), "Short note")
+        XCTAssertEqual(AppDelegate.menuTitle(for: String(repeating: "a", count: 50), maxLength: 10), "aaaaaaaaaa…")
+    }
+}
@@ -96,6 +96,20 @@ final class HistoryCleanupTests: XCTestCase {
         XCTAssertEqual(items.count, 0, "Expected 0 items when Privacy Mode is enabled, got \(items.count).")
     }
 
+    func testHistoryNotSavedWhenIncognitoModeEnabled() throws {
+        UserDefaults.standard.set(true, forKey: "incognitoModeEnabled")
+        defer { UserDefaults.standard.removeObject(forKey: "incognitoModeEnabled") }
+
+        appDelegate.appStateManagerDidTranscribe(text: "Off the record")
+
+        let expectation = XCTestExpectation(description: "Wait for async save task")
+        DispatchQueue.main.asyncAfter(deadline: .now() + 0.2) { expectation.fulfill() }
+        wait(for: [expectation], timeout: 1.0)
+
+        let items = try context.fetch(FetchDescriptor<TranscriptionItem>())
+        XCTAssertEqual(items.count, 0, "Expected 0 items in incognito mode, got \(items.count).")
+    }
+
     func testHistorySavedWhenPrivacyModeDisabled() throws {
         // Arrange: ensure Privacy Mode is off (default)
         UserDefaults.standard.removeObject(forKey: "privacyModeEnabled")
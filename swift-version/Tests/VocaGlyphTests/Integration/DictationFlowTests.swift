@@ -0,0 +1,207 @@
+import XCTest
+import AVFoundation
+@testable import VocaGlyph
+
+/// Whole dictations through `DictationHarness`: the paths between services that the
+/// per-service tests don't reach.
+@MainActor
+final class DictationFlowTests: XCTestCase {
+
+    private var savedSettings: AppSettings!
+
+    override func setUp() {
+        super.setUp()
+        savedSettings = SettingsStore.shared.settings
+        SettingsStore.shared.update {
+            $0.enablePostProcessing = false
+            $0.liveTranscriptionPreview = false
+        }
+    }
+
+    override func tearDown() {
+        let saved = savedSettings!
+        SettingsStore.shared.update { $0 = saved }
+        super.tearDown()
+    }
+
+    private func trimmed(_ outputs: [String]) -> [String] {
+        outputs.map { $0.trimmingCharacters(in: .whitespaces) }
+    }
+
+    // MARK: - Happy Path
+
+    func testHotkeyDictationIsTranscribedAndOutput() async {
+        let harness = DictationHarness()
+        harness.source.audio = SyntheticAudio.sine(seconds: 2)
+
+        await harness.dictate()
+
+        XCTAssertEqual(trimmed(harness.outputs), ["Hello world."])
+        XCTAssertEqual(harness.engine.transcribedFrames, [32_000])
+        XCTAssertEqual(harness.states, [.recording, .processing, .idle])
+        XCTAssertEqual(harness.source.starts, 1)
+        XCTAssertEqual(harness.source.stops, 1)
+    }
+
+    func testSilenceIsDroppedWithoutOutput() async {
+        let harness = DictationHarness(engine: ScriptedTranscriptionEngine([.success("  ")]))
+        harness.source.audio = SyntheticAudio.silence()
+
+        await harness.dictate()
+
+        XCTAssertTrue(harness.outputs.isEmpty)
+        XCTAssertEqual(harness.stateManager.currentState, .idle)
+    }
+
+    // MARK: - Queue
+
+    func testQuickTapStopsOnlyAfterCaptureStarted() async {
+        let harness = DictationHarness()
+        harness.source.startDelay = 0.2
+
+        await harness.dictate()
+
+        XCTAssertEqual(harness.source.starts, 1)
+        XCTAssertEqual(harness.source.stops, 1)
+        XCTAssertEqual(trimmed(harness.outputs), ["Hello world."])
+    }
+
+    func testPressWhileProcessingIsIgnoredAndTheNextDictationRuns() async {
+        let engine = ScriptedTranscriptionEngine([.success("First."), .success("Second.")])
+        engine.delay = 0.3
+        let harness = DictationHarness(engine: engine)
+
+        harness.pressHotkey()
+        harness.releaseHotkey()
+        harness.pressHotkey() // still processing the first
+        XCTAssertEqual(harness.stateManager.currentState, .processing)
+        await harness.waitUntilResting()
+
+        await harness.dictate()
+
+        XCTAssertEqual(trimmed(harness.outputs), ["First.", "Second."])
+        XCTAssertEqual(harness.engine.transcribedFrames.count, 2)
+        XCTAssertEqual(harness.source.starts, 2)
+    }
+
+    // MARK: - Fallback
+
+    func testCleanupFailureFallsBackToTheTranscription() async {
+        SettingsStore.shared.update { $0.enablePostProcessing = true }
+        let cleanup = MockPostProcessingEngine()
+        cleanup.shouldThrowError = true
+        let harness = DictationHarness()
+        harness.stateManager.postProcessingEngine = cleanup
+        harness.stateManager.localLLMIsWarmedUp = true
+
+        await harness.dictate()
+
+        XCTAssertEqual(cleanup.didCallRefineWithText, "Hello world.")
+        XCTAssertEqual(trimmed(harness.outputs), ["Hello world."])
+    }
+
+    func testMissingWhisperCppRuntimeFailsTheDictationWithAHint() async {
+        let harness = DictationHarness()
+        await harness.stateManager.engineRouter?.setEngine(WhisperCppEngine(locateRuntime: { nil }))
+
+        await harness.dictate()
+
+        XCTAssertTrue(harness.outputs.isEmpty)
+        XCTAssertTrue(harness.lastError?.contains("install the whisper.cpp runtime") ?? false,
+                      harness.lastError ?? "no error")
+    }
+
+    func testStuckTranscriptionIsAbandonedAndTheTrayReturnsToIdle() async {
+        SettingsStore.shared.update { $0.processingTimeoutSeconds = 1 }
+        let engine = ScriptedTranscriptionEngine([.success("Too late.")])
+        engine.delay = 10
+        let harness = DictationHarness(engine: engine)
+        var stuck: [DictationStuckEvent] = []
+        let observer = DictationStuckEvent.observe(queue: nil) { stuck.append($0) }
+        defer { NotificationCenter.default.removeObserver(observer) }
+
+        await harness.dictate()
+
+        XCTAssertEqual(harness.stateManager.currentState, .idle)
+        XCTAssertEqual(stuck.map(\.seconds), [1])
+        XCTAssertNil(harness.lastError)
+        XCTAssertTrue(harness.outputs.isEmpty)
+    }
+
+    // MARK: - Pending Output
+
+    func testTranscriptionIsPendingOnlyUntilOutputOrAbandoned() async throws {
+        let root = FileManager.default.temporaryDirectory
+            .appendingPathComponent("DictationFlowTests-\(UUID().uuidString)", isDirectory: true)
+        defer { try? FileManager.default.removeItem(at: root) }
+        let store = PendingOutputStore(dataRoot: root)
+        SettingsStore.shared.update { $0.processingTimeoutSeconds = 1 }
+
+        let harness = DictationHarness()
+        harness.stateManager.pendingOutputs = store
+        await harness.dictate()
+        XCTAssertEqual(trimmed(harness.outputs), ["Hello world."])
+        XCTAssertTrue(store.pending.isEmpty)
+
+        // Stuck after transcribing, in cleanup: the text is kept for the next launch.
+        SettingsStore.shared.update { $0.enablePostProcessing = true }
+        let cleanup = MockPostProcessingEngine()
+        cleanup.shouldTimeout = true
+        harness.stateManager.postProcessingEngine = cleanup
+        harness.stateManager.localLLMIsWarmedUp = true
+        await harness.dictate()
+        XCTAssertEqual(store.pending.map(\.text), ["Hello world."])
+    }
+
+    // MARK: - Errors
+
+    func testEngineFailureShowsAnErrorAndOutputsNothing() async {
+        let harness = DictationHarness(engine: ScriptedTranscriptionEngine([.failure(ScriptedTranscriptionEngine.Failure())]))
+
+        await harness.dictate()
+
+        XCTAssertTrue(harness.outputs.isEmpty)
+        XCTAssertEqual(harness.lastError, "Transcription failed: scripted failure")
+    }
+
+    func testMicrophoneFailureShowsAnErrorAndSkipsTranscription() async {
+        let harness = DictationHarness()
+        harness.source.startError = AudioRecorderError.microphoneAccessNotDetermined
+
+        harness.pressHotkey()
+        await harness.waitUntilResting()
+        harness.releaseHotkey()
+
+        XCTAssertNotNil(harness.lastError)
+        XCTAssertTrue(harness.engine.transcribedFrames.isEmpty)
+        XCTAssertTrue(harness.outputs.isEmpty)
+    }
+
+    func testAnErrorClearsOnTheNextDictation() async {
+        let harness = DictationHarness(engine: ScriptedTranscriptionEngine([
+            .failure(ScriptedTranscriptionEngine.Failure()), .success("Recovered."),
+        ]))
+
+        await harness.dictate()
+        await harness.dictate()
+
+        XCTAssertEqual(trimmed(harness.outputs), ["Recovered."])
+        XCTAssertEqual(harness.stateManager.currentState, .idle)
+    }
+
+    // MARK: - Transcription Service
+
+    func testTranscriptionServiceDecodesAWAVFixtureForTheEngine() async throws {
+        let engine = ScriptedTranscriptionEngine([.success("From another app.")])
+        let wav = try SyntheticAudio.wav(SyntheticAudio.sine(seconds: 1))
+        let request = #"{"jsonrpc": "2.0", "id": 1, "method": "transcribe", "params": {"token": "t", "audio": ""#
+            + wav.base64EncodedString() + #""}}"#
+
+        let response = await TranscriptionServer.respond(to: Data(request.utf8), token: "t") {
+            try await engine.transcribe(audioBuffer: $0)
+        }
+
+        XCTAssertEqual(response.result?.text, "From another app.")
+        XCTAssertEqual(engine.transcribedFrames, [16_000])
+    }
+}
@@ -0,0 +1,200 @@
+import XCTest
+import AVFoundation
+@testable import VocaGlyph
+
+// MARK: - SyntheticAudio
+
+/// PCM fixtures made on the fly, in the 16 kHz mono float format the recorder produces.
+enum SyntheticAudio {
+
+    static let format = AVAudioFormat(standardFormatWithSampleRate: 16_000, channels: 1)!
+
+    static func sine(frequency: Double = 440, seconds: Double = 1, amplitude: Float = 0.5) -> AVAudioPCMBuffer {
+        let buffer = silence(seconds: seconds)
+        let channel = buffer.floatChannelData![0]
+        for frame in 0..<Int(buffer.frameLength) {
+            channel[frame] = amplitude * Float(sin(2 * .pi * frequency * Double(frame) / format.sampleRate))
+        }
+        return buffer
+    }
+
+    static func silence(seconds: Double = 1) -> AVAudioPCMBuffer {
+        let frames = AVAudioFrameCount(seconds * format.sampleRate)
+        let buffer = AVAudioPCMBuffer(pcmFormat: format, frameCapacity: frames)!
+        buffer.frameLength = frames
+        return buffer
+    }
+
+    /// `buffer` as the bytes of a 16-bit WAV file.
+    static func wav(_ buffer: AVAudioPCMBuffer) throws -> Data {
+        let url = FileManager.default.temporaryDirectory.appendingPathComponent("SyntheticAudio-\(UUID().uuidString).wav")
+        defer { try? FileManager.default.removeItem(at: url) }
+        let settings: [String: Any] = [
+            AVFormatIDKey: kAudioFormatLinearPCM,
+            AVSampleRateKey: buffer.format.sampleRate,
+            AVNumberOfChannelsKey: 1,
+            AVLinearPCMBitDepthKey: 16,
+            AVLinearPCMIsFloatKey: false,
+        ]
+        do {
+            let file = try AVAudioFile(forWriting: url, settings: settings,
+                                       commonFormat: buffer.format.commonFormat, interleaved: false)
+            try file.write(from: buffer)
+        }
+        return try Data(contentsOf: url)
+    }
+}
+
+// MARK: - Mock Backends
+
+/// The microphone, replaced by a fixture.
+final class FixtureAudioSource: AudioSource {
+    var audio: AVAudioPCMBuffer? = SyntheticAudio.sine()
+    var startError: Error?
+    /// Seconds `startCapture()` takes, like a cold `AVAudioEngine`.
+    var startDelay: TimeInterval = 0
+    private(set) var starts = 0
+    private(set) var stops = 0
+
+    func startCapture() throws {
+        if startDelay > 0 { Thread.sleep(forTimeInterval: startDelay) }
+        starts += 1
+        if let startError { throw startError }
+    }
+
+    func stopCapture() -> AVAudioPCMBuffer? {
+        stops += 1
+        return audio
+    }
+}
+
+/// A transcriber that answers from a script, one entry per call, then repeats the last.
+final class ScriptedTranscriptionEngine: TranscriptionEngine, @unchecked Sendable {
+    struct Failure: LocalizedError {
+        var errorDescription: String? { "scripted failure" }
+    }
+
+    private let lock = NSLock()
+    private var script: [Result<String, Error>]
+    private var frames: [AVAudioFrameCount] = []
+    /// Seconds each transcription takes.
+    var delay: TimeInterval = 0
+
+    init(_ script: [Result<String, Error>] = [.success("Hello world.")]) {
+        self.script = script
+    }
+
+    /// Frame counts of the buffers transcribed so far.
+    var transcribedFrames: [AVAudioFrameCount] {
+        lock.lock()
+        defer { lock.unlock() }
+        return frames
+    }
+
+    func transcribe(audioBuffer: AVAudioPCMBuffer) async throws -> String {
+        if delay > 0 { try await Task.sleep(nanoseconds: UInt64(delay * 1_000_000_000)) }
+        lock.lock()
+        frames.append(audioBuffer.frameLength)
+        let next = script.count > 1 ? script.removeFirst() : script[0]
+        lock.unlock()
+        return try next.get()
+    }
+}
+
+// MARK: - DictationHarness
+
+/// The app's dictation flow with mock backends: hotkey → `AppStateManager` →
+/// `DictationPipeline` capture → `EngineRouter` → text processing → sinks.
+///
+/// `appStateDidChange` does what `AppDelegate`'s does for recording and processing,
+/// without the tray, overlay or `NSApp`. Use from the main thread, as the app does.
+final class DictationHarness: AppStateManagerDelegate {
+
+    let stateManager = AppStateManager()
+    let source = FixtureAudioSource()
+    let engine: ScriptedTranscriptionEngine
+    let pipeline: DictationPipeline
+    /// What reached the "output" sink, i.e. would have been pasted.
+    private(set) var outputs: [String] = []
+    private(set) var states: [AppState] = []
+
+    init(engine: ScriptedTranscriptionEngine = ScriptedTranscriptionEngine()) {
+        self.engine = engine
+        pipeline = DictationPipeline(source: source)
+        pipeline.addSink(ClosureSink(name: "output") { [weak self] text in
+            self?.outputs.append(text)
+            return true
+        })
+        stateManager.engineRouter = EngineRouter(engine: engine)
+        stateManager.delegate = self
+    }
+
+    // MARK: Driving
+
+    func pressHotkey() { stateManager.startRecording() }
+    func releaseHotkey() { stateManager.stopRecording() }
+
+    /// Presses and releases the hotkey, then waits for the dictation to finish.
+    func dictate(file: StaticString = #filePath, line: UInt = #line) async {
+        pressHotkey()
+        releaseHotkey()
+        await waitUntilResting(file: file, line: line)
+    }
+
+    /// Waits until nothing is recording or processing, failing the test after `timeout`.
+    func waitUntilResting(timeout: TimeInterval = 5, file: StaticString = #filePath, line: UInt = #line) async {
+        let deadline = Date().addingTimeInterval(timeout)
+        // One turn first, so work queued on the main queue has started.
+        try? await Task.sleep(nanoseconds: 10_000_000)
+        while !stateManager.currentState.isResting {
+            guard Date() < deadline else {
+                XCTFail("Still \(stateManager.currentState) after \(timeout)s", file: file, line: line)
+                return
+            }
+            try? await Task.sleep(nanoseconds: 10_000_000)
+        }
+    }
+
+    var lastError: String? {
+        for state in states.reversed() {
+            if case .error(let message) = state { return message }
+        }
+        return nil
+    }
+
+    // MARK: AppStateManagerDelegate
+
+    func appStateDidChange(newState: AppState) {
+        states.append(newState)
+        switch newState {
+        case .recording:
+            pipeline.startCapture { [weak self] error in
+                self?.stateManager.setError("Couldn't start recording: \(error.localizedDescription)")
+            }
+        case .processing:
+            pipeline.stopCapture { [weak self] buffer in
+                guard let self else { return }
+                if let buffer {
+                    self.stateManager.processAudio(buffer: buffer)
+                } else {
+                    self.stateManager.setIdle()
+                }
+            }
+        default:
+            break
+        }
+    }
+
+    func appStateManagerDidTranscribe(text: String) {
+        pipeline.deliver(text)
+    }
+
+    func appStateManagerDidRecognize(command: VoiceCommand) {}
+
+    func appStateManagerDidSummarize(verbatim: String, summary: String) {
+        pipeline.deliver(summary)
+    }
+
+    func appStateManagerRecordingDidTick(elapsed: Int, remaining: Int?) {}
+    func appStateManagerRecordingWillReachLimit(remaining: Int) {}
+}
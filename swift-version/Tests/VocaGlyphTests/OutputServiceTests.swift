@@ -85,5 +85,52 @@ final class OutputServiceTests: XCTestCase {
         let result = service.applyBasicPunctuation(parakeetOutput)
         XCTAssertEqual(result, "Hello how are you doing today.")
     }
+
+    // MARK: - Clipboard history markers
+
+    func testHiddenPasteboardItemCarriesTransientMarkers() {
+        let item = OutputService.pasteboardItem(text: "hello ", hiddenFromHistory: true)
+        XCTAssertEqual(item.string(forType: .string), "hello ")
+        for type in OutputService.transientPasteboardTypes {
+            XCTAssertTrue(item.types.contains(type), "Missing \(type.rawValue)")
+        }
+    }
+
+    func testVisiblePasteboardItemHasOnlyText() {
+        let item = OutputService.pasteboardItem(text: "hello", hiddenFromHistory: false)
+        XCTAssertEqual(item.types, [.string])
+    }
+
+    func testDeliberateCopyIsNotMarked() {
+        let pasteboard = NSPasteboard.withUniqueName()
+        defer { pasteboard.releaseGlobally() }
+
+        OutputService().copyToPasteboard(text: "from history", pasteboard: pasteboard)
+
+        XCTAssertEqual(pasteboard.string(forType: .string), "from history")
+        XCTAssertEqual(pasteboard.types ?? [], [.string])
+    }
+
+    // MARK: - Re-typing target
+
+    func testRetypeNeedsTheSameApp() {
+        let notes = InsertionTarget(bundleIdentifier: "com.apple.Notes", element: nil)
+        let mail = InsertionTarget(bundleIdentifier: "com.apple.mail", element: nil)
+
+        XCTAssertTrue(OutputService.isSameTarget(notes, notes))
+        XCTAssertFalse(OutputService.isSameTarget(notes, mail))
+        XCTAssertFalse(OutputService.isSameTarget(nil, notes), "Nothing pasted")
+        XCTAssertFalse(OutputService.isSameTarget(notes, nil), "Focus unknown")
+    }
+
+    func testRetypeRefusesAnUnidentifiedApp() {
+        let unknown = InsertionTarget(bundleIdentifier: nil, element: nil)
+        XCTAssertFalse(OutputService.isSameTarget(unknown, unknown))
+    }
+
+    func testRetypeWithoutAPasteDoesNothing() {
+        let service = OutputService(focusedTarget: { InsertionTarget(bundleIdentifier: "com.apple.Notes", element: nil) })
+        XCTAssertFalse(service.retypeLastInsertion { $0.uppercased() })
+    }
 }
 
@@ -78,4 +78,20 @@ final class PermissionsServiceTests: XCTestCase {
         XCTAssertTrue(service.isMicrophoneAuthorized)
         XCTAssertFalse(service.areAllCorePermissionsGranted)
     }
+
+    func testBlockedMicrophoneIsNeverPromptedAgain() async {
+        mockProvider.microphoneStatus = .denied
+        XCTAssertTrue(service.isMicrophoneBlocked)
+        let granted = await service.ensureMicrophoneAccess()
+        XCTAssertFalse(granted)
+        XCTAssertFalse(mockProvider.didRequestMicrophoneAccess)
+    }
+
+    func testUndecidedMicrophoneIsPrompted() async {
+        mockProvider.microphoneStatus = .notDetermined
+        XCTAssertFalse(service.isMicrophoneBlocked)
+        let granted = await service.ensureMicrophoneAccess()
+        XCTAssertTrue(granted)
+        XCTAssertTrue(mockProvider.didRequestMicrophoneAccess)
+    }
 }
@@ -0,0 +1,89 @@
+import XCTest
+@testable import VocaGlyph
+
+private final class MockQuickPanelTarget: QuickPanelTarget {
+    var model = "apple-native"
+    var language = "English"
+    var paused = false
+    var lastText: String? = "hello world"
+    var copied: [String] = []
+
+    func handleTrigger(_ command: ExternalTriggerCommand) {}
+    func pasteLastTranscription() -> Bool { false }
+    func openSettings() {}
+
+    func controlStatus() -> ControlStatus {
+        ControlStatus(state: "idle", model: model, language: language, paused: paused)
+    }
+
+    func controlMetrics() -> MetricsSnapshot { MetricsService().snapshot() }
+    func replay(fileAt path: String) -> String? { nil }
+    func dictateToClipboard() {}
+    func togglePause() -> String? { nil }
+    func switchProfile(to profile: String) -> String? { nil }
+    func transcribeFile(atPath path: String) async throws -> String { "" }
+    func handleKey(pressed: Bool) {}
+
+    func switchModel(to model: String) -> String? {
+        guard model != "nope" else { return "Unknown model 'nope'." }
+        self.model = model
+        return nil
+    }
+
+    func switchLanguage(to language: String) -> String? {
+        self.language = language
+        return nil
+    }
+
+    func quickPanelModels() -> [(id: String, title: String)] {
+        [("apple-native", "Apple Native"), ("small", "Whisper Small")]
+    }
+
+    func lastTranscriptionText() -> String? { lastText }
+    func canChangeDictationPause() -> Bool { true }
+    func setDictationPaused(_ paused: Bool) { self.paused = paused }
+    func copyToClipboard(_ text: String) { copied.append(text) }
+}
+
+@MainActor
+final class QuickPanelModelTests: XCTestCase {
+
+    private var target: MockQuickPanelTarget!
+    private var model: QuickPanelModel!
+
+    override func setUp() {
+        super.setUp()
+        target = MockQuickPanelTarget()
+        model = QuickPanelModel(target: target)
+        model.refresh()
+    }
+
+    func testRefreshReadsTheCurrentState() {
+        XCTAssertEqual(model.models.map(\.id), ["apple-native", "small"])
+        XCTAssertEqual(model.selectedModel, "apple-native")
+        XCTAssertEqual(model.selectedLanguage, "English")
+        XCTAssertEqual(model.lastResult, "hello world")
+    }
+
+    func testChangesGoThroughTheTargetAndRefresh() {
+        model.selectModel("small")
+        model.selectLanguage("German")
+        model.setPaused(true)
+
+        XCTAssertEqual(model.selectedModel, "small")
+        XCTAssertEqual(model.selectedLanguage, "German")
+        XCTAssertTrue(model.isPaused)
+        XCTAssertNil(model.message)
+    }
+
+    func testRejectedModelKeepsTheSelectionAndShowsWhy() {
+        model.selectModel("nope")
+        XCTAssertEqual(model.selectedModel, "apple-native")
+        XCTAssertEqual(model.message, "Unknown model 'nope'.")
+    }
+
+    func testCopyUsesTheLastResult() {
+        model.copyLastResult()
+        XCTAssertEqual(target.copied, ["hello world"])
+    }
+}
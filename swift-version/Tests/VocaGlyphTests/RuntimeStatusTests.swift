@@ -0,0 +1,64 @@
+import XCTest
+@testable import VocaGlyph
+
+final class RuntimeStatusTests: XCTestCase {
+
+    private func derive(_ state: AppState = .idle,
+                        paused: Bool = false,
+                        model: ModelStatus.State? = nil,
+                        percent: Int = 0,
+                        clipboardOnly: Bool = false,
+                        micBlocked: Bool = false,
+                        slowFactor: Double? = nil) -> RuntimeStatus {
+        let status = model.map { ModelStatus(model: "small", state: $0, percent: percent) }
+        return RuntimeStatus.derive(state: state, dictationPaused: paused, modelTitle: "Whisper Small",
+                                    modelStatus: status, lastOutputWasClipboardOnly: clipboardOnly,
+                                    microphoneBlocked: micBlocked,
+                                    slowTranscriptionFactor: slowFactor)
+    }
+
+    func testIdleWithABuiltInModelIsReady() {
+        XCTAssertEqual(derive(), .ready)
+        XCTAssertEqual(RuntimeStatus.ready.message, "Ready to dictate")
+    }
+
+    func testMissingOrDamagedModelIsReported() {
+        XCTAssertEqual(derive(model: .notDownloaded), .modelMissing(model: "Whisper Small"))
+        XCTAssertEqual(derive(model: .corrupt), .modelMissing(model: "Whisper Small"))
+        XCTAssertEqual(derive(model: .downloaded), .ready)
+    }
+
+    func testDownloadProgressIsReported() {
+        let status = derive(.initializing, model: .downloading, percent: 42)
+        XCTAssertEqual(status, .downloading(model: "Whisper Small", percent: 42))
+        XCTAssertEqual(status.progress, 42)
+        XCTAssertEqual(status.message, "Downloading Whisper Small — 42%")
+        XCTAssertEqual(derive(.initializing, model: .downloaded), .loading(model: "Whisper Small"))
+    }
+
+    func testActiveWorkWinsOverModelAndPause() {
+        XCTAssertEqual(derive(.recording, paused: true, model: .downloading), .recording)
+        XCTAssertEqual(derive(.processing, clipboardOnly: true), .processing)
+        XCTAssertEqual(derive(.error("Mic unavailable")), .error("Mic unavailable"))
+    }
+
+    func testBlockedMicrophoneIsReportedBeforeTheModel() {
+        XCTAssertEqual(derive(model: .notDownloaded, micBlocked: true), .microphoneBlocked)
+        XCTAssertEqual(derive(.recording, micBlocked: true), .recording)
+        XCTAssertEqual(RuntimeStatus.microphoneBlocked.name, "microphone-blocked")
+    }
+
+    func testPausedThenPasteFallback() {
+        XCTAssertEqual(derive(paused: true, clipboardOnly: true), .paused)
+        XCTAssertEqual(derive(.paused), .paused)
+        XCTAssertEqual(derive(clipboardOnly: true), .pasteFallback)
+        XCTAssertEqual(RuntimeStatus.pasteFallback.name, "paste-fallback")
+    }
+
+    func testSlowTranscriptionIsTheLastWarning() {
+        XCTAssertEqual(derive(slowFactor: 1.4), .slowTranscription(factor: 1.4))
+        XCTAssertEqual(derive(clipboardOnly: true, slowFactor: 1.4), .pasteFallback)
+        XCTAssertEqual(derive(.recording, slowFactor: 1.4), .recording)
+        XCTAssertTrue(RuntimeStatus.slowTranscription(factor: 1.4).message.contains("1.4×"))
+    }
+}
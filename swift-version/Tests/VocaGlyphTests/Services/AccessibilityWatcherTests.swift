@@ -0,0 +1,43 @@
+import XCTest
+@testable import VocaGlyph
+
+final class AccessibilityWatcherTests: XCTestCase {
+
+    func testDoesNotWatchWhenAlreadyTrusted() {
+        let watcher = AccessibilityWatcher(isTrusted: { true })
+        watcher.start()
+        XCTAssertFalse(watcher.isWatching)
+    }
+
+    func testPostsEventAndStopsWhenGranted() {
+        var trusted = false
+        let center = NotificationCenter()
+        let watcher = AccessibilityWatcher(isTrusted: { trusted }, interval: 0.01, center: center)
+        var events = 0
+        let observer = AccessibilityGrantedEvent.observe(center: center, queue: nil) { _ in events += 1 }
+        defer { center.removeObserver(observer) }
+        let granted = expectation(description: "granted")
+        watcher.onGranted = { granted.fulfill() }
+
+        watcher.start()
+        XCTAssertTrue(watcher.isWatching)
+        trusted = true
+
+        wait(for: [granted], timeout: 1)
+        XCTAssertEqual(events, 1)
+        XCTAssertFalse(watcher.isWatching)
+    }
+
+    func testPollDoesNothingAfterStop() {
+        var trusted = false
+        let watcher = AccessibilityWatcher(isTrusted: { trusted })
+        var called = false
+        watcher.onGranted = { called = true }
+
+        watcher.start()
+        watcher.stop()
+        trusted = true
+        watcher.poll()
+        XCTAssertFalse(called)
+    }
+}
@@ -0,0 +1,98 @@
+import XCTest
+@testable import VocaGlyph
+
+final class CaptionServerTests: XCTestCase {
+
+    private let port: UInt16 = 7979
+
+    private func request(_ headers: [String]) -> String {
+        (["GET / HTTP/1.1", "Host: 127.0.0.1:7979"] + headers).joined(separator: "\r\n") + "\r\n\r\n"
+    }
+
+    // MARK: - Handshake
+
+    func testAcceptKeyMatchesTheRFCExample() {
+        XCTAssertEqual(CaptionServer.acceptKey(for: "dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+    }
+
+    func testPlainRequestGetsThePage() {
+        XCTAssertEqual(CaptionServer.reply(to: request([]), port: port), .page)
+        XCTAssertEqual(CaptionServer.reply(to: "GET /?hold=3 HTTP/1.1\r\n\r\n", port: port), .page)
+        XCTAssertEqual(CaptionServer.reply(to: "GET /favicon.ico HTTP/1.1\r\n\r\n", port: port), .notFound)
+        XCTAssertEqual(CaptionServer.reply(to: "POST / HTTP/1.1\r\n\r\n", port: port), .badRequest)
+    }
+
+    func testUpgradeFromThePageOrWithoutOriginIsAccepted() {
+        let upgrade = ["Upgrade: websocket", "Connection: Upgrade", "Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ=="]
+        let accepted = CaptionServer.Reply.upgrade(accept: "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+
+        XCTAssertEqual(CaptionServer.reply(to: request(upgrade), port: port), accepted)
+        XCTAssertEqual(CaptionServer.reply(to: request(upgrade + ["Origin: http://localhost:7979"]), port: port), accepted)
+        XCTAssertEqual(CaptionServer.reply(to: request(upgrade + ["origin: http://127.0.0.1:7979"]), port: port), accepted)
+    }
+
+    func testUpgradeFromAnotherOriginIsRefused() {
+        let upgrade = ["Upgrade: websocket", "Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ=="]
+        XCTAssertEqual(CaptionServer.reply(to: request(upgrade + ["Origin: https://example.com"]), port: port), .forbidden)
+        XCTAssertEqual(CaptionServer.reply(to: request(upgrade + ["Origin: http://localhost:8080"]), port: port), .forbidden)
+    }
+
+    func testUpgradeWithoutKeyIsABadRequest() {
+        XCTAssertEqual(CaptionServer.reply(to: request(["Upgrade: websocket"]), port: port), .badRequest)
+    }
+
+    // MARK: - Frames
+
+    func testTextFrameLengthEncodings() {
+        let short = CaptionServer.textFrame(Data(repeating: 0x61, count: 5))
+        XCTAssertEqual(Array(short.prefix(2)), [0x81, 5])
+        XCTAssertEqual(short.count, 7)
+
+        let medium = CaptionServer.textFrame(Data(repeating: 0x61, count: 300))
+        XCTAssertEqual(Array(medium.prefix(4)), [0x81, 126, 0x01, 0x2C])
+        XCTAssertEqual(medium.count, 304)
+
+        let long = CaptionServer.textFrame(Data(repeating: 0x61, count: 70_000))
+        XCTAssertEqual(Array(long.prefix(10)), [0x81, 127, 0, 0, 0, 0, 0, 0x01, 0x11, 0x70])
+        XCTAssertEqual(long.count, 70_010)
+    }
+
+    // MARK: - Sequence
+
+    func testPartialsAndFinalShareAnId() {
+        var sequence = CaptionSequence()
+        let date = Date(timeIntervalSince1970: 100)
+
+        XCTAssertEqual(sequence.partial(" so today ", at: date),
+                       Caption(type: .partial, id: 1, text: "so today", timestamp: 100))
+        XCTAssertNil(sequence.partial("so today", at: date), "Repeats are skipped")
+        XCTAssertEqual(sequence.partial("so today we", at: date)?.id, 1)
+        XCTAssertEqual(sequence.final("So today we build.", at: date),
+                       Caption(type: .final, id: 1, text: "So today we build.", timestamp: 100))
+        XCTAssertNil(sequence.end(at: date), "A final needs no clear")
+        XCTAssertEqual(sequence.partial("next", at: date)?.id, 2)
+    }
+
+    func testFinalWithoutPartialsStartsADictation() {
+        var sequence = CaptionSequence()
+        XCTAssertEqual(sequence.final("Hello.")?.id, 1)
+        XCTAssertEqual(sequence.final("Again.")?.id, 2)
+    }
+
+    func testDictationWithoutTextIsCleared() {
+        var sequence = CaptionSequence()
+        _ = sequence.partial("um")
+        XCTAssertEqual(sequence.end()?.type, .clear)
+        XCTAssertNil(sequence.end())
+
+        _ = sequence.partial("uh")
+        XCTAssertEqual(sequence.final("  ")?.type, .clear)
+    }
+
+    func testCaptionJSON() throws {
+        let encoder = JSONEncoder()
+        encoder.outputFormatting = .sortedKeys
+        let json = try encoder.encode(Caption(type: .final, id: 3, text: "Hi.", timestamp: 1.5))
+        XCTAssertEqual(String(decoding: json, as: UTF8.self), #"{"id":3,"text":"Hi.","timestamp":1.5,"type":"final"}"#)
+    }
+}
@@ -0,0 +1,151 @@
+import XCTest
+@testable import VocaGlyph
+
+// MARK: - Mock
+
+private final class MockFocusedTextProvider: FocusedTextProvider {
+    var bundleId: String? = "com.apple.TextEdit"
+    var text: String? = "Dear Ms. Okonkwo, thanks for the update on"
+    var selection: String? = "thanks for the update"
+    private(set) var readCount = 0
+    private(set) var lastMaxLength: Int?
+
+    func frontmostBundleIdentifier() -> String? { bundleId }
+
+    func textBeforeInsertionPoint(maxLength: Int) -> String? {
+        readCount += 1
+        lastMaxLength = maxLength
+        return text
+    }
+
+    func selectedText() -> String? { selection }
+}
+
+// MARK: - ContextCaptureServiceTests
+
+final class ContextCaptureServiceTests: XCTestCase {
+
+    private let suiteName = "ContextCaptureServiceTests"
+    private var defaults: UserDefaults!
+
+    override func setUp() {
+        super.setUp()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+    }
+
+    override func tearDown() {
+        defaults.removePersistentDomain(forName: suiteName)
+        defaults = nil
+        super.tearDown()
+    }
+
+    /// Context capture opted in, with post-processing on to use it.
+    private func makeSUT() -> (ContextCaptureService, SettingsStore, MockFocusedTextProvider) {
+        let store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+        store.update {
+            $0.contextCaptureEnabled = true
+            $0.enablePostProcessing = true
+        }
+        let provider = MockFocusedTextProvider()
+        return (ContextCaptureService(store: store, provider: provider), store, provider)
+    }
+
+    func test_capture_offByDefault() {
+        let store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+        let provider = MockFocusedTextProvider()
+        let sut = ContextCaptureService(store: store, provider: provider)
+        XCTAssertFalse(store.settings.contextCaptureEnabled)
+        XCTAssertNil(sut.capture())
+        XCTAssertEqual(provider.readCount, 0)
+    }
+
+    func test_capture_postProcessingOff_doesNotReadText() {
+        let (sut, store, provider) = makeSUT()
+        store.update { $0.enablePostProcessing = false }
+        XCTAssertNil(sut.capture())
+        XCTAssertEqual(provider.readCount, 0)
+    }
+
+    func test_capture_optedIn_readsDefaultCharacterLimit() {
+        let (sut, _, provider) = makeSUT()
+        XCTAssertEqual(sut.capture(), "Dear Ms. Okonkwo, thanks for the update on")
+        XCTAssertEqual(provider.lastMaxLength, ContextCaptureService.defaultCharacterLimit)
+    }
+
+    func test_capture_disabled_doesNotReadText() {
+        let (sut, store, provider) = makeSUT()
+        store.update { $0.contextCaptureEnabled = false }
+        XCTAssertNil(sut.capture())
+        XCTAssertEqual(provider.readCount, 0)
+    }
+
+    func test_capture_incognito_doesNotReadText() {
+        let (sut, store, provider) = makeSUT()
+        store.update { $0.incognitoModeEnabled = true }
+        XCTAssertNil(sut.capture())
+        XCTAssertEqual(provider.readCount, 0)
+    }
+
+    func test_capture_excludedApp_doesNotReadText() {
+        let (sut, store, provider) = makeSUT()
+        store.update { $0.contextCaptureExcludedApps = ["com.apple.TextEdit"] }
+        XCTAssertNil(sut.capture())
+        XCTAssertEqual(provider.readCount, 0)
+    }
+
+    func test_capture_customLimit_keepsTrailingCharacters() {
+        let (sut, store, provider) = makeSUT()
+        store.update { $0.contextCaptureCharacterLimit = 10 }
+        XCTAssertEqual(sut.capture(), "update on")
+        XCTAssertEqual(provider.lastMaxLength, 10)
+    }
+
+    func test_capture_whitespaceOnly_returnsNil() {
+        let (sut, _, provider) = makeSUT()
+        provider.text = "   \n"
+        XCTAssertNil(sut.capture())
+    }
+
+    func test_captureSelection_offByDefault() {
+        let (sut, _, _) = makeSUT()
+        XCTAssertNil(sut.captureSelection())
+    }
+
+    func test_captureSelection_enabled_returnsSelection() {
+        let (sut, store, _) = makeSUT()
+        store.update { $0.selectionRewriteEnabled = true }
+        XCTAssertEqual(sut.captureSelection(), "thanks for the update")
+    }
+
+    func test_captureSelection_respectsIncognitoExclusionsAndLength() {
+        let (sut, store, provider) = makeSUT()
+        store.update {
+            $0.selectionRewriteEnabled = true
+            $0.incognitoModeEnabled = true
+        }
+        XCTAssertNil(sut.captureSelection())
+
+        store.update {
+            $0.incognitoModeEnabled = false
+            $0.contextCaptureExcludedApps = ["com.apple.TextEdit"]
+        }
+        XCTAssertNil(sut.captureSelection())
+
+        store.update { $0.contextCaptureExcludedApps = [] }
+        provider.selection = String(repeating: "a", count: SelectionRewriter.maxSelectionLength + 1)
+        XCTAssertNil(sut.captureSelection())
+        provider.selection = "  \n"
+        XCTAssertNil(sut.captureSelection())
+    }
+
+    func test_appendingContext_emptyPrompt_staysEmpty() {
+        XCTAssertEqual(TemplatePromptRenderer.appendingContext("Hello", to: ""), "")
+    }
+
+    func test_appendingContext_includesContextBlock() {
+        let prompt = TemplatePromptRenderer.appendingContext("Dear Ms. Okonkwo", to: "Fix grammar.")
+        XCTAssertTrue(prompt.hasPrefix("Fix grammar."))
+        XCTAssertTrue(prompt.contains("<context>\nDear Ms. Okonkwo\n</context>"))
+    }
+}
@@ -0,0 +1,290 @@
+import XCTest
+@testable import VocaGlyph
+
+private final class MockControlTarget: ControlAPITarget {
+    var triggers: [ExternalTriggerCommand] = []
+    var model = "apple-native"
+    var hasSomethingToPaste = true
+    var replayed: [String] = []
+    var clipboardDictations = 0
+    var paused = false
+    var canPause = true
+    var profile = "General Cleanup"
+    var transcripts: [String: String] = [:]
+    var keys: [Bool] = []
+
+    func handleTrigger(_ command: ExternalTriggerCommand) { triggers.append(command) }
+    func pasteLastTranscription() -> Bool { hasSomethingToPaste }
+    func openSettings() {}
+
+    func controlStatus() -> ControlStatus {
+        ControlStatus(state: "idle", model: model, language: "English", paused: false)
+    }
+
+    func controlMetrics() -> MetricsSnapshot { MetricsService().snapshot() }
+
+    func replay(fileAt path: String) -> String? {
+        guard FileManager.default.fileExists(atPath: path) else { return "No such file: \(path)" }
+        replayed.append(path)
+        return nil
+    }
+
+    func switchModel(to model: String) -> String? {
+        guard model != "nope" else { return "Unknown model 'nope'." }
+        self.model = model
+        return nil
+    }
+
+    func switchLanguage(to language: String) -> String? { nil }
+
+    func dictateToClipboard() { clipboardDictations += 1 }
+
+    func togglePause() -> String? {
+        guard canPause else { return "Dictation can only be paused between recordings." }
+        paused.toggle()
+        return nil
+    }
+
+    func switchProfile(to profile: String) -> String? {
+        guard ["General Cleanup", "Email"].contains(profile) else { return "Unknown profile '\(profile)'." }
+        self.profile = profile
+        return nil
+    }
+
+    func handleKey(pressed: Bool) { keys.append(pressed) }
+
+    func transcribeFile(atPath path: String) async throws -> String {
+        guard let text = transcripts[path] else { throw TranscriptionServerError.invalidAudio("cannot read \(path)") }
+        return text
+    }
+}
+
+@MainActor
+final class ControlSocketServiceTests: XCTestCase {
+
+    private let token = "secret"
+    private var target: MockControlTarget!
+
+    override func setUp() {
+        super.setUp()
+        target = MockControlTarget()
+    }
+
+    private func respond(_ json: String) -> ControlResponse {
+        ControlSocketService.respond(to: Data(json.utf8), token: token, target: target)
+    }
+
+    func testRequestsWithoutTheTokenAreRejected() {
+        XCTAssertEqual(respond(#"{"command": "toggle"}"#), .failure("Unauthorized."))
+        XCTAssertEqual(respond(#"{"token": "guess", "command": "toggle"}"#), .failure("Unauthorized."))
+        XCTAssertTrue(target.triggers.isEmpty)
+    }
+
+    func testDictationCommandsAreForwarded() {
+        XCTAssertTrue(respond(#"{"token": "secret", "command": "start"}"#).ok)
+        XCTAssertTrue(respond(#"{"token": "secret", "command": "toggle"}"#).ok)
+        XCTAssertEqual(target.triggers, [.start, .toggle])
+    }
+
+    func testStatusReportsTheCurrentConfig() {
+        let response = respond(#"{"token": "secret", "command": "status"}"#)
+        XCTAssertEqual(response.status, ControlStatus(state: "idle", model: "apple-native", language: "English", paused: false))
+    }
+
+    func testMetricsAreReturnedWithTheStatus() {
+        let response = respond(#"{"token": "secret", "command": "metrics"}"#)
+        XCTAssertTrue(response.ok)
+        XCTAssertEqual(response.metrics?.transcriptions, 0)
+        XCTAssertNotNil(response.status)
+        XCTAssertNil(respond(#"{"token": "secret", "command": "status"}"#).metrics)
+    }
+
+    func testSwitchModelNeedsAValueAndReportsRejections() {
+        XCTAssertFalse(respond(#"{"token": "secret", "command": "switch-model"}"#).ok)
+        XCTAssertEqual(respond(#"{"token": "secret", "command": "switch-model", "value": "nope"}"#),
+                       .failure("Unknown model 'nope'."))
+        XCTAssertEqual(respond(#"{"token": "secret", "command": "switch-model", "value": "parakeet-v3"}"#).status?.model,
+                       "parakeet-v3")
+    }
+
+    func testReplayNeedsAFileAndReportsRejections() {
+        XCTAssertEqual(respond(#"{"token": "secret", "command": "replay"}"#), .failure("'replay' needs a \"value\"."))
+        XCTAssertEqual(respond(#"{"token": "secret", "command": "replay", "value": "/nope.wav"}"#),
+                       .failure("No such file: /nope.wav"))
+        XCTAssertTrue(respond(#"{"token": "secret", "command": "replay", "value": "/"}"#).ok)
+        XCTAssertEqual(target.replayed, ["/"])
+    }
+
+    func testLauncherCommandsAreForwarded() {
+        XCTAssertTrue(respond(#"{"token": "secret", "command": "dictate-to-clipboard"}"#).ok)
+        XCTAssertEqual(target.clipboardDictations, 1)
+
+        XCTAssertTrue(respond(#"{"token": "secret", "command": "toggle-pause"}"#).ok)
+        XCTAssertTrue(target.paused)
+        target.canPause = false
+        XCTAssertFalse(respond(#"{"token": "secret", "command": "toggle-pause"}"#).ok)
+
+        XCTAssertEqual(respond(#"{"token": "secret", "command": "switch-profile"}"#),
+                       .failure("'switch-profile' needs a \"value\"."))
+        XCTAssertFalse(respond(#"{"token": "secret", "command": "switch-profile", "value": "Poetry"}"#).ok)
+        XCTAssertTrue(respond(#"{"token": "secret", "command": "switch-profile", "value": " Email "}"#).ok)
+        XCTAssertEqual(target.profile, "Email")
+    }
+
+    func testTranscribeFileReturnsTheText() async {
+        target.transcripts["/tmp/memo.wav"] = "Buy milk."
+        let request = Data(#"{"token": "secret", "command": "transcribe-file", "value": "/tmp/memo.wav"}"#.utf8)
+        let response = await ControlSocketService.response(to: request, token: token, target: target)
+        XCTAssertEqual(response.text, "Buy milk.")
+
+        let missing = Data(#"{"token": "secret", "command": "transcribe-file", "value": "/nope.wav"}"#.utf8)
+        let failure = await ControlSocketService.response(to: missing, token: token, target: target)
+        XCTAssertFalse(failure.ok)
+        XCTAssertNil(failure.text)
+    }
+
+    func testKeyPressesAreForwarded() {
+        XCTAssertTrue(respond(#"{"token": "secret", "command": "key-down"}"#).ok)
+        XCTAssertTrue(respond(#"{"token": "secret", "command": "key-up"}"#).ok)
+        XCTAssertEqual(target.keys, [true, false])
+    }
+
+    func testEventsCarryTheTrayIconAndBadge() throws {
+        let event = ControlEvent(state: .recording, icon: .recording)
+        let json = try XCTUnwrap(String(data: JSONEncoder().encode(event), encoding: .utf8))
+        XCTAssertTrue(json.contains(#""event":"state""#))
+        XCTAssertEqual(event.icon, "recording")
+        XCTAssertEqual(event.title, "REC")
+        XCTAssertNil(ControlEvent(state: .idle, icon: .idle).title)
+    }
+
+    func testSubscribersReceiveStateChanges() async throws {
+        let directory = URL(fileURLWithPath: "/tmp/vg-\(UUID().uuidString.prefix(8))", isDirectory: true)
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        defer { try? FileManager.default.removeItem(at: directory) }
+        let service = ControlSocketService(directory: directory, target: target)
+        try service.start()
+        defer { service.stop() }
+
+        let client = try XCTUnwrap(Self.connect(to: service.socketURL.path))
+        defer { close(client) }
+        let token = try service.loadOrCreateToken()
+        let request = #"{"token": "\#(token)", "command": "subscribe"}"# + "\n"
+        _ = request.withCString { write(client, $0, strlen($0)) }
+        let answer = await Task.detached { Self.readLine(client) }.value
+        XCTAssertTrue(answer.contains(#""ok":true"#))
+
+        for _ in 0..<50 where service.subscriberCount == 0 {
+            try await Task.sleep(nanoseconds: 10_000_000)
+        }
+        service.publish(ControlEvent(state: .processing, icon: .processing))
+        let pushed = await Task.detached { Self.readLine(client) }.value
+        let event = try JSONDecoder().decode(ControlEvent.self, from: Data(pushed.utf8))
+        XCTAssertEqual(event, ControlEvent(state: .processing, icon: .processing))
+    }
+
+    func testSilentClientDoesNotBlockOthers() async throws {
+        let directory = URL(fileURLWithPath: "/tmp/vg-\(UUID().uuidString.prefix(8))", isDirectory: true)
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        defer { try? FileManager.default.removeItem(at: directory) }
+        let service = ControlSocketService(directory: directory, target: target)
+        try service.start()
+        defer { service.stop() }
+
+        // Connects and never sends a line.
+        let silent = try XCTUnwrap(Self.connect(to: service.socketURL.path))
+        defer { close(silent) }
+
+        let client = try XCTUnwrap(Self.connect(to: service.socketURL.path))
+        defer { close(client) }
+        let token = try service.loadOrCreateToken()
+        let request = #"{"token": "\#(token)", "command": "status"}"# + "\n"
+        _ = request.withCString { write(client, $0, strlen($0)) }
+        let answer = await Task.detached { Self.readLine(client) }.value
+        XCTAssertTrue(answer.contains(#""ok":true"#))
+        XCTAssertEqual(service.subscriberCount, 0)
+    }
+
+    func testStopDoesNotWaitForConnectedClients() throws {
+        let directory = URL(fileURLWithPath: "/tmp/vg-\(UUID().uuidString.prefix(8))", isDirectory: true)
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        defer { try? FileManager.default.removeItem(at: directory) }
+        let service = ControlSocketService(directory: directory, target: target)
+        try service.start()
+
+        let silent = try XCTUnwrap(Self.connect(to: service.socketURL.path))
+        defer { close(silent) }
+        let started = Date()
+        service.stop()
+        XCTAssertFalse(service.isRunning)
+        XCTAssertLessThan(Date().timeIntervalSince(started), 1)
+    }
+
+    func testRequestReadTimesOut() {
+        var fds: [Int32] = [0, 0]
+        XCTAssertEqual(socketpair(AF_UNIX, SOCK_STREAM, 0, &fds), 0)
+        defer { fds.forEach { close($0) } }
+        _ = "partial".withCString { write(fds[1], $0, strlen($0)) }
+
+        let started = Date()
+        let request = ControlSocketService.readRequest(from: fds[0], maxBytes: 1024, timeout: 1) {
+            $0.contains(UInt8(ascii: "\n"))
+        }
+        XCTAssertEqual(String(decoding: request, as: UTF8.self), "partial")
+        XCTAssertLessThan(Date().timeIntervalSince(started), 5)
+    }
+
+    private nonisolated static func connect(to path: String) -> Int32? {
+        let fd = socket(AF_UNIX, SOCK_STREAM, 0)
+        var address = sockaddr_un()
+        address.sun_family = sa_family_t(AF_UNIX)
+        withUnsafeMutableBytes(of: &address.sun_path) { $0.copyBytes(from: path.utf8) }
+        let connected = withUnsafePointer(to: &address) {
+            $0.withMemoryRebound(to: sockaddr.self, capacity: 1) {
+                Darwin.connect(fd, $0, socklen_t(MemoryLayout<sockaddr_un>.size))
+            }
+        }
+        guard connected == 0 else {
+            close(fd)
+            return nil
+        }
+        return fd
+    }
+
+    /// Reads one byte at a time up to a newline, so nothing of the next line is consumed.
+    private nonisolated static func readLine(_ fd: Int32) -> String {
+        var line = Data()
+        var byte: UInt8 = 0
+        while read(fd, &byte, 1) == 1, byte != UInt8(ascii: "\n") {
+            line.append(byte)
+        }
+        return String(decoding: line, as: UTF8.self)
+    }
+
+    func testProfilesMatchByIdOrNameIgnoringCase() {
+        let email = PostProcessingTemplate(name: "Email")
+        let templates = [PostProcessingTemplate(name: "General Cleanup"), email]
+        XCTAssertTrue(PostProcessingTemplate.matching("email", in: templates) === email)
+        XCTAssertTrue(PostProcessingTemplate.matching(email.id.uuidString.lowercased(), in: templates) === email)
+        XCTAssertNil(PostProcessingTemplate.matching("Poetry", in: templates))
+    }
+
+    func testMalformedAndUnknownRequests() {
+        XCTAssertFalse(respond("not json").ok)
+        XCTAssertTrue(respond(#"{"token": "secret", "command": "dance"}"#).error?.hasPrefix("Unknown command") ?? false)
+        target.hasSomethingToPaste = false
+        XCTAssertEqual(respond(#"{"token": "secret", "command": "paste-last"}"#), .failure("Nothing to paste."))
+    }
+
+    func testTokenIsCreatedOnceAndKept() throws {
+        let directory = FileManager.default.temporaryDirectory
+            .appendingPathComponent("ControlSocketServiceTests-\(UUID().uuidString)", isDirectory: true)
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        defer { try? FileManager.default.removeItem(at: directory) }
+
+        let service = ControlSocketService(directory: directory, target: target)
+        let first = try service.loadOrCreateToken()
+        XCTAssertEqual(first.count, 64)
+        XCTAssertEqual(try service.loadOrCreateToken(), first)
+    }
+}
@@ -0,0 +1,54 @@
+import XCTest
+@testable import VocaGlyph
+
+final class CrashReporterTests: XCTestCase {
+
+    private var directory: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory
+            .appendingPathComponent("CrashReporterTests-\(UUID().uuidString)", isDirectory: true)
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    func testPendingReportsReadsCrashFilesOnly() throws {
+        try "Signal 6 (SIGABRT)\nframes".write(to: directory.appendingPathComponent("crash-1.crash"), atomically: true, encoding: .utf8)
+        try "".write(to: directory.appendingPathComponent("crash-2.crash"), atomically: true, encoding: .utf8)
+        try "notes".write(to: directory.appendingPathComponent("readme.txt"), atomically: true, encoding: .utf8)
+
+        let reports = CrashReporter.pendingReports(in: directory)
+        XCTAssertEqual(reports.count, 1)
+        XCTAssertEqual(reports.first?.summary, "Signal 6 (SIGABRT)")
+    }
+
+    func testExceptionReportIsWrittenAndDiscarded() {
+        let exception = NSException(name: .invalidArgumentException, reason: "bad index", userInfo: nil)
+        CrashReporter.write(CrashReporter.exceptionReport(exception), to: directory)
+
+        let reports = CrashReporter.pendingReports(in: directory)
+        XCTAssertEqual(reports.first?.summary, "Uncaught exception NSInvalidArgumentException: bad index")
+
+        CrashReporter.discard(reports)
+        XCTAssertTrue(CrashReporter.pendingReports(in: directory).isEmpty)
+    }
+
+    func testIssueURLTruncatesLongReports() throws {
+        let report = CrashReport(url: directory.appendingPathComponent("crash-1.crash"),
+                                 contents: "Signal 11 (SIGSEGV)\n" + String(repeating: "x", count: 10_000))
+        let url = try XCTUnwrap(CrashReporter.issueURL(for: report))
+        let items = URLComponents(url: url, resolvingAgainstBaseURL: false)?.queryItems ?? []
+
+        XCTAssertEqual(items.first { $0.name == "title" }?.value, "Crash: Signal 11 (SIGSEGV)")
+        let body = try XCTUnwrap(items.first { $0.name == "body" }?.value)
+        XCTAssertTrue(body.contains("… (truncated)"))
+        XCTAssertLessThan(body.count, CrashReporter.maxIssueBodyLength + 200)
+    }
+
+    func testCrashReportingIsOptIn() {
+        XCTAssertFalse(AppSettings.defaults.crashReportingEnabled)
+    }
+}
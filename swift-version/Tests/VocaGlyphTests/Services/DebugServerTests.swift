@@ -0,0 +1,47 @@
+import XCTest
+@testable import VocaGlyph
+
+final class DebugServerTests: XCTestCase {
+
+    func testRequestLinesMapToEndpoints() {
+        XCTAssertEqual(DebugServer.endpoint(forRequestLine: "GET /debug/ HTTP/1.1"), .index)
+        XCTAssertEqual(DebugServer.endpoint(forRequestLine: "GET /debug/threads HTTP/1.1"), .threads)
+        XCTAssertEqual(DebugServer.endpoint(forRequestLine: "GET /debug/heap HTTP/1.1"), .heap)
+        XCTAssertEqual(DebugServer.endpoint(forRequestLine: "GET /debug/leaks HTTP/1.0"), .leaks)
+        XCTAssertEqual(DebugServer.endpoint(forRequestLine: "GET /debug/resources HTTP/1.1"), .resources)
+        XCTAssertNil(DebugServer.endpoint(forRequestLine: "GET /debug/pprof HTTP/1.1"))
+        XCTAssertNil(DebugServer.endpoint(forRequestLine: "POST /debug/threads HTTP/1.1"))
+        XCTAssertNil(DebugServer.endpoint(forRequestLine: ""))
+    }
+
+    func testProfileSecondsAreClamped() {
+        XCTAssertEqual(DebugServer.endpoint(forRequestLine: "GET /debug/profile HTTP/1.1"),
+                       .profile(seconds: DebugServer.defaultProfileSeconds))
+        XCTAssertEqual(DebugServer.endpoint(forRequestLine: "GET /debug/profile?seconds=12 HTTP/1.1"), .profile(seconds: 12))
+        XCTAssertEqual(DebugServer.endpoint(forRequestLine: "GET /debug/profile?seconds=600 HTTP/1.1"), .profile(seconds: 60))
+        XCTAssertEqual(DebugServer.endpoint(forRequestLine: "GET /debug/profile?seconds=0 HTTP/1.1"), .profile(seconds: 1))
+    }
+
+    func testOnlyRequestsForThisServerAreAllowed() {
+        let port = DebugServer.defaultPort
+        XCTAssertTrue(DebugServer.isAllowedHost(inRequest: "GET /debug/ HTTP/1.1\r\nHost: localhost:6060\r\n\r\n", port: port))
+        XCTAssertTrue(DebugServer.isAllowedHost(inRequest: "GET /debug/ HTTP/1.1\r\nhost: 127.0.0.1:6060\r\n\r\n", port: port))
+        XCTAssertFalse(DebugServer.isAllowedHost(inRequest: "GET /debug/leaks HTTP/1.1\r\nHost: attacker.example:6060\r\n\r\n", port: port),
+                       "A rebound host name must not reach the endpoints")
+        XCTAssertFalse(DebugServer.isAllowedHost(inRequest: "GET /debug/ HTTP/1.1\r\nHost: localhost:7070\r\n\r\n", port: port))
+        XCTAssertFalse(DebugServer.isAllowedHost(inRequest: "GET /debug/ HTTP/1.0\r\n\r\n", port: port))
+    }
+
+    func testThreadsIncludeANamedThread() {
+        let expectation = expectation(description: "thread listed")
+        let thread = Thread {
+            let threads = DebugServer.threads()
+            XCTAssertTrue(threads.contains { $0.name == "DebugServerTests.worker" })
+            XCTAssertTrue(DebugServer.format(threads).hasPrefix("\(threads.count) threads\n"))
+            expectation.fulfill()
+        }
+        thread.name = "DebugServerTests.worker"
+        thread.start()
+        wait(for: [expectation], timeout: 5)
+    }
+}
@@ -0,0 +1,111 @@
+import XCTest
+import AVFoundation
+@testable import VocaGlyph
+
+final class DictationPipelineTests: XCTestCase {
+
+    private final class MockSource: AudioSource {
+        var startError: Error?
+        /// Blocks `startCapture()` until signalled, to stop while a start is in flight.
+        var startGate: DispatchSemaphore?
+        private(set) var events: [String] = []
+        private let lock = NSLock()
+
+        func startCapture() throws {
+            startGate?.wait()
+            record("start")
+            if let startError { throw startError }
+        }
+
+        func stopCapture() -> AVAudioPCMBuffer? {
+            record("stop")
+            let format = AVAudioFormat(standardFormatWithSampleRate: 16_000, channels: 1)!
+            return AVAudioPCMBuffer(pcmFormat: format, frameCapacity: 160)
+        }
+
+        private func record(_ event: String) {
+            lock.lock()
+            events.append(event)
+            lock.unlock()
+        }
+    }
+
+    private struct Failure: Error {}
+
+    func testSinksRunInOrderUntilOneTakesTheText() {
+        var received: [String] = []
+        let pipeline = DictationPipeline(sinks: [
+            ClosureSink(name: "first") { received.append("first:\($0)"); return false },
+            ClosureSink(name: "second") { received.append("second:\($0)"); return true },
+            ClosureSink(name: "third") { received.append("third:\($0)"); return false },
+        ])
+        pipeline.deliver("hello")
+        XCTAssertEqual(received, ["first:hello", "second:hello"])
+    }
+
+    func testAddSinkInsertsAtIndexOrLast() {
+        let pipeline = DictationPipeline(sinks: [ClosureSink(name: "output") { _ in true }])
+        pipeline.addSink(ClosureSink(name: "webhook") { _ in false }, at: 0)
+        pipeline.addSink(ClosureSink(name: "audit") { _ in false })
+        XCTAssertEqual(pipeline.sinks.map(\.name), ["webhook", "output", "audit"])
+    }
+
+    func testStopWaitsForAStartInFlight() {
+        let source = MockSource()
+        source.startGate = DispatchSemaphore(value: 0)
+        let pipeline = DictationPipeline(source: source)
+        let captured = expectation(description: "audio")
+
+        pipeline.startCapture { _ in XCTFail("start should succeed") }
+        pipeline.stopCapture { buffer in
+            XCTAssertNotNil(buffer)
+            captured.fulfill()
+        }
+        source.startGate?.signal()
+
+        wait(for: [captured], timeout: 2)
+        XCTAssertEqual(source.events, ["start", "stop"])
+    }
+
+    func testStartFailureIsReportedOnMain() {
+        let source = MockSource()
+        source.startError = Failure()
+        let pipeline = DictationPipeline(source: source)
+        let failed = expectation(description: "failure")
+
+        pipeline.startCapture { error in
+            XCTAssertTrue(Thread.isMainThread)
+            XCTAssertTrue(error is Failure)
+            failed.fulfill()
+        }
+        wait(for: [failed], timeout: 2)
+    }
+
+    func testNextSourceIsUsedForOneCaptureOnly() {
+        let live = MockSource()
+        let replay = MockSource()
+        let pipeline = DictationPipeline(source: live)
+        pipeline.nextSource = replay
+
+        for _ in 0..<2 {
+            let captured = expectation(description: "audio")
+            pipeline.startCapture { _ in XCTFail("start should succeed") }
+            pipeline.stopCapture { _ in captured.fulfill() }
+            wait(for: [captured], timeout: 2)
+        }
+
+        XCTAssertEqual(replay.events, ["start", "stop"])
+        XCTAssertEqual(live.events, ["start", "stop"])
+        XCTAssertNil(pipeline.nextSource)
+    }
+
+    func testStopWithoutSourceReportsNoAudio() {
+        let pipeline = DictationPipeline()
+        var called = false
+        pipeline.stopCapture { buffer in
+            XCTAssertNil(buffer)
+            called = true
+        }
+        XCTAssertTrue(called)
+    }
+}
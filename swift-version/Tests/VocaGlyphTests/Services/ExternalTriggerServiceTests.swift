@@ -0,0 +1,77 @@
+import XCTest
+@testable import VocaGlyph
+
+// MARK: - ExternalTriggerServiceTests
+
+final class ExternalTriggerServiceTests: XCTestCase {
+
+    // MARK: - Helpers
+
+    private func makeSUT() -> (ExternalTriggerService, AppStateManager, NotificationCenter) {
+        let manager = AppStateManager()
+        let center = NotificationCenter()
+        let sut = ExternalTriggerService(stateManager: manager, center: center)
+        return (sut, manager, center)
+    }
+
+    // MARK: - Notification names
+
+    func test_notificationNames_arePrefixed() {
+        XCTAssertEqual(ExternalTriggerCommand.start.notificationName.rawValue, "com.vocaglyph.dictation.start")
+        XCTAssertEqual(ExternalTriggerCommand.stop.notificationName.rawValue, "com.vocaglyph.dictation.stop")
+        XCTAssertEqual(ExternalTriggerCommand.toggle.notificationName.rawValue, "com.vocaglyph.dictation.toggle")
+    }
+
+    // MARK: - handle(_:)
+
+    func test_handle_start_fromIdle_startsRecording() {
+        let (sut, manager, _) = makeSUT()
+        sut.handle(.start)
+        XCTAssertEqual(manager.currentState, .recording)
+    }
+
+    func test_handle_stop_whileRecording_movesToProcessing() {
+        let (sut, manager, _) = makeSUT()
+        manager.startRecording()
+        sut.handle(.stop)
+        XCTAssertEqual(manager.currentState, .processing)
+    }
+
+    func test_handle_toggle_alternatesBetweenRecordingAndProcessing() {
+        let (sut, manager, _) = makeSUT()
+        sut.handle(.toggle)
+        XCTAssertEqual(manager.currentState, .recording)
+        sut.handle(.toggle)
+        XCTAssertEqual(manager.currentState, .processing)
+    }
+
+    func test_handle_start_whileInitializing_staysInitializing() {
+        let (sut, manager, _) = makeSUT()
+        manager.setInitializing()
+        sut.handle(.start)
+        XCTAssertEqual(manager.currentState, .initializing)
+    }
+
+    // MARK: - Observation
+
+    func test_start_postedNotification_triggersCommand() {
+        let (sut, manager, center) = makeSUT()
+        sut.start()
+
+        center.post(name: ExternalTriggerCommand.start.notificationName, object: nil)
+        RunLoop.main.run(until: Date().addingTimeInterval(0.05)) // observers deliver on .main
+
+        XCTAssertEqual(manager.currentState, .recording)
+    }
+
+    func test_stop_removesObservers() {
+        let (sut, manager, center) = makeSUT()
+        sut.start()
+        sut.stop()
+
+        center.post(name: ExternalTriggerCommand.start.notificationName, object: nil)
+        RunLoop.main.run(until: Date().addingTimeInterval(0.05)) // observers deliver on .main
+
+        XCTAssertEqual(manager.currentState, .idle)
+    }
+}
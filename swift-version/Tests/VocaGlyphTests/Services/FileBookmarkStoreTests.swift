@@ -0,0 +1,41 @@
+import XCTest
+@testable import VocaGlyph
+
+final class FileBookmarkStoreTests: XCTestCase {
+
+    private let suiteName = "FileBookmarkStoreTests"
+    private var defaults: UserDefaults!
+    private var file: URL!
+    private var store: FileBookmarkStore!
+
+    override func setUpWithError() throws {
+        try super.setUpWithError()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+        file = FileManager.default.temporaryDirectory
+            .appendingPathComponent("FileBookmarkStoreTests-\(UUID().uuidString).bin")
+        try Data([0x01]).write(to: file)
+        // Unscoped: tests don't run in the sandbox.
+        store = FileBookmarkStore(defaults: defaults, scoped: false)
+    }
+
+    override func tearDown() {
+        defaults.removePersistentDomain(forName: suiteName)
+        try? FileManager.default.removeItem(at: file)
+        super.tearDown()
+    }
+
+    func testRememberedFileIsKeptPerPathUntilForgotten() {
+        store.remember(file)
+        XCTAssertTrue(FileBookmarkStore(defaults: defaults, scoped: false).hasBookmark(for: file.path))
+
+        store.forget(file.path)
+        XCTAssertFalse(store.hasBookmark(for: file.path))
+    }
+
+    func testAccessWithoutABookmarkIsANoOp() {
+        let endAccess = store.beginAccess(toPath: "/nonexistent/model.bin")
+        endAccess()
+        XCTAssertFalse(store.hasBookmark(for: "/nonexistent/model.bin"))
+    }
+}
@@ -0,0 +1,94 @@
+import XCTest
+@testable import VocaGlyph
+
+// MARK: - HardwareDefaultsServiceTests
+
+final class HardwareDefaultsServiceTests: XCTestCase {
+
+    private let suiteName = "HardwareDefaultsServiceTests"
+    private var defaults: UserDefaults!
+
+    private let smallMac = HardwareProfile(chip: "Apple M1", isAppleSilicon: true, performanceCores: 4, totalCores: 8, memoryGB: 8)
+    private let bigMac = HardwareProfile(chip: "Apple M3 Max", isAppleSilicon: true, performanceCores: 12, totalCores: 16, memoryGB: 64)
+    private let intelMac = HardwareProfile(chip: "Intel(R) Core(TM) i7-9750H", isAppleSilicon: false, performanceCores: 6, totalCores: 12, memoryGB: 16)
+
+    override func setUp() {
+        super.setUp()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+    }
+
+    override func tearDown() {
+        defaults.removePersistentDomain(forName: suiteName)
+        defaults = nil
+        super.tearDown()
+    }
+
+    private func makeStore() -> SettingsStore {
+        SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+    }
+
+    // MARK: - Recommendation rules
+
+    func test_recommendation_lowMemoryAppleSilicon_picksParakeet() {
+        let recommendation = HardwareRecommendation(profile: smallMac)
+        XCTAssertEqual(recommendation.recommendedModel, "parakeet-v3")
+        XCTAssertEqual(recommendation.transcriptionWorkerCount, 4)
+    }
+
+    func test_recommendation_highMemoryAppleSilicon_picksTurboAndCapsWorkers() {
+        let recommendation = HardwareRecommendation(profile: bigMac)
+        XCTAssertEqual(recommendation.recommendedModel, "large-v3_turbo")
+        XCTAssertEqual(recommendation.transcriptionWorkerCount, 8)
+    }
+
+    func test_recommendation_intel_usesAppleNativeWithoutFallbacks() {
+        let recommendation = HardwareRecommendation(profile: intelMac)
+        XCTAssertEqual(recommendation.recommendedModel, "apple-native")
+        XCTAssertEqual(recommendation.temperatureFallbackCount, 0)
+    }
+
+    func test_recommendation_alwaysPassesValidation() {
+        for profile in [smallMac, bigMac, intelMac] {
+            let recommendation = HardwareRecommendation(profile: profile)
+            var settings = AppSettings.defaults
+            settings.transcriptionWorkerCount = recommendation.transcriptionWorkerCount
+            settings.temperatureFallbackCount = recommendation.temperatureFallbackCount
+            XCTAssertTrue(SettingsValidator.validate(settings).isEmpty, profile.chip)
+        }
+    }
+
+    // MARK: - applyIfNeeded
+
+    func test_applyIfNeeded_firstRun_writesTunedSettingsAndStoresEvaluation() {
+        let store = makeStore()
+
+        HardwareDefaultsService.applyIfNeeded(profile: intelMac, store: store, defaults: defaults)
+
+        XCTAssertEqual(store.settings.transcriptionWorkerCount, 2)
+        XCTAssertEqual(store.settings.temperatureFallbackCount, 0)
+        XCTAssertEqual(HardwareDefaultsService.storedEvaluation(defaults: defaults)?.profile, intelMac)
+    }
+
+    func test_applyIfNeeded_sameHardware_keepsUserChanges() {
+        let store = makeStore()
+        HardwareDefaultsService.applyIfNeeded(profile: smallMac, store: store, defaults: defaults)
+        store.update { $0.transcriptionWorkerCount = 2 }
+
+        HardwareDefaultsService.applyIfNeeded(profile: smallMac, store: store, defaults: defaults)
+
+        XCTAssertEqual(store.settings.transcriptionWorkerCount, 2)
+    }
+
+    func test_applyIfNeeded_newHardware_updatesOnlyUntouchedFields() {
+        let store = makeStore()
+        HardwareDefaultsService.applyIfNeeded(profile: intelMac, store: store, defaults: defaults)
+        store.update { $0.temperatureFallbackCount = 3 }
+
+        let evaluation = HardwareDefaultsService.applyIfNeeded(profile: bigMac, store: store, defaults: defaults)
+
+        XCTAssertEqual(store.settings.transcriptionWorkerCount, 8)
+        XCTAssertEqual(store.settings.temperatureFallbackCount, 3)
+        XCTAssertEqual(evaluation.recommendation.recommendedModel, "large-v3_turbo")
+    }
+}
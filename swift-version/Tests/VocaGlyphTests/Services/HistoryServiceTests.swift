@@ -0,0 +1,117 @@
+import XCTest
+import SwiftData
+@testable import VocaGlyph
+
+@MainActor
+final class HistoryServiceTests: XCTestCase {
+
+    private let suiteName = "HistoryServiceTests"
+    private var defaults: UserDefaults!
+    private var store: SettingsStore!
+    private var container: ModelContainer!
+    private var sut: HistoryService!
+
+    override func setUpWithError() throws {
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+        store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+
+        let schema = Schema([TranscriptionItem.self])
+        container = try ModelContainer(for: schema, configurations: [ModelConfiguration(schema: schema, isStoredInMemoryOnly: true)])
+        sut = HistoryService(container: container, store: store)
+    }
+
+    override func tearDownWithError() throws {
+        defaults.removePersistentDomain(forName: suiteName)
+        sut = nil
+        container = nil
+    }
+
+    private func insert(ageInDays days: [Int]) throws {
+        for day in days {
+            let timestamp = Calendar.current.date(byAdding: .day, value: -day, to: Date())!
+            container.mainContext.insert(TranscriptionItem(text: "\(day) days old", timestamp: timestamp))
+        }
+        try container.mainContext.save()
+    }
+
+    private func texts() throws -> [String] {
+        try container.mainContext.fetch(FetchDescriptor<TranscriptionItem>(sortBy: [SortDescriptor(\.timestamp, order: .reverse)]))
+            .map(\.text)
+    }
+
+    func testDaysRetentionDeletesOlderEntries() throws {
+        store.update { $0.historyRetentionDays = 7 }
+        try insert(ageInDays: [1, 6, 8, 40])
+
+        XCTAssertEqual(sut.enforceRetention(), 2)
+        XCTAssertEqual(try texts(), ["1 days old", "6 days old"])
+    }
+
+    func testEntriesRetentionKeepsNewest() throws {
+        store.update {
+            $0.historyRetention = HistoryService.Retention.entries.rawValue
+            $0.historyRetentionEntries = 2
+        }
+        try insert(ageInDays: [3, 1, 2])
+
+        sut.record("Just now")
+
+        XCTAssertEqual(try texts(), ["Just now", "1 days old"])
+    }
+
+    func testUnlimitedRetentionKeepsEverything() throws {
+        store.update { $0.historyRetention = HistoryService.Retention.unlimited.rawValue }
+        try insert(ageInDays: [1, 400])
+
+        XCTAssertEqual(sut.enforceRetention(), 0)
+        XCTAssertEqual(try texts().count, 2)
+    }
+
+    func testDisabledRetentionRecordsNothing() throws {
+        store.update { $0.historyRetention = HistoryService.Retention.disabled.rawValue }
+
+        XCTAssertNil(sut.record("Secret"))
+        XCTAssertEqual(try texts(), [])
+    }
+
+    func testRecordedTextIsRedactedWhenEnabled() throws {
+        store.update { $0.redactionEnabled = true }
+
+        sut.record("Send it to jane@example.com")
+
+        XCTAssertEqual(try texts(), ["Send it to [email]"])
+    }
+
+    // MARK: - Clearing
+
+    func testClearHistoryRequiresIssuedToken() throws {
+        try insert(ageInDays: [1, 2])
+
+        XCTAssertThrowsError(try sut.clearHistory(confirmationToken: "guess")) { error in
+            XCTAssertEqual(error as? HistoryError, .invalidConfirmationToken)
+        }
+        let token = sut.clearHistoryConfirmationToken()
+
+        XCTAssertEqual(try sut.clearHistory(confirmationToken: token), 2)
+        XCTAssertEqual(try texts(), [])
+        XCTAssertThrowsError(try sut.clearHistory(confirmationToken: token), "Tokens are single-use")
+    }
+
+    func testExpiredTokenIsRejected() throws {
+        let issued = Date()
+        let token = sut.clearHistoryConfirmationToken(now: issued)
+
+        XCTAssertThrowsError(try sut.clearHistory(confirmationToken: token,
+                                                  now: issued.addingTimeInterval(HistoryService.confirmationTokenLifetime + 1)))
+    }
+
+    func testRecordingPostsHistoryChanged() {
+        let center = NotificationCenter()
+        let service = HistoryService(container: container, store: store, notificationCenter: center)
+        let posted = expectation(forNotification: .historyChanged, object: service, notificationCenter: center)
+
+        service.record("hello")
+        wait(for: [posted], timeout: 1)
+    }
+}
@@ -0,0 +1,112 @@
+import XCTest
+@testable import VocaGlyph
+
+final class HotkeyDispatcherTests: XCTestCase {
+
+    private final class MockTarget: HotkeyTarget {
+        var currentState: AppState = .idle
+        /// What `startRecording` moves to; `.idle` for a start the state machine rejects.
+        var stateAfterStart: AppState = .recording
+        private(set) var calls: [String] = []
+
+        func startRecording(capturingContext: Bool) {
+            calls.append("start")
+            currentState = stateAfterStart
+        }
+
+        func stopRecording() {
+            calls.append("stop")
+            if currentState == .recording { currentState = .processing }
+        }
+
+        func flashNotReadyMessage() { calls.append("notReady") }
+    }
+
+    private var target: MockTarget!
+    private var clock: CFAbsoluteTime = 1_000
+    private var dispatcher: HotkeyDispatcher!
+
+    override func setUp() {
+        super.setUp()
+        target = MockTarget()
+        dispatcher = HotkeyDispatcher(target: target, now: { [unowned self] in self.clock })
+    }
+
+    func testPressAndReleaseRecordOnce() {
+        dispatcher.handle(.pressed)
+        dispatcher.handle(.pressed) // auto-repeat
+        dispatcher.handle(.released)
+        XCTAssertEqual(target.calls, ["start", "stop"])
+        XCTAssertEqual(dispatcher.phase, .finishing)
+    }
+
+    func testPressWhileProcessingWaitsForTheAppToRest() {
+        dispatcher.handle(.pressed)
+        dispatcher.handle(.released)
+        clock += 1
+        dispatcher.handle(.pressed)
+        XCTAssertEqual(target.calls, ["start", "stop"])
+
+        target.currentState = .idle
+        dispatcher.handle(.appRested)
+        dispatcher.handle(.pressed)
+        XCTAssertEqual(target.calls, ["start", "stop", "start"])
+    }
+
+    func testBounceWithinTheDebounceIntervalIsIgnored() {
+        dispatcher.handle(.pressed)
+        dispatcher.handle(.released)
+        target.currentState = .idle
+        dispatcher.handle(.appRested)
+        clock += 0.01
+        dispatcher.handle(.pressed)
+        XCTAssertEqual(target.calls, ["start", "stop"])
+    }
+
+    func testPressWhileLoadingFlashesNotReady() {
+        target.currentState = .initializing
+        dispatcher.handle(.pressed)
+        dispatcher.handle(.released)
+        XCTAssertEqual(target.calls, ["notReady"])
+        XCTAssertEqual(dispatcher.phase, .ready)
+    }
+
+    func testReleaseDoesNotStopADictationStartedElsewhere() {
+        target.currentState = .recording // e.g. a foot pedal
+        dispatcher.handle(.pressed)
+        dispatcher.handle(.released)
+        XCTAssertTrue(target.calls.isEmpty)
+    }
+
+    func testRejectedStartLeavesTheNextPressFree() {
+        target.stateAfterStart = .idle
+        dispatcher.handle(.pressed)
+        XCTAssertEqual(dispatcher.phase, .ready)
+        dispatcher.handle(.released)
+        XCTAssertEqual(target.calls, ["start"])
+    }
+
+    func testAppRestingWhileHeldKeepsThePressUntilReleased() {
+        dispatcher.handle(.pressed)
+        target.currentState = .error("mic")
+        dispatcher.handle(.appRested)
+        XCTAssertEqual(dispatcher.phase, .holding)
+        dispatcher.handle(.released)
+        XCTAssertEqual(dispatcher.phase, .ready)
+    }
+
+    func testSentEventsAreHandledInOrderOnTheQueue() {
+        let queue = DispatchQueue(label: "HotkeyDispatcherTests")
+        let queued = HotkeyDispatcher(target: target, queue: queue)
+        var toggles = 0
+        queued.onIncognitoToggle = { toggles += 1 }
+
+        queued.send(.pressed)
+        queued.send(.incognitoPressed)
+        queued.send(.released)
+        queue.sync {}
+
+        XCTAssertEqual(target.calls, ["start", "stop"])
+        XCTAssertEqual(toggles, 1)
+    }
+}
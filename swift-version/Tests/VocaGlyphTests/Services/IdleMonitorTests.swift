@@ -0,0 +1,64 @@
+import XCTest
+@testable import VocaGlyph
+
+final class IdleMonitorTests: XCTestCase {
+
+    private var now = Date(timeIntervalSince1970: 1_000)
+
+    private func makeMonitor(timeout: TimeInterval = 600) -> IdleMonitor {
+        IdleMonitor(timeout: timeout, now: { [unowned self] in self.now })
+    }
+
+    func testGoesIdleOnceAfterTimeout() {
+        let monitor = makeMonitor()
+        var idleCount = 0
+        monitor.onIdle = { idleCount += 1 }
+
+        now += 599
+        monitor.check()
+        XCTAssertFalse(monitor.isIdle)
+
+        now += 1
+        monitor.check()
+        monitor.check()
+        XCTAssertTrue(monitor.isIdle)
+        XCTAssertEqual(idleCount, 1)
+    }
+
+    func testActivityResumesAndRestartsTheCount() {
+        let monitor = makeMonitor()
+        var resumed = 0
+        monitor.onResume = { resumed += 1 }
+
+        now += 600
+        monitor.check()
+        monitor.noteActivity()
+        XCTAssertFalse(monitor.isIdle)
+        XCTAssertEqual(resumed, 1)
+
+        now += 300
+        monitor.check()
+        XCTAssertFalse(monitor.isIdle)
+    }
+
+    func testActivityWhileActiveDoesNotResume() {
+        let monitor = makeMonitor()
+        var resumed = false
+        monitor.onResume = { resumed = true }
+        monitor.noteActivity()
+        XCTAssertFalse(resumed)
+    }
+
+    func testStopResumesWhenIdle() {
+        let monitor = makeMonitor()
+        var resumed = false
+        monitor.onResume = { resumed = true }
+        monitor.start()
+        now += 600
+        monitor.check()
+
+        monitor.stop()
+        XCTAssertTrue(resumed)
+        XCTAssertFalse(monitor.isRunning)
+    }
+}
@@ -86,4 +86,36 @@ final class KeychainServiceTests: XCTestCase {
             XCTFail("Expected itemNotFound, got \(error)")
         }
     }
+    
+    // MARK: - Typed Secrets
+    
+    func testSecretServiceIdentifiersAreStableAndUnique() {
+        // Existing installs saved API keys under these ids.
+        XCTAssertEqual(SecretKey.anthropicApiKey.service, "com.vocaglyph.api.anthropic")
+        XCTAssertEqual(SecretKey.geminiApiKey.service, "com.vocaglyph.api.gemini")
+        
+        let services = SecretKey.allCases.map(\.service)
+        XCTAssertEqual(Set(services).count, services.count, "Every secret needs its own Keychain item")
+    }
+    
+    func testSetSecretRoundTripAndBlankValueClears() async throws {
+        let key = SecretKey.huggingFaceToken
+        // Preserve whatever the developer machine already has saved.
+        let original = try await keychainService.secret(key)
+        
+        try await keychainService.setSecret("  token-123\n", for: key)
+        let saved = try await keychainService.secret(key)
+        XCTAssertEqual(saved, "token-123", "Surrounding whitespace should be trimmed")
+        let isSaved = await keychainService.hasSecret(key)
+        XCTAssertTrue(isSaved)
+        
+        try await keychainService.setSecret("   ", for: key)
+        let cleared = try await keychainService.secret(key)
+        XCTAssertNil(cleared)
+        
+        // Clearing again is a no-op, not an error.
+        try await keychainService.clearSecret(key)
+        
+        try await keychainService.setSecret(original, for: key)
+    }
 }
@@ -0,0 +1,30 @@
+import XCTest
+@testable import VocaGlyph
+
+final class LoggerServiceTests: XCTestCase {
+
+    func test_lastLines_skipsBlankLinesAndKeepsTheEnd() {
+        let text = "one\ntwo\n\nthree\n"
+        XCTAssertEqual(Logger.lastLines(of: text, count: 2), ["two", "three"])
+        XCTAssertEqual(Logger.lastLines(of: text, count: 10), ["one", "two", "three"])
+        XCTAssertEqual(Logger.lastLines(of: text, count: 0), [])
+    }
+
+    func test_lastLines_dropsTheFragmentOfAPartialRead() {
+        XCTAssertEqual(Logger.lastLines(of: "ne\ntwo\nthree\n", count: 5, startsMidLine: true), ["two", "three"])
+    }
+
+    func test_loggedLine_isPostedAndTailed() {
+        let marker = "LoggerServiceTests: \(UUID().uuidString)"
+        let posted = expectation(description: "line posted")
+        let token = LogLineEvent.observe { event in
+            if event.line.hasSuffix(marker) { posted.fulfill() }
+        }
+        defer { NotificationCenter.default.removeObserver(token) }
+
+        Logger.shared.error(marker)
+
+        wait(for: [posted], timeout: 2)
+        XCTAssertTrue(Logger.shared.tail(lines: 20).contains { $0.hasSuffix(marker) })
+    }
+}
@@ -0,0 +1,90 @@
+import XCTest
+import AppKit
+import ServiceManagement
+@testable import VocaGlyph
+
+private final class MockLoginItem: LoginItemRegistering {
+    var status: SMAppService.Status = .notRegistered
+    var registerError: Error?
+    var registerCalls = 0
+
+    func register() throws {
+        registerCalls += 1
+        if let registerError { throw registerError }
+        status = .enabled
+    }
+
+    func unregister() throws { status = .notRegistered }
+}
+
+final class LoginItemServiceTests: XCTestCase {
+
+    private var directory: URL!
+    private var app: MockLoginItem!
+    private var sut: LoginItemService!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory
+            .appendingPathComponent("LoginItemServiceTests-\(UUID().uuidString)", isDirectory: true)
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+        app = MockLoginItem()
+        sut = LoginItemService(app: app, launchAgentsDirectory: directory)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    @discardableResult
+    private func writeAgent(_ name: String, _ plist: [String: Any]) throws -> URL {
+        let url = directory.appendingPathComponent(name)
+        let data = try PropertyListSerialization.data(fromPropertyList: plist, format: .xml, options: 0)
+        try data.write(to: url)
+        return url
+    }
+
+    func testLoginLaunchIsReadFromTheOpenEvent() {
+        let open = NSAppleEventDescriptor(eventClass: AEEventClass(kCoreEventClass), eventID: AEEventID(kAEOpenApplication),
+                                          targetDescriptor: nil, returnID: AEReturnID(kAutoGenerateReturnID),
+                                          transactionID: AETransactionID(kAnyTransactionID))
+        XCTAssertFalse(LoginItemService.isLoginLaunch(open))
+        XCTAssertFalse(LoginItemService.isLoginLaunch(nil))
+
+        open.setParam(NSAppleEventDescriptor(enumCode: OSType(keyAELaunchedAsLogInItem)), forKeyword: AEKeyword(keyAEPropData))
+        XCTAssertTrue(LoginItemService.isLoginLaunch(open))
+    }
+
+    func testOnlyAgentsThatRunVocaGlyphAreFound() throws {
+        try writeAgent("com.vocaglyph.launcher.plist", ["ProgramArguments": ["/Applications/VocaGlyph.app/Contents/MacOS/VocaGlyph"]])
+        try writeAgent("local.open-vocaglyph.plist", ["ProgramArguments": ["/usr/bin/open", "-a", "VocaGlyph"]])
+        try writeAgent("com.example.other.plist", ["Program": "/usr/local/bin/other"])
+
+        XCTAssertEqual(sut.legacyLaunchAgents().map(\.lastPathComponent),
+                       ["com.vocaglyph.launcher.plist", "local.open-vocaglyph.plist"])
+    }
+
+    func testMigrationRegistersAndRemovesTheAgent() throws {
+        let agent = try writeAgent("com.vocaglyph.plist", ["Program": "/Applications/VocaGlyph.app/Contents/MacOS/VocaGlyph", "RunAtLoad": true])
+
+        XCTAssertTrue(sut.migrateLegacyLaunchAgents())
+        XCTAssertTrue(sut.isEnabled)
+        XCTAssertFalse(FileManager.default.fileExists(atPath: agent.path))
+        XCTAssertFalse(sut.migrateLegacyLaunchAgents(), "Nothing left to migrate")
+    }
+
+    func testFailedRegistrationKeepsTheAgent() throws {
+        app.registerError = NSError(domain: "SMAppServiceErrorDomain", code: 1)
+        let agent = try writeAgent("com.vocaglyph.plist", ["Program": "/Applications/VocaGlyph.app/Contents/MacOS/VocaGlyph", "RunAtLoad": true])
+
+        XCTAssertFalse(sut.migrateLegacyLaunchAgents())
+        XCTAssertTrue(FileManager.default.fileExists(atPath: agent.path))
+    }
+
+    func testAgentThatDidNotRunAtLoadIsRemovedWithoutRegistering() throws {
+        try writeAgent("com.vocaglyph.plist", ["Program": "/Applications/VocaGlyph.app/Contents/MacOS/VocaGlyph"])
+
+        XCTAssertTrue(sut.migrateLegacyLaunchAgents())
+        XCTAssertEqual(app.registerCalls, 0)
+        XCTAssertFalse(sut.isEnabled)
+    }
+}
@@ -0,0 +1,90 @@
+import XCTest
+@testable import VocaGlyph
+
+final class MetricsServiceTests: XCTestCase {
+
+    private let trace = UUID()
+    private let start = Date(timeIntervalSince1970: 1_000)
+
+    private func span(_ stage: MetricsStage, seconds: TimeInterval) -> MetricsSpan {
+        MetricsSpan(trace: trace, stage: stage, start: start, end: start + seconds)
+    }
+
+    func testHistogramBucketsAndPercentiles() {
+        var histogram = Histogram(bounds: [1, 2, 5])
+        XCTAssertNil(histogram.percentile(0.5))
+        [0.5, 0.8, 1.5, 4, 9].forEach { histogram.record($0) }
+
+        XCTAssertEqual(histogram.counts, [2, 1, 1, 1])
+        XCTAssertEqual(histogram.count, 5)
+        XCTAssertEqual(histogram.max, 9)
+        XCTAssertEqual(histogram.mean ?? 0, 3.16, accuracy: 0.001)
+        XCTAssertEqual(histogram.percentile(0.5), 2)
+        XCTAssertEqual(histogram.percentile(0.2), 1)
+        XCTAssertEqual(histogram.percentile(0.99), 9)
+    }
+
+    func testStagesAndQueueDepthAreRecorded() {
+        let metrics = MetricsService()
+        metrics.beginTranscription()
+        metrics.beginTranscription()
+        XCTAssertEqual(metrics.snapshot().queueDepth, 2)
+
+        metrics.record(span(.queue, seconds: 0.05))
+        metrics.recordDecode(span(.decode, seconds: 1), audioSeconds: 4)
+        metrics.endTranscription()
+
+        let snapshot = metrics.snapshot()
+        XCTAssertEqual(snapshot.queueDepth, 1)
+        XCTAssertEqual(snapshot.queueDepthSeen.counts, [1, 1, 0, 0, 0])
+        XCTAssertEqual(snapshot.transcriptions, 1)
+        XCTAssertEqual(snapshot.latency(.queue)?.count, 1)
+        XCTAssertEqual(snapshot.latency(.decode)?.sum, 1)
+        XCTAssertEqual(snapshot.realTimeFactor.sum, 0.25)
+        XCTAssertNil(snapshot.latency(.output))
+    }
+
+    func testSlowWarningNeedsSeveralSlowDictations() {
+        let metrics = MetricsService()
+        metrics.recordDecode(span(.decode, seconds: 6), audioSeconds: 4)
+        metrics.recordDecode(span(.decode, seconds: 6), audioSeconds: 4)
+        XCTAssertNil(metrics.slowTranscriptionFactor)
+
+        metrics.recordDecode(span(.decode, seconds: 1), audioSeconds: 4)
+        metrics.recordDecode(span(.decode, seconds: 8), audioSeconds: 4)
+        XCTAssertEqual(metrics.slowTranscriptionFactor, 1.5)
+
+        metrics.modelDidChange()
+        XCTAssertNil(metrics.slowTranscriptionFactor)
+        XCTAssertEqual(metrics.snapshot().realTimeFactor.count, 4)
+    }
+
+    func testFastDictationsDoNotWarn() {
+        XCTAssertNil(MetricsService.slowFactor([0.2, 0.3, 0.9]))
+        XCTAssertEqual(MetricsService.slowFactor([0.5, 1.2, 1.4]), 1.2)
+    }
+
+    func testSpansReachTheObserver() {
+        let metrics = MetricsService()
+        var spans: [MetricsSpan] = []
+        metrics.observeSpans { spans.append($0) }
+        metrics.record(span(.queue, seconds: 0.1))
+        metrics.observeSpans(nil)
+        metrics.record(span(.output, seconds: 0.1))
+        XCTAssertEqual(spans, [span(.queue, seconds: 0.1)])
+    }
+
+    func testPublishesOnlyAfterChanges() {
+        let metrics = MetricsService()
+        let center = NotificationCenter()
+        var events: [MetricsUpdatedEvent] = []
+        let token = MetricsUpdatedEvent.observe(center: center, queue: nil) { events.append($0) }
+        defer { center.removeObserver(token) }
+
+        XCTAssertFalse(metrics.publishIfChanged(to: center))
+        metrics.record(span(.total, seconds: 1.2))
+        XCTAssertTrue(metrics.publishIfChanged(to: center))
+        XCTAssertFalse(metrics.publishIfChanged(to: center))
+        XCTAssertEqual(events.map { $0.snapshot.latency(.total)?.count }, [1])
+    }
+}
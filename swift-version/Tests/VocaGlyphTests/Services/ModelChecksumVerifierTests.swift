@@ -0,0 +1,107 @@
+import XCTest
+@testable import VocaGlyph
+
+final class ModelChecksumVerifierTests: XCTestCase {
+
+    var folder: URL!
+
+    let payload = Data("0123456789".utf8)
+    let payloadSHA256 = "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882"
+
+    override func setUpWithError() throws {
+        folder = FileManager.default.temporaryDirectory
+            .appendingPathComponent("ModelChecksumVerifierTests-\(UUID().uuidString)", isDirectory: true)
+        try FileManager.default.createDirectory(at: folder.appendingPathComponent("AudioEncoder.mlmodelc"),
+                                                withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: folder)
+    }
+
+    private func writeModel(_ data: Data, verifiedAt: Date? = nil) throws {
+        try data.write(to: folder.appendingPathComponent("AudioEncoder.mlmodelc/weight.bin"))
+        let entry = ModelManifest.Entry(path: "AudioEncoder.mlmodelc/weight.bin",
+                                        size: Int64(payload.count),
+                                        sha256: payloadSHA256,
+                                        verifiedAt: verifiedAt)
+        try ModelManifest(entries: [entry]).write(to: folder)
+    }
+
+    func testSHA256MatchesKnownDigest() throws {
+        let url = folder.appendingPathComponent("digest.bin")
+        try payload.write(to: url)
+        XCTAssertEqual(try ModelChecksumVerifier.sha256(of: url), payloadSHA256)
+    }
+
+    func testIntactModelIsValidAndRecordsVerification() throws {
+        try writeModel(payload)
+
+        XCTAssertEqual(ModelChecksumVerifier.verify(modelFolder: folder), .valid)
+        XCTAssertNotNil(ModelManifest.load(from: folder)?.entries.first?.verifiedAt)
+    }
+
+    func testDamagedFileIsReportedCorrupt() throws {
+        // Same size, different bytes — only the checksum can catch this.
+        try writeModel(Data("0123456780".utf8))
+
+        XCTAssertEqual(ModelChecksumVerifier.verify(modelFolder: folder),
+                       .corrupt(["AudioEncoder.mlmodelc/weight.bin"]))
+    }
+
+    func testTruncatedOrMissingFileIsReportedCorrupt() throws {
+        try writeModel(payload.prefix(5))
+        XCTAssertEqual(ModelChecksumVerifier.verify(modelFolder: folder),
+                       .corrupt(["AudioEncoder.mlmodelc/weight.bin"]))
+
+        try FileManager.default.removeItem(at: folder.appendingPathComponent("AudioEncoder.mlmodelc/weight.bin"))
+        XCTAssertEqual(ModelChecksumVerifier.verify(modelFolder: folder),
+                       .corrupt(["AudioEncoder.mlmodelc/weight.bin"]))
+    }
+
+    func testFileUnchangedSinceVerificationIsNotHashedAgain() throws {
+        // Wrong contents, but verified after its last modification — only the size is checked.
+        try writeModel(Data("0123456780".utf8), verifiedAt: Date().addingTimeInterval(60))
+
+        XCTAssertEqual(ModelChecksumVerifier.verify(modelFolder: folder), .valid)
+    }
+
+    func testModelWithoutManifestIsUnverified() {
+        XCTAssertEqual(ModelChecksumVerifier.verify(modelFolder: folder), .unverified)
+    }
+
+    // MARK: - Structural check
+
+    private func writeComponents(weightBytes: Int) throws {
+        for component in ModelRegistry.requiredComponents {
+            let bundle = folder.appendingPathComponent(component)
+            try FileManager.default.createDirectory(at: bundle.appendingPathComponent("weights"), withIntermediateDirectories: true)
+            try Data("coreml".utf8).write(to: bundle.appendingPathComponent("coremldata.bin"))
+            try Data(count: weightBytes).write(to: bundle.appendingPathComponent("weights/weight.bin"))
+        }
+    }
+
+    func testSoundLayoutHasNoStructuralProblems() throws {
+        try writeComponents(weightBytes: Int(ModelChecksumVerifier.minimumWeightBytes))
+        XCTAssertEqual(ModelChecksumVerifier.structuralProblems(modelFolder: folder), [])
+    }
+
+    func testTruncatedWeightsAndMissingBundleDataAreReported() throws {
+        try writeComponents(weightBytes: Int(ModelChecksumVerifier.minimumWeightBytes))
+        try Data(count: 10).write(to: folder.appendingPathComponent("TextDecoder.mlmodelc/weights/weight.bin"))
+        try FileManager.default.removeItem(at: folder.appendingPathComponent("MelSpectrogram.mlmodelc/coremldata.bin"))
+
+        XCTAssertEqual(ModelChecksumVerifier.structuralProblems(modelFolder: folder),
+                       ["MelSpectrogram.mlmodelc/coremldata.bin", "TextDecoder.mlmodelc/weights/weight.bin"])
+    }
+
+    func testGGMLWeightsAreReported() throws {
+        try writeComponents(weightBytes: Int(ModelChecksumVerifier.minimumWeightBytes))
+        var ggml = Data("lmgg".utf8)
+        ggml.append(Data(count: Int(ModelChecksumVerifier.minimumWeightBytes)))
+        try ggml.write(to: folder.appendingPathComponent("AudioEncoder.mlmodelc/weights/weight.bin"))
+
+        XCTAssertEqual(ModelChecksumVerifier.structuralProblems(modelFolder: folder),
+                       ["AudioEncoder.mlmodelc/weights/weight.bin"])
+    }
+}
@@ -0,0 +1,348 @@
+import XCTest
+@testable import VocaGlyph
+
+final class ModelDownloadServiceTests: XCTestCase {
+
+    var session: URLSession!
+    var destination: URL!
+    var service: ModelDownloadService!
+
+    let repo = "argmaxinc/whisperkit-coreml"
+    let folder = "openai_whisper-tiny"
+    let payload = Data("0123456789".utf8)
+
+    override func setUp() async throws {
+        let configuration = URLSessionConfiguration.ephemeral
+        configuration.protocolClasses = [MockURLProtocol.self]
+        session = URLSession(configuration: configuration)
+        destination = FileManager.default.temporaryDirectory
+            .appendingPathComponent("ModelDownloadServiceTests-\(UUID().uuidString)", isDirectory: true)
+        service = ModelDownloadService(session: session, retryBaseDelay: 0)
+    }
+
+    override func tearDown() async throws {
+        MockURLProtocol.requestHandler = nil
+        try? FileManager.default.removeItem(at: destination)
+    }
+
+    private var target: URL {
+        destination.appendingPathComponent("\(folder)/AudioEncoder.mlmodelc/weight.bin")
+    }
+
+    /// Serves a one-file listing and hands file requests to `fileHandler`.
+    private func serve(sha256: String? = nil, gitSHA1: String? = nil, file fileHandler: @escaping (URLRequest) -> (Int, Data)) {
+        let oid = gitSHA1.map { #","oid":"\#($0)""# } ?? ""
+        let lfs = sha256.map { #","lfs":{"oid":"\#($0)","size":\#(payload.count)}"# } ?? ""
+        let listing = """
+        [{"type":"directory","path":"\(folder)/AudioEncoder.mlmodelc"},
+         {"type":"file","path":"\(folder)/AudioEncoder.mlmodelc/weight.bin","size":\(payload.count)\(oid)\(lfs)}]
+        """
+        MockURLProtocol.requestHandler = { request in
+            let url = request.url!
+            if url.path.contains("/api/models/") {
+                let response = HTTPURLResponse(url: url, statusCode: 200, httpVersion: nil, headerFields: nil)!
+                return (response, Data(listing.utf8))
+            }
+            let (status, body) = fileHandler(request)
+            let response = HTTPURLResponse(url: url, statusCode: status, httpVersion: nil, headerFields: nil)!
+            return (response, body)
+        }
+    }
+
+    private func writePartial(_ data: Data) throws {
+        try FileManager.default.createDirectory(at: target.deletingLastPathComponent(), withIntermediateDirectories: true)
+        try data.write(to: target.appendingPathExtension("download"))
+    }
+
+    func testFreshDownloadWritesFileAndClearsMarker() async throws {
+        serve { request in
+            XCTAssertNil(request.value(forHTTPHeaderField: "Range"))
+            return (200, self.payload)
+        }
+        var reports: [ModelDownloadProgress] = []
+
+        try await service.runDownload(repo: repo, folder: folder, destination: destination) { reports.append($0) }
+
+        XCTAssertEqual(try Data(contentsOf: target), payload)
+        XCTAssertFalse(FileManager.default.fileExists(atPath: target.appendingPathExtension("download").path))
+        XCTAssertFalse(ModelDownloadService.isIncomplete(destination.appendingPathComponent(folder)))
+        XCTAssertNil(reports.first?.resumedFromByte)
+        XCTAssertEqual(reports.last?.fractionCompleted, 1)
+    }
+
+    func testResumesPartialFileWithRangeRequest() async throws {
+        try writePartial(payload.prefix(4))
+        serve { request in
+            XCTAssertEqual(request.value(forHTTPHeaderField: "Range"), "bytes=4-")
+            return (206, self.payload.suffix(from: 4))
+        }
+        var reports: [ModelDownloadProgress] = []
+
+        try await service.runDownload(repo: repo, folder: folder, destination: destination) { reports.append($0) }
+
+        XCTAssertEqual(try Data(contentsOf: target), payload)
+        XCTAssertEqual(reports.first?.resumedFromByte, 4)
+        XCTAssertEqual(reports.first?.bytesReceived, 4)
+    }
+
+    func testRestartsFileWhenServerIgnoresRange() async throws {
+        try writePartial(Data("0123".utf8))
+        serve { _ in (200, self.payload) }
+
+        try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+
+        XCTAssertEqual(try Data(contentsOf: target), payload, "A 200 response must replace, not append to, the partial file")
+    }
+
+    func testKeepsPartialFileWhenDownloadFails() async throws {
+        try writePartial(payload.prefix(4))
+        serve { _ in (503, Data()) }
+
+        do {
+            try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+            XCTFail("Expected httpError")
+        } catch let error as ModelDownloadError {
+            XCTAssertEqual(error, .httpError(statusCode: 503, path: "\(folder)/AudioEncoder.mlmodelc/weight.bin"))
+        }
+
+        XCTAssertEqual(try Data(contentsOf: target.appendingPathExtension("download")), payload.prefix(4))
+        XCTAssertTrue(ModelDownloadService.isIncomplete(destination.appendingPathComponent(folder)))
+    }
+
+    func testSkipsFilesAlreadyComplete() async throws {
+        try FileManager.default.createDirectory(at: target.deletingLastPathComponent(), withIntermediateDirectories: true)
+        try payload.write(to: target)
+        serve { _ in
+            XCTFail("A complete file must not be requested again")
+            return (500, Data())
+        }
+
+        try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+
+        XCTAssertEqual(try Data(contentsOf: target), payload)
+    }
+
+    func testFailsFastWhenDiskSpaceIsInsufficient() async throws {
+        try writePartial(payload.prefix(4))
+        service = ModelDownloadService(session: session, retryBaseDelay: 0, availableCapacity: { _ in 5 })
+        serve { _ in
+            XCTFail("No file should be requested without enough space")
+            return (500, Data())
+        }
+
+        do {
+            try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+            XCTFail("Expected insufficientDiskSpace")
+        } catch let error as ModelDownloadError {
+            // Only the 6 bytes not yet on disk are required.
+            XCTAssertEqual(error, .insufficientDiskSpace(required: 6, available: 5))
+            XCTAssertTrue(error.localizedDescription.hasPrefix("Not enough disk space: need"))
+        }
+    }
+
+    // MARK: - Checksums
+
+    let payloadSHA256 = "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882"
+
+    func testWritesVerifiedManifestAfterDownload() async throws {
+        serve(sha256: payloadSHA256) { _ in (200, self.payload) }
+
+        try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+
+        let manifest = try XCTUnwrap(ModelManifest.load(from: destination.appendingPathComponent(folder)))
+        XCTAssertEqual(manifest.entries.count, 1)
+        XCTAssertEqual(manifest.entries.first?.path, "AudioEncoder.mlmodelc/weight.bin")
+        XCTAssertEqual(manifest.entries.first?.sha256, payloadSHA256)
+        XCTAssertNotNil(manifest.entries.first?.verifiedAt)
+    }
+
+    func testChecksumMismatchDiscardsFile() async throws {
+        serve(sha256: String(repeating: "0", count: 64)) { _ in (200, self.payload) }
+
+        do {
+            try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+            XCTFail("Expected checksumMismatch")
+        } catch let error as ModelDownloadError {
+            XCTAssertEqual(error, .checksumMismatch(path: "\(folder)/AudioEncoder.mlmodelc/weight.bin"))
+        }
+
+        XCTAssertFalse(FileManager.default.fileExists(atPath: target.path))
+        XCTAssertFalse(FileManager.default.fileExists(atPath: target.appendingPathExtension("download").path))
+    }
+
+    func testNonLFSFileIsCheckedAgainstItsGitBlobHash() async throws {
+        // `git hash-object` of "0123456789".
+        serve(gitSHA1: "ad471007bd7f5983d273b9584e5629230150fd54") { _ in (200, Data("9876543210".utf8)) }
+
+        do {
+            try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+            XCTFail("Expected checksumMismatch")
+        } catch let error as ModelDownloadError {
+            XCTAssertEqual(error, .checksumMismatch(path: "\(folder)/AudioEncoder.mlmodelc/weight.bin"))
+        }
+        XCTAssertFalse(FileManager.default.fileExists(atPath: target.path))
+
+        serve(gitSHA1: "ad471007bd7f5983d273b9584e5629230150fd54") { _ in (200, self.payload) }
+        try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+        XCTAssertEqual(try Data(contentsOf: target), payload)
+    }
+
+    // MARK: - Mirrors
+
+    func testListingIsNeverTakenFromAMirror() async throws {
+        service = ModelDownloadService(session: session,
+                                       mirrors: [URL(string: "https://primary.test")!, URL(string: "https://mirror.test")!],
+                                       retryBaseDelay: 0)
+        var mirrorListings = 0
+        MockURLProtocol.requestHandler = { request in
+            let url = request.url!
+            if url.host == "mirror.test", url.path.contains("/api/models/") { mirrorListings += 1 }
+            let status = url.host == "primary.test" ? 503 : 200
+            let response = HTTPURLResponse(url: url, statusCode: status, httpVersion: nil, headerFields: nil)!
+            return (response, Data("[]".utf8))
+        }
+
+        do {
+            try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+            XCTFail("Expected the listing to fail with the primary down")
+        } catch let error as ModelDownloadError {
+            XCTAssertEqual(error, .listingFailed(statusCode: 503))
+        }
+        XCTAssertEqual(mirrorListings, 0)
+    }
+
+    func testFailsOverToNextMirrorAndResumes() async throws {
+        service = ModelDownloadService(session: session,
+                                       mirrors: [URL(string: "https://primary.test")!, URL(string: "https://mirror.test")!],
+                                       retryBaseDelay: 0)
+        serve { request in
+            if request.url?.host == "primary.test" {
+                return (503, Data())
+            }
+            return (200, self.payload)
+        }
+        var reports: [ModelDownloadProgress] = []
+
+        try await service.runDownload(repo: repo, folder: folder, destination: destination) { reports.append($0) }
+
+        XCTAssertEqual(try Data(contentsOf: target), payload)
+        XCTAssertEqual(reports.first?.source, "primary.test")
+        XCTAssertEqual(reports.last?.source, "mirror.test")
+        XCTAssertEqual(reports.last?.fractionCompleted, 1)
+    }
+
+    func testOnlyRemoteFailuresTriggerFailover() {
+        XCTAssertTrue(ModelDownloadService.isMirrorFailure(ModelDownloadError.httpError(statusCode: 502, path: "x")))
+        XCTAssertTrue(ModelDownloadService.isMirrorFailure(URLError(.timedOut)))
+        XCTAssertFalse(ModelDownloadService.isMirrorFailure(URLError(.cancelled)))
+        XCTAssertFalse(ModelDownloadService.isMirrorFailure(ModelDownloadError.insufficientDiskSpace(required: 2, available: 1)))
+        XCTAssertFalse(ModelDownloadService.isMirrorFailure(CancellationError()))
+    }
+
+    // MARK: - Retries
+
+    func testRetriesTransientFailureAndResumes() async throws {
+        service = ModelDownloadService(session: session, mirrors: [URL(string: "https://primary.test")!], retryBaseDelay: 0)
+        var requests = 0
+        serve { request in
+            requests += 1
+            if requests == 1 {
+                // The connection drops after four bytes.
+                return (200, self.payload.prefix(4))
+            }
+            XCTAssertEqual(request.value(forHTTPHeaderField: "Range"), "bytes=4-")
+            return (206, self.payload.suffix(from: 4))
+        }
+
+        try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+
+        XCTAssertEqual(requests, 2)
+        XCTAssertEqual(try Data(contentsOf: target), payload)
+    }
+
+    func testGivesUpAfterMaxRetries() async throws {
+        service = ModelDownloadService(session: session, mirrors: [URL(string: "https://primary.test")!],
+                                       maxRetries: 2, retryBaseDelay: 0)
+        var requests = 0
+        serve { _ in
+            requests += 1
+            return (502, Data())
+        }
+
+        do {
+            try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+            XCTFail("Expected httpError")
+        } catch let error as ModelDownloadError {
+            XCTAssertEqual(error, .httpError(statusCode: 502, path: "\(folder)/AudioEncoder.mlmodelc/weight.bin"))
+        }
+        XCTAssertEqual(requests, 3, "One attempt plus two retries")
+    }
+
+    func testClientErrorsAreNotRetried() {
+        XCTAssertFalse(ModelDownloadService.isTransient(ModelDownloadError.httpError(statusCode: 404, path: "x")))
+        XCTAssertTrue(ModelDownloadService.isTransient(ModelDownloadError.httpError(statusCode: 429, path: "x")))
+        XCTAssertTrue(ModelDownloadService.isTransient(URLError(.networkConnectionLost)))
+        XCTAssertFalse(ModelDownloadService.isTransient(ModelDownloadError.checksumMismatch(path: "x")))
+    }
+
+    // MARK: - Access tokens
+
+    func testTokenIsSentOnlyToHuggingFace() async throws {
+        service = ModelDownloadService(session: session,
+                                       mirrors: [URL(string: "https://huggingface.co")!, URL(string: "https://mirror.test")!],
+                                       retryBaseDelay: 0, accessToken: { "hf_test" })
+        var authorizations: [String: String?] = [:]
+        serve { request in
+            authorizations[request.url!.host!] = request.value(forHTTPHeaderField: "Authorization")
+            return request.url?.host == "huggingface.co" ? (503, Data()) : (200, self.payload)
+        }
+
+        try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+
+        XCTAssertEqual(authorizations["huggingface.co"], "Bearer hf_test")
+        XCTAssertEqual(authorizations["mirror.test"], .some(nil), "The token must not leak to third-party mirrors")
+    }
+
+    func testGatedRepoWithoutAccessFailsWithoutRetrying() async throws {
+        var listings = 0
+        MockURLProtocol.requestHandler = { request in
+            listings += 1
+            let response = HTTPURLResponse(url: request.url!, statusCode: 401, httpVersion: nil, headerFields: nil)!
+            return (response, Data())
+        }
+
+        do {
+            try await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+            XCTFail("Expected accessDenied")
+        } catch let error as ModelDownloadError {
+            XCTAssertEqual(error, .accessDenied(repo: repo))
+        }
+        XCTAssertEqual(listings, 1, "Access errors are neither retried nor failed over")
+    }
+
+    // MARK: - Partial downloads
+
+    func testInterruptedDownloadIsFoundWithItsTotalSize() async throws {
+        try writePartial(payload.prefix(4))
+        serve { _ in (404, Data()) }
+        _ = try? await service.runDownload(repo: repo, folder: folder, destination: destination) { _ in }
+
+        let (partials, reclaimed) = ModelDownloadService.findPartialDownloads(under: destination)
+
+        XCTAssertEqual(partials.map(\.folder.lastPathComponent), [folder])
+        XCTAssertEqual(partials.first?.totalBytes, Int64(payload.count))
+        XCTAssertEqual(reclaimed, 0)
+        XCTAssertTrue(FileManager.default.fileExists(atPath: target.appendingPathExtension("download").path),
+                      "Temp files of a resumable download must be kept")
+    }
+
+    func testStrayTempFileInCompleteFolderIsDeleted() throws {
+        try writePartial(payload.prefix(4))
+
+        let (partials, reclaimed) = ModelDownloadService.findPartialDownloads(under: destination)
+
+        XCTAssertTrue(partials.isEmpty)
+        XCTAssertEqual(reclaimed, 4)
+        XCTAssertFalse(FileManager.default.fileExists(atPath: target.appendingPathExtension("download").path))
+    }
+}
@@ -0,0 +1,94 @@
+import XCTest
+@testable import VocaGlyph
+
+final class ModelRegistryTests: XCTestCase {
+
+    var root: URL!
+    var registry: ModelRegistry!
+
+    override func setUpWithError() throws {
+        root = FileManager.default.temporaryDirectory
+            .appendingPathComponent("ModelRegistryTests-\(UUID().uuidString)", isDirectory: true)
+        try FileManager.default.createDirectory(at: root, withIntermediateDirectories: true)
+        registry = ModelRegistry(dataRoot: root)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: root)
+    }
+
+    /// Creates a folder with the compiled CoreML bundles WhisperKit expects.
+    private func makeModelFolder(named name: String, components: [String] = ModelRegistry.requiredComponents) throws -> URL {
+        let folder = root.appendingPathComponent("source/\(name)", isDirectory: true)
+        for component in components {
+            let bundle = folder.appendingPathComponent(component, isDirectory: true)
+            try FileManager.default.createDirectory(at: bundle, withIntermediateDirectories: true)
+            try Data([0x01]).write(to: bundle.appendingPathComponent("coremldata.bin"))
+        }
+        return folder
+    }
+
+    func testImportCopiesFolderAndRegistersModel() throws {
+        let source = try makeModelFolder(named: "Whisper-Medical")
+
+        let model = try registry.importModel(from: source, title: "Medical")
+
+        XCTAssertEqual(model.id, "custom-whisper-medical")
+        XCTAssertNil(model.repo)
+        XCTAssertTrue(FileManager.default.fileExists(
+            atPath: registry.folderURL(for: model).appendingPathComponent("AudioEncoder.mlmodelc").path))
+
+        // Persisted to models.json
+        let reloaded = ModelRegistry(dataRoot: root)
+        XCTAssertEqual(reloaded.customModels, [model])
+    }
+
+    func testImportRejectsFolderMissingComponents() throws {
+        let source = try makeModelFolder(named: "partial", components: ["AudioEncoder.mlmodelc"])
+
+        XCTAssertThrowsError(try registry.importModel(from: source)) { error in
+            XCTAssertEqual(error as? ModelImportError,
+                           .missingComponents(["MelSpectrogram.mlmodelc", "TextDecoder.mlmodelc"]))
+        }
+        XCTAssertTrue(registry.customModels.isEmpty)
+    }
+
+    func testImportRejectsGGMLFileWithExplanation() throws {
+        let file = root.appendingPathComponent("ggml-base.en.bin")
+        // 0x67676d6c ("ggml") written little-endian, as whisper.cpp does.
+        try Data([0x6c, 0x6d, 0x67, 0x67, 0x00, 0x00]).write(to: file)
+
+        XCTAssertThrowsError(try registry.importModel(from: file)) { error in
+            XCTAssertEqual(error as? ModelImportError, .ggmlNotSupported)
+        }
+    }
+
+    func testAddRequiresCustomPrefixAndUniqueId() throws {
+        XCTAssertThrowsError(try registry.add(CustomModel(id: "large-v3", title: "Shadow", repo: "a/b", folder: "x"))) { error in
+            XCTAssertEqual(error as? ModelImportError, .invalidID("large-v3"))
+        }
+
+        let model = CustomModel(id: "custom-finetune", title: "Finetune", repo: "me/whisperkit-finetune", folder: "openai_whisper-finetune")
+        try registry.add(model)
+        XCTAssertThrowsError(try registry.add(model)) { error in
+            XCTAssertEqual(error as? ModelImportError, .duplicate("custom-finetune"))
+        }
+        XCTAssertEqual(registry.folderURL(for: model).path,
+                       root.appendingPathComponent("models/me/whisperkit-finetune/openai_whisper-finetune").path)
+    }
+
+    func testReloadSkipsInvalidEntries() throws {
+        let json = """
+        {"models": [
+          {"id": "custom-ok", "title": "OK", "folder": "ok"},
+          {"id": "medium", "title": "Shadows a built-in", "folder": "medium"},
+          {"id": "custom-ok", "title": "Duplicate", "folder": "dup"}
+        ]}
+        """
+        try Data(json.utf8).write(to: root.appendingPathComponent(ModelRegistry.fileName))
+
+        registry.reload()
+
+        XCTAssertEqual(registry.customModels.map(\.title), ["OK"])
+    }
+}
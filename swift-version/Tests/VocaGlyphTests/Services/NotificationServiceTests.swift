@@ -0,0 +1,25 @@
+import XCTest
+@testable import VocaGlyph
+
+final class NotificationServiceTests: XCTestCase {
+
+    func testClipboardFallbackTellsTheUserToPaste() {
+        XCTAssertEqual(AppNotification.copiedToClipboard.title, "Copied to clipboard")
+        XCTAssertTrue(AppNotification.copiedToClipboard.body.hasPrefix("Press ⌘V"))
+    }
+
+    func testBodiesNameTheSubject() {
+        XCTAssertTrue(AppNotification.hotkeyUnavailable(shortcut: "⌥Space").body.contains("⌥Space"))
+        XCTAssertEqual(AppNotification.modelDownloaded(model: "parakeet-v3").body, "'parakeet-v3' is ready to use.")
+        XCTAssertEqual(AppNotification.transcriptionFailed(message: "No audio").body, "No audio")
+        XCTAssertTrue(AppNotification.permissionsMissing(names: ["Microphone", "Accessibility"]).body
+            .contains("Microphone and Accessibility"))
+    }
+
+    func testRepeatsShareAnIdentifierPerKind() {
+        XCTAssertEqual(AppNotification.transcriptionFailed(message: "a").identifier,
+                       AppNotification.transcriptionFailed(message: "b").identifier)
+        XCTAssertNotEqual(AppNotification.modelDownloaded(model: "tiny").identifier,
+                          AppNotification.modelDownloaded(model: "base").identifier)
+    }
+}
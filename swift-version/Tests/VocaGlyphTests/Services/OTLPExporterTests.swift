@@ -0,0 +1,72 @@
+import XCTest
+@testable import VocaGlyph
+
+final class OTLPExporterTests: XCTestCase {
+
+    private let trace = UUID(uuidString: "0123ABCD-4567-89EF-0123-456789ABCDEF")!
+    private let start = Date(timeIntervalSince1970: 1_000)
+
+    private func span(_ stage: MetricsStage, from offset: TimeInterval, seconds: TimeInterval) -> MetricsSpan {
+        MetricsSpan(trace: trace, stage: stage, start: start + offset, end: start + offset + seconds)
+    }
+
+    func testIdsAndTimestampsAreOTLPShaped() {
+        XCTAssertEqual(OTLPExporter.hex(trace), "0123abcd456789ef0123456789abcdef")
+        XCTAssertEqual(OTLPExporter.spanId(trace, .queue), "0123abcd45678901")
+        XCTAssertEqual(OTLPExporter.spanId(trace, .queue).count, 16)
+        XCTAssertNotEqual(OTLPExporter.spanId(trace, .queue), OTLPExporter.spanId(trace, .total))
+        XCTAssertEqual(OTLPExporter.nanos(start), "1000000000000")
+    }
+
+    func testTracesPayloadHangsStagesOffTheTotalSpan() throws {
+        let payload = OTLPExporter.tracesPayload([
+            span(.queue, from: 0, seconds: 0.1),
+            span(.decode, from: 0.1, seconds: 0.5),
+            span(.total, from: 0, seconds: 0.7),
+        ])
+        let resourceSpans = try XCTUnwrap(payload["resourceSpans"] as? [[String: Any]])
+        let scopeSpans = try XCTUnwrap(resourceSpans.first?["scopeSpans"] as? [[String: Any]])
+        let spans = try XCTUnwrap(scopeSpans.first?["spans"] as? [[String: Any]])
+        XCTAssertEqual(spans.count, 3)
+
+        let root = try XCTUnwrap(spans.first { $0["name"] as? String == "dictation" })
+        XCTAssertNil(root["parentSpanId"])
+        XCTAssertEqual(root["endTimeUnixNano"] as? String, "1000700000000")
+        for child in spans where child["name"] as? String != "dictation" {
+            XCTAssertEqual(child["parentSpanId"] as? String, root["spanId"] as? String)
+            XCTAssertEqual(child["traceId"] as? String, OTLPExporter.hex(trace))
+        }
+        XCTAssertNoThrow(try JSONSerialization.data(withJSONObject: payload))
+    }
+
+    func testMetricsPayloadCarriesHistogramsAndQueueDepth() throws {
+        let metrics = MetricsService()
+        metrics.beginTranscription()
+        metrics.recordDecode(span(.decode, from: 0, seconds: 0.3), audioSeconds: 3)
+        let payload = OTLPExporter.metricsPayload(metrics.snapshot(), startedAt: start, now: start + 60)
+
+        let resourceMetrics = try XCTUnwrap(payload["resourceMetrics"] as? [[String: Any]])
+        let resource = try XCTUnwrap(resourceMetrics.first?["resource"] as? [String: Any])
+        let attributes = try XCTUnwrap(resource["attributes"] as? [[String: Any]])
+        XCTAssertTrue(attributes.contains { $0["key"] as? String == "service.name" })
+
+        let scopeMetrics = try XCTUnwrap(resourceMetrics.first?["scopeMetrics"] as? [[String: Any]])
+        let all = try XCTUnwrap(scopeMetrics.first?["metrics"] as? [[String: Any]])
+        func metric(_ name: String) throws -> [String: Any] {
+            try XCTUnwrap(all.first { $0["name"] as? String == name })
+        }
+
+        let latency = try XCTUnwrap(try metric("vocaglyph.dictation.latency")["histogram"] as? [String: Any])
+        XCTAssertEqual(latency["aggregationTemporality"] as? Int, 2)
+        let points = try XCTUnwrap(latency["dataPoints"] as? [[String: Any]])
+        XCTAssertEqual(points.count, 1)
+        XCTAssertEqual(points[0]["count"] as? String, "1")
+        XCTAssertEqual((points[0]["bucketCounts"] as? [String])?.count,
+                       (points[0]["explicitBounds"] as? [Double]).map { $0.count + 1 })
+
+        let queue = try XCTUnwrap(try metric("vocaglyph.transcription.queue_depth")["gauge"] as? [String: Any])
+        let queuePoints = try XCTUnwrap(queue["dataPoints"] as? [[String: Any]])
+        XCTAssertEqual(queuePoints.first?["asInt"] as? String, "1")
+        XCTAssertNoThrow(try JSONSerialization.data(withJSONObject: payload))
+    }
+}
@@ -0,0 +1,131 @@
+import XCTest
+@testable import VocaGlyph
+
+// MARK: - Mock
+
+private final class MockOnboardingPerformer: OnboardingPerforming {
+    var isMicrophoneAuthorized = false
+    var isAccessibilityTrusted = false
+    var grantMicrophone = true
+    var downloadError: Error?
+    var transcript = "Hello from VocaGlyph"
+    private(set) var downloadedModel: String?
+
+    func requestMicrophoneAccess() async -> Bool {
+        isMicrophoneAuthorized = grantMicrophone
+        return grantMicrophone
+    }
+
+    func promptAccessibilityTrusted() -> Bool { isAccessibilityTrusted }
+
+    func downloadModel(_ model: String, progress: @escaping (Double) -> Void) async throws {
+        downloadedModel = model
+        progress(0.5)
+        if let downloadError { throw downloadError }
+        progress(1.0)
+    }
+
+    func runTestDictation(duration: TimeInterval) async throws -> String { transcript }
+}
+
+// MARK: - OnboardingCoordinatorTests
+
+final class OnboardingCoordinatorTests: XCTestCase {
+
+    private let suiteName = "OnboardingCoordinatorTests"
+    private var defaults: UserDefaults!
+    private var performer: MockOnboardingPerformer!
+
+    override func setUp() {
+        super.setUp()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+        performer = MockOnboardingPerformer()
+    }
+
+    override func tearDown() {
+        defaults.removePersistentDomain(forName: suiteName)
+        defaults = nil
+        performer = nil
+        super.tearDown()
+    }
+
+    private func makeSUT() -> OnboardingCoordinator {
+        OnboardingCoordinator(performer: performer, defaults: defaults, recommendedModel: "parakeet-v3")
+    }
+
+    func test_initialState_allPending_currentStepIsMicrophone() {
+        let sut = makeSUT()
+        XCTAssertEqual(sut.state.currentStep, .microphone)
+        XCTAssertFalse(sut.state.isComplete)
+    }
+
+    func test_requestMicrophone_granted_completesAndPersists() async {
+        let sut = makeSUT()
+        await sut.requestMicrophone()
+
+        XCTAssertEqual(sut.state.statuses[.microphone], .completed)
+        XCTAssertEqual(defaults.stringArray(forKey: OnboardingCoordinator.completedStepsKey), ["microphone"])
+    }
+
+    func test_requestMicrophone_denied_fails() async {
+        performer.grantMicrophone = false
+        let sut = makeSUT()
+        await sut.requestMicrophone()
+
+        guard case .failed = sut.state.statuses[.microphone] else {
+            return XCTFail("Expected failure, got \(String(describing: sut.state.statuses[.microphone]))")
+        }
+    }
+
+    func test_refresh_revokedPermission_movesStepBackToPending() {
+        performer.isAccessibilityTrusted = true
+        let sut = makeSUT()
+        XCTAssertEqual(sut.state.statuses[.accessibility], .completed)
+
+        performer.isAccessibilityTrusted = false
+        sut.refresh()
+
+        XCTAssertEqual(sut.state.statuses[.accessibility], .pending)
+    }
+
+    func test_downloadRecommendedModel_usesRecommendation() async {
+        let sut = makeSUT()
+        await sut.downloadRecommendedModel()
+
+        XCTAssertEqual(performer.downloadedModel, "parakeet-v3")
+        XCTAssertEqual(sut.state.statuses[.modelDownload], .completed)
+    }
+
+    func test_downloadRecommendedModel_error_reportsFailure() async {
+        performer.downloadError = OnboardingError.downloadFailed("parakeet-v3")
+        let sut = makeSUT()
+        await sut.downloadRecommendedModel()
+
+        XCTAssertEqual(sut.state.statuses[.modelDownload], .failed(OnboardingError.downloadFailed("parakeet-v3").localizedDescription))
+    }
+
+    func test_runTestDictation_emptyTranscript_fails() async {
+        performer.transcript = "  "
+        let sut = makeSUT()
+        await sut.runTestDictation()
+
+        guard case .failed = sut.state.statuses[.testDictation] else {
+            return XCTFail("Expected failure")
+        }
+        XCTAssertNil(sut.state.testTranscript)
+    }
+
+    func test_completedSteps_restoredOnNextLaunch() async {
+        performer.isMicrophoneAuthorized = true
+        performer.isAccessibilityTrusted = true
+        let first = makeSUT()
+        await first.downloadRecommendedModel()
+        await first.runTestDictation()
+        XCTAssertTrue(first.state.isComplete)
+
+        let second = makeSUT()
+        XCTAssertTrue(second.state.isComplete)
+        XCTAssertEqual(first.state.testTranscript, "Hello from VocaGlyph")
+    }
+}
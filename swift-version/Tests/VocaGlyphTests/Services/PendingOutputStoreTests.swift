@@ -0,0 +1,48 @@
+import XCTest
+@testable import VocaGlyph
+
+final class PendingOutputStoreTests: XCTestCase {
+
+    private var root: URL!
+    private var store: PendingOutputStore!
+
+    override func setUpWithError() throws {
+        try super.setUpWithError()
+        root = FileManager.default.temporaryDirectory
+            .appendingPathComponent("PendingOutputStoreTests-\(UUID().uuidString)", isDirectory: true)
+        store = PendingOutputStore(dataRoot: root)
+    }
+
+    override func tearDown() {
+        try? FileManager.default.removeItem(at: root)
+        super.tearDown()
+    }
+
+    func testEntriesSurviveANewStoreUntilRemoved() throws {
+        let first = store.add("first")
+        store.add("second")
+
+        let relaunched = PendingOutputStore(dataRoot: root)
+        XCTAssertEqual(relaunched.pending.map(\.text), ["first", "second"])
+
+        relaunched.remove(first)
+        XCTAssertEqual(store.pending.map(\.text), ["second"])
+        let permissions = try FileManager.default.attributesOfItem(atPath: store.fileURL.path)[.posixPermissions] as? Int
+        XCTAssertEqual(permissions, 0o600)
+    }
+
+    func testRemovingTheLastEntryDeletesTheFile() {
+        let id = store.add("only")
+        store.remove(id)
+        XCTAssertFalse(FileManager.default.fileExists(atPath: store.fileURL.path))
+        XCTAssertTrue(store.pending.isEmpty)
+    }
+
+    func testUnreadableFileCountsAsEmpty() throws {
+        try FileManager.default.createDirectory(at: root, withIntermediateDirectories: true)
+        try Data("not json".utf8).write(to: store.fileURL)
+        XCTAssertTrue(store.pending.isEmpty)
+        store.add("fresh")
+        XCTAssertEqual(store.pending.map(\.text), ["fresh"])
+    }
+}
@@ -0,0 +1,82 @@
+import XCTest
+@testable import VocaGlyph
+
+final class PluginHostTests: XCTestCase {
+
+    private var directory: URL!
+
+    override func setUpWithError() throws {
+        directory = FileManager.default.temporaryDirectory
+            .appendingPathComponent("PluginHostTests-\(UUID().uuidString)", isDirectory: true)
+        try FileManager.default.createDirectory(at: directory, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: directory)
+    }
+
+    private func install(_ folder: String, _ json: String) throws {
+        let url = directory.appendingPathComponent(folder, isDirectory: true)
+        try FileManager.default.createDirectory(at: url, withIntermediateDirectories: true)
+        try json.write(to: url.appendingPathComponent(PluginManifest.fileName), atomically: true, encoding: .utf8)
+    }
+
+    private func plugin(_ command: String, arguments: [String]? = nil, timeout: Double? = nil) -> PluginProcess {
+        PluginProcess(manifest: PluginManifest(name: "test", kinds: [.processor], command: command,
+                                               arguments: arguments, timeoutSeconds: timeout),
+                      folder: directory)
+    }
+
+    func testDiscoverSortsByNameAndReportsBadManifests() throws {
+        try install("b", #"{"name": "beta", "kinds": ["sink"], "command": "run"}"#)
+        try install("a", #"{"name": "alpha", "kinds": ["processor", "sink"], "command": "/bin/cat"}"#)
+        try install("off", #"{"name": "off", "kinds": ["sink"], "command": "run", "enabled": false}"#)
+        try install("broken", #"{"name": "broken"}"#)
+        try install("twin", #"{"name": "beta", "kinds": ["sink"], "command": "run"}"#)
+        try FileManager.default.createDirectory(at: directory.appendingPathComponent("empty"),
+                                                withIntermediateDirectories: true)
+
+        let found = PluginHost.discover(in: directory)
+        XCTAssertEqual(found.plugins.map(\.manifest.name), ["alpha", "beta"])
+        XCTAssertEqual(found.problems.count, 2)
+    }
+
+    func testEchoPluginReturnsTheText() async throws {
+        // `cat` echoes the request, whose "text" is read back as the rewrite.
+        let cat = plugin("/bin/cat")
+        defer { cat.stop() }
+        let result = try await cat.process("hello world")
+        XCTAssertEqual(result, "hello world")
+    }
+
+    func testFailingPluginKeepsTheInput() async {
+        let processor = PluginTextProcessor(plugins: [plugin("/usr/bin/false")])
+        let result = try? await processor.process("hello")
+        XCTAssertEqual(result, "hello")
+    }
+
+    func testSilentPluginTimesOut() async {
+        let sleeper = plugin("/bin/sleep", arguments: ["30"], timeout: 0.2)
+        defer { sleeper.stop() }
+        do {
+            _ = try await sleeper.process("hello")
+            XCTFail("expected a timeout")
+        } catch {
+            XCTAssertEqual(error as? PluginError, .timedOut("test"))
+        }
+    }
+
+    func testRepeatedFailuresDisableThePlugin() async {
+        let failing = plugin("/usr/bin/false")
+        for _ in 0..<PluginProcess.maxConsecutiveFailures {
+            _ = try? await failing.process("hello")
+        }
+        XCTAssertTrue(failing.isDisabled)
+        do {
+            _ = try await failing.process("hello")
+            XCTFail("expected the plugin to be disabled")
+        } catch {
+            XCTAssertEqual(error as? PluginError, .disabled("test"))
+        }
+    }
+}
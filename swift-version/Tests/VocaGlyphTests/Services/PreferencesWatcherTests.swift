@@ -0,0 +1,83 @@
+import XCTest
+@testable import VocaGlyph
+
+// MARK: - PreferencesWatcherTests
+
+final class PreferencesWatcherTests: XCTestCase {
+
+    private let suiteName = "PreferencesWatcherTests"
+    private var defaults: UserDefaults!
+
+    override func setUp() {
+        super.setUp()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+    }
+
+    override func tearDown() {
+        defaults.removePersistentDomain(forName: suiteName)
+        defaults = nil
+        super.tearDown()
+    }
+
+    // MARK: - Change delivery
+
+    func test_watchedKeyChange_invokesOnChangeWithKey() {
+        let sut = PreferencesWatcher(defaults: defaults, keys: ["dictationLanguage"], debounceInterval: 0.05)
+        let expectation = expectation(description: "onChange")
+        sut.onChange = { keys in
+            XCTAssertEqual(keys, ["dictationLanguage"])
+            expectation.fulfill()
+        }
+        sut.start()
+
+        defaults.set("French (FR)", forKey: "dictationLanguage")
+
+        wait(for: [expectation], timeout: 1.0)
+        sut.stop()
+    }
+
+    func test_burstOfWrites_isCoalescedIntoSingleCallback() {
+        let sut = PreferencesWatcher(defaults: defaults, keys: ["selectedModel", "dictationLanguage"], debounceInterval: 0.1)
+        let expectation = expectation(description: "onChange")
+        var callCount = 0
+        sut.onChange = { keys in
+            callCount += 1
+            XCTAssertEqual(keys, ["selectedModel", "dictationLanguage"])
+            expectation.fulfill()
+        }
+        sut.start()
+
+        defaults.set("tiny", forKey: "selectedModel")
+        defaults.set("German (DE)", forKey: "dictationLanguage")
+
+        wait(for: [expectation], timeout: 1.0)
+        RunLoop.main.run(until: Date().addingTimeInterval(0.2))
+        XCTAssertEqual(callCount, 1)
+        sut.stop()
+    }
+
+    func test_unwatchedKey_doesNotInvokeOnChange() {
+        let sut = PreferencesWatcher(defaults: defaults, keys: ["selectedModel"], debounceInterval: 0.05)
+        let expectation = expectation(description: "onChange")
+        expectation.isInverted = true
+        sut.onChange = { _ in expectation.fulfill() }
+        sut.start()
+
+        defaults.set(true, forKey: "privacyModeEnabled")
+
+        wait(for: [expectation], timeout: 0.3)
+        sut.stop()
+    }
+
+    func test_change_postsConfigReloadedNotification() {
+        let sut = PreferencesWatcher(defaults: defaults, keys: ["selectedModel"], debounceInterval: 0.05)
+        let expectation = expectation(forNotification: .configReloaded, object: nil)
+        sut.start()
+
+        defaults.set("base", forKey: "selectedModel")
+
+        wait(for: [expectation], timeout: 1.0)
+        sut.stop()
+    }
+}
@@ -0,0 +1,87 @@
+import XCTest
+import AVFoundation
+@testable import VocaGlyph
+
+final class ProcessingWatchdogTests: XCTestCase {
+
+    func testFiresWhenProcessingOutlastsTheLimit() {
+        let stuck = expectation(description: "stuck")
+        let watchdog = ProcessingWatchdog(timeout: { 30 }, secondLength: 0.001) { seconds in
+            XCTAssertEqual(seconds, 30)
+            stuck.fulfill()
+        }
+
+        watchdog.stateDidChange(.recording)
+        XCTAssertFalse(watchdog.isArmed)
+        watchdog.stateDidChange(.processing)
+        XCTAssertTrue(watchdog.isArmed)
+
+        wait(for: [stuck], timeout: 2)
+        XCTAssertFalse(watchdog.isArmed)
+    }
+
+    func testLeavingProcessingDisarms() {
+        let stuck = expectation(description: "stuck")
+        stuck.isInverted = true
+        let watchdog = ProcessingWatchdog(timeout: { 30 }, secondLength: 0.001) { _ in stuck.fulfill() }
+
+        watchdog.stateDidChange(.processing)
+        watchdog.stateDidChange(.idle)
+
+        XCTAssertFalse(watchdog.isArmed)
+        wait(for: [stuck], timeout: 0.1)
+    }
+
+    func testZeroTurnsTheWatchdogOff() {
+        let watchdog = ProcessingWatchdog(timeout: { 0 }) { _ in XCTFail("watchdog is off") }
+        watchdog.stateDidChange(.processing)
+        XCTAssertFalse(watchdog.isArmed)
+    }
+
+    func testLimitGrowsWithTheAudioBeingTranscribed() {
+        let stuck = expectation(description: "stuck")
+        let watchdog = ProcessingWatchdog(timeout: { 30 }, secondLength: 0.001) { seconds in
+            XCTAssertEqual(seconds, 30 + Int(300 * ProcessingWatchdog.secondsPerAudioSecond))
+            stuck.fulfill()
+        }
+
+        watchdog.stateDidChange(.processing)
+        watchdog.noteProgress(audioSeconds: 300)
+        wait(for: [stuck], timeout: 5)
+    }
+
+    func testProgressRestartsTheClock() {
+        let stuck = expectation(description: "stuck")
+        stuck.isInverted = true
+        let watchdog = ProcessingWatchdog(timeout: { 30 }, secondLength: 0.01) { _ in stuck.fulfill() }
+
+        watchdog.stateDidChange(.processing)
+        // Each stage takes 0.2 s of the 0.3 s limit; only the total is over it.
+        for _ in 0..<3 {
+            RunLoop.main.run(until: Date().addingTimeInterval(0.2))
+            watchdog.noteProgress()
+        }
+        XCTAssertTrue(watchdog.isArmed)
+        wait(for: [stuck], timeout: 0.1)
+        watchdog.stateDidChange(.idle)
+    }
+
+    func testProgressOutsideProcessingDoesNotArm() {
+        let watchdog = ProcessingWatchdog(timeout: { 30 }) { _ in XCTFail("not processing") }
+        watchdog.noteProgress(audioSeconds: 10)
+        XCTAssertFalse(watchdog.isArmed)
+    }
+
+    func testAbandonedAudioIsSavedAsReadableWAV() throws {
+        let directory = FileManager.default.temporaryDirectory
+            .appendingPathComponent("ProcessingWatchdogTests-\(UUID().uuidString)", isDirectory: true)
+        defer { try? FileManager.default.removeItem(at: directory) }
+        let buffer = try XCTUnwrap(AVAudioPCMBuffer(pcmFormat: TranscriptionServer.engineFormat, frameCapacity: 16_000))
+        buffer.frameLength = 16_000
+
+        let url = try XCTUnwrap(AppStateManager.saveRecoveredAudio(buffer, to: directory))
+
+        XCTAssertEqual(url.deletingLastPathComponent().standardizedFileURL, directory.standardizedFileURL)
+        XCTAssertEqual(try TranscriptionServer.wavBuffer(Data(contentsOf: url)).frameLength, 16_000)
+    }
+}
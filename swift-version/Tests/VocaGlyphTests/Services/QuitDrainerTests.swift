@@ -0,0 +1,41 @@
+import XCTest
+@testable import VocaGlyph
+
+final class QuitDrainerTests: XCTestCase {
+
+    func testFinishesWhenTheAppComesToRest() {
+        let drainer = QuitDrainer(timeout: 20)
+        var outcomes: [QuitDrainer.Outcome] = []
+        drainer.begin(onProgress: { _ in }, completion: { outcomes.append($0) })
+
+        drainer.stateDidChange(.processing)
+        XCTAssertTrue(drainer.isDraining)
+        drainer.stateDidChange(.idle)
+        drainer.stateDidChange(.idle)
+
+        XCTAssertEqual(outcomes, [.finished])
+        XCTAssertFalse(drainer.isDraining)
+    }
+
+    func testTimesOutAndCountsDown() {
+        let drainer = QuitDrainer(timeout: 2, tickInterval: 0.01)
+        var progress: [Int] = []
+        let done = expectation(description: "timed out")
+        drainer.begin(onProgress: { progress.append($0) }, completion: { outcome in
+            XCTAssertEqual(outcome, .timedOut)
+            done.fulfill()
+        })
+
+        wait(for: [done], timeout: 1)
+        XCTAssertEqual(progress, [2, 1, 0])
+    }
+
+    func testCancelledDrainerIgnoresStateChanges() {
+        let drainer = QuitDrainer(timeout: 20)
+        var called = false
+        drainer.begin(onProgress: { _ in }, completion: { _ in called = true })
+        drainer.cancel()
+        drainer.stateDidChange(.idle)
+        XCTAssertFalse(called)
+    }
+}
@@ -0,0 +1,89 @@
+import XCTest
+@testable import VocaGlyph
+
+final class RegexRulesServiceTests: XCTestCase {
+
+    var root: URL!
+
+    override func setUpWithError() throws {
+        root = FileManager.default.temporaryDirectory
+            .appendingPathComponent("RegexRulesServiceTests-\(UUID().uuidString)", isDirectory: true)
+        try FileManager.default.createDirectory(at: root, withIntermediateDirectories: true)
+    }
+
+    override func tearDownWithError() throws {
+        try? FileManager.default.removeItem(at: root)
+    }
+
+    private func writeRules(_ json: String) throws {
+        try Data(json.utf8).write(to: root.appendingPathComponent(RegexRulesService.fileName))
+    }
+
+    private func apply(_ service: RegexRulesService, to text: String) async throws -> String {
+        try await RegexRuleProcessor(rules: service.compiledRules).process(text)
+    }
+
+    func testMissingFileMeansNoRules() {
+        let service = RegexRulesService(dataRoot: root)
+        XCTAssertTrue(service.rules.isEmpty)
+        XCTAssertTrue(service.compiledRules.isEmpty)
+        XCTAssertTrue(service.problems.isEmpty)
+    }
+
+    func testRulesRunInOrderWithCaptureGroups() async throws {
+        try writeRules(#"""
+        { "rules": [
+            { "pattern": "(\\d+) percent", "replacement": "$1%" },
+            { "pattern": "(\\d+)%", "replacement": "[$1%]" },
+            { "pattern": "Get Hub", "replacement": "GitHub" }
+        ] }
+        """#)
+        let service = RegexRulesService(dataRoot: root)
+
+        let result = try await apply(service, to: "50 percent of get hub")
+        XCTAssertEqual(result, "[50%] of GitHub")
+        XCTAssertEqual(service.activeRuleCount, 3)
+    }
+
+    func testInvalidAndDisabledRulesAreSkipped() async throws {
+        try writeRules(#"""
+        { "rules": [
+            { "pattern": "(unclosed", "replacement": "x" },
+            { "pattern": "cat", "replacement": "dog", "enabled": false },
+            { "pattern": "Bird", "replacement": "fish", "caseInsensitive": false }
+        ] }
+        """#)
+        let service = RegexRulesService(dataRoot: root)
+
+        let result = try await apply(service, to: "cat bird Bird")
+        XCTAssertEqual(result, "cat bird fish")
+        XCTAssertEqual(service.activeRuleCount, 1)
+        XCTAssertEqual(service.problems.count, 1)
+        XCTAssertTrue(service.problems[0].hasPrefix("Rule 1"))
+    }
+
+    func testReloadPicksUpEditsAndKeepsRulesOnParseError() async throws {
+        try writeRules(#"{ "rules": [ { "pattern": "a", "replacement": "b" } ] }"#)
+        let service = RegexRulesService(dataRoot: root)
+
+        try writeRules(#"{ "rules": [ { "pattern": "a", "replacement": "c" } ] }"#)
+        service.reload()
+        let edited = try await apply(service, to: "a")
+        XCTAssertEqual(edited, "c")
+
+        try writeRules(#"{ "rules": [ { "pattern": "#)
+        service.reload()
+        let afterBrokenSave = try await apply(service, to: "a")
+        XCTAssertEqual(afterBrokenSave, "c", "A half-saved file must not drop the loaded rules")
+        XCTAssertEqual(service.problems.count, 1)
+    }
+
+    func testCreateFileIfNeededWritesLoadableExample() throws {
+        let service = RegexRulesService(dataRoot: root)
+        try service.createFileIfNeeded()
+
+        XCTAssertEqual(service.rules.count, 2)
+        XCTAssertEqual(service.activeRuleCount, 1, "The second example ships disabled")
+        XCTAssertTrue(service.problems.isEmpty)
+    }
+}
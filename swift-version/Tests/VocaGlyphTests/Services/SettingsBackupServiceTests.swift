@@ -0,0 +1,89 @@
+import XCTest
+@testable import VocaGlyph
+
+// MARK: - SettingsBackupServiceTests
+
+final class SettingsBackupServiceTests: XCTestCase {
+
+    private var directory: URL!
+    private var clock = Date(timeIntervalSince1970: 1_750_000_000)
+
+    override func setUp() {
+        super.setUp()
+        directory = FileManager.default.temporaryDirectory
+            .appendingPathComponent("SettingsBackupServiceTests-\(UUID().uuidString)", isDirectory: true)
+    }
+
+    override func tearDown() {
+        try? FileManager.default.removeItem(at: directory)
+        directory = nil
+        super.tearDown()
+    }
+
+    private func makeSUT() -> SettingsBackupService {
+        SettingsBackupService(directory: directory, now: { [unowned self] in self.clock })
+    }
+
+    func test_backup_thenLoad_roundTripsSettings() throws {
+        let sut = makeSUT()
+        var settings = AppSettings.defaults
+        settings.dictationLanguage = "French (FR)"
+        settings.removeFillerWords = true
+        settings.contextCaptureExcludedApps = ["com.apple.Safari"]
+
+        let backup = try sut.backup(settings)
+
+        XCTAssertTrue(backup.id.hasPrefix("settings-"))
+        XCTAssertEqual(try sut.load(backup), settings)
+    }
+
+    func test_listBackups_newestFirst() throws {
+        let sut = makeSUT()
+        let first = try sut.backup(.defaults)
+        clock += 60
+        let second = try sut.backup(.defaults)
+
+        XCTAssertEqual(sut.listBackups().map(\.id), [second.id, first.id])
+    }
+
+    func test_backup_sameSecond_doesNotOverwrite() throws {
+        let sut = makeSUT()
+        let first = try sut.backup(.defaults)
+        let second = try sut.backup(.defaults)
+
+        XCTAssertNotEqual(first.url, second.url)
+        XCTAssertEqual(sut.listBackups().count, 2)
+    }
+
+    func test_backup_prunesBeyondMaximum() throws {
+        let sut = makeSUT()
+        for _ in 0...SettingsBackupService.maxBackups {
+            try sut.backup(.defaults)
+            clock += 1
+        }
+        XCTAssertEqual(sut.listBackups().count, SettingsBackupService.maxBackups)
+    }
+
+    func test_load_invalidValue_throwsInvalid() throws {
+        let sut = makeSUT()
+        let backup = try sut.backup(.defaults)
+        try Data(#"{"dictationLanguage": "Klingon"}"#.utf8).write(to: backup.url)
+
+        XCTAssertThrowsError(try sut.load(backup)) { error in
+            guard case SettingsBackupError.invalid(let issues) = error else {
+                return XCTFail("Unexpected error \(error)")
+            }
+            XCTAssertEqual(issues.map(\.field), ["dictationLanguage"])
+        }
+    }
+
+    func test_load_missingKeys_useDefaults() throws {
+        let sut = makeSUT()
+        let backup = try sut.backup(.defaults)
+        try Data(#"{"autoPunctuation": false}"#.utf8).write(to: backup.url)
+
+        var expected = AppSettings.defaults
+        expected.autoPunctuation = false
+        XCTAssertEqual(try sut.load(backup), expected)
+    }
+}
@@ -0,0 +1,173 @@
+import XCTest
+import CoreGraphics
+@testable import VocaGlyph
+
+// MARK: - SettingsStoreTests
+
+final class SettingsStoreTests: XCTestCase {
+
+    private let suiteName = "SettingsStoreTests"
+    private var defaults: UserDefaults!
+
+    override func setUp() {
+        super.setUp()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+    }
+
+    override func tearDown() {
+        defaults.removePersistentDomain(forName: suiteName)
+        defaults = nil
+        super.tearDown()
+    }
+
+    private func makeSUT() -> SettingsStore {
+        SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+    }
+
+    // MARK: - Defaults
+
+    func test_settings_emptyDefaults_matchAppStorageDefaults() {
+        let sut = makeSUT()
+        XCTAssertEqual(sut.settings, AppSettings.defaults)
+        XCTAssertTrue(sut.settings.autoPunctuation)
+        XCTAssertEqual(sut.settings.selectedModel, "apple-native")
+    }
+
+    func test_settings_readsShortcutStoredAsDouble() {
+        defaults.set(Double(49), forKey: "customShortcutKeyCode")
+        defaults.set(Double(CGEventFlags.maskCommand.rawValue), forKey: "customShortcutModifiers")
+        let sut = makeSUT()
+        XCTAssertEqual(sut.settings.shortcutKeyCode, 49)
+        XCTAssertEqual(sut.settings.shortcutModifiers, CGEventFlags.maskCommand.rawValue)
+    }
+
+    // MARK: - update(_:)
+
+    func test_update_persistsOnlyChangedKeys() {
+        let sut = makeSUT()
+
+        let changed = sut.update { $0.dictationLanguage = "French (FR)" }
+
+        XCTAssertEqual(changed, [.dictationLanguage])
+        XCTAssertEqual(defaults.string(forKey: "dictationLanguage"), "French (FR)")
+        XCTAssertNil(defaults.object(forKey: "selectedModel"))
+    }
+
+    func test_update_noChange_doesNotNotify() {
+        let sut = makeSUT()
+        var calls = 0
+        let token = sut.subscribe { _, _ in calls += 1 }
+
+        sut.update { $0.dictationLanguage = "Auto-Detect" }
+
+        XCTAssertEqual(calls, 0)
+        token.cancel()
+    }
+
+    func test_update_notifiesSubscribersWithOldAndNew() {
+        let sut = makeSUT()
+        var received: (AppSettings, AppSettings)?
+        let token = sut.subscribe { old, new in received = (old, new) }
+
+        sut.update { $0.removeFillerWords = true }
+
+        XCTAssertEqual(received?.0.removeFillerWords, false)
+        XCTAssertEqual(received?.1.removeFillerWords, true)
+        token.cancel()
+    }
+
+    // MARK: - reload()
+
+    func test_reload_picksUpExternalWrite() {
+        let sut = makeSUT()
+        defaults.set("tiny", forKey: "selectedModel")
+
+        XCTAssertEqual(sut.settings.selectedModel, "apple-native", "cache is not re-read until reload")
+        sut.reload()
+        XCTAssertEqual(sut.settings.selectedModel, "tiny")
+    }
+
+    func test_didChangeNotification_reloadsCache() {
+        let center = NotificationCenter()
+        let sut = SettingsStore(defaults: defaults, notificationCenter: center)
+        defaults.set(true, forKey: "privacyModeEnabled")
+
+        center.post(name: UserDefaults.didChangeNotification, object: defaults)
+
+        XCTAssertTrue(sut.settings.privacyModeEnabled)
+    }
+
+    // MARK: - Subscriptions
+
+    func test_cancelledSubscription_isNotNotified() {
+        let sut = makeSUT()
+        var calls = 0
+        let token = sut.subscribe { _, _ in calls += 1 }
+        token.cancel()
+
+        sut.update { $0.enableDebugLogging = true }
+
+        XCTAssertEqual(calls, 0)
+    }
+}
+
+// MARK: - Launch Overrides
+
+final class SettingsStoreLaunchOverrideTests: XCTestCase {
+
+    private let suiteName = "SettingsStoreLaunchOverrideTests"
+    private var defaults: UserDefaults!
+
+    override func setUp() {
+        super.setUp()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+    }
+
+    override func tearDown() {
+        defaults.removePersistentDomain(forName: suiteName)
+        defaults = nil
+        super.tearDown()
+    }
+
+    func test_override_isServedButNotPersisted() {
+        let sut = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+
+        sut.applyLaunchOverrides([.selectedModel: "small"])
+
+        XCTAssertEqual(sut.settings.selectedModel, "small")
+        XCTAssertTrue(sut.isOverridden(.selectedModel))
+        XCTAssertNil(defaults.string(forKey: "selectedModel"))
+    }
+
+    func test_override_survivesUnrelatedReload() {
+        let sut = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+        sut.applyLaunchOverrides([.selectedModel: "small"])
+
+        defaults.set(true, forKey: "removeFillerWords")
+        sut.reload()
+
+        XCTAssertEqual(sut.settings.selectedModel, "small")
+    }
+
+    func test_override_isDroppedWhenUserChangesField() {
+        let sut = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+        sut.applyLaunchOverrides([.selectedModel: "small"])
+
+        defaults.set("medium", forKey: "selectedModel")
+        sut.reload()
+
+        XCTAssertEqual(sut.settings.selectedModel, "medium")
+        XCTAssertFalse(sut.isOverridden(.selectedModel))
+    }
+
+    func test_override_wrongType_isIgnored() {
+        let sut = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+
+        sut.applyLaunchOverrides([.autoPunctuation: "yes"])
+
+        XCTAssertTrue(sut.settings.autoPunctuation)
+        XCTAssertFalse(sut.isOverridden(.autoPunctuation))
+    }
+}
@@ -0,0 +1,139 @@
+import XCTest
+import CoreGraphics
+@testable import VocaGlyph
+
+// MARK: - MockSettingsApplier
+
+final class MockSettingsApplier: SettingsApplying {
+    var hotkeyResults: [HotkeyApplyOutcome] = []
+    var hotkeyCalls = 0
+    var modelChanges: [String] = []
+    var postProcessingCalls = 0
+
+    func applyHotkeyChange() -> HotkeyApplyOutcome {
+        hotkeyCalls += 1
+        return hotkeyResults.isEmpty ? .registered : hotkeyResults.removeFirst()
+    }
+
+    func applyModelChange(_ model: String) { modelChanges.append(model) }
+    func applyPostProcessingChange() { postProcessingCalls += 1 }
+}
+
+// MARK: - SettingsUpdaterTests
+
+final class SettingsUpdaterTests: XCTestCase {
+
+    private let suiteName = "SettingsUpdaterTests"
+    private var defaults: UserDefaults!
+    private var store: SettingsStore!
+    private var applier: MockSettingsApplier!
+
+    override func setUp() {
+        super.setUp()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+        store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+        applier = MockSettingsApplier()
+    }
+
+    override func tearDown() {
+        defaults.removePersistentDomain(forName: suiteName)
+        defaults = nil
+        store = nil
+        applier = nil
+        super.tearDown()
+    }
+
+    private func makeSUT(downloaded: Set<String>? = nil) -> SettingsUpdater {
+        SettingsUpdater(store: store, applier: applier, downloadedModels: { downloaded })
+    }
+
+    // MARK: - Validation
+
+    func test_update_invalidSettings_writesNothing() {
+        let sut = makeSUT()
+        var settings = store.settings
+        settings.dictationLanguage = "Klingon"
+        settings.removeFillerWords = true
+
+        let result = sut.update(settings)
+
+        XCTAssertFalse(result.succeeded)
+        XCTAssertEqual(result.issues.map(\.field), ["dictationLanguage"])
+        XCTAssertFalse(store.settings.removeFillerWords, "no field is written when any field is invalid")
+        XCTAssertNil(defaults.object(forKey: "removeFillerWords"))
+    }
+
+    func test_update_alreadyInvalidField_doesNotBlockOtherChanges() {
+        store.update { $0.selectedModel = "small" }
+        let sut = makeSUT(downloaded: [])
+        var settings = store.settings
+        settings.removeFillerWords = true
+
+        let result = sut.update(settings)
+
+        XCTAssertTrue(result.succeeded)
+        XCTAssertEqual(result.appliedKeys, [.removeFillerWords])
+    }
+
+    // MARK: - Apply
+
+    func test_update_multipleFields_appliesEachSideEffectOnce() {
+        let sut = makeSUT(downloaded: ["small"])
+        var settings = store.settings
+        settings.selectedModel = "small"
+        settings.shortcutKeyCode = 49
+        settings.enablePostProcessing = true
+
+        let result = sut.update(settings)
+
+        XCTAssertTrue(result.succeeded)
+        XCTAssertEqual(result.appliedKeys, [.selectedModel, .shortcutKeyCode, .enablePostProcessing])
+        XCTAssertEqual(applier.modelChanges, ["small"])
+        XCTAssertEqual(applier.hotkeyCalls, 1)
+        XCTAssertEqual(applier.postProcessingCalls, 1)
+    }
+
+    func test_update_unchangedSettings_appliesNothing() {
+        let sut = makeSUT()
+
+        let result = sut.update(store.settings)
+
+        XCTAssertTrue(result.succeeded)
+        XCTAssertTrue(result.appliedKeys.isEmpty)
+        XCTAssertEqual(applier.hotkeyCalls, 0)
+        XCTAssertTrue(applier.modelChanges.isEmpty)
+    }
+
+    func test_update_hotkeyRegistrationFails_revertsShortcutOnly() {
+        let sut = makeSUT()
+        applier.hotkeyResults = [.failed, .registered]
+        var settings = store.settings
+        settings.shortcutKeyCode = 49
+        settings.removeFillerWords = true
+
+        let result = sut.update(settings)
+
+        XCTAssertFalse(result.succeeded)
+        XCTAssertEqual(result.revertedKeys, [.shortcutKeyCode])
+        XCTAssertEqual(result.appliedKeys, [.removeFillerWords])
+        XCTAssertEqual(store.settings.shortcutKeyCode, UserDefaults.defaultShortcutKeyCode)
+        XCTAssertTrue(store.settings.removeFillerWords)
+        XCTAssertEqual(applier.hotkeyCalls, 2, "re-registers the previous shortcut after rollback")
+    }
+
+    func test_update_noHotkeyListener_keepsShortcutAsPending() {
+        let sut = makeSUT()
+        applier.hotkeyResults = [.pending]
+        var settings = store.settings
+        settings.shortcutKeyCode = 49
+
+        let result = sut.update(settings)
+
+        XCTAssertTrue(result.succeeded)
+        XCTAssertEqual(result.pendingKeys, [.shortcutKeyCode])
+        XCTAssertTrue(result.revertedKeys.isEmpty)
+        XCTAssertEqual(store.settings.shortcutKeyCode, 49)
+        XCTAssertEqual(applier.hotkeyCalls, 1)
+    }
+}
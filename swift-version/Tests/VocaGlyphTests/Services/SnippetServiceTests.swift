@@ -0,0 +1,74 @@
+import XCTest
+import SwiftData
+@testable import VocaGlyph
+
+@MainActor
+final class SnippetServiceTests: XCTestCase {
+
+    private var container: ModelContainer!
+    private var sut: SnippetService!
+
+    override func setUpWithError() throws {
+        let schema = Schema([TranscriptionItem.self, Snippet.self])
+        container = try ModelContainer(for: schema, configurations: [ModelConfiguration(schema: schema, isStoredInMemoryOnly: true)])
+        sut = SnippetService(container: container)
+    }
+
+    override func tearDownWithError() throws {
+        sut = nil
+        container = nil
+    }
+
+    private func historyItem(_ text: String) -> TranscriptionItem {
+        let item = TranscriptionItem(text: text)
+        container.mainContext.insert(item)
+        return item
+    }
+
+    func testPinCopiesTextAndSortsByName() {
+        sut.pin(historyItem("Best regards, Jane"), name: "Signature")
+        sut.pin(historyItem("Thanks for reaching out."), name: "Acknowledge")
+
+        XCTAssertEqual(sut.all().map(\.name), ["Acknowledge", "Signature"])
+        XCTAssertEqual(sut.all().last?.text, "Best regards, Jane")
+    }
+
+    func testPinningTheSameEntryTwiceRenames() {
+        let item = historyItem("See you tomorrow")
+        let first = sut.pin(item, name: "Bye")
+        let second = sut.pin(item, name: "Goodbye")
+
+        XCTAssertEqual(first.id, second.id)
+        XCTAssertEqual(sut.all().map(\.name), ["Goodbye"])
+    }
+
+    func testSnippetOutlivesItsHistoryEntry() throws {
+        let item = historyItem("Standup notes template")
+        let snippet = sut.pin(item, name: "Standup")
+        container.mainContext.delete(item)
+        try container.mainContext.save()
+
+        XCTAssertEqual(sut.snippet(snippet.id)?.text, "Standup notes template")
+    }
+
+    func testDelete() {
+        let snippet = sut.pin(historyItem("Temporary"), name: "Temp")
+
+        XCTAssertTrue(sut.delete(snippet.id))
+        XCTAssertFalse(sut.delete(snippet.id))
+        XCTAssertTrue(sut.all().isEmpty)
+    }
+
+    func testAppDelegatePinUsesTextWhenNameIsBlank() throws {
+        let appDelegate = AppDelegate()
+        appDelegate.sharedModelContainer = container
+        appDelegate.output = OutputService()
+        let item = historyItem("Please find the report attached")
+        try container.mainContext.save()
+
+        let snippet = appDelegate.historyAPI.pin(item.id, name: "  ")
+
+        XCTAssertEqual(snippet?.name, "Please find the report attached")
+        XCTAssertFalse(appDelegate.historyAPI.pasteSnippet(UUID()))
+    }
+}
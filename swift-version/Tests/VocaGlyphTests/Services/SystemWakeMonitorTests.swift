@@ -0,0 +1,53 @@
+import AppKit
+import XCTest
+@testable import VocaGlyph
+
+final class SystemWakeMonitorTests: XCTestCase {
+
+    private var workspace: NotificationCenter!
+    private var distributed: NotificationCenter!
+    private var sut: SystemWakeMonitor!
+
+    override func setUp() {
+        super.setUp()
+        workspace = NotificationCenter()
+        distributed = NotificationCenter()
+        sut = SystemWakeMonitor(workspaceCenter: workspace, distributedCenter: distributed, settleDelay: 0.05)
+    }
+
+    override func tearDown() {
+        sut.stop()
+        sut = nil
+        super.tearDown()
+    }
+
+    func testBurstOfWakeNotificationsIsCoalesced() {
+        let woke = expectation(description: "onWake")
+        var calls: [String] = []
+        sut.onWake = { reason in
+            calls.append(reason)
+            woke.fulfill()
+        }
+        sut.start()
+
+        workspace.post(name: NSWorkspace.didWakeNotification, object: nil)
+        workspace.post(name: NSWorkspace.screensDidWakeNotification, object: nil)
+        distributed.post(name: SystemWakeMonitor.screenUnlockedNotification, object: nil)
+
+        wait(for: [woke], timeout: 1)
+        XCTAssertEqual(calls.count, 1)
+        XCTAssertTrue(calls[0].contains("com.apple.screenIsUnlocked"))
+    }
+
+    func testStopDropsAPendingWake() {
+        let woke = expectation(description: "onWake")
+        woke.isInverted = true
+        sut.onWake = { _ in woke.fulfill() }
+        sut.start()
+
+        workspace.post(name: NSWorkspace.didWakeNotification, object: nil)
+        sut.stop()
+
+        wait(for: [woke], timeout: 0.2)
+    }
+}
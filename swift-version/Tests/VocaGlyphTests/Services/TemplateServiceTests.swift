@@ -0,0 +1,77 @@
+import XCTest
+import SwiftData
+@testable import VocaGlyph
+
+@MainActor
+final class TemplateServiceTests: XCTestCase {
+
+    private var container: ModelContainer!
+    private var sut: TemplateService!
+
+    override func setUpWithError() throws {
+        let schema = Schema([DictationTemplate.self])
+        container = try ModelContainer(for: schema, configurations: [ModelConfiguration(schema: schema, isStoredInMemoryOnly: true)])
+        sut = TemplateService(container: container)
+    }
+
+    override func tearDownWithError() throws {
+        sut = nil
+        container = nil
+    }
+
+    // MARK: - CRUD
+
+    func testCreateUpdateDelete() throws {
+        let standup = try sut.create(name: " Standup ", body: "Yesterday {yesterday}, today {today}.")
+        try sut.create(name: "Bug", body: "Steps: {steps}")
+        XCTAssertEqual(sut.all().map(\.name), ["Bug", "Standup"])
+        XCTAssertEqual(standup.slots, ["yesterday", "today"])
+
+        XCTAssertTrue(try sut.update(standup.id, name: "Daily", body: "Today {today}"))
+        XCTAssertEqual(sut.template(standup.id)?.body, "Today {today}")
+
+        XCTAssertTrue(sut.delete(standup.id))
+        XCTAssertFalse(sut.delete(standup.id))
+        XCTAssertEqual(sut.all().map(\.name), ["Bug"])
+    }
+
+    func testCreateRejectsBlankNameAndSlotlessBody() {
+        XCTAssertThrowsError(try sut.create(name: "  ", body: "{a}")) {
+            XCTAssertEqual($0 as? TemplateError, .emptyName)
+        }
+        XCTAssertThrowsError(try sut.create(name: "Plain", body: "No slots here")) {
+            XCTAssertEqual($0 as? TemplateError, .noSlots)
+        }
+    }
+
+    // MARK: - Filling
+
+    func testSuccessiveDictationsFillSlotsInOrder() throws {
+        let template = try sut.create(name: "Standup",
+                                      body: "Standup update: yesterday {a}, today {b}, blockers {c}.")
+        XCTAssertEqual(sut.start(template.id)?.nextSlot, "a")
+
+        XCTAssertEqual(sut.fill(with: "Fixed the login bug.")?.nextSlot, "b")
+        XCTAssertEqual(sut.fill(with: " reviewing PRs ")?.nextSlot, "c")
+        let done = sut.fill(with: "none")
+
+        XCTAssertEqual(done?.isComplete, true)
+        XCTAssertEqual(done?.rendered, "Standup update: yesterday Fixed the login bug, today reviewing PRs, blockers none.")
+        XCTAssertNil(sut.activeFill)
+        XCTAssertNil(sut.fill(with: "ignored"))
+    }
+
+    func testRepeatedSlotIsDictatedOnce() {
+        var fill = TemplateFill(templateID: UUID(), name: "Greeting", body: "Hi {name}! Thanks, {name}.")
+        XCTAssertEqual(fill.slots, ["name"])
+        fill.fill("Sam")
+        XCTAssertEqual(fill.rendered, "Hi Sam! Thanks, Sam.")
+    }
+
+    func testDeletingActiveTemplateCancelsFill() throws {
+        let template = try sut.create(name: "Note", body: "{text}")
+        sut.start(template.id)
+        sut.delete(template.id)
+        XCTAssertNil(sut.activeFill)
+    }
+}
@@ -0,0 +1,222 @@
+import XCTest
+@testable import VocaGlyph
+
+final class TextProcessingPipelineTests: XCTestCase {
+
+    private struct FailingProcessor: TextProcessor {
+        func process(_ text: String) async throws -> String {
+            throw NSError(domain: "TestDomain", code: 1)
+        }
+    }
+
+    private struct AppendingProcessor: TextProcessor {
+        let suffix: String
+        func process(_ text: String) async throws -> String { text + suffix }
+    }
+
+    // MARK: - Pipeline
+
+    func testStagesRunInOrderAndFailuresKeepTheirInput() async {
+        let pipeline = TextProcessingPipeline(stages: [
+            (.trim, AppendingProcessor(suffix: "a")),
+            (.replacements, FailingProcessor()),
+            (.capitalization, AppendingProcessor(suffix: "b")),
+        ])
+
+        let result = await pipeline.run("x")
+        XCTAssertEqual(result, "xab")
+    }
+
+    func testStandardPipelineFollowsSettings() {
+        var settings = AppSettings()
+        settings.autoPunctuation = false
+        settings.spokenPunctuationEnabled = true
+        settings.enablePostProcessing = true
+
+        let cleanup = LLMCleanupProcessor(engine: MockPostProcessingEngine(), prompt: "fix")
+        let stages = TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: cleanup)
+            .stages.map(\.stage)
+        XCTAssertEqual(stages, [.trim, .replacements, .regexRules, .punctuationCommands, .llmCleanup])
+
+        // No engine ready — the LLM stage is left out even when enabled.
+        let withoutEngine = TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil)
+            .stages.map(\.stage)
+        XCTAssertFalse(withoutEngine.contains(.llmCleanup))
+    }
+
+    func testStandardPipelineEndToEnd() async {
+        var settings = AppSettings()
+        settings.removeFillerWords = true
+        settings.spokenPunctuationEnabled = true
+
+        let pipeline = TextProcessingPipeline.standard(
+            settings: settings,
+            replacements: [(word: "vocaglyf", replacement: "VocaGlyph")],
+            cleanup: nil
+        )
+        let result = await pipeline.run("  um hello comma vocaglyf is, like, working  ")
+        XCTAssertEqual(result, "Hello, VocaGlyph is, working.")
+    }
+
+    // MARK: - Trim
+
+    func testTrimRemovesSurroundingWhitespace() async throws {
+        let result = try await TrimProcessor().process("  um, hello \n")
+        XCTAssertEqual(result, "um, hello")
+    }
+
+    func testFillerRemovalRunsOnlyWhenEnabled() {
+        var settings = AppSettings()
+        XCTAssertFalse(TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil)
+            .stages.map(\.stage).contains(.fillerRemoval))
+
+        settings.removeFillerWords = true
+        let stages = TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil)
+            .stages.map(\.stage)
+        XCTAssertEqual(Array(stages.prefix(2)), [.trim, .fillerRemoval])
+    }
+
+    // MARK: - Replacements
+
+    func testWordReplacementProcessorUsesApplicator() async throws {
+        let processor = WordReplacementProcessor(replacements: [(word: "can", replacement: "may")])
+        let result = try await processor.process("can I? cannot")
+        XCTAssertEqual(result, "may I? cannot")
+    }
+
+    // MARK: - Punctuation Commands
+
+    func testSpokenPunctuationBecomesSymbols() async throws {
+        let result = try await PunctuationCommandProcessor()
+            .process("hello comma how are you question mark")
+        XCTAssertEqual(result, "hello, how are you?")
+    }
+
+    func testCommandsPunctuatedByTheEngineAreNotDoubled() async throws {
+        let result = try await PunctuationCommandProcessor().process("Done period. Next Full Stop,")
+        XCTAssertEqual(result, "Done. Next.")
+    }
+
+    func testLineBreakCommands() async throws {
+        let result = try await PunctuationCommandProcessor()
+            .process("first new line second new paragraph third")
+        XCTAssertEqual(result, "first\nsecond\n\nthird")
+    }
+
+    func testCommandsOnlyMatchWholeWords() async throws {
+        let result = try await PunctuationCommandProcessor().process("the periodic semicolonic table")
+        XCTAssertEqual(result, "the periodic semicolonic table")
+    }
+
+    // MARK: - Capitalization
+
+    func testCapitalizationCapitalizesAndTerminates() async throws {
+        let processor = CapitalizationProcessor()
+        let plain = try await processor.process("hello world")
+        XCTAssertEqual(plain, "Hello world.")
+        let punctuated = try await processor.process("How are you?")
+        XCTAssertEqual(punctuated, "How are you?")
+        let lineBreak = try await processor.process("list\n")
+        XCTAssertEqual(lineBreak, "List\n")
+    }
+
+    // MARK: - Punctuation Restoration
+
+    private let runOn = "so i went to the store and then i forgot what i needed"
+
+    func testPunctuationRestorationUsesModelOutputWithTheSameWords() async throws {
+        let engine = MockPostProcessingEngine()
+        engine.returnedText = "So I went to the store, and then I forgot what I needed."
+
+        let result = try await PunctuationRestorationProcessor(engine: engine).process(runOn)
+        XCTAssertEqual(result, "So I went to the store, and then I forgot what I needed.")
+        XCTAssertEqual(engine.didCallRefineWithPrompt, PunctuationRestorer.prompt)
+    }
+
+    func testPunctuationRestorationRejectsChangedWords() async throws {
+        let engine = MockPostProcessingEngine()
+        engine.returnedText = "I went to the store and forgot what I needed."
+
+        let result = try await PunctuationRestorationProcessor(engine: engine).process(runOn)
+        XCTAssertEqual(result, runOn)
+    }
+
+    func testPunctuationRestorationSkipsPunctuatedText() async throws {
+        let engine = MockPostProcessingEngine()
+        let punctuated = "So I went to the store, and then I forgot what I needed."
+
+        let result = try await PunctuationRestorationProcessor(engine: engine).process(punctuated)
+        XCTAssertEqual(result, punctuated)
+        XCTAssertNil(engine.didCallRefineWithText)
+    }
+
+    func testPunctuationRestorationNeedsAnEngine() {
+        var settings = AppSettings()
+        settings.punctuationRestorationEnabled = true
+        let withoutEngine = TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil)
+        XCTAssertFalse(withoutEngine.stages.map(\.stage).contains(.punctuationRestoration))
+
+        let stages = TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil,
+                                                     restorer: MockPostProcessingEngine()).stages.map(\.stage)
+        XCTAssertEqual(stages, [.trim, .replacements, .regexRules, .punctuationRestoration, .capitalization])
+    }
+
+    // MARK: - LLM Cleanup
+
+    func testLLMCleanupForwardsTextAndPrompt() async throws {
+        let engine = MockPostProcessingEngine()
+        engine.returnedText = "Refined"
+
+        let result = try await LLMCleanupProcessor(engine: engine, prompt: "fix grammar").process("raw")
+        XCTAssertEqual(result, "Refined")
+        XCTAssertEqual(engine.didCallRefineWithText, "raw")
+        XCTAssertEqual(engine.didCallRefineWithPrompt, "fix grammar")
+    }
+
+    // MARK: - Translation
+
+    private struct UppercasingTranslator: TranslationEngine {
+        func translate(_ text: String, from source: String?, to target: String) async throws -> String {
+            text.uppercased()
+        }
+    }
+
+    func testTranslationNeedsATargetAndAnEngine() {
+        var settings = AppSettings()
+        settings.autoPunctuation = false
+        settings.translationProvider = TranslationProvider.remote.rawValue
+        let noTarget = TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil,
+                                                       translator: UppercasingTranslator())
+        XCTAssertFalse(noTarget.stages.map(\.stage).contains(.translation))
+
+        settings.translationTargetLanguage = "de"
+        let noEngine = TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil)
+        XCTAssertFalse(noEngine.stages.map(\.stage).contains(.translation))
+
+        let ready = TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil,
+                                                    translator: UppercasingTranslator())
+        XCTAssertTrue(ready.stages.map(\.stage).contains(.translation))
+    }
+
+    func testTranslationSkipsTextAlreadyInTheTargetLanguage() async throws {
+        let same = try await TranslationProcessor(engine: UppercasingTranslator(), source: "de", target: "de")
+            .process("hallo")
+        XCTAssertEqual(same, "hallo")
+
+        let other = try await TranslationProcessor(engine: UppercasingTranslator(), source: nil, target: "de")
+            .process("hello")
+        XCTAssertEqual(other, "HELLO")
+    }
+
+    func testLLMCleanupTimesOut() async {
+        let engine = MockPostProcessingEngine()
+        engine.shouldTimeout = true
+
+        do {
+            _ = try await LLMCleanupProcessor(engine: engine, prompt: "fix", timeout: 0.1).process("raw")
+            XCTFail("Expected a timeout")
+        } catch {
+            XCTAssertEqual((error as NSError).code, 408)
+        }
+    }
+}
@@ -0,0 +1,72 @@
+import XCTest
+import AVFoundation
+@testable import VocaGlyph
+
+final class TranscriptionServerTests: XCTestCase {
+
+    private let token = "secret"
+
+    /// One second of 16-bit silence at `sampleRate`, base64-encoded.
+    private func silence(sampleRate: Int = 16_000) -> String {
+        Data(count: sampleRate * 2).base64EncodedString()
+    }
+
+    private func respond(_ json: String) async -> TranscriptionRPC.Response {
+        await TranscriptionServer.respond(to: Data(json.utf8), token: token) { buffer in
+            "\(buffer.frameLength) frames"
+        }
+    }
+
+    func testRequestsWithoutTheTokenAreRejected() async {
+        let response = await respond(#"{"jsonrpc": "2.0", "id": 1, "method": "transcribe", "params": {"token": "guess"}}"#)
+        XCTAssertEqual(response, .failure(.number(1), TranscriptionRPC.Failure.unauthorized, "Unauthorized."))
+    }
+
+    func testMalformedAndUnknownRequestsAreErrors() async {
+        let garbage = await respond("not json")
+        XCTAssertEqual(garbage.error?.code, TranscriptionRPC.Failure.parseError)
+
+        let unknown = await respond(#"{"id": "a", "method": "status", "params": {"token": "secret"}}"#)
+        XCTAssertEqual(unknown.id, .string("a"))
+        XCTAssertEqual(unknown.error?.code, TranscriptionRPC.Failure.methodNotFound)
+
+        let noAudio = await respond(#"{"id": 2, "method": "transcribe", "params": {"token": "secret"}}"#)
+        XCTAssertEqual(noAudio.error?.code, TranscriptionRPC.Failure.invalidParams)
+
+        let badFormat = await respond(#"{"id": 3, "method": "transcribe", "params": {"token": "secret", "audio": "AAAA", "format": "mp3"}}"#)
+        XCTAssertEqual(badFormat.error?.code, TranscriptionRPC.Failure.invalidParams)
+    }
+
+    func testPCMIsResampledForTheEngine() async {
+        let json = #"{"jsonrpc": "2.0", "id": 4, "method": "transcribe", "params": {"token": "secret", "format": "pcm", "sampleRate": 48000, "audio": ""#
+            + silence(sampleRate: 48_000) + #""}}"#
+        let response = await respond(json)
+        XCTAssertNil(response.error)
+        XCTAssertEqual(response.result?.durationSeconds ?? 0, 1, accuracy: 0.01)
+        let frames = Int(response.result?.text.split(separator: " ").first ?? "") ?? 0
+        XCTAssertEqual(frames, 16_000, accuracy: 50)
+    }
+
+    func testWAVIsDecoded() throws {
+        let file = FileManager.default.temporaryDirectory.appendingPathComponent("TranscriptionServerTests-\(UUID().uuidString).wav")
+        defer { try? FileManager.default.removeItem(at: file) }
+        let format = AVAudioFormat(commonFormat: .pcmFormatInt16, sampleRate: 44_100, channels: 2, interleaved: true)!
+        do {
+            let writer = try AVAudioFile(forWriting: file, settings: format.settings,
+                                         commonFormat: .pcmFormatInt16, interleaved: true)
+            let buffer = AVAudioPCMBuffer(pcmFormat: format, frameCapacity: 22_050)!
+            buffer.frameLength = 22_050
+            try writer.write(from: buffer)
+        }
+
+        let decoded = try TranscriptionServer.wavBuffer(Data(contentsOf: file))
+        XCTAssertEqual(decoded.format, TranscriptionServer.engineFormat)
+        XCTAssertEqual(Double(decoded.frameLength), 8_000, accuracy: 50)
+    }
+
+    func testEmptyOrUnreadableAudioIsRejected() {
+        XCTAssertThrowsError(try TranscriptionServer.pcmBuffer(Data(), sampleRate: 16_000))
+        XCTAssertThrowsError(try TranscriptionServer.pcmBuffer(Data(count: 4), sampleRate: 1))
+        XCTAssertThrowsError(try TranscriptionServer.wavBuffer(Data("RIFF nonsense".utf8)))
+    }
+}
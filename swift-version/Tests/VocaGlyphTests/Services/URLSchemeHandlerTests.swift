@@ -0,0 +1,48 @@
+import XCTest
+@testable import VocaGlyph
+
+private final class MockURLSchemeTarget: URLSchemeHandling {
+    var triggers: [ExternalTriggerCommand] = []
+    var pasteCount = 0
+    var settingsOpened = 0
+
+    func handleTrigger(_ command: ExternalTriggerCommand) { triggers.append(command) }
+    func pasteLastTranscription() -> Bool { pasteCount += 1; return true }
+    func openSettings() { settingsOpened += 1 }
+}
+
+@MainActor
+final class URLSchemeHandlerTests: XCTestCase {
+
+    private func command(_ string: String) -> URLCommand? {
+        URL(string: string).flatMap(URLCommand.init(url:))
+    }
+
+    func testParsesHostAndPathForms() {
+        XCTAssertEqual(command("voicetotext://toggle"), .toggle)
+        XCTAssertEqual(command("voicetotext:///start"), .start)
+        XCTAssertEqual(command("VocaGlyph://Paste-Last"), .pasteLast)
+        XCTAssertEqual(command("voicetotext://open-settings"), .openSettings)
+    }
+
+    func testRejectsOtherSchemesAndUnknownCommands() {
+        XCTAssertNil(command("https://toggle"))
+        XCTAssertNil(command("voicetotext://explode"))
+        XCTAssertNil(command("voicetotext://"))
+    }
+
+    func testRoutesCommandsToTheTarget() throws {
+        let target = MockURLSchemeTarget()
+        let handler = URLSchemeHandler(target: target)
+
+        XCTAssertTrue(handler.handle(try XCTUnwrap(URL(string: "voicetotext://start"))))
+        XCTAssertTrue(handler.handle(try XCTUnwrap(URL(string: "voicetotext://stop"))))
+        XCTAssertTrue(handler.handle(try XCTUnwrap(URL(string: "voicetotext://paste-last"))))
+        XCTAssertTrue(handler.handle(try XCTUnwrap(URL(string: "voicetotext://open-settings"))))
+        XCTAssertFalse(handler.handle(try XCTUnwrap(URL(string: "voicetotext://nope"))))
+
+        XCTAssertEqual(target.triggers, [.start, .stop])
+        XCTAssertEqual(target.pasteCount, 1)
+        XCTAssertEqual(target.settingsOpened, 1)
+    }
+}
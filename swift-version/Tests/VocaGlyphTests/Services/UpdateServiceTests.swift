@@ -0,0 +1,22 @@
+import XCTest
+@testable import VocaGlyph
+
+final class UpdateServiceTests: XCTestCase {
+
+    func testMenuTitleAnnouncesAnAvailableUpdate() {
+        XCTAssertEqual(UpdateStatus.available(version: "1.4.0").menuTitle, "Update to 1.4.0 Available…")
+        XCTAssertEqual(UpdateStatus.downloading(version: "1.4.0").menuTitle, "Downloading 1.4.0…")
+        XCTAssertEqual(UpdateStatus.installing(version: "1.4.0").menuTitle, "Installing 1.4.0…")
+    }
+
+    func testMenuTitleFallsBackToCheckForUpdates() {
+        for status: UpdateStatus in [.idle, .upToDate, .failed("offline")] {
+            XCTAssertEqual(status.menuTitle, "Check for Updates…")
+        }
+        XCTAssertEqual(UpdateStatus.checking.menuTitle, "Checking for Updates…")
+    }
+
+    func testAutomaticChecksAreOnByDefault() {
+        XCTAssertTrue(AppSettings.defaults.automaticUpdateChecks)
+    }
+}
@@ -62,3 +62,28 @@ final class ShortcutRecorderButtonTests: XCTestCase {
         XCTAssertTrue(true, "ShortcutRecorderButton requires manual UI testing via the Settings window.")
     }
 }
+
+// MARK: - HotkeyBackend Persistence
+
+final class HotkeyBackendTests: XCTestCase {
+
+    override func tearDown() {
+        UserDefaults.standard.removeObject(forKey: UserDefaults.hotkeyBackendKey)
+        super.tearDown()
+    }
+
+    func test_current_whenUnset_defaultsToEventTap() {
+        UserDefaults.standard.removeObject(forKey: UserDefaults.hotkeyBackendKey)
+        XCTAssertEqual(HotkeyBackend.current, .eventTap)
+    }
+
+    func test_current_readsStoredBackend() {
+        UserDefaults.standard.set(HotkeyBackend.nsEvent.rawValue, forKey: UserDefaults.hotkeyBackendKey)
+        XCTAssertEqual(HotkeyBackend.current, .nsEvent)
+    }
+
+    func test_current_unknownValue_fallsBackToEventTap() {
+        UserDefaults.standard.set("carbon", forKey: UserDefaults.hotkeyBackendKey)
+        XCTAssertEqual(HotkeyBackend.current, .eventTap)
+    }
+}
@@ -0,0 +1,20 @@
+import XCTest
+@testable import VocaGlyph
+
+final class TrayMenuTests: XCTestCase {
+
+    func testTrayModelsListAppleNativeFirstThenDownloadedByTitle() {
+        let models = AppDelegate.trayModels(downloaded: ["parakeet-v3", "large-v3_turbo", "small"], selected: "small")
+        XCTAssertEqual(models.map(\.id), ["apple-native", "parakeet-v3", "large-v3_turbo", "small"])
+    }
+
+    func testTrayModelsKeepTheSelectedModelEvenIfMissing() {
+        let models = AppDelegate.trayModels(downloaded: [], selected: "medium")
+        XCTAssertEqual(models.map(\.id), ["apple-native", "medium"])
+    }
+
+    func testModelMenuTitleFallsBackToTheId() {
+        XCTAssertEqual(AppDelegate.modelMenuTitle(for: "parakeet-v2"), "Parakeet v2")
+        XCTAssertEqual(AppDelegate.modelMenuTitle(for: "mystery-model"), "mystery-model")
+    }
+}
@@ -0,0 +1,123 @@
+import XCTest
+import SwiftData
+@testable import VocaGlyph
+
+final class AppDirsTests: XCTestCase {
+
+    private var sandbox: URL!
+    private var dirs: AppDirs!
+    private let fileManager = FileManager.default
+
+    override func setUpWithError() throws {
+        try super.setUpWithError()
+        sandbox = fileManager.temporaryDirectory
+            .appendingPathComponent("AppDirsTests-\(UUID().uuidString)", isDirectory: true)
+        dirs = AppDirs(root: sandbox.appendingPathComponent("VocaGlyph", isDirectory: true))
+    }
+
+    override func tearDown() {
+        try? fileManager.removeItem(at: sandbox)
+        super.tearDown()
+    }
+
+    private func write(_ text: String, to url: URL) throws {
+        try fileManager.createDirectory(at: url.deletingLastPathComponent(), withIntermediateDirectories: true)
+        try text.write(to: url, atomically: true, encoding: .utf8)
+    }
+
+    private func read(_ url: URL) -> String? {
+        try? String(contentsOf: url, encoding: .utf8)
+    }
+
+    // MARK: - Layout
+
+    func testOverrideLayoutKeepsEverythingUnderTheRoot() {
+        XCTAssertEqual(dirs.models, dirs.root.appendingPathComponent("models", isDirectory: true))
+        XCTAssertEqual(dirs.backups, dirs.root.appendingPathComponent("Backups", isDirectory: true))
+        XCTAssertEqual(dirs.recovered, dirs.root.appendingPathComponent("Recovered", isDirectory: true))
+        XCTAssertEqual(dirs.logs, dirs.root.appendingPathComponent("Logs", isDirectory: true))
+        XCTAssertEqual(dirs.caches, dirs.root.appendingPathComponent("Caches", isDirectory: true))
+        XCTAssertEqual(dirs.historyStore.lastPathComponent, AppDirs.historyStoreName)
+    }
+
+    func testStandardLayoutUsesTheMacOSLocations() {
+        let standard = AppDirs.standard()
+        XCTAssertTrue(standard.root.path.hasSuffix("Application Support/VocaGlyph"))
+        XCTAssertTrue(standard.logs.path.hasSuffix("Logs/VocaGlyph"))
+        XCTAssertTrue(standard.caches.path.hasSuffix("Caches/VocaGlyph"))
+    }
+
+    func testServicesUseTheInjectedRoot() {
+        XCTAssertEqual(ModelRegistry(dataRoot: dirs.root).modelsRoot, dirs.models)
+        XCTAssertEqual(PendingOutputStore(dataRoot: dirs.root).fileURL.deletingLastPathComponent().standardizedFileURL,
+                       dirs.root.standardizedFileURL)
+    }
+
+    func testSocketsStayUnderAShortRoot() {
+        let root = URL(fileURLWithPath: "/Users/me/Library/Application Support/VocaGlyph", isDirectory: true)
+        let fallback = URL(fileURLWithPath: "/tmp", isDirectory: true)
+        XCTAssertEqual(AppDirs.socketDirectory(preferred: root, fallback: fallback), root)
+    }
+
+    func testSocketsMoveOutOfASandboxContainerRoot() {
+        let root = URL(fileURLWithPath: "/Users/someone/Library/Containers/com.vocaglyph.app/Data/Library/Application Support/VocaGlyph",
+                       isDirectory: true)
+        let fallback = URL(fileURLWithPath: "/Users/someone/Library/Containers/com.vocaglyph.app/Data/tmp", isDirectory: true)
+        XCTAssertEqual(AppDirs.socketDirectory(preferred: root, fallback: fallback), fallback)
+    }
+
+    // MARK: - History store
+
+    func testHistoryStoreMovesWithItsSidecars() throws {
+        let store = sandbox.appendingPathComponent("default.store")
+        try write("db", to: store)
+        try write("wal", to: URL(fileURLWithPath: store.path + "-wal"))
+
+        XCTAssertTrue(dirs.adoptHistoryStore(at: store))
+        XCTAssertEqual(read(dirs.historyStore), "db")
+        XCTAssertEqual(read(URL(fileURLWithPath: dirs.historyStore.path + "-wal")), "wal")
+        XCTAssertFalse(fileManager.fileExists(atPath: store.path))
+    }
+
+    func testExistingHistoryStoreIsKept() throws {
+        let store = sandbox.appendingPathComponent("default.store")
+        try write("old", to: store)
+        try write("new", to: dirs.historyStore)
+
+        XCTAssertFalse(dirs.adoptHistoryStore(at: store))
+        XCTAssertEqual(read(dirs.historyStore), "new")
+    }
+
+    func testFailedMoveLeavesTheOldStoreWhole() throws {
+        let store = sandbox.appendingPathComponent("default.store")
+        try write("db", to: store)
+        try write("wal", to: URL(fileURLWithPath: store.path + "-wal"))
+        // A stray sidecar at the destination makes the second move fail.
+        try write("stray", to: URL(fileURLWithPath: dirs.historyStore.path + "-wal"))
+
+        XCTAssertFalse(dirs.adoptHistoryStore(at: store))
+        XCTAssertEqual(read(store), "db")
+        XCTAssertEqual(read(URL(fileURLWithPath: store.path + "-wal")), "wal")
+        XCTAssertFalse(fileManager.fileExists(atPath: dirs.historyStore.path))
+    }
+
+    @MainActor
+    func testAppDelegateAdoptsTheDefaultStore() throws {
+        let store = sandbox.appendingPathComponent("default.store")
+        try fileManager.createDirectory(at: sandbox, withIntermediateDirectories: true)
+        do {
+            let schema = Schema([TranscriptionItem.self, PostProcessingTemplate.self, WordReplacement.self,
+                                 Snippet.self, DictationTemplate.self])
+            let old = try ModelContainer(for: schema, configurations: [ModelConfiguration(schema: schema, url: store)])
+            old.mainContext.insert(TranscriptionItem(text: "Kept across the upgrade"))
+            try old.mainContext.save()
+        }
+
+        let appDelegate = AppDelegate()
+        appDelegate.sharedModelContainer = AppDelegate.makeModelContainer(dirs: dirs, legacyStore: store)
+
+        XCTAssertTrue(fileManager.fileExists(atPath: dirs.historyStore.path))
+        XCTAssertFalse(fileManager.fileExists(atPath: store.path))
+        XCTAssertEqual(appDelegate.historyService?.recent(limit: 10).map(\.text), ["Kept across the upgrade"])
+    }
+}
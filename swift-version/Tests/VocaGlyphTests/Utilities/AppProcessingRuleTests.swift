@@ -0,0 +1,58 @@
+import XCTest
+@testable import VocaGlyph
+
+final class AppProcessingRuleTests: XCTestCase {
+
+    // MARK: - Parsing
+
+    func testParsesStylesAndStageOverrides() throws {
+        let rule = try AppProcessingRule(parsing: "com.microsoft.VSCode: style=codeComment, -llmCleanup +numberNormalization")
+        XCTAssertEqual(rule.bundleIdentifier, "com.microsoft.VSCode")
+        XCTAssertEqual(rule.outputStyle, .codeComment)
+        XCTAssertEqual(rule.stageOverrides, [.llmCleanup: false, .numberNormalization: true])
+    }
+
+    func testLineRoundTrips() throws {
+        let rule = try AppProcessingRule(parsing: "md.obsidian:+numberNormalization,style=bullets")
+        XCTAssertEqual(rule.line, "md.obsidian: style=bullets, +numberNormalization")
+        XCTAssertEqual(try AppProcessingRule(parsing: rule.line), rule)
+    }
+
+    func testParseErrors() {
+        XCTAssertThrowsError(try AppProcessingRule(parsing: "-llmCleanup")) {
+            XCTAssertEqual($0 as? AppProcessingRule.ParseError, .missingBundleIdentifier)
+        }
+        XCTAssertThrowsError(try AppProcessingRule(parsing: "md.obsidian: -markdown")) {
+            XCTAssertEqual($0 as? AppProcessingRule.ParseError, .unknownStage("markdown"))
+        }
+        XCTAssertThrowsError(try AppProcessingRule(parsing: "md.obsidian: -trim")) {
+            XCTAssertEqual($0 as? AppProcessingRule.ParseError, .unknownStage("trim"))
+        }
+        XCTAssertThrowsError(try AppProcessingRule(parsing: "md.obsidian: style=markdown")) {
+            XCTAssertEqual($0 as? AppProcessingRule.ParseError, .unknownStyle("markdown"))
+        }
+        XCTAssertThrowsError(try AppProcessingRule(parsing: "md.obsidian: llmCleanup")) {
+            XCTAssertEqual($0 as? AppProcessingRule.ParseError, .unknownToken("llmCleanup"))
+        }
+    }
+
+    // MARK: - Lookup
+
+    func testRuleForSkipsInvalidLinesAndOtherApps() {
+        let lines = ["com.apple.mail: nonsense", "com.apple.Notes: -capitalization", "com.apple.mail: style=email"]
+        XCTAssertEqual(AppProcessingRule.rule(for: "com.apple.mail", in: lines)?.outputStyle, .email)
+        XCTAssertNil(AppProcessingRule.rule(for: "com.tinyspeck.slackmacgap", in: lines))
+        XCTAssertNil(AppProcessingRule.rule(for: nil, in: lines))
+    }
+
+    // MARK: - Pipeline
+
+    func testOverridesSwitchStagesButNotTrim() {
+        var settings = AppSettings()
+        settings.autoPunctuation = true
+        let stages = TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil,
+                                                     overrides: [.trim: false, .capitalization: false, .numberNormalization: true])
+            .stages.map(\.stage)
+        XCTAssertEqual(stages, [.trim, .replacements, .numberNormalization, .regexRules])
+    }
+}
@@ -0,0 +1,14 @@
+import XCTest
+@testable import VocaGlyph
+
+final class AppSandboxTests: XCTestCase {
+
+    func testContainerIdMarksASandboxedProcess() {
+        XCTAssertTrue(AppSandbox.isActive(environment: ["APP_SANDBOX_CONTAINER_ID": "com.vocaglyph.app"]))
+    }
+
+    func testMissingOrEmptyContainerIdIsNotSandboxed() {
+        XCTAssertFalse(AppSandbox.isActive(environment: [:]))
+        XCTAssertFalse(AppSandbox.isActive(environment: ["APP_SANDBOX_CONTAINER_ID": ""]))
+    }
+}
@@ -0,0 +1,62 @@
+import XCTest
+@testable import VocaGlyph
+
+// MARK: - DataDirectoryOverrideTests
+
+final class DataDirectoryOverrideTests: XCTestCase {
+
+    // MARK: - resolve(arguments:environment:)
+
+    func test_resolve_noFlagOrEnv_returnsNil() {
+        XCTAssertNil(DataDirectoryOverride.resolve(arguments: ["VocaGlyph"], environment: [:]))
+    }
+
+    func test_resolve_launchFlag_returnsPath() {
+        let url = DataDirectoryOverride.resolve(
+            arguments: ["VocaGlyph", "--data-dir", "/Volumes/SSD/VocaGlyph"],
+            environment: [:]
+        )
+        XCTAssertEqual(url?.path, "/Volumes/SSD/VocaGlyph")
+    }
+
+    func test_resolve_inlineLaunchFlag_returnsPath() {
+        let url = DataDirectoryOverride.resolve(
+            arguments: ["VocaGlyph", "--data-dir=/tmp/vg-instance-2"],
+            environment: [:]
+        )
+        XCTAssertEqual(url?.path, "/tmp/vg-instance-2")
+    }
+
+    func test_resolve_environmentVariable_returnsPath() {
+        let url = DataDirectoryOverride.resolve(
+            arguments: ["VocaGlyph"],
+            environment: ["VOCAGLYPH_DATA_DIR": "/tmp/vg-env"]
+        )
+        XCTAssertEqual(url?.path, "/tmp/vg-env")
+    }
+
+    func test_resolve_flagTakesPriorityOverEnvironment() {
+        let url = DataDirectoryOverride.resolve(
+            arguments: ["VocaGlyph", "--data-dir", "/tmp/vg-flag"],
+            environment: ["VOCAGLYPH_DATA_DIR": "/tmp/vg-env"]
+        )
+        XCTAssertEqual(url?.path, "/tmp/vg-flag")
+    }
+
+    func test_resolve_expandsTilde() {
+        let url = DataDirectoryOverride.resolve(arguments: [], environment: ["VOCAGLYPH_DATA_DIR": "~/vg-data"])
+        XCTAssertEqual(url?.path, (NSHomeDirectory() as NSString).appendingPathComponent("vg-data"))
+    }
+
+    func test_resolve_blankValue_returnsNil() {
+        XCTAssertNil(DataDirectoryOverride.resolve(arguments: [], environment: ["VOCAGLYPH_DATA_DIR": "   "]))
+    }
+
+    func test_resolve_flagWithoutValue_fallsBackToEnvironment() {
+        let url = DataDirectoryOverride.resolve(
+            arguments: ["VocaGlyph", "--data-dir"],
+            environment: ["VOCAGLYPH_DATA_DIR": "/tmp/vg-env"]
+        )
+        XCTAssertEqual(url?.path, "/tmp/vg-env")
+    }
+}
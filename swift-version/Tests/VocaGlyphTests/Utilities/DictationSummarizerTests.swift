@@ -0,0 +1,36 @@
+import XCTest
+@testable import VocaGlyph
+
+final class DictationSummarizerTests: XCTestCase {
+
+    func testShouldSummarizeNeedsSettingAndLength() {
+        var settings = AppSettings.defaults
+        settings.summaryMinimumSeconds = 60
+        XCTAssertFalse(DictationSummarizer.shouldSummarize(duration: 120, settings: settings))
+
+        settings.summarizeLongDictations = true
+        XCTAssertTrue(DictationSummarizer.shouldSummarize(duration: 120, settings: settings))
+        XCTAssertTrue(DictationSummarizer.shouldSummarize(duration: 60, settings: settings))
+        XCTAssertFalse(DictationSummarizer.shouldSummarize(duration: 59.5, settings: settings))
+    }
+
+    func testSummarizeReturnsTrimmedEngineOutput() async {
+        let engine = MockPostProcessingEngine()
+        engine.returnedText = "  - Ship on Monday\n"
+        let summary = await DictationSummarizer.summarize("so the plan is we ship on monday", engine: engine)
+        XCTAssertEqual(summary, "- Ship on Monday")
+        XCTAssertEqual(engine.didCallRefineWithPrompt, DictationSummarizer.prompt)
+    }
+
+    func testSummarizeReturnsNilOnFailureOrEmptyOutput() async {
+        let failing = MockPostProcessingEngine()
+        failing.shouldThrowError = true
+        let failed = await DictationSummarizer.summarize("some text", engine: failing)
+        XCTAssertNil(failed)
+
+        let empty = MockPostProcessingEngine()
+        empty.returnedText = "   "
+        let blank = await DictationSummarizer.summarize("some text", engine: empty)
+        XCTAssertNil(blank)
+    }
+}
@@ -0,0 +1,27 @@
+import XCTest
+@testable import VocaGlyph
+
+final class DownloadRateLimiterTests: XCTestCase {
+
+    func testUnlimitedNeverWaits() async throws {
+        let limiter = DownloadRateLimiter()
+        let start = Date()
+        for _ in 0..<100 {
+            try await limiter.consume(1 << 20, bytesPerSecond: 0)
+        }
+        XCTAssertLessThan(Date().timeIntervalSince(start), 0.1)
+    }
+
+    func testBurstUpToOneSecondThenThrottles() async throws {
+        let limiter = DownloadRateLimiter()
+        let start = Date()
+
+        // The first second's worth passes immediately…
+        try await limiter.consume(1_000, bytesPerSecond: 1_000)
+        XCTAssertLessThan(Date().timeIntervalSince(start), 0.1)
+
+        // …then 500 more bytes at 1000 B/s take about half a second.
+        try await limiter.consume(500, bytesPerSecond: 1_000)
+        XCTAssertGreaterThanOrEqual(Date().timeIntervalSince(start), 0.45)
+    }
+}
@@ -0,0 +1,68 @@
+import XCTest
+@testable import VocaGlyph
+
+final class FillerWordRemoverTests: XCTestCase {
+
+    private func clean(_ text: String) -> String {
+        FillerWordRemover.remove(from: text)
+    }
+
+    // MARK: - Hesitations
+
+    func testHesitationsAreRemovedWithTheirComma() {
+        XCTAssertEqual(clean("um, so uh the plumber"), "so the plumber")
+        XCTAssertEqual(clean("I think, erm, we should go"), "I think, we should go")
+    }
+
+    func testSentenceStartStaysCapitalised() {
+        XCTAssertEqual(clean("Um, so we ship it"), "So we ship it")
+    }
+
+    func testTerminalPunctuationIsKept() {
+        XCTAssertEqual(clean("I think um. Next"), "I think. Next")
+    }
+
+    func testWordsContainingFillersAreKept() {
+        XCTAssertEqual(clean("the plumber ate umami"), "the plumber ate umami")
+    }
+
+    // MARK: - Discourse Fillers
+
+    func testDiscourseFillersOnlyWhenSetOffByCommas() {
+        XCTAssertEqual(clean("it is, like, working"), "it is, working")
+        XCTAssertEqual(clean("Like, you know, I mean, fine"), "Fine")
+        XCTAssertEqual(clean("I like it and you know it"), "I like it and you know it")
+    }
+
+    // MARK: - Stutters
+
+    func testRepeatedWordsCollapse() {
+        XCTAssertEqual(clean("I I I think the the plan works"), "I think the plan works")
+        XCTAssertEqual(clean("th- the plan"), "the plan")
+        XCTAssertEqual(clean("the the, end"), "the, end")
+    }
+
+    func testIntentionalRepeatsAreKept() {
+        XCTAssertEqual(clean("he had had enough, very very much"), "he had had enough, very very much")
+    }
+
+    // MARK: - Trailing "so"
+
+    func testTrailingSoIsDropped() {
+        XCTAssertEqual(clean("that's the plan, so"), "that's the plan")
+        XCTAssertEqual(clean("we are done so…"), "we are done")
+        XCTAssertEqual(clean("we are done, so."), "we are done.")
+    }
+
+    func testMeaningfulSoIsKept() {
+        XCTAssertEqual(clean("I think so"), "I think so")
+        XCTAssertEqual(clean("I hope so..."), "I hope so...")
+        XCTAssertEqual(clean("so we begin"), "so we begin")
+    }
+
+    // MARK: - Layout
+
+    func testLinesAreCleanedSeparately() {
+        XCTAssertEqual(clean("um first\nuh second"), "first\nsecond")
+    }
+}
@@ -0,0 +1,100 @@
+import XCTest
+import CoreGraphics
+@testable import VocaGlyph
+
+// MARK: - LaunchOptionsTests
+
+final class LaunchOptionsTests: XCTestCase {
+
+    // MARK: - parse(_:)
+
+    func test_parse_noFlags_isEmpty() {
+        let options = LaunchOptions.parse(["/Applications/VocaGlyph.app/Contents/MacOS/VocaGlyph"])
+        XCTAssertTrue(options.isEmpty)
+        XCTAssertTrue(options.errors.isEmpty)
+    }
+
+    func test_parse_allFlags() {
+        let options = LaunchOptions.parse(["VocaGlyph", "--model", "small", "--language", "de", "--hotkey", "cmd+shift+d", "--hidden"])
+        XCTAssertEqual(options.model, "small")
+        XCTAssertEqual(options.language, "German (DE)")
+        XCTAssertEqual(options.shortcutKeyCode, 2)
+        XCTAssertEqual(options.shortcutModifiers, CGEventFlags([.maskCommand, .maskShift]).rawValue)
+        XCTAssertTrue(options.hidden)
+        XCTAssertTrue(options.errors.isEmpty)
+    }
+
+    func test_parse_launchedAtLogin_impliesHidden() {
+        let options = LaunchOptions.parse(["VocaGlyph", "--launched-at-login"])
+        XCTAssertTrue(options.launchedAtLogin)
+        XCTAssertTrue(options.hidden)
+        XCTAssertFalse(options.isEmpty)
+    }
+
+    func test_parse_debug_defaultAndInlinePort() {
+        XCTAssertNil(LaunchOptions.parse(["VocaGlyph"]).debugPort)
+        XCTAssertEqual(LaunchOptions.parse(["VocaGlyph", "--debug", "--hidden"]).debugPort, DebugServer.defaultPort)
+        XCTAssertEqual(LaunchOptions.parse(["VocaGlyph", "--debug=7070"]).debugPort, 7070)
+
+        let invalid = LaunchOptions.parse(["VocaGlyph", "--debug=http"])
+        XCTAssertNil(invalid.debugPort)
+        XCTAssertEqual(invalid.errors, ["--debug: invalid port 'http'."])
+    }
+
+    func test_parse_replay() {
+        XCTAssertEqual(LaunchOptions.parse(["VocaGlyph", "--replay", "/tmp/take.wav"]).replayFile, "/tmp/take.wav")
+        XCTAssertEqual(LaunchOptions.parse(["VocaGlyph", "--replay=~/take.wav"]).replayFile,
+                       NSHomeDirectory() + "/take.wav")
+        XCTAssertEqual(LaunchOptions.parse(["VocaGlyph", "--replay"]).errors, ["--replay requires a value."])
+    }
+
+    func test_parse_inlineValues() {
+        let options = LaunchOptions.parse(["VocaGlyph", "--model=parakeet-v3", "--language=auto"])
+        XCTAssertEqual(options.model, "parakeet-v3")
+        XCTAssertEqual(options.language, "Auto-Detect")
+    }
+
+    func test_parse_unknownModel_reportsError() {
+        let options = LaunchOptions.parse(["VocaGlyph", "--model", "huge"])
+        XCTAssertNil(options.model)
+        XCTAssertEqual(options.errors.count, 1)
+    }
+
+    func test_parse_missingValue_reportsErrorAndKeepsParsing() {
+        let options = LaunchOptions.parse(["VocaGlyph", "--model", "--hidden"])
+        XCTAssertNil(options.model)
+        XCTAssertTrue(options.hidden)
+        XCTAssertEqual(options.errors, ["--model requires a value."])
+    }
+
+    func test_parse_ignoresUnrelatedArguments() {
+        let options = LaunchOptions.parse(["VocaGlyph", "-NSDocumentRevisionsDebugMode", "YES", "--data-dir", "/tmp/vg"])
+        XCTAssertTrue(options.isEmpty)
+        XCTAssertTrue(options.errors.isEmpty)
+    }
+
+    func test_settingsOverrides_containsOnlyGivenFlags() {
+        let options = LaunchOptions.parse(["VocaGlyph", "--language", "fr"])
+        XCTAssertEqual(options.settingsOverrides.keys.map(\.rawValue), ["dictationLanguage"])
+    }
+
+    // MARK: - parseShortcut(_:)
+
+    func test_parseShortcut_modifierOnly() {
+        let shortcut = LaunchOptions.parseShortcut("ctrl+alt")
+        XCTAssertEqual(shortcut?.keyCode, kModifierOnlyKeyCode)
+        XCTAssertEqual(shortcut?.modifiers, [.maskControl, .maskAlternate])
+    }
+
+    func test_parseShortcut_namedKey() {
+        XCTAssertEqual(LaunchOptions.parseShortcut("option+space")?.keyCode, 49)
+    }
+
+    func test_parseShortcut_noModifier_isRejected() {
+        XCTAssertNil(LaunchOptions.parseShortcut("d"))
+    }
+
+    func test_parseShortcut_twoKeys_isRejected() {
+        XCTAssertNil(LaunchOptions.parseShortcut("cmd+a+b"))
+    }
+}
@@ -0,0 +1,93 @@
+import XCTest
+@testable import VocaGlyph
+
+// MARK: - LocaleDefaultsTests
+
+final class LocaleDefaultsTests: XCTestCase {
+
+    private let suiteName = "LocaleDefaultsTests"
+    private var defaults: UserDefaults!
+
+    override func setUp() {
+        super.setUp()
+        defaults = UserDefaults(suiteName: suiteName)
+        defaults.removePersistentDomain(forName: suiteName)
+    }
+
+    override func tearDown() {
+        defaults.removePersistentDomain(forName: suiteName)
+        defaults = nil
+        super.tearDown()
+    }
+
+    // MARK: - dictationLanguage(forPreferredLanguages:)
+
+    func test_dictationLanguage_regionalVariant_mapsToSupportedLabel() {
+        XCTAssertEqual(LocaleDefaults.dictationLanguage(forPreferredLanguages: ["de-CH"]), "German (DE)")
+        XCTAssertEqual(LocaleDefaults.dictationLanguage(forPreferredLanguages: ["en-GB"]), "English (US)")
+        XCTAssertEqual(LocaleDefaults.dictationLanguage(forPreferredLanguages: ["id-ID"]), "Indonesian (ID)")
+    }
+
+    func test_dictationLanguage_skipsUnsupportedEntries() {
+        XCTAssertEqual(LocaleDefaults.dictationLanguage(forPreferredLanguages: ["zh-Hans-CN", "fr-CA"]), "French (FR)")
+    }
+
+    func test_dictationLanguage_noSupportedLanguage_autoDetects() {
+        XCTAssertEqual(LocaleDefaults.dictationLanguage(forPreferredLanguages: ["ja-JP"]), "Auto-Detect")
+        XCTAssertEqual(LocaleDefaults.dictationLanguage(forPreferredLanguages: []), "Auto-Detect")
+    }
+
+    // MARK: - recommendedModel(_:language:)
+
+    func test_recommendedModel_englishOnSmallMac_prefersEnglishParakeet() {
+        XCTAssertEqual(LocaleDefaults.recommendedModel("parakeet-v3", language: "English (US)"), "parakeet-v2")
+    }
+
+    func test_recommendedModel_nonEnglish_neverEnglishOnly() {
+        XCTAssertEqual(LocaleDefaults.recommendedModel("parakeet-v2", language: "Spanish (ES)"), "parakeet-v3")
+        XCTAssertEqual(LocaleDefaults.recommendedModel("distil-whisper_distil-large-v3", language: "Auto-Detect"), "large-v3_turbo")
+        XCTAssertEqual(LocaleDefaults.recommendedModel("large-v3_turbo", language: "German (DE)"), "large-v3_turbo")
+    }
+
+    // MARK: - applyIfNeeded
+
+    func test_applyIfNeeded_firstRun_writesLocaleLanguage() {
+        let store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+
+        let written = LocaleDefaults.applyIfNeeded(store: store, defaults: defaults, preferredLanguages: ["es-MX"])
+
+        XCTAssertEqual(written, "Spanish (ES)")
+        XCTAssertEqual(store.settings.dictationLanguage, "Spanish (ES)")
+    }
+
+    func test_applyIfNeeded_autoDetect_isStillRecordedOnce() {
+        let store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+
+        LocaleDefaults.applyIfNeeded(store: store, defaults: defaults, preferredLanguages: ["ja-JP"])
+
+        XCTAssertEqual(defaults.string(forKey: "dictationLanguage"), "Auto-Detect")
+        XCTAssertNil(LocaleDefaults.applyIfNeeded(store: store, defaults: defaults, preferredLanguages: ["de-DE"]))
+    }
+
+    func test_applyIfNeeded_existingChoice_isKept() {
+        defaults.set("French (FR)", forKey: "dictationLanguage")
+        let store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+
+        XCTAssertNil(LocaleDefaults.applyIfNeeded(store: store, defaults: defaults, preferredLanguages: ["de-DE"]))
+        XCTAssertEqual(store.settings.dictationLanguage, "French (FR)")
+    }
+
+    func test_applyIfNeeded_upgradeWithoutLanguageKey_keepsAutoDetect() {
+        defaults.set("apple-native", forKey: "selectedModel")
+        let store = SettingsStore(defaults: defaults, notificationCenter: NotificationCenter())
+
+        XCTAssertNil(LocaleDefaults.applyIfNeeded(store: store, defaults: defaults, preferredLanguages: ["de-DE"]))
+        XCTAssertNil(defaults.object(forKey: "dictationLanguage"))
+    }
+
+    func test_applyIfNeeded_onboardingStarted_isNotFirstLaunch() {
+        defaults.set(["welcome"], forKey: OnboardingCoordinator.completedStepsKey)
+
+        XCTAssertFalse(LocaleDefaults.isFirstLaunch(defaults: defaults))
+    }
+}
@@ -0,0 +1,52 @@
+import XCTest
+@testable import VocaGlyph
+
+final class LogLevelsTests: XCTestCase {
+
+    func test_parsing_overridesOnlyListedComponents() throws {
+        let levels = try LogLevels(parsing: ["audio=debug", " whisper = ERROR "], debugLogging: false)
+        XCTAssertTrue(levels.allows(.debug, for: .audio))
+        XCTAssertFalse(levels.allows(.info, for: .whisper))
+        XCTAssertTrue(levels.allows(.error, for: .whisper))
+        XCTAssertFalse(levels.allows(.debug, for: .hotkey))
+        XCTAssertTrue(levels.allows(.info, for: .hotkey))
+    }
+
+    func test_parsing_debugLoggingLowersTheDefault() throws {
+        let levels = try LogLevels(parsing: [], debugLogging: true)
+        XCTAssertTrue(levels.allows(.debug, for: .model))
+    }
+
+    func test_parsing_rejectsBadLines() {
+        XCTAssertThrowsError(try LogLevels(parsing: ["audio"], debugLogging: false)) { error in
+            XCTAssertEqual(error as? LogLevels.ParseError, .malformed("audio"))
+        }
+        XCTAssertThrowsError(try LogLevels(parsing: ["gpu=debug"], debugLogging: false)) { error in
+            XCTAssertEqual(error as? LogLevels.ParseError, .unknownComponent("gpu"))
+        }
+        XCTAssertThrowsError(try LogLevels(parsing: ["audio=trace"], debugLogging: false)) { error in
+            XCTAssertEqual(error as? LogLevels.ParseError, .unknownLevel("trace"))
+        }
+    }
+
+    func test_settings_skipInvalidLines() {
+        var settings = AppSettings.defaults
+        settings.logLevels = ["gpu=debug", "output=error"]
+        let levels = LogLevels(settings: settings)
+        XCTAssertEqual(levels.overrides, [.output: .error])
+        XCTAssertEqual(levels.defaultLevel, .info)
+    }
+
+    func test_lines_roundTripInComponentOrder() throws {
+        let levels = LogLevels(defaultLevel: .info, overrides: [.output: .error, .audio: .debug])
+        XCTAssertEqual(levels.lines, ["audio=debug", "output=error"])
+        XCTAssertEqual(try LogLevels(parsing: levels.lines, debugLogging: false), levels)
+    }
+
+    func test_component_comesFromTheMessagePrefix() {
+        XCTAssertEqual(LogComponent.component(forMessage: "WhisperService: Loaded"), .whisper)
+        XCTAssertEqual(LogComponent.component(forMessage: "HotkeyService: Restarting (wake)"), .hotkey)
+        XCTAssertEqual(LogComponent.component(forMessage: "AppDelegate: Ready"), .app)
+        XCTAssertEqual(LogComponent.component(forMessage: "=== Application Started ==="), .app)
+    }
+}
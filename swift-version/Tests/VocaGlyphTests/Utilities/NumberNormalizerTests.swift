@@ -0,0 +1,83 @@
+import XCTest
+@testable import VocaGlyph
+
+final class NumberNormalizerTests: XCTestCase {
+
+    private func english(_ text: String) -> String {
+        NumberNormalizer.normalize(text, language: .english)
+    }
+
+    private func spanish(_ text: String) -> String {
+        NumberNormalizer.normalize(text, language: .spanish)
+    }
+
+    // MARK: - Cardinals & Ordinals
+
+    func testCardinals() {
+        XCTAssertEqual(english("twenty five people"), "25 people")
+        XCTAssertEqual(english("twenty-five people"), "25 people")
+        XCTAssertEqual(english("one hundred and five"), "105")
+        XCTAssertEqual(english("three thousand four hundred twelve"), "3412")
+        XCTAssertEqual(english("two million"), "2000000")
+    }
+
+    func testOrdinals() {
+        XCTAssertEqual(english("the twenty first floor"), "the 21st floor")
+        XCTAssertEqual(english("our twelfth try"), "our 12th try")
+        XCTAssertEqual(english("the one hundredth visitor"), "the 100th visitor")
+    }
+
+    func testLoneSmallNumbersStayWords() {
+        XCTAssertEqual(english("one of them was first"), "one of them was first")
+        XCTAssertEqual(english("wait a second"), "wait a second")
+    }
+
+    // MARK: - Years & Times
+
+    func testYears() {
+        XCTAssertEqual(english("back in nineteen eighty four"), "back in 1984")
+        XCTAssertEqual(english("twenty twenty five"), "2025")
+        XCTAssertEqual(english("nineteen oh five"), "1905")
+        XCTAssertEqual(english("two thousand and twenty five"), "2025")
+    }
+
+    func testTimes() {
+        XCTAssertEqual(english("meet at three thirty pm"), "meet at 3:30 PM")
+        XCTAssertEqual(english("three oh five p.m. sharp"), "3:05 PM sharp")
+        XCTAssertEqual(english("seven am"), "7 AM")
+        XCTAssertEqual(english("seven o'clock"), "7:00")
+    }
+
+    // MARK: - Dates
+
+    func testDates() {
+        XCTAssertEqual(english("march third twenty twenty five"), "March 3, 2025")
+        XCTAssertEqual(english("due june fifth"), "due June 5")
+        XCTAssertEqual(english("on the third of march"), "on March 3")
+    }
+
+    func testMonthNamesWithoutADayAreLeftAlone() {
+        XCTAssertEqual(english("we march ten miles"), "we march 10 miles")
+        XCTAssertEqual(english("you may go"), "you may go")
+    }
+
+    // MARK: - Languages
+
+    func testSpanish() {
+        XCTAssertEqual(spanish("tres de marzo de dos mil veinticinco"), "3 de marzo de 2025")
+        XCTAssertEqual(spanish("ciento treinta y dos libros"), "132 libros")
+        XCTAssertEqual(spanish("primero de mayo"), "1 de mayo")
+        XCTAssertEqual(spanish("un libro y dos lápices"), "un libro y dos lápices")
+    }
+
+    func testLanguageSelection() {
+        XCTAssertEqual(NumberNormalizer.Language.forCode(nil)?.code, "en")
+        XCTAssertEqual(NumberNormalizer.Language.forCode("es")?.code, "es")
+        XCTAssertNil(NumberNormalizer.Language.forCode("de"))
+    }
+
+    func testProcessorPassesUnsupportedLanguagesThrough() async throws {
+        let result = try await NumberNormalizationProcessor(language: nil).process("vingt-cinq")
+        XCTAssertEqual(result, "vingt-cinq")
+    }
+}
@@ -0,0 +1,63 @@
+import XCTest
+@testable import VocaGlyph
+
+final class OutputStyleTests: XCTestCase {
+
+    // MARK: - Spoken Commands
+
+    func testSpokenCommandIsSplitOff() {
+        let command = OutputStyle.spokenCommand(in: "Bullet list: milk, eggs and bread")
+        XCTAssertEqual(command?.style, .bullets)
+        XCTAssertEqual(command?.remainder, "milk, eggs and bread")
+
+        XCTAssertEqual(OutputStyle.spokenCommand(in: "code comment this needs a lock")?.style, .codeComment)
+    }
+
+    func testOrdinaryDictationIsNotACommand() {
+        XCTAssertNil(OutputStyle.spokenCommand(in: "Email John about the bullet list"))
+        XCTAssertNil(OutputStyle.spokenCommand(in: "bulletsproof vests"))
+        XCTAssertNil(OutputStyle.spokenCommand(in: "Bullets were flying everywhere"))
+    }
+
+    // MARK: - Formatting
+
+    func testPlainLeavesTextAlone() {
+        XCTAssertEqual(OutputStyle.plain.apply("hi there, bob"), "hi there, bob")
+    }
+
+    func testEmailWritesFullSentences() {
+        let text = "hi sam. the report is ready, let me know what you think\nthanks"
+        XCTAssertEqual(OutputStyle.email.apply(text),
+                       "Hi sam. The report is ready, let me know what you think.\n\nThanks.")
+    }
+
+    func testBulletsSplitSentencesAndLines() {
+        XCTAssertEqual(OutputStyle.bullets.apply("Buy milk. call mom\nwalk the dog."),
+                       "- Buy milk\n- Call mom\n- Walk the dog")
+    }
+
+    func testBulletsSplitASingleCommaSeparatedSentence() {
+        XCTAssertEqual(OutputStyle.bullets.apply("Milk, eggs and bread."), "- Milk\n- Eggs\n- Bread")
+    }
+
+    func testCodeCommentPrefixesAndWrapsLines() {
+        XCTAssertEqual(OutputStyle.codeComment.apply("Retry once.\n\nThen give up."),
+                       "// Retry once.\n//\n// Then give up.")
+
+        let long = Array(repeating: "word", count: 30).joined(separator: " ")
+        let lines = OutputStyle.codeComment.apply(long).components(separatedBy: "\n")
+        XCTAssertGreaterThan(lines.count, 1)
+        XCTAssertTrue(lines.allSatisfy { $0.hasPrefix("// ") && $0.count <= OutputStyle.commentWidth })
+    }
+
+    // MARK: - Pipeline
+
+    func testStyleStageRunsAfterCleanupAndBeforeProfanity() {
+        var settings = AppSettings()
+        settings.outputStyle = OutputStyle.bullets.rawValue
+        settings.profanityFilter = ProfanityFilter.Mode.mask.rawValue
+        let stages = TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil)
+            .stages.map(\.stage)
+        XCTAssertEqual(Array(stages.suffix(2)), [.outputStyle, .profanity])
+    }
+}
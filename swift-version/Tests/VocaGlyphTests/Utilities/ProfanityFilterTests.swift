@@ -0,0 +1,41 @@
+import XCTest
+@testable import VocaGlyph
+
+final class ProfanityFilterTests: XCTestCase {
+
+    func testOffLeavesTextUntouched() {
+        XCTAssertEqual(ProfanityFilter.filter("well shit", mode: .off), "well shit")
+    }
+
+    func testMaskKeepsFirstLetter() {
+        XCTAssertEqual(ProfanityFilter.filter("this is shit", mode: .mask), "this is s***")
+        XCTAssertEqual(ProfanityFilter.filter("Damn it", mode: .mask), "D*** it")
+    }
+
+    func testDropRemovesWordAndSpace() {
+        XCTAssertEqual(ProfanityFilter.filter("oh shit, no", mode: .drop), "oh, no")
+        XCTAssertEqual(ProfanityFilter.filter("Damn it works", mode: .drop), "it works")
+    }
+
+    func testInflectionsAreMatched() {
+        XCTAssertEqual(ProfanityFilter.filter("the fucking printer", mode: .mask), "the f****** printer")
+        XCTAssertEqual(ProfanityFilter.filter("bitches", mode: .mask), "b******")
+    }
+
+    func testWordsInsideOtherWordsAreLeftAlone() {
+        let text = "Scunthorpe will assess the class"
+        XCTAssertEqual(ProfanityFilter.filter(text, mode: .mask), text)
+    }
+
+    func testCustomWords() {
+        XCTAssertEqual(ProfanityFilter.filter("what the heck", mode: .mask, customWords: ["heck"]), "what the h***")
+        XCTAssertEqual(ProfanityFilter.filter("what the heck", mode: .mask), "what the heck")
+    }
+
+    func testSettingsOverload() {
+        var settings = AppSettings()
+        settings.profanityFilter = ProfanityFilter.Mode.drop.rawValue
+        settings.profanityCustomWords = ["frak"]
+        XCTAssertEqual(ProfanityFilter.filter("frak this", settings: settings), "this")
+    }
+}
@@ -0,0 +1,24 @@
+import XCTest
+@testable import VocaGlyph
+
+final class PunctuationRestorerTests: XCTestCase {
+
+    func testNeedsRestorationOnlyForLongUnpunctuatedText() {
+        XCTAssertTrue(PunctuationRestorer.needsRestoration("we should ship it on monday and then tell the team"))
+        // A closing period alone doesn't count as punctuated.
+        XCTAssertTrue(PunctuationRestorer.needsRestoration("we should ship it on monday and then tell the team."))
+        XCTAssertFalse(PunctuationRestorer.needsRestoration("We should ship it on Monday, then tell the team."))
+        XCTAssertFalse(PunctuationRestorer.needsRestoration("ship it on monday"))
+    }
+
+    func testValidatedAllowsPunctuationAndCaseChanges() {
+        XCTAssertEqual(PunctuationRestorer.validated(" Dont worry, it's fine. ", against: "dont worry its fine"),
+                       "Dont worry, it's fine.")
+    }
+
+    func testValidatedRejectsChangedWords() {
+        XCTAssertNil(PunctuationRestorer.validated("Don't worry, it is fine.", against: "dont worry its fine"))
+        XCTAssertNil(PunctuationRestorer.validated("Here you go: dont worry its fine", against: "dont worry its fine"))
+        XCTAssertNil(PunctuationRestorer.validated("", against: "dont worry its fine"))
+    }
+}
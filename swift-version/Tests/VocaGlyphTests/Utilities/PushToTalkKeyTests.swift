@@ -0,0 +1,32 @@
+import XCTest
+@testable import VocaGlyph
+
+final class PushToTalkKeyTests: XCTestCase {
+
+    private var clock: CFAbsoluteTime = 1_000
+    private var key: PushToTalkKey!
+
+    override func setUp() {
+        super.setUp()
+        key = PushToTalkKey(now: { [unowned self] in self.clock })
+    }
+
+    func testTapStartsAndTheNextPressStops() {
+        XCTAssertEqual(key.keyDown(isRecording: false), .start)
+        clock += 0.1
+        XCTAssertNil(key.keyUp())
+
+        XCTAssertEqual(key.keyDown(isRecording: true), .stop)
+        XCTAssertNil(key.keyUp())
+    }
+
+    func testHoldIsPushToTalk() {
+        XCTAssertEqual(key.keyDown(isRecording: false), .start)
+        clock += PushToTalkKey.holdThreshold
+        XCTAssertEqual(key.keyUp(), .stop)
+    }
+
+    func testStrayReleaseDoesNothing() {
+        XCTAssertNil(key.keyUp())
+    }
+}
@@ -0,0 +1,38 @@
+import XCTest
+@testable import VocaGlyph
+
+final class ResourceUsageTests: XCTestCase {
+
+    private let gb: Int64 = 1_073_741_824
+
+    private func usage(footprint: Int64, model: ResourceUsage.Model?) -> ResourceUsage {
+        ResourceUsage(residentBytes: footprint, footprintBytes: footprint, physicalMemoryBytes: 16 * gb,
+                      model: model, audioBuffer: .empty, gpu: nil)
+    }
+
+    func testComfortableModelSizeLeavesHalfTheRAM() {
+        XCTAssertEqual(usage(footprint: 1 * gb, model: nil).comfortableModelBytes, 7 * gb)
+    }
+
+    func testLoadedModelDoesNotCountAgainstItsReplacement() {
+        let loaded = ResourceUsage.Model(id: "large-v3", bytes: 3 * gb, isLoaded: true)
+        XCTAssertEqual(usage(footprint: 4 * gb, model: loaded).comfortableModelBytes, 7 * gb)
+
+        let unloaded = ResourceUsage.Model(id: "large-v3", bytes: 3 * gb, isLoaded: false)
+        XCTAssertEqual(usage(footprint: 4 * gb, model: unloaded).comfortableModelBytes, 4 * gb)
+    }
+
+    func testComfortableModelSizeIsNeverNegative() {
+        XCTAssertEqual(usage(footprint: 12 * gb, model: nil).comfortableModelBytes, 0)
+    }
+
+    func testSampleReadsThisProcess() throws {
+        let memory = try XCTUnwrap(ResourceUsage.processMemory())
+        XCTAssertGreaterThan(memory.resident, 0)
+        XCTAssertGreaterThan(memory.footprint, 0)
+
+        let sample = ResourceUsage.sample(model: nil, audioBuffer: .empty)
+        XCTAssertEqual(sample.physicalMemoryBytes, Int64(ProcessInfo.processInfo.physicalMemory))
+        XCTAssertEqual(sample.audioBuffer, .empty)
+    }
+}
@@ -0,0 +1,27 @@
+import XCTest
+@testable import VocaGlyph
+
+final class SelectionRewriterTests: XCTestCase {
+
+    func testRewriteSendsSelectionWithInstruction() async {
+        let engine = MockPostProcessingEngine()
+        engine.returnedText = "  Thank you for the update.\n"
+        let result = await SelectionRewriter.rewrite("thx for the update", instruction: "make this more formal", engine: engine)
+
+        XCTAssertEqual(result, "Thank you for the update.")
+        XCTAssertEqual(engine.didCallRefineWithText, "thx for the update")
+        XCTAssertTrue(engine.didCallRefineWithPrompt?.contains("make this more formal") ?? false)
+    }
+
+    func testRewriteReturnsNilOnFailureOrEmptyOutput() async {
+        let failing = MockPostProcessingEngine()
+        failing.shouldThrowError = true
+        let failed = await SelectionRewriter.rewrite("text", instruction: "shorten", engine: failing)
+        XCTAssertNil(failed)
+
+        let empty = MockPostProcessingEngine()
+        empty.returnedText = ""
+        let blank = await SelectionRewriter.rewrite("text", instruction: "shorten", engine: empty)
+        XCTAssertNil(blank)
+    }
+}
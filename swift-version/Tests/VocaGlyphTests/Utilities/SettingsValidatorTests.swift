@@ -0,0 +1,259 @@
+import XCTest
+import CoreGraphics
+@testable import VocaGlyph
+
+// MARK: - SettingsValidatorTests
+
+final class SettingsValidatorTests: XCTestCase {
+
+    private func fields(_ issues: [SettingsValidationIssue]) -> [String] {
+        issues.map(\.field)
+    }
+
+    // MARK: - Typed settings
+
+    func test_validate_defaults_hasNoIssues() {
+        XCTAssertTrue(SettingsValidator.validate(AppSettings.defaults).isEmpty)
+    }
+
+    func test_validate_shortcutWithoutModifiers_reportsModifiersField() {
+        var settings = AppSettings.defaults
+        settings.shortcutModifiers = 0
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["customShortcutModifiers"])
+    }
+
+    func test_validate_outOfRangeKeyCode_reportsKeyCodeField() {
+        var settings = AppSettings.defaults
+        settings.shortcutKeyCode = 500
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["customShortcutKeyCode"])
+    }
+
+    func test_validate_modifierOnlyShortcut_isValid() {
+        var settings = AppSettings.defaults
+        settings.shortcutKeyCode = Int(kModifierOnlyKeyCode)
+        settings.shortcutModifiers = CGEventFlags([.maskCommand, .maskAlternate]).rawValue
+        XCTAssertTrue(SettingsValidator.validate(settings).isEmpty)
+    }
+
+    func test_validate_unknownModel_reportsSelectedModel() {
+        var settings = AppSettings.defaults
+        settings.selectedModel = "gpt-whisper-9000"
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["selectedModel"])
+    }
+
+    func test_validate_knownModelNotDownloaded_reportsSelectedModel() {
+        var settings = AppSettings.defaults
+        settings.selectedModel = "large-v3_turbo"
+        XCTAssertEqual(fields(SettingsValidator.validate(settings, downloadedModels: [])), ["selectedModel"])
+        XCTAssertTrue(SettingsValidator.validate(settings, downloadedModels: ["large-v3_turbo"]).isEmpty)
+    }
+
+    func test_validate_appleNative_needsNoDownload() {
+        XCTAssertTrue(SettingsValidator.validate(AppSettings.defaults, downloadedModels: []).isEmpty)
+    }
+
+    func test_validate_unsupportedLanguage_reportsLanguage() {
+        var settings = AppSettings.defaults
+        settings.dictationLanguage = "Klingon"
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["dictationLanguage"])
+    }
+
+    func test_validate_unknownPostProcessingValues_reportsBothFields() {
+        var settings = AppSettings.defaults
+        settings.selectedTaskModel = "gpt"
+        settings.selectedCloudProvider = "mistral"
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["selectedTaskModel", "selectedCloudProvider"])
+    }
+
+    func test_validate_openAICompatibleEndpoint_mustBeHTTPURL() {
+        var settings = AppSettings.defaults
+        settings.selectedCloudProvider = "openai"
+        settings.openAICompatibleEndpoint = "api.openai.com/v1"
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["openAICompatibleEndpoint"])
+        settings.openAICompatibleEndpoint = "http://localhost:11434/v1"
+        XCTAssertTrue(SettingsValidator.validate(settings).isEmpty)
+    }
+
+    func test_validate_voiceCommandsWithoutPrefix_reportsPrefix() {
+        var settings = AppSettings.defaults
+        settings.voiceCommandPrefix = " , "
+        XCTAssertTrue(SettingsValidator.validate(settings).isEmpty)
+        settings.voiceCommandsEnabled = true
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["voiceCommandPrefix"])
+    }
+
+    func test_validate_appProcessingRules_reportsInvalidAndDuplicateLines() {
+        var settings = AppSettings.defaults
+        settings.appProcessingRules = ["md.obsidian: style=bullets"]
+        XCTAssertTrue(SettingsValidator.validate(settings).isEmpty)
+        settings.appProcessingRules += ["md.obsidian: -llmCleanup", "com.microsoft.VSCode: -markdown"]
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["appProcessingRules", "appProcessingRules"])
+    }
+
+    func test_validate_contextLimitOutOfRange_reportsLimitField() {
+        var settings = AppSettings.defaults
+        settings.contextCaptureCharacterLimit = 0
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["contextCaptureCharacterLimit"])
+    }
+
+    func test_validate_blankExcludedApp_reportsExcludedAppsField() {
+        var settings = AppSettings.defaults
+        settings.contextCaptureExcludedApps = ["com.apple.Safari", " "]
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["contextCaptureExcludedApps"])
+    }
+
+    func test_validate_concurrentDownloadsOutOfRange_reportsField() {
+        var settings = AppSettings.defaults
+        settings.maxConcurrentDownloads = 0
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["maxConcurrentDownloads"])
+    }
+
+    func test_validate_unknownHistoryRetentionAndZeroDays_reportsFields() {
+        var settings = AppSettings.defaults
+        settings.historyRetention = "forever"
+        settings.historyRetentionDays = 0
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["historyRetention", "historyRetentionDays"])
+    }
+
+    func test_validate_unknownRedactionRuleAndInvalidPattern_reportsFields() {
+        var settings = AppSettings.defaults
+        settings.redactionRules = ["creditCard", "passport"]
+        settings.redactionCustomPatterns = ["[unclosed"]
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["redactionRules", "redactionCustomPatterns"])
+    }
+
+    func test_validate_incognitoShortcutSameAsDictation_reportsField() {
+        var settings = AppSettings.defaults
+        settings.incognitoShortcutKeyCode = settings.shortcutKeyCode
+        settings.incognitoShortcutModifiers = settings.shortcutModifiers
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["incognitoShortcutKeyCode"])
+
+        settings.incognitoShortcutKeyCode = 34 // I
+        XCTAssertTrue(SettingsValidator.validate(settings).isEmpty)
+    }
+
+    func test_validate_remoteTranslationWithoutTargetOrEndpoint_reportsFields() {
+        var settings = AppSettings.defaults
+        settings.translationProvider = TranslationProvider.remote.rawValue
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["translationTargetLanguage", "translationEndpoint"])
+
+        settings.translationTargetLanguage = "pt-BR"
+        settings.translationEndpoint = "http://localhost:5000"
+        XCTAssertTrue(SettingsValidator.validate(settings).isEmpty)
+    }
+
+    func test_validate_unknownProfanityMode_reportsField() {
+        var settings = AppSettings.defaults
+        settings.profanityFilter = "bleep"
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["profanityFilter"])
+    }
+
+    func test_validate_unknownTrayIconTheme_reportsField() {
+        var settings = AppSettings.defaults
+        settings.trayIconTheme = "neon"
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["trayIconTheme"])
+    }
+
+    func test_validate_quitWaitOutOfRange_reportsField() {
+        var settings = AppSettings.defaults
+        settings.quitWaitSeconds = 600
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["quitWaitSeconds"])
+    }
+
+    func test_validate_processingTimeout_allowsOffOrRange() {
+        var settings = AppSettings.defaults
+        settings.processingTimeoutSeconds = 0
+        XCTAssertTrue(SettingsValidator.validate(settings).isEmpty)
+        settings.processingTimeoutSeconds = 5
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["processingTimeoutSeconds"])
+    }
+
+    func test_validate_idleReductionMinutesOutOfRange_reportsField() {
+        var settings = AppSettings.defaults
+        settings.idleReductionMinutes = 0
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["idleReductionMinutes"])
+    }
+
+    func test_validate_liveCaptionsPortOutOfRange_reportsField() {
+        var settings = AppSettings.defaults
+        settings.liveCaptionsPort = 80
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["liveCaptionsPort"])
+    }
+
+    func test_validate_unknownLogComponent_reportsField() {
+        var settings = AppSettings.defaults
+        settings.logLevels = ["audio=debug", "gpu=error"]
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["logLevels"])
+    }
+
+    func test_validate_unknownTranscriptLogging_reportsField() {
+        var settings = AppSettings.defaults
+        settings.transcriptLogging = "encrypted"
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["transcriptLogging"])
+    }
+
+    func test_validate_otlpEndpoint_mustBeHTTPURLWhenEnabled() {
+        var settings = AppSettings.defaults
+        settings.otlpEndpoint = "localhost:4318"
+        XCTAssertTrue(SettingsValidator.validate(settings).isEmpty)
+        settings.otlpExportEnabled = true
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["otlpEndpoint"])
+        settings.otlpEndpoint = "https://otel.example.com:4318"
+        XCTAssertTrue(SettingsValidator.validate(settings).isEmpty)
+    }
+
+    func test_validate_whisperCpp_needsAModelFile() {
+        var settings = AppSettings.defaults
+        settings.selectedModel = WhisperCppEngine.modelID
+        XCTAssertEqual(fields(SettingsValidator.validate(settings, downloadedModels: [])), ["whisperCppModelPath"])
+        settings.whisperCppModelPath = "/models/ggml-base.en.bin"
+        XCTAssertTrue(SettingsValidator.validate(settings, downloadedModels: []).isEmpty)
+    }
+
+    func test_validate_summaryMinimumOutOfRange_reportsField() {
+        var settings = AppSettings.defaults
+        settings.summaryMinimumSeconds = 5
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["summaryMinimumSeconds"])
+    }
+
+    func test_validate_maxRecordingOutOfRange_reportsField() {
+        var settings = AppSettings.defaults
+        settings.maxRecordingSeconds = 10
+        XCTAssertEqual(fields(SettingsValidator.validate(settings)), ["maxRecordingSeconds"])
+    }
+
+    func test_validate_noRecordingLimit_isValid() {
+        var settings = AppSettings.defaults
+        settings.maxRecordingSeconds = 0
+        XCTAssertTrue(SettingsValidator.validate(settings).isEmpty)
+    }
+
+    // MARK: - JSON
+
+    func test_validateJSON_validObject_hasNoIssues() {
+        let json = #"{"selectedModel": "apple-native", "autoPunctuation": false, "customShortcutKeyCode": 49}"#
+        XCTAssertTrue(SettingsValidator.validate(json: Data(json.utf8)).isEmpty)
+    }
+
+    func test_validateJSON_notAnObject_reportsRootIssue() {
+        let issues = SettingsValidator.validate(json: Data("[1, 2]".utf8))
+        XCTAssertEqual(issues.count, 1)
+        XCTAssertEqual(issues.first?.field, "")
+    }
+
+    func test_validateJSON_wrongTypes_reportsEachField() {
+        let json = #"{"autoPunctuation": "yes", "customShortcutKeyCode": true, "hotkeyBackend": "carbon"}"#
+        let issues = SettingsValidator.validate(json: Data(json.utf8))
+        XCTAssertEqual(Set(fields(issues)), ["autoPunctuation", "customShortcutKeyCode", "hotkeyBackend"])
+    }
+
+    func test_validateJSON_unknownKey_isReported() {
+        let issues = SettingsValidator.validate(json: Data(#"{"outputMode": "paste"}"#.utf8))
+        XCTAssertEqual(fields(issues), ["outputMode"])
+    }
+
+    func test_validateJSON_semanticErrorsAreIncluded() {
+        let issues = SettingsValidator.validate(json: Data(#"{"dictationLanguage": "Klingon"}"#.utf8))
+        XCTAssertEqual(fields(issues), ["dictationLanguage"])
+    }
+}
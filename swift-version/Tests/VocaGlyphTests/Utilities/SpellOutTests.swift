@@ -0,0 +1,47 @@
+import XCTest
+@testable import VocaGlyph
+
+final class SpellOutTests: XCTestCase {
+
+    // MARK: - Spoken Command
+
+    func testSpokenCommandSplitsOffRemainder() {
+        XCTAssertEqual(SpellOut.spokenCommand(in: "Spell out: alpha bravo seven."), "alpha bravo seven.")
+        XCTAssertEqual(SpellOut.spokenCommand(in: "spelling mode, X-ray nine"), "X-ray nine")
+        XCTAssertNil(SpellOut.spokenCommand(in: "Alpha bravo seven"))
+        XCTAssertNil(SpellOut.spokenCommand(in: "Spellings are hard"))
+        XCTAssertNil(SpellOut.spokenCommand(in: "Spelling mistakes crept into the draft"))
+        XCTAssertNil(SpellOut.spokenCommand(in: "Spell mode is what I'd call it"))
+    }
+
+    // MARK: - Decoding
+
+    func testNatoAlphabetAndDigits() {
+        XCTAssertEqual(SpellOut.decode("alpha bravo seven"), "AB7")
+        XCTAssertEqual(SpellOut.decode("Alpha, Bravo, 7."), "AB7")
+        XCTAssertEqual(SpellOut.decode("x-ray niner zero"), "X90")
+    }
+
+    func testCaseModifiers() {
+        XCTAssertEqual(SpellOut.decode("lowercase alpha capital bravo charlie"), "aBC")
+        XCTAssertEqual(SpellOut.decode("small k one"), "k1")
+    }
+
+    func testRepeats() {
+        XCTAssertEqual(SpellOut.decode("double seven triple zero"), "77000")
+    }
+
+    func testEmailAndURL() {
+        XCTAssertEqual(SpellOut.decode("john at example dot com"), "john@example.com")
+        XCTAssertEqual(SpellOut.decode("example dot com forward slash docs underscore v two"),
+                       "example.com/docs_V2")
+    }
+
+    func testUnknownWordsKeepTheirCase() {
+        XCTAssertEqual(SpellOut.decode("AB7 dash Key"), "AB7-Key")
+    }
+
+    func testSpaceIsOnlyAddedWhenSpoken() {
+        XCTAssertEqual(SpellOut.decode("alpha space bravo"), "A B")
+    }
+}
@@ -94,4 +94,41 @@ final class TemplatePromptRendererTests: XCTestCase {
 
         XCTAssertFalse(TemplatePromptRenderer.isOverRecommendedLength(template: template))
     }
+
+    // MARK: - Context Template
+
+    func testDefaultContextTemplateFillsAllPlaceholders() {
+        let output = TemplatePromptRenderer.appendingContext(
+            "Hi team,", to: "Fix grammar.",
+            customVocabulary: ["Kubernetes", "VocaGlyph"], language: "German"
+        )
+
+        XCTAssertTrue(output.hasPrefix("Fix grammar.\n\nThe transcription is in German."))
+        XCTAssertTrue(output.contains("exactly as written: Kubernetes, VocaGlyph."))
+        XCTAssertTrue(output.contains("<context>\nHi team,\n</context>"))
+    }
+
+    func testParagraphsWithEmptyPlaceholdersAreLeftOut() {
+        let output = TemplatePromptRenderer.renderContextTemplate(
+            TemplatePromptRenderer.defaultContextTemplate,
+            context: nil, customVocabulary: [], language: "English"
+        )
+        XCTAssertEqual(output, "The transcription is in English.")
+
+        let nothing = TemplatePromptRenderer.appendingContext(nil, to: "Fix grammar.")
+        XCTAssertEqual(nothing, "Fix grammar.")
+    }
+
+    func testCustomTemplateIsUsed() {
+        let output = TemplatePromptRenderer.appendingContext(
+            "previous {language} text", to: "Fix grammar.",
+            template: "Language: {language}\nBefore: {context}", language: "Spanish"
+        )
+        XCTAssertEqual(output, "Fix grammar.\n\nLanguage: Spanish\nBefore: previous {language} text")
+    }
+
+    func testUnknownPlaceholdersAreReported() {
+        XCTAssertEqual(TemplatePromptRenderer.unknownPlaceholders(in: "{context} {vocab} {language}"), ["{vocab}"])
+        XCTAssertTrue(TemplatePromptRenderer.unknownPlaceholders(in: TemplatePromptRenderer.defaultContextTemplate).isEmpty)
+    }
 }
@@ -0,0 +1,60 @@
+import XCTest
+@testable import VocaGlyph
+
+final class TermCorrectorTests: XCTestCase {
+
+    private func correct(_ text: String, _ terms: [String]) -> String {
+        TermCorrector.correct(text, terms: terms)
+    }
+
+    // MARK: - Matching
+
+    func testSoundAlikeRunsBecomeTheTerm() {
+        XCTAssertEqual(correct("deploy it on cooper netties today", ["Kubernetes"]),
+                       "deploy it on Kubernetes today")
+        XCTAssertEqual(correct("the postcrass database", ["Postgres"]), "the Postgres database")
+    }
+
+    func testCloseSpellingsAndSplitWords() {
+        XCTAssertEqual(correct("push it to get hub.", ["GitHub"]), "push it to GitHub.")
+        XCTAssertEqual(correct("ask vocaglif", ["VocaGlyph"]), "ask VocaGlyph")
+    }
+
+    func testNeighbouringWordsAreNotSwallowed() {
+        XCTAssertEqual(correct("a kubernetes cluster", ["Kubernetes"]), "a Kubernetes cluster")
+        XCTAssertEqual(correct("tensorflow is fast", ["TensorFlow"]), "TensorFlow is fast")
+    }
+
+    func testUnrelatedWordsAreLeftAlone() {
+        XCTAssertEqual(correct("cheer up, we went to the cafe", ["Jira", "Kafka"]),
+                       "cheer up, we went to the cafe")
+    }
+
+    func testRunsDoNotSpanPunctuation() {
+        XCTAssertEqual(correct("get, hub", ["GitHub"]), "get, hub")
+    }
+
+    // MARK: - Helpers
+
+    func testPhoneticKeyIgnoresSpellingOfTheSameSounds() {
+        XCTAssertEqual(TermCorrector.phoneticKey("coopernetties"), TermCorrector.phoneticKey("kubernetes"))
+        XCTAssertNotEqual(TermCorrector.phoneticKey("doctor"), TermCorrector.phoneticKey("docker"))
+    }
+
+    func testLevenshtein() {
+        XCTAssertEqual(TermCorrector.levenshtein("kitten", "sitting"), 3)
+        XCTAssertEqual(TermCorrector.levenshtein("", "abc"), 3)
+    }
+
+    // MARK: - Pipeline
+
+    func testStageNeedsTerms() {
+        var settings = AppSettings()
+        XCTAssertFalse(TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil)
+            .stages.map(\.stage).contains(.termCorrection))
+
+        settings.customTerms = ["Kubernetes"]
+        XCTAssertTrue(TextProcessingPipeline.standard(settings: settings, replacements: [], cleanup: nil)
+            .stages.map(\.stage).contains(.termCorrection))
+    }
+}
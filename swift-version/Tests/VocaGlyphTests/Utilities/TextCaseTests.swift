@@ -0,0 +1,45 @@
+import XCTest
+@testable import VocaGlyph
+
+final class TextCaseTests: XCTestCase {
+
+    // MARK: - Conversion
+
+    func testConversions() {
+        let text = "Ship the new login flow"
+        XCTAssertEqual(TextCase.upper.apply(text), "SHIP THE NEW LOGIN FLOW")
+        XCTAssertEqual(TextCase.lower.apply(text), "ship the new login flow")
+        XCTAssertEqual(TextCase.title.apply(text), "Ship The New Login Flow")
+        XCTAssertEqual(TextCase.snake.apply(text), "ship_the_new_login_flow")
+        XCTAssertEqual(TextCase.camel.apply(text), "shipTheNewLoginFlow")
+        XCTAssertEqual(TextCase.kebab.apply(text), "ship-the-new-login-flow")
+    }
+
+    func testIdentifierCasesDropPunctuation() {
+        XCTAssertEqual(TextCase.snake.apply("User ID, v2."), "user_id_v2")
+    }
+
+    func testTitleCaseKeepsInnerCapitals() {
+        XCTAssertEqual(TextCase.title.apply("my iPhone and NASA"), "My iPhone And NASA")
+    }
+
+    // MARK: - Spoken Commands
+
+    func testCommandsWithReference() {
+        XCTAssertEqual(TextCase.command(in: "Make that title case."), .title)
+        XCTAssertEqual(TextCase.command(in: "all caps that"), .upper)
+        XCTAssertEqual(TextCase.command(in: "Change it to snake case, please"), .snake)
+        XCTAssertEqual(TextCase.command(in: "make this camel-case"), .camel)
+    }
+
+    func testBareNamesNeedPrefix() {
+        XCTAssertNil(TextCase.command(in: "title case"))
+        XCTAssertEqual(TextCase.command(in: "title case", requireReference: false), .title)
+        XCTAssertEqual(VoiceCommand.parse("Computer, all caps.", prefix: "computer"), .changeCase(.upper))
+    }
+
+    func testOrdinaryDictationIsNotACommand() {
+        XCTAssertNil(TextCase.command(in: "make that change before the title case review"))
+        XCTAssertNil(TextCase.command(in: "I like that"))
+    }
+}
@@ -0,0 +1,51 @@
+import XCTest
+@testable import VocaGlyph
+
+final class TextRedactorTests: XCTestCase {
+
+    func test_creditCard_groupedNumberPassingLuhn_isRedacted() {
+        let result = TextRedactor.redact("My card is 4111 1111 1111 1111, expiry soon", rules: [.creditCard])
+        XCTAssertEqual(result, "My card is [credit card], expiry soon")
+    }
+
+    func test_creditCard_numberFailingLuhn_isKept() {
+        let text = "Order 4111 1111 1111 1112 shipped"
+        XCTAssertEqual(TextRedactor.redact(text, rules: [.creditCard]), text)
+    }
+
+    func test_creditCard_shortNumbers_areKept() {
+        let text = "Call 555-123-4567 at 10"
+        XCTAssertEqual(TextRedactor.redact(text, rules: [.creditCard]), text)
+    }
+
+    func test_email_isRedactedCaseInsensitively() {
+        let result = TextRedactor.redact("Write to Jane.Doe@Example.COM today", rules: [.email])
+        XCTAssertEqual(result, "Write to [email] today")
+    }
+
+    func test_customPatterns_areRedacted_andInvalidOnesSkipped() {
+        let result = TextRedactor.redact("Ticket acme-123456 is open", rules: [], customPatterns: [#"\bACME-\d{6}\b"#, "("])
+        XCTAssertEqual(result, "Ticket [redacted] is open")
+    }
+
+    func test_settings_disabled_leavesTextUntouched() {
+        var settings = AppSettings.defaults
+        let text = "jane@example.com"
+        XCTAssertEqual(TextRedactor.redact(text, settings: settings), text)
+
+        settings.redactionEnabled = true
+        XCTAssertEqual(TextRedactor.redact(text, settings: settings), "[email]")
+    }
+
+    func test_redactForLog_respectsRedactLogs() {
+        var settings = AppSettings.defaults
+        settings.redactionEnabled = true
+        settings.redactLogs = false
+        XCTAssertEqual(TextRedactor.redactForLog("jane@example.com", settings: settings), "jane@example.com")
+    }
+
+    func test_luhnCheck() {
+        XCTAssertTrue(TextRedactor.passesLuhnCheck("5500-0000-0000-0004"))
+        XCTAssertFalse(TextRedactor.passesLuhnCheck("5500-0000-0000-0005"))
+    }
+}
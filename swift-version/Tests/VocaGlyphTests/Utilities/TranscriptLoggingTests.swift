@@ -0,0 +1,25 @@
+import XCTest
+@testable import VocaGlyph
+
+final class TranscriptLoggingTests: XCTestCase {
+
+    private let text = "Meet Dana at the clinic on Friday"
+
+    func test_full_keepsTheText() {
+        XCTAssertEqual(TranscriptLogging.full.render(text), text)
+    }
+
+    func test_truncated_keepsOnlyTheStart() {
+        XCTAssertEqual(TranscriptLogging.truncated.render(text), "Meet Dana at… <33 chars>")
+        XCTAssertEqual(TranscriptLogging.truncated.render("Hi there"), "<8 chars>")
+    }
+
+    func test_hashed_isStableAndHidesTheText() {
+        let rendered = TranscriptLogging.hashed.render(text)
+        XCTAssertEqual(rendered, TranscriptLogging.hashed.render(text))
+        XCTAssertNotEqual(rendered, TranscriptLogging.hashed.render(text + "."))
+        XCTAssertFalse(rendered.contains("Dana"))
+        XCTAssertTrue(rendered.hasPrefix("<sha256:"))
+        XCTAssertTrue(rendered.hasSuffix(", 33 chars>"))
+    }
+}
@@ -0,0 +1,43 @@
+import XCTest
+@testable import VocaGlyph
+
+final class TrayIconThemeTests: XCTestCase {
+
+    func testRestingStatesShowPausedThenIncognito() {
+        XCTAssertEqual(TrayIcon.for(.idle, dictationPaused: false, incognito: false), .idle)
+        XCTAssertEqual(TrayIcon.for(.idle, dictationPaused: false, incognito: true), .incognito)
+        XCTAssertEqual(TrayIcon.for(.idle, dictationPaused: true, incognito: true), .paused)
+        XCTAssertEqual(TrayIcon.for(.paused, dictationPaused: false, incognito: false), .paused)
+    }
+
+    func testBusyStatesIgnorePausedAndIncognito() {
+        XCTAssertEqual(TrayIcon.for(.recording, dictationPaused: true, incognito: true), .recording)
+        XCTAssertEqual(TrayIcon.for(.processing, dictationPaused: true, incognito: true), .processing)
+        XCTAssertEqual(TrayIcon.for(.error("boom"), dictationPaused: true, incognito: true), .error)
+    }
+
+    func testStandardIdleUsesTheAppIcon() {
+        XCTAssertNil(TrayIconTheme.standard.symbol(for: .idle))
+        XCTAssertEqual(TrayIconTheme.standard.symbol(for: .recording)?.name, "waveform.circle.fill")
+    }
+
+    func testMonochromeIsAllTemplateImages() {
+        for icon in TrayIcon.allCases {
+            XCTAssertNotNil(TrayIconTheme.monochrome.symbol(for: icon), "\(icon)")
+            XCTAssertNil(TrayIconTheme.monochrome.symbol(for: icon)?.color, "\(icon)")
+        }
+    }
+
+    func testHighContrastGivesEveryStateItsOwnShape() {
+        let names = TrayIcon.allCases.compactMap { TrayIconTheme.highContrast.symbol(for: $0)?.name }
+        XCTAssertEqual(names.count, TrayIcon.allCases.count)
+        XCTAssertEqual(Set(names).count, names.count)
+    }
+
+    func testDockBadgeOnlyForBusyOrAttentionStates() {
+        XCTAssertNil(TrayIcon.idle.dockBadge)
+        XCTAssertNil(TrayIcon.incognito.dockBadge)
+        XCTAssertEqual(TrayIcon.recording.dockBadge, "REC")
+        XCTAssertEqual(TrayIcon.error.dockBadge, "!")
+    }
+}
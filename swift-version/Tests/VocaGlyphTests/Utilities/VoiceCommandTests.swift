@@ -0,0 +1,49 @@
+import XCTest
+@testable import VocaGlyph
+
+final class VoiceCommandTests: XCTestCase {
+
+    private func parse(_ text: String) -> VoiceCommand? {
+        VoiceCommand.parse(text, prefix: "computer")
+    }
+
+    // MARK: - Commands
+
+    func testLanguageCommands() {
+        XCTAssertEqual(parse("Computer, switch to Spanish."), .switchLanguage("Spanish (ES)"))
+        XCTAssertEqual(parse("computer dictate in French"), .switchLanguage("French (FR)"))
+        XCTAssertEqual(parse("Computer, switch to auto-detect"), .switchLanguage("Auto-Detect"))
+    }
+
+    func testModelCommands() {
+        XCTAssertEqual(parse("Computer, use the small model."), .useModel("small"))
+        XCTAssertEqual(parse("computer switch to the large turbo model"), .useModel("large-v3_turbo"))
+        XCTAssertEqual(parse("Computer, use Parakeet."), .useModel("parakeet-v3"))
+    }
+
+    func testPauseAndResume() {
+        XCTAssertEqual(parse("Computer, pause dictation."), .pauseDictation)
+        XCTAssertEqual(parse("Computer, please resume dictation"), .resumeDictation)
+    }
+
+    // MARK: - Not Commands
+
+    func testRequiresPrefix() {
+        XCTAssertNil(parse("Switch to Spanish."))
+        XCTAssertNil(VoiceCommand.parse("switch to Spanish", prefix: "  "))
+    }
+
+    func testPrefixedDictationIsNotACommand() {
+        XCTAssertNil(parse("Computer science is hard."))
+        XCTAssertNil(parse("Computer, use the small model for the demo"))
+        XCTAssertNil(parse("computers pause"))
+    }
+
+    func testLanguageNameIsNotAModel() {
+        XCTAssertNil(parse("computer use the Spanish model"))
+    }
+
+    func testMultiWordPrefix() {
+        XCTAssertEqual(VoiceCommand.parse("Hey Glyph, pause.", prefix: "hey glyph"), .pauseDictation)
+    }
+}